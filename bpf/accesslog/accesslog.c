@@ -52,11 +52,13 @@ char __license[] SEC("license") = "Dual MIT/GPL";
 #include "l24/read_l4.c"
 #include "l24/read_l3.c"
 #include "l24/read_l2.c"
+#include "l24/health.c"
 
 // tls monitoring
 #include "tls/go_tls.c"
 #include "tls/node_tls.c"
 #include "tls/openssl.c"
+#include "tls/rustls.c"
 
 // ambient istio
 #include "ambient/ztunnel.c"
\ No newline at end of file
@@ -17,21 +17,43 @@
 
 #include "ztunnel.h"
 
-static __inline bool get_socket_addr_ip_in_ztunnel(bool success, void * arg, __u32 *ip, __u16 *port) {
+// the tag of the rust "std::net::SocketAddr" enum, the address fields of both variants start at the sockaddr[2]
+#define ZTUNNEL_SOCKET_ADDR_V4 0
+#define ZTUNNEL_SOCKET_ADDR_V6 1
+
+static __inline bool get_socket_addr_ip_in_ztunnel(bool success, void * arg, __u8 *ip, __u16 *port) {
     if (!success) {
         return false;
     }
-    __u8 sockaddr[8];
+    // the enum is large enough to hold the IPv6 variant, so it's safe to read whole variant
+    __u8 sockaddr[20];
     if (bpf_probe_read(&sockaddr, sizeof(sockaddr), (void *)arg) != 0) {
        return false;
     }
-    // ip is stored in sockaddr[2], sockaddr[3], sockaddr[4], sockaddr[5]
-    *ip = ((__u32)sockaddr[2] << 24) | ((__u32)sockaddr[3] << 16) | ((__u32)sockaddr[4] << 8) | (__u32)sockaddr[5];
-    if (port != NULL) {
-        // port is stored in sockaddr[6], sockaddr[7](should convert to big-endian)
-        *port = ((__u16)sockaddr[7] << 8) | sockaddr[6];
+    if (sockaddr[0] == ZTUNNEL_SOCKET_ADDR_V4) {
+        // ip is stored in sockaddr[2], sockaddr[3], sockaddr[4], sockaddr[5], convert to the IPv4-mapped IPv6 address
+        __builtin_memset(ip, 0, 10);
+        ip[10] = 0xff;
+        ip[11] = 0xff;
+        ip[12] = sockaddr[2];
+        ip[13] = sockaddr[3];
+        ip[14] = sockaddr[4];
+        ip[15] = sockaddr[5];
+        if (port != NULL) {
+            // port is stored in sockaddr[6], sockaddr[7](should convert to big-endian)
+            *port = ((__u16)sockaddr[7] << 8) | sockaddr[6];
+        }
+        return true;
+    } else if (sockaddr[0] == ZTUNNEL_SOCKET_ADDR_V6) {
+        // ip is stored in sockaddr[2] to sockaddr[17]
+        __builtin_memcpy(ip, &sockaddr[2], 16);
+        if (port != NULL) {
+            // port is stored in sockaddr[18], sockaddr[19](should convert to big-endian)
+            *port = ((__u16)sockaddr[19] << 8) | sockaddr[18];
+        }
+        return true;
     }
-    return true;
+    return false;
 }
 
 SEC("uprobe/connection_manager_track_outbound")
@@ -41,12 +63,13 @@ int connection_manager_track_outbound(struct pt_regs* ctx) {
         return 0;
     }
     bool success = true;
-    success = get_socket_addr_ip_in_ztunnel(success, (void *)PT_REGS_PARM3(ctx), &event->orginal_src_ip, &event->src_port);
-    success = get_socket_addr_ip_in_ztunnel(success, (void *)PT_REGS_PARM4(ctx), &event->original_dst_ip, &event->dst_port);
-    success = get_socket_addr_ip_in_ztunnel(success, (void *)PT_REGS_PARM5(ctx), &event->lb_dst_ip, &event->lb_dst_port);
+    success = get_socket_addr_ip_in_ztunnel(success, (void *)PT_REGS_PARM3(ctx), event->orginal_src_ip, &event->src_port);
+    success = get_socket_addr_ip_in_ztunnel(success, (void *)PT_REGS_PARM4(ctx), event->original_dst_ip, &event->dst_port);
+    success = get_socket_addr_ip_in_ztunnel(success, (void *)PT_REGS_PARM5(ctx), event->lb_dst_ip, &event->lb_dst_port);
     if (!success) {
         return 0;
     }
+    event->pid = bpf_get_current_pid_tgid() >> 32;
     bpf_perf_event_output(ctx, &ztunnel_lb_socket_mapping_event_queue, BPF_F_CURRENT_CPU, event, sizeof(*event));
     return 0;
 }
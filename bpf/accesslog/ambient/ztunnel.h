@@ -15,15 +15,16 @@
 // specific language governing permissions and limitations
 // under the License.
 
+// the IPv4 address is stored as the IPv4-mapped IPv6 address
 struct ztunnel_socket_mapping_t {
-    __u32 orginal_src_ip;       // origin local ip
-    __u32 original_dst_ip;      // origin remote ip(should be service ip)
+    __u8 orginal_src_ip[16];    // origin local ip
+    __u8 original_dst_ip[16];   // origin remote ip(should be service ip)
+    __u8 lb_dst_ip[16];         // load balanced remote ip(should be real pod ip)
     __u16 src_port;             // origin local port
     __u16 dst_port;             // origin remote port
-    __u32 lb_dst_ip;            // load balanced remote ip(should be real pod ip)
     __u16 lb_dst_port;          // load balanced remote port
     __u16 pad0;
-    __u32 pad1;
+    __u32 pid;                  // the pid of the ztunnel process
 };
 
 struct {
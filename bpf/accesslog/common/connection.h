@@ -93,7 +93,9 @@ struct socket_connect_event_t {
     __u8 socket_family;
     // is connect success or not
     __u8 success;
-    __u32 __pad0;
+    // the L4 protocol of the socket(IPPROTO_*), such as TCP or SCTP
+    __u8 transport;
+    __u8 __pad0[3];
 
     // upstream
     __u32 remote_addr_v4;
@@ -131,8 +133,14 @@ struct active_connection_t {
     __u8 ssl;
     // skip data upload when the protocol break(such as HTTP2)
     __u8 skip_data_upload;
-    __u8 pad0;
-    __u32 pad1;
+    // the count of the uploaded TLS handshake data, the encrypted data after the handshake is not uploaded
+    // unless the process exports the TLS key log
+    __u8 tls_handshake_uploads;
+    // for detecting the protocol through the registered ports
+    __u16 local_port;
+    __u16 remote_port;
+    // the L4 protocol of the socket(IPPROTO_*)
+    __u8 transport;
 };
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
@@ -166,6 +174,63 @@ static __inline bool family_should_trace(const __u32 family) {
     return family != AF_UNKNOWN && family != AF_INET && family != AF_INET6 ? false : true;
 }
 
+// the connecting socket(SYN_SENT) of the connect operation, for detecting the async connect failure
+struct connecting_sock_t {
+    __u64 conid;
+    __u64 random_id;
+    __u64 start_time;
+};
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, struct connecting_sock_t);
+} connecting_sock_map SEC(".maps");
+
+// the excluded remote address, the IPv4 address is mapped as the IPv6(::ffff:a.b.c.d), the port 0 means any port
+struct exclude_address_t {
+    __u8 addr[16];
+    __u32 port;
+};
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 1024);
+	__type(key, struct exclude_address_t);
+	__type(value, __u32);
+} exclude_address_control SEC(".maps");
+
+// the connections excluded by the remote address, the value is the random ID,
+// the connection events and the data of them are not generated
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, __u64);
+} excluded_connection_map SEC(".maps");
+
+static __inline bool remote_address_excluded(struct socket_connect_event_t *event) {
+    struct exclude_address_t key = {};
+    if (event->socket_family == AF_INET) {
+        key.addr[10] = 0xff;
+        key.addr[11] = 0xff;
+        __builtin_memcpy(&key.addr[12], &event->remote_addr_v4, sizeof(event->remote_addr_v4));
+    } else if (event->socket_family == AF_INET6) {
+        __builtin_memcpy(key.addr, event->remote_addr_v6, sizeof(key.addr));
+    } else {
+        return false;
+    }
+    key.port = event->remote_port;
+    if (bpf_map_lookup_elem(&exclude_address_control, &key) != NULL) {
+        return true;
+    }
+    key.port = 0;
+    return bpf_map_lookup_elem(&exclude_address_control, &key) != NULL;
+}
+
+static __inline bool connection_excluded(__u64 conid) {
+    return bpf_map_lookup_elem(&excluded_connection_map, &conid) != NULL;
+}
+
 static __always_inline void submit_new_connection(void* ctx, bool success, __u32 func_name, __u32 tgid, __u32 fd, __u64 start_nacs,
                                             struct sockaddr* addr, const struct socket* socket, struct connect_track_remote* conntrack, __u8 role) {
     // send to the user-space the connection event
@@ -207,6 +272,7 @@ static __always_inline void submit_new_connection(void* ctx, bool success, __u32
     __u8 socket_family;
     event->local_port = 0;
     event->remote_port = 0;
+    event->transport = 0;
     if (socket == NULL) {
         struct task_struct* task_ptr = (struct task_struct*)bpf_get_current_task();
         struct files_struct *files = _(task_ptr->files);
@@ -226,6 +292,22 @@ static __always_inline void submit_new_connection(void* ctx, bool success, __u32
         BPF_CORE_READ_INTO(&skc_family, s, __sk_common.skc_family);
         event->socket_family = skc_family;
         socket_family = skc_family;
+        // the sk_protocol is the bitfield in the old kernels
+        event->transport = (__u8)BPF_CORE_READ_BITFIELD_PROBED(s, sk_protocol);
+
+        // the connect is in progress, tracking it for detecting the failure when the handshake finished
+        if (func_name == SOCKET_OPTS_TYPE_CONNECT && success) {
+            unsigned char skc_state;
+            BPF_CORE_READ_INTO(&skc_state, s, __sk_common.skc_state);
+            if (skc_state == BPF_TCP_SYN_SENT) {
+                struct connecting_sock_t connecting = {};
+                connecting.conid = conid;
+                connecting.random_id = random_id;
+                connecting.start_time = start_nacs;
+                __u64 sk_key = (__u64)s;
+                bpf_map_update_elem(&connecting_sock_map, &sk_key, &connecting, 0);
+            }
+        }
 
         if (event->socket_family == AF_INET) {
             BPF_CORE_READ_INTO(&port, s, __sk_common.skc_num);
@@ -264,6 +346,14 @@ static __always_inline void submit_new_connection(void* ctx, bool success, __u32
         socket_family = AF_UNKNOWN;
     }
 
+    // the excluded connection is remembered, so the data of it doesn't create the connection again
+    if (remote_address_excluded(event)) {
+        rover_discard_buf(event);
+        bpf_map_update_elem(&excluded_connection_map, &conid, &random_id, 0);
+        return;
+    }
+    bpf_map_delete_elem(&excluded_connection_map, &conid);
+
     rover_submit_buf(ctx, &socket_connection_event_queue, event, sizeof(*event));
     if (success == false) {
         return;
@@ -282,6 +372,9 @@ static __always_inline void submit_new_connection(void* ctx, bool success, __u32
     con.sockfd = fd;
     con.role = role;
     con.socket_family = socket_family;
+    con.local_port = event->local_port;
+    con.remote_port = event->remote_port;
+    con.transport = event->transport;
     bpf_map_update_elem(&active_connection_map, &conid, &con, 0);
 }
 
@@ -291,6 +384,9 @@ static __inline struct active_connection_t* get_or_create_active_conn(void *ctx,
     if (conn != NULL) {
         return conn;
     }
+    if (connection_excluded(conid)) {
+        return NULL;
+    }
     submit_new_connection(ctx, true, func_name, tgid, fd, 0, NULL, NULL, NULL, role);
     return bpf_map_lookup_elem(&active_connection_map, &conid);
 }
@@ -302,7 +398,7 @@ static __inline void submit_connection_when_not_exists(void *ctx, __u64 id, stru
 
     __u64 conid = gen_tgid_fd(tgid, fd);
     struct active_connection_t *conn = bpf_map_lookup_elem(&active_connection_map, &conid);
-    if (conn != NULL) {
+    if (conn != NULL || connection_excluded(conid)) {
        return;
     }
     submit_new_connection(ctx, true, func_name, tgid, connect_args->fd, connect_args->start_nacs, connect_args->addr, NULL, &connect_args->remote, role);
@@ -331,6 +427,7 @@ static __inline void notify_close_connection(void* ctx, __u64 conid, struct acti
 static __inline void submit_close_connection(void* ctx, __u32 tgid, __u32 fd, __u64 start_nacs, int ret) {
     __u64 curr_nacs = bpf_ktime_get_ns();
     __u64 conid = gen_tgid_fd(tgid, fd);
+    bpf_map_delete_elem(&excluded_connection_map, &conid);
     struct active_connection_t* con = bpf_map_lookup_elem(&active_connection_map, &conid);
     if (con == NULL) {
         return;
@@ -37,6 +37,7 @@ struct sock_data_args_t {
     struct iovec *iovec;
     __u64 data_id;
     // for openssl
+    void* ssl;
     __u32 excepted_size;
     __u32 ifindex;
     __u64 total_package_size;
@@ -63,6 +64,16 @@ struct sock_data_args_t {
     __u64 l2_enter_queue_package_count;
     __u64 l2_ready_send_duration;
     __u64 l2_send_duration;
+
+    // socket buffer pressure
+    __u32 buffer_pressure;
+    __u16 send_buffer_full_count;
+    __u16 receive_buffer_drop_count;
+
+    // the syscall blocked time, waiting for the send buffer memory or the receive data
+    __u64 wait_start_time;
+    __u64 send_buffer_wait_time;
+    __u64 receive_wait_time;
 };
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
@@ -0,0 +1,36 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#include "health.h"
+
+SEC("tracepoint/tcp/tcp_send_reset")
+int tracepoint_tcp_send_reset(struct trace_event_raw_tcp_event_sk_skb_t *args) {
+    struct socket_health_t *health = find_socket_health_by_sock(args->skaddr);
+    if (health != NULL) {
+        health->resets_sent++;
+    }
+    return 0;
+}
+
+SEC("tracepoint/tcp/tcp_receive_reset")
+int tracepoint_tcp_receive_reset(struct trace_event_raw_tcp_event_sk_t *args) {
+    struct socket_health_t *health = find_socket_health_by_sock(args->skaddr);
+    if (health != NULL) {
+        health->resets_received++;
+    }
+    return 0;
+}
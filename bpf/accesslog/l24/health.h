@@ -0,0 +1,128 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#pragma once
+
+#include "../common/connection.h"
+
+// the network health of the connection, deleted by the user-space after reading it when the connection closed
+struct socket_health_t {
+    // the random id of the connection, the health is reset when the connection id is reused
+    __u64 random_id;
+    __u32 retransmits;
+    // the write times when the peer advertised zero window
+    __u32 zero_windows;
+    __u32 resets_sent;
+    __u32 resets_received;
+    // smoothed RTT samples(microseconds) when writing data
+    __u32 rtt_min;
+    __u32 rtt_max;
+    __u64 rtt_sum;
+    __u32 rtt_samples;
+    __u32 __pad0;
+};
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, struct socket_health_t);
+} socket_health_map SEC(".maps");
+
+// the connection of the socket, for finding the connection in the softirq context, such as retransmit and reset
+struct sock_connection_t {
+    __u64 conid;
+    __u64 random_id;
+};
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, struct sock_connection_t);
+} sock_connection_map SEC(".maps");
+
+struct trace_event_raw_tcp_event_sk_skb_t {
+    struct trace_entry ent;
+    const void *skbaddr;
+    const void *skaddr;
+};
+
+struct trace_event_raw_tcp_event_sk_t {
+    struct trace_entry ent;
+    const void *skaddr;
+};
+
+static __inline struct socket_health_t* get_or_create_socket_health(__u64 conid, __u64 random_id) {
+    struct socket_health_t *health = bpf_map_lookup_elem(&socket_health_map, &conid);
+    if (health != NULL && health->random_id == random_id) {
+        return health;
+    }
+    struct socket_health_t empty = {};
+    empty.random_id = random_id;
+    bpf_map_update_elem(&socket_health_map, &conid, &empty, 0);
+    return bpf_map_lookup_elem(&socket_health_map, &conid);
+}
+
+static __inline struct socket_health_t* find_socket_health_by_sock(const void *sk) {
+    if (sk == NULL) {
+        return NULL;
+    }
+    __u64 sk_key = (__u64)sk;
+    struct sock_connection_t *con = bpf_map_lookup_elem(&sock_connection_map, &sk_key);
+    if (con == NULL) {
+        return NULL;
+    }
+    return get_or_create_socket_health(con->conid, con->random_id);
+}
+
+// record the socket of the connection and the health sampled when writing data
+static __inline void record_socket_health(__u32 tgid, __u32 fd, struct sock *sk) {
+    __u64 conid = gen_tgid_fd(tgid, fd);
+    struct active_connection_t *con = bpf_map_lookup_elem(&active_connection_map, &conid);
+    if (con == NULL) {
+        return;
+    }
+    __u64 sk_key = (__u64)sk;
+    struct sock_connection_t sock_con = {};
+    sock_con.conid = conid;
+    sock_con.random_id = con->random_id;
+    bpf_map_update_elem(&sock_connection_map, &sk_key, &sock_con, 0);
+
+    struct socket_health_t *health = get_or_create_socket_health(conid, con->random_id);
+    if (health == NULL) {
+        return;
+    }
+    struct tcp_sock *tp = (struct tcp_sock *)sk;
+    __u32 snd_wnd = 0, srtt = 0;
+    BPF_CORE_READ_INTO(&snd_wnd, tp, snd_wnd);
+    if (snd_wnd == 0) {
+        health->zero_windows++;
+    }
+    // the srtt_us is saved as the 8 times of the smoothed RTT
+    BPF_CORE_READ_INTO(&srtt, tp, srtt_us);
+    srtt = srtt >> 3;
+    if (srtt == 0) {
+        return;
+    }
+    if (health->rtt_samples == 0 || srtt < health->rtt_min) {
+        health->rtt_min = srtt;
+    }
+    if (srtt > health->rtt_max) {
+        health->rtt_max = srtt;
+    }
+    health->rtt_sum += srtt;
+    health->rtt_samples++;
+}
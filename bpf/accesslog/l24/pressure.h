@@ -0,0 +1,62 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#pragma once
+
+#include "../common/data_args.h"
+
+// the last receive queue dropped count of the socket
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, __u32);
+} socket_receive_drops_map SEC(".maps");
+
+// detect the socket buffer pressure when reading or writing data,
+// the sk_drops is accumulated in the socket, so only the increased drops since the last detection are counted
+static __inline void detect_socket_buffer_pressure(struct sock_data_args_t *data_args, struct sock *sk) {
+    struct tcp_sock *tp = (struct tcp_sock *)sk;
+    __u32 snd_wnd = 0, rcv_wnd = 0;
+    BPF_CORE_READ_INTO(&snd_wnd, tp, snd_wnd);
+    BPF_CORE_READ_INTO(&rcv_wnd, tp, rcv_wnd);
+    if (snd_wnd == 0) {
+        data_args->buffer_pressure |= SOCKET_BUFFER_PRESSURE_PEER_ZERO_WINDOW;
+        __u8 probes = 0;
+        BPF_CORE_READ_INTO(&probes, (struct inet_connection_sock *)sk, icsk_probes_out);
+        if (probes > 0) {
+            data_args->buffer_pressure |= SOCKET_BUFFER_PRESSURE_PEER_ZERO_WINDOW_PROBE;
+        }
+    }
+    if (rcv_wnd == 0) {
+        data_args->buffer_pressure |= SOCKET_BUFFER_PRESSURE_LOCAL_ZERO_WINDOW;
+    }
+
+    int drops = 0;
+    BPF_CORE_READ_INTO(&drops, sk, sk_drops.counter);
+    if (drops <= 0) {
+        return;
+    }
+    __u64 sk_key = (__u64)sk;
+    __u32 current = drops;
+    __u32 *last = bpf_map_lookup_elem(&socket_receive_drops_map, &sk_key);
+    if (last != NULL && current > *last) {
+        data_args->buffer_pressure |= SOCKET_BUFFER_PRESSURE_RECEIVE_BUFFER_DROP;
+        data_args->receive_buffer_drop_count += current - *last;
+    }
+    bpf_map_update_elem(&socket_receive_drops_map, &sk_key, &current, 0);
+}
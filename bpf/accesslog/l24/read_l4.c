@@ -17,6 +17,7 @@
 
 #include "l24.h"
 #include "../common/data_args.h"
+#include "pressure.h"
 
 SEC("kprobe/tcp_v4_rcv")
 int tcp_v4_rcv(struct pt_regs * ctx) {
@@ -57,3 +58,35 @@ int tcp_v6_rcv_ret(struct pt_regs * ctx) {
     }
     return 0;
 }
+
+SEC("kprobe/tcp_recvmsg")
+int tcp_recvmsg(struct pt_regs * ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
+    if (data_args != NULL) {
+        struct sock *sk = (void *)PT_REGS_PARM1(ctx);
+        detect_socket_buffer_pressure(data_args, sk);
+    }
+    return 0;
+}
+
+SEC("kprobe/sk_wait_data")
+int sk_wait_data(struct pt_regs * ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
+    if (data_args != NULL) {
+        data_args->wait_start_time = bpf_ktime_get_ns();
+    }
+    return 0;
+}
+
+SEC("kretprobe/sk_wait_data")
+int sk_wait_data_ret(struct pt_regs * ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
+    if (data_args != NULL && data_args->wait_start_time > 0) {
+        data_args->receive_wait_time += bpf_ktime_get_ns() - data_args->wait_start_time;
+        data_args->wait_start_time = 0;
+    }
+    return 0;
+}
@@ -18,6 +18,8 @@
 #include "l24.h"
 #include "../common/data_args.h"
 #include "../common/sock.h"
+#include "health.h"
+#include "pressure.h"
 
 struct trace_event_raw_kfree_skb {
     struct trace_entry ent;
@@ -35,6 +37,8 @@ int tcp_sendmsg(struct pt_regs* ctx) {
         // getting the socket role is client or server
         struct sock *sk = (void *)PT_REGS_PARM1(ctx);
         data_args->sk_role = get_sock_role(data_args->sk_role, sk);
+        detect_socket_buffer_pressure(data_args, sk);
+        record_socket_health((__u32)(id >> 32), data_args->fd, sk);
     }
     return 0;
 };
@@ -61,8 +65,36 @@ int tcp_transmit_skb(struct pt_regs* ctx) {
     return 0;
 };
 
+SEC("kprobe/sk_stream_wait_memory")
+int sk_stream_wait_memory(struct pt_regs* ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
+    if (data_args != NULL) {
+        data_args->buffer_pressure |= SOCKET_BUFFER_PRESSURE_SEND_BUFFER_FULL;
+        data_args->send_buffer_full_count++;
+        data_args->wait_start_time = bpf_ktime_get_ns();
+    }
+    return 0;
+}
+
+SEC("kretprobe/sk_stream_wait_memory")
+int sk_stream_wait_memory_ret(struct pt_regs* ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
+    if (data_args != NULL && data_args->wait_start_time > 0) {
+        data_args->send_buffer_wait_time += bpf_ktime_get_ns() - data_args->wait_start_time;
+        data_args->wait_start_time = 0;
+    }
+    return 0;
+}
+
 SEC("tracepoint/tcp/tcp_retransmit_skb")
-int tracepoint_tcp_retransmit_skb() {
+int tracepoint_tcp_retransmit_skb(struct trace_event_raw_tcp_event_sk_skb_t *args) {
+    // the retransmission by the timer is not in the syscall context, so only counted in the connection health
+    struct socket_health_t *health = find_socket_health_by_sock(args->skaddr);
+    if (health != NULL) {
+        health->retransmits++;
+    }
     __u64 id = bpf_get_current_pid_tgid();
     struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
     if (data_args != NULL) {
@@ -24,9 +24,34 @@ struct {
 	__type(value, __u32);
 } process_monitor_control SEC(".maps");
 
-volatile __u32 ztunnel_process_pid;
+// the processes which export the TLS key log, the encrypted data would be uploaded for decryption
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u32);
+	__type(value, __u32);
+} tls_key_log_control SEC(".maps");
+
+// the ztunnel processes in the current node, multiple ztunnel could be running with the different mesh revisions
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 64);
+	__type(key, __u32);
+	__type(value, __u32);
+} ztunnel_process_control SEC(".maps");
+
+// the processes excluded from the collection even when monitored, such as rover itself
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 1024);
+	__type(key, __u32);
+	__type(value, __u32);
+} process_exclude_control SEC(".maps");
 
 static __inline bool tgid_should_trace(__u32 tgid) {
+    if (bpf_map_lookup_elem(&process_exclude_control, &tgid) != NULL) {
+        return false;
+    }
     __u32 *val = bpf_map_lookup_elem(&process_monitor_control, &tgid);
     if (!val) {
         return false;
@@ -35,5 +60,11 @@ static __inline bool tgid_should_trace(__u32 tgid) {
 }
 
 static __inline bool tgid_is_ztunnel(__u32 tgid) {
-    return ztunnel_process_pid > 0 && tgid == ztunnel_process_pid ? true : false;
-}
\ No newline at end of file
+    __u32 *val = bpf_map_lookup_elem(&ztunnel_process_control, &tgid);
+    return val != NULL && (*val) == 1 ? true : false;
+}
+
+static __inline bool tgid_should_decrypt_tls(__u32 tgid) {
+    __u32 *val = bpf_map_lookup_elem(&tls_key_log_control, &tgid);
+    return val != NULL && (*val) == 1 ? true : false;
+}
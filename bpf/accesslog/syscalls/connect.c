@@ -20,6 +20,7 @@
 #include "socket_opts.h"
 #include "../process/process.h"
 #include "../common/connection.h"
+#include "connect_failure.c"
 
 static __inline void process_connect(void *ctx, __u64 id, struct connect_args_t *connect_args, long ret) {
     bool success = true;
@@ -33,6 +34,9 @@ static __inline void process_connect(void *ctx, __u64 id, struct connect_args_t
     struct sock *sock = connect_args->sock;
     struct socket *s = _(sock->sk_socket);
     submit_new_connection(ctx, success, SOCKET_OPTS_TYPE_CONNECT, tgid, connect_args->fd, connect_args->start_nacs, connect_args->addr, s, &connect_args->remote, 0);
+    if (success == false && connection_excluded(gen_tgid_fd(tgid, connect_args->fd)) == false) {
+        submit_connect_failure_from_addr(ctx, id, connect_args, ret);
+    }
 }
 
 static __inline void process_accept(void *ctx, __u64 id, struct accept_args_t *accept_args, long ret) {
@@ -118,14 +122,24 @@ int sock_alloc_ret(struct pt_regs *ctx) {
     return 0;
 }
 
-SEC("kprobe/ip4_datagram_connect")
-int ip4_udp_datagram_connect(struct pt_regs *ctx) {
+static __inline void udp_datagram_connect(struct pt_regs *ctx) {
     __u64 id = bpf_get_current_pid_tgid();
     struct connect_args_t *connect_args = bpf_map_lookup_elem(&conecting_args, &id);
     if (connect_args) {
         struct sock *sock = (struct sock*)PT_REGS_PARM1(ctx);
         connect_args->sock = sock;
     }
+}
+
+SEC("kprobe/ip4_datagram_connect")
+int ip4_udp_datagram_connect(struct pt_regs *ctx) {
+    udp_datagram_connect(ctx);
+    return 0;
+}
+
+SEC("kprobe/ip6_datagram_connect")
+int ip6_udp_datagram_connect(struct pt_regs *ctx) {
+    udp_datagram_connect(ctx);
     return 0;
 }
 
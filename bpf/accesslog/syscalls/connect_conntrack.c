@@ -77,6 +77,15 @@ static __always_inline int nf_conntrack_tuple_to_conntrack_tuple(struct connect_
             if (local_addr_v4 != t->daddr_l || local_port != t->dport) {
                 return 0;
             }
+        } else if (skc_family == AF_INET6 && ct->src.l3num == AF_INET6) {
+            __u16 local_port;
+            BPF_CORE_READ_INTO(&local_port, s, __sk_common.skc_num);
+            __u64 local_addr_v6[2];
+            BPF_CORE_READ_INTO(&local_addr_v6, s, __sk_common.skc_v6_rcv_saddr);
+            // make sure connntrack with the same socket address
+            if (local_addr_v6[0] != t->daddr_h || local_addr_v6[1] != t->daddr_l || local_port != t->dport) {
+                return 0;
+            }
         }
     }
     return 1;
@@ -0,0 +1,91 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#include "connect_failure.h"
+
+static __inline void submit_connect_failure_from_addr(void *ctx, __u64 id, struct connect_args_t *connect_args, long ret) {
+    if (connect_args->addr == NULL) {
+        return;
+    }
+    struct socket_connect_failure_event_t *event = rover_reserve_buf(&socket_connect_failure_event_queue, sizeof(*event));
+    if (event == NULL) {
+        return;
+    }
+    __u32 tgid = id >> 32;
+    event->conid = gen_tgid_fd(tgid, connect_args->fd);
+    event->random_id = 0;
+    event->start_time = connect_args->start_nacs;
+    event->end_time = bpf_ktime_get_ns();
+    event->pid = tgid;
+    event->error = -ret;
+
+    __u16 port = 0;
+    event->socket_family = _(connect_args->addr->sa_family);
+    event->remote_addr_v4 = 0;
+    __builtin_memset(&event->remote_addr_v6, 0, sizeof(event->remote_addr_v6));
+    if (event->socket_family == AF_INET) {
+        struct sockaddr_in *daddr = (struct sockaddr_in *)connect_args->addr;
+        bpf_probe_read(&event->remote_addr_v4, sizeof(event->remote_addr_v4), &daddr->sin_addr.s_addr);
+        bpf_probe_read(&port, sizeof(port), &daddr->sin_port);
+    } else if (event->socket_family == AF_INET6) {
+        struct sockaddr_in6 *daddr = (struct sockaddr_in6 *)connect_args->addr;
+        bpf_probe_read(&event->remote_addr_v6, sizeof(event->remote_addr_v6), &daddr->sin6_addr.s6_addr);
+        bpf_probe_read(&port, sizeof(port), &daddr->sin6_port);
+    } else {
+        rover_discard_buf(event);
+        return;
+    }
+    event->remote_port = bpf_ntohs(port);
+    rover_submit_buf(ctx, &socket_connect_failure_event_queue, event, sizeof(*event));
+}
+
+SEC("tracepoint/sock/inet_sock_set_state")
+int tracepoint_inet_sock_set_state(struct trace_event_raw_inet_sock_set_state_t *args) {
+    if (args->protocol != IPPROTO_TCP || args->oldstate != BPF_TCP_SYN_SENT) {
+        return 0;
+    }
+    __u64 sk_key = (__u64)args->skaddr;
+    struct connecting_sock_t *connecting = bpf_map_lookup_elem(&connecting_sock_map, &sk_key);
+    if (connecting == NULL) {
+        return 0;
+    }
+    if (args->newstate != BPF_TCP_CLOSE || connection_excluded(connecting->conid)) {
+        // the handshake finished, or the connection is excluded
+        bpf_map_delete_elem(&connecting_sock_map, &sk_key);
+        return 0;
+    }
+
+    struct socket_connect_failure_event_t *event = rover_reserve_buf(&socket_connect_failure_event_queue, sizeof(*event));
+    if (event != NULL) {
+        struct sock *sk = (struct sock *)args->skaddr;
+        int sk_err = 0;
+        BPF_CORE_READ_INTO(&sk_err, sk, sk_err);
+        event->conid = connecting->conid;
+        event->random_id = connecting->random_id;
+        event->start_time = connecting->start_time;
+        event->end_time = bpf_ktime_get_ns();
+        event->pid = connecting->conid >> 32;
+        event->error = sk_err;
+        event->socket_family = args->family;
+        event->remote_port = args->dport;
+        bpf_probe_read(&event->remote_addr_v4, sizeof(event->remote_addr_v4), args->daddr);
+        bpf_probe_read(&event->remote_addr_v6, sizeof(event->remote_addr_v6), args->daddr_v6);
+        rover_submit_buf(args, &socket_connect_failure_event_queue, event, sizeof(*event));
+    }
+    bpf_map_delete_elem(&connecting_sock_map, &sk_key);
+    return 0;
+}
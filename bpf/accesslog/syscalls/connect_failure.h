@@ -0,0 +1,54 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#pragma once
+
+#include "../common/connection.h"
+
+// notify to the user-space the connect operation failure
+// the random_id is zero when the connect syscall return failure directly
+struct socket_connect_failure_event_t {
+    __u64 conid;
+    __u64 random_id;
+    // connect operation start nanosecond
+    __u64 start_time;
+    // the failure detected nanosecond
+    __u64 end_time;
+    __u32 pid;
+    // the error number of the failure, such as ECONNREFUSED, ETIMEDOUT or EHOSTUNREACH
+    __u32 error;
+    __u32 socket_family;
+    __u32 remote_port;
+    __u32 remote_addr_v4;
+    __u8 remote_addr_v6[16];
+};
+DATA_QUEUE(socket_connect_failure_event_queue);
+
+struct trace_event_raw_inet_sock_set_state_t {
+    struct trace_entry ent;
+    const void *skaddr;
+    int oldstate;
+    int newstate;
+    __u16 sport;
+    __u16 dport;
+    __u16 family;
+    __u16 protocol;
+    __u8 saddr[4];
+    __u8 daddr[4];
+    __u8 saddr_v6[16];
+    __u8 daddr_v6[16];
+};
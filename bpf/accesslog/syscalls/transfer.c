@@ -22,6 +22,7 @@
 #include "../common/sock.h"
 #include "../l24/l24.h"
 #include "transfer.h"
+#include "../tls/rustls.h"
 
 
 struct trace_event_raw_skb_copy_datagram_iovec {
@@ -544,6 +545,7 @@ int security_socket_sendmsg(struct pt_regs* ctx) {
     struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
     if (data_args != NULL) {
         data_args->is_sock_event = true;
+        rustls_socket_write(ctx, id, data_args->fd);
     }
     return 0;
 }
@@ -554,6 +556,7 @@ int security_socket_recvmsg(struct pt_regs* ctx) {
     struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
     if (data_args != NULL) {
         data_args->is_sock_event = true;
+        rustls_socket_read(id, data_args->fd);
     }
     return 0;
 }
\ No newline at end of file
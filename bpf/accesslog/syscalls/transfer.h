@@ -24,7 +24,25 @@
 #include "../common/connection.h"
 #include "../common/data_args.h"
 
+// the max count of the uploaded data in the TLS handshake, the record header and body could be read separately
+#define TLS_MAX_HANDSHAKE_UPLOADS 8
 
+// the percentage(0-100) of the connections whose socket data is skipped, updated by the adaptive sampling in user space
+struct {
+	__uint(type, BPF_MAP_TYPE_ARRAY);
+	__uint(max_entries, 1);
+	__type(key, __u32);
+	__type(value, __u32);
+} socket_data_sampling_skip SEC(".maps");
+
+static __always_inline bool socket_data_sampled_out(__u64 random_id) {
+    __u32 zero = 0;
+    __u32 *skip = bpf_map_lookup_elem(&socket_data_sampling_skip, &zero);
+    if (skip == NULL || *skip == 0) {
+        return false;
+    }
+    return (random_id % 100) < *skip;
+}
 
 // openssl read or write
 struct {
@@ -67,10 +85,57 @@ struct socket_detail_t {
     __u8 op_func_name;
     __u8 data_protocol;
     __u8 ssl;
+    __u16 send_buffer_full_count;
+    __u16 receive_buffer_drop_count;
+    __u32 buffer_pressure;
+    __u32 send_buffer_wait_duration;
+    __u32 receive_wait_duration;
 };
 
 DATA_QUEUE(socket_detail_queue);
 
+struct protocol_port_t {
+    __u16 port;
+    // the L4 protocol of the port(IPPROTO_*), 0 means matching all transports
+    __u8 transport;
+    __u8 __pad0;
+};
+// the protocol of the connections which using the port, registered by the pluggable protocol analyzers in the user space
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 1024);
+	__type(key, struct protocol_port_t);
+	__type(value, __u8);
+} protocol_port_map SEC(".maps");
+
+static __always_inline __u8* lookup_protocol_by_port(__u16 port, __u8 transport) {
+    struct protocol_port_t key = {};
+    key.port = port;
+    key.transport = transport;
+    __u8 *protocol = bpf_map_lookup_elem(&protocol_port_map, &key);
+    if (protocol != NULL) {
+        return protocol;
+    }
+    key.transport = 0;
+    return bpf_map_lookup_elem(&protocol_port_map, &key);
+}
+
+static __always_inline void analyze_protocol_by_port(struct active_connection_t *conn) {
+    __u8 *protocol = lookup_protocol_by_port(conn->remote_port, conn->transport);
+    __u32 role = CONNECTION_ROLE_TYPE_CLIENT;
+    if (protocol == NULL) {
+        protocol = lookup_protocol_by_port(conn->local_port, conn->transport);
+        role = CONNECTION_ROLE_TYPE_SERVER;
+    }
+    if (protocol == NULL) {
+        return;
+    }
+    conn->protocol = *protocol;
+    if (conn->role == CONNECTION_ROLE_TYPE_UNKNOWN) {
+        conn->role = role;
+    }
+}
+
 static __always_inline void process_write_data(void *ctx, __u64 id, struct sock_data_args_t *args, ssize_t bytes_count,
                                         __u32 data_direction, const bool vecs, __u8 func_name, bool ssl) {
     __u64 curr_nacs = bpf_ktime_get_ns();
@@ -113,10 +178,21 @@ static __always_inline void process_write_data(void *ctx, __u64 id, struct sock_
     // if the protocol or role is unknown in the connection and the current data content is plaintext
     // then try to use protocol analyzer to analyze request or response and protocol type
     __u32 msg_type = 0;
+    // the TLS handshake is detected before the plaintext is captured by the TLS library probes, so detect it again
+    if (ssl && conn->protocol == CONNECTION_PROTOCOL_TLS) {
+        conn->protocol = CONNECTION_PROTOCOL_UNKNOWN;
+    }
     if ((conn->role == CONNECTION_ROLE_TYPE_UNKNOWN || conn->protocol == 0) && conn->ssl == ssl) {
         struct socket_buffer_reader_t *buf_reader = read_socket_data(args->buf, args->iovec, bytes_count);
         if (buf_reader != NULL) {
             msg_type = analyze_protocol(buf_reader->buffer, buf_reader->data_len, &conn->protocol);
+            // the TLS handshake is detected for collecting the handshake metadata
+            if (conn->protocol == CONNECTION_PROTOCOL_UNKNOWN && ssl == false) {
+                msg_type = infer_tls_message(buf_reader->buffer, buf_reader->data_len);
+                if (msg_type != CONNECTION_MESSAGE_TYPE_UNKNOWN) {
+                    conn->protocol = CONNECTION_PROTOCOL_TLS;
+                }
+            }
             // if send request data to remote address or receive response data from remote address
             // then, recognized current connection is client
             if ((msg_type == CONNECTION_MESSAGE_TYPE_REQUEST && data_direction == SOCK_DATA_DIRECTION_EGRESS) ||
@@ -130,6 +206,10 @@ static __always_inline void process_write_data(void *ctx, __u64 id, struct sock_
                 conn->role = CONNECTION_ROLE_TYPE_SERVER;
             }
         }
+        // the protocol cannot be detected by the content, then try to detect by the registered ports
+        if (conn->protocol == CONNECTION_PROTOCOL_UNKNOWN) {
+            analyze_protocol_by_port(conn);
+        }
     }
 
     __u64 conid = gen_tgid_fd(tgid, args->fd);
@@ -169,11 +249,31 @@ static __always_inline void process_write_data(void *ctx, __u64 id, struct sock_
             detail->l3_total_recv_time = args->l3_rcv_duration;
             detail->l2_enter_queue_count = args->l2_enter_queue_count;
             detail->l4_package_rcv_from_queue_time = args->total_package_receive_from_queue_time;
+            detail->send_buffer_full_count = args->send_buffer_full_count;
+            detail->receive_buffer_drop_count = args->receive_buffer_drop_count;
+            detail->buffer_pressure = args->buffer_pressure;
+            detail->send_buffer_wait_duration = args->send_buffer_wait_time;
+            detail->receive_wait_duration = args->receive_wait_time;
 
             rover_submit_buf(ctx, &socket_detail_queue, detail, sizeof(*detail));
         }
     }
 
+    // only the handshake of TLS is uploaded, the encrypted data is uploaded when the process exports the TLS key log
+    __u8 skip_data_upload = conn->skip_data_upload;
+    if (conn->protocol == CONNECTION_PROTOCOL_TLS && ssl == false && tgid_should_decrypt_tls(tgid) == false) {
+        if (conn->tls_handshake_uploads >= TLS_MAX_HANDSHAKE_UPLOADS) {
+            skip_data_upload = 1;
+        } else {
+            conn->tls_handshake_uploads++;
+        }
+    }
+
+    // sample the socket data by connections when the events are dropping, the connection and detail events are always kept
+    if (skip_data_upload == 0 && socket_data_sampled_out(conn->random_id)) {
+        skip_data_upload = 1;
+    }
+
     // upload the socket data if need
     struct upload_data_args *upload_data_args = generate_socket_upload_args();
     if (upload_data_args != NULL) {
@@ -190,7 +290,7 @@ static __always_inline void process_write_data(void *ctx, __u64 id, struct sock_
         upload_data_args->connection_protocol = conn->protocol;
         upload_data_args->connection_ssl = conn->ssl;
         upload_data_args->socket_ssl_buffer_force_unfinished = args->ssl_buffer_force_unfinished;
-        upload_data_args->connection_skip_data_upload = conn->skip_data_upload;
+        upload_data_args->connection_skip_data_upload = skip_data_upload;
         upload_data_args->socket_data_ssl = ssl;
         upload_socket_data(ctx, upload_data_args);
     };
@@ -64,7 +64,8 @@ static int get_fd(uint32_t tgid, bool read, void* ssl) {
         return fd;
     }
 
-    return 0;
+    // the SSL read may be served from the buffered data without any socket syscall, so use the learned fd
+    return get_cached_openssl_fd(tgid, ssl);
 }
 
 SEC("uprobe/ssl_write")
@@ -78,6 +79,7 @@ int openssl_write(struct pt_regs* ctx) {
     char* buf = (char*)PT_REGS_PARM2(ctx);
     struct sock_data_args_t data_args = {};
     data_args.fd = fd;
+    data_args.ssl = ssl;
     data_args.buf = buf;
     data_args.start_nacs = bpf_ktime_get_ns();
     bpf_map_update_elem(&openssl_sock_data_args, &id, &data_args, 0);
@@ -91,6 +93,7 @@ int openssl_write_ret(struct pt_regs* ctx) {
     __u64 id = bpf_get_current_pid_tgid();
     struct sock_data_args_t *args = bpf_map_lookup_elem(&openssl_sock_data_args, &id);
     if (args && args->fd > 0) {
+        update_cached_openssl_fd(id >> 32, args->ssl, args->fd);
         args->data_id = get_socket_data_id(2, id, args->fd);
         process_openssl_data(ctx, id, SOCK_DATA_DIRECTION_EGRESS, args, SOCKET_OPTS_TYPE_SSL_WRITE);
     }
@@ -110,6 +113,7 @@ int openssl_read(struct pt_regs* ctx) {
     size_t excepted_size = PT_REGS_PARM3(ctx);
     struct sock_data_args_t data_args = {};
     data_args.fd = fd;
+    data_args.ssl = ssl;
     data_args.buf = buf;
     data_args.excepted_size = excepted_size;
     data_args.start_nacs = bpf_ktime_get_ns();
@@ -124,6 +128,7 @@ int openssl_read_ret(struct pt_regs* ctx) {
     __u64 id = bpf_get_current_pid_tgid();
     struct sock_data_args_t *args = bpf_map_lookup_elem(&openssl_sock_data_args, &id);
     if (args && args->fd > 0) {
+        update_cached_openssl_fd(id >> 32, args->ssl, args->fd);
         args->data_id = get_socket_data_id(4, id, args->fd);
         process_openssl_data(ctx, id, SOCK_DATA_DIRECTION_INGRESS, args, SOCKET_OPTS_TYPE_SSL_READ);
     }
@@ -15,3 +15,38 @@
 // specific language governing permissions and limitations
 // under the License.
 
+
+#pragma once
+
+// the SSL instance and the socket fd, the fd of the statically linked BoringSSL(such as Envoy) cannot be read
+// through the offsets, so it's learned from the socket syscalls inside the SSL read/write
+struct openssl_fd_key_t {
+    __u32 tgid;
+    __u32 pad;
+    void* ssl;
+};
+
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, struct openssl_fd_key_t);
+	__type(value, __u32);
+} openssl_fd_cache SEC(".maps");
+
+static __inline __u32 get_cached_openssl_fd(__u32 tgid, void* ssl) {
+    struct openssl_fd_key_t key = {};
+    key.tgid = tgid;
+    key.ssl = ssl;
+    __u32 *fd = bpf_map_lookup_elem(&openssl_fd_cache, &key);
+    if (fd == NULL) {
+        return 0;
+    }
+    return *fd;
+}
+
+static __inline void update_cached_openssl_fd(__u32 tgid, void* ssl, __u32 fd) {
+    struct openssl_fd_key_t key = {};
+    key.tgid = tgid;
+    key.ssl = ssl;
+    bpf_map_update_elem(&openssl_fd_cache, &key, &fd, 0);
+}
@@ -0,0 +1,81 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#include "rustls.h"
+
+// <rustls::conn::Writer as std::io::Write>::write(&mut self, buf: &[u8]) -> io::Result<usize>
+SEC("uprobe/rustls_write")
+int rustls_write(struct pt_regs* ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t data_args = {};
+    data_args.buf = (char*)PT_REGS_PARM2(ctx);
+    data_args.start_nacs = bpf_ktime_get_ns();
+    bpf_map_update_elem(&rustls_sock_data_args, &id, &data_args, 0);
+    return 0;
+}
+
+SEC("uretprobe/rustls_write")
+int rustls_write_ret(struct pt_regs* ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *args = bpf_map_lookup_elem(&rustls_sock_data_args, &id);
+    if (args == NULL) {
+        return 0;
+    }
+    __u64 written = RUST_IO_RESULT_VALUE(ctx);
+    if (PT_REGS_RC(ctx) == 0 && written > 0) {
+        // the plaintext is only buffered, uploading when the encrypted data is written to the socket
+        args->excepted_size = written;
+        bpf_map_update_elem(&rustls_pending_write, &id, args, 0);
+    }
+    bpf_map_delete_elem(&rustls_sock_data_args, &id);
+    return 0;
+}
+
+// <rustls::conn::Reader as std::io::Read>::read(&mut self, buf: &mut [u8]) -> io::Result<usize>
+SEC("uprobe/rustls_read")
+int rustls_read(struct pt_regs* ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    __u32 *fd = bpf_map_lookup_elem(&rustls_last_read_fd, &id);
+    if (fd == NULL) {
+        return 0;
+    }
+    struct sock_data_args_t data_args = {};
+    data_args.fd = *fd;
+    data_args.buf = (char*)PT_REGS_PARM2(ctx);
+    data_args.excepted_size = PT_REGS_PARM3(ctx);
+    data_args.start_nacs = bpf_ktime_get_ns();
+    bpf_map_update_elem(&rustls_sock_data_args, &id, &data_args, 0);
+
+    set_conn_as_ssl(ctx, id >> 32, data_args.fd, SOCKET_OPTS_TYPE_SSL_READ);
+    return 0;
+}
+
+SEC("uretprobe/rustls_read")
+int rustls_read_ret(struct pt_regs* ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    struct sock_data_args_t *args = bpf_map_lookup_elem(&rustls_sock_data_args, &id);
+    if (args == NULL) {
+        return 0;
+    }
+    __u64 read = RUST_IO_RESULT_VALUE(ctx);
+    if (PT_REGS_RC(ctx) == 0 && read > 0 && args->fd > 0) {
+        args->data_id = get_socket_data_id(11, id, args->fd);
+        process_write_data(ctx, id, args, read, SOCK_DATA_DIRECTION_INGRESS, false, SOCKET_OPTS_TYPE_SSL_READ, true);
+    }
+    bpf_map_delete_elem(&rustls_sock_data_args, &id);
+    return 0;
+}
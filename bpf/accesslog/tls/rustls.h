@@ -0,0 +1,87 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#pragma once
+
+#include "tls.h"
+
+// the io::Result<usize> is returned through two registers in the Rust ABI, the first is the discriminant(0 means Ok),
+// and the second is the value
+#if defined(bpf_target_x86)
+#define RUST_IO_RESULT_VALUE(x) PT_REGS_PARM3(x)
+#else
+#define RUST_IO_RESULT_VALUE(x) PT_REGS_PARM2(x)
+#endif
+
+// the processes which linked the rustls, for reducing the socket fd tracking of the other processes
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 1000);
+	__type(key, __u32);
+	__type(value, __u8);
+} rustls_process_map SEC(".maps");
+
+// the rustls read or write, the plaintext is buffered in the rustls, so the fd is correlated
+// through the socket syscalls in the same thread
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, struct sock_data_args_t);
+} rustls_sock_data_args SEC(".maps");
+
+// the written plaintext which waiting for the next socket write of the same thread
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, struct sock_data_args_t);
+} rustls_pending_write SEC(".maps");
+
+// the last socket fd read by the thread, the rustls reads the plaintext after the encrypted data is read
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__uint(max_entries, 10000);
+	__type(key, __u64);
+	__type(value, __u32);
+} rustls_last_read_fd SEC(".maps");
+
+static __inline bool tgid_linked_rustls(__u32 tgid) {
+    return bpf_map_lookup_elem(&rustls_process_map, &tgid) != NULL;
+}
+
+// called when the socket reading in the syscall, the transfer.h must be included before
+static __always_inline void rustls_socket_read(__u64 id, __u32 fd) {
+    if (tgid_linked_rustls(id >> 32) == false) {
+        return;
+    }
+    bpf_map_update_elem(&rustls_last_read_fd, &id, &fd, 0);
+}
+
+// called when the socket writing in the syscall, upload the pending plaintext of the same thread
+static __always_inline void rustls_socket_write(void *ctx, __u64 id, __u32 fd) {
+    struct sock_data_args_t *args = bpf_map_lookup_elem(&rustls_pending_write, &id);
+    if (args == NULL) {
+        return;
+    }
+    __u32 tgid = id >> 32;
+    args->fd = fd;
+    args->data_id = get_socket_data_id(10, id, fd);
+    set_conn_as_ssl(ctx, tgid, fd, SOCKET_OPTS_TYPE_SSL_WRITE);
+    process_write_data(ctx, id, args, args->excepted_size, SOCK_DATA_DIRECTION_EGRESS, false, SOCKET_OPTS_TYPE_SSL_WRITE, true);
+    bpf_map_delete_elem(&rustls_pending_write, &id);
+}
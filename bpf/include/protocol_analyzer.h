@@ -20,6 +20,7 @@
 #define CONNECTION_PROTOCOL_UNKNOWN 0
 #define CONNECTION_PROTOCOL_HTTP1 1
 #define CONNECTION_PROTOCOL_HTTP2 2
+#define CONNECTION_PROTOCOL_TLS 3
 
 #define CONNECTION_MESSAGE_TYPE_UNKNOWN 0
 #define CONNECTION_MESSAGE_TYPE_REQUEST 1
@@ -178,6 +179,27 @@ static __inline __u32 infer_http2_message(const char* buf, size_t count) {
 	return CONNECTION_MESSAGE_TYPE_UNKNOWN;
 }
 
+// TLS handshake record, for collecting the handshake metadata and the key log decryption
+// record format: https://www.rfc-editor.org/rfc/rfc8446#section-5.1
+static __inline __u32 infer_tls_message(const char* buf, size_t count) {
+    if (count < 6) {
+        return CONNECTION_MESSAGE_TYPE_UNKNOWN;
+    }
+    // handshake record with version 3.x
+    if (buf[0] != 0x16 || buf[1] != 0x03 || buf[2] > 0x04) {
+        return CONNECTION_MESSAGE_TYPE_UNKNOWN;
+    }
+    // client hello
+    if (buf[5] == 0x01) {
+        return CONNECTION_MESSAGE_TYPE_REQUEST;
+    }
+    // server hello
+    if (buf[5] == 0x02) {
+        return CONNECTION_MESSAGE_TYPE_RESPONSE;
+    }
+    return CONNECTION_MESSAGE_TYPE_UNKNOWN;
+}
+
 static __inline __u32 analyze_protocol(char *buf, __u32 count, __u8 *protocol_ref) {
     __u32 protocol = CONNECTION_PROTOCOL_UNKNOWN, type = CONNECTION_MESSAGE_TYPE_UNKNOWN;
 
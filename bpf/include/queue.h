@@ -19,11 +19,18 @@
 
 #include "api.h"
 
+// the data queues are declared as the ring buffer, the user space downgrades them to the perf event array
+// when the ring buffer is disabled or not supported by the kernel(before 5.8), and resizes them when loading
 #define DATA_QUEUE(name)                            \
 	struct {                                        \
-		__uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);\
+		__uint(type, BPF_MAP_TYPE_RINGBUF);         \
+		__uint(max_entries, 256 * 1024);            \
 	} name SEC(".maps");
 
+// whether the data queues are the ring buffer, rewritten by the user space when loading,
+// the branches of the other transport are pruned by the verifier as the dead code
+const volatile __u8 rover_ringbuf_enabled = 0;
+
 struct {
 	__uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
 	__uint(max_entries, 1);
@@ -31,15 +38,49 @@ struct {
 	__uint(value_size, 30960); // all events are less than 30KB
 } rover_data_heap SEC(".maps");
 
+// the count of the events failed to submit into the queues(such as the perf buffer is full)
+struct {
+	__uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+	__uint(max_entries, 1);
+	__type(key, __u32);
+	__type(value, __u64);
+} rover_queue_submit_failure SEC(".maps");
+
+static __always_inline void rover_increase_submit_failure() {
+	__u32 zero = 0;
+	__u64 *failures = bpf_map_lookup_elem(&rover_queue_submit_failure, &zero);
+	if (failures != NULL) {
+		(*failures)++;
+	}
+}
+
 static __always_inline void *rover_reserve_buf(void *map, __u64 size) {
 	static const int zero = 0;
 
+	if (rover_ringbuf_enabled) {
+		void *buf = bpf_ringbuf_reserve(map, size, 0);
+		if (buf == NULL) {
+			rover_increase_submit_failure();
+		}
+		return buf;
+	}
 	return bpf_map_lookup_elem(&rover_data_heap, &zero);
 }
 
 static __always_inline void rover_discard_buf(void *buf) {
+	if (rover_ringbuf_enabled) {
+		bpf_ringbuf_discard(buf, 0);
+	}
 }
 
 static __always_inline long rover_submit_buf(void *ctx, void *map, void *buf, __u64 size) {
-	return bpf_perf_event_output(ctx, map, BPF_F_CURRENT_CPU, buf, size);
-}
\ No newline at end of file
+	if (rover_ringbuf_enabled) {
+		bpf_ringbuf_submit(buf, 0);
+		return 0;
+	}
+	long ret = bpf_perf_event_output(ctx, map, BPF_F_CURRENT_CPU, buf, size);
+	if (ret != 0) {
+		rover_increase_submit_failure();
+	}
+	return ret;
+}
@@ -54,6 +54,7 @@ struct sock_common {
 		} __attribute__((preserve_access_index));
 	};
 	short unsigned int skc_family;
+	volatile unsigned char	skc_state;
 	struct in6_addr_redefine		skc_v6_daddr;
     struct in6_addr_redefine		skc_v6_rcv_saddr;
 } __attribute__((preserve_access_index));
@@ -75,10 +76,16 @@ struct iov_iter {
 	};
 } __attribute__((preserve_access_index));
 
+struct atomic_t_redefine {
+	int counter;
+} __attribute__((preserve_access_index));
+
 struct sock {
     struct sock_common	__sk_common;
+	struct atomic_t_redefine	sk_drops;
 	struct socket		*sk_socket;
 	__u32			sk_max_ack_backlog;
+	int			sk_err;
 } __attribute__((preserve_access_index));
 
 struct tcp_sock {
@@ -87,6 +94,12 @@ struct tcp_sock {
     __u32 write_seq;
     __u32 packets_out;
     __u32 retrans_out;
+    __u32 snd_wnd;
+    __u32 rcv_wnd;
+} __attribute__((preserve_access_index));
+
+struct inet_connection_sock {
+	__u8 icsk_probes_out;
 } __attribute__((preserve_access_index));
 
 struct user_msghdr {
@@ -55,3 +55,10 @@
 // data direction
 #define SOCK_DATA_DIRECTION_INGRESS 1 //receive from
 #define SOCK_DATA_DIRECTION_EGRESS 2  //write to
+
+// the socket buffer pressure flags when transferring data
+#define SOCKET_BUFFER_PRESSURE_PEER_ZERO_WINDOW         1   // the peer advertised zero window
+#define SOCKET_BUFFER_PRESSURE_PEER_ZERO_WINDOW_PROBE   2   // the zero window is persistent, the probes has been sent
+#define SOCKET_BUFFER_PRESSURE_LOCAL_ZERO_WINDOW        4   // the local advertised zero window
+#define SOCKET_BUFFER_PRESSURE_SEND_BUFFER_FULL         8   // waiting for the send buffer memory
+#define SOCKET_BUFFER_PRESSURE_RECEIVE_BUFFER_DROP      16  // the receive queue dropped packets
@@ -0,0 +1,72 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+
+#include "api.h"
+
+char __license[] SEC("license") = "Dual MIT/GPL";
+
+// continue to the next program or the default action, same with the TC_ACT_UNSPEC and TCX_NEXT
+#define TC_NEXT -1
+
+#define TRAFFIC_DIRECTION_INGRESS 0
+#define TRAFFIC_DIRECTION_EGRESS 1
+
+struct interface_traffic_key {
+    __u32 ifindex;
+    __u32 direction;
+};
+
+struct interface_traffic_value {
+    __u64 bytes;
+    __u64 packets;
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_HASH);
+    __uint(max_entries, 65536);
+    __type(key, struct interface_traffic_key);
+    __type(value, struct interface_traffic_value);
+} interface_traffic SEC(".maps");
+
+static __always_inline void record_interface_traffic(struct __sk_buff *skb, __u32 direction) {
+    struct interface_traffic_key key = {};
+    key.ifindex = skb->ifindex;
+    key.direction = direction;
+    struct interface_traffic_value *value = bpf_map_lookup_elem(&interface_traffic, &key);
+    if (value != NULL) {
+        value->bytes += skb->len;
+        value->packets++;
+        return;
+    }
+    struct interface_traffic_value init = {};
+    init.bytes = skb->len;
+    init.packets = 1;
+    bpf_map_update_elem(&interface_traffic, &key, &init, BPF_NOEXIST);
+}
+
+SEC("tc")
+int tc_ingress(struct __sk_buff *skb) {
+    record_interface_traffic(skb, TRAFFIC_DIRECTION_INGRESS);
+    return TC_NEXT;
+}
+
+SEC("tc")
+int tc_egress(struct __sk_buff *skb) {
+    record_interface_traffic(skb, TRAFFIC_DIRECTION_EGRESS);
+    return TC_NEXT;
+}
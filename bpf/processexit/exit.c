@@ -0,0 +1,86 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+
+#include "api.h"
+
+char __license[] SEC("license") = "Dual MIT/GPL";
+
+#define PROCESS_EXIT_TYPE_SIGNAL 1
+#define PROCESS_EXIT_TYPE_OOM_KILL 2
+
+struct process_exit_event {
+    __u32 pid;
+    __u32 type;
+    __u32 exit_code;
+    __u32 reserve;
+    __u64 timestamp;
+};
+
+struct {
+	__uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
+} process_exit_queue SEC(".maps");
+
+// only declare the fields which used in this file, the real layout is relocated by CO-RE
+struct signal_struct___exit {
+    struct {
+        int counter;
+    } live;
+} __attribute__((preserve_access_index));
+
+struct task_struct___exit {
+    __u32 tgid;
+    int exit_code;
+    struct signal_struct___exit *signal;
+} __attribute__((preserve_access_index));
+
+struct trace_event_raw_mark_victim {
+    struct trace_entry ent;
+    int pid;
+} __attribute__((preserve_access_index));
+
+SEC("tracepoint/sched/sched_process_exit")
+int tracepoint_sched_process_exit(void *ctx) {
+    struct task_struct___exit *task = (void *) bpf_get_current_task();
+    // only the last exited thread of the process should be reported
+    if (BPF_CORE_READ(task, signal, live.counter) != 0) {
+        return 0;
+    }
+    int exit_code = BPF_CORE_READ(task, exit_code);
+    // the lower 7 bits is the signal which terminated the process, zero means exited normally
+    if ((exit_code & 0x7f) == 0) {
+        return 0;
+    }
+
+    struct process_exit_event event = {};
+    event.pid = BPF_CORE_READ(task, tgid);
+    event.type = PROCESS_EXIT_TYPE_SIGNAL;
+    event.exit_code = exit_code;
+    event.timestamp = bpf_ktime_get_ns();
+    bpf_perf_event_output(ctx, &process_exit_queue, BPF_F_CURRENT_CPU, &event, sizeof(event));
+    return 0;
+}
+
+SEC("tracepoint/oom/mark_victim")
+int tracepoint_oom_mark_victim(struct trace_event_raw_mark_victim *ctx) {
+    struct process_exit_event event = {};
+    event.pid = ctx->pid;
+    event.type = PROCESS_EXIT_TYPE_OOM_KILL;
+    event.timestamp = bpf_ktime_get_ns();
+    bpf_perf_event_output(ctx, &process_exit_queue, BPF_F_CURRENT_CPU, &event, sizeof(event));
+    return 0;
+}
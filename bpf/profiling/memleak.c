@@ -0,0 +1,151 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+#include "api.h"
+#include "memleak.h"
+
+char __license[] SEC("license") = "Dual MIT/GPL";
+
+static __always_inline bool is_monitor_process(__u64 pid_tgid) {
+    int monitor_pid;
+    asm("%0 = MONITOR_PID ll" : "=r"(monitor_pid));
+    return (pid_tgid >> 32) == monitor_pid;
+}
+
+static __always_inline int alloc_enter(__u64 size) {
+    __u64 id = bpf_get_current_pid_tgid();
+    if (!is_monitor_process(id) || size == 0) {
+        return 0;
+    }
+    // only track one of each sample rate allocations
+    int sample_rate;
+    asm("%0 = SAMPLE_RATE ll" : "=r"(sample_rate));
+    if (sample_rate > 1 && bpf_get_prandom_u32() % sample_rate != 0) {
+        return 0;
+    }
+    __u32 tid = id;
+    bpf_map_update_elem(&alloc_sizes, &tid, &size, BPF_ANY);
+    return 0;
+}
+
+static __always_inline int alloc_exit_with_addr(struct pt_regs *ctx, __u32 tid, __u64 addr) {
+    __u64 *size = bpf_map_lookup_elem(&alloc_sizes, &tid);
+    if (size == NULL) {
+        return 0;
+    }
+    struct alloc_info_t info = {};
+    info.size = *size;
+    bpf_map_delete_elem(&alloc_sizes, &tid);
+
+    if (addr == 0) {
+        return 0;
+    }
+    info.timestamp = bpf_ktime_get_ns();
+    info.user_stack_id = bpf_get_stackid(ctx, &stacks, BPF_F_USER_STACK);
+    bpf_map_update_elem(&allocations, &addr, &info, BPF_ANY);
+    return 0;
+}
+
+static __always_inline int alloc_exit(struct pt_regs *ctx) {
+    return alloc_exit_with_addr(ctx, bpf_get_current_pid_tgid(), PT_REGS_RC(ctx));
+}
+
+static __always_inline int free_enter(__u64 addr) {
+    if (!is_monitor_process(bpf_get_current_pid_tgid()) || addr == 0) {
+        return 0;
+    }
+    bpf_map_delete_elem(&allocations, &addr);
+    return 0;
+}
+
+SEC("uprobe/malloc")
+int malloc_enter(struct pt_regs *ctx) {
+    return alloc_enter(PT_REGS_PARM1(ctx));
+}
+
+SEC("uretprobe/malloc")
+int malloc_exit(struct pt_regs *ctx) {
+    return alloc_exit(ctx);
+}
+
+SEC("uprobe/calloc")
+int calloc_enter(struct pt_regs *ctx) {
+    return alloc_enter(PT_REGS_PARM1(ctx) * PT_REGS_PARM2(ctx));
+}
+
+SEC("uretprobe/calloc")
+int calloc_exit(struct pt_regs *ctx) {
+    return alloc_exit(ctx);
+}
+
+SEC("uprobe/realloc")
+int realloc_enter(struct pt_regs *ctx) {
+    // the original address is freed by the realloc
+    free_enter(PT_REGS_PARM1(ctx));
+    return alloc_enter(PT_REGS_PARM2(ctx));
+}
+
+SEC("uretprobe/realloc")
+int realloc_exit(struct pt_regs *ctx) {
+    return alloc_exit(ctx);
+}
+
+SEC("uprobe/free")
+int free_enter_probe(struct pt_regs *ctx) {
+    return free_enter(PT_REGS_PARM1(ctx));
+}
+
+// memalign(alignment, size) and aligned_alloc(alignment, size)
+SEC("uprobe/aligned_alloc")
+int aligned_alloc_enter(struct pt_regs *ctx) {
+    return alloc_enter(PT_REGS_PARM2(ctx));
+}
+
+SEC("uretprobe/aligned_alloc")
+int aligned_alloc_exit(struct pt_regs *ctx) {
+    return alloc_exit(ctx);
+}
+
+// posix_memalign(memptr, alignment, size), the address is written into the memptr when returned zero
+SEC("uprobe/posix_memalign")
+int posix_memalign_enter(struct pt_regs *ctx) {
+    __u64 id = bpf_get_current_pid_tgid();
+    __u32 tid = id;
+    __u64 memptr = PT_REGS_PARM1(ctx);
+    alloc_enter(PT_REGS_PARM3(ctx));
+    if (bpf_map_lookup_elem(&alloc_sizes, &tid) != NULL) {
+        bpf_map_update_elem(&memptrs, &tid, &memptr, BPF_ANY);
+    }
+    return 0;
+}
+
+SEC("uretprobe/posix_memalign")
+int posix_memalign_exit(struct pt_regs *ctx) {
+    __u32 tid = bpf_get_current_pid_tgid();
+    __u64 *memptr = bpf_map_lookup_elem(&memptrs, &tid);
+    if (memptr == NULL) {
+        return 0;
+    }
+    __u64 memptr_addr = *memptr;
+    bpf_map_delete_elem(&memptrs, &tid);
+
+    __u64 addr = 0;
+    if (PT_REGS_RC(ctx) == 0) {
+        bpf_probe_read_user(&addr, sizeof(addr), (void *)memptr_addr);
+    }
+    return alloc_exit_with_addr(ctx, tid, addr);
+}
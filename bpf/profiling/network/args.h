@@ -111,6 +111,9 @@ struct sock_data_args_t {
     __u8 package_count;
     __u64 total_package_size;
     __u32 ifindex;
+    // the latest TCP quality of the socket
+    __u32 srtt_us;
+    __u32 snd_cwnd;
 };
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
@@ -33,17 +33,24 @@ struct {
 	__type(value, struct go_tls_connection_args_t);
 } go_tls_active_connection_args SEC(".maps");
 
+// the max valid fd, for validating the fd read from the connection which type is unknown
+#define GO_TLS_MAX_FD (1 << 20)
+
 static __always_inline int get_fd_from_go_tls_conn(struct go_interface conn, struct go_tls_args_symaddr_t* symaddr) {
     // read connection
     bpf_probe_read(&conn, sizeof(conn), conn.ptr + symaddr->tls_conn_offset);
 
-    if (conn.type != symaddr->tcp_conn_offset) {
+    // the TCP connection type is unknown when the itab symbol is not found(such as stripped binary),
+    // then the connection is trusted as the TCP connection, and the fd is validated below
+    if (symaddr->tcp_conn_offset != 0 && conn.type != symaddr->tcp_conn_offset) {
         return 0;
     }
 
     void* fd_ptr;
     bpf_probe_read(&fd_ptr, sizeof(fd_ptr), conn.ptr);
     __u64 sysfd;
-    bpf_probe_read(&sysfd, sizeof(sysfd), fd_ptr + symaddr->fd_sys_offset);
+    if (bpf_probe_read(&sysfd, sizeof(sysfd), fd_ptr + symaddr->fd_sys_offset) != 0 || sysfd <= 2 || sysfd > GO_TLS_MAX_FD) {
+        return 0;
+    }
     return sysfd;
 }
\ No newline at end of file
@@ -39,6 +39,7 @@
 #include "args.h"
 #include "protocol_analyzer.h"
 #include "socket_detail.h"
+#include "tls_handshake.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
@@ -196,6 +197,8 @@ static __inline void notify_close_connection(struct pt_regs* ctx, __u64 conid, s
     close_event.read_exe_time = con->read_exe_time;
     close_event.write_rtt_count = con->write_rtt_count;
     close_event.write_rtt_time = con->write_rtt_time;
+    close_event.srtt_us = con->srtt_us;
+    close_event.snd_cwnd = con->snd_cwnd;
 
     bpf_perf_event_output(ctx, &socket_close_event_queue, BPF_F_CURRENT_CPU, &close_event, sizeof(close_event));
 }
@@ -209,6 +212,7 @@ static __inline void submit_close_connection(struct pt_regs* ctx, __u32 tgid, __
     }
     notify_close_connection(ctx, conid, con, start_nacs, curr_nacs);
     bpf_map_delete_elem(&active_connection_map, &conid);
+    bpf_map_delete_elem(&tls_handshake_states, &conid);
 }
 
 static __inline struct active_connection_t* get_or_create_active_conn(struct pt_regs *ctx, __u32 tgid, __u32 fd, __u32 func_name) {
@@ -330,6 +334,11 @@ static __always_inline void process_write_data(struct pt_regs *ctx, __u64 id, st
         }
     }
 
+    // detect the TLS handshake from the raw socket data, the handshake is finished before any plaintext been analyzed
+    if (ssl == false && (conn->protocol == CONNECTION_PROTOCOL_UNKNOWN || conn->ssl)) {
+        process_tls_handshake(ctx, conid, conn, args, bytes_count, data_direction);
+    }
+
     // upload the socket detail
     upload_socket_detail(ctx, conid, conn, func_name, args, ssl);
 
@@ -374,6 +383,12 @@ static __always_inline void process_write_data(struct pt_regs *ctx, __u64 id, st
         }
     }
 
+    // TCP quality, keep the latest one
+    if (args->srtt_us > 0 || args->snd_cwnd > 0) {
+        conn->srtt_us = args->srtt_us;
+        conn->snd_cwnd = args->snd_cwnd;
+    }
+
     // RTT
     if (args->rtt_count > 0) {
         conn->write_rtt_count += args->rtt_count;
@@ -1091,12 +1106,34 @@ int sys_close_ret(struct pt_regs* ctx) {
     return 0;
 }
 
+// read the smoothed RTT and congestion window of the TCP socket, for reporting the quality of every connection
+static __inline void read_socket_tcp_quality(struct sock_data_args_t *data_args, struct socket *socket) {
+    if (socket == NULL) {
+        return;
+    }
+    struct sock *s;
+    BPF_CORE_READ_INTO(&s, socket, sk);
+    if (s == NULL) {
+        return;
+    }
+    // the sk_protocol is the bitfield in the old kernels
+    if (BPF_CORE_READ_BITFIELD_PROBED(s, sk_protocol) != IPPROTO_TCP) {
+        return;
+    }
+    struct tcp_sock *tcp_sock = (struct tcp_sock *)s;
+    __u32 srtt;
+    BPF_CORE_READ_INTO(&srtt, tcp_sock, srtt_us);
+    data_args->srtt_us = srtt >> 3;
+    BPF_CORE_READ_INTO(&data_args->snd_cwnd, tcp_sock, snd_cwnd);
+}
+
 SEC("kprobe/security_socket_sendmsg")
 int security_socket_sendmsg(struct pt_regs* ctx) {
     __u64 id = bpf_get_current_pid_tgid();
     struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
     if (data_args != NULL) {
         data_args->is_sock_event = true;
+        read_socket_tcp_quality(data_args, (void *)PT_REGS_PARM1(ctx));
     }
     return 0;
 }
@@ -1107,11 +1144,12 @@ int security_socket_recvmsg(struct pt_regs* ctx) {
     struct sock_data_args_t *data_args = bpf_map_lookup_elem(&socket_data_args, &id);
     if (data_args != NULL) {
         data_args->is_sock_event = true;
+        read_socket_tcp_quality(data_args, (void *)PT_REGS_PARM1(ctx));
     }
     return 0;
 }
 
-static __inline void send_socket_exception_operation_event(struct pt_regs *ctx, __u32 type, struct sock *s) {
+static __inline void send_socket_exception_operation_event(struct pt_regs *ctx, __u32 type, struct sock *s, __u32 drop_reason) {
     __u64 id = bpf_get_current_pid_tgid();
     __u32 tgid = id >> 32;
     // pid is contains
@@ -1122,6 +1160,7 @@ static __inline void send_socket_exception_operation_event(struct pt_regs *ctx,
     struct socket_exception_operation_event_t event = {};
     event.pid = tgid;
     event.type = type;
+    event.drop_reason = drop_reason;
     __u16 skc_family, port;
     BPF_CORE_READ_INTO(&skc_family, s, __sk_common.skc_family);
     event.socket_family = skc_family;
@@ -1143,31 +1182,50 @@ static __inline void send_socket_exception_operation_event(struct pt_regs *ctx,
         return;
     }
 
+    struct tcp_sock *tcp_sock = (struct tcp_sock *)s;
+    __u32 srtt;
+    BPF_CORE_READ_INTO(&srtt, tcp_sock, srtt_us);
+    event.srtt_us = srtt >> 3;
+    BPF_CORE_READ_INTO(&event.snd_cwnd, tcp_sock, snd_cwnd);
+
     bpf_perf_event_output(ctx, &socket_exception_operation_event_queue, BPF_F_CURRENT_CPU, &event, sizeof(event));
 }
 
 SEC("kprobe/tcp_retransmit_skb")
 int tcp_retransmit(struct pt_regs *ctx) {
     struct sock *s = (void *)PT_REGS_PARM1(ctx);
-    send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_RETRANSMIT, s);
+    send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_RETRANSMIT, s, 0);
     return 0;
 }
 
 SEC("kprobe/tcp_drop")
 int tcp_drop(struct pt_regs *ctx) {
     struct sock *s = (void *)PT_REGS_PARM1(ctx);
-    send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_DROP, s);
+    send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_DROP, s, 0);
+    return 0;
+}
+
+SEC("kprobe/tcp_data_queue_ofo")
+int tcp_data_queue_ofo(struct pt_regs *ctx) {
+    struct sock *s = (void *)PT_REGS_PARM1(ctx);
+    send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_OUT_OF_ORDER, s, 0);
     return 0;
 }
 
 SEC("kprobe/kfree_skb_reason")
 int kfree_skb_reason(struct pt_regs *ctx) {
-    struct sock *s = (void *)PT_REGS_PARM1(ctx);
+    struct sk_buff *skb = (void *)PT_REGS_PARM1(ctx);
     enum skb_drop_reason reason = PT_REGS_PARM2(ctx);
+    if (reason <= SKB_DROP_REASON_NOT_SPECIFIED) {
+        return 0;
+    }
 
-    if (reason > SKB_DROP_REASON_NOT_SPECIFIED) {
-        send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_DROP, s);
+    // the first parameter is the skb, so the socket should be read from it
+    struct sock *s = _(skb->sk);
+    if (s == NULL) {
+        return 0;
     }
+    send_socket_exception_operation_event(ctx, SOCKET_EXCEPTION_OPERATION_TYPE_DROP, s, reason);
     return 0;
 }
 
@@ -67,6 +67,10 @@ struct active_connection_t {
     __u8 ssl;
     __u8 fix1;
     __u32 fix2;
+
+    // the latest TCP quality(smoothed RTT and congestion window) of the connection
+    __u32 srtt_us;
+    __u32 snd_cwnd;
 };
 struct {
 	__uint(type, BPF_MAP_TYPE_HASH);
@@ -171,6 +175,10 @@ struct socket_close_event_t {
     // RTT when write
     __u32 write_rtt_count;
     __u32 write_rtt_time;
+
+    // the latest TCP quality of the connection
+    __u32 srtt_us;
+    __u32 snd_cwnd;
 };
 struct {
 	__uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
@@ -232,6 +240,7 @@ static __inline void add_to_socket_connection_stats_histogram(__u64 conid, __u64
 
 #define SOCKET_EXCEPTION_OPERATION_TYPE_RETRANSMIT 1
 #define SOCKET_EXCEPTION_OPERATION_TYPE_DROP 2
+#define SOCKET_EXCEPTION_OPERATION_TYPE_OUT_OF_ORDER 3
 struct socket_exception_operation_event_t {
     __u32 pid;
     // socket type
@@ -246,6 +255,11 @@ struct socket_exception_operation_event_t {
     __u16 local_port;
     // operation type
     __u32 type;
+    // the TCP quality snapshot when the operation happens
+    __u32 srtt_us;
+    __u32 snd_cwnd;
+    // the reason of dropping package, only works for the drop type
+    __u32 drop_reason;
 };
 struct {
 	__uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
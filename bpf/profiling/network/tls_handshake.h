@@ -0,0 +1,136 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+
+#pragma once
+
+#define TLS_HANDSHAKE_DATA_MAX_SIZE 256
+// the max count of events in each connection, avoid to keep tracking the connection which handshake cannot be detected
+#define TLS_HANDSHAKE_MAX_EVENT_COUNT 16
+
+#define TLS_RECORD_CHANGE_CIPHER_SPEC 20
+#define TLS_RECORD_ALERT 21
+#define TLS_RECORD_HANDSHAKE 22
+#define TLS_RECORD_APPLICATION_DATA 23
+#define TLS_HANDSHAKE_CLIENT_HELLO 1
+
+struct tls_handshake_state_t {
+    __u32 event_count;
+    // the data direction of the client hello
+    __u8 client_direction;
+    // the client sent the encrypted record, means the handshake finished
+    __u8 finished;
+};
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __uint(max_entries, 10000);
+    __type(key, __u64);
+    __type(value, struct tls_handshake_state_t);
+} tls_handshake_states SEC(".maps");
+
+struct tls_handshake_event_t {
+    __u64 conid;
+    __u64 random_id;
+    __u64 timestamp;
+    __u32 data_len;
+    __u8 direction;
+    __u8 record_type;
+    __u8 finished;
+    __u8 reserved;
+    char data[TLS_HANDSHAKE_DATA_MAX_SIZE];
+};
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __type(key, __u32);
+    __type(value, struct tls_handshake_event_t);
+    __uint(max_entries, 1);
+} tls_handshake_event_per_cpu_map SEC(".maps");
+struct {
+    __uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
+} tls_handshake_event_queue SEC(".maps");
+
+// send the TLS records of the handshake in the raw socket data, from the client hello to the first encrypted record of the client
+static __always_inline void process_tls_handshake(struct pt_regs *ctx, __u64 conid, struct active_connection_t *conn,
+                                                  struct sock_data_args_t *args, __u64 bytes_count, __u32 data_direction) {
+    if (bytes_count < 6) {
+        return;
+    }
+    struct tls_handshake_state_t *state = bpf_map_lookup_elem(&tls_handshake_states, &conid);
+    if (state != NULL && (state->finished || state->event_count >= TLS_HANDSHAKE_MAX_EVENT_COUNT)) {
+        return;
+    }
+
+    char *data_buf = args->buf;
+    __u64 size = bytes_count;
+    if (data_buf == NULL && args->iovec != NULL) {
+        struct iovec iov;
+        bpf_probe_read(&iov, sizeof(iov), args->iovec);
+        data_buf = (char *)iov.iov_base;
+        if (iov.iov_len < size) {
+            size = iov.iov_len;
+        }
+    }
+    if (data_buf == NULL || size < 6) {
+        return;
+    }
+    char header[6];
+    bpf_probe_read(&header, sizeof(header), data_buf);
+    __u8 record_type = header[0];
+    if (header[1] != 0x03 || record_type < TLS_RECORD_CHANGE_CIPHER_SPEC || record_type > TLS_RECORD_APPLICATION_DATA) {
+        return;
+    }
+    if (state == NULL) {
+        // only start tracking from the client hello
+        if (record_type != TLS_RECORD_HANDSHAKE || header[5] != TLS_HANDSHAKE_CLIENT_HELLO) {
+            return;
+        }
+        struct tls_handshake_state_t new_state = {};
+        new_state.client_direction = data_direction;
+        bpf_map_update_elem(&tls_handshake_states, &conid, &new_state, BPF_ANY);
+        state = bpf_map_lookup_elem(&tls_handshake_states, &conid);
+        if (state == NULL) {
+            return;
+        }
+    }
+    state->event_count++;
+    if (record_type == TLS_RECORD_APPLICATION_DATA && data_direction == state->client_direction) {
+        state->finished = 1;
+    }
+
+    __u32 kZero = 0;
+    struct tls_handshake_event_t *event = bpf_map_lookup_elem(&tls_handshake_event_per_cpu_map, &kZero);
+    if (event == NULL) {
+        return;
+    }
+    event->conid = conid;
+    event->random_id = conn->random_id;
+    event->timestamp = bpf_ktime_get_ns();
+    event->direction = data_direction;
+    event->record_type = record_type;
+    event->finished = state->finished;
+    event->data_len = 0;
+    // only the plaintext records contains the useful data, such as the hello and alert
+    if (record_type == TLS_RECORD_HANDSHAKE || record_type == TLS_RECORD_ALERT) {
+        if (size > TLS_HANDSHAKE_DATA_MAX_SIZE - 1) {
+            size = TLS_HANDSHAKE_DATA_MAX_SIZE - 1;
+        }
+        asm volatile("%[size] &= 0xff;\n" ::[size] "+r"(size) :);
+        bpf_probe_read(&event->data, size & (TLS_HANDSHAKE_DATA_MAX_SIZE - 1), data_buf);
+        event->data_len = size & (TLS_HANDSHAKE_DATA_MAX_SIZE - 1);
+    }
+    bpf_perf_event_output(ctx, &tls_handshake_event_queue, BPF_F_CURRENT_CPU, event, sizeof(*event));
+}
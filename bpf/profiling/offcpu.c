@@ -79,4 +79,50 @@ int do_finish_task_switch(struct pt_regs *ctx) {
     (*val).counts += 1;
     (*val).deltas += t_end - t_start;
     return 0;
-}
\ No newline at end of file
+}
+
+// the scheduler switch tracepoint is called in the context of the previous task, so the stack of the blocked point
+// is recorded when the monitored thread switched out, and the blocked time is aggregated when it switched in again
+SEC("tracepoint/sched/sched_switch")
+int sched_switch(struct trace_event_raw_sched_switch___offcpu *args) {
+    int monitor_pid;
+    asm("%0 = MONITOR_PID ll" : "=r"(monitor_pid));
+
+    __u64 pid_tgid = bpf_get_current_pid_tgid();
+    __u32 pid = pid_tgid;
+    __u32 tgid = pid_tgid >> 32;
+    if (tgid == monitor_pid && (args->prev_state & TASK_BLOCKED_STATE_MASK) != 0) {
+        struct blocked_start_t start = {};
+        start.ts = bpf_ktime_get_ns();
+        start.key.kernel_stack_id = bpf_get_stackid(args, &stacks, 0);
+        start.key.user_stack_id = bpf_get_stackid(args, &stacks, (1ULL << 8));
+        bpf_map_update_elem(&blocked_starts, &pid, &start, BPF_ANY);
+    }
+
+    // only the threads of the monitored process have the start record
+    __u32 next_pid = args->next_pid;
+    struct blocked_start_t *start = bpf_map_lookup_elem(&blocked_starts, &next_pid);
+    if (start == NULL) {
+        return 0;
+    }
+    __u64 t_start = start->ts;
+    struct key_t key = start->key;
+    bpf_map_delete_elem(&blocked_starts, &next_pid);
+    __u64 t_end = bpf_ktime_get_ns();
+    if (t_start > t_end) {
+        return 0;
+    }
+
+    struct value_t *val = bpf_map_lookup_elem(&counts, &key);
+    if (!val) {
+        struct value_t value = {};
+        bpf_map_update_elem(&counts, &key, &value, BPF_NOEXIST);
+        val = bpf_map_lookup_elem(&counts, &key);
+        if (!val) {
+            return 0;
+        }
+    }
+    __sync_fetch_and_add(&val->counts, 1);
+    __sync_fetch_and_add(&val->deltas, t_end - t_start);
+    return 0;
+}
@@ -25,6 +25,12 @@ struct value_t {
     __u64 deltas;
 };
 
+// the blocked thread start time and the stack when it switched out
+struct blocked_start_t {
+    __u64 ts;
+    struct key_t key;
+};
+
 struct {
     __uint(type, BPF_MAP_TYPE_STACK_TRACE);
     __uint(key_size, sizeof(__u32));
@@ -44,4 +50,20 @@ struct {
 	__type(key, struct key_t);
 	__type(value, struct value_t);
 	__uint(max_entries, 10000);
-} counts SEC(".maps");
\ No newline at end of file
+} counts SEC(".maps");
+
+struct {
+	__uint(type, BPF_MAP_TYPE_LRU_HASH);
+	__type(key, __u32);
+	__type(value, struct blocked_start_t);
+	__uint(max_entries, 10000);
+} blocked_starts SEC(".maps");
+
+// only declare the fields which used in this file, the real layout is relocated by CO-RE
+struct trace_event_raw_sched_switch___offcpu {
+    long prev_state;
+    int next_pid;
+} __attribute__((preserve_access_index));
+
+// the task is sleeping(TASK_INTERRUPTIBLE or TASK_UNINTERRUPTIBLE) instead of being preempted when switched out
+#define TASK_BLOCKED_STATE_MASK 0x3
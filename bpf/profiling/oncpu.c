@@ -20,6 +20,55 @@
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
+// walking the CPython frames of current running thread, return 0 when the process is not python
+static __always_inline __u32 python_stack_id() {
+    __u32 zero = 0;
+    struct python_offsets_t *offsets = bpf_map_lookup_elem(&python_offsets, &zero);
+    if (offsets == NULL || offsets->tstate_current_addr == 0) {
+        return 0;
+    }
+    struct python_stack_t *stack = bpf_map_lookup_elem(&python_stack_heap, &zero);
+    if (stack == NULL) {
+        return 0;
+    }
+
+    // only the thread which holding the GIL is running the python code
+    void *tstate = NULL;
+    bpf_probe_read_user(&tstate, sizeof(tstate), (void *)offsets->tstate_current_addr);
+    if (tstate == NULL) {
+        return 0;
+    }
+    void *frame = NULL;
+    bpf_probe_read_user(&frame, sizeof(frame), tstate + offsets->tstate_frame);
+    if (frame != NULL && offsets->cframe_current_frame > 0) {
+        void *cframe = frame;
+        frame = NULL;
+        bpf_probe_read_user(&frame, sizeof(frame), cframe + offsets->cframe_current_frame);
+    }
+    if (frame == NULL) {
+        return 0;
+    }
+
+    __u32 hash = 2166136261;
+#pragma unroll
+    for (int i = 0; i < PYTHON_STACK_MAX_DEPTH; i++) {
+        stack->codes[i] = 0;
+        if (frame == NULL) {
+            continue;
+        }
+        bpf_probe_read_user(&stack->codes[i], sizeof(__u64), frame + offsets->frame_code);
+        hash = (hash ^ (__u32)stack->codes[i]) * 16777619;
+        void *back = NULL;
+        bpf_probe_read_user(&back, sizeof(back), frame + offsets->frame_back);
+        frame = back;
+    }
+    if (hash == 0) {
+        hash = 1;
+    }
+    bpf_map_update_elem(&python_stacks, &hash, stack, BPF_ANY);
+    return hash;
+}
+
 SEC("perf_event")
 int do_perf_event(struct pt_regs *ctx) {
     int monitor_pid;
@@ -38,6 +87,7 @@ int do_perf_event(struct pt_regs *ctx) {
     // get stacks
     key.kernel_stack_id = bpf_get_stackid(ctx, &stacks, 0);
     key.user_stack_id = bpf_get_stackid(ctx, &stacks, BPF_F_USER_STACK);
+    key.python_stack_id = python_stack_id();
 
     __u32 *val;
     val = bpf_map_lookup_elem(&counts, &key);
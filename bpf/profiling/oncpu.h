@@ -18,6 +18,7 @@
 struct key_t {
     __u32 user_stack_id;
     __u32 kernel_stack_id;
+    __u32 python_stack_id;
 };
 
 struct {
@@ -32,4 +33,43 @@ struct {
     __uint(key_size, sizeof(__u32));
     __uint(value_size, 100 * sizeof(__u64));
     __uint(max_entries, 10000);
-} stacks SEC(".maps");
\ No newline at end of file
+} stacks SEC(".maps");
+#define PYTHON_STACK_MAX_DEPTH 32
+
+// the CPython structure offsets of the monitored process, provided by the user space
+struct python_offsets_t {
+    // the address of the current running thread state pointer(_PyRuntime.gilstate.tstate_current)
+    __u64 tstate_current_addr;
+    // the offset of the top frame in the PyThreadState, it's the "cframe" since python 3.11
+    __u32 tstate_frame;
+    // the offset of the current frame in the _PyCFrame, 0 means the frame is read from the thread state directly
+    __u32 cframe_current_frame;
+    __u32 frame_back;
+    __u32 frame_code;
+};
+
+struct {
+	__uint(type, BPF_MAP_TYPE_ARRAY);
+	__type(key, __u32);
+	__type(value, struct python_offsets_t);
+	__uint(max_entries, 1);
+} python_offsets SEC(".maps");
+
+// the code object addresses of the python stack, the top frame first
+struct python_stack_t {
+    __u64 codes[PYTHON_STACK_MAX_DEPTH];
+};
+
+struct {
+	__uint(type, BPF_MAP_TYPE_HASH);
+	__type(key, __u32);
+	__type(value, struct python_stack_t);
+	__uint(max_entries, 10000);
+} python_stacks SEC(".maps");
+
+struct {
+	__uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+	__type(key, __u32);
+	__type(value, struct python_stack_t);
+	__uint(max_entries, 1);
+} python_stack_heap SEC(".maps");
@@ -77,7 +77,7 @@ func check(configPath, outputPath, format string) error {
 
 	notify := make(chan bool, 1)
 	go func(notify chan bool) {
-		err = boot.RunModules(context.Background(), configPath, func(manager *module.Manager) {
+		err = boot.RunModules(context.Background(), configPath, 0, func(manager *module.Manager) {
 			// startup success
 			processModuleStartSuccess(notify, nil, manager, outFile, format)
 		})
@@ -0,0 +1,104 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/apache/skywalking-rover/pkg/boot"
+)
+
+func newDebugCmd() *cobra.Command {
+	configPath := ""
+	outputFormat := ""
+	var processWait time.Duration
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "verify the config and the kernel capabilities in the current node without starting the collection",
+		// the usage is not helpful when the config is invalid
+		SilenceUsage: true,
+		RunE: func(*cobra.Command, []string) error {
+			report := boot.DryRun(context.Background(), configPath, processWait)
+			if err := writeDryRunReport(report, os.Stdout, outputFormat); err != nil {
+				return err
+			}
+			if report.ConfigError != "" {
+				return fmt.Errorf("the config is invalid: %s", report.ConfigError)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", "configs/rover_configs.yaml", "the rover config file path")
+	cmd.Flags().StringVarP(&outputFormat, "format", "f", "plain", "the debug output format, support \"json\", \"plain\"")
+	cmd.Flags().DurationVar(&processWait, "process-wait", time.Second*10,
+		"the duration of waiting the processes detected, the processes are not detected when zero")
+	return cmd
+}
+
+func writeDryRunReport(report *boot.DryRunReport, writer io.Writer, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	builder := &strings.Builder{}
+	configStatus := "valid"
+	if report.ConfigError != "" {
+		configStatus = report.ConfigError
+	}
+	fmt.Fprintf(builder, "Config: %s\nKernel: %s\n\nCapabilities:\n", configStatus, report.Capability.Kernel)
+	for _, c := range report.Capability.Capabilities {
+		if c.Supported {
+			fmt.Fprintf(builder, "  %-18s supported\n", c.Name)
+		} else {
+			fmt.Fprintf(builder, "  %-18s unsupported: %s\n", c.Name, c.Reason)
+		}
+	}
+	builder.WriteString("\nModules:\n")
+	for _, m := range report.Modules {
+		fmt.Fprintf(builder, "  %-18s %s", m.Name, m.Status)
+		if m.Reason != "" {
+			fmt.Fprintf(builder, ": %s", m.Reason)
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\nProcesses:\n")
+	if report.ProcessError != "" {
+		fmt.Fprintf(builder, "  detect failure: %s\n", report.ProcessError)
+	}
+	for _, p := range report.Processes {
+		fmt.Fprintf(builder, "  %d %s service: %s, instance: %s, process: %s, monitored: %t",
+			p.Pid, p.DetectType, p.Service, p.Instance, p.Process, p.Monitored)
+		if len(p.Modules) > 0 {
+			fmt.Fprintf(builder, ", modules: %s", strings.Join(p.Modules, ","))
+		}
+		builder.WriteString("\n")
+	}
+	_, err := writer.Write([]byte(builder.String()))
+	return err
+}
@@ -0,0 +1,113 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type pcapOptions struct {
+	address    string
+	pid        int
+	service    string
+	protocol   string
+	localIP    string
+	localPort  int
+	remoteIP   string
+	remotePort int
+	duration   time.Duration
+	maxSize    string
+	output     string
+	remoteFile bool
+}
+
+func newPcapCmd() *cobra.Command {
+	options := &pcapOptions{}
+	cmd := &cobra.Command{
+		Use:   "pcap",
+		Short: "capture the packets of a connection through the running rover",
+		RunE: func(*cobra.Command, []string) error {
+			return capturePackets(options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.address, "address", "a", "127.0.0.1:6063", "the address of the rover pcap server")
+	cmd.Flags().IntVar(&options.pid, "pid", 0, "capture in the network namespace of the process")
+	cmd.Flags().StringVar(&options.service, "service", "", "capture in the network namespace of the first process of the service")
+	cmd.Flags().StringVar(&options.protocol, "protocol", "", "the transport protocol, support \"tcp\", \"udp\", empty means both")
+	cmd.Flags().StringVar(&options.localIP, "local-ip", "", "the local address of the connection")
+	cmd.Flags().IntVar(&options.localPort, "local-port", 0, "the local port of the connection")
+	cmd.Flags().StringVar(&options.remoteIP, "remote-ip", "", "the remote address of the connection")
+	cmd.Flags().IntVar(&options.remotePort, "remote-port", 0, "the remote port of the connection")
+	cmd.Flags().DurationVarP(&options.duration, "duration", "d", 10*time.Second, "the duration of the capture")
+	cmd.Flags().StringVar(&options.maxSize, "max-size", "", "the max size of the capture, limited by the rover config")
+	cmd.Flags().StringVarP(&options.output, "output", "o", "capture.pcap", "the output pcap file, \"-\" means the stdout")
+	cmd.Flags().BoolVar(&options.remoteFile, "remote-file", false, "write the capture file in the rover instead of streaming back")
+	return cmd
+}
+
+func capturePackets(options *pcapOptions) error {
+	query := url.Values{}
+	addIfNotEmpty := func(key, value string) {
+		if value != "" && value != "0" {
+			query.Set(key, value)
+		}
+	}
+	addIfNotEmpty("pid", strconv.Itoa(options.pid))
+	addIfNotEmpty("service", options.service)
+	addIfNotEmpty("protocol", options.protocol)
+	addIfNotEmpty("local_ip", options.localIP)
+	addIfNotEmpty("local_port", strconv.Itoa(options.localPort))
+	addIfNotEmpty("remote_ip", options.remoteIP)
+	addIfNotEmpty("remote_port", strconv.Itoa(options.remotePort))
+	addIfNotEmpty("duration", options.duration.String())
+	addIfNotEmpty("max_size", options.maxSize)
+	if options.remoteFile {
+		query.Set("output", "file")
+	}
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/pcap?%s", options.address, query.Encode()))
+	if err != nil {
+		return fmt.Errorf("request the capture failure: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("capture failure, status: %d, message: %s", resp.StatusCode, message)
+	}
+
+	var writer io.Writer = os.Stdout
+	if options.output != "-" && !options.remoteFile {
+		file, err := os.Create(options.output)
+		if err != nil {
+			return fmt.Errorf("create the output file failure: %v", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	_, err = io.Copy(writer, resp.Body)
+	return err
+}
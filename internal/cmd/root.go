@@ -27,5 +27,7 @@ func NewRoot() *cobra.Command {
 	}
 	cmd.AddCommand(newStartCmd())
 	cmd.AddCommand(newCheckCmd())
+	cmd.AddCommand(newDebugCmd())
+	cmd.AddCommand(newPcapCmd())
 	return cmd
 }
@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -27,6 +28,7 @@ import (
 
 func newStartCmd() *cobra.Command {
 	configPath := ""
+	var reloadPeriod time.Duration
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "start the rover",
@@ -34,10 +36,12 @@ func newStartCmd() *cobra.Command {
 			ctx := context.Background()
 
 			// run modules
-			return boot.RunModules(ctx, configPath, nil)
+			return boot.RunModules(ctx, configPath, reloadPeriod, nil)
 		},
 	}
 
 	cmd.Flags().StringVarP(&configPath, "config", "c", "configs/rover_configs.yaml", "the rover config file path")
+	cmd.Flags().DurationVar(&reloadPeriod, "config-reload-period", time.Second*10,
+		"the period of checking the config file changes to reload the dynamic settings, disabled when zero")
 	return cmd
 }
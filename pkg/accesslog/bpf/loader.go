@@ -27,20 +27,37 @@ import (
 // nolint
 //go:generate go run github.com/cilium/ebpf/cmd/bpf2go -no-global-types -target $TARGET -cc $BPF_CLANG -cflags $BPF_CFLAGS bpf $REPO_ROOT/bpf/accesslog/accesslog.c -- -I$REPO_ROOT/bpf/include
 
+// HandoverMaps are the maps pinned into the BPF filesystem when the state handover is active,
+// for keeping the active connections and the monitored processes across the restarting of rover
+var HandoverMaps = []string{"active_connection_map", "process_monitor_control"}
+
 type Loader struct {
 	*btf.Linker
 	*bpfObjects
+
+	// Handover means the pinned maps are reused from the previous rover
+	Handover bool
 }
 
-func NewLoader() (*Loader, error) {
+// NewLoader loads the BPF objects, the data queues are transported through the ring buffer
+// when the size is bigger than 0 and the kernel supports it, the handover maps are pinned into
+// the pin path when it is not empty
+func NewLoader(ringBufferSize int, pinPath string) (*Loader, error) {
 	objs := bpfObjects{}
-	if err := btf.LoadBPFAndAssign(loadBpf, &objs); err != nil {
+	var pinning *btf.MapPinning
+	if pinPath != "" {
+		pinning = &btf.MapPinning{Path: pinPath, Maps: HandoverMaps}
+	}
+	handover, err := btf.LoadBPFAndAssignWithPinning(loadBpf, &objs, ringBufferSize, pinning)
+	if err != nil {
 		return nil, err
 	}
+	btf.RegisterMapsUtilization(&objs.bpfMaps)
 
 	return &Loader{
 		bpfObjects: &objs,
 		Linker:     btf.NewLinker(),
+		Handover:   handover,
 	}, nil
 }
 
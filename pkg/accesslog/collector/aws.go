@@ -0,0 +1,172 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/aws"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+const awsENIAttributePrefix = "rover.remote.aws."
+
+var awsENICollectInstance = NewAWSENICollector()
+
+// AWSENICollector resolve the remote non-cluster addresses to the AWS ENI metadata, and attach them to the connections,
+// so the traffic to the managed AWS services(through the VPC endpoints, NAT gateways, etc.) could be identified
+type AWSENICollector struct {
+	context         *common.AccessLogContext
+	periodic        *reporter.Periodic
+	ec2             *aws.EC2Client
+	cacheTTL        time.Duration
+	failureCacheTTL time.Duration
+	timeout         time.Duration
+	maxLookups      int
+
+	// the resolved interface of each remote address, the interface is nil when the address is not owned by any ENI
+	addresses map[string]*awsENICacheEntry
+}
+
+type awsENICacheEntry struct {
+	networkInterface *aws.NetworkInterface
+	attributes       map[string]string
+	resolveTime      time.Time
+	// the lookup is failed, cached in the failure TTL to avoid querying the EC2 API in every period
+	failed bool
+}
+
+func NewAWSENICollector() *AWSENICollector {
+	return &AWSENICollector{
+		addresses: make(map[string]*awsENICacheEntry),
+	}
+}
+
+func (c *AWSENICollector) Start(_ *module.Manager, ctx *common.AccessLogContext) (err error) {
+	config := ctx.Config.AWS
+	if !config.ENIMetadata {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	if c.cacheTTL, err = time.ParseDuration(config.CacheTTL); err != nil {
+		return fmt.Errorf("parsing the AWS cache TTL failure: %v", err)
+	}
+	if c.failureCacheTTL, err = time.ParseDuration(config.FailureCacheTTL); err != nil {
+		return fmt.Errorf("parsing the AWS failure cache TTL failure: %v", err)
+	}
+	if c.timeout, err = time.ParseDuration(config.LookupTimeout); err != nil {
+		return fmt.Errorf("parsing the AWS lookup timeout failure: %v", err)
+	}
+	if c.ec2, err = aws.NewEC2Client(ctx.RuntimeContext, c.timeout); err != nil {
+		return fmt.Errorf("create the EC2 client failure: %v", err)
+	}
+	c.context = ctx
+	c.maxLookups = config.MaxLookups
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "AWS ENI metadata", period, c.flush)
+	return nil
+}
+
+func (c *AWSENICollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *AWSENICollector) flush(context.Context) error {
+	now := time.Now()
+	c.expire(now)
+	lookups := 0
+	c.context.ConnectionMgr.RangeConnections(func(con *common.ConnectionInfo) {
+		remote, ok := con.RPCConnection.GetRemote().GetAddress().(*v3.ConnectionAddress_Ip)
+		if !ok {
+			return
+		}
+		address := remote.Ip.GetHost()
+		entry := c.addresses[address]
+		if entry == nil {
+			// limit the lookups to avoid the EC2 API throttling, the others would be resolved in the next period
+			if lookups >= c.maxLookups {
+				return
+			}
+			lookups++
+			entry = c.resolve(address, now)
+		}
+		if entry.networkInterface == nil {
+			return
+		}
+		if con.Attributes()[awsENIAttributePrefix+"eni_id"] != entry.networkInterface.ID {
+			con.SetAttributes(entry.attributes)
+		}
+	})
+	return nil
+}
+
+func (c *AWSENICollector) resolve(address string, now time.Time) *awsENICacheEntry {
+	ctx, cancel := context.WithTimeout(c.context.RuntimeContext, c.timeout)
+	defer cancel()
+	entry := &awsENICacheEntry{resolveTime: now}
+	networkInterface, err := c.ec2.FindNetworkInterface(ctx, address)
+	if err != nil {
+		log.Warnf("resolve the ENI of the address %s failure, skip it in the next %s: %v", address, c.failureCacheTTL, err)
+		entry.failed = true
+	} else if networkInterface != nil {
+		entry.networkInterface = networkInterface
+		entry.attributes = buildAWSENIAttributes(networkInterface)
+	}
+	c.addresses[address] = entry
+	return entry
+}
+
+func (c *AWSENICollector) expire(now time.Time) {
+	for address, entry := range c.addresses {
+		ttl := c.cacheTTL
+		if entry.failed {
+			ttl = c.failureCacheTTL
+		}
+		if now.Sub(entry.resolveTime) >= ttl {
+			delete(c.addresses, address)
+		}
+	}
+}
+
+func buildAWSENIAttributes(networkInterface *aws.NetworkInterface) map[string]string {
+	attrs := map[string]string{
+		"eni_id":            networkInterface.ID,
+		"interface_type":    networkInterface.InterfaceType,
+		"vpc_id":            networkInterface.VpcID,
+		"subnet_id":         networkInterface.SubnetID,
+		"availability_zone": networkInterface.AvailabilityZone,
+		"owner_id":          networkInterface.OwnerID,
+		"requester_id":      networkInterface.RequesterID,
+		"vpc_endpoint_id":   networkInterface.VPCEndpointID(),
+	}
+	result := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		if v != "" {
+			result[awsENIAttributePrefix+k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	bandwidthProcessEgressMeterName  = "access_log_bandwidth_process_egress_bytes_rate"
+	bandwidthProcessIngressMeterName = "access_log_bandwidth_process_ingress_bytes_rate"
+	bandwidthPodEgressMeterName      = "access_log_bandwidth_pod_egress_bytes_rate"
+	bandwidthPodIngressMeterName     = "access_log_bandwidth_pod_ingress_bytes_rate"
+	bandwidthRemoteEgressMeterName   = "access_log_bandwidth_remote_egress_bytes_rate"
+	bandwidthRemoteIngressMeterName  = "access_log_bandwidth_remote_ingress_bytes_rate"
+)
+
+var bandwidthCollectInstance = NewBandwidthCollector()
+
+// BandwidthCollector periodically export the ingress and egress byte rates of each process and pod over the sliding window,
+// with the top remote endpoints of each pod, it doesn't require the protocol analyze or the access log detail
+type BandwidthCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.MeterSender
+	periodic *reporter.Periodic
+	window   *common.BandwidthWindow
+	period   time.Duration
+	topN     int
+}
+
+type bandwidthPodKey struct {
+	service  string
+	instance string
+}
+
+func NewBandwidthCollector() *BandwidthCollector {
+	return &BandwidthCollector{}
+}
+
+func (b *BandwidthCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if !ctx.Config.Bandwidth.Active {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Bandwidth.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the bandwidth period failure: %v", err)
+	}
+	window, err := time.ParseDuration(ctx.Config.Bandwidth.Window)
+	if err != nil {
+		return fmt.Errorf("parsing the bandwidth window failure: %v", err)
+	}
+	if period <= 0 || window < period || window%period != 0 {
+		return fmt.Errorf("the bandwidth window must be the multiple of the period, window: %s, period: %s", window, period)
+	}
+	if ctx.Config.Bandwidth.TopN <= 0 {
+		return fmt.Errorf("the bandwidth top N must be bigger than zero")
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	b.context = ctx
+	b.sender = reporter.NewMeterSender("bandwidth meters", coreOperator.BackendOperator().GetConnection())
+	b.window = common.NewBandwidthWindow(int(window / period))
+	b.period = period
+	b.topN = ctx.Config.Bandwidth.TopN
+	b.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "bandwidth meters", period, b.flush)
+	return nil
+}
+
+func (b *BandwidthCollector) Stop() {
+	b.periodic.Stop()
+}
+
+func (b *BandwidthCollector) flush(ctx context.Context) error {
+	b.window.Push(b.context.ConnectionMgr.SnapshotBandwidth())
+	seconds := (time.Duration(b.window.Buckets()) * b.period).Seconds()
+	processes := b.window.Sum()
+	if len(processes) == 0 {
+		return nil
+	}
+
+	collections := make([]*v3.MeterDataCollection, 0)
+	now := time.Now().UnixMilli()
+	pods := make(map[bandwidthPodKey]*common.ProcessBandwidth)
+	for pid, bandwidth := range processes {
+		for _, p := range b.context.ConnectionMgr.FindMonitoringProcesses(pid) {
+			labels := []*v3.Label{{Name: "process_id", Value: p.ID()}}
+			collections = append(collections, buildBandwidthCollection(p.Entity().ServiceName, p.Entity().InstanceName, now,
+				reporter.SingleValueMeter(bandwidthProcessEgressMeterName, labels, float64(bandwidth.EgressBytes)/seconds),
+				reporter.SingleValueMeter(bandwidthProcessIngressMeterName, labels, float64(bandwidth.IngressBytes)/seconds)))
+
+			key := bandwidthPodKey{service: p.Entity().ServiceName, instance: p.Entity().InstanceName}
+			pod := pods[key]
+			if pod == nil {
+				pod = &common.ProcessBandwidth{Remotes: make(map[string]*common.Bandwidth)}
+				pods[key] = pod
+			}
+			pod.Merge(bandwidth)
+		}
+	}
+	for key, pod := range pods {
+		data := []*v3.MeterData{
+			reporter.SingleValueMeter(bandwidthPodEgressMeterName, nil, float64(pod.EgressBytes)/seconds),
+			reporter.SingleValueMeter(bandwidthPodIngressMeterName, nil, float64(pod.IngressBytes)/seconds),
+		}
+		for i, remote := range pod.TopRemotes(b.topN) {
+			labels := []*v3.Label{
+				{Name: "remote", Value: remote.Remote},
+				{Name: "rank", Value: fmt.Sprintf("%d", i+1)},
+			}
+			data = append(data,
+				reporter.SingleValueMeter(bandwidthRemoteEgressMeterName, labels, float64(remote.EgressBytes)/seconds),
+				reporter.SingleValueMeter(bandwidthRemoteIngressMeterName, labels, float64(remote.IngressBytes)/seconds))
+		}
+		collections = append(collections, buildBandwidthCollection(key.service, key.instance, now, data...))
+	}
+	return b.sender.Send(ctx, collections)
+}
+
+func buildBandwidthCollection(service, instance string, timestamp int64, data ...*v3.MeterData) *v3.MeterDataCollection {
+	data[0].Service = service
+	data[0].ServiceInstance = instance
+	data[0].Timestamp = timestamp
+	return &v3.MeterDataCollection{MeterData: data}
+}
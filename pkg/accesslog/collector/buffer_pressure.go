@@ -0,0 +1,95 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	bufferPressureMeterName = "access_log_socket_buffer_pressure_counter"
+	bufferSlownessMeterName = "access_log_socket_buffer_slowness_counter"
+)
+
+var bufferPressureCollectInstance = NewBufferPressureCollector()
+
+// BufferPressureCollector report the socket buffer pressure detected when reading or writing data as the meters of the process
+type BufferPressureCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.MeterSender
+	periodic *reporter.Periodic
+}
+
+func NewBufferPressureCollector() *BufferPressureCollector {
+	return &BufferPressureCollector{}
+}
+
+func (c *BufferPressureCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.sender = reporter.NewMeterSender("socket buffer pressure metrics", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "socket buffer pressure metrics", period, c.flush)
+	return nil
+}
+
+func (c *BufferPressureCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *BufferPressureCollector) flush(ctx context.Context) error {
+	flags, causes := c.context.BufferPressure.Swap()
+	meters := make(map[rpcServiceInstance][]*v3.MeterData)
+	c.appendMeters(meters, flags, bufferPressureMeterName, "flag")
+	c.appendMeters(meters, causes, bufferSlownessMeterName, "cause")
+
+	now := time.Now().UnixMilli()
+	collections := make([]*v3.MeterDataCollection, 0, len(meters))
+	for instance, data := range meters {
+		data[0].Service = instance.service
+		data[0].ServiceInstance = instance.instance
+		data[0].Timestamp = now
+		collections = append(collections, &v3.MeterDataCollection{MeterData: data})
+	}
+	return c.sender.Send(ctx, collections)
+}
+
+func (c *BufferPressureCollector) appendMeters(meters map[rpcServiceInstance][]*v3.MeterData,
+	counts map[common.SocketBufferPressureKey]uint64, name, labelName string) {
+	for key, count := range counts {
+		for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(key.PID) {
+			instance := rpcServiceInstance{service: p.Entity().ServiceName, instance: p.Entity().InstanceName}
+			meters[instance] = append(meters[instance], reporter.SingleValueMeter(name, []*v3.Label{
+				{Name: labelName, Value: key.Name},
+				{Name: "process_name", Value: p.Entity().ProcessName},
+			}, float64(count)))
+		}
+	}
+}
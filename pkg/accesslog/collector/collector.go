@@ -34,9 +34,28 @@ func Collectors() []Collector {
 	return []Collector{
 		l24CollectorsInstance,
 		transferCollectInstance,
-		NewConnectionCollector([]CollectFilter{zTunnelCollectInstance}),
+		NewConnectionCollector([]CollectFilter{zTunnelCollectInstance, sidecarCollectInstance, dnsCollectInstance}),
+		connectFailureCollectInstance,
 		tlsCollectInstance,
 		processCollectInstance,
 		zTunnelCollectInstance,
+		// must after the ztunnel, the waypoint hop is detected through the ztunnel attachment
+		waypointCollectInstance,
+		sidecarCollectInstance,
+		topologyCollectInstance,
+		bandwidthCollectInstance,
+		sctpCollectInstance,
+		correlationCollectInstance,
+		rpcCollectInstance,
+		bufferPressureCollectInstance,
+		dnsCollectInstance,
+		tlsHandshakeCollectInstance,
+		connectionHealthCollectInstance,
+		connectionHeartbeatCollectInstance,
+		syscallLatencyCollectInstance,
+		payloadCollectInstance,
+		keyLogCollectInstance,
+		awsENICollectInstance,
+		parseStatsCollectInstance,
 	}
 }
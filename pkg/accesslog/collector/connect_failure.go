@@ -0,0 +1,177 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/accesslog/forwarder"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const connectFailureMeterName = "access_log_connect_failure_counter"
+
+var connectFailureCollectInstance = NewConnectFailureCollector()
+
+// ConnectFailureCollector monitoring the failed connect operations(refused, timeout, unreachable) of each destination
+type ConnectFailureCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.MeterSender
+	periodic *reporter.Periodic
+
+	mutex    sync.Mutex
+	failures map[connectFailureKey]int64
+}
+
+type connectFailureKey struct {
+	PID    uint32
+	Remote string
+	Reason string
+}
+
+func NewConnectFailureCollector() *ConnectFailureCollector {
+	return &ConnectFailureCollector{failures: make(map[connectFailureKey]int64)}
+}
+
+func (c *ConnectFailureCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.sender = reporter.NewMeterSender("connect failure metrics", coreOperator.BackendOperator().GetConnection())
+
+	ctx.BPF.AddTracePoint("sock", "inet_sock_set_state", ctx.BPF.TracepointInetSockSetState)
+	ctx.BPF.ReadEventAsync(ctx.BPF.SocketConnectFailureEventQueue, func(data interface{}) {
+		c.onConnectFailure(data.(*events.SocketConnectFailureEvent))
+	}, func() interface{} {
+		return &events.SocketConnectFailureEvent{}
+	})
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "connect failure metrics", period, c.flush)
+	return nil
+}
+
+func (c *ConnectFailureCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *ConnectFailureCollector) onConnectFailure(event *events.SocketConnectFailureEvent) {
+	if !c.context.ConnectionMgr.ProcessIsMonitor(event.PID) {
+		return
+	}
+	remoteIP, supported := connectFailureRemoteIP(event)
+	if !supported {
+		return
+	}
+	remote := fmt.Sprintf("%s:%d", remoteIP, event.RemoteAddrPort)
+	reason := connectFailureReason(event.Error)
+	log.Debugf("detect connect failure, connection ID: %d, randomID: %d, pid: %d, remote: %s, reason: %s",
+		event.ConID, event.RandomID, event.PID, remote, reason)
+
+	// the connection is reported as success when the connect is in progress, so correct it
+	if event.RandomID != 0 {
+		forwarder.SendConnectFailureEvent(c.context, event)
+	}
+	dnsCollectInstance.onConnectFailure(event, remoteIP, reason)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.failures[connectFailureKey{PID: event.PID, Remote: remote, Reason: reason}]++
+}
+
+func (c *ConnectFailureCollector) flush(ctx context.Context) error {
+	c.mutex.Lock()
+	failures := c.failures
+	c.failures = make(map[connectFailureKey]int64)
+	c.mutex.Unlock()
+	if len(failures) == 0 {
+		return nil
+	}
+
+	collections := make([]*v3.MeterDataCollection, 0)
+	now := time.Now().UnixMilli()
+	for key, count := range failures {
+		for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(key.PID) {
+			collections = append(collections, &v3.MeterDataCollection{MeterData: []*v3.MeterData{{
+				Metric: &v3.MeterData_SingleValue{
+					SingleValue: &v3.MeterSingleValue{
+						Name: connectFailureMeterName,
+						Labels: []*v3.Label{
+							{Name: "process_id", Value: p.ID()},
+							{Name: "remote_address", Value: key.Remote},
+							{Name: "reason", Value: key.Reason},
+						},
+						Value: float64(count),
+					},
+				},
+				Service:         p.Entity().ServiceName,
+				ServiceInstance: p.Entity().InstanceName,
+				Timestamp:       now,
+			}}})
+		}
+	}
+	return c.sender.Send(ctx, collections)
+}
+
+// connectFailureRemoteIP parse the remote IP of the connect target, only the IPv4 and IPv6 are supported
+func connectFailureRemoteIP(event *events.SocketConnectFailureEvent) (string, bool) {
+	switch event.SocketFamily {
+	case unix.AF_INET:
+		return ip.ParseIPV4(event.RemoteAddrV4), true
+	case unix.AF_INET6:
+		return ip.ParseIPV6(event.RemoteAddrV6), true
+	}
+	return "", false
+}
+
+// connectFailureReason convert the error number to the readable reason
+func connectFailureReason(errno uint32) string {
+	switch syscall.Errno(errno) {
+	case unix.ECONNREFUSED:
+		// received the RST when sending the SYN
+		return "refused"
+	case unix.ETIMEDOUT:
+		// the SYN retransmission exhausted
+		return "timeout"
+	case unix.EHOSTUNREACH:
+		return "host_unreachable"
+	case unix.ENETUNREACH:
+		return "network_unreachable"
+	case 0:
+		return "unknown"
+	}
+	if name := unix.ErrnoName(syscall.Errno(errno)); name != "" {
+		return strings.ToLower(name)
+	}
+	return fmt.Sprintf("errno_%d", errno)
+}
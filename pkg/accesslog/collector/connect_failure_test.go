@@ -0,0 +1,93 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+)
+
+func TestConnectFailureReason(t *testing.T) {
+	tests := []struct {
+		errno    uint32
+		expected string
+	}{
+		{errno: uint32(unix.ECONNREFUSED), expected: "refused"},
+		{errno: uint32(unix.ETIMEDOUT), expected: "timeout"},
+		{errno: uint32(unix.EHOSTUNREACH), expected: "host_unreachable"},
+		{errno: uint32(unix.ENETUNREACH), expected: "network_unreachable"},
+		{errno: 0, expected: "unknown"},
+		{errno: uint32(unix.EADDRNOTAVAIL), expected: "eaddrnotavail"},
+		{errno: 10000, expected: "errno_10000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if actual := connectFailureReason(tt.errno); actual != tt.expected {
+				t.Errorf("expected reason: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConnectFailureRemoteIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     *events.SocketConnectFailureEvent
+		expected  string
+		supported bool
+	}{
+		{
+			name:      "ipv4",
+			event:     &events.SocketConnectFailureEvent{SocketFamily: unix.AF_INET, RemoteAddrV4: 0x0100000a},
+			expected:  "10.0.0.1",
+			supported: true,
+		},
+		{
+			name: "ipv6",
+			event: &events.SocketConnectFailureEvent{SocketFamily: unix.AF_INET6,
+				RemoteAddrV6: [16]uint8{0xfd, 0x00, 15: 0x01}},
+			expected:  "fd00::1",
+			supported: true,
+		},
+		{
+			name: "ipv4-mapped ipv6",
+			event: &events.SocketConnectFailureEvent{SocketFamily: unix.AF_INET6,
+				RemoteAddrV6: [16]uint8{10: 0xff, 11: 0xff, 12: 127, 15: 1}},
+			expected:  "127.0.0.1",
+			supported: true,
+		},
+		{
+			name:  "unix socket",
+			event: &events.SocketConnectFailureEvent{SocketFamily: unix.AF_UNIX},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, supported := connectFailureRemoteIP(tt.event)
+			if supported != tt.supported {
+				t.Fatalf("expected supported: %t, actual: %t", tt.supported, supported)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected remote IP: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -19,9 +19,7 @@ package collector
 
 import (
 	"context"
-	"encoding/binary"
 	"fmt"
-	"net"
 	"os"
 
 	"github.com/docker/go-units"
@@ -56,6 +54,7 @@ type CollectFilter interface {
 }
 
 type ConnectCollector struct {
+	context    *common.AccessLogContext
 	eventQueue *btf.EventQueue
 	filters    []CollectFilter
 }
@@ -99,6 +98,13 @@ func (c *ConnectCollector) Start(m *module.Manager, ctx *common.AccessLogContext
 		return int(data.(*events.SocketCloseEvent).ConnectionID)
 	})
 	c.eventQueue.Start(ctx.RuntimeContext, ctx.BPF.Linker)
+	if ctx.Watchdog != nil {
+		ctx.Watchdog.Register(c.eventQueue.WatchdogComponents()...)
+	}
+	if ctx.Config.ConnectionAnalyze.DiscoverExisting {
+		c.context = ctx
+		ctx.ConnectionMgr.AddExistingConnectionListener(c)
+	}
 
 	ctx.BPF.AddTracePoint("syscalls", "sys_enter_connect", ctx.BPF.TracepointEnterConnect)
 	ctx.BPF.AddTracePoint("syscalls", "sys_exit_connect", ctx.BPF.TracepointExitConnect)
@@ -118,6 +124,10 @@ func (c *ConnectCollector) Start(m *module.Manager, ctx *common.AccessLogContext
 	ctx.BPF.AddLink(link.Kprobe, map[string]*ebpf.Program{
 		"ip4_datagram_connect": ctx.BPF.Ip4UdpDatagramConnect,
 	})
+	// the IPv6 could be disabled in the kernel
+	_ = ctx.BPF.AddLinkOrError(link.Kprobe, map[string]*ebpf.Program{
+		"ip6_datagram_connect": ctx.BPF.Ip6UdpDatagramConnect,
+	})
 
 	_ = ctx.BPF.AddLinkOrError(link.Kprobe, map[string]*ebpf.Program{
 		"__nf_conntrack_hash_insert": ctx.BPF.NfConntrackHashInsert,
@@ -191,14 +201,7 @@ func (c *ConnectionPartitionContext) FixSocketFamilyIfNeed(event *events.SocketC
 	if result == nil {
 		return
 	}
-	if parseIP := net.ParseIP(result.SrcIP); parseIP != nil {
-		var actual uint32
-		if parseIP.To4() != nil {
-			actual = unix.AF_INET
-		} else {
-			actual = unix.AF_INET6
-		}
-
+	if actual := ip.Family(result.SrcIP); actual != 0 {
 		if result.Family != actual {
 			connectionLogger.Debugf("fix the socket family from %d to %d, connection ID: %d, randomID: %d",
 				result.Family, actual, event.ConID, event.RandomID)
@@ -226,7 +229,11 @@ func (c *ConnectionPartitionContext) BuildSocketFromConnectEvent(event *events.S
 		return pair
 	}
 
-	pair, err := ip.ParseSocket(event.PID, event.SocketFD)
+	parseSocket := ip.ParseSocket
+	if enums.TransportProtocol(event.Transport) == enums.TransportProtocolSCTP {
+		parseSocket = ip.ParseSCTPSocket
+	}
+	pair, err := parseSocket(event.PID, event.SocketFD)
 	if err != nil {
 		connectionLogger.Debugf("cannot found the socket, pid: %d, socket FD: %d, error: %v", event.PID, event.SocketFD, err)
 		return nil
@@ -293,14 +300,12 @@ func (c *ConnectionPartitionContext) BuildSocketPair(event *events.SocketConnect
 		}
 		remoteAddr := ip.ParseIPV6(event.RemoteAddrV6)
 		ignoredConntrack := true
-		if event.ConnTrackUpstreamIPl != 0 && event.ConnTrackUpstreamPort != 0 {
+		// the all-zero upstream address means the conntrack is not found
+		if (event.ConnTrackUpstreamIPh != 0 || event.ConnTrackUpstreamIPl != 0) && event.ConnTrackUpstreamPort != 0 {
 			haveConnTrack = true
 			var conntrackIP string
 			if event.ConnTrackUpstreamIPh != 0 {
-				var ipv6 [16]uint8
-				binary.BigEndian.PutUint64(ipv6[0:8], event.ConnTrackUpstreamIPh)
-				binary.BigEndian.PutUint64(ipv6[8:16], event.ConnTrackUpstreamIPl)
-				conntrackIP = ip.ParseIPV6(ipv6)
+				conntrackIP = ip.ParseIPV6FromUint64(event.ConnTrackUpstreamIPh, event.ConnTrackUpstreamIPl)
 			} else {
 				conntrackIP = ip.ParseIPV4(uint32(event.ConnTrackUpstreamIPl))
 			}
@@ -333,8 +338,8 @@ func (c *ConnectionPartitionContext) BuildSocketPair(event *events.SocketConnect
 }
 
 func (c *ConnectionPartitionContext) CheckNeedConntrack(event *events.SocketConnectEvent, socket *ip.SocketPair) {
+	// the accepted connection would query the conntrack by the reply direction, to recover the peer address before NAT
 	if socket == nil || !socket.IsValid() || tools.IsLocalHostAddress(socket.DestIP) ||
-		event.FuncName == enums.SocketFunctionNameAccept || // accept event don't need to update the remote address
 		!c.context.ConnectionMgr.ProcessIsDetectBy(event.PID, api.Kubernetes) { // only the k8s process need to update the remote address from conntrack
 		return
 	}
@@ -0,0 +1,88 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+
+	"golang.org/x/sys/unix"
+)
+
+// OnExistingProcessMonitoring discover the connections which established before the process monitored,
+// such as the long-lived connections when the rover restarted
+func (c *ConnectCollector) OnExistingProcessMonitoring(pid int32) {
+	// the listener is notified under the process lock, so discover in the background
+	go c.discoverExistingConnections(pid)
+}
+
+func (c *ConnectCollector) discoverExistingConnections(pid int32) {
+	sockets, err := ip.ParseEstablishedSockets(pid)
+	if err != nil {
+		connectionLogger.Debugf("cannot discover the existing connections, pid: %d, error: %v", pid, err)
+		return
+	}
+	if len(sockets) == 0 {
+		return
+	}
+	// the BPF time is the monotonic time since boot
+	var now unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &now); err != nil {
+		connectionLogger.Warnf("cannot read the monotonic time for discover the existing connections: %v", err)
+		return
+	}
+	count := 0
+	for _, socket := range sockets {
+		conID := uint64(pid)<<32 | uint64(socket.FD)
+		randomID, err := c.context.ConnectionMgr.ExistingConnectionRandomID(conID, socket.Socket)
+		if err != nil {
+			connectionLogger.Warnf("generate the random ID of the existing connection failure, pid: %d, fd: %d, error: %v", pid, socket.FD, err)
+			continue
+		}
+		c.eventQueue.Push(int(conID), buildExistingConnectEvent(conID, randomID, uint64(now.Nano()), socket))
+		count++
+	}
+	connectionLogger.Infof("discovered %d existing connections in the process: %d", count, pid)
+}
+
+func buildExistingConnectEvent(conID, randomID, now uint64, socket *ip.EstablishedSocket) *events.SocketConnectEvent {
+	pid, fd := events.ParseConnectionID(conID)
+	event := &events.SocketConnectEvent{
+		ConID:          conID,
+		RandomID:       randomID,
+		StartTime:      now,
+		EndTime:        now,
+		PID:            pid,
+		SocketFD:       fd,
+		FuncName:       enums.SocketFunctionNameExisting,
+		Role:           uint8(socket.Socket.Role),
+		SocketFamily:   uint8(socket.Socket.Family),
+		ConnectSuccess: 1,
+		RemoteAddrPort: uint32(socket.Socket.DestPort),
+		LocalAddrPort:  uint32(socket.Socket.SrcPort),
+	}
+	if socket.Socket.Family == unix.AF_INET {
+		event.RemoteAddrV4 = ip.ToBPFIPV4(socket.Socket.DestIP)
+		event.LocalAddrV4 = ip.ToBPFIPV4(socket.Socket.SrcIP)
+	} else {
+		event.RemoteAddrV6 = ip.ToBPFIPV6(socket.Socket.DestIP)
+		event.LocalAddrV6 = ip.ToBPFIPV6(socket.Socket.SrcIP)
+	}
+	return event
+}
@@ -0,0 +1,180 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const connectionHealthLogKind = "ACCESS_LOG_CONNECTION_HEALTH"
+
+var connectionHealthCollectInstance = NewConnectionHealthCollector()
+
+// ConnectionHealthCollector send the network health(retransmits, zero windows, resets and RTT) of the closed connections as logs,
+// for distinguishing the network layer problems from the application latency
+type ConnectionHealthCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.LogSender
+	periodic *reporter.Periodic
+
+	mutex   sync.Mutex
+	reports []*connectionHealthReport
+}
+
+type connectionHealthReport struct {
+	connection *common.ConnectionInfo
+	health     *events.SocketHealth
+	closeTime  time.Time
+}
+
+type connectionHealthLogBody struct {
+	Role           string  `json:"role"`
+	LocalAddress   string  `json:"local_address"`
+	RemoteAddress  string  `json:"remote_address"`
+	Retransmits    uint32  `json:"retransmits"`
+	ZeroWindows    uint32  `json:"zero_windows"`
+	ResetsSent     uint32  `json:"resets_sent"`
+	ResetsReceived uint32  `json:"resets_received"`
+	RTTMinMs       float64 `json:"rtt_min_ms"`
+	RTTMaxMs       float64 `json:"rtt_max_ms"`
+	RTTAvgMs       float64 `json:"rtt_avg_ms"`
+	RTTSamples     uint32  `json:"rtt_samples"`
+}
+
+func NewConnectionHealthCollector() *ConnectionHealthCollector {
+	return &ConnectionHealthCollector{}
+}
+
+func (c *ConnectionHealthCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if !ctx.Config.ConnectionHealth.Active {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.sender = reporter.NewLogSender("connection health logs", coreOperator.BackendOperator().GetConnection())
+
+	ctx.BPF.AddTracePoint("tcp", "tcp_send_reset", ctx.BPF.TracepointTcpSendReset)
+	ctx.BPF.AddTracePoint("tcp", "tcp_receive_reset", ctx.BPF.TracepointTcpReceiveReset)
+	ctx.ConnectionMgr.RegisterNewFlushListener(c)
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "connection health logs", period, c.flush)
+	return nil
+}
+
+func (c *ConnectionHealthCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *ConnectionHealthCollector) ReadyToFlushConnection(connection *common.ConnectionInfo, event events.Event) {
+	if _, isClose := event.(*common.CloseEventWithNotify); !isClose || connection.Socket == nil {
+		return
+	}
+	health := &events.SocketHealth{}
+	if err := c.context.BPF.SocketHealthMap.Lookup(connection.ConnectionID, health); err != nil {
+		// no data written in the connection
+		return
+	}
+	if health.RandomID != connection.RandomID {
+		return
+	}
+	if err := c.context.BPF.SocketHealthMap.Delete(connection.ConnectionID); err != nil {
+		log.Debugf("delete the connection health failure, connection ID: %d, error: %v", connection.ConnectionID, err)
+	}
+	if !health.Abnormal() && !c.context.Config.ConnectionHealth.ReportHealthy {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reports = append(c.reports, &connectionHealthReport{connection: connection, health: health, closeTime: time.Now()})
+}
+
+func (c *ConnectionHealthCollector) flush(ctx context.Context) error {
+	c.mutex.Lock()
+	reports := c.reports
+	c.reports = nil
+	c.mutex.Unlock()
+
+	logs := make([]*logv3.LogData, 0, len(reports))
+	for _, report := range reports {
+		logs = c.appendLogs(logs, report)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *ConnectionHealthCollector) appendLogs(logs []*logv3.LogData, report *connectionHealthReport) []*logv3.LogData {
+	socket := report.connection.Socket
+	health := report.health
+	body := &connectionHealthLogBody{
+		Role:           socket.Role.String(),
+		LocalAddress:   fmt.Sprintf("%s:%d", socket.SrcIP, socket.SrcPort),
+		RemoteAddress:  fmt.Sprintf("%s:%d", socket.DestIP, socket.DestPort),
+		Retransmits:    health.Retransmits,
+		ZeroWindows:    health.ZeroWindows,
+		ResetsSent:     health.ResetsSent,
+		ResetsReceived: health.ResetsReceived,
+		RTTMinMs:       microsecondsToMilliseconds(health.RTTMin),
+		RTTMaxMs:       microsecondsToMilliseconds(health.RTTMax),
+		RTTAvgMs:       float64(health.AvgRTT().Microseconds()) / 1000,
+		RTTSamples:     health.RTTSamples,
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the connection health log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: connectionHealthLogKind},
+		{Key: "abnormal", Value: fmt.Sprintf("%t", health.Abnormal())},
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(report.connection.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       report.closeTime.UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
+
+func microsecondsToMilliseconds(us uint32) float64 {
+	return float64(us) / 1000
+}
@@ -0,0 +1,133 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const connectionHeartbeatLogKind = "ACCESS_LOG_CONNECTION_HEARTBEAT"
+
+var connectionHeartbeatCollectInstance = NewConnectionHeartbeatCollector()
+
+// ConnectionHeartbeatCollector periodically send the interval deltas of the long-lived connections as logs,
+// so the connections which never close(such as the gRPC channels and database connection pools) are reported timely
+type ConnectionHeartbeatCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.LogSender
+	periodic *reporter.Periodic
+}
+
+type connectionHeartbeatLogBody struct {
+	Role          string `json:"role"`
+	LocalAddress  string `json:"local_address"`
+	RemoteAddress string `json:"remote_address"`
+	Protocol      string `json:"protocol"`
+	IntervalMs    int64  `json:"interval_ms"`
+	WriteBytes    uint64 `json:"write_bytes"`
+	ReadBytes     uint64 `json:"read_bytes"`
+	Requests      uint64 `json:"requests"`
+	Errors        uint64 `json:"errors"`
+}
+
+func NewConnectionHeartbeatCollector() *ConnectionHeartbeatCollector {
+	return &ConnectionHeartbeatCollector{}
+}
+
+func (c *ConnectionHeartbeatCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if !ctx.Config.Heartbeat.Active {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Heartbeat.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the heartbeat period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.sender = reporter.NewLogSender("connection heartbeat logs", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "connection heartbeat logs", period, c.flush)
+	return nil
+}
+
+func (c *ConnectionHeartbeatCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *ConnectionHeartbeatCollector) flush(ctx context.Context) error {
+	heartbeats := c.context.ConnectionMgr.SnapshotHeartbeats()
+	now := time.Now()
+	logs := make([]*logv3.LogData, 0, len(heartbeats))
+	for _, heartbeat := range heartbeats {
+		logs = c.appendLogs(logs, heartbeat, now)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *ConnectionHeartbeatCollector) appendLogs(logs []*logv3.LogData, heartbeat *common.ConnectionHeartbeat,
+	now time.Time) []*logv3.LogData {
+	connection := heartbeat.Connection
+	socket := connection.Socket
+	if socket == nil {
+		return logs
+	}
+	body := &connectionHeartbeatLogBody{
+		Role:          socket.Role.String(),
+		LocalAddress:  fmt.Sprintf("%s:%d", socket.SrcIP, socket.SrcPort),
+		RemoteAddress: fmt.Sprintf("%s:%d", socket.DestIP, socket.DestPort),
+		Protocol:      connection.RPCConnection.GetProtocol().String(),
+		IntervalMs:    heartbeat.Interval.Milliseconds(),
+		WriteBytes:    heartbeat.WriteBytes,
+		ReadBytes:     heartbeat.ReadBytes,
+		Requests:      heartbeat.Requests,
+		Errors:        heartbeat.Errors,
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the connection heartbeat log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: connectionHeartbeatLogKind},
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(connection.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       now.UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
@@ -0,0 +1,175 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const (
+	correlationLogKind = "ACCESS_LOG_CORRELATION"
+	// the max duration to wait the connection of the record been built
+	correlationRetainTime = time.Minute
+)
+
+var correlationCollectInstance = NewCorrelationCollector()
+
+// CorrelationCollector send the correlation records as logs, the backend could join them with the proxy access logs
+// through the request ID, or the connection address and the timestamps
+type CorrelationCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.LogSender
+	periodic *reporter.Periodic
+	retainer *common.Retainer[*common.CorrelationRecord]
+	// counting the outbound requests which propagated the correlation value of the inbound request
+	propagation *common.CorrelationPropagation
+}
+
+type correlationLogBody struct {
+	RequestID           string `json:"request_id"`
+	Role                string `json:"role"`
+	LocalAddress        string `json:"local_address"`
+	RemoteAddress       string `json:"remote_address"`
+	OriginalDestination string `json:"original_destination,omitempty"`
+	StartTime           int64  `json:"start_time"`
+	EndTime             int64  `json:"end_time"`
+	Method              string `json:"method"`
+	Path                string `json:"path"`
+	StatusCode          int    `json:"status_code"`
+	// the values of the extra headers
+	Headers map[string]string `json:"headers,omitempty"`
+	// the count of outbound requests from the same process which carry the same correlation value, only for the server role
+	PropagatedCount *int `json:"propagated_count,omitempty"`
+}
+
+func NewCorrelationCollector() *CorrelationCollector {
+	return &CorrelationCollector{}
+}
+
+func (c *CorrelationCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if ctx.Correlation == nil {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.retainer = common.NewRetainer(correlationRetainTime, func(record *common.CorrelationRecord) time.Time {
+		return record.CreateTime
+	})
+	c.propagation = common.NewCorrelationPropagation(correlationRetainTime)
+	c.sender = reporter.NewLogSender("correlation logs", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "correlation logs", period, c.flush)
+	return nil
+}
+
+func (c *CorrelationCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *CorrelationCollector) flush(ctx context.Context) error {
+	records := c.retainer.Merge(c.context.Correlation.Swap())
+	// the outbound requests must be recorded before the inbound request which triggered them
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].EndTime < records[j].EndTime
+	})
+	c.propagation.Expire(time.Now())
+	logs := make([]*logv3.LogData, 0, len(records))
+	for _, record := range records {
+		connection := c.context.ConnectionMgr.FindByID(record.ConnectionID, record.RandomID)
+		if connection == nil {
+			// the connection may not be built yet, so check it in the next period
+			c.retainer.Retain(record)
+			continue
+		}
+		logs = c.appendLogs(logs, connection, record)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *CorrelationCollector) appendLogs(logs []*logv3.LogData, connection *common.ConnectionInfo,
+	record *common.CorrelationRecord) []*logv3.LogData {
+	socket := connection.Socket
+	body := &correlationLogBody{
+		RequestID:     record.RequestID,
+		Role:          socket.Role.String(),
+		LocalAddress:  fmt.Sprintf("%s:%d", socket.SrcIP, socket.SrcPort),
+		RemoteAddress: fmt.Sprintf("%s:%d", socket.DestIP, socket.DestPort),
+		StartTime:     host.Time(record.StartTime).UnixNano(),
+		EndTime:       host.Time(record.EndTime).UnixNano(),
+		Method:        record.Method,
+		Path:          record.Path,
+		StatusCode:    record.StatusCode,
+		Headers:       record.Headers,
+	}
+	switch socket.Role {
+	case enums.ConnectionRoleClient:
+		c.propagation.RecordOutbound(connection.PID, record.RequestID, time.Now())
+	case enums.ConnectionRoleServer:
+		count := c.propagation.PropagatedCount(connection.PID, record.RequestID)
+		body.PropagatedCount = &count
+	}
+	if socket.OriginalDestIP != "" {
+		body.OriginalDestination = fmt.Sprintf("%s:%d", socket.OriginalDestIP, socket.OriginalDestPort)
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the correlation log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: correlationLogKind},
+		{Key: c.context.Correlation.Header, Value: record.RequestID},
+	}
+	for _, name := range c.context.Correlation.ExtraHeaders {
+		if value := record.Headers[name]; value != "" {
+			tags = append(tags, &commonv3.KeyStringValuePair{Key: name, Value: value})
+		}
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(connection.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       host.Time(record.EndTime).UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
@@ -0,0 +1,238 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/dns"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const (
+	dnsLogKind = "ACCESS_LOG_DNS"
+	// the max duration to wait the connection of the lookup been built
+	dnsRetainTime = time.Minute
+	dnsPort       = 53
+)
+
+var dnsCollectInstance = NewDNSCollector()
+
+// DNSCollector send the slow or failed DNS lookups with the following connect attempts of the same process as logs,
+// for diagnosing the connection timeouts which caused by the DNS
+type DNSCollector struct {
+	context    *common.AccessLogContext
+	sender     *reporter.LogSender
+	periodic   *reporter.Periodic
+	correlator *dns.Correlator
+	retainer   *common.Retainer[*dns.Lookup]
+}
+
+type dnsLogBody struct {
+	QueryName  string              `json:"query_name"`
+	QueryType  string              `json:"query_type"`
+	RCode      string              `json:"rcode"`
+	Resolver   string              `json:"resolver"`
+	StartTime  int64               `json:"start_time"`
+	EndTime    int64               `json:"end_time"`
+	DurationMs float64             `json:"duration_ms"`
+	Slow       bool                `json:"slow"`
+	Answers    []string            `json:"answers,omitempty"`
+	Connects   []*dnsLogConnection `json:"connects"`
+}
+
+type dnsLogConnection struct {
+	RemoteAddress string `json:"remote_address"`
+	StartTime     int64  `json:"start_time"`
+	// the duration from the lookup finished to the connect started
+	DelayMs    float64 `json:"delay_ms"`
+	DurationMs float64 `json:"duration_ms"`
+	// "success" or the failure reason
+	Result string `json:"result"`
+}
+
+func NewDNSCollector() *DNSCollector {
+	return &DNSCollector{}
+}
+
+func (c *DNSCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if ctx.DNS == nil {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	slowThreshold, err := time.ParseDuration(ctx.Config.DNS.SlowThreshold)
+	if err != nil {
+		return fmt.Errorf("parsing the DNS slow threshold failure: %v", err)
+	}
+	window, err := time.ParseDuration(ctx.Config.DNS.CorrelateWindow)
+	if err != nil {
+		return fmt.Errorf("parsing the DNS correlate window failure: %v", err)
+	} else if window <= 0 {
+		return fmt.Errorf("the DNS correlate window must be bigger than 0")
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.retainer = common.NewRetainer(dnsRetainTime, func(lookup *dns.Lookup) time.Time {
+		return lookup.CreateTime
+	})
+	c.correlator = dns.NewCorrelator(slowThreshold, window)
+	c.sender = reporter.NewLogSender("DNS logs", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "DNS logs", period, c.flush)
+	return nil
+}
+
+func (c *DNSCollector) Stop() {
+	c.periodic.Stop()
+}
+
+// OnConnectEvent record the connect attempts of the client, the event is never filtered
+func (c *DNSCollector) OnConnectEvent(event *events.SocketConnectEvent, socket *ip.SocketPair) bool {
+	if c.correlator == nil || socket == nil || socket.Role != enums.ConnectionRoleClient || socket.DestPort == dnsPort {
+		return true
+	}
+	attempt := &dns.ConnectAttempt{
+		ConnectionID: event.ConID,
+		RandomID:     event.RandomID,
+		PID:          event.PID,
+		RemoteIP:     socket.DestIP,
+		RemotePort:   socket.DestPort,
+		StartTime:    event.StartTime,
+		EndTime:      event.EndTime,
+		CreateTime:   time.Now(),
+	}
+	if event.ConnectSuccess == 0 {
+		// the reason is updated when receiving the connect failure event
+		attempt.Failure = "unknown"
+	}
+	c.correlator.AddAttempt(attempt)
+	return true
+}
+
+// onConnectFailure update the result of the connect attempt, the connection may fail after the connect returned
+func (c *DNSCollector) onConnectFailure(event *events.SocketConnectFailureEvent, remoteIP, reason string) {
+	if c.correlator == nil || event.RemoteAddrPort == dnsPort {
+		return
+	}
+	c.correlator.MarkFailure(&dns.ConnectAttempt{
+		ConnectionID: event.ConID,
+		RandomID:     event.RandomID,
+		PID:          event.PID,
+		RemoteIP:     remoteIP,
+		RemotePort:   uint16(event.RemoteAddrPort),
+		StartTime:    event.StartTime,
+		EndTime:      event.EndTime,
+		Failure:      reason,
+		CreateTime:   time.Now(),
+	})
+}
+
+func (c *DNSCollector) flush(ctx context.Context) error {
+	lookups := c.retainer.Merge(c.context.DNS.Swap())
+	for _, lookup := range lookups {
+		connection := c.context.ConnectionMgr.FindByID(lookup.ConnectionID, lookup.RandomID)
+		if connection == nil {
+			// the connection may not be built yet, so check it in the next period
+			c.retainer.Retain(lookup)
+			continue
+		}
+		if connection.Socket != nil {
+			lookup.Resolver = fmt.Sprintf("%s:%d", connection.Socket.DestIP, connection.Socket.DestPort)
+		}
+		c.correlator.AddLookup(connection.PID, lookup)
+	}
+
+	logs := make([]*logv3.LogData, 0)
+	for _, correlation := range c.correlator.Expire(time.Now()) {
+		logs = c.appendLogs(logs, correlation)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *DNSCollector) appendLogs(logs []*logv3.LogData, correlation *dns.Correlation) []*logv3.LogData {
+	lookup := correlation.Lookup
+	body := &dnsLogBody{
+		QueryName:  lookup.Name,
+		QueryType:  lookup.Type,
+		RCode:      lookup.RCode,
+		Resolver:   lookup.Resolver,
+		StartTime:  host.Time(lookup.StartTime).UnixNano(),
+		EndTime:    host.Time(lookup.EndTime).UnixNano(),
+		DurationMs: float64(lookup.Duration()) / float64(time.Millisecond),
+		Slow:       c.correlator.IsSlow(lookup),
+		Answers:    lookup.Answers,
+		Connects:   make([]*dnsLogConnection, 0, len(correlation.Attempts)),
+	}
+	for _, attempt := range correlation.Attempts {
+		result := attempt.Failure
+		if result == "" {
+			result = "success"
+		}
+		var duration uint64
+		if attempt.EndTime > attempt.StartTime {
+			duration = attempt.EndTime - attempt.StartTime
+		}
+		body.Connects = append(body.Connects, &dnsLogConnection{
+			RemoteAddress: fmt.Sprintf("%s:%d", attempt.RemoteIP, attempt.RemotePort),
+			StartTime:     host.Time(attempt.StartTime).UnixNano(),
+			DelayMs:       float64(attempt.StartTime-lookup.EndTime) / float64(time.Millisecond),
+			DurationMs:    float64(duration) / float64(time.Millisecond),
+			Result:        result,
+		})
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the DNS log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: dnsLogKind},
+		{Key: "query_name", Value: lookup.Name},
+		{Key: "rcode", Value: lookup.RCode},
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(correlation.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       host.Time(lookup.EndTime).UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
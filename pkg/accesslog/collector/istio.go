@@ -0,0 +1,77 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
+)
+
+// findIstioProxies find the Envoy processes which are started by the istio agent in the proxy mode
+func findIstioProxies(mode string) ([]int32, error) {
+	processes, err := procfs.Processes()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int32, 0)
+	for _, p := range processes {
+		exe, err := procfs.Exe(p.Pid)
+		if err != nil || !strings.HasSuffix(exe, "/envoy") {
+			continue
+		}
+		ppid, err := readParentPid(p.Pid)
+		if err != nil {
+			continue
+		}
+		cmdline, err := procfs.ReadFile(ppid, "cmdline")
+		if err != nil {
+			continue
+		}
+		if common.ParseIstioProxyMode(strings.Split(string(bytes.TrimRight(cmdline, "\x00")), "\x00")) == mode {
+			result = append(result, p.Pid)
+		}
+	}
+	return result, nil
+}
+
+// readParentPid read the parent pid from the "stat" file, the fields after the command name are
+// "state ppid ...", the command name could contain the spaces, so find the last ")"
+func readParentPid(pid int32) (int32, error) {
+	stat, err := procfs.ReadFile(pid, "stat")
+	if err != nil {
+		return 0, err
+	}
+	index := bytes.LastIndexByte(stat, ')')
+	if index < 0 {
+		return 0, fmt.Errorf("invalid stat file of process %d", pid)
+	}
+	fields := strings.Fields(string(stat[index+1:]))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("invalid stat file of process %d", pid)
+	}
+	ppid, err := strconv.ParseInt(fields[1], 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(ppid), nil
+}
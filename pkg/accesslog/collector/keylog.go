@@ -0,0 +1,80 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"github.com/cilium/ebpf"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+)
+
+var keyLogLog = logger.GetLogger("access_log", "collector", "keylog")
+
+var keyLogCollectInstance = NewKeyLogCollector()
+
+// KeyLogCollector find the TLS key log file of the monitoring processes,
+// and notify the eBPF to upload the encrypted data of these processes
+type KeyLogCollector struct {
+	context *common.AccessLogContext
+}
+
+func NewKeyLogCollector() *KeyLogCollector {
+	return &KeyLogCollector{}
+}
+
+func (c *KeyLogCollector) Start(_ *module.Manager, context *common.AccessLogContext) error {
+	if context.TLSKeyLogs == nil {
+		return nil
+	}
+	keyLogLog.Warnf("the TLS key log decryption is active, the decrypted data would be printed in the logs, " +
+		"please only use it for debugging")
+	c.context = context
+	context.ConnectionMgr.AddProcessListener(c)
+	return nil
+}
+
+func (c *KeyLogCollector) Stop() {
+}
+
+func (c *KeyLogCollector) OnNewProcessMonitoring(pid int32) {
+	path, err := ssl.FindKeyLogFile(pid, c.context.Config.ProtocolAnalyze.TLSKeyLog.Path)
+	if err != nil {
+		keyLogLog.Debugf("cannot found the TLS key log file of the process %d: %v", pid, err)
+		return
+	}
+	c.context.TLSKeyLogs.Add(uint32(pid), ssl.NewKeyLogReader(path))
+	if err := c.context.BPF.TlsKeyLogControl.Update(uint32(pid), uint32(1), ebpf.UpdateAny); err != nil {
+		keyLogLog.Warnf("failed to enable the TLS decryption of the process %d: %v", pid, err)
+		c.context.TLSKeyLogs.Remove(uint32(pid))
+		return
+	}
+	keyLogLog.Infof("the TLS decryption is enabled for the process %d, key log file: %s", pid, path)
+}
+
+func (c *KeyLogCollector) OnProcessRemoved(pid int32) {
+	if c.context.TLSKeyLogs.Find(uint32(pid)) == nil {
+		return
+	}
+	c.context.TLSKeyLogs.Remove(uint32(pid))
+	if err := c.context.BPF.TlsKeyLogControl.Delete(uint32(pid)); err != nil {
+		keyLogLog.Debugf("failed to disable the TLS decryption of the process %d: %v", pid, err)
+	}
+}
@@ -60,6 +60,12 @@ func (c *L24Collector) startRead(_ *module.Manager, context *common.AccessLogCon
 	context.BPF.AddLink(link.Kretprobe, map[string]*ebpf.Program{"tcp_v4_rcv": context.BPF.TcpV4RcvRet})
 	context.BPF.AddLink(link.Kprobe, map[string]*ebpf.Program{"tcp_v6_rcv": context.BPF.TcpV6Rcv})
 	context.BPF.AddLink(link.Kretprobe, map[string]*ebpf.Program{"tcp_v6_rcv": context.BPF.TcpV6RcvRet})
+	context.BPF.AddLink(link.Kprobe, map[string]*ebpf.Program{"tcp_recvmsg": context.BPF.TcpRecvmsg})
+	if context.Config.SyscallLatency.Active {
+		// the time of the read syscall blocked on waiting the data
+		context.BPF.AddLink(link.Kprobe, map[string]*ebpf.Program{"sk_wait_data": context.BPF.SkWaitData})
+		context.BPF.AddLink(link.Kretprobe, map[string]*ebpf.Program{"sk_wait_data": context.BPF.SkWaitDataRet})
+	}
 }
 
 func (c *L24Collector) startWrite(_ *module.Manager, context *common.AccessLogContext) {
@@ -68,6 +74,11 @@ func (c *L24Collector) startWrite(_ *module.Manager, context *common.AccessLogCo
 	context.BPF.AddLink(link.Kretprobe, map[string]*ebpf.Program{"tcp_sendmsg": context.BPF.TcpSendmsgRet})
 	context.BPF.AddLink(link.Kprobe, map[string]*ebpf.Program{"__tcp_transmit_skb": context.BPF.TcpTransmitSkb})
 	context.BPF.AddTracePoint("tcp", "tcp_retransmit_skb", context.BPF.TracepointTcpRetransmitSkb)
+	context.BPF.AddLink(link.Kprobe, map[string]*ebpf.Program{"sk_stream_wait_memory": context.BPF.SkStreamWaitMemory})
+	if context.Config.SyscallLatency.Active {
+		// the time of the write syscall blocked on waiting the send buffer memory
+		context.BPF.AddLink(link.Kretprobe, map[string]*ebpf.Program{"sk_stream_wait_memory": context.BPF.SkStreamWaitMemoryRet})
+	}
 	context.BPF.AddTracePoint("skb", "kfree_skb", context.BPF.KfreeSkb)
 
 	// l3
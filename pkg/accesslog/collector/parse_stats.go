@@ -0,0 +1,121 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const parseIssueMeterName = "access_log_protocol_parse_issue_counter"
+
+var parseStatsCollectInstance = NewParseStatsCollector()
+
+// ParseStatsCollector summarizing the protocol parse issues periodically, and report them to the backend
+type ParseStatsCollector struct {
+	context    *common.AccessLogContext
+	sender     *reporter.MeterSender
+	periodic   *reporter.Periodic
+	instanceID string
+}
+
+func NewParseStatsCollector() *ParseStatsCollector {
+	return &ParseStatsCollector{}
+}
+
+func (c *ParseStatsCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	period, err := time.ParseDuration(ctx.Config.ProtocolAnalyze.ParseStatsPeriod)
+	if err != nil {
+		return fmt.Errorf("parsing the parse stats period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.instanceID = coreOperator.InstanceID()
+	c.sender = reporter.NewMeterSender("protocol parse issue", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "protocol parse issue metrics", period, c.flush)
+	return nil
+}
+
+func (c *ParseStatsCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *ParseStatsCollector) flush(ctx context.Context) error {
+	issues := aggregateParseIssues(c.context.ParseStats.Swap())
+	if len(issues) == 0 {
+		return nil
+	}
+
+	summary := make([]string, 0, len(issues))
+	data := make([]*v3.MeterData, 0, len(issues))
+	for _, issue := range issues {
+		summary = append(summary, fmt.Sprintf("%s/%s: %d", issue.protocol, issue.issue, issue.count))
+		data = append(data, reporter.SingleValueMeter(parseIssueMeterName, []*v3.Label{
+			{Name: "protocol", Value: issue.protocol},
+			{Name: "issue", Value: issue.issue},
+		}, float64(issue.count)))
+	}
+	log.Infof("protocol parse issues in the last period: %s", strings.Join(summary, ", "))
+	data[0].Service = common.SelfMeterService
+	data[0].ServiceInstance = c.instanceID
+	data[0].Timestamp = time.Now().UnixMilli()
+
+	return c.sender.Send(ctx, []*v3.MeterDataCollection{{MeterData: data}})
+}
+
+type parseIssueCount struct {
+	protocol string
+	issue    string
+	count    uint64
+}
+
+// aggregateParseIssues merge the counts by the protocol name and issue, sorted for the readable summary
+func aggregateParseIssues(counts map[common.ProtocolParseIssueKey]uint64) []*parseIssueCount {
+	indexes := make(map[[2]string]*parseIssueCount)
+	result := make([]*parseIssueCount, 0, len(counts))
+	for key, count := range counts {
+		if count == 0 {
+			continue
+		}
+		index := [2]string{key.ProtocolName(), string(key.Issue)}
+		if exist := indexes[index]; exist != nil {
+			exist.count += count
+			continue
+		}
+		issue := &parseIssueCount{protocol: index[0], issue: index[1], count: count}
+		indexes[index] = issue
+		result = append(result, issue)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].protocol != result[j].protocol {
+			return result[i].protocol < result[j].protocol
+		}
+		return result[i].issue < result[j].issue
+	})
+	return result
+}
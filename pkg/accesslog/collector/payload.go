@@ -0,0 +1,146 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const (
+	payloadLogKind = "ACCESS_LOG_PAYLOAD"
+	// the max duration to wait the connection of the payload been built
+	payloadRetainTime = time.Minute
+)
+
+var payloadCollectInstance = NewPayloadCollector()
+
+// PayloadCollector send the captured and redacted payloads of the protocols as logs
+type PayloadCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.LogSender
+	periodic *reporter.Periodic
+	retainer *common.Retainer[*common.PayloadRecord]
+}
+
+type payloadLogBody struct {
+	Protocol      string                 `json:"protocol"`
+	Method        string                 `json:"method,omitempty"`
+	Path          string                 `json:"path,omitempty"`
+	StatusCode    int                    `json:"status_code,omitempty"`
+	Duration      int64                  `json:"duration_ms"`
+	Role          string                 `json:"role"`
+	LocalAddress  string                 `json:"local_address"`
+	RemoteAddress string                 `json:"remote_address"`
+	Request       *common.PayloadMessage `json:"request,omitempty"`
+	Response      *common.PayloadMessage `json:"response,omitempty"`
+}
+
+func NewPayloadCollector() *PayloadCollector {
+	return &PayloadCollector{}
+}
+
+func (c *PayloadCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if ctx.Payloads == nil {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.retainer = common.NewRetainer(payloadRetainTime, func(record *common.PayloadRecord) time.Time {
+		return record.CreateTime
+	})
+	c.sender = reporter.NewLogSender("payload logs", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "payload logs", period, c.flush)
+	return nil
+}
+
+func (c *PayloadCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *PayloadCollector) flush(ctx context.Context) error {
+	records := c.retainer.Merge(c.context.Payloads.Swap())
+	logs := make([]*logv3.LogData, 0, len(records))
+	for _, record := range records {
+		connection := c.context.ConnectionMgr.FindByID(record.ConnectionID, record.RandomID)
+		if connection == nil {
+			// the connection may not be built yet, so check it in the next period
+			c.retainer.Retain(record)
+			continue
+		}
+		logs = c.appendLogs(logs, connection, record)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *PayloadCollector) appendLogs(logs []*logv3.LogData, connection *common.ConnectionInfo,
+	record *common.PayloadRecord) []*logv3.LogData {
+	socket := connection.Socket
+	body := &payloadLogBody{
+		Protocol:      record.Protocol,
+		Method:        record.Method,
+		Path:          record.Path,
+		StatusCode:    record.StatusCode,
+		Duration:      time.Duration(record.EndTime - record.StartTime).Milliseconds(),
+		Role:          socket.Role.String(),
+		LocalAddress:  fmt.Sprintf("%s:%d", socket.SrcIP, socket.SrcPort),
+		RemoteAddress: fmt.Sprintf("%s:%d", socket.DestIP, socket.DestPort),
+		Request:       record.Request,
+		Response:      record.Response,
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the payload log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: payloadLogKind},
+		{Key: "protocol", Value: record.Protocol},
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(connection.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       host.Time(record.StartTime).UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Endpoint:        record.Path,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
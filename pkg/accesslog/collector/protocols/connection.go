@@ -36,6 +36,7 @@ type PartitionConnection struct {
 	closed                 bool
 	skipAllDataAnalyze     bool
 	lastCheckCloseTime     time.Time
+	protocolMgr            *ProtocolManager
 }
 
 func (p *PartitionConnection) Metrics(protocol enums.ConnectionProtocol) ProtocolMetrics {
@@ -66,3 +67,9 @@ func (p *PartitionConnection) AppendData(data buffer.SocketDataBuffer) {
 	}
 	p.dataBuffers[data.Protocol()].AppendDataEvent(data)
 }
+
+// AppendDecryptedData append the data decrypted from the TLS connection, it's analyzed by the protocol of the data
+func (p *PartitionConnection) AppendDecryptedData(data buffer.SocketDataBuffer) {
+	p.appendProtocolIfNeed(p.protocolMgr, p.connectionID, p.randomID, data.Protocol(), data.DataID())
+	p.AppendData(data)
+}
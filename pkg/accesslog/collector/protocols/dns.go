@@ -0,0 +1,209 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/dns"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+const (
+	dnsProtocolName = "dns"
+	// the max count of the queries which waiting for the response in one connection
+	dnsMaxPendingQueries = 1024
+)
+
+const (
+	dnsFramingUnknown = iota
+	// each message is one datagram
+	dnsFramingUDP
+	// each message is prefixed with the two bytes length
+	dnsFramingTCP
+)
+
+var dnsPorts = []uint16{53}
+
+func init() {
+	if _, err := RegisterProtocolAnalyzer(&dnsAnalyzer{}); err != nil {
+		panic(err)
+	}
+}
+
+// dnsAnalyzer decode the DNS queries over the connected UDP socket and TCP,
+// the query type is reported as the operation, and the query name as the resource
+type dnsAnalyzer struct {
+}
+
+func (d *dnsAnalyzer) Name() string {
+	return dnsProtocolName
+}
+
+func (d *dnsAnalyzer) Ports() []uint16 {
+	return dnsPorts
+}
+
+func (d *dnsAnalyzer) NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol {
+	return NewRPCProtocol(ctx, protocol, func() RPCDecoder {
+		return &dnsDecoder{lookups: ctx.DNS, inFlight: make(map[uint16]*dnsQuery)}
+	})
+}
+
+type dnsQuery struct {
+	name      string
+	queryType string
+	size      int
+	startTime uint64
+}
+
+type dnsDecoder struct {
+	// the queue for receiving the lookups, nil means the correlation is disabled
+	lookups  *common.FlushQueue[*dns.Lookup]
+	framing  int
+	inFlight map[uint16]*dnsQuery
+}
+
+func (d *dnsDecoder) Decode(stream *RPCStream) (int, []*RPCExchange, error) {
+	data := stream.Data()
+	offset := 0
+	var exchanges []*RPCExchange
+	for offset < len(data) {
+		rest := data[offset:]
+		if d.framing == dnsFramingUnknown {
+			framing, err := dnsDetectFraming(rest)
+			if err != nil {
+				return offset, exchanges, err
+			} else if framing == dnsFramingUnknown {
+				break
+			}
+			d.framing = framing
+		}
+		length, err := d.messageLength(rest)
+		if err != nil {
+			return offset, exchanges, err
+		}
+		if length < 0 {
+			if stream.Lost() == 0 {
+				break
+			}
+			// the message is truncated, the rest data are treated as the message
+			length = len(rest) + stream.Lost()
+		}
+		if !stream.Ready(offset, length) {
+			break
+		}
+		message := rest[:min(length, len(rest))]
+		if d.framing == dnsFramingTCP {
+			message = message[min(2, len(message)):]
+		}
+		exchanges = d.handleMessage(stream, exchanges, message, offset, length)
+		offset += length
+	}
+	return offset, exchanges, nil
+}
+
+// messageLength returns the length of the message at the start of data(includes the length prefix in TCP),
+// -1 if the data is not complete
+func (d *dnsDecoder) messageLength(data []byte) (int, error) {
+	if d.framing == dnsFramingUDP {
+		return dns.MessageLength(data)
+	}
+	if len(data) < 2 {
+		return -1, nil
+	}
+	return 2 + int(binary.BigEndian.Uint16(data)), nil
+}
+
+func (d *dnsDecoder) handleMessage(stream *RPCStream, exchanges []*RPCExchange, data []byte, offset, length int) []*RPCExchange {
+	message, err := dns.ParseMessage(data, true)
+	if err != nil {
+		return exchanges
+	}
+
+	if !message.Response {
+		query := &dnsQuery{name: message.Name, queryType: message.Type, size: length, startTime: stream.StartTime(offset)}
+		// the resolver retry the query with the same ID when the response is not received in time
+		if previous := d.inFlight[message.ID]; previous != nil && previous.name == query.name && previous.queryType == query.queryType {
+			exchanges = append(exchanges, d.finish(stream, previous, dns.StatusTimeout, query.startTime, 0, nil))
+		}
+		if len(d.inFlight) >= dnsMaxPendingQueries {
+			// the responses are lost, clean the queries to avoid the memory leak
+			d.inFlight = make(map[uint16]*dnsQuery)
+		}
+		d.inFlight[message.ID] = query
+		return exchanges
+	}
+
+	query := d.inFlight[message.ID]
+	if query == nil || query.name != message.Name || query.queryType != message.Type {
+		return exchanges
+	}
+	delete(d.inFlight, message.ID)
+	return append(exchanges, d.finish(stream, query, dns.RCodeName(message.RCode),
+		stream.EndTime(offset+length-1), length, message.Answers))
+}
+
+func (d *dnsDecoder) finish(stream *RPCStream, query *dnsQuery, status string, endTime uint64,
+	responseSize int, answers []string) *RPCExchange {
+	if d.lookups != nil {
+		d.lookups.Append(&dns.Lookup{
+			ConnectionID: stream.connection.ConnectionID,
+			RandomID:     stream.connection.RandomID,
+			Name:         query.name,
+			Type:         query.queryType,
+			RCode:        status,
+			StartTime:    query.startTime,
+			EndTime:      endTime,
+			Answers:      answers,
+			CreateTime:   time.Now(),
+		})
+	}
+	return &RPCExchange{
+		Operation:     query.queryType,
+		Resource:      query.name,
+		Status:        status,
+		StartTime:     query.startTime,
+		EndTime:       endTime,
+		RequestBytes:  uint64(query.size),
+		ResponseBytes: uint64(responseSize),
+	}
+}
+
+// dnsAnswerAddresses read the addresses of A and AAAA records in the answer section,
+// the parsed addresses are returned when the answers are truncated
+// dnsDetectFraming detect the message is sent through UDP or TCP by checking which one could be decoded exactly
+func dnsDetectFraming(data []byte) (int, error) {
+	if len(data) >= 2 {
+		prefix := int(binary.BigEndian.Uint16(data))
+		if len(data) >= 2+prefix {
+			if length, err := dns.MessageLength(data[2 : 2+prefix]); err == nil && length == prefix {
+				return dnsFramingTCP, nil
+			}
+		}
+	}
+	length, err := dns.MessageLength(data)
+	if err != nil {
+		return dnsFramingUnknown, err
+	} else if length < 0 {
+		return dnsFramingUnknown, nil
+	}
+	return dnsFramingUDP, nil
+}
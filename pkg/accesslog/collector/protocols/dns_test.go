@@ -0,0 +1,152 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/dns"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func buildDNSMessage(t *testing.T, id uint16, response bool, rcode dnsmessage.RCode, name string,
+	queryType dnsmessage.Type, answers ...[4]byte) []byte {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, Response: response, RCode: rcode})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		t.Fatal(err)
+	}
+	question := dnsmessage.Question{Name: dnsmessage.MustNewName(name), Type: queryType, Class: dnsmessage.ClassINET}
+	if err := builder.Question(question); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.StartAnswers(); err != nil {
+		t.Fatal(err)
+	}
+	for _, answer := range answers {
+		header := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 30}
+		if err := builder.AResource(header, dnsmessage.AResource{A: answer}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	message, err := builder.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func TestDNSDecoder(t *testing.T) {
+	withLength := func(message []byte) []byte {
+		return append(binary.BigEndian.AppendUint16(nil, uint16(len(message))), message...)
+	}
+	query := buildDNSMessage(t, 7, false, dnsmessage.RCodeSuccess, "Api.Example.com.", dnsmessage.TypeA)
+	response := buildDNSMessage(t, 7, true, dnsmessage.RCodeSuccess, "api.example.com.", dnsmessage.TypeA, [4]byte{10, 0, 0, 1})
+	failedQuery := buildDNSMessage(t, 8, false, dnsmessage.RCodeSuccess, "missing.local.", dnsmessage.TypeAAAA)
+	failedResponse := buildDNSMessage(t, 8, true, dnsmessage.RCodeNameError, "missing.local.", dnsmessage.TypeAAAA)
+	tests := []struct {
+		name      string
+		framing   func([]byte) []byte
+		exchanges []RPCExchange
+		lookups   []dns.Lookup
+	}{
+		{
+			name:    "udp",
+			framing: func(message []byte) []byte { return message },
+			exchanges: []RPCExchange{
+				{Operation: "A", Resource: "api.example.com", Status: "NOERROR", StartTime: 10, EndTime: 21,
+					RequestBytes: uint64(len(query)), ResponseBytes: uint64(len(response))},
+				{Operation: "AAAA", Resource: "missing.local", Status: "NXDOMAIN", StartTime: 10, EndTime: 21,
+					RequestBytes: uint64(len(failedQuery)), ResponseBytes: uint64(len(failedResponse))},
+			},
+		},
+		{
+			name:    "tcp",
+			framing: withLength,
+			exchanges: []RPCExchange{
+				{Operation: "A", Resource: "api.example.com", Status: "NOERROR", StartTime: 10, EndTime: 21,
+					RequestBytes: uint64(len(query) + 2), ResponseBytes: uint64(len(response) + 2)},
+				{Operation: "AAAA", Resource: "missing.local", Status: "NXDOMAIN", StartTime: 10, EndTime: 21,
+					RequestBytes: uint64(len(failedQuery) + 2), ResponseBytes: uint64(len(failedResponse) + 2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &RPCMetrics{ConnectionID: 1, RandomID: 2}
+			client := &RPCStream{direction: enums.SocketDataDirectionEgress, connection: metrics}
+			server := &RPCStream{direction: enums.SocketDataDirectionIngress, connection: metrics}
+			lookups := common.NewFlushQueue[*dns.Lookup]()
+			decoder := &dnsDecoder{lookups: lookups, inFlight: make(map[uint16]*dnsQuery)}
+			decode := func(stream *RPCStream, data []byte, startTime uint64) []*RPCExchange {
+				stream.data = append(stream.data[:0], data...)
+				stream.segments = []rpcStreamSegment{{end: len(data), startTime: startTime, endTime: startTime + 1}}
+				consumed, exchanges, err := decoder.Decode(stream)
+				if err != nil || consumed != len(data) {
+					t.Fatalf("decode failure, consumed: %d, error: %v", consumed, err)
+				}
+				return exchanges
+			}
+
+			// the queries are sent together through the sendmmsg
+			decode(client, append(tt.framing(query), tt.framing(failedQuery)...), 10)
+			exchanges := decode(server, append(tt.framing(response), tt.framing(failedResponse)...), 20)
+			if len(exchanges) != len(tt.exchanges) {
+				t.Fatalf("expected exchanges count: %d, actual: %d", len(tt.exchanges), len(exchanges))
+			}
+			for i := range tt.exchanges {
+				if *exchanges[i] != tt.exchanges[i] {
+					t.Errorf("expected exchange: %+v, actual: %+v", tt.exchanges[i], *exchanges[i])
+				}
+			}
+			result := lookups.Swap()
+			if len(result) != 2 || !reflect.DeepEqual(result[0].Answers, []string{"10.0.0.1"}) ||
+				result[1].RCode != "NXDOMAIN" || result[0].ConnectionID != 1 || result[0].RandomID != 2 {
+				t.Fatalf("unexpected lookups: %+v", result)
+			}
+		})
+	}
+}
+
+func TestDNSDecoderRetry(t *testing.T) {
+	metrics := &RPCMetrics{}
+	client := &RPCStream{direction: enums.SocketDataDirectionEgress, connection: metrics}
+	decoder := &dnsDecoder{inFlight: make(map[uint16]*dnsQuery)}
+	query := buildDNSMessage(t, 3, false, dnsmessage.RCodeSuccess, "slow.example.com.", dnsmessage.TypeA)
+	var exchanges []*RPCExchange
+	for i, startTime := range []uint64{10, 5000} {
+		client.data = append(client.data[:0], query...)
+		client.segments = []rpcStreamSegment{{end: len(query), startTime: startTime, endTime: startTime + 1}}
+		if _, result, err := decoder.Decode(client); err != nil {
+			t.Fatal(err)
+		} else if i == 0 && len(result) != 0 {
+			t.Fatalf("the first query should be waiting for the response: %+v", result)
+		} else {
+			exchanges = append(exchanges, result...)
+		}
+		client.consume(len(query))
+	}
+	if len(exchanges) != 1 || exchanges[0].Status != dns.StatusTimeout || exchanges[0].StartTime != 10 || exchanges[0].EndTime != 5000 {
+		t.Fatalf("the retried query should be reported as timeout: %+v", exchanges)
+	}
+}
@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+)
+
+const (
+	grpcProtocolName        = "grpc"
+	grpcContentTypePrefix   = "application/grpc"
+	grpcStatusHeader        = "grpc-status"
+	grpcMessageHeader       = "grpc-message"
+	grpcMessagePrefixLength = 5
+	// the status of the call which is finished without the grpc-status, same as the UNKNOWN code
+	grpcStatusUnknown = "2"
+)
+
+// GRPCMessageCounter counting the length-prefixed messages of gRPC in the DATA frames of one direction,
+// the message and its prefix could be split into multiple frames
+type GRPCMessageCounter struct {
+	count int
+	// the size of the current message which not received yet
+	remaining int
+	// the received bytes of the message prefix
+	prefix []byte
+}
+
+func (c *GRPCMessageCounter) Feed(data []byte) {
+	for len(data) > 0 {
+		if c.remaining > 0 {
+			n := min(c.remaining, len(data))
+			c.remaining -= n
+			data = data[n:]
+			continue
+		}
+		n := min(grpcMessagePrefixLength-len(c.prefix), len(data))
+		c.prefix = append(c.prefix, data[:n]...)
+		data = data[n:]
+		if len(c.prefix) < grpcMessagePrefixLength {
+			return
+		}
+		// the compressed flag, then the message length
+		c.count++
+		c.remaining = int(binary.BigEndian.Uint32(c.prefix[1:]))
+		c.prefix = c.prefix[:0]
+	}
+}
+
+// Count of the messages which prefix is received
+func (c *GRPCMessageCounter) Count() int {
+	return c.count
+}
+
+func isGRPCContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, grpcContentTypePrefix)
+}
+
+// grpcServiceMethod split the path(such as "/helloworld.Greeter/SayHello") into the service and method
+func grpcServiceMethod(path string) (service, method string) {
+	path = strings.TrimPrefix(path, "/")
+	if index := strings.LastIndex(path, "/"); index >= 0 {
+		return path[:index], path[index+1:]
+	}
+	return path, ""
+}
+
+// sendGRPCRecord report the finished gRPC call as the RPC record, the service is the resource and the method is the operation
+func sendGRPCRecord(ctx *common.AccessLogContext, connection *PartitionConnection, stream *HTTP2Streaming, details []events.SocketDetail) {
+	service, method := grpcServiceMethod(stream.ReqHeader[":path"])
+	status := stream.RespHeader[grpcStatusHeader]
+	if status == "" {
+		status = grpcStatusUnknown
+	}
+	if message := stream.RespHeader[grpcMessageHeader]; message != "" && status != "0" {
+		if decoded, err := url.PathUnescape(message); err == nil {
+			message = decoded
+		}
+		http2Log.Debugf("the gRPC call is failed, connection ID: %d, path: %s, status: %s, message: %s",
+			connection.connectionID, stream.ReqHeader[":path"], status, message)
+	}
+	ctx.RPC.Append(&common.RPCRecord{
+		ConnectionID:     connection.connectionID,
+		RandomID:         connection.randomID,
+		Protocol:         grpcProtocolName,
+		Operation:        method,
+		Resource:         service,
+		Status:           status,
+		StartTime:        details[0].GetStartTime(),
+		EndTime:          details[len(details)-1].GetEndTime(),
+		RequestBytes:     uint64(stream.ReqHeaderBuffer.DataSize()) + uint64(stream.ReqBodyBuffer.DataSize()),
+		ResponseBytes:    uint64(stream.RespHeaderBuffer.DataSize()) + uint64(stream.RespBodyBuffer.DataSize()),
+		RequestMessages:  uint64(stream.ReqMessages.Count()),
+		ResponseMessages: uint64(stream.RespMessages.Count()),
+		CreateTime:       time.Now(),
+	})
+}
@@ -0,0 +1,66 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import "testing"
+
+func TestGRPCMessageCounter(t *testing.T) {
+	message := func(size int) []byte {
+		return append([]byte{0, 0, 0, 0, byte(size)}, make([]byte, size)...)
+	}
+	tests := []struct {
+		name   string
+		frames [][]byte
+		count  int
+	}{
+		{name: "single message", frames: [][]byte{message(3)}, count: 1},
+		{name: "multiple messages in one frame", frames: [][]byte{append(append(message(2), message(0)...), message(4)...)}, count: 3},
+		{name: "message split into frames", frames: [][]byte{message(10)[:7], message(10)[7:]}, count: 1},
+		{name: "prefix split into frames", frames: [][]byte{message(2)[:3], append(message(2)[3:], message(1)...)}, count: 2},
+		{name: "empty frame", frames: [][]byte{{}}, count: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &GRPCMessageCounter{}
+			for _, frame := range tt.frames {
+				counter.Feed(frame)
+			}
+			if counter.Count() != tt.count {
+				t.Errorf("expected count: %d, actual: %d", tt.count, counter.Count())
+			}
+		})
+	}
+}
+
+func TestGRPCServiceMethod(t *testing.T) {
+	tests := []struct {
+		path    string
+		service string
+		method  string
+	}{
+		{path: "/helloworld.Greeter/SayHello", service: "helloworld.Greeter", method: "SayHello"},
+		{path: "/grpc.health.v1.Health/Watch", service: "grpc.health.v1.Health", method: "Watch"},
+		{path: "/unknown", service: "unknown", method: ""},
+	}
+	for _, tt := range tests {
+		service, method := grpcServiceMethod(tt.path)
+		if service != tt.service || method != tt.method {
+			t.Errorf("path %s expected: %s/%s, actual: %s/%s", tt.path, tt.service, tt.method, service, method)
+		}
+	}
+}
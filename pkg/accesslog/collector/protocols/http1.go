@@ -62,7 +62,7 @@ type HTTP1Metrics struct {
 	ConnectionID uint64
 	RandomID     uint64
 
-	halfRequests      *list.List
+	requests          *http1RequestQueue
 	analyzeUnFinished *list.List
 }
 
@@ -70,7 +70,7 @@ func (p *HTTP1Protocol) GenerateConnection(connectionID, randomID uint64) Protoc
 	return &HTTP1Metrics{
 		ConnectionID:      connectionID,
 		RandomID:          randomID,
-		halfRequests:      list.New(),
+		requests:          newHTTP1RequestQueue(),
 		analyzeUnFinished: list.New(),
 	}
 }
@@ -96,6 +96,7 @@ func (p *HTTP1Protocol) Analyze(connection *PartitionConnection, _ *AnalyzeHelpe
 		messageType, err := p.reader.IdentityMessageType(buf)
 		if err != nil {
 			http1Log.Debugf("failed to identity message type, %v", err)
+			p.ctx.ParseStats.Increase(enums.ConnectionProtocolHTTP, common.ProtocolParseIssueError)
 			if buf.SkipCurrentElement() {
 				break
 			}
@@ -114,6 +115,7 @@ func (p *HTTP1Protocol) Analyze(connection *PartitionConnection, _ *AnalyzeHelpe
 		if err != nil {
 			http1Log.Debugf("failed to handle HTTP/1.x protocol, connection ID: %d, random ID: %d, data id: %d, type: %d, error: %v",
 				metrics.ConnectionID, metrics.RandomID, buf.Position().DataID(), messageType, err)
+			p.ctx.ParseStats.Increase(enums.ConnectionProtocolHTTP, common.ProtocolParseIssueError)
 		}
 
 		http1Log.Debugf("readed message, messageType: %v, buf: %p, data id: %d, "+
@@ -148,26 +150,34 @@ func (p *HTTP1Protocol) handleRequest(metrics *HTTP1Metrics, buf *buffer.Buffer)
 	if result != enums.ParseResultSuccess {
 		return result, nil
 	}
-	metrics.appendRequestToList(req)
+	if dropped := metrics.requests.Push(newHTTP1PendingRequest(req)); dropped > 0 {
+		p.increaseUnmatched(dropped)
+	}
 	return result, nil
 }
 
 func (p *HTTP1Protocol) handleResponse(metrics *HTTP1Metrics, connection *PartitionConnection,
 	b *buffer.Buffer) (enums.ParseResult, error) {
-	request := metrics.findMatchesRequest(b.Position().DataID(), b.Position().PrevDataID())
-	if request == nil {
+	pending, dropped := metrics.requests.Pop(b.Position().DataID(), b.Position().StartTime())
+	if dropped > 0 {
+		http1Log.Debugf("dropped %d stale requests which response is missed, connection ID: %d, random ID: %d, current data id: %d",
+			dropped, metrics.ConnectionID, metrics.RandomID, b.Position().DataID())
+		p.increaseUnmatched(dropped)
+	}
+	if pending == nil {
 		log.Debugf("cannot found request for response, skip response, connection ID: %d, random ID: %d, "+
-			"required prev data id: %d, current data id: %d",
-			metrics.ConnectionID, metrics.RandomID, b.Position().PrevDataID(), b.Position().DataID())
+			"current data id: %d, pending requests: %d",
+			metrics.ConnectionID, metrics.RandomID, b.Position().DataID(), metrics.requests.Len())
 		return enums.ParseResultSkipPackage, nil
 	}
+	request := pending.request
 
 	// parsing response
 	response, result, err := p.reader.ReadResponse(request, b, true)
 	defer func() {
-		// if parsing response failed, then put the request back to the list
+		// if parsing response failed, then put the request back to the queue
 		if result != enums.ParseResultSuccess {
-			metrics.halfRequests.PushFront(request)
+			metrics.requests.Push(pending)
 		}
 	}()
 	if err != nil {
@@ -183,6 +193,12 @@ func (p *HTTP1Protocol) handleResponse(metrics *HTTP1Metrics, connection *Partit
 	return enums.ParseResultSuccess, nil
 }
 
+func (p *HTTP1Protocol) increaseUnmatched(count int) {
+	for i := 0; i < count; i++ {
+		p.ctx.ParseStats.Increase(enums.ConnectionProtocolHTTP, common.ProtocolParseIssueUnmatched)
+	}
+}
+
 func (p *HTTP1Protocol) appendAnalyzeUnFinished(metrics *HTTP1Metrics, request *reader.Request, response *reader.Response) {
 	metrics.analyzeUnFinished.PushBack(&HTTP1AnalyzeUnFinished{
 		request:    request,
@@ -250,7 +266,7 @@ func (p *HTTP1Protocol) HandleHTTPData(metrics *HTTP1Metrics, _ *PartitionConnec
 				Version:   v3.AccessLogHTTPProtocolVersion_HTTP1,
 				Request: &v3.AccessLogHTTPProtocolRequest{
 					Method:             TransformHTTPMethod(originalRequest.Method),
-					Path:               originalRequest.URL.Path,
+					Path:               p.ctx.EndpointNormalizer.Normalize(originalRequest.URL.Path),
 					SizeOfHeadersBytes: uint64(request.HeaderBuffer().DataSize()),
 					SizeOfBodyBytes:    uint64(request.BodyBuffer().DataSize()),
 					Trace: AnalyzeTraceInfo(func(key string) string {
@@ -266,50 +282,39 @@ func (p *HTTP1Protocol) HandleHTTPData(metrics *HTTP1Metrics, _ *PartitionConnec
 			},
 		},
 	}))
+	forwarder.SendCorrelationEvent(p.ctx, details, originalRequest.Header.Get, originalRequest.Method,
+		p.ctx.EndpointNormalizer.Normalize(originalRequest.URL.Path), originalResponse.StatusCode)
+	p.capturePayload(details, request, response)
+	forwarder.SendContinuousProfilingEvent(p.ctx, details, originalRequest.URL.RequestURI(), originalResponse.StatusCode)
 	return nil
 }
 
-func (p *HTTP1Protocol) OnProtocolBreak(*HTTP1Metrics, *PartitionConnection) {
-}
-
-func (p *HTTP1Protocol) CloseStream(ioReader io.Closer) {
-	if ioReader != nil {
-		_ = ioReader.Close()
-	}
-}
-
-func (m *HTTP1Metrics) appendRequestToList(req *reader.Request) {
-	if m.halfRequests.Len() == 0 {
-		m.halfRequests.PushFront(req)
+func (p *HTTP1Protocol) capturePayload(details []events.SocketDetail, request *reader.Request, response *reader.Response) {
+	rule := forwarder.PayloadCaptureRule(p.ctx, "http1")
+	if rule == nil {
 		return
 	}
-	if m.halfRequests.Back().Value.(*reader.Request).MinDataID() < req.MinDataID() {
-		m.halfRequests.PushBack(req)
-		return
+	originalRequest, originalResponse := request.Original(), response.Original()
+	record := &common.PayloadRecord{
+		Protocol:   "http1",
+		Method:     originalRequest.Method,
+		Path:       p.ctx.EndpointNormalizer.Normalize(originalRequest.URL.Path),
+		StatusCode: originalResponse.StatusCode,
 	}
-	beenAdded := false
-	for element := m.halfRequests.Front(); element != nil; element = element.Next() {
-		existEvent := element.Value.(*reader.Request)
-		if existEvent.MinDataID() > req.MinDataID() {
-			m.halfRequests.InsertBefore(req, element)
-			beenAdded = true
-			break
-		}
+	if rule.Request {
+		record.Request = buildHTTP1PayloadMessage(p.ctx.Payloads, rule, originalRequest.Header, request.BodyBuffer())
 	}
-	if !beenAdded {
-		m.halfRequests.PushBack(req)
+	if rule.Response {
+		record.Response = buildHTTP1PayloadMessage(p.ctx.Payloads, rule, originalResponse.Header, response.BodyBuffer())
 	}
+	forwarder.SendPayloadEvent(p.ctx, details, record)
 }
 
-func (m *HTTP1Metrics) findMatchesRequest(currentDataID, prevDataID uint64) *reader.Request {
-	for element := m.halfRequests.Front(); element != nil; element = element.Next() {
-		req := element.Value.(*reader.Request)
-		// if the tail data id of request is equals to the prev data id of response
-		// or tail request data id+1==first response data id, then return the request
-		if uint64(req.MaxDataID()) == prevDataID || uint64(req.MaxDataID()+1) == currentDataID {
-			m.halfRequests.Remove(element)
-			return req
-		}
+func (p *HTTP1Protocol) OnProtocolBreak(*HTTP1Metrics, *PartitionConnection) {
+}
+
+func (p *HTTP1Protocol) CloseStream(ioReader io.Closer) {
+	if ioReader != nil {
+		_ = ioReader.Close()
 	}
-	return nil
 }
@@ -0,0 +1,115 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"container/list"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/layer7/protocols/http1/reader"
+)
+
+// the max count of the pending requests in one connection, the oldest one is dropped when exceed
+var http1MaxPendingRequests = 100
+
+// http1PendingRequest is the request waiting for the response
+type http1PendingRequest struct {
+	request   *reader.Request
+	minDataID uint64
+	maxDataID uint64
+	startTime uint64
+}
+
+// http1RequestQueue keeps the pending requests of the connection ordered by the data id,
+// the HTTP/1.x responses are always sent in the same order as the requests(even pipelined),
+// so the response matches the oldest request which is sent before it
+type http1RequestQueue struct {
+	requests *list.List
+}
+
+func newHTTP1RequestQueue() *http1RequestQueue {
+	return &http1RequestQueue{requests: list.New()}
+}
+
+func newHTTP1PendingRequest(req *reader.Request) *http1PendingRequest {
+	return &http1PendingRequest{
+		request:   req,
+		minDataID: uint64(req.MinDataID()),
+		maxDataID: uint64(req.MaxDataID()),
+		startTime: req.StartTime(),
+	}
+}
+
+func (q *http1RequestQueue) Len() int {
+	return q.requests.Len()
+}
+
+// Push the request into the queue by the order of the data id, return the count of the dropped requests
+func (q *http1RequestQueue) Push(pending *http1PendingRequest) int {
+	added := false
+	for element := q.requests.Back(); element != nil; element = element.Prev() {
+		if element.Value.(*http1PendingRequest).minDataID <= pending.minDataID {
+			q.requests.InsertAfter(pending, element)
+			added = true
+			break
+		}
+	}
+	if !added {
+		q.requests.PushFront(pending)
+	}
+	dropped := 0
+	for q.requests.Len() > http1MaxPendingRequests {
+		q.requests.Remove(q.requests.Front())
+		dropped++
+	}
+	return dropped
+}
+
+// Pop the request matched with the response which starts at the data id and time,
+// return the matched request(nil if not found) and the count of the stale requests been dropped.
+// The request is stale when there is a gap of the data id between it and the next request,
+// which means the response data of the request has been passed(such as lost or failed to parse), otherwise
+// the responses after it would be all mismatched.
+func (q *http1RequestQueue) Pop(responseDataID, responseStartTime uint64) (*http1PendingRequest, int) {
+	dropped := 0
+	for element := q.requests.Front(); element != nil; {
+		pending := element.Value.(*http1PendingRequest)
+		if !pending.isBefore(responseDataID, responseStartTime) {
+			return nil, dropped
+		}
+		next := element.Next()
+		if next != nil {
+			nextPending := next.Value.(*http1PendingRequest)
+			if nextPending.isBefore(responseDataID, responseStartTime) && nextPending.minDataID > pending.maxDataID+1 {
+				q.requests.Remove(element)
+				dropped++
+				element = next
+				continue
+			}
+		}
+		q.requests.Remove(element)
+		return pending, dropped
+	}
+	return nil, dropped
+}
+
+func (r *http1PendingRequest) isBefore(dataID, startTime uint64) bool {
+	if r.maxDataID >= dataID {
+		return false
+	}
+	return r.startTime == 0 || startTime == 0 || r.startTime <= startTime
+}
@@ -0,0 +1,161 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/events"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/layer7/protocols/http1/reader"
+	"github.com/apache/skywalking-rover/pkg/tools/buffer"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+type http1CapturedAnalyzer struct {
+	exchanges []string
+}
+
+func (a *http1CapturedAnalyzer) HandleHTTPData(_ *HTTP1Metrics, _ *PartitionConnection,
+	request *reader.Request, response *reader.Response) error {
+	a.exchanges = append(a.exchanges, fmt.Sprintf("%s %s %d",
+		request.Original().Method, request.Original().URL.Path, response.Original().StatusCode))
+	return nil
+}
+
+func (a *http1CapturedAnalyzer) OnProtocolBreak(*HTTP1Metrics, *PartitionConnection) {
+}
+
+type http1CapturedData struct {
+	dataID  uint64
+	ingress bool
+	data    string
+}
+
+func buildHTTP1DataEvent(d http1CapturedData) *events.SocketDataUploadEvent {
+	event := &events.SocketDataUploadEvent{
+		Protocol0:   enums.ConnectionProtocolHTTP,
+		Direction0:  enums.SocketDataDirectionEgress,
+		Finished:    1,
+		DataLen:     uint16(len(d.data)),
+		StartTime0:  d.dataID * 10,
+		EndTime0:    d.dataID*10 + 1,
+		DataID0:     d.dataID,
+		PrevDataID0: d.dataID - 1,
+		TotalSize0:  uint64(len(d.data)),
+	}
+	if d.ingress {
+		event.Direction0 = enums.SocketDataDirectionIngress
+	}
+	copy(event.Buffer[:], d.data)
+	return event
+}
+
+func TestHTTP1RequestResponseMatching(t *testing.T) {
+	tests := []struct {
+		name      string
+		captures  [][]http1CapturedData
+		exchanges []string
+		unmatched uint64
+	}{
+		{
+			name: "pipelined requests",
+			captures: [][]http1CapturedData{{
+				{dataID: 1, ingress: true, data: "GET /a HTTP/1.1\r\nHost: test\r\n\r\nGET /b HTTP/1.1\r\nHost: test\r\n\r\n"},
+				{dataID: 2, ingress: true, data: "POST /c HTTP/1.1\r\nHost: test\r\nContent-Length: 2\r\n\r\nhi"},
+				{dataID: 3, data: "HTTP/1.1 200 OK\r\nContent-Length: 1\r\n\r\naHTTP/1.1 201 Created\r\nContent-Length: 1\r\n\r\nb"},
+				{dataID: 4, data: "HTTP/1.1 202 Accepted\r\nContent-Length: 1\r\n\r\nc"},
+			}},
+			exchanges: []string{"GET /a 200", "GET /b 201", "POST /c 202"},
+		},
+		{
+			name: "keep-alive requests",
+			captures: [][]http1CapturedData{{
+				{dataID: 1, ingress: true, data: "GET /a HTTP/1.1\r\nHost: test\r\n\r\n"},
+				{dataID: 2, data: "HTTP/1.1 200 OK\r\nContent-Length: 1\r\n\r\na"},
+				{dataID: 3, ingress: true, data: "GET /b HTTP/1.1\r\nHost: test\r\n\r\n"},
+				{dataID: 4, data: "HTTP/1.1 404 Not Found\r\nContent-Length: 0\r\n\r\n"},
+			}},
+			exchanges: []string{"GET /a 200", "GET /b 404"},
+		},
+		{
+			name: "chunked response split across data events",
+			captures: [][]http1CapturedData{
+				{
+					{dataID: 1, ingress: true, data: "GET /chunked HTTP/1.1\r\nHost: test\r\n\r\n"},
+					{dataID: 2, data: "HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n"},
+				},
+				{
+					{dataID: 3, data: "6\r\n world\r\n"},
+					{dataID: 4, data: "0\r\n\r\n"},
+					{dataID: 5, ingress: true, data: "GET /next HTTP/1.1\r\nHost: test\r\n\r\n"},
+					{dataID: 6, data: "HTTP/1.1 204 No Content\r\n\r\n"},
+				},
+			},
+			exchanges: []string{"GET /chunked 200", "GET /next 204"},
+		},
+		{
+			name: "response of the request is missed",
+			captures: [][]http1CapturedData{{
+				{dataID: 1, ingress: true, data: "GET /lost HTTP/1.1\r\nHost: test\r\n\r\n"},
+				{dataID: 3, ingress: true, data: "GET /b HTTP/1.1\r\nHost: test\r\n\r\n"},
+				{dataID: 4, data: "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"},
+			}},
+			exchanges: []string{"GET /b 200"},
+			unmatched: 1,
+		},
+		{
+			name: "response without request",
+			captures: [][]http1CapturedData{{
+				{dataID: 1, data: "HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"},
+				{dataID: 2, ingress: true, data: "GET /a HTTP/1.1\r\nHost: test\r\n\r\n"},
+				{dataID: 3, data: "HTTP/1.1 500 Internal Server Error\r\nContent-Length: 0\r\n\r\n"},
+			}},
+			exchanges: []string{"GET /a 500"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &common.AccessLogContext{ParseStats: common.NewProtocolParseStats()}
+			analyzer := &http1CapturedAnalyzer{}
+			protocol := NewHTTP1Analyzer(ctx, analyzer)
+			connection := &PartitionConnection{
+				dataBuffers:     map[enums.ConnectionProtocol]*buffer.Buffer{enums.ConnectionProtocolHTTP: buffer.NewBuffer()},
+				protocolMetrics: map[enums.ConnectionProtocol]ProtocolMetrics{enums.ConnectionProtocolHTTP: protocol.GenerateConnection(1, 1)},
+			}
+			for _, capture := range tt.captures {
+				for _, d := range capture {
+					connection.AppendData(buildHTTP1DataEvent(d))
+				}
+				if err := protocol.Analyze(connection, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(tt.exchanges, analyzer.exchanges) {
+				t.Fatalf("expected exchanges: %v, actual: %v", tt.exchanges, analyzer.exchanges)
+			}
+			unmatched := ctx.ParseStats.Swap()[common.ProtocolParseIssueKey{
+				Protocol: enums.ConnectionProtocolHTTP, Issue: common.ProtocolParseIssueUnmatched}]
+			if unmatched != tt.unmatched {
+				t.Fatalf("expected unmatched count: %d, actual: %d", tt.unmatched, unmatched)
+			}
+		})
+	}
+}
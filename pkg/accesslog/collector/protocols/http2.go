@@ -81,6 +81,15 @@ type HTTP2Streaming struct {
 	RespHeaderBuffer *buffer.Buffer
 	RespBodyBuffer   *buffer.Buffer
 	Connection       *PartitionConnection
+	// IsGRPC is detected by the content type of the request, the messages are counted only for gRPC
+	IsGRPC       bool
+	ReqMessages  GRPCMessageCounter
+	RespMessages GRPCMessageCounter
+	// the captured body data when the payload capture is enabled
+	ReqPayload  []byte
+	RespPayload []byte
+	// IsTunnel is the HBONE CONNECT stream, the data is the tunneled workload traffic which analyzed in the workload connection
+	IsTunnel bool
 }
 
 func (r *HTTP2Protocol) GenerateConnection(connectionID, randomID uint64) ProtocolMetrics {
@@ -107,6 +116,7 @@ func (r *HTTP2Protocol) Analyze(connection *PartitionConnection, helper *Analyze
 		header, err := http2.ReadFrameHeader(buf)
 		if err != nil {
 			http2Log.Debugf("failed to read frame header, %v", err)
+			r.ctx.ParseStats.Increase(enums.ConnectionProtocolHTTP2, common.ProtocolParseIssueError)
 			if buf.SkipCurrentElement() {
 				break
 			}
@@ -183,6 +193,8 @@ func (r *HTTP2Protocol) HandleHeader(connection *PartitionConnection, header *ht
 			RespHeader:      make(map[string]string),
 			ReqHeaderBuffer: buf.Slice(true, startPos, buf.Position()),
 			Connection:      connection,
+			IsGRPC:          isGRPCContentType(headers["content-type"]),
+			IsTunnel:        r.recordHBONEStream(metrics, headers),
 		}
 		metrics.Streams[header.StreamID] = streaming
 		return enums.ParseResultSuccess, false, nil
@@ -211,13 +223,41 @@ func (r *HTTP2Protocol) HandleHeader(connection *PartitionConnection, header *ht
 	// is end of stream and in the response
 	if header.Flags.Has(http2.FlagHeadersEndStream) {
 		// should be end of the stream and send to the protocol
-		_ = r.analyzer.HandleWholeStream(connection, streaming)
+		if !streaming.IsTunnel {
+			_ = r.analyzer.HandleWholeStream(connection, streaming)
+		}
 		// delete streaming
 		delete(metrics.Streams, header.StreamID)
 	}
 	return enums.ParseResultSuccess, false, nil
 }
 
+// recordHBONEStream record the real destination of the HBONE CONNECT stream for correlating with the workload connection
+func (r *HTTP2Protocol) recordHBONEStream(metrics *HTTP2Metrics, headers map[string]string) bool {
+	if r.ctx.HBONE == nil || headers[":method"] != "CONNECT" {
+		return false
+	}
+	destIP, destPort, err := common.ParseHBONEConnect(headers)
+	if err != nil {
+		http2Log.Debugf("failed to parse the HBONE CONNECT authority, connection ID: %d, error: %v", metrics.ConnectionID, err)
+		return false
+	}
+	tunnel := r.ctx.ConnectionMgr.FindByID(metrics.ConnectionID, metrics.RandomID)
+	if tunnel == nil || tunnel.Socket == nil {
+		return false
+	}
+	r.ctx.HBONE.Add(&common.HBONEStream{
+		TunnelConnectionID: metrics.ConnectionID,
+		TunnelRandomID:     metrics.RandomID,
+		DestIP:             destIP,
+		DestPort:           destPort,
+		PeerIP:             tunnel.Socket.DestIP,
+	})
+	http2Log.Debugf("found the HBONE stream, tunnel connection ID: %d, random ID: %d, destination: %s:%d, peer: %s",
+		metrics.ConnectionID, metrics.RandomID, destIP, destPort, tunnel.Socket.DestIP)
+	return true
+}
+
 func (r *HTTP2Protocol) validateIsStreamOpenTooLong(connection *PartitionConnection,
 	metrics *HTTP2Metrics, id uint32, streaming *HTTP2Streaming) {
 	// if in the response mode or the request body is not nil, then skip
@@ -243,7 +283,7 @@ func (r *HTTP2Protocol) validateIsStreamOpenTooLong(connection *PartitionConnect
 	}
 }
 
-func (r *HTTP2Protocol) HandleWholeStream(_ *PartitionConnection, stream *HTTP2Streaming) error {
+func (r *HTTP2Protocol) HandleWholeStream(connection *PartitionConnection, stream *HTTP2Streaming) error {
 	details := make([]events.SocketDetail, 0)
 	var allInclude = true
 	var idRange *buffer.DataIDRange
@@ -271,7 +311,7 @@ func (r *HTTP2Protocol) HandleWholeStream(_ *PartitionConnection, stream *HTTP2S
 				Version:   v3.AccessLogHTTPProtocolVersion_HTTP2,
 				Request: &v3.AccessLogHTTPProtocolRequest{
 					Method:             r.ParseHTTPMethod(stream),
-					Path:               stream.ReqHeader[":path"],
+					Path:               r.ctx.EndpointNormalizer.Normalize(stream.ReqHeader[":path"]),
 					SizeOfHeadersBytes: r.BufferSizeOfZero(stream.ReqHeaderBuffer),
 					SizeOfBodyBytes:    r.BufferSizeOfZero(stream.ReqBodyBuffer),
 					Host:               streamHost,
@@ -287,9 +327,38 @@ func (r *HTTP2Protocol) HandleWholeStream(_ *PartitionConnection, stream *HTTP2S
 			},
 		},
 	}))
+	forwarder.SendCorrelationEvent(r.ctx, details, func(key string) string {
+		return stream.ReqHeader[key]
+	}, stream.ReqHeader[":method"], r.ctx.EndpointNormalizer.Normalize(stream.ReqHeader[":path"]), stream.Status)
+	r.capturePayload(details, stream)
+	forwarder.SendContinuousProfilingEvent(r.ctx, details, stream.ReqHeader[":path"], stream.Status)
+	// the split stream is not finished, so only the finished gRPC call is recorded
+	if stream.IsGRPC && stream.IsInResponse {
+		sendGRPCRecord(r.ctx, connection, stream, details)
+	}
 	return nil
 }
 
+func (r *HTTP2Protocol) capturePayload(details []events.SocketDetail, stream *HTTP2Streaming) {
+	rule := forwarder.PayloadCaptureRule(r.ctx, "http2")
+	if rule == nil {
+		return
+	}
+	record := &common.PayloadRecord{
+		Protocol:   "http2",
+		Method:     stream.ReqHeader[":method"],
+		Path:       r.ctx.EndpointNormalizer.Normalize(stream.ReqHeader[":path"]),
+		StatusCode: stream.Status,
+	}
+	if rule.Request {
+		record.Request = buildHTTP2PayloadMessage(r.ctx.Payloads, rule, stream.ReqHeader, stream.ReqPayload)
+	}
+	if rule.Response {
+		record.Response = buildHTTP2PayloadMessage(r.ctx.Payloads, rule, stream.RespHeader, stream.RespPayload)
+	}
+	forwarder.SendPayloadEvent(r.ctx, details, record)
+}
+
 func (r *HTTP2Protocol) OnProtocolBreak(*PartitionConnection, *HTTP2Metrics) {
 }
 
@@ -337,11 +406,38 @@ func (r *HTTP2Protocol) HandleData(connection *PartitionConnection, header *http
 	if err := buf.ReadUntilBufferFull(bytes); err != nil {
 		return enums.ParseResultSkipPackage, false, err
 	}
+	if streaming.IsTunnel {
+		// the tunneled data is not buffered, the stream is finished when any side closed
+		if header.Flags.Has(http2.FlagDataEndStream) {
+			delete(metrics.Streams, header.StreamID)
+		}
+		return enums.ParseResultSuccess, false, nil
+	}
 	if !streaming.IsInResponse {
 		streaming.ReqBodyBuffer = buffer.CombineSlices(true, buf, streaming.ReqBodyBuffer, buf.Slice(true, startPos, buf.Position()))
 	} else {
 		streaming.RespBodyBuffer = buffer.CombineSlices(true, buf, streaming.RespBodyBuffer, buf.Slice(true, startPos, buf.Position()))
 	}
+	data := bytes
+	if header.Flags.Has(http2.FlagDataPadded) && len(data) > 0 {
+		padding := int(data[0])
+		data = data[1:max(1, len(data)-padding)]
+	}
+	if streaming.IsGRPC {
+		if streaming.IsInResponse {
+			streaming.RespMessages.Feed(data)
+		} else {
+			streaming.ReqMessages.Feed(data)
+		}
+	}
+	if rule := forwarder.PayloadCaptureRule(r.ctx, "http2"); rule != nil {
+		limit := rule.MaxBytes + common.PayloadReadMargin
+		if streaming.IsInResponse && rule.Response {
+			streaming.RespPayload = appendHTTP2PayloadBody(streaming.RespPayload, data, limit)
+		} else if !streaming.IsInResponse && rule.Request {
+			streaming.ReqPayload = appendHTTP2PayloadBody(streaming.ReqPayload, data, limit)
+		}
+	}
 
 	r.validateIsStreamOpenTooLong(connection, metrics, header.StreamID, streaming)
 	return enums.ParseResultSuccess, false, nil
@@ -0,0 +1,433 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+const (
+	kafkaProtocolName = "kafka"
+	// the max size of the Kafka message, bigger message is treated as not the Kafka protocol
+	kafkaMaxMessageSize = 100 * 1024 * 1024
+	// the max count of the requests which waiting for the response in one connection
+	kafkaMaxInFlightRequests = 1024
+	kafkaMaxAPIKey           = 100
+	kafkaMaxAPIVersion       = 30
+
+	kafkaAPIKeyProduce int16 = 0
+	kafkaAPIKeyFetch   int16 = 1
+	// the first version of the Produce and Fetch which using the flexible(compact) format
+	kafkaProduceFlexibleVersion int16 = 9
+	kafkaFetchFlexibleVersion   int16 = 12
+	// the first version of the Produce and Fetch which using the topic ID instead of the topic name
+	kafkaProduceTopicIDVersion int16 = 13
+	kafkaFetchTopicIDVersion   int16 = 13
+)
+
+var kafkaPorts = []uint16{9092}
+
+var kafkaAPINames = map[int16]string{
+	0:  "Produce",
+	1:  "Fetch",
+	2:  "ListOffsets",
+	3:  "Metadata",
+	8:  "OffsetCommit",
+	9:  "OffsetFetch",
+	10: "FindCoordinator",
+	11: "JoinGroup",
+	12: "Heartbeat",
+	13: "LeaveGroup",
+	14: "SyncGroup",
+	15: "DescribeGroups",
+	16: "ListGroups",
+	17: "SaslHandshake",
+	18: "ApiVersions",
+	19: "CreateTopics",
+	20: "DeleteTopics",
+	22: "InitProducerId",
+	24: "AddPartitionsToTxn",
+	25: "AddOffsetsToTxn",
+	26: "EndTxn",
+	28: "TxnOffsetCommit",
+	36: "SaslAuthenticate",
+}
+
+var errKafkaShortData = errors.New("the Kafka data is too short")
+
+func init() {
+	if _, err := RegisterProtocolAnalyzer(&kafkaAnalyzer{}); err != nil {
+		panic(err)
+	}
+}
+
+// kafkaAnalyzer decode the Kafka wire protocol, the Produce and Fetch requests are decoded with the topic
+type kafkaAnalyzer struct {
+}
+
+func (k *kafkaAnalyzer) Name() string {
+	return kafkaProtocolName
+}
+
+func (k *kafkaAnalyzer) Ports() []uint16 {
+	return kafkaPorts
+}
+
+func (k *kafkaAnalyzer) NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol {
+	return NewRPCProtocol(ctx, protocol, func() RPCDecoder {
+		return &kafkaDecoder{inFlight: make(map[int32]*kafkaRequest)}
+	})
+}
+
+type kafkaRequest struct {
+	apiKey        int16
+	apiVersion    int16
+	correlationID int32
+	// the first topic and partition of the Produce and Fetch request, the partition is -1 if not found
+	topic     string
+	partition int32
+	// the Produce request without acknowledgment would not receive the response
+	noResponse bool
+
+	size      int
+	startTime uint64
+	endTime   uint64
+}
+
+type kafkaDecoder struct {
+	inFlight map[int32]*kafkaRequest
+}
+
+func (d *kafkaDecoder) Decode(stream *RPCStream) (int, []*RPCExchange, error) {
+	data := stream.Data()
+	offset := 0
+	var exchanges []*RPCExchange
+	for len(data)-offset >= 4 {
+		size := int(int32(binary.BigEndian.Uint32(data[offset:])))
+		if size < 4 || size > kafkaMaxMessageSize {
+			return offset, exchanges, fmt.Errorf("invalid Kafka message size: %d", size)
+		}
+		length := size + 4
+		if !stream.Ready(offset, length) {
+			break
+		}
+		message := data[offset+4 : min(offset+length, len(data))]
+		exchange, err := d.decodeMessage(stream, message, offset, length)
+		if err != nil {
+			return offset, exchanges, err
+		}
+		if exchange != nil {
+			exchanges = append(exchanges, exchange)
+		}
+		offset += length
+	}
+	return offset, exchanges, nil
+}
+
+func (d *kafkaDecoder) decodeMessage(stream *RPCStream, message []byte, offset, length int) (*RPCExchange, error) {
+	isRequest, detected := stream.IsRequest()
+	if !detected || isRequest {
+		request, err := parseKafkaRequest(message)
+		if err != nil {
+			if !detected {
+				// the response of the request which sent before monitoring, skip it
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !detected {
+			stream.MarkRequest()
+		}
+		request.size = length
+		request.startTime, request.endTime = stream.StartTime(offset), stream.EndTime(offset+length-1)
+		rpcLog.Debugf("decoded Kafka request, api key: %d, version: %d, correlation ID: %d, topic: %s, partition: %d",
+			request.apiKey, request.apiVersion, request.correlationID, request.topic, request.partition)
+		if request.noResponse {
+			return request.exchange(request.endTime, 0, ""), nil
+		}
+		if len(d.inFlight) >= kafkaMaxInFlightRequests {
+			// the responses are lost, clean the requests to avoid the memory leak
+			d.inFlight = make(map[int32]*kafkaRequest)
+		}
+		d.inFlight[request.correlationID] = request
+		return nil, nil
+	}
+
+	if len(message) < 4 {
+		return nil, errKafkaShortData
+	}
+	correlationID := int32(binary.BigEndian.Uint32(message))
+	request := d.inFlight[correlationID]
+	if request == nil {
+		return nil, nil
+	}
+	delete(d.inFlight, correlationID)
+	var status string
+	if code, ok := parseKafkaResponseErrorCode(message[4:], request.apiKey, request.apiVersion); ok {
+		status = strconv.Itoa(int(code))
+	}
+	return request.exchange(stream.EndTime(offset+length-1), length, status), nil
+}
+
+func (r *kafkaRequest) exchange(endTime uint64, responseSize int, status string) *RPCExchange {
+	operation := kafkaAPINames[r.apiKey]
+	if operation == "" {
+		operation = fmt.Sprintf("api_%d", r.apiKey)
+	}
+	return &RPCExchange{
+		Operation:     operation,
+		Resource:      r.topic,
+		Status:        status,
+		StartTime:     r.startTime,
+		EndTime:       endTime,
+		RequestBytes:  uint64(r.size),
+		ResponseBytes: uint64(responseSize),
+	}
+}
+
+// parseKafkaRequest parse the request header(without the size), and the first topic of the Produce and Fetch request,
+// the body could be truncated
+func parseKafkaRequest(data []byte) (*kafkaRequest, error) {
+	r := &kafkaReader{data: data}
+	request := &kafkaRequest{
+		apiKey:        r.int16(),
+		apiVersion:    r.int16(),
+		correlationID: r.int32(),
+		partition:     -1,
+	}
+	clientIDLength := r.int16()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if request.apiKey < 0 || request.apiKey > kafkaMaxAPIKey || request.apiVersion < 0 || request.apiVersion > kafkaMaxAPIVersion ||
+		request.correlationID < 0 || clientIDLength < -1 {
+		return nil, fmt.Errorf("invalid Kafka request header, api key: %d, version: %d, correlation ID: %d",
+			request.apiKey, request.apiVersion, request.correlationID)
+	}
+	r.skip(int(max(clientIDLength, 0)))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	switch request.apiKey {
+	case kafkaAPIKeyProduce:
+		flexible := request.apiVersion >= kafkaProduceFlexibleVersion
+		if flexible {
+			r.skipTaggedFields()
+		}
+		if request.apiVersion >= 3 {
+			r.skipString(flexible) // transactional ID
+		}
+		acks := r.int16()
+		request.noResponse = r.err == nil && acks == 0
+		r.int32() // timeout
+		request.topic, request.partition = r.firstTopicPartition(flexible, request.apiVersion >= kafkaProduceTopicIDVersion)
+	case kafkaAPIKeyFetch:
+		flexible := request.apiVersion >= kafkaFetchFlexibleVersion
+		if flexible {
+			r.skipTaggedFields()
+		}
+		if request.apiVersion <= 14 {
+			r.int32() // replica ID
+		}
+		r.int32() // max wait
+		r.int32() // min bytes
+		if request.apiVersion >= 3 {
+			r.int32() // max bytes
+		}
+		if request.apiVersion >= 4 {
+			r.skip(1) // isolation level
+		}
+		if request.apiVersion >= 7 {
+			r.int32() // session ID
+			r.int32() // session epoch
+		}
+		request.topic, request.partition = r.firstTopicPartition(flexible, request.apiVersion >= kafkaFetchTopicIDVersion)
+	}
+	if r.err != nil && !errors.Is(r.err, errKafkaShortData) {
+		return nil, r.err
+	}
+	return request, nil
+}
+
+// parseKafkaResponseErrorCode parse the error code of the Produce and Fetch response(without the size and correlation ID),
+// the top level error code is used if exists, otherwise the error code of the first partition
+func parseKafkaResponseErrorCode(data []byte, apiKey, version int16) (int16, bool) {
+	r := &kafkaReader{data: data}
+	var flexible, topicID bool
+	switch apiKey {
+	case kafkaAPIKeyProduce:
+		flexible, topicID = version >= kafkaProduceFlexibleVersion, version >= kafkaProduceTopicIDVersion
+	case kafkaAPIKeyFetch:
+		flexible, topicID = version >= kafkaFetchFlexibleVersion, version >= kafkaFetchTopicIDVersion
+		if flexible {
+			r.skipTaggedFields()
+		}
+		if version >= 1 {
+			r.int32() // throttle time
+		}
+		if version >= 7 {
+			code := r.int16()
+			r.int32() // session ID
+			if r.err != nil {
+				return 0, false
+			}
+			if code != 0 {
+				return code, true
+			}
+		}
+	default:
+		return 0, false
+	}
+	if apiKey == kafkaAPIKeyProduce && flexible {
+		r.skipTaggedFields()
+	}
+	if r.arrayLength(flexible) < 1 {
+		return 0, false
+	}
+	r.skipTopic(flexible, topicID)
+	if r.arrayLength(flexible) < 1 {
+		return 0, false
+	}
+	r.int32() // partition index
+	code := r.int16()
+	if r.err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// kafkaReader read the Kafka primitive types, the error is recorded and the following reading returns zero value
+type kafkaReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *kafkaReader) skip(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.offset+n > len(r.data) {
+		r.err = errKafkaShortData
+		return nil
+	}
+	result := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return result
+}
+
+func (r *kafkaReader) int16() int16 {
+	if b := r.skip(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) int32() int32 {
+	if b := r.skip(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data[r.offset:])
+	if n <= 0 {
+		r.err = errKafkaShortData
+		return 0
+	}
+	r.offset += n
+	return value
+}
+
+// stringLength read the length of the string, the compact string is encoded as the unsigned varint with length+1
+func (r *kafkaReader) stringLength(compact bool) int {
+	if compact {
+		return int(r.uvarint()) - 1
+	}
+	return int(r.int16())
+}
+
+func (r *kafkaReader) string(compact bool) string {
+	length := r.stringLength(compact)
+	if length <= 0 {
+		return ""
+	}
+	return string(r.skip(length))
+}
+
+func (r *kafkaReader) skipString(compact bool) {
+	if length := r.stringLength(compact); length > 0 {
+		r.skip(length)
+	}
+}
+
+// arrayLength read the length of the array, the compact array is encoded as the unsigned varint with length+1
+func (r *kafkaReader) arrayLength(compact bool) int {
+	if compact {
+		return int(r.uvarint()) - 1
+	}
+	return int(r.int32())
+}
+
+func (r *kafkaReader) skipTaggedFields() {
+	count := r.uvarint()
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		r.uvarint() // tag
+		r.skip(int(r.uvarint()))
+	}
+}
+
+// skipTopic skip the topic name or the topic ID(UUID)
+func (r *kafkaReader) skipTopic(compact, topicID bool) {
+	if topicID {
+		r.skip(16)
+		return
+	}
+	r.skipString(compact)
+}
+
+// firstTopicPartition read the first topic name and partition of the topics array, the topic name is empty if using the topic ID
+func (r *kafkaReader) firstTopicPartition(compact, topicID bool) (topic string, partition int32) {
+	partition = -1
+	if r.arrayLength(compact) < 1 {
+		return "", partition
+	}
+	if topicID {
+		r.skip(16)
+	} else {
+		topic = r.string(compact)
+	}
+	if r.arrayLength(compact) < 1 {
+		return topic, partition
+	}
+	if p := r.int32(); r.err == nil {
+		partition = p
+	}
+	return topic, partition
+}
@@ -0,0 +1,226 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+type kafkaTestBuilder struct {
+	data []byte
+}
+
+func (b *kafkaTestBuilder) int8(v int8) *kafkaTestBuilder {
+	b.data = append(b.data, byte(v))
+	return b
+}
+
+func (b *kafkaTestBuilder) int16(v int16) *kafkaTestBuilder {
+	b.data = binary.BigEndian.AppendUint16(b.data, uint16(v))
+	return b
+}
+
+func (b *kafkaTestBuilder) int32(v int32) *kafkaTestBuilder {
+	b.data = binary.BigEndian.AppendUint32(b.data, uint32(v))
+	return b
+}
+
+func (b *kafkaTestBuilder) int64(v int64) *kafkaTestBuilder {
+	b.data = binary.BigEndian.AppendUint64(b.data, uint64(v))
+	return b
+}
+
+func (b *kafkaTestBuilder) uvarint(v uint64) *kafkaTestBuilder {
+	b.data = binary.AppendUvarint(b.data, v)
+	return b
+}
+
+func (b *kafkaTestBuilder) string(v string) *kafkaTestBuilder {
+	b.int16(int16(len(v)))
+	b.data = append(b.data, v...)
+	return b
+}
+
+func (b *kafkaTestBuilder) compactString(v string) *kafkaTestBuilder {
+	b.uvarint(uint64(len(v) + 1))
+	b.data = append(b.data, v...)
+	return b
+}
+
+func (b *kafkaTestBuilder) header(apiKey, version int16, correlationID int32) *kafkaTestBuilder {
+	return b.int16(apiKey).int16(version).int32(correlationID).string("producer-1")
+}
+
+func (b *kafkaTestBuilder) message() []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(b.data))), b.data...)
+}
+
+func TestParseKafkaRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		apiKey     int16
+		topic      string
+		partition  int32
+		noResponse bool
+		hasError   bool
+	}{
+		{
+			name: "produce v7",
+			data: (&kafkaTestBuilder{}).header(0, 7, 3).int16(-1).int16(1).int32(30000).
+				int32(1).string("orders").int32(1).int32(2).int32(0).data,
+			apiKey: 0, topic: "orders", partition: 2,
+		},
+		{
+			name: "produce v9 without acks",
+			data: (&kafkaTestBuilder{}).header(0, 9, 4).uvarint(0).uvarint(0).int16(0).int32(30000).
+				uvarint(2).compactString("payments").uvarint(2).int32(5).data,
+			apiKey: 0, topic: "payments", partition: 5, noResponse: true,
+		},
+		{
+			name: "fetch v11",
+			data: (&kafkaTestBuilder{}).header(1, 11, 5).int32(-1).int32(500).int32(1).int32(52428800).int8(0).
+				int32(0).int32(-1).int32(1).string("orders").int32(1).int32(7).int32(0).int64(100).data,
+			apiKey: 1, topic: "orders", partition: 7,
+		},
+		{
+			name:   "truncated produce topic",
+			data:   (&kafkaTestBuilder{}).header(0, 7, 6).int16(-1).int16(1).int32(30000).int32(1).int16(6).data,
+			apiKey: 0, partition: -1,
+		},
+		{
+			name:   "metadata",
+			data:   (&kafkaTestBuilder{}).header(3, 9, 7).data,
+			apiKey: 3, partition: -1,
+		},
+		{
+			name:     "invalid api key",
+			data:     (&kafkaTestBuilder{}).header(1000, 0, 8).data,
+			hasError: true,
+		},
+		{
+			name:     "short header",
+			data:     []byte{0, 0, 0},
+			hasError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := parseKafkaRequest(tt.data)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("expected error: %t, actual: %v", tt.hasError, err)
+			}
+			if err != nil {
+				return
+			}
+			if request.apiKey != tt.apiKey || request.topic != tt.topic || request.partition != tt.partition ||
+				request.noResponse != tt.noResponse {
+				t.Errorf("unexpected request: %+v", request)
+			}
+		})
+	}
+}
+
+func TestParseKafkaResponseErrorCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		apiKey  int16
+		version int16
+		code    int16
+		found   bool
+	}{
+		{
+			name:   "produce v7",
+			data:   (&kafkaTestBuilder{}).int32(1).string("orders").int32(1).int32(2).int16(6).data,
+			apiKey: 0, version: 7, code: 6, found: true,
+		},
+		{
+			name:   "produce v9",
+			data:   (&kafkaTestBuilder{}).uvarint(0).uvarint(2).compactString("orders").uvarint(2).int32(2).int16(0).data,
+			apiKey: 0, version: 9, code: 0, found: true,
+		},
+		{
+			name:   "fetch v11 top level error",
+			data:   (&kafkaTestBuilder{}).int32(0).int16(70).int32(0).int32(0).data,
+			apiKey: 1, version: 11, code: 70, found: true,
+		},
+		{
+			name:   "fetch v4 partition error",
+			data:   (&kafkaTestBuilder{}).int32(0).int32(1).string("orders").int32(1).int32(7).int16(1).data,
+			apiKey: 1, version: 4, code: 1, found: true,
+		},
+		{
+			name:   "metadata",
+			data:   (&kafkaTestBuilder{}).int32(0).data,
+			apiKey: 3, version: 9,
+		},
+		{
+			name:   "truncated",
+			data:   (&kafkaTestBuilder{}).int32(1).data,
+			apiKey: 0, version: 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, found := parseKafkaResponseErrorCode(tt.data, tt.apiKey, tt.version)
+			if found != tt.found || code != tt.code {
+				t.Errorf("expected code: %d(%t), actual: %d(%t)", tt.code, tt.found, code, found)
+			}
+		})
+	}
+}
+
+func TestKafkaDecoder(t *testing.T) {
+	metrics := &RPCMetrics{requestDirection: enums.SocketDataDirectionEgress}
+	request := &RPCStream{direction: enums.SocketDataDirectionEgress, connection: metrics}
+	response := &RPCStream{direction: enums.SocketDataDirectionIngress, connection: metrics}
+	decoder := &kafkaDecoder{inFlight: make(map[int32]*kafkaRequest)}
+
+	produce := (&kafkaTestBuilder{}).header(0, 7, 3).int16(-1).int16(1).int32(30000).
+		int32(1).string("orders").int32(1).int32(2).int32(0).message()
+	// the request is split into two syscalls
+	request.data = append(request.data, produce[:10]...)
+	request.segments = []rpcStreamSegment{{end: 10, startTime: 100, endTime: 110}}
+	if consumed, exchanges, err := decoder.Decode(request); err != nil || consumed != 0 || len(exchanges) != 0 {
+		t.Fatalf("the request should wait for more data, consumed: %d, exchanges: %d, error: %v", consumed, len(exchanges), err)
+	}
+	request.data = append(request.data, produce[10:]...)
+	request.segments = append(request.segments, rpcStreamSegment{end: len(produce), startTime: 120, endTime: 130})
+	if consumed, exchanges, err := decoder.Decode(request); err != nil || consumed != len(produce) || len(exchanges) != 0 {
+		t.Fatalf("the request should be consumed, consumed: %d, exchanges: %d, error: %v", consumed, len(exchanges), err)
+	}
+
+	// unknown correlation ID response is skipped
+	unknown := (&kafkaTestBuilder{}).int32(99).int32(0).message()
+	result := (&kafkaTestBuilder{}).int32(3).int32(1).string("orders").int32(1).int32(2).int16(0).message()
+	response.data = append(append(response.data, unknown...), result...)
+	response.segments = []rpcStreamSegment{{end: len(response.data), startTime: 200, endTime: 250}}
+	consumed, exchanges, err := decoder.Decode(response)
+	if err != nil || consumed != len(response.data) || len(exchanges) != 1 {
+		t.Fatalf("the response should be decoded, consumed: %d, exchanges: %d, error: %v", consumed, len(exchanges), err)
+	}
+	expected := RPCExchange{Operation: "Produce", Resource: "orders", Status: "0", StartTime: 100, EndTime: 250,
+		RequestBytes: uint64(len(produce)), ResponseBytes: uint64(len(result))}
+	if *exchanges[0] != expected {
+		t.Errorf("expected exchange: %+v, actual: %+v", expected, *exchanges[0])
+	}
+}
@@ -0,0 +1,380 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+const (
+	mysqlProtocolName = "mysql"
+	// the max count of the prepared statements which cached in one connection
+	mysqlMaxPreparedStatements = 1024
+	// the protocol version of the server greeting packet
+	mysqlGreetingProtocolVersion = 0x0a
+	mysqlCapabilityDeprecateEOF  = 0x01000000
+	mysqlServerMoreResultsExists = 0x0008
+
+	mysqlPacketOK  = 0x00
+	mysqlPacketEOF = 0xfe
+	mysqlPacketERR = 0xff
+	// the max payload size of the EOF packet, the bigger packet start with 0xfe is the row or OK packet
+	mysqlMaxEOFPacketSize = 9
+	mysqlMaxPacketSize    = 0xffffff
+
+	mysqlCommandQuit             = 0x01
+	mysqlCommandQuery            = 0x03
+	mysqlCommandStmtPrepare      = 0x16
+	mysqlCommandStmtExecute      = 0x17
+	mysqlCommandStmtSendLongData = 0x18
+	mysqlCommandStmtClose        = 0x19
+)
+
+var mysqlPorts = []uint16{3306}
+
+var mysqlCommandNames = map[byte]string{
+	0x01: "QUIT",
+	0x02: "INIT_DB",
+	0x03: "QUERY",
+	0x04: "FIELD_LIST",
+	0x08: "SHUTDOWN",
+	0x09: "STATISTICS",
+	0x0d: "DEBUG",
+	0x0e: "PING",
+	0x11: "CHANGE_USER",
+	0x12: "BINLOG_DUMP",
+	0x16: "PREPARE",
+	0x17: "EXECUTE",
+	0x18: "SEND_LONG_DATA",
+	0x19: "CLOSE_STMT",
+	0x1a: "RESET_STMT",
+	0x1b: "SET_OPTION",
+	0x1c: "FETCH",
+	0x1f: "RESET_CONNECTION",
+}
+
+func init() {
+	if _, err := RegisterProtocolAnalyzer(&mysqlAnalyzer{}); err != nil {
+		panic(err)
+	}
+}
+
+// mysqlAnalyzer decode the MySQL client/server protocol, the statements are reported as the digest with literals stripped
+type mysqlAnalyzer struct {
+}
+
+func (m *mysqlAnalyzer) Name() string {
+	return mysqlProtocolName
+}
+
+func (m *mysqlAnalyzer) Ports() []uint16 {
+	return mysqlPorts
+}
+
+func (m *mysqlAnalyzer) NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol {
+	return NewRPCProtocol(ctx, protocol, func() RPCDecoder {
+		return &mysqlDecoder{statements: make(map[uint32]*sqlStatement)}
+	})
+}
+
+type mysqlResponseState int
+
+const (
+	mysqlResponseStart mysqlResponseState = iota
+	mysqlResponseColumns
+	mysqlResponseColumnsEOF
+	mysqlResponseRows
+)
+
+type mysqlCommand struct {
+	command   byte
+	statement *sqlStatement
+	status    string
+
+	startTime     uint64
+	endTime       uint64
+	requestBytes  int
+	responseBytes int
+	responded     bool
+
+	state          mysqlResponseState
+	pendingColumns uint64
+}
+
+type mysqlDecoder struct {
+	deprecateEOF bool
+	current      *mysqlCommand
+	// the prepared statements by the statement ID
+	statements map[uint32]*sqlStatement
+}
+
+func (d *mysqlDecoder) Decode(stream *RPCStream) (int, []*RPCExchange, error) {
+	data := stream.Data()
+	offset := 0
+	var exchanges []*RPCExchange
+	for len(data)-offset >= 4 {
+		length := int(data[offset]) | int(data[offset+1])<<8 | int(data[offset+2])<<16
+		sequence := data[offset+3]
+		total := length + 4
+		if !stream.Ready(offset, total) {
+			break
+		}
+		payload := data[offset+4 : min(offset+total, len(data))]
+		exchanges = append(exchanges, d.decodePacket(stream, sequence, payload, length, offset, total)...)
+		offset += total
+	}
+	return offset, exchanges, nil
+}
+
+func (d *mysqlDecoder) decodePacket(stream *RPCStream, sequence byte, payload []byte, length, offset, total int) []*RPCExchange {
+	isRequest, detected := stream.IsRequest()
+	if !detected {
+		// only the greeting of server and the command of client start with the zero sequence
+		if sequence != 0 || len(payload) == 0 {
+			return nil
+		}
+		if isMySQLGreeting(payload) {
+			stream.MarkResponse()
+			return nil
+		}
+		stream.MarkRequest()
+		isRequest = true
+	}
+
+	if isRequest {
+		if sequence != 0 {
+			// the handshake response contains the capabilities of the client
+			if sequence == 1 && d.current == nil && len(payload) >= 4 {
+				d.deprecateEOF = binary.LittleEndian.Uint32(payload)&mysqlCapabilityDeprecateEOF != 0
+			}
+			return nil
+		}
+		return d.handleCommand(stream, payload, offset, total)
+	}
+
+	if d.current == nil {
+		// the greeting and authentication packets of server, or the following packets of the prepared statement
+		return nil
+	}
+	d.current.responded = true
+	d.current.responseBytes += total
+	d.current.endTime = stream.EndTime(offset + total - 1)
+	if d.handleResponse(payload, length) {
+		exchange := d.current.exchange()
+		d.current = nil
+		return []*RPCExchange{exchange}
+	}
+	return nil
+}
+
+func (d *mysqlDecoder) handleCommand(stream *RPCStream, payload []byte, offset, total int) []*RPCExchange {
+	var exchanges []*RPCExchange
+	// the previous command is finished when the new command is sent, if the terminated packet is not found
+	if d.current != nil && d.current.responded {
+		exchanges = append(exchanges, d.current.exchange())
+	}
+	d.current = nil
+	if len(payload) == 0 {
+		return exchanges
+	}
+	command := &mysqlCommand{
+		command:      payload[0],
+		startTime:    stream.StartTime(offset),
+		endTime:      stream.EndTime(offset + total - 1),
+		requestBytes: total,
+	}
+	switch payload[0] {
+	case mysqlCommandQuery, mysqlCommandStmtPrepare:
+		command.statement = newSQLStatement(string(payload[1:]), true)
+	case mysqlCommandStmtExecute, mysqlCommandStmtSendLongData, mysqlCommandStmtClose:
+		if len(payload) >= 5 {
+			statementID := binary.LittleEndian.Uint32(payload[1:])
+			command.statement = d.statements[statementID]
+			if payload[0] == mysqlCommandStmtClose {
+				delete(d.statements, statementID)
+			}
+		}
+	}
+	switch payload[0] {
+	case mysqlCommandQuit, mysqlCommandStmtSendLongData, mysqlCommandStmtClose:
+		// the server never response these commands
+		return append(exchanges, command.exchange())
+	}
+	d.current = command
+	return exchanges
+}
+
+// handleResponse handle the response packet of the current command, return true if the response is finished
+func (d *mysqlDecoder) handleResponse(payload []byte, length int) bool {
+	if len(payload) == 0 {
+		return false
+	}
+	current := d.current
+	switch current.state {
+	case mysqlResponseStart:
+		switch {
+		case payload[0] == mysqlPacketOK:
+			current.status = "0"
+			if current.command == mysqlCommandStmtPrepare && len(payload) >= 5 && len(d.statements) < mysqlMaxPreparedStatements {
+				d.statements[binary.LittleEndian.Uint32(payload[1:])] = current.statement
+			}
+			return current.command == mysqlCommandStmtPrepare || !mysqlMoreResults(payload, false)
+		case payload[0] == mysqlPacketERR:
+			current.status = mysqlErrorCode(payload)
+			return true
+		case payload[0] == mysqlPacketEOF && length < mysqlMaxEOFPacketSize:
+			current.status = "0"
+			return true
+		}
+		// the result set, starts with the column count
+		current.pendingColumns, _ = mysqlLengthEncodedInt(payload)
+		current.state = mysqlResponseColumns
+		if current.pendingColumns == 0 {
+			current.state = mysqlResponseRows
+		}
+	case mysqlResponseColumns:
+		current.pendingColumns--
+		if current.pendingColumns == 0 {
+			current.state = mysqlResponseColumnsEOF
+			if d.deprecateEOF {
+				current.state = mysqlResponseRows
+			}
+		}
+	case mysqlResponseColumnsEOF:
+		current.state = mysqlResponseRows
+		if payload[0] != mysqlPacketEOF || length >= mysqlMaxEOFPacketSize {
+			return d.handleResponse(payload, length)
+		}
+	case mysqlResponseRows:
+		switch {
+		case payload[0] == mysqlPacketERR:
+			current.status = mysqlErrorCode(payload)
+			return true
+		case payload[0] == mysqlPacketEOF && length < mysqlMaxPacketSize:
+			current.status = "0"
+			if mysqlMoreResults(payload, length < mysqlMaxEOFPacketSize) {
+				current.state = mysqlResponseStart
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (c *mysqlCommand) exchange() *RPCExchange {
+	operation := mysqlCommandNames[c.command]
+	if operation == "" {
+		operation = fmt.Sprintf("command_%d", c.command)
+	}
+	var resource string
+	if c.statement != nil {
+		resource = c.statement.digest
+		if c.command == mysqlCommandQuery || c.command == mysqlCommandStmtExecute {
+			operation = c.statement.command
+		}
+	}
+	return &RPCExchange{
+		Operation:     operation,
+		Resource:      resource,
+		Status:        c.status,
+		StartTime:     c.startTime,
+		EndTime:       c.endTime,
+		RequestBytes:  uint64(c.requestBytes),
+		ResponseBytes: uint64(c.responseBytes),
+	}
+}
+
+// isMySQLGreeting check the packet is the initial handshake packet of server, which contains the null-terminated server version
+func isMySQLGreeting(payload []byte) bool {
+	if len(payload) < 2 || payload[0] != mysqlGreetingProtocolVersion {
+		return false
+	}
+	for _, c := range payload[1:] {
+		if c == 0 {
+			return true
+		}
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return false
+}
+
+func mysqlErrorCode(payload []byte) string {
+	if len(payload) < 3 {
+		return ""
+	}
+	return strconv.Itoa(int(binary.LittleEndian.Uint16(payload[1:])))
+}
+
+// mysqlMoreResults check the status flags of the OK or EOF packet contains more results
+func mysqlMoreResults(payload []byte, eof bool) bool {
+	var status []byte
+	if eof {
+		// header, warnings, status flags
+		if len(payload) >= 5 {
+			status = payload[3:5]
+		}
+	} else {
+		// header, affected rows, last insert ID, status flags
+		_, affectedRowsSize := mysqlLengthEncodedInt(payload[1:])
+		if affectedRowsSize == 0 {
+			return false
+		}
+		_, lastInsertIDSize := mysqlLengthEncodedInt(payload[1+affectedRowsSize:])
+		if lastInsertIDSize == 0 {
+			return false
+		}
+		if start := 1 + affectedRowsSize + lastInsertIDSize; len(payload) >= start+2 {
+			status = payload[start : start+2]
+		}
+	}
+	return status != nil && binary.LittleEndian.Uint16(status)&mysqlServerMoreResultsExists != 0
+}
+
+// mysqlLengthEncodedInt read the length encoded integer, return the value and the size of it, the size is zero if invalid
+func mysqlLengthEncodedInt(data []byte) (value uint64, size int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	switch data[0] {
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	case 0xfb, 0xff:
+		return 0, 0
+	default:
+		return uint64(data[0]), 1
+	}
+	if len(data) < size {
+		return 0, 0
+	}
+	for i := size - 1; i > 0; i-- {
+		value = value<<8 | uint64(data[i])
+	}
+	return value, size
+}
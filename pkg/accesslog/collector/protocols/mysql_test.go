@@ -0,0 +1,92 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func mysqlTestPacket(sequence byte, payload ...byte) []byte {
+	return append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequence}, payload...)
+}
+
+func TestMySQLDecoder(t *testing.T) {
+	metrics := &RPCMetrics{}
+	client := &RPCStream{direction: enums.SocketDataDirectionEgress, connection: metrics}
+	server := &RPCStream{direction: enums.SocketDataDirectionIngress, connection: metrics}
+	decoder := &mysqlDecoder{statements: make(map[uint32]*sqlStatement)}
+	decode := func(stream *RPCStream, data []byte, startTime uint64) []*RPCExchange {
+		stream.data = append(stream.data[:0], data...)
+		stream.segments = []rpcStreamSegment{{end: len(data), startTime: startTime, endTime: startTime + 1}}
+		consumed, exchanges, err := decoder.Decode(stream)
+		if err != nil || consumed != len(data) {
+			t.Fatalf("decode failure, consumed: %d, error: %v", consumed, err)
+		}
+		return exchanges
+	}
+	eof := []byte{0xfe, 0, 0, 0x02, 0}
+
+	// the direction is detected by the server greeting
+	decode(server, mysqlTestPacket(0, append([]byte{0x0a}, "8.0.36\x00"...)...), 1)
+	if isRequest, detected := client.IsRequest(); !detected || !isRequest {
+		t.Fatalf("the client stream should be detected as the request")
+	}
+	decode(client, mysqlTestPacket(1, binary.LittleEndian.AppendUint32(nil, 0x000fa685)...), 2)
+	decode(server, mysqlTestPacket(2, 0, 0, 0, 0x02, 0, 0, 0), 3)
+
+	// the result set with one column and one row
+	query := mysqlTestPacket(0, append([]byte{mysqlCommandQuery}, "SELECT name FROM users WHERE id = 10"...)...)
+	if exchanges := decode(client, query, 10); len(exchanges) != 0 {
+		t.Fatalf("the query should wait for the response")
+	}
+	var resultSet []byte
+	resultSet = append(resultSet, mysqlTestPacket(1, 1)...)
+	resultSet = append(resultSet, mysqlTestPacket(2, append([]byte{3}, "def"...)...)...)
+	resultSet = append(resultSet, mysqlTestPacket(3, eof...)...)
+	resultSet = append(resultSet, mysqlTestPacket(4, append([]byte{4}, "test"...)...)...)
+	resultSet = append(resultSet, mysqlTestPacket(5, eof...)...)
+	exchanges := decode(server, resultSet, 20)
+	expected := RPCExchange{Operation: "SELECT", Resource: "SELECT name FROM users WHERE id = ?", Status: "0",
+		StartTime: 10, EndTime: 21, RequestBytes: uint64(len(query)), ResponseBytes: uint64(len(resultSet))}
+	if len(exchanges) != 1 || *exchanges[0] != expected {
+		t.Fatalf("unexpected exchanges of query: %+v", exchanges)
+	}
+
+	// the error response of the prepared statement execution
+	prepare := mysqlTestPacket(0, append([]byte{mysqlCommandStmtPrepare}, "DELETE FROM users WHERE id = ?"...)...)
+	decode(client, prepare, 30)
+	if exchanges = decode(server, mysqlTestPacket(1, 0, 7, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0), 31); len(exchanges) != 1 ||
+		exchanges[0].Operation != "PREPARE" {
+		t.Fatalf("unexpected exchanges of prepare: %+v", exchanges)
+	}
+	decode(client, mysqlTestPacket(0, mysqlCommandStmtExecute, 7, 0, 0, 0, 0, 1, 0, 0, 0), 40)
+	exchanges = decode(server, mysqlTestPacket(1, 0xff, 0x26, 0x04, '#', 'H', 'Y', '0', '0', '0'), 41)
+	if len(exchanges) != 1 || exchanges[0].Operation != "DELETE" || exchanges[0].Resource != "DELETE FROM users WHERE id = ?" ||
+		exchanges[0].Status != "1062" {
+		t.Fatalf("unexpected exchanges of execute: %+v", exchanges)
+	}
+
+	// the closing statement has no response
+	if exchanges = decode(client, mysqlTestPacket(0, mysqlCommandStmtClose, 7, 0, 0, 0), 50); len(exchanges) != 1 ||
+		exchanges[0].Operation != "CLOSE_STMT" || len(decoder.statements) != 0 {
+		t.Fatalf("unexpected exchanges of close statement: %+v", exchanges)
+	}
+}
@@ -0,0 +1,89 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/buffer"
+)
+
+// isPlainPayload checks the body could be captured as the text by the content type and encoding
+func isPlainPayload(contentType, contentEncoding string) (bool, string) {
+	if contentEncoding != "" && !strings.EqualFold(contentEncoding, "identity") {
+		return false, fmt.Sprintf("[not plain, content encoding: %s]", contentEncoding)
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if mediaType == "" || strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") || mediaType == "application/x-www-form-urlencoded" {
+		return true, ""
+	}
+	return false, fmt.Sprintf("[not plain, content type: %s]", contentType)
+}
+
+// readHTTP1PayloadBody reads the body of HTTP/1.x message at most the limit bytes, the chunked body is decoded
+func readHTTP1PayloadBody(headers http.Header, body *buffer.Buffer, limit int) []byte {
+	if body == nil || limit <= 0 {
+		return nil
+	}
+	if plain, desc := isPlainPayload(headers.Get("Content-Type"), headers.Get("Content-Encoding")); !plain {
+		return []byte(desc)
+	}
+	var reader io.Reader = body
+	if strings.EqualFold(headers.Get("Transfer-Encoding"), "chunked") {
+		reader = httputil.NewChunkedReader(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, int64(limit)))
+	if err != nil && len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+func buildHTTP1PayloadMessage(capture *common.PayloadCapture, rule *common.PayloadCaptureRule,
+	headers http.Header, body *buffer.Buffer) *common.PayloadMessage {
+	values := make(map[string]string, len(headers))
+	for name, v := range headers {
+		values[name] = strings.Join(v, ", ")
+	}
+	return capture.BuildMessage(rule, values, readHTTP1PayloadBody(headers, body, rule.MaxBytes+common.PayloadReadMargin))
+}
+
+// appendHTTP2PayloadBody appends the data of the DATA frame into the captured body until the limit
+func appendHTTP2PayloadBody(body, data []byte, limit int) []byte {
+	if remain := limit - len(body); remain > 0 {
+		if len(data) > remain {
+			data = data[:remain]
+		}
+		body = append(body, data...)
+	}
+	return body
+}
+
+func buildHTTP2PayloadMessage(capture *common.PayloadCapture, rule *common.PayloadCaptureRule,
+	headers map[string]string, body []byte) *common.PayloadMessage {
+	if plain, desc := isPlainPayload(headers["content-type"], headers["content-encoding"]); !plain {
+		body = []byte(desc)
+	}
+	return capture.BuildMessage(rule, headers, body)
+}
@@ -0,0 +1,360 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+const (
+	postgresProtocolName = "postgresql"
+	// the max count of the prepared statements and portals which cached in one connection
+	postgresMaxStatements = 1024
+	// the max count of the queries which waiting for the response in one connection
+	postgresMaxPendingQueries = 1024
+	// the max length of one message, the bigger message is treated as not the PostgreSQL protocol
+	postgresMaxMessageLength = 1 << 30
+	// the SQLSTATE of the successful completion
+	postgresStatusSuccess = "00000"
+
+	postgresProtocolVersion3 = 196608
+	postgresSSLRequest       = 80877103
+	postgresGSSENCRequest    = 80877104
+	postgresCancelRequest    = 80877102
+
+	// frontend messages
+	postgresMessageQuery     = 'Q'
+	postgresMessageParse     = 'P'
+	postgresMessageBind      = 'B'
+	postgresMessageExecute   = 'E'
+	postgresMessageSync      = 'S'
+	postgresMessageClose     = 'C'
+	postgresMessageTerminate = 'X'
+
+	// backend messages
+	postgresMessageAuthentication   = 'R'
+	postgresMessageCommandComplete  = 'C'
+	postgresMessageEmptyQuery       = 'I'
+	postgresMessagePortalSuspended  = 's'
+	postgresMessageErrorResponse    = 'E'
+	postgresMessageReadyForQuery    = 'Z'
+	postgresErrorFieldSQLState      = 'C'
+	postgresCloseStatement          = 'S'
+	postgresCommandTagEmptyQuery    = "EMPTY"
+	postgresCommandTagPortalSuspend = "SUSPENDED"
+)
+
+var postgresPorts = []uint16{5432}
+
+// the types of the messages sent by the client after the startup
+var postgresFrontendMessages = map[byte]bool{
+	'Q': true, 'P': true, 'B': true, 'D': true, 'E': true, 'S': true, 'H': true, 'C': true, 'X': true,
+	'p': true, 'd': true, 'c': true, 'f': true, 'F': true,
+}
+
+var errPostgresInvalidData = errors.New("invalid PostgreSQL data")
+
+func init() {
+	if _, err := RegisterProtocolAnalyzer(&postgresAnalyzer{}); err != nil {
+		panic(err)
+	}
+}
+
+// postgresAnalyzer decode the simple and extended query protocol of PostgreSQL,
+// the statements are reported as the digest with literals stripped
+type postgresAnalyzer struct {
+}
+
+func (p *postgresAnalyzer) Name() string {
+	return postgresProtocolName
+}
+
+func (p *postgresAnalyzer) Ports() []uint16 {
+	return postgresPorts
+}
+
+func (p *postgresAnalyzer) NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol {
+	return NewRPCProtocol(ctx, protocol, func() RPCDecoder {
+		return &postgresDecoder{
+			statements: make(map[string]*sqlStatement),
+			portals:    make(map[string]*sqlStatement),
+		}
+	})
+}
+
+type postgresQueryType int
+
+const (
+	// the query of simple query protocol, finished by the ReadyForQuery
+	postgresQuerySimple postgresQueryType = iota
+	// the execution of the portal in extended query protocol, finished by the CommandComplete
+	postgresQueryExecute
+	// the sync of the extended query protocol, finished by the ReadyForQuery
+	postgresQuerySync
+)
+
+type postgresQuery struct {
+	queryType postgresQueryType
+	statement *sqlStatement
+	tag       string
+	status    string
+
+	startTime     uint64
+	endTime       uint64
+	requestBytes  int
+	responseBytes int
+}
+
+type postgresDecoder struct {
+	// waiting for the single byte response of the SSLRequest or GSSENCRequest
+	encryptionRequested bool
+	pending             []*postgresQuery
+	// the parse, bind and describe messages before the execution
+	batchStartTime uint64
+	batchBytes     int
+	// the prepared statements and the bound portals by the name, the empty name is the unnamed one
+	statements map[string]*sqlStatement
+	portals    map[string]*sqlStatement
+}
+
+func (d *postgresDecoder) Decode(stream *RPCStream) (int, []*RPCExchange, error) {
+	data := stream.Data()
+	offset := 0
+	var exchanges []*RPCExchange
+	for offset < len(data) {
+		isRequest, detected := stream.IsRequest()
+		if !detected {
+			// the client starts with the untyped startup message, and the server starts with the authentication
+			switch data[offset] {
+			case 0:
+				stream.MarkRequest()
+			case postgresMessageAuthentication:
+				stream.MarkResponse()
+			default:
+				return len(data), exchanges, nil
+			}
+			isRequest = data[offset] == 0
+		}
+		if !isRequest && d.encryptionRequested {
+			// the server accepted('S', 'G') or rejected('N') the encryption, the encrypted data is captured by the TLS probes
+			d.encryptionRequested = false
+			offset++
+			continue
+		}
+
+		untyped := isRequest && data[offset] == 0
+		length, minLength := 0, 4
+		if untyped {
+			if len(data)-offset < 4 {
+				break
+			}
+			length = int(binary.BigEndian.Uint32(data[offset:]))
+		} else {
+			if len(data)-offset < 5 {
+				break
+			}
+			if isRequest && !postgresFrontendMessages[data[offset]] {
+				return offset, exchanges, errPostgresInvalidData
+			}
+			// the length field of the typed message includes itself, so the message contains at least the type and the length
+			length, minLength = 1+int(binary.BigEndian.Uint32(data[offset+1:])), 5
+		}
+		if length < minLength || length > postgresMaxMessageLength {
+			return offset, exchanges, errPostgresInvalidData
+		}
+		if !stream.Ready(offset, length) {
+			break
+		}
+		message := data[offset:min(offset+length, len(data))]
+		switch {
+		case untyped:
+			d.handleStartup(message)
+		case isRequest:
+			d.handleFrontend(stream, message, offset, length)
+		default:
+			exchanges = d.handleBackend(stream, exchanges, message, offset, length)
+		}
+		offset += length
+	}
+	return offset, exchanges, nil
+}
+
+func (d *postgresDecoder) handleStartup(message []byte) {
+	if len(message) < 8 {
+		return
+	}
+	switch binary.BigEndian.Uint32(message[4:]) {
+	case postgresSSLRequest, postgresGSSENCRequest:
+		d.encryptionRequested = true
+	case postgresProtocolVersion3, postgresCancelRequest:
+		d.encryptionRequested = false
+	}
+}
+
+func (d *postgresDecoder) handleFrontend(stream *RPCStream, message []byte, offset, length int) {
+	if d.batchStartTime == 0 {
+		d.batchStartTime = stream.StartTime(offset)
+	}
+	d.batchBytes += length
+	body := message[5:]
+	switch message[0] {
+	case postgresMessageQuery:
+		d.appendQuery(&postgresQuery{queryType: postgresQuerySimple, statement: newSQLStatement(postgresString(body), false)})
+	case postgresMessageParse:
+		name := postgresString(body)
+		if len(d.statements) < postgresMaxStatements || d.statements[name] != nil {
+			d.statements[name] = newSQLStatement(postgresString(body[min(len(name)+1, len(body)):]), false)
+		}
+	case postgresMessageBind:
+		portal := postgresString(body)
+		if len(d.portals) < postgresMaxStatements || d.portals[portal] != nil {
+			d.portals[portal] = d.statements[postgresString(body[min(len(portal)+1, len(body)):])]
+		}
+	case postgresMessageExecute:
+		d.appendQuery(&postgresQuery{queryType: postgresQueryExecute, statement: d.portals[postgresString(body)]})
+	case postgresMessageSync:
+		d.appendQuery(&postgresQuery{queryType: postgresQuerySync})
+	case postgresMessageClose:
+		if len(body) > 0 {
+			if body[0] == postgresCloseStatement {
+				delete(d.statements, postgresString(body[1:]))
+			} else {
+				delete(d.portals, postgresString(body[1:]))
+			}
+		}
+	case postgresMessageTerminate:
+		d.batchStartTime, d.batchBytes = 0, 0
+	}
+}
+
+// appendQuery add the query which waiting for the response, the previous messages in the batch are counted as the request
+func (d *postgresDecoder) appendQuery(query *postgresQuery) {
+	query.startTime, query.requestBytes = d.batchStartTime, d.batchBytes
+	d.batchStartTime, d.batchBytes = 0, 0
+	if len(d.pending) >= postgresMaxPendingQueries {
+		// the responses are lost, clean the queries to avoid the memory leak
+		d.pending = nil
+	}
+	d.pending = append(d.pending, query)
+}
+
+func (d *postgresDecoder) handleBackend(stream *RPCStream, exchanges []*RPCExchange, message []byte,
+	offset, length int) []*RPCExchange {
+	if len(d.pending) == 0 {
+		// the authentication, parameter status and notification messages
+		return exchanges
+	}
+	current := d.pending[0]
+	current.responseBytes += length
+	current.endTime = stream.EndTime(offset + length - 1)
+	body := message[5:]
+	switch message[0] {
+	case postgresMessageCommandComplete, postgresMessageEmptyQuery, postgresMessagePortalSuspended:
+		switch message[0] {
+		case postgresMessageCommandComplete:
+			current.tag = postgresCommandTag(postgresString(body))
+		case postgresMessageEmptyQuery:
+			current.tag = postgresCommandTagEmptyQuery
+		default:
+			current.tag = postgresCommandTagPortalSuspend
+		}
+		if current.status == "" {
+			current.status = postgresStatusSuccess
+		}
+		if current.queryType == postgresQueryExecute {
+			d.pending = d.pending[1:]
+			exchanges = append(exchanges, current.exchange())
+		}
+	case postgresMessageErrorResponse:
+		current.status = postgresErrorCode(body)
+		if current.queryType == postgresQueryExecute {
+			// the server discard the following messages until the sync after the error
+			d.pending = d.pending[1:]
+			exchanges = append(exchanges, current.exchange())
+			for len(d.pending) > 0 && d.pending[0].queryType == postgresQueryExecute {
+				d.pending = d.pending[1:]
+			}
+		}
+	case postgresMessageReadyForQuery:
+		for len(d.pending) > 0 {
+			query := d.pending[0]
+			d.pending = d.pending[1:]
+			if query.queryType == postgresQuerySimple {
+				exchanges = append(exchanges, query.exchange())
+			}
+			if query.queryType != postgresQueryExecute {
+				break
+			}
+		}
+	}
+	return exchanges
+}
+
+func (q *postgresQuery) exchange() *RPCExchange {
+	var operation, resource string
+	if q.statement != nil {
+		operation, resource = q.statement.command, q.statement.digest
+	}
+	if q.tag != "" && q.tag != postgresCommandTagEmptyQuery && q.tag != postgresCommandTagPortalSuspend {
+		operation = q.tag
+	}
+	return &RPCExchange{
+		Operation:     operation,
+		Resource:      resource,
+		Status:        q.status,
+		StartTime:     q.startTime,
+		EndTime:       q.endTime,
+		RequestBytes:  uint64(q.requestBytes),
+		ResponseBytes: uint64(q.responseBytes),
+	}
+}
+
+// postgresString read the null-terminated string, the rest data is returned when the terminator is not found
+func postgresString(data []byte) string {
+	if end := bytes.IndexByte(data, 0); end >= 0 {
+		return string(data[:end])
+	}
+	return string(data)
+}
+
+// postgresCommandTag remove the row count of the command tag, such as "INSERT 0 1" to "INSERT"
+func postgresCommandTag(tag string) string {
+	fields := strings.Fields(tag)
+	for len(fields) > 1 && strings.Trim(fields[len(fields)-1], "0123456789") == "" {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, " ")
+}
+
+// postgresErrorCode read the SQLSTATE code from the fields of the error response
+func postgresErrorCode(body []byte) string {
+	for len(body) > 0 && body[0] != 0 {
+		value := postgresString(body[1:])
+		if body[0] == postgresErrorFieldSQLState {
+			return value
+		}
+		body = body[min(len(value)+2, len(body)):]
+	}
+	return ""
+}
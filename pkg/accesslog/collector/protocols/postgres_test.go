@@ -0,0 +1,168 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func postgresTestMessage(messageType byte, fields ...string) []byte {
+	var body []byte
+	for _, field := range fields {
+		body = append(body, field...)
+	}
+	message := []byte{messageType}
+	message = binary.BigEndian.AppendUint32(message, uint32(len(body)+4))
+	return append(message, body...)
+}
+
+func postgresTestMessages(messages ...[]byte) []byte {
+	var result []byte
+	for _, message := range messages {
+		result = append(result, message...)
+	}
+	return result
+}
+
+func TestPostgresCommandTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{tag: "SELECT 5", expected: "SELECT"},
+		{tag: "INSERT 0 1", expected: "INSERT"},
+		{tag: "CREATE TABLE", expected: "CREATE TABLE"},
+		{tag: "BEGIN", expected: "BEGIN"},
+	}
+	for _, tt := range tests {
+		if actual := postgresCommandTag(tt.tag); actual != tt.expected {
+			t.Errorf("tag %q expected: %q, actual: %q", tt.tag, tt.expected, actual)
+		}
+	}
+}
+
+func TestPostgresDecoder(t *testing.T) {
+	metrics := &RPCMetrics{}
+	client := &RPCStream{direction: enums.SocketDataDirectionEgress, connection: metrics}
+	server := &RPCStream{direction: enums.SocketDataDirectionIngress, connection: metrics}
+	decoder := &postgresDecoder{statements: make(map[string]*sqlStatement), portals: make(map[string]*sqlStatement)}
+	decode := func(stream *RPCStream, data []byte, startTime uint64) []*RPCExchange {
+		stream.data = append(stream.data[:0], data...)
+		stream.segments = []rpcStreamSegment{{end: len(data), startTime: startTime, endTime: startTime + 1}}
+		consumed, exchanges, err := decoder.Decode(stream)
+		if err != nil || consumed != len(data) {
+			t.Fatalf("decode failure, consumed: %d, error: %v", consumed, err)
+		}
+		return exchanges
+	}
+	verify := func(exchanges []*RPCExchange, expected ...RPCExchange) {
+		if len(exchanges) != len(expected) {
+			t.Fatalf("expected exchanges count: %d, actual: %d", len(expected), len(exchanges))
+		}
+		for i := range expected {
+			if *exchanges[i] != expected[i] {
+				t.Errorf("expected exchange: %+v, actual: %+v", expected[i], *exchanges[i])
+			}
+		}
+	}
+	ready := postgresTestMessage('Z', "I")
+
+	// the SSL request is rejected, then the startup message and the authentication
+	decode(client, binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, 8), postgresSSLRequest), 1)
+	if isRequest, detected := client.IsRequest(); !detected || !isRequest {
+		t.Fatalf("the client stream should be detected as the request")
+	}
+	decode(server, []byte{'N'}, 2)
+	startup := binary.BigEndian.AppendUint32(nil, postgresProtocolVersion3)
+	startup = append(startup, "user\x00app\x00\x00"...)
+	decode(client, append(binary.BigEndian.AppendUint32(nil, uint32(len(startup)+4)), startup...), 3)
+	decode(server, postgresTestMessages(postgresTestMessage('R', "\x00\x00\x00\x00"),
+		postgresTestMessage('S', "server_version\x0016\x00"), ready), 4)
+
+	// simple query with the result set
+	query := postgresTestMessage('Q', "SELECT name FROM users WHERE id = 10\x00")
+	if exchanges := decode(client, query, 10); len(exchanges) != 0 {
+		t.Fatalf("the query should wait for the response")
+	}
+	response := postgresTestMessages(postgresTestMessage('T', "\x00\x01name\x00"), postgresTestMessage('D', "\x00\x01\x00\x00\x00\x03bob"),
+		postgresTestMessage('C', "SELECT 1\x00"), ready)
+	verify(decode(server, response, 20), RPCExchange{Operation: "SELECT", Resource: "SELECT name FROM users WHERE id = ?",
+		Status: "00000", StartTime: 10, EndTime: 21, RequestBytes: uint64(len(query)), ResponseBytes: uint64(len(response))})
+
+	// extended query protocol with two pipelined executions, the second one is failed
+	parse := postgresTestMessage('P', "stmt1\x00", "INSERT INTO orders(id, note) VALUES ($1, 'new')\x00", "\x00\x00")
+	bind := postgresTestMessage('B', "\x00", "stmt1\x00", "\x00\x00\x00\x00\x00\x00")
+	execute := postgresTestMessage('E', "\x00", "\x00\x00\x00\x00")
+	sync := postgresTestMessage('S')
+	request := postgresTestMessages(parse, bind, execute, bind, execute, sync)
+	decode(client, request, 30)
+	errorResponse := postgresTestMessage('E', "SERROR\x00", "C23505\x00", "Mduplicate key\x00", "\x00")
+	verify(decode(server, postgresTestMessages(postgresTestMessage('1'), postgresTestMessage('2'),
+		postgresTestMessage('C', "INSERT 0 1\x00"), postgresTestMessage('2'), errorResponse, ready), 40),
+		RPCExchange{Operation: "INSERT", Resource: "INSERT INTO orders(id, note) VALUES ($1, ?)", Status: "00000",
+			StartTime: 30, EndTime: 41, RequestBytes: uint64(len(parse) + len(bind) + len(execute)), ResponseBytes: 5 + 5 + 16},
+		RPCExchange{Operation: "INSERT", Resource: "INSERT INTO orders(id, note) VALUES ($1, ?)", Status: "23505",
+			StartTime: 30, EndTime: 41, RequestBytes: uint64(len(bind) + len(execute)), ResponseBytes: uint64(5 + len(errorResponse))})
+	if len(decoder.pending) != 0 {
+		t.Fatalf("the pending queries should be cleaned after the ready for query")
+	}
+}
+
+func TestPostgresDecoderInvalidLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction enums.SocketDataDirection
+		data      []byte
+		invalid   bool
+	}{
+		{name: "typed request with the length field 3", direction: enums.SocketDataDirectionEgress,
+			data: []byte{0x42, 0x00, 0x00, 0x00, 0x03}, invalid: true},
+		{name: "typed response with the length field 3", direction: enums.SocketDataDirectionIngress,
+			data: []byte{0x43, 0x00, 0x00, 0x00, 0x03}, invalid: true},
+		{name: "untyped request with the length 3", direction: enums.SocketDataDirectionEgress,
+			data: []byte{0x00, 0x00, 0x00, 0x03}, invalid: true},
+		{name: "typed request exceed the max length", direction: enums.SocketDataDirectionEgress,
+			data: []byte{0x51, 0x7f, 0xff, 0xff, 0xff}, invalid: true},
+		{name: "typed request without the body", direction: enums.SocketDataDirectionEgress,
+			data: []byte{0x53, 0x00, 0x00, 0x00, 0x04}},
+		{name: "typed response without the body", direction: enums.SocketDataDirectionIngress,
+			data: []byte{0x43, 0x00, 0x00, 0x00, 0x04}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &RPCMetrics{requestDirection: enums.SocketDataDirectionEgress}
+			stream := &RPCStream{direction: tt.direction, connection: metrics, data: tt.data,
+				segments: []rpcStreamSegment{{end: len(tt.data), startTime: 1, endTime: 2}}}
+			decoder := &postgresDecoder{statements: make(map[string]*sqlStatement), portals: make(map[string]*sqlStatement),
+				pending: []*postgresQuery{{queryType: postgresQuerySimple}}}
+			consumed, _, err := decoder.Decode(stream)
+			if tt.invalid {
+				if err != errPostgresInvalidData {
+					t.Errorf("expected the invalid data error, actual: %v", err)
+				}
+				return
+			}
+			if err != nil || consumed != len(tt.data) {
+				t.Errorf("expected consumed all data, actual consumed: %d, error: %v", consumed, err)
+			}
+		})
+	}
+}
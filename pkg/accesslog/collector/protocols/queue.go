@@ -36,6 +36,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/tools/btf"
 	"github.com/apache/skywalking-rover/pkg/tools/buffer"
 	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
 
 	"github.com/docker/go-units"
 
@@ -80,15 +81,22 @@ func NewAnalyzeQueue(ctx *common.AccessLogContext) (*AnalyzeQueue, error) {
 			return &events.SocketDetailEvent{}
 		},
 		supportAnalyzers: func(ctx *common.AccessLogContext) []Protocol {
-			return []Protocol{
+			return append([]Protocol{
 				NewHTTP1Analyzer(ctx, nil),
 				NewHTTP2Analyzer(ctx, nil),
-			}
+				NewTLSAnalyzer(ctx),
+			}, registeredProtocols(ctx)...)
 		},
 	}, nil
 }
 
 func (q *AnalyzeQueue) Start(ctx context.Context) {
+	for port, protocol := range registeredProtocolPorts() {
+		if err := q.context.BPF.ProtocolPortMap.Update(port, uint8(protocol), ebpf.UpdateAny); err != nil {
+			log.Warnf("failed to register the %s port %d of %s protocol: %v", port.Transport, port.Port,
+				enums.ConnectionProtocolString(protocol), err)
+		}
+	}
 	q.eventQueue = btf.NewEventQueue("socket data analyzer",
 		q.context.Config.ProtocolAnalyze.AnalyzeParallels, q.context.Config.ProtocolAnalyze.QueueSize,
 		func(num int) btf.PartitionContext {
@@ -108,6 +116,9 @@ func (q *AnalyzeQueue) Start(ctx context.Context) {
 		})
 
 	q.eventQueue.Start(ctx, q.context.BPF.Linker)
+	if q.context.Watchdog != nil {
+		q.context.Watchdog.Register(q.eventQueue.WatchdogComponents()...)
+	}
 }
 
 func (q *AnalyzeQueue) ChangeDetailSupplier(supplier func() events.SocketDetail) {
@@ -137,6 +148,7 @@ func newPartitionConnection(protocolMgr *ProtocolManager, conID, randomID uint64
 		protocolAnalyzer:   make(map[enums.ConnectionProtocol]Protocol),
 		protocolMetrics:    make(map[enums.ConnectionProtocol]ProtocolMetrics),
 		lastCheckCloseTime: time.Now(),
+		protocolMgr:        protocolMgr,
 	}
 	connection.appendProtocolIfNeed(protocolMgr, conID, randomID, protocol, currentDataID)
 	return connection
@@ -206,8 +218,19 @@ func (p *PartitionContext) Consume(data interface{}) {
 			"function name: %s, package count: %d, package size: %d, ssl: %d, protocol: %d",
 			event.GetConnectionID(), event.GetRandomID(), pid, event.DataID(), event.GetFunctionName(),
 			event.GetL4PackageCount(), event.GetL4TotalPackageSize(), event.GetSSL(), event.GetProtocol())
+		if pressure := event.GetBufferPressure(); pressure != 0 {
+			log.Debugf("detect the socket buffer pressure, connection ID: %d, random ID: %d, pid: %d, flags: %s, cause: %s",
+				event.GetConnectionID(), event.GetRandomID(), pid, pressure, pressure.Cause())
+			p.context.BufferPressure.Record(pid, event)
+		}
+		if p.context.ShedLevel() >= selfprotect.LevelL4Only {
+			// the protocol analysis is disabled by the self-protection, only keep the kernel log
+			forwarder.SendTransferNoProtocolEvent(p.context, event)
+			return
+		}
 		if event.GetProtocol() == enums.ConnectionProtocolUnknown {
 			// if the connection protocol is unknown, we just needs to add this into the kernel log
+			p.context.ParseStats.Increase(enums.ConnectionProtocolUnknown, common.ProtocolParseIssueUnknownProtocol)
 			forwarder.SendTransferNoProtocolEvent(p.context, event)
 			return
 		}
@@ -219,6 +242,13 @@ func (p *PartitionContext) Consume(data interface{}) {
 			"data id: %d, sequence: %d, finished: %t, protocol: %d, size: %d",
 			event.ConnectionID, event.RandomID, pid, event.PrevDataID0, event.DataID0, event.Sequence0, event.IsFinished(),
 			event.Protocol0, event.BufferLen())
+		if p.context.ShedLevel() >= selfprotect.LevelL4Only {
+			buffer.PooledBuffer.Put(event.ReleaseBuffer())
+			return
+		}
+		if event.IsFinished() && event.HaveReduceDataAfterChunk() {
+			p.context.ParseStats.Increase(event.Protocol0, common.ProtocolParseIssueTruncated)
+		}
 		connection := p.GetConnectionContext(event.ConnectionID, event.RandomID, event.Protocol0, event.DataID0)
 		connection.AppendData(event)
 	}
@@ -338,8 +368,13 @@ func (p *PartitionContext) processConnectionEvents(connection *PartitionConnecti
 		return connection.protocol[sortedProtocols[i]] < connection.protocol[sortedProtocols[j]]
 	})
 	for _, protocol := range sortedProtocols {
+		alreadyBreak := helper.ProtocolBreak
 		if err := connection.protocolAnalyzer[protocol].Analyze(connection, helper); err != nil {
 			log.Warnf("failed to analyze the %s protocol data: %v", enums.ConnectionProtocolString(protocol), err)
+			p.context.ParseStats.Increase(protocol, common.ProtocolParseIssueError)
+		}
+		if helper.ProtocolBreak && !alreadyBreak {
+			p.context.ParseStats.Increase(protocol, common.ProtocolParseIssueProtocolBreak)
 		}
 	}
 
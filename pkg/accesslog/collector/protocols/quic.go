@@ -0,0 +1,202 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+)
+
+const (
+	quicProtocolName = "quic"
+	// the max size of the reassembled CRYPTO stream, the hello message bigger than it is ignored
+	quicMaxCryptoSize = 64 * 1024
+	// the max count of the pending CRYPTO frames which not continuous
+	quicMaxPendingFrames = 32
+)
+
+var quicPorts = []uint16{443}
+
+var errQUICNotDetected = errors.New("the first datagram is not the QUIC initial packet")
+
+func init() {
+	if _, err := RegisterProtocolAnalyzer(&quicAnalyzer{}); err != nil {
+		panic(err)
+	}
+}
+
+// quicAnalyzer decode the Initial packets of the QUIC(HTTP/3) over the UDP socket,
+// the SNI and ALPN in the handshake are reported as the TLS handshake, and the connection metrics are sent by the kernel logs
+type quicAnalyzer struct {
+}
+
+func (q *quicAnalyzer) Name() string {
+	return quicProtocolName
+}
+
+func (q *quicAnalyzer) Ports() []uint16 {
+	return quicPorts
+}
+
+func (q *quicAnalyzer) NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol {
+	return NewRPCProtocol(ctx, protocol, func() RPCDecoder {
+		return &quicDecoder{handshakes: ctx.TLSHandshakes}
+	})
+}
+
+type quicDecoder struct {
+	// the queue for receiving the handshakes, nil means the handshake metadata is not collected
+	handshakes *common.FlushQueue[*common.TLSHandshake]
+	finished   bool
+
+	// the destination connection ID of the first client Initial packet, all the Initial keys are derived from it
+	initialDCID     []byte
+	clientDirection enums.SocketDataDirection
+	clientCrypto    quicCryptoStream
+	serverCrypto    quicCryptoStream
+	clientHello     *ssl.TLSHello
+}
+
+// quicCryptoStream reassemble the CRYPTO frames from the offset zero
+type quicCryptoStream struct {
+	data    []byte
+	pending []*ssl.QUICCryptoFrame
+}
+
+// Decode each datagram of the stream until the handshake is finished, the datagrams after that are only consumed
+func (q *quicDecoder) Decode(stream *RPCStream) (int, []*RPCExchange, error) {
+	data := stream.Data()
+	offset := 0
+	for offset < len(data) {
+		end := stream.SegmentEnd(offset)
+		if !q.finished {
+			if err := q.handleDatagram(stream, data[offset:end], offset); err != nil {
+				return offset, nil, err
+			}
+		}
+		offset = end
+	}
+	return offset, nil, nil
+}
+
+func (q *quicDecoder) handleDatagram(stream *RPCStream, datagram []byte, offset int) error {
+	if !ssl.IsQUICLongHeader(datagram) {
+		if q.initialDCID == nil {
+			return errQUICNotDetected
+		}
+		// the short header packet is sent after the handshake finished
+		q.finish(stream, offset, nil)
+		return nil
+	}
+	client := q.initialDCID == nil || stream.Direction() == q.clientDirection
+	initial, err := ssl.ParseQUICInitial(datagram, q.initialDCID, client)
+	if err != nil {
+		if q.initialDCID == nil {
+			return errQUICNotDetected
+		}
+		// the Handshake or 0-RTT packets, or the Initial packets after the retry
+		return nil
+	}
+	if q.initialDCID == nil {
+		q.initialDCID = append([]byte(nil), initial.DCID...)
+		q.clientDirection = stream.Direction()
+		stream.MarkRequest()
+	}
+
+	if client {
+		message := q.clientCrypto.append(initial.CryptoFrames)
+		if message != nil && q.clientHello == nil {
+			if hello, err := ssl.ParseTLSHello(message); err == nil && hello.Type == ssl.TLSHandshakeClientHello {
+				q.clientHello = hello
+			}
+		}
+		return nil
+	}
+	if message := q.serverCrypto.append(initial.CryptoFrames); message != nil {
+		if hello, err := ssl.ParseTLSHello(message); err == nil && hello.Type == ssl.TLSHandshakeServerHello {
+			q.finish(stream, offset, hello)
+		}
+	}
+	return nil
+}
+
+// finish the handshake, the server hello could be nil when it's not captured
+func (q *quicDecoder) finish(stream *RPCStream, offset int, serverHello *ssl.TLSHello) {
+	q.finished = true
+	q.clientCrypto, q.serverCrypto = quicCryptoStream{}, quicCryptoStream{}
+	if q.handshakes == nil || q.clientHello == nil {
+		return
+	}
+	handshake := &common.TLSHandshake{
+		ConnectionID: stream.connection.ConnectionID,
+		RandomID:     stream.connection.RandomID,
+		ServerName:   q.clientHello.ServerName,
+		ALPNOffered:  q.clientHello.ALPN,
+		Time:         stream.StartTime(offset),
+		QUIC:         true,
+		CreateTime:   time.Now(),
+	}
+	if serverHello != nil {
+		handshake.Version, handshake.CipherSuite = serverHello.Version, serverHello.CipherSuite
+	}
+	// the ALPN is selected in the encrypted extensions, so only the single offered protocol could be confirmed
+	if len(q.clientHello.ALPN) == 1 {
+		handshake.ALPN = q.clientHello.ALPN[0]
+	}
+	q.handshakes.Append(handshake)
+}
+
+// append the frames and return the first handshake message when it's fully received
+func (c *quicCryptoStream) append(frames []*ssl.QUICCryptoFrame) []byte {
+	c.pending = append(c.pending, frames...)
+	for merged := true; merged; {
+		merged = false
+		rest := c.pending[:0]
+		for _, frame := range c.pending {
+			end := frame.Offset + uint64(len(frame.Data))
+			switch {
+			case end <= uint64(len(c.data)):
+				// the retransmitted data
+			case frame.Offset <= uint64(len(c.data)) && end <= quicMaxCryptoSize:
+				c.data = append(c.data, frame.Data[uint64(len(c.data))-frame.Offset:]...)
+				merged = true
+			default:
+				rest = append(rest, frame)
+			}
+		}
+		c.pending = rest
+	}
+	if len(c.pending) > quicMaxPendingFrames {
+		c.pending = c.pending[:0]
+	}
+
+	// type(1) + length(3)
+	if len(c.data) < 4 {
+		return nil
+	}
+	length := 4 + int(binary.BigEndian.Uint32(c.data)&0xffffff)
+	if len(c.data) < length {
+		return nil
+	}
+	return c.data[:length]
+}
@@ -0,0 +1,281 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+const (
+	redisProtocolName = "redis"
+	// the max count of the commands which waiting for the reply in one connection
+	redisMaxPendingCommands = 1024
+	// the max length of the line(type and length) in RESP, longer line is treated as not the Redis protocol
+	redisMaxLineLength = 64 * 1024
+	// the max nested depth of the aggregate types
+	redisMaxDepth = 16
+	// the delimiter of the key prefix
+	redisKeyDelimiter = ":"
+)
+
+var redisPorts = []uint16{6379}
+
+// the commands which the first argument is not the key
+var redisKeylessCommands = map[string]bool{
+	"AUTH": true, "HELLO": true, "SELECT": true, "PING": true, "ECHO": true, "INFO": true, "CONFIG": true,
+	"CLIENT": true, "CLUSTER": true, "COMMAND": true, "EVAL": true, "EVALSHA": true, "EVAL_RO": true,
+	"EVALSHA_RO": true, "FCALL": true, "FCALL_RO": true, "SCRIPT": true, "FUNCTION": true, "SUBSCRIBE": true,
+	"PSUBSCRIBE": true, "SSUBSCRIBE": true, "UNSUBSCRIBE": true, "PUNSUBSCRIBE": true, "SUNSUBSCRIBE": true,
+	"PUBLISH": true, "SPUBLISH": true, "PUBSUB": true, "SCAN": true, "MULTI": true, "EXEC": true, "DISCARD": true,
+	"DBSIZE": true, "FLUSHDB": true, "FLUSHALL": true, "SAVE": true, "BGSAVE": true, "TIME": true, "SLOWLOG": true,
+	"MEMORY": true, "OBJECT": true, "XREAD": true, "XREADGROUP": true, "QUIT": true, "RESET": true, "READONLY": true,
+	"READWRITE": true, "WAIT": true, "LATENCY": true, "ACL": true, "MONITOR": true, "ROLE": true, "REPLICAOF": true,
+}
+
+var errRedisInvalidData = errors.New("invalid Redis data")
+
+func init() {
+	if _, err := RegisterProtocolAnalyzer(&redisAnalyzer{}); err != nil {
+		panic(err)
+	}
+}
+
+// redisAnalyzer decode the RESP2 and RESP3 protocol of Redis, the key is reported as the prefix for limiting the cardinality
+type redisAnalyzer struct {
+}
+
+func (r *redisAnalyzer) Name() string {
+	return redisProtocolName
+}
+
+func (r *redisAnalyzer) Ports() []uint16 {
+	return redisPorts
+}
+
+func (r *redisAnalyzer) NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol {
+	return NewRPCProtocol(ctx, protocol, func() RPCDecoder {
+		return &redisDecoder{}
+	})
+}
+
+type redisCommand struct {
+	command   string
+	keyPrefix string
+
+	size      int
+	startTime uint64
+}
+
+type redisDecoder struct {
+	// the commands are replied in order, even the commands are pipelined
+	pending []*redisCommand
+}
+
+func (d *redisDecoder) Decode(stream *RPCStream) (int, []*RPCExchange, error) {
+	data := stream.Data()
+	offset := 0
+	var exchanges []*RPCExchange
+	for offset < len(data) {
+		isRequest, detected := stream.IsRequest()
+		var length int
+		var err error
+		if (!detected || isRequest) && redisIsInlineCommand(data[offset]) {
+			length = bytes.IndexByte(data[offset:], '\n') + 1
+			if length == 0 {
+				length = -1
+			}
+		} else if length, err = redisValueLength(data[offset:], 0); err != nil {
+			return offset, exchanges, err
+		}
+		if length < 0 {
+			if stream.Lost() == 0 {
+				break
+			}
+			// the value is truncated, the rest data are treated as the value
+			length = len(data) - offset + stream.Lost()
+		}
+		if !stream.Ready(offset, length) {
+			break
+		}
+		message := data[offset:min(offset+length, len(data))]
+		if !detected || isRequest {
+			if err := d.handleCommand(stream, message, offset, length); err != nil {
+				return offset, exchanges, err
+			}
+		} else if exchange := d.handleReply(stream, message, offset, length); exchange != nil {
+			exchanges = append(exchanges, exchange)
+		}
+		offset += length
+	}
+	return offset, exchanges, nil
+}
+
+func (d *redisDecoder) handleCommand(stream *RPCStream, message []byte, offset, length int) error {
+	args := redisCommandArgs(message, 2)
+	if len(args) == 0 {
+		return fmt.Errorf("the Redis command is not found")
+	}
+	if _, detected := stream.IsRequest(); !detected {
+		// the client always send the command first
+		stream.MarkRequest()
+	}
+	command := &redisCommand{command: strings.ToUpper(args[0]), size: length, startTime: stream.StartTime(offset)}
+	if len(args) > 1 && !redisKeylessCommands[command.command] {
+		command.keyPrefix = redisKeyPrefix(args[1])
+	}
+	if len(d.pending) >= redisMaxPendingCommands {
+		// the replies are lost, clean the commands to avoid the memory leak
+		d.pending = nil
+	}
+	d.pending = append(d.pending, command)
+	return nil
+}
+
+func (d *redisDecoder) handleReply(stream *RPCStream, message []byte, offset, length int) *RPCExchange {
+	// the attribute and push data are not the reply of the command
+	if len(d.pending) == 0 || message[0] == '|' || message[0] == '>' {
+		return nil
+	}
+	command := d.pending[0]
+	d.pending = d.pending[1:]
+	status := "OK"
+	if message[0] == '-' || message[0] == '!' {
+		status = redisErrorPrefix(message)
+	}
+	return &RPCExchange{
+		Operation:     command.command,
+		Resource:      command.keyPrefix,
+		Status:        status,
+		StartTime:     command.startTime,
+		EndTime:       stream.EndTime(offset + length - 1),
+		RequestBytes:  uint64(command.size),
+		ResponseBytes: uint64(length),
+	}
+}
+
+func redisIsInlineCommand(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// redisValueLength returns the length of the RESP value at the start of data, -1 if the data is not complete.
+// The length could be bigger than the data when the last bulk string is not fully received
+func redisValueLength(data []byte, depth int) (int, error) {
+	if depth > redisMaxDepth {
+		return 0, errRedisInvalidData
+	}
+	lineEnd := bytes.Index(data, []byte("\r\n"))
+	if lineEnd < 0 {
+		if len(data) > redisMaxLineLength {
+			return 0, errRedisInvalidData
+		}
+		return -1, nil
+	}
+	headerLength := lineEnd + 2
+	switch data[0] {
+	case '+', '-', ':', '_', '#', ',', '(':
+		return headerLength, nil
+	case '$', '!', '=':
+		size, err := strconv.Atoi(string(data[1:lineEnd]))
+		if err != nil || size < -1 {
+			return 0, errRedisInvalidData
+		}
+		if size < 0 {
+			return headerLength, nil
+		}
+		return headerLength + size + 2, nil
+	case '*', '~', '>', '%', '|':
+		count, err := strconv.Atoi(string(data[1:lineEnd]))
+		if err != nil || count < -1 {
+			return 0, errRedisInvalidData
+		}
+		if data[0] == '%' || data[0] == '|' {
+			count *= 2
+		}
+		offset := headerLength
+		for i := 0; i < count; i++ {
+			if offset >= len(data) {
+				return -1, nil
+			}
+			length, err := redisValueLength(data[offset:], depth+1)
+			if err != nil || length < 0 {
+				return length, err
+			}
+			offset += length
+			if offset > len(data) && i < count-1 {
+				return -1, nil
+			}
+		}
+		return offset, nil
+	}
+	return 0, errRedisInvalidData
+}
+
+// redisCommandArgs read the first arguments of the command in the RESP array or the inline command
+func redisCommandArgs(message []byte, limit int) []string {
+	if message[0] != '*' {
+		fields := strings.Fields(string(message))
+		return fields[:min(limit, len(fields))]
+	}
+	args := make([]string, 0, limit)
+	lineEnd := bytes.Index(message, []byte("\r\n"))
+	offset := lineEnd + 2
+	for len(args) < limit && offset < len(message) && message[offset] == '$' {
+		end := bytes.Index(message[offset:], []byte("\r\n"))
+		if end < 0 {
+			break
+		}
+		size, err := strconv.Atoi(string(message[offset+1 : offset+end]))
+		start := offset + end + 2
+		if err != nil || size < 0 || start+size > len(message) {
+			break
+		}
+		args = append(args, string(message[start:start+size]))
+		offset = start + size + 2
+	}
+	return args
+}
+
+// redisKeyPrefix is the first segment of the key split by the delimiter, the key without delimiter is not reported
+func redisKeyPrefix(key string) string {
+	if index := strings.Index(key, redisKeyDelimiter); index > 0 {
+		return key[:index]
+	}
+	return ""
+}
+
+// redisErrorPrefix is the first word of the error, such as "ERR", "WRONGTYPE"
+func redisErrorPrefix(message []byte) string {
+	content := message[1:]
+	if message[0] == '!' {
+		// the blob error contains the length line
+		if index := bytes.Index(content, []byte("\r\n")); index >= 0 {
+			content = content[index+2:]
+		}
+	}
+	if index := bytes.IndexAny(content, " \r\n"); index >= 0 {
+		content = content[:index]
+	}
+	return string(content)
+}
@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func TestRedisValueLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		length   int
+		hasError bool
+	}{
+		{name: "simple string", data: "+OK\r\n+PONG\r\n", length: 5},
+		{name: "error", data: "-ERR unknown command\r\n", length: 22},
+		{name: "bulk string", data: "$5\r\nhello\r\n", length: 11},
+		{name: "null bulk string", data: "$-1\r\n", length: 5},
+		{name: "bulk string not fully received", data: "$10\r\nhel", length: 17},
+		{name: "array", data: "*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n", length: 22},
+		{name: "nested array", data: "*2\r\n*1\r\n:1\r\n#t\r\n", length: 16},
+		{name: "array not complete", data: "*2\r\n$3\r\nGET\r\n", length: -1},
+		{name: "map", data: "%1\r\n+key\r\n,1.5\r\n", length: 16},
+		{name: "line not complete", data: "+OK", length: -1},
+		{name: "invalid type", data: "?1\r\n", hasError: true},
+		{name: "invalid length", data: "$abc\r\n", hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, err := redisValueLength([]byte(tt.data), 0)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("expected error: %t, actual: %v", tt.hasError, err)
+			}
+			if err == nil && length != tt.length {
+				t.Errorf("expected length: %d, actual: %d", tt.length, length)
+			}
+		})
+	}
+}
+
+func TestRedisDecoder(t *testing.T) {
+	metrics := &RPCMetrics{}
+	client := &RPCStream{direction: enums.SocketDataDirectionEgress, connection: metrics}
+	server := &RPCStream{direction: enums.SocketDataDirectionIngress, connection: metrics}
+	decoder := &redisDecoder{}
+	decode := func(stream *RPCStream, data string, startTime uint64) []*RPCExchange {
+		stream.data = append(stream.data[:0], data...)
+		stream.segments = []rpcStreamSegment{{end: len(data), startTime: startTime, endTime: startTime + 1}}
+		consumed, exchanges, err := decoder.Decode(stream)
+		if err != nil || consumed != len(data) {
+			t.Fatalf("decode failure, consumed: %d, error: %v", consumed, err)
+		}
+		return exchanges
+	}
+
+	// pipelined commands
+	decode(client, "*3\r\n$3\r\nset\r\n$11\r\nuser:1:name\r\n$3\r\nbob\r\n"+"PING\r\n"+"*2\r\n$4\r\nINCR\r\n$7\r\ncounter\r\n", 10)
+	exchanges := decode(server, "+OK\r\n"+"+PONG\r\n"+"-WRONGTYPE Operation against a key\r\n", 20)
+	expected := []RPCExchange{
+		{Operation: "SET", Resource: "user", Status: "OK", StartTime: 10, EndTime: 21, RequestBytes: 40, ResponseBytes: 5},
+		{Operation: "PING", Status: "OK", StartTime: 10, EndTime: 21, RequestBytes: 6, ResponseBytes: 7},
+		{Operation: "INCR", Status: "WRONGTYPE", StartTime: 10, EndTime: 21, RequestBytes: 27, ResponseBytes: 36},
+	}
+	if len(exchanges) != len(expected) {
+		t.Fatalf("expected exchanges count: %d, actual: %d", len(expected), len(exchanges))
+	}
+	for i := range expected {
+		if *exchanges[i] != expected[i] {
+			t.Errorf("expected exchange: %+v, actual: %+v", expected[i], *exchanges[i])
+		}
+	}
+
+	// the push data in RESP3 is not the reply
+	decode(client, "*2\r\n$3\r\nGET\r\n$9\r\norder:100\r\n", 30)
+	if exchanges = decode(server, ">2\r\n$7\r\nmessage\r\n$2\r\nhi\r\n", 40); len(exchanges) != 0 {
+		t.Fatalf("the push data should not be the reply: %+v", exchanges)
+	}
+	if exchanges = decode(server, "$-1\r\n", 50); len(exchanges) != 1 || exchanges[0].Resource != "order" {
+		t.Fatalf("unexpected exchanges: %+v", exchanges)
+	}
+}
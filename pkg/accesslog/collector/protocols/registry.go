@@ -0,0 +1,135 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+// the protocols of the registered analyzers start from here, the smaller ones are reserved for the protocols detected in the kernel
+const registeredProtocolStart = 16
+
+// ProtocolAnalyzer is the pluggable application protocol decoder, register it through the RegisterProtocolAnalyzer
+// in the "init" function, then the data of the protocol is analyzed without modifying the collectors
+type ProtocolAnalyzer interface {
+	// Name of the protocol, such as "kafka"
+	Name() string
+	// Ports are the server ports of the protocol, the data of the connections which local or remote port is matched
+	// are uploaded from the kernel when the protocol cannot be detected by the content
+	Ports() []uint16
+	// NewProtocol create the protocol in each analyze partition, it detects and parses the data, then sends the access logs
+	// through the forwarder. When the data is not the protocol, mark the AnalyzeHelper.ProtocolBreak to only send the kernel logs
+	NewProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol) Protocol
+}
+
+// TransportProtocolAnalyzer is the optional interface of the ProtocolAnalyzer, the ports are only matched for the connections
+// of the transport, such as the Diameter over SCTP. The analyzer without it matches the ports of all transports
+type TransportProtocolAnalyzer interface {
+	ProtocolAnalyzer
+	// Transport of the ports, such as the enums.TransportProtocolSCTP
+	Transport() enums.TransportProtocol
+}
+
+// protocolPort is the key of the port in the BPF, the transport 0 means matching all transports
+type protocolPort struct {
+	Port      uint16
+	Transport enums.TransportProtocol
+	Pad0      uint8
+}
+
+type registeredAnalyzer struct {
+	protocol enums.ConnectionProtocol
+	analyzer ProtocolAnalyzer
+}
+
+var (
+	registeredAnalyzers     = make([]*registeredAnalyzer, 0)
+	registeredAnalyzersLock sync.RWMutex
+)
+
+// RegisterProtocolAnalyzer register the protocol analyzer, return the allocated protocol of the analyzer
+func RegisterProtocolAnalyzer(analyzer ProtocolAnalyzer) (enums.ConnectionProtocol, error) {
+	registeredAnalyzersLock.Lock()
+	defer registeredAnalyzersLock.Unlock()
+	if registeredProtocolStart+len(registeredAnalyzers) > math.MaxUint8 {
+		return 0, fmt.Errorf("too many protocol analyzers registered")
+	}
+	for _, exist := range registeredAnalyzers {
+		if exist.analyzer.Name() == analyzer.Name() {
+			return 0, fmt.Errorf("the protocol analyzer %s is already registered", analyzer.Name())
+		}
+		if !transportOverlap(analyzerTransport(exist.analyzer), analyzerTransport(analyzer)) {
+			continue
+		}
+		for _, existPort := range exist.analyzer.Ports() {
+			for _, port := range analyzer.Ports() {
+				if existPort == port {
+					return 0, fmt.Errorf("the port %d of protocol analyzer %s is already registered by %s",
+						port, analyzer.Name(), exist.analyzer.Name())
+				}
+			}
+		}
+	}
+
+	protocol := enums.ConnectionProtocol(registeredProtocolStart + len(registeredAnalyzers))
+	enums.RegisterConnectionProtocolString(protocol, analyzer.Name())
+	registeredAnalyzers = append(registeredAnalyzers, &registeredAnalyzer{protocol: protocol, analyzer: analyzer})
+	return protocol, nil
+}
+
+// registeredProtocols create the protocols of all registered analyzers
+func registeredProtocols(ctx *common.AccessLogContext) []Protocol {
+	registeredAnalyzersLock.RLock()
+	defer registeredAnalyzersLock.RUnlock()
+	result := make([]Protocol, 0, len(registeredAnalyzers))
+	for _, r := range registeredAnalyzers {
+		result = append(result, r.analyzer.NewProtocol(ctx, r.protocol))
+	}
+	return result
+}
+
+// registeredProtocolPorts is the protocol of each port of all registered analyzers
+func registeredProtocolPorts() map[protocolPort]enums.ConnectionProtocol {
+	registeredAnalyzersLock.RLock()
+	defer registeredAnalyzersLock.RUnlock()
+	result := make(map[protocolPort]enums.ConnectionProtocol)
+	for _, r := range registeredAnalyzers {
+		transport := analyzerTransport(r.analyzer)
+		for _, port := range r.analyzer.Ports() {
+			result[protocolPort{Port: port, Transport: transport}] = r.protocol
+		}
+	}
+	return result
+}
+
+// analyzerTransport is the transport of the analyzer ports, the unknown means all transports
+func analyzerTransport(analyzer ProtocolAnalyzer) enums.TransportProtocol {
+	if t, ok := analyzer.(TransportProtocolAnalyzer); ok {
+		return t.Transport()
+	}
+	return enums.TransportProtocolUnknown
+}
+
+func transportOverlap(t1, t2 enums.TransportProtocol) bool {
+	return t1 == enums.TransportProtocolUnknown || t2 == enums.TransportProtocolUnknown || t1 == t2
+}
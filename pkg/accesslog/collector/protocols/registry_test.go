@@ -0,0 +1,111 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+type testProtocolAnalyzer struct {
+	name  string
+	ports []uint16
+}
+
+func (t *testProtocolAnalyzer) Name() string {
+	return t.name
+}
+
+func (t *testProtocolAnalyzer) Ports() []uint16 {
+	return t.ports
+}
+
+func (t *testProtocolAnalyzer) NewProtocol(*common.AccessLogContext, enums.ConnectionProtocol) Protocol {
+	return nil
+}
+
+type testTransportProtocolAnalyzer struct {
+	testProtocolAnalyzer
+	transport enums.TransportProtocol
+}
+
+func (t *testTransportProtocolAnalyzer) Transport() enums.TransportProtocol {
+	return t.transport
+}
+
+func newTestSCTPAnalyzer(name string, ports ...uint16) *testTransportProtocolAnalyzer {
+	return &testTransportProtocolAnalyzer{
+		testProtocolAnalyzer: testProtocolAnalyzer{name: name, ports: ports},
+		transport:            enums.TransportProtocolSCTP,
+	}
+}
+
+func TestRegisterProtocolAnalyzer(t *testing.T) {
+	// the built-in analyzers are registered in the init function
+	builtin := registeredAnalyzers
+	registeredAnalyzers = make([]*registeredAnalyzer, 0)
+	defer func() {
+		// restore the protocol names overwritten or added by the test analyzers
+		for _, r := range registeredAnalyzers {
+			enums.UnregisterConnectionProtocolString(r.protocol)
+		}
+		for _, r := range builtin {
+			enums.RegisterConnectionProtocolString(r.protocol, r.analyzer.Name())
+		}
+		registeredAnalyzers = builtin
+	}()
+	tests := []struct {
+		name     string
+		analyzer ProtocolAnalyzer
+		expected enums.ConnectionProtocol
+		hasError bool
+	}{
+		{name: "first", analyzer: &testProtocolAnalyzer{name: "test1", ports: []uint16{9000}}, expected: registeredProtocolStart},
+		{name: "second", analyzer: &testProtocolAnalyzer{name: "test2", ports: []uint16{9001, 9002}}, expected: registeredProtocolStart + 1},
+		{name: "duplicate name", analyzer: &testProtocolAnalyzer{name: "test1", ports: []uint16{9003}}, hasError: true},
+		{name: "duplicate port", analyzer: &testProtocolAnalyzer{name: "test3", ports: []uint16{9002}}, hasError: true},
+		{name: "sctp port registered by all transports", analyzer: newTestSCTPAnalyzer("test3", 9002), hasError: true},
+		{name: "sctp", analyzer: newTestSCTPAnalyzer("test4", 3868), expected: registeredProtocolStart + 2},
+		{name: "tcp port registered by sctp", analyzer: &testTransportProtocolAnalyzer{
+			testProtocolAnalyzer: testProtocolAnalyzer{name: "test5", ports: []uint16{3868}},
+			transport:            enums.TransportProtocolTCP,
+		}, expected: registeredProtocolStart + 3},
+		{name: "duplicate sctp port", analyzer: newTestSCTPAnalyzer("test6", 3868), hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protocol, err := RegisterProtocolAnalyzer(tt.analyzer)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("expected error: %t, actual: %v", tt.hasError, err)
+			}
+			if err == nil && protocol != tt.expected {
+				t.Errorf("expected protocol: %d, actual: %d", tt.expected, protocol)
+			}
+		})
+	}
+	if name := enums.ConnectionProtocolString(registeredProtocolStart + 1); name != "test2" {
+		t.Errorf("expected protocol name: test2, actual: %s", name)
+	}
+	ports := registeredProtocolPorts()
+	if len(ports) != 5 || ports[protocolPort{Port: 9001}] != registeredProtocolStart+1 ||
+		ports[protocolPort{Port: 3868, Transport: enums.TransportProtocolSCTP}] != registeredProtocolStart+2 {
+		t.Errorf("unexpected registered ports: %v", ports)
+	}
+}
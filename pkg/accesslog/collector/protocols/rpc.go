@@ -0,0 +1,343 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/accesslog/forwarder"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/tools/buffer"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+var rpcLog = logger.GetLogger("accesslog", "collector", "protocols", "rpc")
+
+// the max pending size of one direction, the message bigger than it is decoded from the received data
+var rpcMaxPendingSize = 1024 * 1024
+
+// RPCExchange is the request and response decoded by the RPCDecoder
+type RPCExchange struct {
+	Operation string
+	Resource  string
+	Status    string
+	// the BPF time of the request start and response end
+	StartTime uint64
+	EndTime   uint64
+
+	RequestBytes  uint64
+	ResponseBytes uint64
+}
+
+// RPCDecoder decode the messages of the request-response protocols in one connection,
+// the decoded exchanges are reported as the meters instead of the protocol logs
+type RPCDecoder interface {
+	// Decode the messages from the data of the stream, return the length of the consumed data and the finished exchanges.
+	// The message should not be consumed until the stream is Ready, then it's decoded again when more data received.
+	// The consumed length could be bigger than the data, the following data of the message would be discarded.
+	Decode(stream *RPCStream) (int, []*RPCExchange, error)
+}
+
+// RPCProtocol is the protocol for the request-response protocols, it buffers the data of each direction
+// and decodes them through the RPCDecoder of the connection
+type RPCProtocol struct {
+	ctx        *common.AccessLogContext
+	protocol   enums.ConnectionProtocol
+	newDecoder func() RPCDecoder
+}
+
+func NewRPCProtocol(ctx *common.AccessLogContext, protocol enums.ConnectionProtocol, newDecoder func() RPCDecoder) *RPCProtocol {
+	return &RPCProtocol{ctx: ctx, protocol: protocol, newDecoder: newDecoder}
+}
+
+type RPCMetrics struct {
+	ConnectionID uint64
+	RandomID     uint64
+
+	decoder          RPCDecoder
+	requestDirection enums.SocketDataDirection
+	lastDataID       uint64
+	lastSequence     int
+	streams          map[enums.SocketDataDirection]*RPCStream
+}
+
+// RPCStream is the data of one direction in the connection which not consumed by the decoder yet
+type RPCStream struct {
+	direction  enums.SocketDataDirection
+	connection *RPCMetrics
+	data       []byte
+	segments   []rpcStreamSegment
+	// the size of the data which is not uploaded at the end of the data because of the upload limit
+	lost int
+	// the size of the following data should be discarded, because the message is consumed before fully received
+	discard int
+
+	currentDataID   uint64
+	currentUploaded uint64
+}
+
+// rpcStreamSegment is the data from the same syscall
+type rpcStreamSegment struct {
+	end       int
+	startTime uint64
+	endTime   uint64
+}
+
+type rpcTimedData interface {
+	StartTime() uint64
+	EndTime() uint64
+}
+
+func (r *RPCProtocol) ForProtocol() enums.ConnectionProtocol {
+	return r.protocol
+}
+
+func (r *RPCProtocol) GenerateConnection(connectionID, randomID uint64) ProtocolMetrics {
+	return &RPCMetrics{
+		ConnectionID: connectionID,
+		RandomID:     randomID,
+		decoder:      r.newDecoder(),
+		streams:      make(map[enums.SocketDataDirection]*RPCStream),
+	}
+}
+
+func (r *RPCProtocol) Analyze(connection *PartitionConnection, helper *AnalyzeHelper) error {
+	metrics := connection.Metrics(r.protocol).(*RPCMetrics)
+	buf := connection.Buffer(r.protocol)
+	if metrics.requestDirection == 0 {
+		metrics.requestDirection = r.detectRequestDirection(metrics)
+	}
+	var handledDataID uint64
+	for _, data := range buf.TotalBuffer() {
+		if data.DataID() < metrics.lastDataID || (data.DataID() == metrics.lastDataID && data.DataSequence() <= metrics.lastSequence) {
+			continue
+		}
+		metrics.lastDataID, metrics.lastSequence = data.DataID(), data.DataSequence()
+		if data.IsFinished() {
+			handledDataID = data.DataID()
+		} else if data.DataID() > 0 {
+			handledDataID = data.DataID() - 1
+		}
+		if err := r.handleData(metrics, data); err != nil {
+			rpcLog.Debugf("stop analyzing the %s protocol data, connection ID: %d, random ID: %d, error: %v",
+				enums.ConnectionProtocolString(r.protocol), metrics.ConnectionID, metrics.RandomID, err)
+			r.ctx.ParseStats.Increase(r.protocol, common.ProtocolParseIssueError)
+			helper.ProtocolBreak = true
+			return nil
+		}
+	}
+
+	// the exchanges are reported as the meters, so the details are sent without protocol
+	for _, detail := range buf.RemoveEventsBefore(handledDataID) {
+		forwarder.SendTransferNoProtocolEvent(r.ctx, detail.(events.SocketDetail))
+	}
+	return nil
+}
+
+// detectRequestDirection detect the direction of the request by the role of connection, return zero if the role is unknown
+func (r *RPCProtocol) detectRequestDirection(metrics *RPCMetrics) enums.SocketDataDirection {
+	connection := r.ctx.ConnectionMgr.FindByID(metrics.ConnectionID, metrics.RandomID)
+	if connection == nil || connection.Socket == nil {
+		return 0
+	}
+	switch connection.Socket.Role {
+	case enums.ConnectionRoleClient:
+		return enums.SocketDataDirectionEgress
+	case enums.ConnectionRoleServer:
+		return enums.SocketDataDirectionIngress
+	}
+	return 0
+}
+
+func (r *RPCProtocol) handleData(metrics *RPCMetrics, data buffer.SocketDataBuffer) error {
+	stream := metrics.streams[data.Direction()]
+	if stream == nil {
+		stream = &RPCStream{direction: data.Direction(), connection: metrics}
+		metrics.streams[data.Direction()] = stream
+	}
+	if err := stream.append(data); err != nil {
+		return err
+	}
+	if len(stream.data) > rpcMaxPendingSize {
+		return fmt.Errorf("the pending data exceed the max size")
+	}
+	consumed, exchanges, err := metrics.decoder.Decode(stream)
+	if err != nil {
+		return err
+	}
+	stream.consume(consumed)
+
+	name := enums.ConnectionProtocolString(r.protocol)
+	for _, exchange := range exchanges {
+		r.ctx.RPC.Append(&common.RPCRecord{
+			ConnectionID:  metrics.ConnectionID,
+			RandomID:      metrics.RandomID,
+			Protocol:      name,
+			Operation:     exchange.Operation,
+			Resource:      exchange.Resource,
+			Status:        exchange.Status,
+			StartTime:     exchange.StartTime,
+			EndTime:       exchange.EndTime,
+			RequestBytes:  exchange.RequestBytes,
+			ResponseBytes: exchange.ResponseBytes,
+			CreateTime:    time.Now(),
+		})
+	}
+	return nil
+}
+
+// Direction of the stream data
+func (s *RPCStream) Direction() enums.SocketDataDirection {
+	return s.direction
+}
+
+// Data is the received data which not consumed yet
+func (s *RPCStream) Data() []byte {
+	return s.data
+}
+
+// Lost is the size of the data which is not uploaded at the end of the data because of the upload limit
+func (s *RPCStream) Lost() int {
+	return s.lost
+}
+
+// IsRequest check the stream is sending the requests, the second result is false when the direction is not detected yet
+func (s *RPCStream) IsRequest() (isRequest, detected bool) {
+	if s.connection.requestDirection == 0 {
+		return false, false
+	}
+	return s.connection.requestDirection == s.direction, true
+}
+
+// MarkRequest mark the stream is sending the requests, for the decoder which detect the request through the content
+func (s *RPCStream) MarkRequest() {
+	s.connection.requestDirection = s.direction
+}
+
+// MarkResponse mark the stream is sending the responses, for the decoder which detect the response through the content
+func (s *RPCStream) MarkResponse() {
+	switch s.direction {
+	case enums.SocketDataDirectionEgress:
+		s.connection.requestDirection = enums.SocketDataDirectionIngress
+	case enums.SocketDataDirectionIngress:
+		s.connection.requestDirection = enums.SocketDataDirectionEgress
+	}
+}
+
+// Ready check the message in the range could be decoded, the message is fully received,
+// or cannot be fully received(the data is truncated or too large)
+func (s *RPCStream) Ready(offset, length int) bool {
+	return offset+length <= len(s.data) || s.lost > 0 || length > rpcMaxPendingSize
+}
+
+// StartTime is the BPF start time of the syscall which contains the data at the offset
+func (s *RPCStream) StartTime(offset int) uint64 {
+	if segment := s.segment(offset); segment != nil {
+		return segment.startTime
+	}
+	return 0
+}
+
+// EndTime is the BPF end time of the syscall which contains the data at the offset
+func (s *RPCStream) EndTime(offset int) uint64 {
+	if segment := s.segment(offset); segment != nil {
+		return segment.endTime
+	}
+	return 0
+}
+
+// SegmentEnd is the end offset of the syscall data which contains the offset, such as the end of the datagram
+func (s *RPCStream) SegmentEnd(offset int) int {
+	if segment := s.segment(offset); segment != nil && segment.end > offset {
+		return segment.end
+	}
+	return len(s.data)
+}
+
+func (s *RPCStream) segment(offset int) *rpcStreamSegment {
+	if len(s.segments) == 0 {
+		return nil
+	}
+	for i := range s.segments {
+		if offset < s.segments[i].end {
+			return &s.segments[i]
+		}
+	}
+	return &s.segments[len(s.segments)-1]
+}
+
+func (s *RPCStream) append(data buffer.SocketDataBuffer) error {
+	if data.DataID() != s.currentDataID {
+		s.currentDataID, s.currentUploaded = data.DataID(), 0
+	}
+	s.currentUploaded += uint64(data.BufferLen())
+	content := data.BufferData()
+	if s.discard > 0 {
+		n := min(s.discard, len(content))
+		content, s.discard = content[n:], s.discard-n
+	}
+	var lost int
+	if data.IsFinished() && data.HaveReduceDataAfterChunk() && data.TotalSize() > s.currentUploaded {
+		lost = int(data.TotalSize() - s.currentUploaded)
+	}
+	if lost > 0 && s.discard > 0 {
+		n := min(s.discard, lost)
+		lost, s.discard = lost-n, s.discard-n
+	}
+	if len(content) == 0 && lost == 0 {
+		return nil
+	}
+	if s.lost > 0 {
+		return fmt.Errorf("the truncated data is not consumed, data id: %d", data.DataID())
+	}
+
+	var startTime, endTime uint64
+	if timed, ok := data.(rpcTimedData); ok {
+		startTime, endTime = timed.StartTime(), timed.EndTime()
+	}
+	s.data = append(s.data, content...)
+	s.segments = append(s.segments, rpcStreamSegment{end: len(s.data), startTime: startTime, endTime: endTime})
+	s.lost = lost
+	return nil
+}
+
+func (s *RPCStream) consume(n int) {
+	if n <= 0 {
+		return
+	}
+	if n >= len(s.data) {
+		if overflow := n - len(s.data) - s.lost; overflow > 0 {
+			s.discard += overflow
+		}
+		s.data, s.segments, s.lost = s.data[:0], s.segments[:0], 0
+		return
+	}
+	s.data = append(s.data[:0], s.data[n:]...)
+	segments := s.segments[:0]
+	for _, segment := range s.segments {
+		if segment.end <= n {
+			continue
+		}
+		segment.end -= n
+		segments = append(segments, segment)
+	}
+	s.segments = segments
+}
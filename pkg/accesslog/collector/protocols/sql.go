@@ -0,0 +1,128 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"regexp"
+	"strings"
+)
+
+// the max length of the statement digest, the longer digest is truncated
+const sqlMaxDigestLength = 256
+
+var sqlValueListPattern = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+
+// sqlStatement is the digest of the statement and the command type of it
+type sqlStatement struct {
+	command string
+	digest  string
+}
+
+func newSQLStatement(statement string, doubleQuoteString bool) *sqlStatement {
+	digest := sqlDigest(statement, doubleQuoteString)
+	return &sqlStatement{command: sqlCommand(digest), digest: digest}
+}
+
+// sqlDigest strip the literals and comments of the statement, then collapse the whitespaces and the value lists,
+// the double-quoted text is treated as the string literal when the doubleQuoteString is true(such as MySQL),
+// otherwise it's the identifier(such as PostgreSQL)
+func sqlDigest(statement string, doubleQuoteString bool) string {
+	var result strings.Builder
+	space := false
+	writeSpace := func() {
+		if result.Len() > 0 {
+			space = true
+		}
+	}
+	write := func(b ...byte) {
+		if space {
+			result.WriteByte(' ')
+			space = false
+		}
+		result.Write(b)
+	}
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == '\'' || (c == '"' && doubleQuoteString):
+			i = sqlSkipQuoted(statement, i)
+			write('?')
+		case c == '-' && i+1 < len(statement) && statement[i+1] == '-', c == '#' && doubleQuoteString:
+			for i < len(statement) && statement[i] != '\n' {
+				i++
+			}
+			writeSpace()
+		case c == '/' && i+1 < len(statement) && statement[i+1] == '*':
+			end := strings.Index(statement[i+2:], "*/")
+			if end < 0 {
+				i = len(statement)
+			} else {
+				i += end + 3
+			}
+			writeSpace()
+		case c >= '0' && c <= '9' && (i == 0 || !sqlIdentifierChar(statement[i-1])):
+			for i+1 < len(statement) && (sqlIdentifierChar(statement[i+1]) || statement[i+1] == '.') {
+				i++
+			}
+			write('?')
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			writeSpace()
+		default:
+			write(c)
+		}
+	}
+	digest := sqlValueListPattern.ReplaceAllString(result.String(), "?")
+	if len(digest) > sqlMaxDigestLength {
+		digest = digest[:sqlMaxDigestLength]
+	}
+	return digest
+}
+
+// sqlSkipQuoted find the end index of the quoted text which start from the index,
+// the quote could be escaped by the backslash or doubled quote
+func sqlSkipQuoted(statement string, start int) int {
+	quote := statement[start]
+	for i := start + 1; i < len(statement); i++ {
+		switch statement[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(statement) && statement[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(statement)
+}
+
+func sqlIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// sqlCommand is the command type of the statement digest, such as "SELECT"
+func sqlCommand(digest string) string {
+	command := strings.TrimLeft(digest, "( ")
+	if end := strings.IndexFunc(command, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ';'
+	}); end >= 0 {
+		command = command[:end]
+	}
+	return strings.ToUpper(command)
+}
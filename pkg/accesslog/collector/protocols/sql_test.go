@@ -0,0 +1,73 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import "testing"
+
+func TestSQLDigest(t *testing.T) {
+	tests := []struct {
+		name              string
+		statement         string
+		doubleQuoteString bool
+		digest            string
+		command           string
+	}{
+		{
+			name:      "literals",
+			statement: "SELECT * FROM users WHERE name = 'it''s' AND age > 18 AND score = 1.5e3",
+			digest:    "SELECT * FROM users WHERE name = ? AND age > ? AND score = ?",
+			command:   "SELECT",
+		},
+		{
+			name:              "mysql double quote and comments",
+			statement:         "/* app */ update t1  set v = \"a\\\"b\" -- comment\n where id in (1, 2,3) # end",
+			doubleQuoteString: true,
+			digest:            "update t1 set v = ? where id in (?)",
+			command:           "UPDATE",
+		},
+		{
+			name:      "postgres identifier and placeholders",
+			statement: "insert into \"order_2024\" (id, name) values ($1, $2), (3, 'x')",
+			digest:    "insert into \"order_2024\" (id, name) values ($1, $2), (?)",
+			command:   "INSERT",
+		},
+		{
+			name:      "identifier with number",
+			statement: "(select col1 from t2)",
+			digest:    "(select col1 from t2)",
+			command:   "SELECT",
+		},
+		{
+			name:      "hex literal",
+			statement: "delete from t where id = 0xFF",
+			digest:    "delete from t where id = ?",
+			command:   "DELETE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := sqlDigest(tt.statement, tt.doubleQuoteString)
+			if digest != tt.digest {
+				t.Errorf("expected digest: %q, actual: %q", tt.digest, digest)
+			}
+			if command := sqlCommand(digest); command != tt.command {
+				t.Errorf("expected command: %s, actual: %s", tt.command, command)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocols
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/accesslog/forwarder"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/tools/buffer"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+
+	"golang.org/x/net/http2"
+)
+
+var tlsLog = logger.GetLogger("accesslog", "collector", "protocols", "tls")
+
+// the max pending size of one direction, the decryption is stopped when exceed, such as the secret never exported
+var tlsMaxPendingSize = 1024 * 1024
+
+// errTLSHandshakeOnly means the handshake metadata is collected, and the process not exports the key log for decrypting
+var errTLSHandshakeOnly = errors.New("only the handshake is analyzed")
+
+// TLSProtocol collect the handshake metadata of the TLS connections,
+// and decrypt the TLS data through the key log exported by the process,
+// the decrypted data is analyzed by the HTTP/1.x or HTTP/2 protocol analyzer
+type TLSProtocol struct {
+	ctx *common.AccessLogContext
+}
+
+func NewTLSAnalyzer(ctx *common.AccessLogContext) *TLSProtocol {
+	return &TLSProtocol{ctx: ctx}
+}
+
+type TLSMetrics struct {
+	ConnectionID uint64
+	RandomID     uint64
+	PID          uint32
+
+	lastDataID   uint64
+	lastSequence int
+	streams      map[enums.SocketDataDirection]*tlsStream
+	clientRandom []byte
+	serverRandom []byte
+	cipherSuite  uint16
+	tls13        bool
+	broken       bool
+	// the handshake metadata from the client hello
+	serverName  string
+	alpnOffered []string
+	// the selected protocol from the server hello(TLS 1.2) or the encrypted extensions(TLS 1.3)
+	alpn string
+	// the protocol of the decrypted data, and the data ids which the decrypted data routed to the protocol
+	plaintextProtocol enums.ConnectionProtocol
+	routedDataIDs     map[uint64]bool
+}
+
+type tlsStream struct {
+	pending   []byte
+	client    bool
+	encrypted bool
+	// the TLS 1.3 handshake messages are encrypted by the handshake secret
+	handshakeFinished bool
+	// the decrypted handshake messages which not completed in the records
+	handshakePending []byte
+	decryptor        *ssl.RecordDecryptor
+}
+
+func (t *TLSProtocol) ForProtocol() enums.ConnectionProtocol {
+	return enums.ConnectionProtocolTLS
+}
+
+func (t *TLSProtocol) GenerateConnection(connectionID, randomID uint64) ProtocolMetrics {
+	pid, _ := events.ParseConnectionID(connectionID)
+	return &TLSMetrics{
+		ConnectionID:  connectionID,
+		RandomID:      randomID,
+		PID:           pid,
+		streams:       make(map[enums.SocketDataDirection]*tlsStream),
+		routedDataIDs: make(map[uint64]bool),
+	}
+}
+
+func (t *TLSProtocol) Analyze(connection *PartitionConnection, _ *AnalyzeHelper) error {
+	metrics := connection.Metrics(enums.ConnectionProtocolTLS).(*TLSMetrics)
+	buf := connection.Buffer(enums.ConnectionProtocolTLS)
+	var handledDataID uint64
+	for _, data := range buf.TotalBuffer() {
+		if data.DataID() < metrics.lastDataID || (data.DataID() == metrics.lastDataID && data.DataSequence() <= metrics.lastSequence) {
+			continue
+		}
+		metrics.lastDataID, metrics.lastSequence = data.DataID(), data.DataSequence()
+		if data.IsFinished() {
+			handledDataID = data.DataID()
+		} else if data.DataID() > 0 {
+			handledDataID = data.DataID() - 1
+		}
+		if metrics.broken {
+			continue
+		}
+		if err := t.handleData(connection, metrics, data); errors.Is(err, errTLSHandshakeOnly) {
+			metrics.broken = true
+			metrics.streams = nil
+		} else if errors.Is(err, ssl.ErrUnsupportedCipherSuite) {
+			tlsLog.Warnf("stop decrypting the TLS data, connection ID: %d, random ID: %d, pid: %d, error: %v",
+				metrics.ConnectionID, metrics.RandomID, metrics.PID, err)
+			t.ctx.ParseStats.Increase(enums.ConnectionProtocolTLS, common.ProtocolParseIssueError)
+			metrics.broken = true
+			metrics.streams = nil
+		} else if err != nil {
+			tlsLog.Debugf("stop decrypting the TLS data, connection ID: %d, random ID: %d, error: %v",
+				metrics.ConnectionID, metrics.RandomID, err)
+			t.ctx.ParseStats.Increase(enums.ConnectionProtocolTLS, common.ProtocolParseIssueError)
+			metrics.broken = true
+			metrics.streams = nil
+		}
+	}
+
+	// the details of the decrypted data are moved to the plaintext protocol, others are sent without protocol
+	for _, detail := range buf.RemoveEventsBefore(handledDataID) {
+		if metrics.routedDataIDs[detail.DataID()] {
+			connection.Buffer(metrics.plaintextProtocol).AppendDetailEvent(detail)
+			continue
+		}
+		forwarder.SendTransferNoProtocolEvent(t.ctx, detail.(events.SocketDetail))
+	}
+	for dataID := range metrics.routedDataIDs {
+		if dataID <= handledDataID {
+			delete(metrics.routedDataIDs, dataID)
+		}
+	}
+	return nil
+}
+
+func (t *TLSProtocol) handleData(connection *PartitionConnection, metrics *TLSMetrics, data buffer.SocketDataBuffer) error {
+	if data.HaveReduceDataAfterChunk() {
+		return fmt.Errorf("the socket data is not fully uploaded, data id: %d", data.DataID())
+	}
+	stream := metrics.streams[data.Direction()]
+	if stream == nil {
+		stream = &tlsStream{}
+		metrics.streams[data.Direction()] = stream
+	}
+	stream.pending = append(stream.pending, data.BufferData()...)
+	if len(stream.pending) > tlsMaxPendingSize {
+		return fmt.Errorf("the pending data exceed the max size")
+	}
+
+	records, read := ssl.ParseTLSRecords(stream.pending)
+	var plaintext []byte
+	for i, record := range records {
+		finished, applicationData, err := t.handleRecord(metrics, stream, data, record)
+		if err != nil {
+			return err
+		}
+		plaintext = append(plaintext, applicationData...)
+		// the secret is not exported yet, waiting for the next analyzing
+		if !finished {
+			read = 0
+			for _, r := range records[:i] {
+				read += len(r.Header) + len(r.Fragment)
+			}
+			break
+		}
+	}
+	stream.pending = append(stream.pending[:0], stream.pending[read:]...)
+	if len(plaintext) > 0 {
+		t.routePlaintext(connection, metrics, data, plaintext)
+	}
+	return nil
+}
+
+// routePlaintext append the decrypted data into the HTTP/1.x or HTTP/2 protocol buffer of the connection, with the same data id
+func (t *TLSProtocol) routePlaintext(connection *PartitionConnection, metrics *TLSMetrics, data buffer.SocketDataBuffer, plaintext []byte) {
+	if metrics.plaintextProtocol == enums.ConnectionProtocolUnknown {
+		metrics.plaintextProtocol = enums.ConnectionProtocolHTTP
+		if metrics.alpn == "h2" || bytes.HasPrefix(plaintext, []byte(http2.ClientPreface)) {
+			metrics.plaintextProtocol = enums.ConnectionProtocolHTTP2
+		}
+	}
+	event := &events.SocketDataUploadEvent{Buffer: *buffer.BorrowNewBuffer()}
+	event.Protocol0 = metrics.plaintextProtocol
+	event.Direction0 = data.Direction()
+	if data.IsFinished() {
+		event.Finished = 1
+	}
+	event.Sequence0 = uint16(data.DataSequence())
+	event.DataLen = uint16(copy(event.Buffer[:], plaintext))
+	event.StartTime0, event.EndTime0 = data.StartTime(), data.EndTime()
+	event.ConnectionID, event.RandomID = metrics.ConnectionID, metrics.RandomID
+	event.DataID0, event.PrevDataID0 = data.DataID(), data.PrevDataID()
+	event.TotalSize0 = uint64(event.DataLen)
+	connection.AppendDecryptedData(event)
+	metrics.routedDataIDs[data.DataID()] = true
+}
+
+// handleRecord handle the TLS record, return the record is handled or waiting for the secret, and the decrypted application data
+func (t *TLSProtocol) handleRecord(metrics *TLSMetrics, stream *tlsStream, data buffer.SocketDataBuffer,
+	record *ssl.TLSRecord) (finished bool, plaintext []byte, err error) {
+	if !stream.encrypted {
+		switch record.Type {
+		case ssl.TLSRecordHandshake:
+			return true, nil, t.handleHello(metrics, stream, record, data)
+		case ssl.TLSRecordChangeCipherSpec:
+			// the TLS 1.3 sends the change cipher spec only for the compatibility
+			stream.encrypted = !metrics.tls13
+			return true, nil, nil
+		case ssl.TLSRecordApplicationData:
+			stream.encrypted = true
+		default:
+			return true, nil, nil
+		}
+	}
+	if stream.decryptor == nil {
+		decryptor, err := t.buildDecryptor(metrics, stream)
+		if err != nil || decryptor == nil {
+			return false, nil, err
+		}
+		stream.decryptor = decryptor
+	}
+	contentType, plaintext, err := stream.decryptor.Decrypt(record)
+	if err != nil {
+		return false, nil, err
+	}
+	switch contentType {
+	case ssl.TLSRecordHandshake:
+		if metrics.tls13 && !stream.handshakeFinished {
+			t.handleEncryptedHandshake(metrics, stream, plaintext)
+		}
+	case ssl.TLSRecordApplicationData:
+		return true, plaintext, nil
+	}
+	return true, nil, nil
+}
+
+// handleEncryptedHandshake walk the handshake messages encrypted by the handshake secret in TLS 1.3,
+// the server coalesces the encrypted extensions, certificate and finished messages into the same record normally
+func (t *TLSProtocol) handleEncryptedHandshake(metrics *TLSMetrics, stream *tlsStream, plaintext []byte) {
+	stream.handshakePending = append(stream.handshakePending, plaintext...)
+	messages, read := ssl.ParseTLSHandshakeMessages(stream.handshakePending)
+	for _, message := range messages {
+		switch message.Type {
+		case ssl.TLSHandshakeEncryptedExtensions:
+			metrics.alpn = ssl.ParseEncryptedExtensionsALPN(message.Body)
+		case ssl.TLSHandshakeFinished:
+			// switch to the traffic secret after the handshake finished
+			stream.handshakeFinished = true
+			stream.decryptor = nil
+			stream.handshakePending = nil
+			return
+		}
+	}
+	stream.handshakePending = append(stream.handshakePending[:0], stream.handshakePending[read:]...)
+}
+
+func (t *TLSProtocol) handleHello(metrics *TLSMetrics, stream *tlsStream, record *ssl.TLSRecord, data buffer.SocketDataBuffer) error {
+	hello, err := ssl.ParseTLSHello(record.Fragment)
+	if err != nil {
+		// the other handshake messages in plaintext, such as certificate in TLS 1.2
+		return nil
+	}
+	if hello.Type == ssl.TLSHandshakeClientHello {
+		metrics.clientRandom, metrics.serverName, metrics.alpnOffered = hello.Random, hello.ServerName, hello.ALPN
+		stream.client = true
+		return nil
+	}
+	metrics.serverRandom, metrics.cipherSuite, metrics.tls13 = hello.Random, hello.CipherSuite, hello.IsTLS13
+	if len(hello.ALPN) > 0 {
+		metrics.alpn = hello.ALPN[0]
+	}
+	if t.ctx.TLSHandshakes != nil {
+		handshake := &common.TLSHandshake{
+			ConnectionID: metrics.ConnectionID,
+			RandomID:     metrics.RandomID,
+			ServerName:   metrics.serverName,
+			Version:      hello.Version,
+			CipherSuite:  hello.CipherSuite,
+			ALPNOffered:  metrics.alpnOffered,
+			Time:         data.StartTime(),
+			CreateTime:   time.Now(),
+		}
+		if len(hello.ALPN) > 0 {
+			handshake.ALPN = hello.ALPN[0]
+		}
+		t.ctx.TLSHandshakes.Append(handshake)
+	}
+	if t.ctx.TLSKeyLogs == nil || t.ctx.TLSKeyLogs.Find(metrics.PID) == nil {
+		return errTLSHandshakeOnly
+	}
+	// the data after the server hello are encrypted in TLS 1.3
+	if metrics.tls13 {
+		for _, s := range metrics.streams {
+			s.encrypted = true
+		}
+	}
+	return nil
+}
+
+// buildDecryptor build the decryptor from the key log, return nil if the secret is not exported yet
+func (t *TLSProtocol) buildDecryptor(metrics *TLSMetrics, stream *tlsStream) (*ssl.RecordDecryptor, error) {
+	if metrics.clientRandom == nil || metrics.serverRandom == nil {
+		return nil, fmt.Errorf("the TLS hello messages are not found")
+	}
+	if t.ctx.ShedLevel() >= selfprotect.LevelDisablePayload {
+		return nil, fmt.Errorf("the payload inspection is disabled by the self-protection")
+	}
+	keyLog := t.ctx.TLSKeyLogs.Find(metrics.PID)
+	if keyLog == nil {
+		return nil, fmt.Errorf("the key log of the process is not found")
+	}
+	label := ssl.KeyLogClientRandom
+	if metrics.tls13 {
+		label = tls13SecretLabel(stream.client, stream.handshakeFinished)
+	}
+	secret, err := keyLog.Find(metrics.clientRandom, label)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	if metrics.tls13 {
+		return ssl.NewTLS13Decryptor(metrics.cipherSuite, secret)
+	}
+	return ssl.NewTLS12Decryptor(metrics.cipherSuite, secret, metrics.clientRandom, metrics.serverRandom, stream.client)
+}
+
+func tls13SecretLabel(client, handshakeFinished bool) string {
+	switch {
+	case client && handshakeFinished:
+		return ssl.KeyLogClientTrafficSecret0
+	case client:
+		return ssl.KeyLogClientHandshakeTrafficSecret
+	case handshakeFinished:
+		return ssl.KeyLogServerTrafficSecret0
+	default:
+		return ssl.KeyLogServerHandshakeTrafficSecret
+	}
+}
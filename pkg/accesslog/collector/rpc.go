@@ -0,0 +1,241 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	rpcMeterPrefix = "access_log_rpc_"
+	// the max duration to wait the connection of the record been built
+	rpcRetainTime = time.Minute
+	// the resources(such as SQL digest, Redis key prefix) are from the traffic, so the distinct resources of each process
+	// are limited in the meter labels, the others are folded into the "other" resource, the log keeps the full resource
+	rpcMaxResourcesPerProcess = 100
+	rpcOtherResource          = "other"
+)
+
+var rpcCollectInstance = NewRPCCollector()
+
+// RPCCollector aggregate the RPC records of the protocols(such as Kafka) in each flush period,
+// then report them as the meters of the process
+type RPCCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.MeterSender
+	periodic *reporter.Periodic
+	retainer *common.Retainer[*common.RPCRecord]
+
+	resources *rpcResources
+}
+
+// rpcResources is the distinct resources of each process which are reported in the meter labels
+type rpcResources struct {
+	mutex     sync.Mutex
+	limit     int
+	processes map[uint32]map[string]bool
+}
+
+type rpcMetricsKey struct {
+	pid       uint32
+	protocol  string
+	role      string
+	operation string
+	resource  string
+	status    string
+}
+
+type rpcMetrics struct {
+	count            uint64
+	durationNanos    uint64
+	requestBytes     uint64
+	responseBytes    uint64
+	requestMessages  uint64
+	responseMessages uint64
+}
+
+type rpcServiceInstance struct {
+	service  string
+	instance string
+}
+
+func NewRPCCollector() *RPCCollector {
+	return &RPCCollector{resources: newRPCResources(rpcMaxResourcesPerProcess)}
+}
+
+func newRPCResources(limit int) *rpcResources {
+	return &rpcResources{limit: limit, processes: make(map[uint32]map[string]bool)}
+}
+
+// fold return the resource when it is already reported or the process still under the limit, otherwise return the "other"
+func (r *rpcResources) fold(pid uint32, resource string) string {
+	if resource == "" {
+		return resource
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	resources := r.processes[pid]
+	if resources == nil {
+		resources = make(map[string]bool)
+		r.processes[pid] = resources
+	}
+	if resources[resource] {
+		return resource
+	}
+	if len(resources) >= r.limit {
+		return rpcOtherResource
+	}
+	resources[resource] = true
+	return resource
+}
+
+func (r *rpcResources) remove(pid uint32) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.processes, pid)
+}
+
+func (c *RPCCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.retainer = common.NewRetainer(rpcRetainTime, func(record *common.RPCRecord) time.Time {
+		return record.CreateTime
+	})
+	c.sender = reporter.NewMeterSender("RPC metrics", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "RPC metrics", period, c.flush)
+	ctx.ConnectionMgr.AddProcessListener(c)
+	return nil
+}
+
+func (c *RPCCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *RPCCollector) OnNewProcessMonitoring(int32) {
+}
+
+func (c *RPCCollector) OnProcessRemoved(pid int32) {
+	c.resources.remove(uint32(pid))
+}
+
+func (c *RPCCollector) flush(ctx context.Context) error {
+	records := c.retainer.Merge(c.context.RPC.Swap())
+	metrics := make(map[rpcMetricsKey]*rpcMetrics)
+	for _, record := range records {
+		connection := c.context.ConnectionMgr.FindByID(record.ConnectionID, record.RandomID)
+		if connection == nil {
+			// the connection may not be built yet, so check it in the next period
+			c.retainer.Retain(record)
+			continue
+		}
+		aggregateRPCRecord(metrics, c.resources, connection.PID, connection.Socket.Role.String(), record)
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	meters := make(map[rpcServiceInstance][]*v3.MeterData)
+	for key, value := range metrics {
+		for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(key.pid) {
+			instance := rpcServiceInstance{service: p.Entity().ServiceName, instance: p.Entity().InstanceName}
+			meters[instance] = append(meters[instance], buildRPCMeters(key, value)...)
+		}
+	}
+	if len(meters) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	collections := make([]*v3.MeterDataCollection, 0, len(meters))
+	for instance, data := range meters {
+		data[0].Service = instance.service
+		data[0].ServiceInstance = instance.instance
+		data[0].Timestamp = now
+		collections = append(collections, &v3.MeterDataCollection{MeterData: data})
+	}
+	return c.sender.Send(ctx, collections)
+}
+
+// aggregateRPCRecord merge the record into the metrics with the same process, protocol, role, operation, resource and status,
+// the resource is folded when the process reached the limit of the distinct resources
+func aggregateRPCRecord(metrics map[rpcMetricsKey]*rpcMetrics, resources *rpcResources, pid uint32, role string, record *common.RPCRecord) {
+	key := rpcMetricsKey{
+		pid:       pid,
+		protocol:  record.Protocol,
+		role:      role,
+		operation: record.Operation,
+		resource:  resources.fold(pid, record.Resource),
+		status:    record.Status,
+	}
+	value := metrics[key]
+	if value == nil {
+		value = &rpcMetrics{}
+		metrics[key] = value
+	}
+	value.count++
+	if record.EndTime > record.StartTime {
+		value.durationNanos += record.EndTime - record.StartTime
+	}
+	value.requestBytes += record.RequestBytes
+	value.responseBytes += record.ResponseBytes
+	value.requestMessages += record.RequestMessages
+	value.responseMessages += record.ResponseMessages
+}
+
+func buildRPCMeters(key rpcMetricsKey, value *rpcMetrics) []*v3.MeterData {
+	labels := []*v3.Label{
+		{Name: "protocol", Value: key.protocol},
+		{Name: "role", Value: key.role},
+		{Name: "operation", Value: key.operation},
+		{Name: "resource", Value: key.resource},
+		{Name: "status", Value: key.status},
+	}
+	build := func(name string, val float64) *v3.MeterData {
+		return &v3.MeterData{
+			Metric: &v3.MeterData_SingleValue{
+				SingleValue: &v3.MeterSingleValue{Name: rpcMeterPrefix + name, Labels: labels, Value: val},
+			},
+		}
+	}
+	meters := []*v3.MeterData{
+		build("call_count", float64(value.count)),
+		build("duration_sum", float64(value.durationNanos)/float64(time.Millisecond)),
+		build("request_bytes", float64(value.requestBytes)),
+		build("response_bytes", float64(value.responseBytes)),
+	}
+	// only the protocols which counting the messages report the message meters
+	if value.requestMessages > 0 || value.responseMessages > 0 {
+		meters = append(meters, build("request_messages", float64(value.requestMessages)),
+			build("response_messages", float64(value.responseMessages)))
+	}
+	return meters
+}
@@ -0,0 +1,113 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+)
+
+func TestAggregateRPCRecord(t *testing.T) {
+	records := []struct {
+		pid    uint32
+		record *common.RPCRecord
+	}{
+		{pid: 1, record: &common.RPCRecord{Protocol: "kafka", Operation: "Produce", Resource: "orders", Status: "0",
+			StartTime: 1_000_000, EndTime: 3_000_000, RequestBytes: 100, ResponseBytes: 20}},
+		{pid: 1, record: &common.RPCRecord{Protocol: "kafka", Operation: "Produce", Resource: "orders", Status: "0",
+			StartTime: 5_000_000, EndTime: 6_000_000, RequestBytes: 50, ResponseBytes: 20}},
+		{pid: 1, record: &common.RPCRecord{Protocol: "kafka", Operation: "Fetch", Resource: "orders", Status: "0",
+			StartTime: 5_000_000, EndTime: 4_000_000, RequestBytes: 10, ResponseBytes: 500}},
+		{pid: 2, record: &common.RPCRecord{Protocol: "kafka", Operation: "Produce", Resource: "orders", Status: "0"}},
+		{pid: 2, record: &common.RPCRecord{Protocol: "grpc", Operation: "Watch", Resource: "grpc.health.v1.Health", Status: "0",
+			RequestMessages: 1, ResponseMessages: 3}},
+		{pid: 2, record: &common.RPCRecord{Protocol: "grpc", Operation: "Watch", Resource: "grpc.health.v1.Health", Status: "0",
+			RequestMessages: 1, ResponseMessages: 2}},
+	}
+	metrics := make(map[rpcMetricsKey]*rpcMetrics)
+	resources := newRPCResources(rpcMaxResourcesPerProcess)
+	for _, r := range records {
+		aggregateRPCRecord(metrics, resources, r.pid, "client", r.record)
+	}
+
+	tests := []struct {
+		key      rpcMetricsKey
+		expected rpcMetrics
+	}{
+		{
+			key:      rpcMetricsKey{pid: 1, protocol: "kafka", role: "client", operation: "Produce", resource: "orders", status: "0"},
+			expected: rpcMetrics{count: 2, durationNanos: 3_000_000, requestBytes: 150, responseBytes: 40},
+		},
+		{
+			// the end time is before the start time, should not count the duration
+			key:      rpcMetricsKey{pid: 1, protocol: "kafka", role: "client", operation: "Fetch", resource: "orders", status: "0"},
+			expected: rpcMetrics{count: 1, requestBytes: 10, responseBytes: 500},
+		},
+		{
+			key:      rpcMetricsKey{pid: 2, protocol: "kafka", role: "client", operation: "Produce", resource: "orders", status: "0"},
+			expected: rpcMetrics{count: 1},
+		},
+		{
+			key:      rpcMetricsKey{pid: 2, protocol: "grpc", role: "client", operation: "Watch", resource: "grpc.health.v1.Health", status: "0"},
+			expected: rpcMetrics{count: 2, requestMessages: 2, responseMessages: 5},
+		},
+	}
+	if len(metrics) != len(tests) {
+		t.Fatalf("expected metrics count: %d, actual: %d", len(tests), len(metrics))
+	}
+	for _, tt := range tests {
+		actual := metrics[tt.key]
+		if actual == nil {
+			t.Fatalf("cannot found the metrics: %v", tt.key)
+		}
+		if *actual != tt.expected {
+			t.Errorf("metrics of %v, expected: %v, actual: %v", tt.key, tt.expected, *actual)
+		}
+	}
+}
+
+func TestRPCResourcesFold(t *testing.T) {
+	resources := newRPCResources(2)
+	tests := []struct {
+		name     string
+		pid      uint32
+		resource string
+		expected string
+	}{
+		{name: "first resource", pid: 1, resource: "SELECT a FROM t1", expected: "SELECT a FROM t1"},
+		{name: "empty resource not counted", pid: 1, resource: "", expected: ""},
+		{name: "second resource", pid: 1, resource: "SELECT b FROM t2", expected: "SELECT b FROM t2"},
+		{name: "exceed the limit", pid: 1, resource: "SELECT c FROM t3", expected: rpcOtherResource},
+		{name: "known resource after the limit", pid: 1, resource: "SELECT a FROM t1", expected: "SELECT a FROM t1"},
+		{name: "another process", pid: 2, resource: "SELECT c FROM t3", expected: "SELECT c FROM t3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := resources.fold(tt.pid, tt.resource); actual != tt.expected {
+				t.Errorf("expected resource: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+
+	// the resources are released after the process removed
+	resources.remove(1)
+	if actual := resources.fold(1, "SELECT c FROM t3"); actual != "SELECT c FROM t3" {
+		t.Errorf("expected the resource is reported after the process removed, actual: %s", actual)
+	}
+}
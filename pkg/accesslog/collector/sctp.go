@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	sctpTxQueueMeterName     = "access_log_sctp_association_tx_queue"
+	sctpRxQueueMeterName     = "access_log_sctp_association_rx_queue"
+	sctpInStreamsMeterName   = "access_log_sctp_association_in_streams"
+	sctpOutStreamsMeterName  = "access_log_sctp_association_out_streams"
+	sctpRetransmitsMeterName = "access_log_sctp_association_retransmits"
+	sctpPathsMeterName       = "access_log_sctp_association_paths"
+)
+
+var sctpCollectInstance = NewSCTPCollector()
+
+// SCTPCollector periodically export the association level metrics of the SCTP connections,
+// since the one-to-many style socket multiplexes the associations which cannot be distinguished in the syscalls
+type SCTPCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.MeterSender
+	periodic *reporter.Periodic
+}
+
+func NewSCTPCollector() *SCTPCollector {
+	return &SCTPCollector{}
+}
+
+func (s *SCTPCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if !ctx.Config.SCTP.Active {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.SCTP.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the SCTP period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	s.context = ctx
+	s.sender = reporter.NewMeterSender("SCTP associations", coreOperator.BackendOperator().GetConnection())
+	s.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "SCTP associations", period, s.flush)
+	return nil
+}
+
+func (s *SCTPCollector) Stop() {
+	s.periodic.Stop()
+}
+
+func (s *SCTPCollector) flush(ctx context.Context) error {
+	associations := s.context.ConnectionMgr.SnapshotSCTPAssociations()
+	if len(associations) == 0 {
+		return nil
+	}
+
+	collections := make([]*v3.MeterDataCollection, 0)
+	now := time.Now().UnixMilli()
+	for _, assoc := range associations {
+		for _, p := range s.context.ConnectionMgr.FindMonitoringProcesses(assoc.Connection.PID) {
+			labels := []*v3.Label{
+				{Name: "process_id", Value: p.ID()},
+				{Name: "association_id", Value: fmt.Sprintf("%d", assoc.ID)},
+				{Name: "state", Value: assoc.State},
+				{Name: "local_port", Value: fmt.Sprintf("%d", assoc.LocalPort)},
+				{Name: "remote_address", Value: sctpRemoteAddress(assoc)},
+				{Name: "remote_paths", Value: strings.Join(assoc.RemoteAddresses, ",")},
+			}
+			data := []*v3.MeterData{
+				reporter.SingleValueMeter(sctpTxQueueMeterName, labels, float64(assoc.TxQueue)),
+				reporter.SingleValueMeter(sctpRxQueueMeterName, labels, float64(assoc.RxQueue)),
+				reporter.SingleValueMeter(sctpInStreamsMeterName, labels, float64(assoc.InStreams)),
+				reporter.SingleValueMeter(sctpOutStreamsMeterName, labels, float64(assoc.OutStreams)),
+				reporter.SingleValueMeter(sctpRetransmitsMeterName, labels, float64(assoc.Retransmits)),
+				reporter.SingleValueMeter(sctpPathsMeterName, labels, float64(len(assoc.RemoteAddresses))),
+			}
+			data[0].Service = p.Entity().ServiceName
+			data[0].ServiceInstance = p.Entity().InstanceName
+			data[0].Timestamp = now
+			collections = append(collections, &v3.MeterDataCollection{MeterData: data})
+		}
+	}
+	return s.sender.Send(ctx, collections)
+}
+
+// sctpRemoteAddress is the primary remote address with the port
+func sctpRemoteAddress(assoc *common.SCTPAssociation) string {
+	if len(assoc.RemoteAddresses) == 0 {
+		return ""
+	}
+	return net.JoinHostPort(assoc.RemoteAddresses[0], fmt.Sprintf("%d", assoc.RemotePort))
+}
@@ -0,0 +1,210 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+)
+
+var (
+	// SidecarProcessFinderInterval is the interval to find the sidecar proxy processes
+	SidecarProcessFinderInterval = time.Second * 30
+	// SidecarOutboundCapturePort is the port of the sidecar proxy which accepts the intercepted outbound traffic of the application
+	SidecarOutboundCapturePort uint16 = 15001
+)
+
+var sidecarCollectInstance = NewSidecarCollector(time.Minute)
+
+// SidecarCollector is a collector for the sidecar proxies(Envoy) in the classic Istio scenario,
+// the sidecar shares the network namespace with the application, and intercepts the traffic through the iptables,
+// so the connections of the pods which have the sidecar are marked as the sidecar hop
+type SidecarCollector struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	alc    *common.AccessLogContext
+
+	// the network namespace and pid of the sidecar proxies
+	proxies map[uint64]int32
+	lock    sync.RWMutex
+
+	// the original destination of the application outbound connections, same as the "SO_ORIGINAL_DST" of the sidecar
+	originalDestinations    *cache.Expiring
+	originalDestinationTime time.Duration
+}
+
+type sidecarOriginalDestination struct {
+	ip   string
+	port uint16
+}
+
+func NewSidecarCollector(expireTime time.Duration) *SidecarCollector {
+	return &SidecarCollector{
+		proxies:                 make(map[uint64]int32),
+		originalDestinations:    cache.NewExpiring(),
+		originalDestinationTime: expireTime,
+	}
+}
+
+func (s *SidecarCollector) Start(_ *module.Manager, ctx *common.AccessLogContext) error {
+	s.ctx, s.cancel = context.WithCancel(ctx.RuntimeContext)
+	s.alc = ctx
+	ctx.ConnectionMgr.RegisterNewFlushListener(s)
+
+	if err := s.findSidecarProcesses(); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(SidecarProcessFinderInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.findSidecarProcesses(); err != nil {
+					log.Errorf("failed to find the sidecar proxy processes: %v", err)
+				}
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *SidecarCollector) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// OnConnectEvent correlate the intercepted outbound connection of the application and the accepted connection of the sidecar,
+// they have the same source address in the same network namespace
+func (s *SidecarCollector) OnConnectEvent(e *events.SocketConnectEvent, socket *ip.SocketPair) bool {
+	if e == nil || socket == nil {
+		return true
+	}
+	netNS, proxyPID := s.findProxy(e.PID)
+	if proxyPID == 0 {
+		return true
+	}
+	if int32(e.PID) != proxyPID && socket.Role == enums.ConnectionRoleClient {
+		s.originalDestinations.Set(s.buildOriginalDestinationKey(netNS, socket.SrcIP, socket.SrcPort),
+			&sidecarOriginalDestination{ip: socket.DestIP, port: socket.DestPort}, s.originalDestinationTime)
+	} else if int32(e.PID) == proxyPID {
+		s.fillOriginalDestination(netNS, socket)
+	}
+	return true
+}
+
+func (s *SidecarCollector) ReadyToFlushConnection(connection *common.ConnectionInfo, _ events.Event) {
+	if connection == nil || connection.Socket == nil {
+		return
+	}
+	socket := connection.Socket
+	if connection.MeshHop == common.MeshHopSidecarInbound && socket.OriginalDestIP == "" {
+		// the application connection could be received after the sidecar accepted
+		netNS, _ := s.findProxy(connection.PID)
+		s.fillOriginalDestination(netNS, socket)
+		return
+	}
+	if connection.MeshHop != common.MeshHopNone {
+		return
+	}
+	_, proxyPID := s.findProxy(connection.PID)
+	if proxyPID == 0 {
+		return
+	}
+	isProxy := int32(connection.PID) == proxyPID
+	switch {
+	case isProxy && socket.Role == enums.ConnectionRoleServer:
+		connection.MeshHop = common.MeshHopSidecarInbound
+	case isProxy && socket.Role == enums.ConnectionRoleClient:
+		connection.MeshHop = common.MeshHopSidecarOutbound
+	case socket.Role == enums.ConnectionRoleClient:
+		connection.MeshHop = common.MeshHopToSidecar
+	case socket.Role == enums.ConnectionRoleServer:
+		connection.MeshHop = common.MeshHopFromSidecar
+	}
+}
+
+// fillOriginalDestination fill the original destination of the intercepted outbound connection accepted by the sidecar
+func (s *SidecarCollector) fillOriginalDestination(netNS uint64, socket *ip.SocketPair) {
+	if netNS == 0 || socket.Role != enums.ConnectionRoleServer || socket.SrcPort != SidecarOutboundCapturePort {
+		return
+	}
+	dest, found := s.originalDestinations.Get(s.buildOriginalDestinationKey(netNS, socket.DestIP, socket.DestPort))
+	if !found {
+		return
+	}
+	socket.OriginalDestIP = dest.(*sidecarOriginalDestination).ip
+	socket.OriginalDestPort = dest.(*sidecarOriginalDestination).port
+}
+
+// findProxy find the sidecar proxy in the same network namespace with the process
+func (s *SidecarCollector) findProxy(pid uint32) (netNS uint64, proxyPID int32) {
+	if s.alc == nil {
+		return 0, 0
+	}
+	netNS = s.alc.ConnectionMgr.FindProcessNetNS(pid)
+	if netNS == 0 {
+		return 0, 0
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return netNS, s.proxies[netNS]
+}
+
+func (s *SidecarCollector) buildOriginalDestinationKey(netNS uint64, srcIP string, srcPort uint16) string {
+	return fmt.Sprintf("%d-%s:%d", netNS, srcIP, srcPort)
+}
+
+func (s *SidecarCollector) findSidecarProcesses() error {
+	pids, err := findIstioProxies(common.IstioProxyModeSidecar)
+	if err != nil {
+		return err
+	}
+	proxies := make(map[uint64]int32)
+	for _, pid := range pids {
+		netNS, err := host.NetworkNamespaceInode(pid)
+		if err != nil {
+			log.Warnf("failed to read the network namespace of the sidecar proxy process %d: %v", pid, err)
+			continue
+		}
+		proxies[netNS] = pid
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for netNS, pid := range proxies {
+		if s.proxies[netNS] != pid {
+			log.Infof("sidecar proxy process founded in current node, pid: %d", pid)
+		}
+	}
+	s.proxies = proxies
+	return nil
+}
@@ -0,0 +1,144 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const syscallLatencyLogKind = "ACCESS_LOG_SYSCALL_LATENCY"
+
+var syscallLatencyCollectInstance = NewSyscallLatencyCollector()
+
+// SyscallLatencyCollector periodically send the syscall timing breakdown of the connections as logs,
+// so the latency could be attributed to the kernel processing or the waiting on the network
+type SyscallLatencyCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.LogSender
+	periodic *reporter.Periodic
+}
+
+type syscallLatencyLogBody struct {
+	Role          string                   `json:"role"`
+	LocalAddress  string                   `json:"local_address"`
+	RemoteAddress string                   `json:"remote_address"`
+	Protocol      string                   `json:"protocol"`
+	Syscalls      []*syscallLatencyLogItem `json:"syscalls"`
+}
+
+type syscallLatencyLogItem struct {
+	Function   string `json:"function"`
+	Count      uint64 `json:"count"`
+	DurationUs uint64 `json:"duration_us"`
+	KernelUs   uint64 `json:"kernel_us"`
+	StackUs    uint64 `json:"stack_us"`
+	WaitUs     uint64 `json:"wait_us"`
+}
+
+func NewSyscallLatencyCollector() *SyscallLatencyCollector {
+	return &SyscallLatencyCollector{}
+}
+
+func (c *SyscallLatencyCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if !ctx.Config.SyscallLatency.Active {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.SyscallLatency.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the syscall latency period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.sender = reporter.NewLogSender("syscall latency logs", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "syscall latency logs", period, c.flush)
+	return nil
+}
+
+func (c *SyscallLatencyCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *SyscallLatencyCollector) flush(ctx context.Context) error {
+	latencies := c.context.ConnectionMgr.SnapshotSyscallLatency()
+	now := time.Now()
+	logs := make([]*logv3.LogData, 0, len(latencies))
+	for _, latency := range latencies {
+		logs = c.appendLogs(logs, latency, now)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *SyscallLatencyCollector) appendLogs(logs []*logv3.LogData, latency *common.ConnectionSyscallLatency,
+	now time.Time) []*logv3.LogData {
+	connection := latency.Connection
+	socket := connection.Socket
+	if socket == nil {
+		return logs
+	}
+	body := &syscallLatencyLogBody{
+		Role:          socket.Role.String(),
+		LocalAddress:  fmt.Sprintf("%s:%d", socket.SrcIP, socket.SrcPort),
+		RemoteAddress: fmt.Sprintf("%s:%d", socket.DestIP, socket.DestPort),
+		Protocol:      connection.RPCConnection.GetProtocol().String(),
+		Syscalls:      make([]*syscallLatencyLogItem, 0, len(latency.Syscalls)),
+	}
+	for _, s := range latency.Syscalls {
+		body.Syscalls = append(body.Syscalls, &syscallLatencyLogItem{
+			Function:   s.Function.String(),
+			Count:      s.Count,
+			DurationUs: s.Duration / uint64(time.Microsecond),
+			KernelUs:   s.KernelDuration() / uint64(time.Microsecond),
+			StackUs:    s.StackDuration / uint64(time.Microsecond),
+			WaitUs:     s.WaitDuration / uint64(time.Microsecond),
+		})
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the syscall latency log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: syscallLatencyLogKind},
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(connection.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       now.UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/tools/btf"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
 	"github.com/apache/skywalking-rover/pkg/tools/ssl"
 )
 
@@ -47,6 +48,10 @@ func NewTLSCollector() *TLSCollector {
 
 func (c *TLSCollector) Start(_ *module.Manager, context *common.AccessLogContext) error {
 	c.context = context
+	if !capability.Supported(capability.Uprobe) {
+		capability.Degrade("access_log", "TLS plaintext capture", capability.Uprobe)
+		return nil
+	}
 	context.ConnectionMgr.AddProcessListener(c)
 	return nil
 }
@@ -81,6 +86,12 @@ func (c *TLSCollector) addProcess(pid int32) {
 	register.Envoy(nil, c.context.BPF.OpensslWrite, c.context.BPF.OpensslWriteRet,
 		c.context.BPF.OpensslRead, c.context.BPF.OpensslReadRet)
 
+	register.BoringSSL(c.context.BPF.OpensslWrite, c.context.BPF.OpensslWriteRet,
+		c.context.BPF.OpensslRead, c.context.BPF.OpensslReadRet)
+
+	register.Rustls(c.context.BPF.RustlsProcessMap, c.context.BPF.RustlsWrite, c.context.BPF.RustlsWriteRet,
+		c.context.BPF.RustlsRead, c.context.BPF.RustlsReadRet)
+
 	register.GoTLS(c.context.BPF.GoTlsArgsSymaddrMap, c.context.BPF.GoTlsWrite, c.context.BPF.GoTlsWriteRet,
 		c.context.BPF.GoTlsRead, c.context.BPF.GoTlsReadRet)
 
@@ -97,4 +108,5 @@ func (c *TLSCollector) OnProcessRemoved(pid int32) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	delete(c.monitoredProcesses, pid)
+	_ = c.context.BPF.RustlsProcessMap.Delete(uint32(pid))
 }
@@ -0,0 +1,151 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+const (
+	tlsHandshakeLogKind = "ACCESS_LOG_TLS_HANDSHAKE"
+	// the max duration to wait the connection of the handshake been built
+	tlsHandshakeRetainTime = time.Minute
+)
+
+var tlsHandshakeCollectInstance = NewTLSHandshakeCollector()
+
+// TLSHandshakeCollector send the TLS handshake metadata of the connections as logs,
+// for auditing the weak TLS usage and the SNI based routing without decrypting
+type TLSHandshakeCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.LogSender
+	periodic *reporter.Periodic
+	retainer *common.Retainer[*common.TLSHandshake]
+}
+
+type tlsHandshakeLogBody struct {
+	ServerName    string   `json:"server_name,omitempty"`
+	Version       string   `json:"version"`
+	CipherSuite   string   `json:"cipher_suite"`
+	ALPN          string   `json:"alpn,omitempty"`
+	ALPNOffered   []string `json:"alpn_offered,omitempty"`
+	Transport     string   `json:"transport"`
+	Role          string   `json:"role"`
+	LocalAddress  string   `json:"local_address"`
+	RemoteAddress string   `json:"remote_address"`
+}
+
+func NewTLSHandshakeCollector() *TLSHandshakeCollector {
+	return &TLSHandshakeCollector{}
+}
+
+func (c *TLSHandshakeCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if ctx.TLSHandshakes == nil {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Flush.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the flush period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	c.context = ctx
+	c.retainer = common.NewRetainer(tlsHandshakeRetainTime, func(handshake *common.TLSHandshake) time.Time {
+		return handshake.CreateTime
+	})
+	c.sender = reporter.NewLogSender("TLS handshake logs", coreOperator.BackendOperator().GetConnection())
+	c.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "TLS handshake logs", period, c.flush)
+	return nil
+}
+
+func (c *TLSHandshakeCollector) Stop() {
+	c.periodic.Stop()
+}
+
+func (c *TLSHandshakeCollector) flush(ctx context.Context) error {
+	handshakes := c.retainer.Merge(c.context.TLSHandshakes.Swap())
+	logs := make([]*logv3.LogData, 0, len(handshakes))
+	for _, handshake := range handshakes {
+		connection := c.context.ConnectionMgr.FindByID(handshake.ConnectionID, handshake.RandomID)
+		if connection == nil {
+			// the connection may not be built yet, so check it in the next period
+			c.retainer.Retain(handshake)
+			continue
+		}
+		logs = c.appendLogs(logs, connection, handshake)
+	}
+	return c.sender.Send(ctx, logs)
+}
+
+func (c *TLSHandshakeCollector) appendLogs(logs []*logv3.LogData, connection *common.ConnectionInfo,
+	handshake *common.TLSHandshake) []*logv3.LogData {
+	socket := connection.Socket
+	body := &tlsHandshakeLogBody{
+		ServerName:    handshake.ServerName,
+		Version:       ssl.TLSVersionName(handshake.Version),
+		CipherSuite:   tls.CipherSuiteName(handshake.CipherSuite),
+		ALPN:          handshake.ALPN,
+		ALPNOffered:   handshake.ALPNOffered,
+		Transport:     "tcp",
+		Role:          socket.Role.String(),
+		LocalAddress:  fmt.Sprintf("%s:%d", socket.SrcIP, socket.SrcPort),
+		RemoteAddress: fmt.Sprintf("%s:%d", socket.DestIP, socket.DestPort),
+	}
+	if handshake.QUIC {
+		body.Transport = "quic"
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		log.Warnf("format the TLS handshake log body failure: %v", err)
+		return logs
+	}
+
+	tags := []*commonv3.KeyStringValuePair{
+		{Key: "LOG_KIND", Value: tlsHandshakeLogKind},
+	}
+	if handshake.ServerName != "" {
+		tags = append(tags, &commonv3.KeyStringValuePair{Key: "server_name", Value: handshake.ServerName})
+	}
+	for _, p := range c.context.ConnectionMgr.FindMonitoringProcesses(connection.PID) {
+		logs = append(logs, &logv3.LogData{
+			Timestamp:       host.Time(handshake.Time).UnixMilli(),
+			Service:         p.Entity().ServiceName,
+			ServiceInstance: p.Entity().InstanceName,
+			Layer:           p.Entity().Layer,
+			Tags:            &logv3.LogTags{Data: tags},
+			Body: &logv3.LogDataBody{
+				Type:    "json",
+				Content: &logv3.LogDataBody_Json{Json: &logv3.JSONLog{Json: string(bodyJSON)}},
+			},
+		})
+	}
+	return logs
+}
@@ -0,0 +1,104 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	topologyConnectionsMeterName = "access_log_topology_connections"
+	topologyWriteBytesMeterName  = "access_log_topology_write_bytes"
+	topologyReadBytesMeterName   = "access_log_topology_read_bytes"
+)
+
+var topologyCollectInstance = NewTopologyCollector()
+
+// TopologyCollector periodically export the snapshot of the connections in the connection manager,
+// it provides the L4 topology even when the protocol analyze is skipped
+type TopologyCollector struct {
+	context  *common.AccessLogContext
+	sender   *reporter.MeterSender
+	periodic *reporter.Periodic
+}
+
+func NewTopologyCollector() *TopologyCollector {
+	return &TopologyCollector{}
+}
+
+func (t *TopologyCollector) Start(mgr *module.Manager, ctx *common.AccessLogContext) error {
+	if !ctx.Config.Topology.Active {
+		return nil
+	}
+	period, err := time.ParseDuration(ctx.Config.Topology.Period)
+	if err != nil {
+		return fmt.Errorf("parsing the topology period failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	t.context = ctx
+	t.sender = reporter.NewMeterSender("topology snapshot", coreOperator.BackendOperator().GetConnection())
+	t.periodic = reporter.StartPeriodic(ctx.RuntimeContext, "topology snapshot", period, t.flush)
+	return nil
+}
+
+func (t *TopologyCollector) Stop() {
+	t.periodic.Stop()
+}
+
+func (t *TopologyCollector) flush(ctx context.Context) error {
+	edges := t.context.ConnectionMgr.SnapshotTopology()
+	if len(edges) == 0 {
+		return nil
+	}
+
+	collections := make([]*v3.MeterDataCollection, 0)
+	now := time.Now().UnixMilli()
+	for key, edge := range edges {
+		for _, p := range t.context.ConnectionMgr.FindMonitoringProcesses(key.PID) {
+			labels := []*v3.Label{
+				{Name: "process_id", Value: p.ID()},
+				{Name: "role", Value: key.Role},
+				{Name: "state", Value: key.State},
+				{Name: "remote_service", Value: key.RemoteService},
+				{Name: "remote_address", Value: key.RemoteAddress},
+				{Name: "encryption", Value: key.Encryption},
+				{Name: "mesh_revision", Value: key.MeshRevision},
+				{Name: "mesh_hop", Value: key.MeshHop},
+			}
+			data := []*v3.MeterData{
+				reporter.SingleValueMeter(topologyConnectionsMeterName, labels, float64(edge.Connections)),
+				reporter.SingleValueMeter(topologyWriteBytesMeterName, labels, float64(edge.WriteBytes)),
+				reporter.SingleValueMeter(topologyReadBytesMeterName, labels, float64(edge.ReadBytes)),
+			}
+			data[0].Service = p.Entity().ServiceName
+			data[0].ServiceInstance = p.Entity().InstanceName
+			data[0].Timestamp = now
+			collections = append(collections, &v3.MeterDataCollection{MeterData: data})
+		}
+	}
+	return t.sender.Send(ctx, collections)
+}
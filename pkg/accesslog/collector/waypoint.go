@@ -0,0 +1,173 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
+)
+
+// WaypointProcessFinderInterval is the interval to find the waypoint proxy processes
+var WaypointProcessFinderInterval = time.Second * 30
+
+var waypointCollectInstance = NewWaypointCollector()
+
+// WaypointCollector is a collector for the waypoint proxies(Envoy) in the Ambient Istio scenario,
+// the L7 traffic of the workloads is routed through the waypoint proxies instead of the sidecar,
+// so the connections which pass through the waypoint proxies are marked as the waypoint hop
+type WaypointCollector struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	proxies   map[int32]*waypointProxy
+	addresses map[string]*waypointProxy
+	lock      sync.RWMutex
+}
+
+// waypointProxy is a waypoint Envoy process running in the current node
+type waypointProxy struct {
+	pid       int32
+	addresses []string
+}
+
+func NewWaypointCollector() *WaypointCollector {
+	return &WaypointCollector{
+		proxies:   make(map[int32]*waypointProxy),
+		addresses: make(map[string]*waypointProxy),
+	}
+}
+
+func (w *WaypointCollector) Start(_ *module.Manager, ctx *common.AccessLogContext) error {
+	w.ctx, w.cancel = context.WithCancel(ctx.RuntimeContext)
+	ctx.ConnectionMgr.RegisterNewFlushListener(w)
+
+	if err := w.findWaypointProcesses(); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(WaypointProcessFinderInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.findWaypointProcesses(); err != nil {
+					log.Errorf("failed to find the waypoint proxy processes: %v", err)
+				}
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *WaypointCollector) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+func (w *WaypointCollector) ReadyToFlushConnection(connection *common.ConnectionInfo, _ events.Event) {
+	if connection == nil || connection.Socket == nil || connection.MeshHop != common.MeshHopNone {
+		return
+	}
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	if len(w.proxies) == 0 {
+		return
+	}
+	role := connection.Socket.Role
+	// the connections of the waypoint proxy itself
+	if w.proxies[int32(connection.PID)] != nil {
+		if role == enums.ConnectionRoleServer {
+			connection.MeshHop = common.MeshHopWaypointInbound
+		} else if role == enums.ConnectionRoleClient {
+			connection.MeshHop = common.MeshHopWaypointOutbound
+		}
+		return
+	}
+	// the connections between the workload and waypoint proxy, the ztunnel redirects the traffic to the waypoint proxy,
+	// so the real destination address of the ztunnel attachment would be the waypoint proxy
+	remote := connection.Socket.DestIP
+	if realDest := connection.RPCConnection.GetAttachment().GetZTunnel().GetRealDestinationIp(); realDest != "" {
+		remote = realDest
+	}
+	if w.addresses[remote] == nil {
+		return
+	}
+	if role == enums.ConnectionRoleClient {
+		connection.MeshHop = common.MeshHopToWaypoint
+	} else if role == enums.ConnectionRoleServer {
+		connection.MeshHop = common.MeshHopFromWaypoint
+	}
+}
+
+func (w *WaypointCollector) findWaypointProcesses() error {
+	pids, err := findIstioProxies(common.IstioProxyModeWaypoint)
+	if err != nil {
+		return err
+	}
+	proxies := make(map[int32]*waypointProxy)
+	for _, pid := range pids {
+		if exist := w.findProxy(pid); exist != nil {
+			proxies[pid] = exist
+			continue
+		}
+		environ, err := procfs.ReadFile(pid, "environ")
+		if err != nil {
+			log.Warnf("failed to read the environments of the waypoint proxy process %d: %v", pid, err)
+			continue
+		}
+		proxy := &waypointProxy{
+			pid:       pid,
+			addresses: common.ParseWaypointAddresses(strings.Split(string(environ), "\x00")),
+		}
+		log.Infof("waypoint proxy process founded in current node, pid: %d, addresses: %v", pid, proxy.addresses)
+		proxies[pid] = proxy
+	}
+
+	addresses := make(map[string]*waypointProxy)
+	for _, proxy := range proxies {
+		for _, address := range proxy.addresses {
+			addresses[address] = proxy
+		}
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for pid := range w.proxies {
+		if proxies[pid] == nil {
+			log.Infof("detected waypoint proxy process is not running, pid: %d", pid)
+		}
+	}
+	w.proxies, w.addresses = proxies, addresses
+	return nil
+}
+
+func (w *WaypointCollector) findProxy(pid int32) *waypointProxy {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.proxies[pid]
+}
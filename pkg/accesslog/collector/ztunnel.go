@@ -20,18 +20,23 @@ package collector
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
-	"k8s.io/apimachinery/pkg/util/cache"
-
 	"github.com/apache/skywalking-rover/pkg/accesslog/common"
 	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/tools/elf"
 	"github.com/apache/skywalking-rover/pkg/tools/enums"
 	"github.com/apache/skywalking-rover/pkg/tools/host"
 	"github.com/apache/skywalking-rover/pkg/tools/ip"
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
 
 	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
 
@@ -44,57 +49,85 @@ var (
 	// ZTunnelTrackBoundSymbolPrefix is the prefix of the symbol name to track outbound connections in ztunnel process
 	// ztunnel::proxy::connection_manager::ConnectionManager::track_outbound
 	ZTunnelTrackBoundSymbolPrefix = "_ZN7ztunnel5proxy18connection_manager17ConnectionManager14track_outbound"
+	// ZTunnelAdminTimeout is the timeout of requesting the ztunnel admin interface
+	ZTunnelAdminTimeout = time.Second * 10
 )
 
-var zTunnelCollectInstance = NewZTunnelCollector(time.Minute)
+var ztunnelLog = logger.GetLogger("access_log", "collector", "ztunnel")
+
+var zTunnelCollectInstance = NewZTunnelCollector(time.Minute, 100_000)
 
-// ZTunnelCollector is a collector for ztunnel process in the Ambient Istio scenario
+// ZTunnelCollector is a collector for ztunnel processes in the Ambient Istio scenario,
+// multiple ztunnel processes could be running in the same node when the cluster have multiple mesh revisions
 type ZTunnelCollector struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	alc    *common.AccessLogContext
 
-	collectingProcess       *process.Process
-	ipMappingCache          *cache.Expiring
+	proxies                 map[int32]*zTunnelProxy
+	proxiesLock             sync.RWMutex
 	ipMappingExpireDuration time.Duration
+	ipMappingMaxEntries     int
+}
+
+// zTunnelProxy is a collecting ztunnel process, the mapping cache is independent for each proxy
+type zTunnelProxy struct {
+	process *process.Process
+	// identity is the mesh and revision of the proxy
+	identity       string
+	ipMappingCache *zTunnelMappingCache
 }
 
-func NewZTunnelCollector(expireTime time.Duration) *ZTunnelCollector {
+func NewZTunnelCollector(expireTime time.Duration, maxEntries int) *ZTunnelCollector {
 	return &ZTunnelCollector{
-		ipMappingCache:          cache.NewExpiring(),
+		proxies:                 make(map[int32]*zTunnelProxy),
 		ipMappingExpireDuration: expireTime,
+		ipMappingMaxEntries:     maxEntries,
 	}
 }
 
 func (z *ZTunnelCollector) Start(_ *module.Manager, ctx *common.AccessLogContext) error {
 	z.ctx, z.cancel = context.WithCancel(ctx.RuntimeContext)
 	z.alc = ctx
+	if conf := ctx.Config.ZTunnel; conf.MappingExpire != "" {
+		expire, err := time.ParseDuration(conf.MappingExpire)
+		if err != nil {
+			return fmt.Errorf("parse the ztunnel mapping expire error: %v", err)
+		}
+		z.ipMappingExpireDuration = expire
+	}
+	if ctx.Config.ZTunnel.MappingMaxEntries > 0 {
+		z.ipMappingMaxEntries = ctx.Config.ZTunnel.MappingMaxEntries
+	}
 	ctx.ConnectionMgr.RegisterNewFlushListener(z)
+	selfobs.RegisterGauge(selfobs.ZTunnelMappingCacheSize, "", "", z.mappingCacheSize)
 
-	err := z.findZTunnelProcessAndCollect()
+	err := z.findZTunnelProcessesAndCollect()
 	if err != nil {
 		return err
 	}
 
-	if z.collectingProcess == nil {
-		return nil
-	}
-
 	ctx.BPF.ReadEventAsync(ctx.BPF.ZtunnelLbSocketMappingEventQueue, func(data interface{}) {
 		event := data.(*events.ZTunnelSocketMappingEvent)
-		localIP := z.convertBPFIPToString(event.OriginalSrcIP)
+		localIP := ip.ParseIPV6(event.OriginalSrcIP)
 		localPort := event.OriginalSrcPort
-		remoteIP := z.convertBPFIPToString(event.OriginalDestIP)
+		remoteIP := ip.ParseIPV6(event.OriginalDestIP)
 		remotePort := event.OriginalDestPort
-		lbIP := z.convertBPFIPToString(event.LoadBalancedDestIP)
-		log.Debugf("received ztunnel lb socket mapping event: %s:%d -> %s:%d, lb: %s", localIP, localPort, remoteIP, remotePort, lbIP)
+		lbIP := ip.ParseIPV6(event.LoadBalancedDestIP)
+		ztunnelLog.Debugf("received ztunnel lb socket mapping event: %s:%d -> %s:%d, lb: %s, pid: %d",
+			localIP, localPort, remoteIP, remotePort, lbIP, event.PID)
 
+		proxy := z.findProxy(int32(event.PID))
+		if proxy == nil {
+			ztunnelLog.Debugf("the ztunnel process %d is not collecting, ignore the socket mapping event", event.PID)
+			return
+		}
 		key := z.buildIPMappingCacheKey(localIP, int(localPort), remoteIP, int(remotePort))
-		z.ipMappingCache.Set(key, &ZTunnelLoadBalanceAddress{
+		proxy.ipMappingCache.Set(key, &ZTunnelLoadBalanceAddress{
 			IP:   lbIP,
 			Port: event.LoadBalancedDestPort,
 			From: v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_OUTBOUND_FUNC,
-		}, z.ipMappingExpireDuration)
+		})
 	}, func() interface{} {
 		return &events.ZTunnelSocketMappingEvent{}
 	})
@@ -103,9 +136,9 @@ func (z *ZTunnelCollector) Start(_ *module.Manager, ctx *common.AccessLogContext
 		for {
 			select {
 			case <-ticker.C:
-				err := z.findZTunnelProcessAndCollect()
+				err := z.findZTunnelProcessesAndCollect()
 				if err != nil {
-					log.Error("failed to find and collect ztunnel process: ", err)
+					ztunnelLog.Error("failed to find and collect ztunnel process: ", err)
 				}
 			case <-z.ctx.Done():
 				ticker.Stop()
@@ -117,43 +150,55 @@ func (z *ZTunnelCollector) Start(_ *module.Manager, ctx *common.AccessLogContext
 }
 
 func (z *ZTunnelCollector) OnConnectEvent(e *events.SocketConnectEvent, s *ip.SocketPair) bool {
-	if z.collectingProcess != nil && e != nil && s != nil && uint32(z.collectingProcess.Pid) == e.PID &&
-		s.Role == enums.ConnectionRoleClient {
+	if e == nil || s == nil {
+		return true
+	}
+	proxy := z.findProxy(int32(e.PID))
+	if proxy != nil && s.Role == enums.ConnectionRoleClient {
 		// must be the client side(outbound) connect
 		// revert the source and dest for the workload application accept
 		key := z.buildIPMappingCacheKey(s.DestIP, int(s.DestPort), s.SrcIP, int(s.SrcPort))
-		z.ipMappingCache.Set(key, &ZTunnelLoadBalanceAddress{
+		proxy.ipMappingCache.Set(key, &ZTunnelLoadBalanceAddress{
 			From: v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_INBOUND_FUNC,
-		}, z.ipMappingExpireDuration)
-		log.Debugf("found the ztunnel outbound connection, "+
-			"connection ID: %d, randomID: %d, pid: %d, fd: %d, role: %s, local: %s:%d, remote: %s:%d",
-			e.ConID, e.RandomID, e.PID, e.SocketFD, enums.ConnectionRole(e.Role), s.SrcIP, s.SrcPort, s.DestIP, s.DestPort)
+		})
+		ztunnelLog.Debugf("found the ztunnel outbound connection, "+
+			"connection ID: %d, randomID: %d, pid: %d, fd: %d, role: %s, local: %s:%d, remote: %s:%d, ztunnel: %s",
+			e.ConID, e.RandomID, e.PID, e.SocketFD, enums.ConnectionRole(e.Role), s.SrcIP, s.SrcPort, s.DestIP, s.DestPort,
+			proxy.identity)
 		return false
 	}
 	return true
 }
 
 func (z *ZTunnelCollector) ReadyToFlushConnection(connection *common.ConnectionInfo, _ events.Event) {
-	if connection == nil || connection.Socket == nil || connection.RPCConnection == nil || connection.RPCConnection.Attachment != nil ||
-		z.ipMappingCache.Len() == 0 {
+	if connection == nil || connection.Socket == nil || connection.RPCConnection == nil || connection.RPCConnection.Attachment != nil {
+		return
+	}
+	if z.attachHBONETunnel(connection) {
 		return
 	}
 	key := z.buildIPMappingCacheKey(connection.Socket.SrcIP, int(connection.Socket.SrcPort),
 		connection.Socket.DestIP, int(connection.Socket.DestPort))
-	lbIPObj, found := z.ipMappingCache.Get(key)
-	if !found {
-		log.Debugf("there no ztunnel mapped IP address found for connection ID: %d, random ID: %d",
+	address, proxy := z.findIPMapping(key)
+	if address == nil {
+		ztunnelLog.Debugf("there no ztunnel mapped IP address found for connection ID: %d, random ID: %d",
 			connection.ConnectionID, connection.RandomID)
 		return
 	}
-	address := lbIPObj.(*ZTunnelLoadBalanceAddress)
-	log.Debugf("found the ztunnel load balanced IP for the connection: %s, connectionID: %d, randomID: %d",
-		address.String(), connection.ConnectionID, connection.RandomID)
+	ztunnelLog.Debugf("found the ztunnel load balanced IP for the connection: %s, connectionID: %d, randomID: %d, ztunnel: %s",
+		address.String(), connection.ConnectionID, connection.RandomID, proxy.identity)
 	securityPolicy := v3.ZTunnelAttachmentSecurityPolicy_NONE
 	// if the target port is 15008, this mean ztunnel have use mTLS
-	if address.From == v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_OUTBOUND_FUNC && address.Port == 15008 {
+	if address.From == v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_OUTBOUND_FUNC && address.Port == common.HBONEPort {
+		securityPolicy = v3.ZTunnelAttachmentSecurityPolicy_MTLS
+	}
+	if stream := z.findHBONEStream(connection, address); stream != nil {
+		ztunnelLog.Debugf("the connection is carried by the HBONE tunnel, connection ID: %d, random ID: %d, "+
+			"tunnel connection ID: %d, tunnel random ID: %d, destination: %s:%d", connection.ConnectionID, connection.RandomID,
+			stream.TunnelConnectionID, stream.TunnelRandomID, stream.DestIP, stream.DestPort)
 		securityPolicy = v3.ZTunnelAttachmentSecurityPolicy_MTLS
 	}
+	connection.MeshRevision = proxy.identity
 	connection.RPCConnection.Attachment = &v3.ConnectionAttachment{
 		Environment: &v3.ConnectionAttachment_ZTunnel{
 			ZTunnel: &v3.ZTunnelAttachmentEnvironment{
@@ -165,8 +210,73 @@ func (z *ZTunnelCollector) ReadyToFlushConnection(connection *common.ConnectionI
 	}
 }
 
-func (z *ZTunnelCollector) convertBPFIPToString(ipAddr uint32) string {
-	return fmt.Sprintf("%d.%d.%d.%d", ipAddr>>24, ipAddr>>16&0xff, ipAddr>>8&0xff, ipAddr&0xff)
+// attachHBONETunnel attach the real destination from the CONNECT authority to the HBONE tunnel connection accepted by the ztunnel,
+// so the tunnel is shown as the end-to-end connection rather than to the ztunnel
+func (z *ZTunnelCollector) attachHBONETunnel(connection *common.ConnectionInfo) bool {
+	if z.alc.HBONE == nil || connection.Socket.SrcPort != common.HBONEPort {
+		return false
+	}
+	stream := z.alc.HBONE.FindByTunnel(connection.ConnectionID, connection.RandomID)
+	if stream == nil {
+		return false
+	}
+	connection.RPCConnection.Attachment = &v3.ConnectionAttachment{
+		Environment: &v3.ConnectionAttachment_ZTunnel{
+			ZTunnel: &v3.ZTunnelAttachmentEnvironment{
+				RealDestinationIp: stream.DestIP,
+				By:                v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_INBOUND_FUNC,
+				SecurityPolicy:    v3.ZTunnelAttachmentSecurityPolicy_MTLS,
+			},
+		},
+	}
+	return true
+}
+
+// findHBONEStream find the HBONE stream which carrying the workload connection,
+// the inbound connection is accepted from the source workload, and the outbound connection is sent to the load balanced address
+func (z *ZTunnelCollector) findHBONEStream(connection *common.ConnectionInfo, address *ZTunnelLoadBalanceAddress) *common.HBONEStream {
+	if z.alc.HBONE == nil {
+		return nil
+	}
+	if address.From == v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_INBOUND_FUNC {
+		return z.alc.HBONE.Find(connection.Socket.SrcIP, connection.Socket.DestIP)
+	}
+	return z.alc.HBONE.Find(address.IP, connection.Socket.SrcIP)
+}
+
+func (z *ZTunnelCollector) findProxy(pid int32) *zTunnelProxy {
+	z.proxiesLock.RLock()
+	defer z.proxiesLock.RUnlock()
+	return z.proxies[pid]
+}
+
+// findIPMapping find the mapped address from the caches of all proxies, the connection only passes through one of them
+func (z *ZTunnelCollector) findIPMapping(key string) (*ZTunnelLoadBalanceAddress, *zTunnelProxy) {
+	z.proxiesLock.RLock()
+	defer z.proxiesLock.RUnlock()
+	for _, proxy := range z.proxies {
+		if proxy.ipMappingCache.Len() == 0 {
+			continue
+		}
+		if address := proxy.ipMappingCache.Get(key); address != nil {
+			selfobs.Increase(selfobs.ZTunnelMappingCache, "event", "hit", 1)
+			return address, proxy
+		}
+	}
+	if len(z.proxies) > 0 {
+		selfobs.Increase(selfobs.ZTunnelMappingCache, "event", "miss", 1)
+	}
+	return nil, nil
+}
+
+func (z *ZTunnelCollector) mappingCacheSize() float64 {
+	z.proxiesLock.RLock()
+	defer z.proxiesLock.RUnlock()
+	var size int
+	for _, proxy := range z.proxies {
+		size += proxy.ipMappingCache.Len()
+	}
+	return float64(size)
 }
 
 func (z *ZTunnelCollector) buildIPMappingCacheKey(localIP string, localPort int, remoteIP string, remotePort int) string {
@@ -179,45 +289,72 @@ func (z *ZTunnelCollector) Stop() {
 	}
 }
 
-func (z *ZTunnelCollector) findZTunnelProcessAndCollect() error {
-	if z.collectingProcess != nil {
-		running, err := z.collectingProcess.IsRunning()
-		if err == nil && running {
-			// already collecting the process
-			log.Debugf("found the ztunnel process and collecting ztunnel data from pid: %d", z.collectingProcess.Pid)
-			return nil
-		}
-		log.Warnf("detected ztunnel process is not running, should re-scan process to find and collect it")
-	}
+func (z *ZTunnelCollector) findZTunnelProcessesAndCollect() error {
+	z.removeStoppedProxies()
 
-	processes, err := process.Processes()
+	processes, err := procfs.Processes()
 	if err != nil {
 		return err
 	}
-	var zTunnelProcess *process.Process
 	for _, p := range processes {
-		name, err := p.Exe()
-		if err != nil {
+		name, err := procfs.Exe(p.Pid)
+		if err != nil || !strings.HasSuffix(name, "/ztunnel") {
 			continue
 		}
-		if strings.HasSuffix(name, "/ztunnel") {
-			zTunnelProcess = p
-			break
+		if z.findProxy(p.Pid) != nil {
+			// already collecting the process
+			ztunnelLog.Debugf("found the ztunnel process and collecting ztunnel data from pid: %d", p.Pid)
+			continue
 		}
+
+		mappingCache, err := newZTunnelMappingCache(z.ipMappingMaxEntries, z.ipMappingExpireDuration)
+		if err != nil {
+			return err
+		}
+		proxy := &zTunnelProxy{
+			process:        p,
+			identity:       z.readProxyIdentity(p.Pid),
+			ipMappingCache: mappingCache,
+		}
+		ztunnelLog.Infof("ztunnel process founded in current node, pid: %d, mesh revision: %s", p.Pid, proxy.identity)
+		if err := z.collectZTunnelProcess(proxy); err != nil {
+			return err
+		}
+		z.proxiesLock.Lock()
+		z.proxies[p.Pid] = proxy
+		z.proxiesLock.Unlock()
 	}
+	return nil
+}
 
-	if zTunnelProcess == nil {
-		log.Debugf("ztunnel process not found is current node")
-		return nil
+func (z *ZTunnelCollector) removeStoppedProxies() {
+	z.proxiesLock.Lock()
+	defer z.proxiesLock.Unlock()
+	for pid, proxy := range z.proxies {
+		if running, err := proxy.process.IsRunning(); err == nil && running {
+			continue
+		}
+		ztunnelLog.Warnf("detected ztunnel process is not running, stop collecting it, pid: %d, mesh revision: %s", pid, proxy.identity)
+		if err := z.alc.BPF.ZtunnelProcessControl.Delete(uint32(pid)); err != nil {
+			ztunnelLog.Warnf("failed to delete the ztunnel process %d in the BPF: %v", pid, err)
+		}
+		delete(z.proxies, pid)
 	}
+}
 
-	log.Infof("ztunnel process founded in current node, pid: %d", zTunnelProcess.Pid)
-	z.collectingProcess = zTunnelProcess
-	return z.collectZTunnelProcess(zTunnelProcess)
+// readProxyIdentity read the mesh and revision of the ztunnel process through its environments
+func (z *ZTunnelCollector) readProxyIdentity(pid int32) string {
+	environ, err := os.ReadFile(host.GetHostProcInHost(fmt.Sprintf("%d/environ", pid)))
+	if err != nil {
+		ztunnelLog.Warnf("failed to read the environments of the ztunnel process %d: %v", pid, err)
+		return common.ZTunnelDefaultRevision
+	}
+	return common.ParseZTunnelIdentity(strings.Split(string(environ), "\x00"))
 }
 
-func (z *ZTunnelCollector) collectZTunnelProcess(p *process.Process) error {
-	pidExeFile := host.GetHostProcInHost(fmt.Sprintf("%d/exe", p.Pid))
+func (z *ZTunnelCollector) collectZTunnelProcess(proxy *zTunnelProxy) error {
+	pid := proxy.process.Pid
+	pidExeFile := host.GetHostProcInHost(fmt.Sprintf("%d/exe", pid))
 	elfFile, err := elf.NewFile(pidExeFile)
 	if err != nil {
 		return fmt.Errorf("read executable file error: %v", err)
@@ -232,13 +369,62 @@ func (z *ZTunnelCollector) collectZTunnelProcess(p *process.Process) error {
 	uprobeFile := z.alc.BPF.OpenUProbeExeFile(pidExeFile)
 	uprobeFile.AddLink(trackBoundSymbol[0].Name, z.alc.BPF.ConnectionManagerTrackOutbound, nil)
 
-	// setting the ztunnel pid in the BPF
-	if err = z.alc.BPF.ZtunnelProcessPid.Set(p.Pid); err != nil {
-		return fmt.Errorf("failed to set ztunnel process pid in the BPF: %v", err)
+	// adding the ztunnel pid in the BPF
+	if err = z.alc.BPF.ZtunnelProcessControl.Put(uint32(pid), uint32(1)); err != nil {
+		return fmt.Errorf("failed to add ztunnel process pid in the BPF: %v", err)
+	}
+	if z.alc.Config.ZTunnel.Prewarm {
+		go z.prewarmIPMappingCache(proxy)
 	}
 	return nil
 }
 
+// prewarmIPMappingCache populate the IP mapping cache from the outbound connections of the ztunnel admin config dump,
+// because the uprobe cannot observe the connections which established before attached
+func (z *ZTunnelCollector) prewarmIPMappingCache(proxy *zTunnelProxy) {
+	pid := proxy.process.Pid
+	client := &http.Client{
+		Timeout: ZTunnelAdminTimeout,
+		Transport: &http.Transport{
+			// the admin port only bound on the localhost of the ztunnel pod
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return host.DialInNetworkNamespace(ctx, pid, network, addr)
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+	request, err := http.NewRequestWithContext(z.ctx, http.MethodGet,
+		fmt.Sprintf("http://127.0.0.1:%d/config_dump", z.alc.Config.ZTunnel.AdminPort), http.NoBody)
+	if err != nil {
+		ztunnelLog.Warnf("failed to build the ztunnel config dump request: %v", err)
+		return
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		ztunnelLog.Warnf("failed to request the ztunnel config dump, pid: %d, error: %v", pid, err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		ztunnelLog.Warnf("failed to request the ztunnel config dump, pid: %d, status: %s", pid, response.Status)
+		return
+	}
+	connections, err := common.ParseZTunnelOutboundConnections(response.Body)
+	if err != nil {
+		ztunnelLog.Warnf("failed to parse the ztunnel config dump, pid: %d, error: %v", pid, err)
+		return
+	}
+	for _, c := range connections {
+		key := z.buildIPMappingCacheKey(c.SrcIP, int(c.SrcPort), c.OriginalDstIP, int(c.OriginalDstPort))
+		proxy.ipMappingCache.Set(key, &ZTunnelLoadBalanceAddress{
+			IP:   c.ActualDstIP,
+			Port: c.ActualDstPort,
+			From: v3.ZTunnelAttachmentEnvironmentDetectBy_ZTUNNEL_OUTBOUND_FUNC,
+		})
+	}
+	ztunnelLog.Infof("pre-warmed %d ztunnel IP mappings from the config dump, pid: %d, mesh revision: %s", len(connections), pid, proxy.identity)
+}
+
 type ZTunnelLoadBalanceAddress struct {
 	IP   string
 	Port uint16
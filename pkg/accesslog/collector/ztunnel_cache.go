@@ -0,0 +1,74 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// zTunnelMappingCache is the IP mapping cache of a ztunnel process, the mappings are expired after the expire duration,
+// and the least recently used mapping is evicted when reached the max entries
+type zTunnelMappingCache struct {
+	cache  *lru.Cache
+	expire time.Duration
+}
+
+type zTunnelMappingEntry struct {
+	address  *ZTunnelLoadBalanceAddress
+	expireAt time.Time
+}
+
+func newZTunnelMappingCache(maxEntries int, expire time.Duration) (*zTunnelMappingCache, error) {
+	cache, err := lru.NewWithEvict(maxEntries, func(_, value interface{}) {
+		// the expired mappings are removed when reading or evicted, only the alive mappings are counted as the eviction
+		if time.Now().After(value.(*zTunnelMappingEntry).expireAt) {
+			selfobs.Increase(selfobs.ZTunnelMappingCache, "event", "expired", 1)
+			return
+		}
+		selfobs.Increase(selfobs.ZTunnelMappingCache, "event", "eviction", 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &zTunnelMappingCache{cache: cache, expire: expire}, nil
+}
+
+func (c *zTunnelMappingCache) Set(key string, address *ZTunnelLoadBalanceAddress) {
+	c.cache.Add(key, &zTunnelMappingEntry{address: address, expireAt: time.Now().Add(c.expire)})
+}
+
+func (c *zTunnelMappingCache) Get(key string) *ZTunnelLoadBalanceAddress {
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil
+	}
+	entry := value.(*zTunnelMappingEntry)
+	if time.Now().After(entry.expireAt) {
+		c.cache.Remove(key)
+		return nil
+	}
+	return entry.address
+}
+
+func (c *zTunnelMappingCache) Len() int {
+	return c.cache.Len()
+}
@@ -0,0 +1,72 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+)
+
+func TestZTunnelMappingCache(t *testing.T) {
+	eventCount := func(event string) int64 {
+		for _, s := range selfobs.Samples() {
+			if s.Name == selfobs.ZTunnelMappingCache && s.LabelValue == event {
+				return s.Value
+			}
+		}
+		return 0
+	}
+
+	cache, err := newZTunnelMappingCache(2, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evictions := eventCount("eviction")
+	cache.Set("a", &ZTunnelLoadBalanceAddress{IP: "10.0.0.1"})
+	cache.Set("b", &ZTunnelLoadBalanceAddress{IP: "10.0.0.2"})
+	// "a" is recently used, so "b" should be evicted
+	if address := cache.Get("a"); address == nil || address.IP != "10.0.0.1" {
+		t.Fatalf("the mapping of a should be found, actual: %v", address)
+	}
+	cache.Set("c", &ZTunnelLoadBalanceAddress{IP: "10.0.0.3"})
+	if address := cache.Get("b"); address != nil {
+		t.Fatalf("the mapping of b should be evicted, actual: %v", address)
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("the cache size should be 2, actual: %d", cache.Len())
+	}
+	if count := eventCount("eviction") - evictions; count != 1 {
+		t.Fatalf("the eviction count should be 1, actual: %d", count)
+	}
+
+	expiring, err := newZTunnelMappingCache(2, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := eventCount("expired")
+	expiring.Set("a", &ZTunnelLoadBalanceAddress{IP: "10.0.0.1"})
+	time.Sleep(time.Millisecond * 5)
+	if address := expiring.Get("a"); address != nil {
+		t.Fatalf("the mapping of a should be expired, actual: %v", address)
+	}
+	if count := eventCount("expired") - expired; count != 1 {
+		t.Fatalf("the expired count should be 1, actual: %d", count)
+	}
+}
@@ -19,8 +19,12 @@ package common
 
 import (
 	"context"
+	"math/rand"
 
 	"github.com/apache/skywalking-rover/pkg/accesslog/bpf"
+	"github.com/apache/skywalking-rover/pkg/dns"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+	"github.com/apache/skywalking-rover/pkg/tools/watchdog"
 )
 
 type AccessLogContext struct {
@@ -29,4 +33,42 @@ type AccessLogContext struct {
 	ConnectionMgr  *ConnectionManager
 	Config         *Config
 	RuntimeContext context.Context
+	// Correlation is the queue of correlation records, nil means the correlation is disabled
+	Correlation *CorrelationQueue
+	// TLSKeyLogs is the key logs of the processes for decrypting the TLS data, nil means the decryption is disabled
+	TLSKeyLogs *TLSKeyLogs
+	// Watchdog is detecting and restarting the stalled components of the pipeline, nil means the watchdog is disabled
+	Watchdog *watchdog.Watchdog
+	// ParseStats is counting the data quality gaps of the protocol analyzers
+	ParseStats *ProtocolParseStats
+	// BufferPressure is counting the socket buffer pressure of the processes
+	BufferPressure *SocketBufferPressureStats
+	// RPC is the queue of the request and response records of the protocols which are reported as meters
+	RPC *FlushQueue[*RPCRecord]
+	// DNS is the queue of the DNS lookups for correlating with the following connections, nil means the correlation is disabled
+	DNS *FlushQueue[*dns.Lookup]
+	// Payloads is the queue of the captured and redacted payloads of the protocols, nil means the capture is disabled
+	Payloads *PayloadCapture
+	// TLSHandshakes is the queue of the TLS handshake metadata of the connections, nil means the collecting is disabled
+	TLSHandshakes *FlushQueue[*TLSHandshake]
+	// HBONE is the CONNECT streams of the ztunnel HBONE tunnels, nil means the HBONE correlation is disabled
+	HBONE *HBONETunnels
+	// SelfProtection is deciding the load shedding level from the resource usage of rover, nil means never shed load
+	SelfProtection *selfprotect.Guard
+	// EndpointNormalizer is shaping the HTTP paths before reporting, nil means the paths are reported as it is
+	EndpointNormalizer *EndpointNormalizer
+}
+
+// ShedLevel is the current load shedding level of the self-protection
+func (c *AccessLogContext) ShedLevel() selfprotect.Level {
+	return c.SelfProtection.Level()
+}
+
+// SampleProtocolLog decides the protocol log should be kept or not, all logs are kept until the sampling level is reached
+func (c *AccessLogContext) SampleProtocolLog() bool {
+	if c.ShedLevel() < selfprotect.LevelSampling {
+		return true
+	}
+	// nolint
+	return rand.Intn(100) < c.Config.SelfProtection.SamplingRate
 }
@@ -0,0 +1,49 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+// AttributeCNIEncryption is how the traffic of the connection encrypted by the CNI, only exists when encrypted
+const AttributeCNIEncryption = "rover.cni.encryption"
+
+// SetAttributes merge the attributes into the connection, such as the metadata of the remote address,
+// the attributes are exported with the access logs of the connection
+func (c *ConnectionInfo) SetAttributes(attrs map[string]string) {
+	for {
+		current := c.attributes.Load()
+		merged := make(map[string]string, len(attrs))
+		if current != nil {
+			for k, v := range *current {
+				merged[k] = v
+			}
+		}
+		for k, v := range attrs {
+			merged[k] = v
+		}
+		if c.attributes.CompareAndSwap(current, &merged) {
+			return
+		}
+	}
+}
+
+// Attributes of the connection, the returned map is shared and must not be modified
+func (c *ConnectionInfo) Attributes() map[string]string {
+	if attrs := c.attributes.Load(); attrs != nil {
+		return *attrs
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConnectionAttributes(t *testing.T) {
+	con := &ConnectionInfo{}
+	if attrs := con.Attributes(); attrs != nil {
+		t.Fatalf("the attributes should be empty, actual: %v", attrs)
+	}
+	con.SetAttributes(map[string]string{"a": "1", "b": "2"})
+	first := con.Attributes()
+	con.SetAttributes(map[string]string{"b": "3", "c": "4"})
+	if expected := map[string]string{"a": "1", "b": "3", "c": "4"}; !reflect.DeepEqual(con.Attributes(), expected) {
+		t.Errorf("the merged attributes not same, expected: %v, actual: %v", expected, con.Attributes())
+	}
+	if expected := map[string]string{"a": "1", "b": "2"}; !reflect.DeepEqual(first, expected) {
+		t.Errorf("the previous attributes should not be modified, expected: %v, actual: %v", expected, first)
+	}
+}
@@ -0,0 +1,154 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// Bandwidth is the transferred bytes of the process or the remote endpoint
+type Bandwidth struct {
+	EgressBytes  uint64
+	IngressBytes uint64
+}
+
+func (b *Bandwidth) add(egress, ingress uint64) {
+	b.EgressBytes += egress
+	b.IngressBytes += ingress
+}
+
+// ProcessBandwidth is the transferred bytes of the process, with the remote endpoints
+type ProcessBandwidth struct {
+	Bandwidth
+	Remotes map[string]*Bandwidth
+}
+
+func (p *ProcessBandwidth) add(remote string, egress, ingress uint64) {
+	p.Bandwidth.add(egress, ingress)
+	r := p.Remotes[remote]
+	if r == nil {
+		r = &Bandwidth{}
+		p.Remotes[remote] = r
+	}
+	r.add(egress, ingress)
+}
+
+// Merge the bandwidth of another process, such as aggregating the processes in the same pod
+func (p *ProcessBandwidth) Merge(other *ProcessBandwidth) {
+	for remote, r := range other.Remotes {
+		p.add(remote, r.EgressBytes, r.IngressBytes)
+	}
+}
+
+// RankedRemote is the remote endpoint ranked by the total transferred bytes
+type RankedRemote struct {
+	Remote string
+	Bandwidth
+}
+
+// TopRemotes ranks the remote endpoints by the total transferred bytes in descending order, and only keep the first N
+func (p *ProcessBandwidth) TopRemotes(n int) []*RankedRemote {
+	result := make([]*RankedRemote, 0, len(p.Remotes))
+	for remote, b := range p.Remotes {
+		if b.EgressBytes+b.IngressBytes == 0 {
+			continue
+		}
+		result = append(result, &RankedRemote{Remote: remote, Bandwidth: *b})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ti, tj := result[i].EgressBytes+result[i].IngressBytes, result[j].EgressBytes+result[j].IngressBytes
+		if ti != tj {
+			return ti > tj
+		}
+		return result[i].Remote < result[j].Remote
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// SnapshotBandwidth aggregates the transferred bytes since the last snapshot by the local process and the remote endpoint,
+// the remote endpoint is the remote service when it's known, otherwise the remote address
+func (c *ConnectionManager) SnapshotBandwidth() map[uint32]*ProcessBandwidth {
+	result := make(map[uint32]*ProcessBandwidth)
+	c.connections.IterCb(func(_ string, v interface{}) {
+		con, ok := v.(*ConnectionInfo)
+		if !ok || con == nil {
+			return
+		}
+		write, read := atomic.LoadUint64(&con.WriteBytes), atomic.LoadUint64(&con.ReadBytes)
+		egress := write - atomic.SwapUint64(&con.bandwidthWriteBytes, write)
+		ingress := read - atomic.SwapUint64(&con.bandwidthReadBytes, read)
+		if egress == 0 && ingress == 0 {
+			return
+		}
+		remoteService, remoteAddress := topologyRemote(con.RPCConnection.GetRole(), con.RPCConnection.GetRemote())
+		remote := remoteService
+		if remote == "" {
+			remote = remoteAddress
+		}
+		process := result[con.PID]
+		if process == nil {
+			process = &ProcessBandwidth{Remotes: make(map[string]*Bandwidth)}
+			result[con.PID] = process
+		}
+		process.add(remote, egress, ingress)
+	})
+	return result
+}
+
+// BandwidthWindow is the sliding window of the bandwidth snapshots
+type BandwidthWindow struct {
+	buckets []map[uint32]*ProcessBandwidth
+	size    int
+}
+
+func NewBandwidthWindow(size int) *BandwidthWindow {
+	return &BandwidthWindow{size: size}
+}
+
+// Push the latest snapshot into the window, the oldest snapshot is removed when the window is full
+func (w *BandwidthWindow) Push(snapshot map[uint32]*ProcessBandwidth) {
+	w.buckets = append(w.buckets, snapshot)
+	if len(w.buckets) > w.size {
+		w.buckets = w.buckets[len(w.buckets)-w.size:]
+	}
+}
+
+// Buckets is the count of snapshots in the window
+func (w *BandwidthWindow) Buckets() int {
+	return len(w.buckets)
+}
+
+// Sum the bandwidth of each process in the window
+func (w *BandwidthWindow) Sum() map[uint32]*ProcessBandwidth {
+	result := make(map[uint32]*ProcessBandwidth)
+	for _, bucket := range w.buckets {
+		for pid, b := range bucket {
+			process := result[pid]
+			if process == nil {
+				process = &ProcessBandwidth{Remotes: make(map[string]*Bandwidth)}
+				result[pid] = process
+			}
+			process.Merge(b)
+		}
+	}
+	return result
+}
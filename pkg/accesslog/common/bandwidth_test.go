@@ -0,0 +1,80 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBandwidthWindow(t *testing.T) {
+	snapshot := func(remotes map[string]Bandwidth) map[uint32]*ProcessBandwidth {
+		p := &ProcessBandwidth{Remotes: make(map[string]*Bandwidth)}
+		for remote, b := range remotes {
+			p.add(remote, b.EgressBytes, b.IngressBytes)
+		}
+		return map[uint32]*ProcessBandwidth{1: p}
+	}
+	tests := []struct {
+		name      string
+		snapshots []map[uint32]*ProcessBandwidth
+		topN      int
+		total     Bandwidth
+		top       []*RankedRemote
+	}{
+		{
+			name: "ranked by the total bytes",
+			snapshots: []map[uint32]*ProcessBandwidth{
+				snapshot(map[string]Bandwidth{"a": {EgressBytes: 10}, "b": {IngressBytes: 30}, "c": {EgressBytes: 5, IngressBytes: 5}}),
+			},
+			topN:  2,
+			total: Bandwidth{EgressBytes: 15, IngressBytes: 35},
+			top: []*RankedRemote{
+				{Remote: "b", Bandwidth: Bandwidth{IngressBytes: 30}},
+				{Remote: "a", Bandwidth: Bandwidth{EgressBytes: 10}},
+			},
+		},
+		{
+			name: "the oldest snapshot slides out of the window",
+			snapshots: []map[uint32]*ProcessBandwidth{
+				snapshot(map[string]Bandwidth{"a": {EgressBytes: 100}}),
+				snapshot(map[string]Bandwidth{"b": {EgressBytes: 20}}),
+				snapshot(map[string]Bandwidth{"a": {EgressBytes: 10}, "b": {IngressBytes: 1}}),
+			},
+			topN:  5,
+			total: Bandwidth{EgressBytes: 30, IngressBytes: 1},
+			top: []*RankedRemote{
+				{Remote: "b", Bandwidth: Bandwidth{EgressBytes: 20, IngressBytes: 1}},
+				{Remote: "a", Bandwidth: Bandwidth{EgressBytes: 10}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		window := NewBandwidthWindow(2)
+		for _, s := range tt.snapshots {
+			window.Push(s)
+		}
+		sum := window.Sum()[1]
+		if sum.Bandwidth != tt.total {
+			t.Fatalf("%s: expected total: %+v, actual: %+v", tt.name, tt.total, sum.Bandwidth)
+		}
+		if top := sum.TopRemotes(tt.topN); !reflect.DeepEqual(top, tt.top) {
+			t.Fatalf("%s: unexpected top remotes: %v", tt.name, top)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+)
+
+// SocketBufferPressureKey is the process and the detected pressure flag or slowness cause
+type SocketBufferPressureKey struct {
+	PID  uint32
+	Name string
+}
+
+// SocketBufferPressureStats counting the socket buffer pressure of each process
+type SocketBufferPressureStats struct {
+	mutex  sync.Mutex
+	flags  map[SocketBufferPressureKey]uint64
+	causes map[SocketBufferPressureKey]uint64
+}
+
+func NewSocketBufferPressureStats() *SocketBufferPressureStats {
+	return &SocketBufferPressureStats{
+		flags:  make(map[SocketBufferPressureKey]uint64),
+		causes: make(map[SocketBufferPressureKey]uint64),
+	}
+}
+
+// Record the pressure of the socket detail, the send buffer full and receive buffer drop are counted by the times
+// in the syscall, and the other flags are counted by the syscalls which detected them
+func (s *SocketBufferPressureStats) Record(pid uint32, detail events.SocketDetail) {
+	pressure := detail.GetBufferPressure()
+	if pressure == 0 {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, flag := range pressure.Flags() {
+		count := uint64(1)
+		switch flag {
+		case events.SocketBufferPressureSendBufferFull:
+			count = uint64(detail.GetSendBufferFullCount())
+		case events.SocketBufferPressureReceiveBufferDrop:
+			count = uint64(detail.GetReceiveBufferDropCount())
+		}
+		s.flags[SocketBufferPressureKey{PID: pid, Name: flag.String()}] += count
+	}
+	if cause := pressure.Cause(); cause != "" {
+		s.causes[SocketBufferPressureKey{PID: pid, Name: cause}]++
+	}
+}
+
+// Swap returns the counts of the flags and causes since the last swap
+func (s *SocketBufferPressureStats) Swap() (flags, causes map[SocketBufferPressureKey]uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	flags, causes = s.flags, s.causes
+	s.flags = make(map[SocketBufferPressureKey]uint64)
+	s.causes = make(map[SocketBufferPressureKey]uint64)
+	return flags, causes
+}
@@ -0,0 +1,58 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+)
+
+func TestSocketBufferPressureStats(t *testing.T) {
+	stats := NewSocketBufferPressureStats()
+	stats.Record(1, &events.SocketDetailEvent{})
+	stats.Record(1, &events.SocketDetailEvent{
+		BufferPressure:      events.SocketBufferPressurePeerZeroWindow | events.SocketBufferPressureSendBufferFull,
+		SendBufferFullCount: 3,
+	})
+	stats.Record(1, &events.SocketDetailEvent{BufferPressure: events.SocketBufferPressureSendBufferFull, SendBufferFullCount: 2})
+	stats.Record(2, &events.SocketDetailEvent{BufferPressure: events.SocketBufferPressureReceiveBufferDrop, ReceiveBufferDropCount: 5})
+
+	flags, causes := stats.Swap()
+	tests := []struct {
+		counts   map[SocketBufferPressureKey]uint64
+		key      SocketBufferPressureKey
+		expected uint64
+	}{
+		{flags, SocketBufferPressureKey{PID: 1, Name: "peer_zero_window"}, 1},
+		{flags, SocketBufferPressureKey{PID: 1, Name: "send_buffer_full"}, 5},
+		{flags, SocketBufferPressureKey{PID: 2, Name: "receive_buffer_drop"}, 5},
+		{flags, SocketBufferPressureKey{PID: 2, Name: "send_buffer_full"}, 0},
+		{causes, SocketBufferPressureKey{PID: 1, Name: "slow_peer"}, 1},
+		{causes, SocketBufferPressureKey{PID: 1, Name: "slow_network"}, 1},
+		{causes, SocketBufferPressureKey{PID: 2, Name: "slow_local"}, 1},
+	}
+	for _, tt := range tests {
+		if tt.counts[tt.key] != tt.expected {
+			t.Errorf("count of %d/%s: expected %d, actual %d", tt.key.PID, tt.key.Name, tt.expected, tt.counts[tt.key])
+		}
+	}
+	if flags, causes = stats.Swap(); len(flags) != 0 || len(causes) != 0 {
+		t.Errorf("the counts should be reset after swap")
+	}
+}
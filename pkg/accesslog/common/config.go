@@ -17,7 +17,10 @@
 
 package common
 
-import "github.com/apache/skywalking-rover/pkg/module"
+import (
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
+)
 
 type Config struct {
 	module.Config
@@ -25,9 +28,30 @@ type Config struct {
 	Active            bool                    `mapstructure:"active"`
 	ExcludeNamespaces string                  `mapstructure:"exclude_namespaces"`
 	ExcludeClusters   string                  `mapstructure:"exclude_cluster"`
+	Filter            filter.Config           `mapstructure:"filter"`
 	Flush             FlushConfig             `mapstructure:"flush"`
+	Exporter          ExporterConfig          `mapstructure:"exporter"`
+	Sender            SenderConfig            `mapstructure:"sender"`
+	RingBuffer        RingBufferConfig        `mapstructure:"ring_buffer"`
+	StateHandover     StateHandoverConfig     `mapstructure:"state_handover"`
 	ConnectionAnalyze ConnectionAnalyzeConfig `mapstructure:"connection_analyze"`
 	ProtocolAnalyze   ProtocolAnalyzeConfig   `mapstructure:"protocol_analyze"`
+	Topology          TopologyConfig          `mapstructure:"topology"`
+	Bandwidth         BandwidthConfig         `mapstructure:"bandwidth"`
+	Exclude           ExcludeConfig           `mapstructure:"exclude"`
+	SCTP              SCTPConfig              `mapstructure:"sctp"`
+	Correlation       CorrelationConfig       `mapstructure:"correlation"`
+	DNS               DNSConfig               `mapstructure:"dns"`
+	TLSHandshake      TLSHandshakeConfig      `mapstructure:"tls_handshake"`
+	ConnectionHealth  ConnectionHealthConfig  `mapstructure:"connection_health"`
+	Heartbeat         HeartbeatConfig         `mapstructure:"heartbeat"`
+	SyscallLatency    SyscallLatencyConfig    `mapstructure:"syscall_latency"`
+	Payload           PayloadConfig           `mapstructure:"payload"`
+	ZTunnel           ZTunnelConfig           `mapstructure:"ztunnel"`
+	Watchdog          WatchdogConfig          `mapstructure:"watchdog"`
+	AWS               AWSConfig               `mapstructure:"aws"`
+	SelfProtection    SelfProtectionConfig    `mapstructure:"self_protection"`
+	DropDetection     DropDetectionConfig     `mapstructure:"drop_detection"`
 }
 
 type FlushConfig struct {
@@ -35,18 +59,184 @@ type FlushConfig struct {
 	Period            string `mapstructure:"period"`
 }
 
+type ExporterConfig struct {
+	Type string             `mapstructure:"type"`
+	File FileExporterConfig `mapstructure:"file"`
+	GRPC GRPCExporterConfig `mapstructure:"grpc"`
+}
+
+type GRPCExporterConfig struct {
+	Compression string `mapstructure:"compression"`
+}
+
+type SenderConfig struct {
+	BatchSize         int               `mapstructure:"batch_size"`
+	BatchInterval     string            `mapstructure:"batch_interval"`
+	MaxPendingBatches int               `mapstructure:"max_pending_batches"`
+	Retry             SenderRetryConfig `mapstructure:"retry"`
+}
+
+type SenderRetryConfig struct {
+	Active      bool   `mapstructure:"active"`
+	MaxSize     string `mapstructure:"max_size"`
+	Period      string `mapstructure:"period"`
+	MaxAttempts int    `mapstructure:"max_attempts"`
+}
+
+type FileExporterConfig struct {
+	Path       string `mapstructure:"path"`
+	MaxSize    string `mapstructure:"max_size"`
+	MaxBackups int    `mapstructure:"max_backups"`
+}
+
+type RingBufferConfig struct {
+	Active bool   `mapstructure:"active"`
+	Size   string `mapstructure:"size"`
+}
+
+type StateHandoverConfig struct {
+	Active  bool   `mapstructure:"active"`
+	PinPath string `mapstructure:"pin_path"`
+}
+
 type ConnectionAnalyzeConfig struct {
-	PerCPUBufferSize string `mapstructure:"per_cpu_buffer"`
-	ParseParallels   int    `mapstructure:"parse_parallels"`
-	AnalyzeParallels int    `mapstructure:"analyze_parallels"`
-	QueueSize        int    `mapstructure:"queue_size"`
+	PerCPUBufferSize    string `mapstructure:"per_cpu_buffer"`
+	ParseParallels      int    `mapstructure:"parse_parallels"`
+	AnalyzeParallels    int    `mapstructure:"analyze_parallels"`
+	QueueSize           int    `mapstructure:"queue_size"`
+	Deduplicate         bool   `mapstructure:"deduplicate"`
+	DiscoverExisting    bool   `mapstructure:"discover_existing"`
+	DetectCNIEncryption bool   `mapstructure:"detect_cni_encryption"`
 }
 
 type ProtocolAnalyzeConfig struct {
-	PerCPUBufferSize string `mapstructure:"per_cpu_buffer"`
-	ParseParallels   int    `mapstructure:"parse_parallels"`
-	AnalyzeParallels int    `mapstructure:"analyze_parallels"`
-	QueueSize        int    `mapstructure:"queue_size"`
+	PerCPUBufferSize string          `mapstructure:"per_cpu_buffer"`
+	ParseParallels   int             `mapstructure:"parse_parallels"`
+	AnalyzeParallels int             `mapstructure:"analyze_parallels"`
+	QueueSize        int             `mapstructure:"queue_size"`
+	ParseStatsPeriod string          `mapstructure:"parse_stats_period"`
+	TLSKeyLog        TLSKeyLogConfig `mapstructure:"tls_key_log"`
+	Endpoint         EndpointConfig  `mapstructure:"endpoint"`
+}
+
+type EndpointConfig struct {
+	Rules      string `mapstructure:"rules"`
+	CollapseID bool   `mapstructure:"collapse_id"`
+	MaxDepth   int    `mapstructure:"max_depth"`
+}
+
+type TLSKeyLogConfig struct {
+	Active bool   `mapstructure:"active"`
+	Path   string `mapstructure:"path"`
+}
+
+type TopologyConfig struct {
+	Active bool   `mapstructure:"active"`
+	Period string `mapstructure:"period"`
+}
+
+type CorrelationConfig struct {
+	Active       bool   `mapstructure:"active"`
+	Header       string `mapstructure:"header"`
+	ExtraHeaders string `mapstructure:"extra_headers"`
+}
+
+type DNSConfig struct {
+	Active          bool   `mapstructure:"active"`
+	SlowThreshold   string `mapstructure:"slow_threshold"`
+	CorrelateWindow string `mapstructure:"correlate_window"`
+}
+
+type TLSHandshakeConfig struct {
+	Active bool `mapstructure:"active"`
+}
+
+type ConnectionHealthConfig struct {
+	Active        bool `mapstructure:"active"`
+	ReportHealthy bool `mapstructure:"report_healthy"`
+}
+
+type HeartbeatConfig struct {
+	Active bool   `mapstructure:"active"`
+	Period string `mapstructure:"period"`
+}
+
+type SyscallLatencyConfig struct {
+	Active bool   `mapstructure:"active"`
+	Period string `mapstructure:"period"`
+}
+
+type BandwidthConfig struct {
+	Active bool   `mapstructure:"active"`
+	Period string `mapstructure:"period"`
+	// the sliding window of the byte rates, must be the multiple of the period
+	Window string `mapstructure:"window"`
+	// the count of the top remote endpoints of each pod
+	TopN int `mapstructure:"top_n"`
+}
+
+type ExcludeConfig struct {
+	// exclude the traffic of rover itself
+	Self bool `mapstructure:"self"`
+	// exclude the traffic to the backend(OAP) addresses
+	Backend bool `mapstructure:"backend"`
+	// the excluded process IDs in the host, split by ","
+	PIDs string `mapstructure:"pids"`
+	// the excluded remote addresses as "host", "host:port" or "[ipv6]:port", split by ","
+	Addresses string `mapstructure:"addresses"`
+}
+
+type SCTPConfig struct {
+	Active bool   `mapstructure:"active"`
+	Period string `mapstructure:"period"`
+}
+
+type ZTunnelConfig struct {
+	Prewarm   bool `mapstructure:"prewarm"`
+	AdminPort int  `mapstructure:"admin_port"`
+	HBONE     bool `mapstructure:"hbone"`
+	// the expiry and the max entries of the IP mapping cache of each ztunnel process
+	MappingExpire     string `mapstructure:"mapping_expire"`
+	MappingMaxEntries int    `mapstructure:"mapping_max_entries"`
+}
+
+type WatchdogConfig struct {
+	Active       bool   `mapstructure:"active"`
+	CheckPeriod  string `mapstructure:"check_period"`
+	StallTimeout string `mapstructure:"stall_timeout"`
+	MaxBackoff   string `mapstructure:"max_backoff"`
+}
+
+type AWSConfig struct {
+	ENIMetadata     bool   `mapstructure:"eni_metadata"`
+	CacheTTL        string `mapstructure:"cache_ttl"`
+	FailureCacheTTL string `mapstructure:"failure_cache_ttl"`
+	MaxLookups      int    `mapstructure:"max_lookups"`
+	LookupTimeout   string `mapstructure:"lookup_timeout"`
+}
+
+type PayloadConfig struct {
+	Active         bool   `mapstructure:"active"`
+	Rules          string `mapstructure:"rules"`
+	RedactHeaders  string `mapstructure:"redact_headers"`
+	RedactPatterns string `mapstructure:"redact_patterns"`
+}
+
+type SelfProtectionConfig struct {
+	Active        bool   `mapstructure:"active"`
+	CheckPeriod   string `mapstructure:"check_period"`
+	CPULimit      string `mapstructure:"cpu_limit"`
+	MemoryLimit   string `mapstructure:"memory_limit"`
+	HighWatermark int    `mapstructure:"high_watermark"`
+	LowWatermark  int    `mapstructure:"low_watermark"`
+	SamplingRate  int    `mapstructure:"sampling_rate"`
+}
+
+type DropDetectionConfig struct {
+	CheckPeriod      string `mapstructure:"check_period"`
+	AdaptiveSampling bool   `mapstructure:"adaptive_sampling"`
+	MinSamplingRate  int    `mapstructure:"min_sampling_rate"`
+	RecoverStep      int    `mapstructure:"recover_step"`
 }
 
 func (c *Config) IsActive() bool {
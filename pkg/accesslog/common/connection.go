@@ -21,7 +21,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -33,6 +36,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/process/api"
 	"github.com/apache/skywalking-rover/pkg/process/finders/kubernetes"
 	"github.com/apache/skywalking-rover/pkg/tools"
+	"github.com/apache/skywalking-rover/pkg/tools/cni"
 	"github.com/apache/skywalking-rover/pkg/tools/enums"
 	"github.com/apache/skywalking-rover/pkg/tools/host"
 	"github.com/apache/skywalking-rover/pkg/tools/ip"
@@ -83,30 +87,57 @@ type ProcessListener interface {
 	OnProcessRemoved(pid int32)
 }
 
+// ExistingConnectionListener is notified when the process is monitored at the rover startup or handed over
+// from the previous rover, such process could hold the connections established before monitoring
+type ExistingConnectionListener interface {
+	OnExistingProcessMonitoring(pid int32)
+}
+
 type ConnectionManager struct {
 	moduleMgr   *module.Manager
 	processOP   process.Operator
 	connections cmap.ConcurrentMap
 	// localIPWithPid cache all local monitoring process bind IP address
 	// for checking the remote address is local or not
-	localIPWithPid map[string]int32
+	// multiple processes could be shared the same address, such as the host network or overlapping subnets
+	localIPWithPid map[string][]int32
+	// processNetNS cache the network namespace inode of all monitoring processes
+	processNetNS map[int32]uint64
 	// monitoringProcesses management all monitoring processes
 	monitoringProcesses   map[int32][]api.ProcessInterface
 	monitoringProcessLock sync.RWMutex
 	// monitoring process map in BPF
 	processMonitorMap   *ebpf.Map
 	activeConnectionMap *ebpf.Map
+	// the excluded processes and remote addresses in BPF
+	processExcludeMap *ebpf.Map
+	excludeAddressMap *ebpf.Map
+	excludeConfig     *ExcludeConfig
+	// handoverProcesses are the processes monitored by the previous rover in the pinned map,
+	// the listeners are notified when they are monitored again, so the existing connections could be rehydrated
+	handoverProcesses sync.Map
+	// startupSynced means the processes existing at the rover startup are all synchronized
+	startupSynced atomic.Bool
 
 	monitorFilter MonitorFilter
 
-	processors       []ConnectionProcessor
-	processListeners []ProcessListener
+	processors        []ConnectionProcessor
+	processListeners  []ProcessListener
+	existingListeners []ExistingConnectionListener
 
 	flushListeners []FlusherListener
 
 	connectTracker *ip.ConnTrack
 
 	connectionProtocolBreakMap *cache.Expiring
+
+	// ownership deduplicate the same connection observed multiple times, nil means disabled
+	ownership *connectionOwnership
+	// the encrypted routes of the CNI, nil means not detected
+	cniEncryption       atomic.Pointer[cni.EncryptionTable]
+	detectCNIEncryption bool
+	// is accumulating the syscall timing of the connections
+	recordSyscallLatency bool
 }
 
 func (c *ConnectionManager) RegisterProcessor(processor ConnectionProcessor) {
@@ -117,6 +148,10 @@ func (c *ConnectionManager) AddProcessListener(listener ProcessListener) {
 	c.processListeners = append(c.processListeners, listener)
 }
 
+func (c *ConnectionManager) AddExistingConnectionListener(listener ExistingConnectionListener) {
+	c.existingListeners = append(c.existingListeners, listener)
+}
+
 func (c *ConnectionManager) RegisterNewFlushListener(listener FlusherListener) {
 	c.flushListeners = append(c.flushListeners, listener)
 }
@@ -131,9 +166,39 @@ type ConnectionInfo struct {
 	LastCheckExistTime time.Time
 	DeleteAfter        *time.Time
 	ProtocolBreak      bool
-}
-
-func NewConnectionManager(_ *Config, moduleMgr *module.Manager, bpfLoader *bpf.Loader, filter MonitorFilter) *ConnectionManager {
+	// Encryption is how the traffic of the connection encrypted by the CNI(WireGuard, IPsec)
+	Encryption cni.EncryptionType
+	// MeshRevision is the mesh and revision of the ztunnel which the connection passes through
+	MeshRevision string
+	// MeshHop is how the connection passes through the waypoint proxy
+	MeshHop MeshHop
+	// Transport is the L4 protocol of the connection, such as TCP or SCTP
+	Transport enums.TransportProtocol
+	// Discovered means the connection is discovered from the procfs, it's not in the BPF until the data is transferred
+	Discovered bool
+
+	// the total transferred bytes of the connection
+	WriteBytes uint64
+	ReadBytes  uint64
+	// the transferred bytes when the last topology snapshot
+	snapshotWriteBytes uint64
+	snapshotReadBytes  uint64
+	// the transferred bytes when the last bandwidth snapshot
+	bandwidthWriteBytes uint64
+	bandwidthReadBytes  uint64
+	// the total requests and errors analyzed from the protocol
+	Requests uint64
+	Errors   uint64
+	// the counters when the last heartbeat
+	heartbeat *connectionHeartbeatState
+	// the syscall timing since the last snapshot
+	syscalls    map[enums.SocketFunctionName]*SyscallLatency
+	syscallLock sync.Mutex
+	// the extra attributes of the connection, replaced as a whole when changed
+	attributes atomic.Pointer[map[string]string]
+}
+
+func NewConnectionManager(config *Config, moduleMgr *module.Manager, bpfLoader *bpf.Loader, filter MonitorFilter) *ConnectionManager {
 	track, err := ip.NewConnTrack()
 	if err != nil {
 		log.Warnf("cannot create the connection tracker, %v", err)
@@ -142,20 +207,36 @@ func NewConnectionManager(_ *Config, moduleMgr *module.Manager, bpfLoader *bpf.L
 		moduleMgr:                  moduleMgr,
 		processOP:                  moduleMgr.FindModule(process.ModuleName).(process.Operator),
 		connections:                cmap.New(),
-		localIPWithPid:             make(map[string]int32),
+		localIPWithPid:             make(map[string][]int32),
+		processNetNS:               make(map[int32]uint64),
 		monitoringProcesses:        make(map[int32][]api.ProcessInterface),
 		processMonitorMap:          bpfLoader.ProcessMonitorControl,
 		activeConnectionMap:        bpfLoader.ActiveConnectionMap,
+		processExcludeMap:          bpfLoader.ProcessExcludeControl,
+		excludeAddressMap:          bpfLoader.ExcludeAddressControl,
+		excludeConfig:              &config.Exclude,
 		monitorFilter:              filter,
 		flushListeners:             make([]FlusherListener, 0),
 		connectTracker:             track,
 		connectionProtocolBreakMap: cache.NewExpiring(),
 	}
+	if bpfLoader.Handover {
+		mgr.pruneHandoverState()
+	}
+	mgr.detectCNIEncryption = config.ConnectionAnalyze.DetectCNIEncryption
+	mgr.recordSyscallLatency = config.SyscallLatency.Active
+	if config.ConnectionAnalyze.Deduplicate {
+		mgr.ownership = newConnectionOwnership()
+	}
 	return mgr
 }
 
 func (c *ConnectionManager) Start(ctx context.Context, accessLogContext *AccessLogContext) {
 	c.processOP.AddListener(c)
+	c.startExcludeTraffic(ctx)
+	if c.detectCNIEncryption {
+		c.startDetectCNIEncryption(ctx)
+	}
 
 	// starting to clean up the un-active connection in BPF
 	go func() {
@@ -240,7 +321,17 @@ func (c *ConnectionManager) Find(event events.Event) *ConnectionInfo {
 		if localAddress == nil || remoteAddress == nil {
 			return nil
 		}
+		if c.ownership != nil && !c.ownership.Claim(connectionKey, socket) {
+			log.Debugf("the connection is already reported by another monitored connection, so skip it, "+
+				"connection ID: %d, randomID: %d, role: %s, local: %s:%d, remote: %s:%d",
+				e.GetConnectionID(), e.GetRandomID(), socket.Role, socket.SrcIP, socket.SrcPort, socket.DestIP, socket.DestPort)
+			return nil
+		}
 		connection := c.buildConnection(e, socket, localAddress, remoteAddress, connectionKey)
+		connection.Encryption = c.detectConnectionEncryption(socket.DestIP, remoteAddress)
+		if connection.Encryption != cni.EncryptionNone {
+			connection.SetAttributes(map[string]string{AttributeCNIEncryption: string(connection.Encryption)})
+		}
 		c.connections.Set(connectionKey, connection)
 		if log.Enable(logrus.DebugLevel) {
 			log.Debugf("building flushing connection, connection ID: %d, randomID: %d, role: %s, local: %s:%d, remote: %s:%d, "+
@@ -254,6 +345,24 @@ func (c *ConnectionManager) Find(event events.Event) *ConnectionInfo {
 	return nil
 }
 
+// FindByID find the exists connection through the connection and random ID, return nil if not exists
+func (c *ConnectionManager) FindByID(conID, ranID uint64) *ConnectionInfo {
+	data, exist := c.connections.Get(fmt.Sprintf("%d_%d", conID, ranID))
+	if !exist {
+		return nil
+	}
+	return data.(*ConnectionInfo)
+}
+
+// RangeConnections iterate all the connections in the manager
+func (c *ConnectionManager) RangeConnections(f func(con *ConnectionInfo)) {
+	c.connections.IterCb(func(_ string, v interface{}) {
+		if con, ok := v.(*ConnectionInfo); ok && con != nil {
+			f(con)
+		}
+	})
+}
+
 func (c *ConnectionManager) buildRemoteAddress(e *events.SocketConnectEvent, socket *ip.SocketPair) *v3.ConnectionAddress {
 	// if the remote address is local, then no needs to build the address(access log no need to send by communicate with self)
 	if tools.IsLocalHostAddress(socket.DestIP) {
@@ -261,22 +370,55 @@ func (c *ConnectionManager) buildRemoteAddress(e *events.SocketConnectEvent, soc
 	}
 
 	// if the remote connection is need to use conntrack, then update the real peer address
-	if socket.NeedConnTrack {
+	if socket.NeedConnTrack && c.connectTracker != nil {
 		if err := c.connectTracker.UpdateRealPeerAddress(socket); err != nil {
 			log.Debugf("cannot update the real peer address, %v", err)
 		}
 	}
 
 	// found local address with pid
-	if pid, exist := c.localIPWithPid[socket.DestIP]; exist && pid != 0 {
+	if pid := c.findLocalPidWithAddress(socket.DestIP, socket.DestPort); pid != 0 {
 		return c.buildLocalAddress(uint32(pid), socket.DestPort, socket)
 	}
 
-	log.Debugf("building the remote address to unknown, connection: %d-%d, role: %s, local: %s:%d, remote: %s:%d",
-		e.GetConnectionID(), e.GetRandomID(), socket.Role, socket.SrcIP, socket.SrcPort, socket.DestIP, socket.DestPort)
+	log.Debugf("building the remote address to unknown, connection: %d-%d, role: %s, local: %s:%d, remote: %s:%d, "+
+		"original destination: %s:%d", e.GetConnectionID(), e.GetRandomID(), socket.Role, socket.SrcIP, socket.SrcPort,
+		socket.DestIP, socket.DestPort, socket.OriginalDestIP, socket.OriginalDestPort)
 	return c.buildAddressFromRemote(socket.DestIP, socket.DestPort)
 }
 
+// findLocalPidWithAddress find the monitoring process which owned the address,
+// if multiple processes in different network namespaces have the same address,
+// then find the process which listening the port in its network namespace
+func (c *ConnectionManager) findLocalPidWithAddress(address string, port uint16) int32 {
+	c.monitoringProcessLock.RLock()
+	defer c.monitoringProcessLock.RUnlock()
+	return selectListeningPid(c.localIPWithPid[address], c.processNetNS, port, ip.IsListeningPort)
+}
+
+// selectListeningPid find the first process which listening the port, the processes in the same network namespace
+// only checked once, return the first process when no process listening the port
+func selectListeningPid(pids []int32, processNetNS map[int32]uint64, port uint16, isListening func(pid int32, port uint16) bool) int32 {
+	if len(pids) == 0 {
+		return 0
+	} else if len(pids) == 1 {
+		return pids[0]
+	}
+	checkedNetNS := make(map[uint64]bool)
+	for _, pid := range pids {
+		if netNS := processNetNS[pid]; netNS != 0 {
+			if checkedNetNS[netNS] {
+				continue
+			}
+			checkedNetNS[netNS] = true
+		}
+		if isListening(pid, port) {
+			return pid
+		}
+	}
+	return pids[0]
+}
+
 func (c *ConnectionManager) connectionPostHandle(connection *ConnectionInfo, event events.Event) {
 	if connection == nil {
 		return
@@ -285,6 +427,8 @@ func (c *ConnectionManager) connectionPostHandle(connection *ConnectionInfo, eve
 	case *CloseEventWithNotify:
 		connection.MarkDeletable = true
 	case events.SocketDetail:
+		connection.RecordTransfer(e)
+		c.RecordSyscall(connection, e)
 		tlsMode := connection.RPCConnection.TlsMode
 		protocol := connection.RPCConnection.Protocol
 		if e.GetSSL() == 1 && connection.RPCConnection.TlsMode == v3.AccessLogConnectionTLSMode_Plain {
@@ -333,6 +477,20 @@ func (c *ConnectionManager) ProcessIsMonitor(pid uint32) bool {
 	return len(c.monitoringProcesses[int32(pid)]) > 0
 }
 
+// FindMonitoringProcesses get all monitoring process entities of the pid
+func (c *ConnectionManager) FindMonitoringProcesses(pid uint32) []api.ProcessInterface {
+	c.monitoringProcessLock.RLock()
+	defer c.monitoringProcessLock.RUnlock()
+	return c.monitoringProcesses[int32(pid)]
+}
+
+// FindProcessNetNS find the network namespace inode of the monitoring process, return 0 if not found
+func (c *ConnectionManager) FindProcessNetNS(pid uint32) uint64 {
+	c.monitoringProcessLock.RLock()
+	defer c.monitoringProcessLock.RUnlock()
+	return c.processNetNS[int32(pid)]
+}
+
 func (c *ConnectionManager) ProcessIsDetectBy(pid uint32, detectType api.ProcessDetectType) bool {
 	c.monitoringProcessLock.RLock()
 	defer c.monitoringProcessLock.RUnlock()
@@ -374,6 +532,8 @@ func (c *ConnectionManager) buildConnection(event *events.SocketConnectEvent, so
 		Socket:             socket,
 		LastCheckExistTime: time.Now(),
 		ProtocolBreak:      protocolBreak,
+		Transport:          enums.TransportProtocol(event.Transport),
+		Discovered:         enums.SocketFunctionName(event.FuncName) == enums.SocketFunctionNameExisting,
 	}
 }
 
@@ -447,27 +607,63 @@ func (c *ConnectionManager) AddNewProcess(pid int32, entities []api.ProcessInter
 	}
 	c.monitoringProcesses[pid] = monitorProcesses
 	c.updateMonitorStatusForProcess(pid, true)
+	c.processNetNS[pid] = c.readProcessNetNS(pid)
 	for _, entity := range monitorProcesses {
 		for _, host := range entity.ExposeHosts() {
-			c.localIPWithPid[host] = pid
+			c.localIPWithPid[host] = appendPidIfNotExist(c.localIPWithPid[host], pid)
 		}
 	}
 	c.printTotalAddressesWithPid("adding monitoring process")
+	_, handover := c.handoverProcesses.LoadAndDelete(pid)
 	for _, l := range c.processListeners {
 		l.OnNewProcessMonitoring(pid)
 	}
+	if handover || !c.startupSynced.Load() {
+		c.notifyExistingProcessMonitoring(pid)
+	}
+}
+
+func (c *ConnectionManager) notifyExistingProcessMonitoring(pid int32) {
+	for _, l := range c.existingListeners {
+		l.OnExistingProcessMonitoring(pid)
+	}
 }
 
 func (c *ConnectionManager) rebuildLocalIPWithPID() {
-	result := make(map[string]int32)
+	result := make(map[string][]int32)
+	netNS := make(map[int32]uint64)
 	for pid, entities := range c.monitoringProcesses {
+		if inode, exist := c.processNetNS[pid]; exist {
+			netNS[pid] = inode
+		} else {
+			netNS[pid] = c.readProcessNetNS(pid)
+		}
 		for _, entity := range entities {
 			for _, host := range entity.ExposeHosts() {
-				result[host] = pid
+				result[host] = appendPidIfNotExist(result[host], pid)
 			}
 		}
 	}
 	c.localIPWithPid = result
+	c.processNetNS = netNS
+}
+
+func (c *ConnectionManager) readProcessNetNS(pid int32) uint64 {
+	inode, err := host.NetworkNamespaceInode(pid)
+	if err != nil {
+		log.Debugf("cannot read the network namespace of the process: %d, %v", pid, err)
+		return 0
+	}
+	return inode
+}
+
+func appendPidIfNotExist(pids []int32, pid int32) []int32 {
+	for _, p := range pids {
+		if p == pid {
+			return pids
+		}
+	}
+	return append(pids, pid)
 }
 
 func (c *ConnectionManager) printTotalAddressesWithPid(prefix string) {
@@ -478,7 +674,7 @@ func (c *ConnectionManager) printTotalAddressesWithPid(prefix string) {
 	log.Debugf("----------------------------")
 	log.Debugf("total local address with pid: %d", len(c.localIPWithPid))
 	for k, v := range c.localIPWithPid {
-		log.Debugf("local address: %s, pid: %d", k, v)
+		log.Debugf("local address: %s, pids: %v", k, v)
 	}
 	log.Debugf("----------------------------")
 }
@@ -496,6 +692,10 @@ func (c *ConnectionManager) checkConnectionIsExist(con *ConnectionInfo) bool {
 	var activateConn ActiveConnection
 	if err := c.activeConnectionMap.Lookup(con.ConnectionID, &activateConn); err != nil {
 		if errors.Is(err, ebpf.ErrKeyNotExist) {
+			// the discovered connection is alive while the file descriptor is still opened
+			if con.Discovered && c.fileDescriptorExist(con.ConnectionID) {
+				return true
+			}
 			con.MarkDeletable = true
 			return false
 		}
@@ -510,6 +710,54 @@ func (c *ConnectionManager) checkConnectionIsExist(con *ConnectionInfo) bool {
 	return true
 }
 
+// pruneHandoverState remove the processes and connections exited while the rover is restarting from the pinned maps,
+// since no close event of them would be received, and keep the alive processes for rehydrating
+func (c *ConnectionManager) pruneHandoverState() {
+	exitedProcesses := make([]uint32, 0)
+	processes := c.processMonitorMap.Iterate()
+	var pid, monitor uint32
+	for processes.Next(&pid, &monitor) {
+		if _, err := os.Stat(host.GetHostProcInHost(fmt.Sprintf("%d", pid))); err != nil {
+			exitedProcesses = append(exitedProcesses, pid)
+			continue
+		}
+		c.handoverProcesses.Store(int32(pid), true)
+	}
+	if err := processes.Err(); err != nil {
+		log.Warnf("iterate the handover processes failure: %v", err)
+	}
+	for _, p := range exitedProcesses {
+		if err := c.processMonitorMap.Delete(p); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			log.Warnf("delete the exited handover process %d failure: %v", p, err)
+		}
+	}
+
+	closedConnections := make([]uint64, 0)
+	connections := c.activeConnectionMap.Iterate()
+	var conID uint64
+	var activateConn ActiveConnection
+	for connections.Next(&conID, &activateConn) {
+		if !c.fileDescriptorExist(conID) {
+			closedConnections = append(closedConnections, conID)
+		}
+	}
+	if err := connections.Err(); err != nil {
+		log.Warnf("iterate the handover connections failure: %v", err)
+	}
+	for _, id := range closedConnections {
+		if err := c.activeConnectionMap.Delete(id); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			log.Warnf("delete the closed handover connection %d failure: %v", id, err)
+		}
+	}
+	log.Infof("pruned the handover state, exited processes: %d, closed connections: %d", len(exitedProcesses), len(closedConnections))
+}
+
+func (c *ConnectionManager) fileDescriptorExist(conID uint64) bool {
+	pid, fd := events.ParseConnectionID(conID)
+	_, err := os.Stat(host.GetHostProcInHost(fmt.Sprintf("%d/fd/%d", pid, fd)))
+	return err == nil
+}
+
 func (c *ConnectionManager) RemoveProcess(pid int32, _ []api.ProcessInterface) {
 	c.monitoringProcessLock.Lock()
 	defer c.monitoringProcessLock.Unlock()
@@ -554,12 +802,19 @@ func (c *ConnectionManager) RecheckAllProcesses(processes map[int32][]api.Proces
 			}
 		}
 	}
+	startup := !c.startupSynced.Swap(true)
 	for pid := range c.monitoringProcesses {
+		_, handover := c.handoverProcesses.LoadAndDelete(pid)
 		if _, ok := processInBPF[pid]; !ok {
 			c.updateMonitorStatusForProcess(pid, true)
-			for _, l := range c.processListeners {
-				l.OnNewProcessMonitoring(pid)
-			}
+		} else if !handover {
+			continue
+		}
+		for _, l := range c.processListeners {
+			l.OnNewProcessMonitoring(pid)
+		}
+		if handover || startup {
+			c.notifyExistingProcessMonitoring(pid)
 		}
 	}
 
@@ -616,6 +871,9 @@ func (c *ConnectionManager) OnBuildConnectionLogFinished() {
 	for key := range deletableConnections {
 		log.Debugf("deleting the connection in manager: %s", key)
 		c.connections.Remove(key)
+		if c.ownership != nil {
+			c.ownership.Release(key)
+		}
 	}
 }
 
@@ -651,6 +909,27 @@ func (c *ConnectionManager) SkipAllDataAnalyzeAndDowngradeProtocol(conID, ranID
 	}
 }
 
+// ExistingConnectionRandomID generate the random ID of the connection which established before monitoring,
+// the handed over connection in the BPF reuses its random ID, otherwise the random ID only lives in the user space,
+// the connection is replaced when the BPF observes the data of it and creates the active connection
+func (c *ConnectionManager) ExistingConnectionRandomID(conID uint64, socket *ip.SocketPair) (uint64, error) {
+	var activateConn ActiveConnection
+	if err := c.activeConnectionMap.Lookup(conID, &activateConn); err == nil {
+		// the connection handed over from the previous rover, the file descriptor could be reused by another socket
+		// when the connection is closed during the restarting, so the stale one is removed
+		if activateConn.LocalPort == 0 || (activateConn.LocalPort == socket.SrcPort && activateConn.RemotePort == socket.DestPort) {
+			return activateConn.RandomID, nil
+		}
+		if err = c.activeConnectionMap.Delete(conID); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			return 0, err
+		}
+	} else if !errors.Is(err, ebpf.ErrKeyNotExist) {
+		return 0, err
+	}
+	// same range with the random ID generated in the BPF
+	return uint64(rand.Uint32()), nil
+}
+
 func getSocketPairFromConnectEvent(event events.Event) (*events.SocketConnectEvent, *ip.SocketPair) {
 	if e, ok := event.(*ConnectEventWithSocket); ok {
 		return e.SocketConnectEvent, e.SocketPair
@@ -659,15 +938,17 @@ func getSocketPairFromConnectEvent(event events.Event) (*events.SocketConnectEve
 }
 
 type ActiveConnection struct {
-	RandomID       uint64
-	PID            uint32
-	SocketFD       uint32
-	Role           uint32
-	SocketFamily   uint32
-	Protocol       uint8
-	SSL            uint8
-	SkipDataUpload uint8
-	// PAD for make sure it have same size when marshal data to the BPF
-	PAD0 uint8
-	PAD1 uint32
+	RandomID            uint64
+	PID                 uint32
+	SocketFD            uint32
+	Role                uint32
+	SocketFamily        uint32
+	Protocol            uint8
+	SSL                 uint8
+	SkipDataUpload      uint8
+	TLSHandshakeUploads uint8
+	LocalPort           uint16
+	RemotePort          uint16
+	Transport           uint8
+	_                   [7]uint8
 }
@@ -0,0 +1,72 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import "testing"
+
+func TestSelectListeningPid(t *testing.T) {
+	listening := map[int32]uint16{2: 8080, 3: 8080, 4: 9090}
+	tests := []struct {
+		name     string
+		pids     []int32
+		netNS    map[int32]uint64
+		port     uint16
+		expected int32
+		checked  []int32
+	}{
+		{name: "no process", port: 8080},
+		{name: "single process without checking", pids: []int32{4}, port: 8080, expected: 4},
+		{name: "listening process", pids: []int32{1, 2}, netNS: map[int32]uint64{1: 100, 2: 200}, port: 8080,
+			expected: 2, checked: []int32{1, 2}},
+		{name: "same network namespace checked once", pids: []int32{1, 2, 4}, netNS: map[int32]uint64{1: 100, 2: 100, 4: 300}, port: 9090,
+			expected: 4, checked: []int32{1, 4}},
+		{name: "unknown network namespace always checked", pids: []int32{1, 5, 3}, port: 8080,
+			expected: 3, checked: []int32{1, 5, 3}},
+		{name: "no process listening", pids: []int32{1, 5}, netNS: map[int32]uint64{1: 100, 5: 500}, port: 7070,
+			expected: 1, checked: []int32{1, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked []int32
+			actual := selectListeningPid(tt.pids, tt.netNS, tt.port, func(pid int32, port uint16) bool {
+				checked = append(checked, pid)
+				return listening[pid] == port
+			})
+			if actual != tt.expected {
+				t.Errorf("expected pid: %d, actual: %d", tt.expected, actual)
+			}
+			if len(checked) != len(tt.checked) {
+				t.Fatalf("expected checked pids: %v, actual: %v", tt.checked, checked)
+			}
+			for i := range checked {
+				if checked[i] != tt.checked[i] {
+					t.Fatalf("expected checked pids: %v, actual: %v", tt.checked, checked)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendPidIfNotExist(t *testing.T) {
+	pids := appendPidIfNotExist(nil, 1)
+	pids = appendPidIfNotExist(pids, 2)
+	pids = appendPidIfNotExist(pids, 1)
+	if len(pids) != 2 || pids[0] != 1 || pids[1] != 2 {
+		t.Errorf("unexpected pids: %v", pids)
+	}
+}
@@ -0,0 +1,121 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"strings"
+	"time"
+)
+
+// CorrelationRecord is the request observed in the kernel which contains the correlation header,
+// it could be joined with the access logs of the proxy(such as the Envoy sidecar) through the request ID
+// or the connection address and timestamps
+type CorrelationRecord struct {
+	ConnectionID uint64
+	RandomID     uint64
+	RequestID    string
+	// the BPF time of the request start and response end
+	StartTime  uint64
+	EndTime    uint64
+	Method     string
+	Path       string
+	StatusCode int
+	// the values of the extra headers which exists in the request
+	Headers map[string]string
+
+	// the time of the record been created, for expiring the record which connection is not found
+	CreateTime time.Time
+}
+
+// CorrelationQueue cache all the correlation records until the next flush
+type CorrelationQueue struct {
+	Header       string
+	ExtraHeaders []string
+
+	FlushQueue[*CorrelationRecord]
+}
+
+func NewCorrelationQueue(header, extraHeaders string) *CorrelationQueue {
+	return &CorrelationQueue{Header: strings.ToLower(header), ExtraHeaders: ParseCorrelationHeaders(extraHeaders)}
+}
+
+// ParseCorrelationHeaders split the comma separated header names, the names are lower cased and deduplicated
+func ParseCorrelationHeaders(headers string) []string {
+	result := make([]string, 0)
+	exists := make(map[string]bool)
+	for _, h := range strings.Split(headers, ",") {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" || exists[h] {
+			continue
+		}
+		exists[h] = true
+		result = append(result, h)
+	}
+	return result
+}
+
+type correlationPropagationKey struct {
+	pid       uint32
+	requestID string
+}
+
+// CorrelationPropagation counts the outbound requests which carry the same correlation value in the same process,
+// so the inbound request could report how many downstream requests the correlation value is propagated to.
+// The outbound requests are always finished before the inbound request which triggered them, so they are recorded first.
+type CorrelationPropagation struct {
+	retain   time.Duration
+	outbound map[correlationPropagationKey]*correlationPropagationCount
+}
+
+type correlationPropagationCount struct {
+	count    int
+	lastTime time.Time
+}
+
+func NewCorrelationPropagation(retain time.Duration) *CorrelationPropagation {
+	return &CorrelationPropagation{retain: retain, outbound: make(map[correlationPropagationKey]*correlationPropagationCount)}
+}
+
+// RecordOutbound records the outbound request of the process
+func (p *CorrelationPropagation) RecordOutbound(pid uint32, requestID string, now time.Time) {
+	key := correlationPropagationKey{pid: pid, requestID: requestID}
+	count := p.outbound[key]
+	if count == nil {
+		count = &correlationPropagationCount{}
+		p.outbound[key] = count
+	}
+	count.count++
+	count.lastTime = now
+}
+
+// PropagatedCount returns the count of outbound requests of the process which carry the correlation value
+func (p *CorrelationPropagation) PropagatedCount(pid uint32, requestID string) int {
+	if count := p.outbound[correlationPropagationKey{pid: pid, requestID: requestID}]; count != nil {
+		return count.count
+	}
+	return 0
+}
+
+// Expire removes the outbound records which is not been updated in the retain duration
+func (p *CorrelationPropagation) Expire(now time.Time) {
+	for key, count := range p.outbound {
+		if now.Sub(count.lastTime) > p.retain {
+			delete(p.outbound, key)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCorrelationHeaders(t *testing.T) {
+	tests := []struct {
+		headers string
+		result  []string
+	}{
+		{headers: "", result: []string{}},
+		{headers: "X-B3-TraceId", result: []string{"x-b3-traceid"}},
+		{headers: " x-tenant , x-session,,X-Tenant", result: []string{"x-tenant", "x-session"}},
+	}
+	for _, tt := range tests {
+		if result := ParseCorrelationHeaders(tt.headers); !reflect.DeepEqual(result, tt.result) {
+			t.Fatalf("parse %q: expected %v, actual %v", tt.headers, tt.result, result)
+		}
+	}
+}
+
+func TestCorrelationPropagation(t *testing.T) {
+	now := time.Now()
+	propagation := NewCorrelationPropagation(time.Minute)
+	propagation.RecordOutbound(1, "req-1", now.Add(-2*time.Minute))
+	propagation.RecordOutbound(1, "req-2", now)
+	propagation.RecordOutbound(1, "req-2", now)
+	propagation.RecordOutbound(2, "req-2", now)
+	propagation.Expire(now)
+
+	tests := []struct {
+		pid       uint32
+		requestID string
+		count     int
+	}{
+		{pid: 1, requestID: "req-1", count: 0},
+		{pid: 1, requestID: "req-2", count: 2},
+		{pid: 2, requestID: "req-2", count: 1},
+		{pid: 3, requestID: "req-2", count: 0},
+	}
+	for _, tt := range tests {
+		if count := propagation.PropagatedCount(tt.pid, tt.requestID); count != tt.count {
+			t.Fatalf("pid %d with %s: expected %d, actual %d", tt.pid, tt.requestID, tt.count, count)
+		}
+	}
+}
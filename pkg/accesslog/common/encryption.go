@@ -0,0 +1,67 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/cni"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+const cniEncryptionRefreshInterval = time.Minute
+
+// startDetectCNIEncryption refresh the encrypted routes of the CNI periodically, since the peers(nodes) could be changed
+func (c *ConnectionManager) startDetectCNIEncryption(ctx context.Context) {
+	refresh := func() {
+		table, err := cni.DetectEncryption()
+		if err != nil {
+			log.Debugf("detect the CNI encryption failure: %v", err)
+		}
+		if previous := c.cniEncryption.Swap(table); previous == nil {
+			log.Infof("detected the CNI encryption types: %v", table.Types())
+		}
+	}
+	refresh()
+	go func() {
+		ticker := time.NewTicker(cniEncryptionRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refresh()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// detectConnectionEncryption check the traffic to the remote address is encrypted by the CNI or not,
+// the remote address in the same node never leave the node, so it's not encrypted
+func (c *ConnectionManager) detectConnectionEncryption(remoteIP string, remote *v3.ConnectionAddress) cni.EncryptionType {
+	if _, isIP := remote.GetAddress().(*v3.ConnectionAddress_Ip); !isIP {
+		return cni.EncryptionNone
+	}
+	if route := c.cniEncryption.Load().Match(remoteIP); route != nil {
+		return route.Type
+	}
+	return cni.EncryptionNone
+}
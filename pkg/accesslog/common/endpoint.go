@@ -0,0 +1,121 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	endpointNumberPlaceholder = "{number}"
+	endpointIDPlaceholder     = "{id}"
+	// the hex segment at least this length is treated as an identity, such as the hash or object id
+	endpointMinHexIDLength = 16
+)
+
+var (
+	endpointNumberSegment = regexp.MustCompile(`^[0-9]+$`)
+	endpointUUIDSegment   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	endpointHexSegment    = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+type endpointRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// EndpointNormalizer shapes the HTTP paths before reporting as the endpoint names, to control the endpoint cardinality
+type EndpointNormalizer struct {
+	rules      []*endpointRule
+	collapseID bool
+	maxDepth   int
+}
+
+// NewEndpointNormalizer creates the normalizer, the rules are separated by ";", each rule is "regex=>replacement"
+func NewEndpointNormalizer(rules string, collapseID bool, maxDepth int) (*EndpointNormalizer, error) {
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("the max depth cannot be small than 0")
+	}
+	n := &EndpointNormalizer{collapseID: collapseID, maxDepth: maxDepth}
+	for _, rule := range strings.Split(rules, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		pattern, replacement, found := strings.Cut(rule, "=>")
+		if !found {
+			return nil, fmt.Errorf("the endpoint rule must be in the format of regex=>replacement: %s", rule)
+		}
+		reg, err := regexp.Compile(strings.TrimSpace(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("compile the endpoint rule %s failure: %v", rule, err)
+		}
+		n.rules = append(n.rules, &endpointRule{pattern: reg, replacement: strings.TrimSpace(replacement)})
+	}
+	return n, nil
+}
+
+// Normalize the path without query, the regex rules are applied first,
+// then collapsing the identity segments and truncating by the depth
+func (n *EndpointNormalizer) Normalize(path string) string {
+	if n == nil {
+		return path
+	}
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	for _, rule := range n.rules {
+		path = rule.pattern.ReplaceAllString(path, rule.replacement)
+	}
+	if !n.collapseID && n.maxDepth == 0 {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	// the first segment is empty when the path starts with "/"
+	depth := 0
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		depth++
+		if n.maxDepth > 0 && depth > n.maxDepth {
+			segments = segments[:i]
+			break
+		}
+		if n.collapseID {
+			segments[i] = collapseEndpointSegment(segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func collapseEndpointSegment(segment string) string {
+	switch {
+	case endpointNumberSegment.MatchString(segment):
+		return endpointNumberPlaceholder
+	case endpointUUIDSegment.MatchString(segment):
+		return endpointIDPlaceholder
+	case len(segment) >= endpointMinHexIDLength && endpointHexSegment.MatchString(segment):
+		return endpointIDPlaceholder
+	default:
+		return segment
+	}
+}
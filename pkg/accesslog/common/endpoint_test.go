@@ -0,0 +1,65 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import "testing"
+
+func TestEndpointNormalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      string
+		collapseID bool
+		maxDepth   int
+		path       string
+		expected   string
+	}{
+		{name: "only strip query", path: "/api/users?page=1", expected: "/api/users"},
+		{
+			name: "collapse identities", collapseID: true,
+			path:     "/api/users/123/orders/3f2b8c1e-4a5d-4e6f-8a9b-0c1d2e3f4a5b/items/0123456789abcdef0123",
+			expected: "/api/users/{number}/orders/{id}/items/{id}",
+		},
+		{name: "short hex is kept", collapseID: true, path: "/api/cafe/beef", expected: "/api/cafe/beef"},
+		{name: "truncate depth", maxDepth: 2, path: "/api/users/123/orders", expected: "/api/users"},
+		{name: "depth not exceed", maxDepth: 4, path: "/api/users/", expected: "/api/users/"},
+		{
+			name: "regex rules before collapsing", rules: "^/v[0-9]+/ => /; /users/[^/]+/profile$=>/users/{name}/profile",
+			collapseID: true, path: "/v2/users/alice/profile", expected: "/users/{name}/profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewEndpointNormalizer(tt.rules, tt.collapseID, tt.maxDepth)
+			if err != nil {
+				t.Fatalf("create normalizer failure: %v", err)
+			}
+			if actual := n.Normalize(tt.path); actual != tt.expected {
+				t.Fatalf("expected %s, actual %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEndpointNormalizerInvalidRules(t *testing.T) {
+	for _, rules := range []string{"/users/[0-9+", "/users/[0-9]+"} {
+		if _, err := NewEndpointNormalizer(rules, false, 0); err == nil {
+			t.Fatalf("the rule %s should be invalid", rules)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/ebpf"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+const excludeAddressRefreshInterval = time.Minute
+
+// excludeAddress is the key of the excluded remote address in BPF,
+// the IPv4 address is mapped as the IPv6, the port 0 means any port
+type excludeAddress struct {
+	Addr [16]byte
+	Port uint32
+}
+
+// startExcludeTraffic exclude the traffic of rover itself and the configured processes and remote addresses in BPF,
+// the remote addresses are resolved periodically since the domain(such as the OAP service) could be changed
+func (c *ConnectionManager) startExcludeTraffic(ctx context.Context) {
+	for _, pid := range c.excludePIDs() {
+		if err := c.processExcludeMap.Update(pid, uint32(1), ebpf.UpdateAny); err != nil {
+			log.Warnf("failed to exclude the traffic of process %d: %v", pid, err)
+		} else {
+			log.Infof("excluded the traffic of process %d", pid)
+		}
+	}
+
+	excluded := make(map[excludeAddress]bool)
+	refresh := func() {
+		addresses := c.resolveExcludeAddresses()
+		for addr := range addresses {
+			if excluded[addr] {
+				continue
+			}
+			if err := c.excludeAddressMap.Update(addr, uint32(1), ebpf.UpdateAny); err != nil {
+				log.Warnf("failed to exclude the traffic of address %s:%d: %v", net.IP(addr.Addr[:]), addr.Port, err)
+				continue
+			}
+			excluded[addr] = true
+		}
+		for addr := range excluded {
+			if addresses[addr] {
+				continue
+			}
+			if err := c.excludeAddressMap.Delete(addr); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+				log.Warnf("failed to delete the excluded address %s:%d: %v", net.IP(addr.Addr[:]), addr.Port, err)
+				continue
+			}
+			delete(excluded, addr)
+		}
+	}
+	refresh()
+	go func() {
+		ticker := time.NewTicker(excludeAddressRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refresh()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (c *ConnectionManager) excludePIDs() []uint32 {
+	result := make([]uint32, 0)
+	if c.excludeConfig.Self {
+		result = append(result, selfPIDInHost())
+	}
+	for _, p := range strings.Split(c.excludeConfig.PIDs, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		pid, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			log.Warnf("ignore the invalid excluded pid: %s", p)
+			continue
+		}
+		result = append(result, uint32(pid))
+	}
+	return result
+}
+
+// selfPIDInHost is the pid of rover in the host, since rover could be running in the container with an isolated PID namespace
+func selfPIDInHost() uint32 {
+	if link, err := os.Readlink(host.GetHostProcInHost("self")); err == nil {
+		if pid, err := strconv.ParseUint(link, 10, 32); err == nil {
+			return uint32(pid)
+		}
+	}
+	return uint32(os.Getpid())
+}
+
+func (c *ConnectionManager) resolveExcludeAddresses() map[excludeAddress]bool {
+	endpoints := make([]string, 0)
+	if c.excludeConfig.Backend {
+		if coreOperator, ok := c.moduleMgr.FindModule(core.ModuleName).(core.Operator); ok {
+			endpoints = append(endpoints, coreOperator.BackendOperator().Addresses()...)
+		}
+	}
+	endpoints = append(endpoints, strings.Split(c.excludeConfig.Addresses, ",")...)
+
+	result := make(map[excludeAddress]bool)
+	for _, endpoint := range endpoints {
+		if endpoint = strings.TrimSpace(endpoint); endpoint == "" {
+			continue
+		}
+		addresses, err := parseExcludeAddress(endpoint)
+		if err != nil {
+			log.Warnf("resolve the excluded address %s failure: %v", endpoint, err)
+			continue
+		}
+		for _, addr := range addresses {
+			result[addr] = true
+		}
+	}
+	return result
+}
+
+// parseExcludeAddress parse the address as "host", "host:port" or "[ipv6]:port", the domain is resolved as all IP addresses
+func parseExcludeAddress(endpoint string) ([]excludeAddress, error) {
+	hostname, port := strings.Trim(endpoint, "[]"), uint64(0)
+	if h, p, err := net.SplitHostPort(endpoint); err == nil {
+		if port, err = strconv.ParseUint(p, 10, 16); err != nil {
+			return nil, err
+		}
+		hostname = h
+	}
+	ips := []net.IP{net.ParseIP(hostname)}
+	if ips[0] == nil {
+		var err error
+		if ips, err = net.LookupIP(hostname); err != nil {
+			return nil, err
+		}
+	}
+	result := make([]excludeAddress, 0, len(ips))
+	for _, ip := range ips {
+		addr := excludeAddress{Port: uint32(port)}
+		copy(addr.Addr[:], ip.To16())
+		result = append(result, addr)
+	}
+	return result, nil
+}
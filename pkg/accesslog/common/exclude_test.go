@@ -0,0 +1,56 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseExcludeAddress(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		ip       string
+		port     uint32
+		err      bool
+	}{
+		{endpoint: "10.0.0.1", ip: "10.0.0.1"},
+		{endpoint: "10.0.0.1:11800", ip: "10.0.0.1", port: 11800},
+		{endpoint: "[fd00::1]:11800", ip: "fd00::1", port: 11800},
+		{endpoint: "fd00::1", ip: "fd00::1"},
+		{endpoint: "10.0.0.1:port", err: true},
+	}
+	for _, tt := range tests {
+		addresses, err := parseExcludeAddress(tt.endpoint)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("%s: should be failure", tt.endpoint)
+			}
+			continue
+		}
+		if err != nil || len(addresses) != 1 {
+			t.Fatalf("%s: unexpected result: %v, %v", tt.endpoint, addresses, err)
+		}
+		var expected [16]byte
+		copy(expected[:], net.ParseIP(tt.ip).To16())
+		if addresses[0].Addr != expected || addresses[0].Port != tt.port {
+			t.Fatalf("%s: expected: %s:%d, actual: %s:%d", tt.endpoint, tt.ip, tt.port,
+				net.IP(addresses[0].Addr[:]), addresses[0].Port)
+		}
+	}
+}
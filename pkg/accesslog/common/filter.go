@@ -20,8 +20,10 @@ package common
 import (
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 	"github.com/apache/skywalking-rover/pkg/process/finders/kubernetes"
 )
 
@@ -33,37 +35,53 @@ type MonitorFilter interface {
 }
 
 type StaticMonitorFilter struct {
+	lock               sync.RWMutex
 	namespaces         map[string]bool
 	clusters           map[string]bool
 	originalNamespaces []string
+	processFilter      *filter.Filter
 }
 
-func NewStaticMonitorFilter(namespaces, clusters []string) *StaticMonitorFilter {
+func NewStaticMonitorFilter(namespaces, clusters []string, processFilter *filter.Filter) *StaticMonitorFilter {
 	return &StaticMonitorFilter{
 		namespaces:         convertArrayToMapBool(namespaces),
 		clusters:           convertArrayToMapBool(clusters),
 		originalNamespaces: namespaces,
+		processFilter:      processFilter,
 	}
 }
 
+// Update the excluded namespaces and clusters, the monitoring processes are rechecked by the process listener
+func (s *StaticMonitorFilter) Update(namespaces, clusters []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.namespaces = convertArrayToMapBool(namespaces)
+	s.clusters = convertArrayToMapBool(clusters)
+	s.originalNamespaces = namespaces
+}
+
 func (s *StaticMonitorFilter) ShouldIncludeProcesses(processes []api.ProcessInterface) (res []api.ProcessInterface) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	var selfPid = os.Getpid()
 	for _, entity := range processes {
 		if int(entity.Pid()) == selfPid {
 			continue
 		}
-		if entity.DetectType() != api.Kubernetes { // for now, we only have the kubernetes detected processes
+		if entity.DetectType() != api.Kubernetes && entity.DetectType() != api.VM {
 			continue
 		}
-		namespace := entity.DetectProcess().(*kubernetes.Process).PodContainer().Pod.Namespace
-		nameExclude := s.namespaces[namespace]
+		nameExclude := false
+		if entity.DetectType() == api.Kubernetes {
+			nameExclude = s.namespaces[entity.DetectProcess().(*kubernetes.Process).PodContainer().Pod.Namespace]
+		}
 		clusterExclude := false
 		if cluster, _, found := strings.Cut(entity.Entity().ServiceName, "::"); found && s.clusters[cluster] {
 			clusterExclude = true
 		}
 
-		// if the namespace and cluster are not excluded, include the process
-		if !nameExclude && !clusterExclude {
+		// if the namespace and cluster are not excluded, and matches the process filter, include the process
+		if !nameExclude && !clusterExclude && s.processFilter.Match(entity) {
 			res = append(res, entity)
 		}
 	}
@@ -71,6 +89,8 @@ func (s *StaticMonitorFilter) ShouldIncludeProcesses(processes []api.ProcessInte
 }
 
 func (s *StaticMonitorFilter) ExcludeNamespaces() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	return s.originalNamespaces
 }
 
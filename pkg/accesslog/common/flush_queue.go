@@ -0,0 +1,74 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+// FlushQueue cache all the elements(such as RPC records) until the next flush of the collector
+type FlushQueue[T any] struct {
+	mutex    sync.Mutex
+	elements []T
+}
+
+func NewFlushQueue[T any]() *FlushQueue[T] {
+	return &FlushQueue[T]{}
+}
+
+func (q *FlushQueue[T]) Append(elements ...T) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.elements = append(q.elements, elements...)
+}
+
+// Swap return all the cached elements and clean the queue
+func (q *FlushQueue[T]) Swap() []T {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	result := q.elements
+	q.elements = nil
+	return result
+}
+
+// Retainer keeps the elements which cannot be handled in the current flush(such as the connection is not built yet),
+// the retained elements are returned again in the next flush until they exceed the retain time
+type Retainer[T any] struct {
+	retain     time.Duration
+	createTime func(T) time.Time
+	pending    []T
+}
+
+func NewRetainer[T any](retain time.Duration, createTime func(T) time.Time) *Retainer[T] {
+	return &Retainer[T]{retain: retain, createTime: createTime}
+}
+
+// Merge return the retained elements with the new elements, and clean the retained elements
+func (r *Retainer[T]) Merge(elements []T) []T {
+	result := append(r.pending, elements...)
+	r.pending = nil
+	return result
+}
+
+// Retain keeps the element to the next flush, the element is dropped when exceed the retain time
+func (r *Retainer[T]) Retain(element T) {
+	if time.Since(r.createTime(element)) < r.retain {
+		r.pending = append(r.pending, element)
+	}
+}
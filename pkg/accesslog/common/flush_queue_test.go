@@ -0,0 +1,70 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlushQueue(t *testing.T) {
+	queue := NewFlushQueue[int]()
+	queue.Append(1, 2)
+	queue.Append(3)
+	if result := queue.Swap(); !reflect.DeepEqual(result, []int{1, 2, 3}) {
+		t.Errorf("expected elements: %v, actual: %v", []int{1, 2, 3}, result)
+	}
+	if result := queue.Swap(); len(result) != 0 {
+		t.Errorf("the queue should be cleaned after swap, actual: %v", result)
+	}
+}
+
+func TestRetainer(t *testing.T) {
+	now := time.Now()
+	retainer := NewRetainer(time.Minute, func(createTime time.Time) time.Time {
+		return createTime
+	})
+
+	tests := []struct {
+		name     string
+		element  time.Time
+		retained bool
+	}{
+		{"fresh", now, true},
+		{"inside retain time", now.Add(-time.Second * 30), true},
+		{"expired", now.Add(-time.Minute * 2), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retainer.Retain(tt.element)
+			merged := retainer.Merge(nil)
+			if retained := len(merged) == 1; retained != tt.retained {
+				t.Errorf("expected retained: %v, actual: %v", tt.retained, retained)
+			}
+			if result := retainer.Merge(nil); len(result) != 0 {
+				t.Errorf("the retained elements should be cleaned after merge, actual: %v", result)
+			}
+		})
+	}
+
+	retainer.Retain(now)
+	if result := retainer.Merge([]time.Time{now.Add(time.Second)}); len(result) != 2 || !result[0].Equal(now) {
+		t.Errorf("expected the retained element before the new elements, actual: %v", result)
+	}
+}
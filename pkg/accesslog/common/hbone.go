@@ -0,0 +1,109 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// HBONEPort is the port of the ztunnel accepting the HBONE(HTTP/2 CONNECT over mTLS) tunnels
+	HBONEPort = 15008
+	// the expiry of the HBONE streams which not correlated with the connections
+	hboneStreamExpireDuration = time.Minute
+)
+
+// HBONEStream is the CONNECT stream in the HBONE tunnel, each stream is carrying one workload connection
+type HBONEStream struct {
+	// the tunnel connection which carrying the stream
+	TunnelConnectionID uint64
+	TunnelRandomID     uint64
+	// the real destination parsed from the CONNECT authority
+	DestIP   string
+	DestPort uint16
+	// the peer address of the tunnel, it's the source workload IP when the ztunnel running in the pod network namespace
+	PeerIP string
+}
+
+// ParseHBONEConnect parse the real destination from the CONNECT request headers of the HTTP/2 stream
+func ParseHBONEConnect(headers map[string]string) (ip string, port uint16, err error) {
+	if headers[":method"] != "CONNECT" {
+		return "", 0, fmt.Errorf("not the CONNECT request")
+	}
+	host, portStr, err := net.SplitHostPort(headers[":authority"])
+	if err != nil {
+		return "", 0, err
+	}
+	if net.ParseIP(host) == nil {
+		return "", 0, fmt.Errorf("the authority host is not an IP address: %s", host)
+	}
+	p, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("illegal authority port: %s", portStr)
+	}
+	return host, uint16(p), nil
+}
+
+// HBONETunnels caching the HBONE streams for correlating the workload connections with the tunnel connections
+type HBONETunnels struct {
+	// the latest stream of each destination and peer
+	streams *cache.Expiring
+	// the latest stream of each tunnel connection
+	tunnels *cache.Expiring
+}
+
+func NewHBONETunnels() *HBONETunnels {
+	return &HBONETunnels{
+		streams: cache.NewExpiring(),
+		tunnels: cache.NewExpiring(),
+	}
+}
+
+func (h *HBONETunnels) Add(stream *HBONEStream) {
+	h.streams.Set(h.streamKey(stream.DestIP, stream.PeerIP), stream, hboneStreamExpireDuration)
+	h.tunnels.Set(h.tunnelKey(stream.TunnelConnectionID, stream.TunnelRandomID), stream, hboneStreamExpireDuration)
+}
+
+// Find the stream carrying the workload connection to the destination IP from the peer IP
+func (h *HBONETunnels) Find(destIP, peerIP string) *HBONEStream {
+	if stream, ok := h.streams.Get(h.streamKey(destIP, peerIP)); ok {
+		return stream.(*HBONEStream)
+	}
+	return nil
+}
+
+// FindByTunnel find the latest stream in the tunnel connection
+func (h *HBONETunnels) FindByTunnel(connectionID, randomID uint64) *HBONEStream {
+	if stream, ok := h.tunnels.Get(h.tunnelKey(connectionID, randomID)); ok {
+		return stream.(*HBONEStream)
+	}
+	return nil
+}
+
+func (h *HBONETunnels) streamKey(destIP, peerIP string) string {
+	return destIP + "-" + peerIP
+}
+
+func (h *HBONETunnels) tunnelKey(connectionID, randomID uint64) string {
+	return fmt.Sprintf("%d_%d", connectionID, randomID)
+}
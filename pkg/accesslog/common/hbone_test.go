@@ -0,0 +1,63 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import "testing"
+
+func TestParseHBONEConnect(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		ip      string
+		port    uint16
+		err     bool
+	}{
+		{name: "ipv4", headers: map[string]string{":method": "CONNECT", ":authority": "10.0.0.2:8080"}, ip: "10.0.0.2", port: 8080},
+		{name: "ipv6", headers: map[string]string{":method": "CONNECT", ":authority": "[fd00::2]:9080"}, ip: "fd00::2", port: 9080},
+		{name: "not connect", headers: map[string]string{":method": "GET", ":authority": "10.0.0.2:8080"}, err: true},
+		{name: "host name", headers: map[string]string{":method": "CONNECT", ":authority": "reviews:9080"}, err: true},
+		{name: "no port", headers: map[string]string{":method": "CONNECT", ":authority": "10.0.0.2"}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, port, err := ParseHBONEConnect(tt.headers)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error: %t, actual: %v", tt.err, err)
+			}
+			if ip != tt.ip || port != tt.port {
+				t.Fatalf("expected %s:%d, actual: %s:%d", tt.ip, tt.port, ip, port)
+			}
+		})
+	}
+}
+
+func TestHBONETunnels(t *testing.T) {
+	tunnels := NewHBONETunnels()
+	tunnels.Add(&HBONEStream{TunnelConnectionID: 1, TunnelRandomID: 2, DestIP: "10.0.0.2", DestPort: 8080, PeerIP: "10.0.0.1"})
+	tunnels.Add(&HBONEStream{TunnelConnectionID: 1, TunnelRandomID: 2, DestIP: "10.0.0.3", DestPort: 9080, PeerIP: "10.0.0.1"})
+
+	if stream := tunnels.Find("10.0.0.2", "10.0.0.1"); stream == nil || stream.DestPort != 8080 {
+		t.Fatalf("the stream to 10.0.0.2 should be found, actual: %v", stream)
+	}
+	if stream := tunnels.Find("10.0.0.2", "10.0.0.4"); stream != nil {
+		t.Fatalf("the stream from other peer should not be found, actual: %v", stream)
+	}
+	if stream := tunnels.FindByTunnel(1, 2); stream == nil || stream.DestIP != "10.0.0.3" {
+		t.Fatalf("the latest stream of the tunnel should be found, actual: %v", stream)
+	}
+}
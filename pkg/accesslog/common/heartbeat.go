@@ -0,0 +1,95 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sync/atomic"
+	"time"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+// ConnectionHeartbeat is the transferred data of the long-lived connection since the last heartbeat
+type ConnectionHeartbeat struct {
+	Connection *ConnectionInfo
+	// the interval since the last heartbeat
+	Interval   time.Duration
+	WriteBytes uint64
+	ReadBytes  uint64
+	Requests   uint64
+	Errors     uint64
+}
+
+// connectionHeartbeatState is the counters of the connection when the last heartbeat
+type connectionHeartbeatState struct {
+	time       time.Time
+	writeBytes uint64
+	readBytes  uint64
+	requests   uint64
+	errors     uint64
+}
+
+// RecordRequest accumulate the request count of the connection, the HTTP response with 5xx status is counted as the error
+func (c *ConnectionInfo) RecordRequest(protocolLog *v3.AccessLogProtocolLogs) {
+	if protocolLog == nil {
+		return
+	}
+	atomic.AddUint64(&c.Requests, 1)
+	if http := protocolLog.GetHttp(); http != nil && http.GetResponse().GetStatusCode() >= 500 {
+		atomic.AddUint64(&c.Errors, 1)
+	}
+}
+
+// SnapshotHeartbeats build the heartbeats of the active connections which have been alive since the last snapshot,
+// the connections without any transfer in the interval are skipped
+func (c *ConnectionManager) SnapshotHeartbeats() []*ConnectionHeartbeat {
+	now := time.Now()
+	result := make([]*ConnectionHeartbeat, 0)
+	c.connections.IterCb(func(_ string, v interface{}) {
+		con, ok := v.(*ConnectionInfo)
+		if !ok || con == nil || con.MarkDeletable {
+			return
+		}
+		current := &connectionHeartbeatState{
+			time:       now,
+			writeBytes: atomic.LoadUint64(&con.WriteBytes),
+			readBytes:  atomic.LoadUint64(&con.ReadBytes),
+			requests:   atomic.LoadUint64(&con.Requests),
+			errors:     atomic.LoadUint64(&con.Errors),
+		}
+		last := con.heartbeat
+		con.heartbeat = current
+		// the first snapshot of the connection only records the counters
+		if last == nil {
+			return
+		}
+		heartbeat := &ConnectionHeartbeat{
+			Connection: con,
+			Interval:   current.time.Sub(last.time),
+			WriteBytes: current.writeBytes - last.writeBytes,
+			ReadBytes:  current.readBytes - last.readBytes,
+			Requests:   current.requests - last.requests,
+			Errors:     current.errors - last.errors,
+		}
+		if heartbeat.WriteBytes == 0 && heartbeat.ReadBytes == 0 && heartbeat.Requests == 0 {
+			return
+		}
+		result = append(result, heartbeat)
+	})
+	return result
+}
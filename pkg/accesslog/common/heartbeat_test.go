@@ -0,0 +1,74 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"testing"
+
+	cmap "github.com/orcaman/concurrent-map"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+func TestSnapshotHeartbeats(t *testing.T) {
+	mgr := &ConnectionManager{connections: cmap.New()}
+	con := &ConnectionInfo{ConnectionID: 1, RandomID: 1}
+	mgr.connections.Set("1_1", con)
+
+	httpLog := func(status int32) *v3.AccessLogProtocolLogs {
+		return &v3.AccessLogProtocolLogs{Protocol: &v3.AccessLogProtocolLogs_Http{Http: &v3.AccessLogHTTPProtocol{
+			Response: &v3.AccessLogHTTPProtocolResponse{StatusCode: status},
+		}}}
+	}
+
+	tests := []struct {
+		name       string
+		writeBytes uint64
+		statuses   []int32
+		closed     bool
+		heartbeat  *ConnectionHeartbeat
+	}{
+		{name: "first snapshot only records", writeBytes: 100, statuses: []int32{200}},
+		{name: "deltas since last snapshot", writeBytes: 50, statuses: []int32{200, 503},
+			heartbeat: &ConnectionHeartbeat{WriteBytes: 50, Requests: 2, Errors: 1}},
+		{name: "idle connection", heartbeat: nil},
+		{name: "closing connection", writeBytes: 10, closed: true, heartbeat: nil},
+	}
+	for _, tt := range tests {
+		con.WriteBytes += tt.writeBytes
+		for _, status := range tt.statuses {
+			con.RecordRequest(httpLog(status))
+		}
+		con.MarkDeletable = tt.closed
+		heartbeats := mgr.SnapshotHeartbeats()
+		if tt.heartbeat == nil {
+			if len(heartbeats) != 0 {
+				t.Fatalf("%s: should not have heartbeat, actual: %v", tt.name, heartbeats[0])
+			}
+			continue
+		}
+		if len(heartbeats) != 1 {
+			t.Fatalf("%s: should have one heartbeat, actual: %d", tt.name, len(heartbeats))
+		}
+		h := heartbeats[0]
+		if h.WriteBytes != tt.heartbeat.WriteBytes || h.Requests != tt.heartbeat.Requests || h.Errors != tt.heartbeat.Errors {
+			t.Fatalf("%s: expected write: %d, requests: %d, errors: %d, actual write: %d, requests: %d, errors: %d", tt.name,
+				tt.heartbeat.WriteBytes, tt.heartbeat.Requests, tt.heartbeat.Errors, h.WriteBytes, h.Requests, h.Errors)
+		}
+	}
+}
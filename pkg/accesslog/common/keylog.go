@@ -0,0 +1,53 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+)
+
+// TLSKeyLogs is the key log readers of the monitoring processes which export the TLS key log
+type TLSKeyLogs struct {
+	readers map[uint32]*ssl.KeyLogReader
+	lock    sync.RWMutex
+}
+
+func NewTLSKeyLogs() *TLSKeyLogs {
+	return &TLSKeyLogs{readers: make(map[uint32]*ssl.KeyLogReader)}
+}
+
+func (t *TLSKeyLogs) Add(pid uint32, reader *ssl.KeyLogReader) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.readers[pid] = reader
+}
+
+func (t *TLSKeyLogs) Remove(pid uint32) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.readers, pid)
+}
+
+// Find the key log reader of the process, return nil if the process not export the key log
+func (t *TLSKeyLogs) Find(pid uint32) *ssl.KeyLogReader {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.readers[pid]
+}
@@ -0,0 +1,64 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+// MeshHop is how the connection passes through the proxy of the service mesh
+type MeshHop string
+
+const (
+	MeshHopNone MeshHop = ""
+	// MeshHopToWaypoint the workload sends the traffic to the waypoint proxy
+	MeshHopToWaypoint MeshHop = "to_waypoint"
+	// MeshHopFromWaypoint the workload receives the traffic forwarded by the waypoint proxy
+	MeshHopFromWaypoint MeshHop = "from_waypoint"
+	// MeshHopWaypointInbound the connection is accepted by the waypoint proxy
+	MeshHopWaypointInbound MeshHop = "waypoint_inbound"
+	// MeshHopWaypointOutbound the connection is created by the waypoint proxy to the destination workload
+	MeshHopWaypointOutbound MeshHop = "waypoint_outbound"
+	// MeshHopToSidecar the application sends the traffic which is intercepted by the sidecar proxy
+	MeshHopToSidecar MeshHop = "to_sidecar"
+	// MeshHopFromSidecar the application receives the traffic forwarded by the sidecar proxy
+	MeshHopFromSidecar MeshHop = "from_sidecar"
+	// MeshHopSidecarInbound the connection is accepted by the sidecar proxy, include the intercepted outbound traffic
+	// of the application and the inbound traffic from other workloads
+	MeshHopSidecarInbound MeshHop = "sidecar_inbound"
+	// MeshHopSidecarOutbound the connection is created by the sidecar proxy
+	MeshHopSidecarOutbound MeshHop = "sidecar_outbound"
+)
+
+// the proxy modes of the istio agent
+const (
+	IstioProxyModeSidecar  = "sidecar"
+	IstioProxyModeRouter   = "router"
+	IstioProxyModeWaypoint = "waypoint"
+)
+
+// ParseIstioProxyMode parse the proxy mode from the command line arguments of the istio agent,
+// such as "/usr/local/bin/pilot-agent proxy waypoint --domain ...", return empty if it's not starting a proxy
+func ParseIstioProxyMode(args []string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] != "proxy" {
+			continue
+		}
+		switch args[i+1] {
+		case IstioProxyModeSidecar, IstioProxyModeRouter, IstioProxyModeWaypoint:
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import "testing"
+
+func TestParseIstioProxyMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "waypoint", args: []string{"/usr/local/bin/pilot-agent", "proxy", "waypoint", "--domain", "default.svc.cluster.local"},
+			expected: IstioProxyModeWaypoint},
+		{name: "sidecar", args: []string{"/usr/local/bin/pilot-agent", "proxy", "sidecar", "--domain", "default.svc.cluster.local"},
+			expected: IstioProxyModeSidecar},
+		{name: "router", args: []string{"/usr/local/bin/pilot-agent", "proxy", "router"}, expected: IstioProxyModeRouter},
+		{name: "unknown mode", args: []string{"/usr/local/bin/pilot-agent", "proxy", "unknown"}},
+		{name: "not proxy", args: []string{"/usr/local/bin/pilot-agent", "wait"}},
+		{name: "empty", args: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := ParseIstioProxyMode(tt.args); actual != tt.expected {
+				t.Errorf("expected: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
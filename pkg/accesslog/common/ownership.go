@@ -0,0 +1,78 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+)
+
+// connectionOwnership make sure each side of the TCP connection only attributed to one monitored connection,
+// the same side could be observed multiple times on the same node, such as the process in the host network pod
+// and the process in the node root namespace shared the same socket.
+// Both sides of the connection are kept when they are monitored processes on the same node,
+// because the client and server roles are reported separately.
+type connectionOwnership struct {
+	// tuple key -> owner connection key
+	owners map[string]string
+	// owner connection key -> tuple key
+	tuples map[string]string
+	lock   sync.Mutex
+}
+
+func newConnectionOwnership() *connectionOwnership {
+	return &connectionOwnership{
+		owners: make(map[string]string),
+		tuples: make(map[string]string),
+	}
+}
+
+// Claim the socket for the connection, return false if the socket is already owned by another connection
+func (o *connectionOwnership) Claim(connectionKey string, socket *ip.SocketPair) bool {
+	tuple := socketTupleKey(socket)
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if owner, exist := o.owners[tuple]; exist && owner != connectionKey {
+		return false
+	}
+	o.owners[tuple] = connectionKey
+	o.tuples[connectionKey] = tuple
+	return true
+}
+
+// Release the socket owned by the connection, then the socket could be claimed by others
+func (o *connectionOwnership) Release(connectionKey string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	tuple, exist := o.tuples[connectionKey]
+	if !exist {
+		return
+	}
+	delete(o.tuples, connectionKey)
+	if o.owners[tuple] == connectionKey {
+		delete(o.owners, tuple)
+	}
+}
+
+// socketTupleKey build the direction dependent key of the socket,
+// so the client and server side of the same connection have different keys
+func socketTupleKey(socket *ip.SocketPair) string {
+	return fmt.Sprintf("%s:%d-%s:%d", socket.SrcIP, socket.SrcPort, socket.DestIP, socket.DestPort)
+}
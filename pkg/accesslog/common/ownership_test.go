@@ -0,0 +1,53 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+)
+
+func TestConnectionOwnership(t *testing.T) {
+	client := &ip.SocketPair{SrcIP: "10.0.0.1", SrcPort: 34567, DestIP: "10.0.0.2", DestPort: 80}
+	server := &ip.SocketPair{SrcIP: "10.0.0.2", SrcPort: 80, DestIP: "10.0.0.1", DestPort: 34567}
+	other := &ip.SocketPair{SrcIP: "10.0.0.1", SrcPort: 34568, DestIP: "10.0.0.2", DestPort: 80}
+
+	ownership := newConnectionOwnership()
+	tests := []struct {
+		name   string
+		action func() bool
+		result bool
+	}{
+		{name: "client claim", action: func() bool { return ownership.Claim("1_1", client) }, result: true},
+		{name: "client claim again", action: func() bool { return ownership.Claim("1_1", client) }, result: true},
+		{name: "server side kept", action: func() bool { return ownership.Claim("2_1", server) }, result: true},
+		{name: "shared socket skipped", action: func() bool { return ownership.Claim("3_1", client) }, result: false},
+		{name: "other connection", action: func() bool { return ownership.Claim("4_1", other) }, result: true},
+		{name: "shared server socket skipped", action: func() bool { return ownership.Claim("5_1", server) }, result: false},
+		{name: "claim after release", action: func() bool {
+			ownership.Release("1_1")
+			return ownership.Claim("3_1", client)
+		}, result: true},
+	}
+	for _, tt := range tests {
+		if got := tt.action(); got != tt.result {
+			t.Fatalf("%s: expected %t, actual %t", tt.name, tt.result, got)
+		}
+	}
+}
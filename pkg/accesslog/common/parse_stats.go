@@ -0,0 +1,85 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+)
+
+// SelfMeterService is the service name of the meters which describe the rover itself
+const SelfMeterService = selfobs.ServiceName
+
+// ProtocolParseIssue is the kind of the data quality gap when analyzing the protocol data
+type ProtocolParseIssue string
+
+const (
+	// ProtocolParseIssueError the data cannot be parsed by the protocol analyzer
+	ProtocolParseIssueError ProtocolParseIssue = "parse_error"
+	// ProtocolParseIssueTruncated the payload is truncated because exceed the upload limit in the BPF
+	ProtocolParseIssueTruncated ProtocolParseIssue = "truncated"
+	// ProtocolParseIssueUnknownProtocol the protocol is unknown, only the transfer is recorded
+	ProtocolParseIssueUnknownProtocol ProtocolParseIssue = "unknown_protocol"
+	// ProtocolParseIssueProtocolBreak the analyzer is stopped in the connection and fallback to only record the transfer
+	ProtocolParseIssueProtocolBreak ProtocolParseIssue = "protocol_break"
+	// ProtocolParseIssueUnmatched the request is dropped because its response is missed
+	ProtocolParseIssueUnmatched ProtocolParseIssue = "unmatched"
+)
+
+type ProtocolParseIssueKey struct {
+	Protocol enums.ConnectionProtocol
+	Issue    ProtocolParseIssue
+}
+
+// ProtocolParseStats counting the parse issues of each protocol analyzer
+type ProtocolParseStats struct {
+	mutex  sync.Mutex
+	counts map[ProtocolParseIssueKey]uint64
+}
+
+func NewProtocolParseStats() *ProtocolParseStats {
+	return &ProtocolParseStats{counts: make(map[ProtocolParseIssueKey]uint64)}
+}
+
+func (s *ProtocolParseStats) Increase(protocol enums.ConnectionProtocol, issue ProtocolParseIssue) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.counts[ProtocolParseIssueKey{Protocol: protocol, Issue: issue}]++
+}
+
+// Swap returns the counts since the last swap
+func (s *ProtocolParseStats) Swap() map[ProtocolParseIssueKey]uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	result := s.counts
+	s.counts = make(map[ProtocolParseIssueKey]uint64)
+	return result
+}
+
+// ProtocolName is the readable name of the analyzer, the HTTP/1.x and HTTP/2 are separated
+func (k ProtocolParseIssueKey) ProtocolName() string {
+	switch k.Protocol {
+	case enums.ConnectionProtocolHTTP:
+		return "http1"
+	case enums.ConnectionProtocolHTTP2:
+		return "http2"
+	}
+	return enums.ConnectionProtocolString(k.Protocol)
+}
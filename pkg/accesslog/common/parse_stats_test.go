@@ -0,0 +1,53 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func TestProtocolParseStats(t *testing.T) {
+	stats := NewProtocolParseStats()
+	stats.Increase(enums.ConnectionProtocolHTTP, ProtocolParseIssueError)
+	stats.Increase(enums.ConnectionProtocolHTTP, ProtocolParseIssueError)
+	stats.Increase(enums.ConnectionProtocolHTTP2, ProtocolParseIssueTruncated)
+
+	tests := []struct {
+		key      ProtocolParseIssueKey
+		name     string
+		expected uint64
+	}{
+		{ProtocolParseIssueKey{enums.ConnectionProtocolHTTP, ProtocolParseIssueError}, "http1", 2},
+		{ProtocolParseIssueKey{enums.ConnectionProtocolHTTP2, ProtocolParseIssueTruncated}, "http2", 1},
+		{ProtocolParseIssueKey{enums.ConnectionProtocolTLS, ProtocolParseIssueError}, "tls", 0},
+	}
+	counts := stats.Swap()
+	for _, tt := range tests {
+		if counts[tt.key] != tt.expected {
+			t.Errorf("count of %s/%s: expected %d, actual %d", tt.name, tt.key.Issue, tt.expected, counts[tt.key])
+		}
+		if tt.key.ProtocolName() != tt.name {
+			t.Errorf("protocol name: expected %s, actual %s", tt.name, tt.key.ProtocolName())
+		}
+	}
+	if len(stats.Swap()) != 0 {
+		t.Errorf("the counts should be reset after swap")
+	}
+}
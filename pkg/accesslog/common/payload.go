@@ -0,0 +1,184 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PayloadRedacted is the placeholder of the redacted header values and body fields
+	PayloadRedacted = "[REDACTED]"
+	// PayloadReadMargin is the extra bytes should be read after the max bytes of the body,
+	// so the sensitive data across the truncated position still could be matched by the redact patterns
+	PayloadReadMargin = 64
+)
+
+// PayloadCaptureRule decides which parts of the messages of a protocol are captured
+type PayloadCaptureRule struct {
+	Request  bool
+	Response bool
+	// the max bytes of each captured body, the exceeded part is truncated
+	MaxBytes int
+}
+
+// PayloadMessage is the captured headers and body of a request or response
+type PayloadMessage struct {
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      string            `json:"body,omitempty"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+// PayloadRecord is the captured request and response of a protocol, all the sensitive data has been redacted
+type PayloadRecord struct {
+	ConnectionID uint64
+	RandomID     uint64
+	Protocol     string
+	// the BPF time of the request start and response end
+	StartTime  uint64
+	EndTime    uint64
+	Method     string
+	Path       string
+	StatusCode int
+	Request    *PayloadMessage
+	Response   *PayloadMessage
+
+	// the time of the record been created, for expiring the record which connection is not found
+	CreateTime time.Time
+}
+
+// PayloadCapture cache all the captured payloads until the next flush
+type PayloadCapture struct {
+	rules          map[string]*PayloadCaptureRule
+	redactHeaders  map[string]bool
+	redactPatterns []*regexp.Regexp
+
+	FlushQueue[*PayloadRecord]
+}
+
+// NewPayloadCapture creates the capture, the rules are separated by ";", each rule is "protocol:parts:max_bytes",
+// the parts are "request", "response" or both separated by ",", such as "http1:request,response:4096",
+// the redact headers are separated by ",", and the redact patterns(regex) are separated by ";"
+func NewPayloadCapture(rules, redactHeaders, redactPatterns string) (*PayloadCapture, error) {
+	c := &PayloadCapture{
+		rules:         make(map[string]*PayloadCaptureRule),
+		redactHeaders: make(map[string]bool),
+	}
+	for _, rule := range strings.Split(rules, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		protocol, parsed, err := parsePayloadCaptureRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		c.rules[protocol] = parsed
+	}
+	for _, h := range ParseCorrelationHeaders(redactHeaders) {
+		c.redactHeaders[h] = true
+	}
+	for _, pattern := range strings.Split(redactPatterns, ";") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile the payload redact pattern %s failure: %v", pattern, err)
+		}
+		c.redactPatterns = append(c.redactPatterns, reg)
+	}
+	return c, nil
+}
+
+func parsePayloadCaptureRule(rule string) (string, *PayloadCaptureRule, error) {
+	parts := strings.Split(rule, ":")
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("the payload capture rule must be in the format of protocol:parts:max_bytes: %s", rule)
+	}
+	protocol := strings.ToLower(strings.TrimSpace(parts[0]))
+	if protocol != "http1" && protocol != "http2" {
+		return "", nil, fmt.Errorf("the payload capture only supports the http1 and http2 protocols: %s", rule)
+	}
+	result := &PayloadCaptureRule{}
+	for _, part := range strings.Split(parts[1], ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "request":
+			result.Request = true
+		case "response":
+			result.Response = true
+		default:
+			return "", nil, fmt.Errorf("the payload capture part must be request or response: %s", rule)
+		}
+	}
+	maxBytes, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+	if err != nil || maxBytes < 0 {
+		return "", nil, fmt.Errorf("the payload capture max bytes must be a non-negative number: %s", rule)
+	}
+	result.MaxBytes = maxBytes
+	return protocol, result, nil
+}
+
+// Rule returns the capture rule of the protocol, nil means the protocol is not captured
+func (c *PayloadCapture) Rule(protocol string) *PayloadCaptureRule {
+	if c == nil {
+		return nil
+	}
+	return c.rules[protocol]
+}
+
+// RedactHeaders copies the headers, the values of the sensitive headers are replaced,
+// and the redact patterns are applied to the other values
+func (c *PayloadCapture) RedactHeaders(headers map[string]string) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if c.redactHeaders[strings.ToLower(name)] {
+			result[name] = PayloadRedacted
+			continue
+		}
+		result[name] = c.RedactText(value)
+	}
+	return result
+}
+
+// RedactText replaces all the matches of the redact patterns
+func (c *PayloadCapture) RedactText(text string) string {
+	for _, pattern := range c.redactPatterns {
+		text = pattern.ReplaceAllString(text, PayloadRedacted)
+	}
+	return text
+}
+
+// BuildMessage redacts the headers and body, then truncates the body by the max bytes of the rule
+func (c *PayloadCapture) BuildMessage(rule *PayloadCaptureRule, headers map[string]string, body []byte) *PayloadMessage {
+	message := &PayloadMessage{Headers: c.RedactHeaders(headers)}
+	text := c.RedactText(string(body))
+	if len(body) > rule.MaxBytes {
+		message.Truncated = true
+		if len(text) > rule.MaxBytes {
+			text = text[:rule.MaxBytes]
+		}
+	}
+	message.Body = text
+	return message
+}
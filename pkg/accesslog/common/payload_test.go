@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPayloadCapture(t *testing.T) {
+	tests := []struct {
+		rules  string
+		result map[string]*PayloadCaptureRule
+		err    bool
+	}{
+		{rules: "", result: map[string]*PayloadCaptureRule{}},
+		{
+			rules: "http1:request,response:4096; HTTP2:response:128",
+			result: map[string]*PayloadCaptureRule{
+				"http1": {Request: true, Response: true, MaxBytes: 4096},
+				"http2": {Response: true, MaxBytes: 128},
+			},
+		},
+		{rules: "http1:request", err: true},
+		{rules: "kafka:request:10", err: true},
+		{rules: "http1:body:10", err: true},
+		{rules: "http1:request:-1", err: true},
+	}
+	for _, tt := range tests {
+		capture, err := NewPayloadCapture(tt.rules, "", "")
+		if tt.err {
+			if err == nil {
+				t.Fatalf("parse %q: expected error", tt.rules)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("parse %q: unexpected error: %v", tt.rules, err)
+		}
+		if !reflect.DeepEqual(capture.rules, tt.result) {
+			t.Fatalf("parse %q: expected %v, actual %v", tt.rules, tt.result, capture.rules)
+		}
+	}
+}
+
+func TestPayloadCaptureBuildMessage(t *testing.T) {
+	capture, err := NewPayloadCapture("http1:request:24", "Authorization, cookie", `\b(?:\d[ -]?){12,18}\d\b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    string
+		result  *PayloadMessage
+	}{
+		{
+			name:    "headers",
+			headers: map[string]string{"authorization": "Bearer abc", "Cookie": "session=1", "x-card": "4111 1111 1111 1111"},
+			body:    "hello",
+			result: &PayloadMessage{
+				Headers: map[string]string{"authorization": PayloadRedacted, "Cookie": PayloadRedacted, "x-card": PayloadRedacted},
+				Body:    "hello",
+			},
+		},
+		{
+			name:   "redact across the truncated position",
+			body:   `{"card":"4111111111111111"}`,
+			result: &PayloadMessage{Headers: map[string]string{}, Body: `{"card":"[REDACTED]"}`, Truncated: true},
+		},
+		{
+			name:   "truncated",
+			body:   "abcdefghijklmnopqrstuvwxyz",
+			result: &PayloadMessage{Headers: map[string]string{}, Body: "abcdefghijklmnopqrstuvwx", Truncated: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := capture.BuildMessage(capture.Rule("http1"), tt.headers, []byte(tt.body)); !reflect.DeepEqual(result, tt.result) {
+				t.Fatalf("expected %+v, actual %+v", tt.result, result)
+			}
+		})
+	}
+}
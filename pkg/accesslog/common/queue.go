@@ -19,6 +19,7 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/apache/skywalking-rover/pkg/accesslog/events"
 	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
 
 	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
 )
@@ -51,6 +53,7 @@ type Queue struct {
 	period        time.Duration
 	consumer      QueueConsumer
 	consumeLock   *sync.Mutex
+	consumedCount atomic.Uint64
 
 	dropKernelLogCount   int64
 	dropProtocolLogCount int64
@@ -76,6 +79,7 @@ func (q *Queue) AppendKernelLog(log KernelLog) {
 	case q.kernelLogs <- log:
 	default:
 		atomic.AddInt64(&q.dropKernelLogCount, 1)
+		selfobs.Increase(selfobs.QueueDropped, "queue", "access_log_kernel", 1)
 		return
 	}
 	q.consumeIfNeed()
@@ -86,6 +90,7 @@ func (q *Queue) AppendProtocolLog(log ProtocolLog) {
 	case q.protocolLogs <- log:
 	default:
 		atomic.AddInt64(&q.dropProtocolLogCount, 1)
+		selfobs.Increase(selfobs.QueueDropped, "queue", "access_log_protocol", 1)
 		return
 	}
 	q.consumeIfNeed()
@@ -138,4 +143,24 @@ func (q *Queue) consume() {
 		log.Debugf("start to consume kernel logs: %d, protocol logs: %d", len(q.kernelLogs), len(q.protocolLogs))
 	}
 	q.consumer.Consume(q.kernelLogs, q.protocolLogs)
+	q.consumedCount.Add(1)
+}
+
+func (q *Queue) Name() string {
+	return "access log queue"
+}
+
+func (q *Queue) Progress() (handled, pending uint64) {
+	return q.consumedCount.Load(), uint64(len(q.kernelLogs) + len(q.protocolLogs))
+}
+
+// Restart the queue consuming when no consuming is running, the running consuming cannot be canceled,
+// so it is only reported as stalled, for avoiding draining the logs concurrently
+func (q *Queue) Restart() error {
+	if !q.consumeLock.TryLock() {
+		return fmt.Errorf("the previous consuming is still running")
+	}
+	q.consumeLock.Unlock()
+	go q.consume()
+	return nil
 }
@@ -0,0 +1,48 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"time"
+)
+
+// RPCRecord is the request and response of the protocols which cannot be represented as the protocol logs(such as Kafka),
+// they are aggregated as the meters of the process
+type RPCRecord struct {
+	ConnectionID uint64
+	RandomID     uint64
+	Protocol     string
+	// the operation of the request, such as "Produce" in Kafka
+	Operation string
+	// the resource of the request, such as the topic name in Kafka
+	Resource string
+	// the status of the response, empty means the protocol not provide the status
+	Status string
+	// the BPF time of the request start and response end
+	StartTime uint64
+	EndTime   uint64
+
+	RequestBytes  uint64
+	ResponseBytes uint64
+	// the count of the messages in the streaming call(such as gRPC), zero means the protocol not counting the messages
+	RequestMessages  uint64
+	ResponseMessages uint64
+
+	// the time of the record been created, for expiring the record which connection is not found
+	CreateTime time.Time
+}
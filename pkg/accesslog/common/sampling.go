@@ -0,0 +1,70 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import "sync"
+
+// AdaptiveSampler decides the percentage of the connections whose socket data is kept by the dropped events,
+// the rate is halved when any events dropped in the check period, and recovers by steps when no events dropped
+type AdaptiveSampler struct {
+	lock        sync.Mutex
+	minRate     int
+	recoverStep int
+
+	rate int
+}
+
+func NewAdaptiveSampler(minRate, recoverStep int) *AdaptiveSampler {
+	return &AdaptiveSampler{minRate: minRate, recoverStep: recoverStep, rate: 100}
+}
+
+// Rate is the current percentage(0-100) of the connections whose socket data is kept
+func (s *AdaptiveSampler) Rate() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.rate
+}
+
+// Update the sampler by the count of the dropped events in the check period, returns whether the rate is changed
+func (s *AdaptiveSampler) Update(dropped uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	rate := s.rate
+	if dropped > 0 {
+		rate /= 2
+		if rate < s.minRate {
+			rate = s.minRate
+		}
+	} else {
+		rate += s.recoverStep
+		if rate > 100 {
+			rate = 100
+		}
+	}
+	changed := rate != s.rate
+	s.rate = rate
+	return changed
+}
+
+// Reconfigure the min rate and recover step, the current rate is kept and adjusted in the next update
+func (s *AdaptiveSampler) Reconfigure(minRate, recoverStep int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.minRate = minRate
+	s.recoverStep = recoverStep
+}
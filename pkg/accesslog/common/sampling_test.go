@@ -0,0 +1,47 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import "testing"
+
+func TestAdaptiveSampler(t *testing.T) {
+	sampler := NewAdaptiveSampler(10, 20)
+	tests := []struct {
+		dropped uint64
+		rate    int
+		changed bool
+	}{
+		{dropped: 0, rate: 100, changed: false},
+		{dropped: 5, rate: 50, changed: true},
+		{dropped: 3, rate: 25, changed: true},
+		{dropped: 92, rate: 12, changed: true},
+		{dropped: 1, rate: 10, changed: true},
+		{dropped: 1, rate: 10, changed: false},
+		{dropped: 0, rate: 30, changed: true},
+		{dropped: 0, rate: 50, changed: true},
+		{dropped: 0, rate: 70, changed: true},
+		{dropped: 0, rate: 90, changed: true},
+		{dropped: 0, rate: 100, changed: true},
+		{dropped: 0, rate: 100, changed: false},
+	}
+	for i, tt := range tests {
+		if changed := sampler.Update(tt.dropped); changed != tt.changed || sampler.Rate() != tt.rate {
+			t.Fatalf("step %d: expected changed %t, rate %d, actual changed %t, rate %d", i, tt.changed, tt.rate, changed, sampler.Rate())
+		}
+	}
+}
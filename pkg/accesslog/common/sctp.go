@@ -0,0 +1,66 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/ip"
+)
+
+// SCTPAssociation is the SCTP association of the connection, the one-to-many style socket has multiple associations
+type SCTPAssociation struct {
+	Connection *ConnectionInfo
+	*ip.SCTPAssociation
+}
+
+// SnapshotSCTPAssociations read the associations of all SCTP connections, the associations are matched by the socket inode,
+// and the associations file is only read once for the processes in the same network namespace
+func (c *ConnectionManager) SnapshotSCTPAssociations() []*SCTPAssociation {
+	connections := make([]*ConnectionInfo, 0)
+	c.connections.IterCb(func(_ string, v interface{}) {
+		if con, ok := v.(*ConnectionInfo); ok && con != nil && con.Transport == enums.TransportProtocolSCTP {
+			connections = append(connections, con)
+		}
+	})
+
+	result := make([]*SCTPAssociation, 0)
+	netNSAssociations := make(map[uint64][]*ip.SCTPAssociation)
+	for _, con := range connections {
+		_, fd := events.ParseConnectionID(con.ConnectionID)
+		inode, err := ip.SocketInode(con.PID, fd)
+		if err != nil {
+			log.Debugf("cannot read the socket inode of the SCTP connection, pid: %d, fd: %d: %v", con.PID, fd, err)
+			continue
+		}
+		netNS := c.FindProcessNetNS(con.PID)
+		associations, exist := netNSAssociations[netNS]
+		if !exist || netNS == 0 {
+			if associations, err = ip.ReadSCTPAssociations(int32(con.PID)); err != nil {
+				log.Debugf("cannot read the SCTP associations of the process %d: %v", con.PID, err)
+			}
+			netNSAssociations[netNS] = associations
+		}
+		for _, assoc := range associations {
+			if assoc.Inode == inode {
+				result = append(result, &SCTPAssociation{Connection: con, SCTPAssociation: assoc})
+			}
+		}
+	}
+	return result
+}
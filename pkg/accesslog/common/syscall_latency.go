@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"sort"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+// SyscallLatency is the aggregated timing of the same syscall function in the connection
+type SyscallLatency struct {
+	Function enums.SocketFunctionName
+	Count    uint64
+	// the total time in the syscall(nanoseconds)
+	Duration uint64
+	// the time in the TCP/IP protocol stack of the syscall(nanoseconds)
+	StackDuration uint64
+	// the time of the syscall blocked on waiting the send buffer memory or the receive data(nanoseconds)
+	WaitDuration uint64
+}
+
+// KernelDuration is the time of the syscall processing in the kernel, excluding the blocked time
+func (s *SyscallLatency) KernelDuration() uint64 {
+	if s.WaitDuration >= s.Duration {
+		return 0
+	}
+	return s.Duration - s.WaitDuration
+}
+
+// ConnectionSyscallLatency is the syscall timing breakdown of the connection since the last snapshot
+type ConnectionSyscallLatency struct {
+	Connection *ConnectionInfo
+	Syscalls   []*SyscallLatency
+}
+
+// RecordSyscall accumulate the syscall timing of the connection when the syscall latency is active
+func (c *ConnectionManager) RecordSyscall(connection *ConnectionInfo, detail events.SocketDetail) {
+	if !c.recordSyscallLatency || connection == nil {
+		return
+	}
+	connection.recordSyscall(detail)
+}
+
+func (c *ConnectionInfo) recordSyscall(detail events.SocketDetail) {
+	event, ok := detail.(*events.SocketDetailEvent)
+	if !ok || event.EndTime < event.StartTime {
+		return
+	}
+	var wait uint64
+	switch event.FunctionName.GetSocketOperationType() {
+	case enums.SocketOperationTypeWrite:
+		wait = uint64(event.SendBufferWaitDuration)
+	case enums.SocketOperationTypeRead:
+		wait = uint64(event.ReceiveWaitDuration)
+	default:
+		return
+	}
+
+	c.syscallLock.Lock()
+	defer c.syscallLock.Unlock()
+	if c.syscalls == nil {
+		c.syscalls = make(map[enums.SocketFunctionName]*SyscallLatency)
+	}
+	latency := c.syscalls[event.FunctionName]
+	if latency == nil {
+		latency = &SyscallLatency{Function: event.FunctionName}
+		c.syscalls[event.FunctionName] = latency
+	}
+	latency.Count++
+	latency.Duration += event.EndTime - event.StartTime
+	latency.StackDuration += uint64(event.L4Duration)
+	latency.WaitDuration += wait
+}
+
+// SnapshotSyscallLatency take out the syscall timing of all connections since the last snapshot,
+// the connections without any syscall in the interval are skipped
+func (c *ConnectionManager) SnapshotSyscallLatency() []*ConnectionSyscallLatency {
+	result := make([]*ConnectionSyscallLatency, 0)
+	c.connections.IterCb(func(_ string, v interface{}) {
+		con, ok := v.(*ConnectionInfo)
+		if !ok || con == nil {
+			return
+		}
+		con.syscallLock.Lock()
+		syscalls := con.syscalls
+		con.syscalls = nil
+		con.syscallLock.Unlock()
+		if len(syscalls) == 0 {
+			return
+		}
+		latency := &ConnectionSyscallLatency{Connection: con, Syscalls: make([]*SyscallLatency, 0, len(syscalls))}
+		for _, s := range syscalls {
+			latency.Syscalls = append(latency.Syscalls, s)
+		}
+		sort.Slice(latency.Syscalls, func(i, j int) bool {
+			return latency.Syscalls[i].Function < latency.Syscalls[j].Function
+		})
+		result = append(result, latency)
+	})
+	return result
+}
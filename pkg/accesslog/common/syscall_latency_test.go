@@ -0,0 +1,81 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"testing"
+
+	cmap "github.com/orcaman/concurrent-map"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func TestSnapshotSyscallLatency(t *testing.T) {
+	mgr := &ConnectionManager{connections: cmap.New(), recordSyscallLatency: true}
+	con := &ConnectionInfo{ConnectionID: 1, RandomID: 1}
+	mgr.connections.Set("1_1", con)
+
+	tests := []struct {
+		name     string
+		details  []*events.SocketDetailEvent
+		expected []SyscallLatency
+	}{
+		{
+			name: "write with send buffer wait",
+			details: []*events.SocketDetailEvent{
+				{FunctionName: enums.SocketFunctionNameWrite, StartTime: 100, EndTime: 400, L4Duration: 50, SendBufferWaitDuration: 200},
+				{FunctionName: enums.SocketFunctionNameWrite, StartTime: 500, EndTime: 600, L4Duration: 30},
+			},
+			expected: []SyscallLatency{{Function: enums.SocketFunctionNameWrite, Count: 2, Duration: 400, StackDuration: 80, WaitDuration: 200}},
+		},
+		{
+			name: "read and write are split by function",
+			details: []*events.SocketDetailEvent{
+				{FunctionName: enums.SocketFunctionNameRecvMsg, StartTime: 100, EndTime: 300, ReceiveWaitDuration: 150, SendBufferWaitDuration: 10},
+				{FunctionName: enums.SocketFunctionNameSendMsg, StartTime: 300, EndTime: 350},
+				{FunctionName: enums.SocketFunctionNameClose, StartTime: 400, EndTime: 450},
+			},
+			expected: []SyscallLatency{
+				{Function: enums.SocketFunctionNameSendMsg, Count: 1, Duration: 50},
+				{Function: enums.SocketFunctionNameRecvMsg, Count: 1, Duration: 200, WaitDuration: 150},
+			},
+		},
+		{name: "no syscall since last snapshot"},
+	}
+	for _, tt := range tests {
+		for _, d := range tt.details {
+			mgr.RecordSyscall(con, d)
+		}
+		latencies := mgr.SnapshotSyscallLatency()
+		if len(tt.expected) == 0 {
+			if len(latencies) != 0 {
+				t.Fatalf("%s: should not have latency, actual: %d", tt.name, len(latencies))
+			}
+			continue
+		}
+		if len(latencies) != 1 || len(latencies[0].Syscalls) != len(tt.expected) {
+			t.Fatalf("%s: unexpected latency count: %v", tt.name, latencies)
+		}
+		for i, expected := range tt.expected {
+			if actual := latencies[0].Syscalls[i]; *actual != expected {
+				t.Fatalf("%s: expected: %+v, actual: %+v", tt.name, expected, *actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"time"
+)
+
+// TLSHandshake is the metadata of the TLS handshake in plaintext, detected from the client and server hello
+type TLSHandshake struct {
+	ConnectionID uint64
+	RandomID     uint64
+	// the SNI in the client hello
+	ServerName string
+	// the negotiated protocol version and cipher suite
+	Version     uint16
+	CipherSuite uint16
+	// the selected application protocol, empty when not negotiated or encrypted in TLS 1.3
+	ALPN string
+	// the application protocols offered by the client
+	ALPNOffered []string
+	// the BPF time of the server hello
+	Time uint64
+	// the handshake is carried by the QUIC Initial packets
+	QUIC bool
+
+	// the time of the handshake been created, for expiring the handshake which connection is not found
+	CreateTime time.Time
+}
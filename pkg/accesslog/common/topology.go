@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+
+	v32 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+const (
+	TopologyStateActive  = "active"
+	TopologyStateClosing = "closing"
+)
+
+// TopologyEdgeKey identify the aggregated connections between the local process and the remote address
+type TopologyEdgeKey struct {
+	PID           uint32
+	Role          string
+	State         string
+	RemoteService string
+	RemoteAddress string
+	Encryption    string
+	MeshRevision  string
+	MeshHop       string
+}
+
+// TopologyEdge is the aggregated connections in the topology snapshot
+type TopologyEdge struct {
+	Connections int64
+	// the transferred bytes since the last snapshot
+	WriteBytes uint64
+	ReadBytes  uint64
+}
+
+// RecordTransfer accumulate the transferred bytes of the connection
+func (c *ConnectionInfo) RecordTransfer(detail events.SocketDetail) {
+	switch detail.GetFunctionName().GetSocketOperationType() {
+	case enums.SocketOperationTypeWrite:
+		atomic.AddUint64(&c.WriteBytes, detail.GetL4TotalPackageSize())
+	case enums.SocketOperationTypeRead:
+		atomic.AddUint64(&c.ReadBytes, detail.GetL4TotalPackageSize())
+	}
+}
+
+// SnapshotTopology aggregates all the connections in the manager by the local process and the remote address
+func (c *ConnectionManager) SnapshotTopology() map[TopologyEdgeKey]*TopologyEdge {
+	result := make(map[TopologyEdgeKey]*TopologyEdge)
+	c.connections.IterCb(func(_ string, v interface{}) {
+		con, ok := v.(*ConnectionInfo)
+		if !ok || con == nil {
+			return
+		}
+		remoteService, remoteAddress := topologyRemote(con.RPCConnection.GetRole(), con.RPCConnection.GetRemote())
+		state := TopologyStateActive
+		if con.MarkDeletable {
+			state = TopologyStateClosing
+		}
+		key := TopologyEdgeKey{
+			PID:           con.PID,
+			Role:          con.RPCConnection.GetRole().String(),
+			State:         state,
+			RemoteService: remoteService,
+			RemoteAddress: remoteAddress,
+			Encryption:    string(con.Encryption),
+			MeshRevision:  con.MeshRevision,
+			MeshHop:       string(con.MeshHop),
+		}
+		edge := result[key]
+		if edge == nil {
+			edge = &TopologyEdge{}
+			result[key] = edge
+		}
+		edge.Connections++
+		write, read := atomic.LoadUint64(&con.WriteBytes), atomic.LoadUint64(&con.ReadBytes)
+		edge.WriteBytes += write - atomic.SwapUint64(&con.snapshotWriteBytes, write)
+		edge.ReadBytes += read - atomic.SwapUint64(&con.snapshotReadBytes, read)
+	})
+	return result
+}
+
+// topologyRemote build the remote service and address of the connection,
+// the remote port of the server side is ignored, because it's the random port of the client
+func topologyRemote(role v32.DetectPoint, remote *v3.ConnectionAddress) (service, address string) {
+	switch addr := remote.GetAddress().(type) {
+	case *v3.ConnectionAddress_Kubernetes:
+		service, address = addr.Kubernetes.GetServiceName(), addr.Kubernetes.GetPodName()
+		if role == v32.DetectPoint_client {
+			address = fmt.Sprintf("%s:%d", address, addr.Kubernetes.GetPort())
+		}
+	case *v3.ConnectionAddress_Ip:
+		address = addr.Ip.GetHost()
+		if role == v32.DetectPoint_client {
+			address = fmt.Sprintf("%s:%d", address, addr.Ip.GetPort())
+		}
+	}
+	return service, address
+}
@@ -0,0 +1,51 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"testing"
+
+	v32 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+func TestTopologyRemote(t *testing.T) {
+	k8s := &v3.ConnectionAddress{Address: &v3.ConnectionAddress_Kubernetes{
+		Kubernetes: &v3.KubernetesProcessAddress{ServiceName: "default::productpage", PodName: "productpage-1", Port: 9080},
+	}}
+	ipAddr := &v3.ConnectionAddress{Address: &v3.ConnectionAddress_Ip{Ip: &v3.IPAddress{Host: "10.0.0.1", Port: 43567}}}
+	tests := []struct {
+		name    string
+		role    v32.DetectPoint
+		remote  *v3.ConnectionAddress
+		service string
+		address string
+	}{
+		{name: "client to kubernetes", role: v32.DetectPoint_client, remote: k8s, service: "default::productpage", address: "productpage-1:9080"},
+		{name: "server from kubernetes", role: v32.DetectPoint_server, remote: k8s, service: "default::productpage", address: "productpage-1"},
+		{name: "client to ip", role: v32.DetectPoint_client, remote: ipAddr, address: "10.0.0.1:43567"},
+		{name: "server from ip", role: v32.DetectPoint_server, remote: ipAddr, address: "10.0.0.1"},
+		{name: "no address", role: v32.DetectPoint_client, remote: nil},
+	}
+	for _, tt := range tests {
+		service, address := topologyRemote(tt.role, tt.remote)
+		if service != tt.service || address != tt.address {
+			t.Fatalf("%s: expected %s/%s, actual %s/%s", tt.name, tt.service, tt.address, service, address)
+		}
+	}
+}
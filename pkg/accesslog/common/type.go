@@ -23,4 +23,5 @@ const (
 	LogTypeConnect LogType = iota
 	LogTypeKernelTransfer
 	LogTypeClose
+	LogTypeConnectFailure
 )
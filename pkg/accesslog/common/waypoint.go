@@ -0,0 +1,52 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"net"
+	"strings"
+)
+
+// ParseWaypointAddresses parse the pod addresses of the waypoint proxy from the environments("key=value"),
+// the dual-stack pod has multiple addresses in the "INSTANCE_IPS"
+func ParseWaypointAddresses(environ []string) []string {
+	var instanceIP, instanceIPs string
+	for _, env := range environ {
+		key, value, found := strings.Cut(env, "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "INSTANCE_IP":
+			instanceIP = value
+		case "INSTANCE_IPS":
+			instanceIPs = value
+		}
+	}
+	if instanceIPs == "" {
+		instanceIPs = instanceIP
+	}
+	result := make([]string, 0)
+	for _, address := range strings.Split(instanceIPs, ",") {
+		// same format with the address of socket
+		if parsed := net.ParseIP(strings.TrimSpace(address)); parsed != nil {
+			result = append(result, parsed.String())
+		}
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWaypointAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  []string
+		expected []string
+	}{
+		{name: "single stack", environ: []string{"PATH=/usr/bin", "INSTANCE_IP=10.0.1.7"}, expected: []string{"10.0.1.7"}},
+		{name: "dual stack", environ: []string{"INSTANCE_IP=10.0.1.7", "INSTANCE_IPS=10.0.1.7,fd00:0::7"},
+			expected: []string{"10.0.1.7", "fd00::7"}},
+		{name: "invalid", environ: []string{"INSTANCE_IP=unknown"}, expected: []string{}},
+		{name: "missing", environ: []string{"PATH=/usr/bin"}, expected: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := ParseWaypointAddresses(tt.environ); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected: %v, actual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ZTunnelDefaultRevision is the revision of the ztunnel which installed without the revision
+const ZTunnelDefaultRevision = "default"
+
+// the environments of the ztunnel process which contains the mesh and revision, the former has higher priority
+var (
+	zTunnelMeshEnvs     = []string{"ISTIO_META_MESH_ID", "TRUST_DOMAIN"}
+	zTunnelRevisionEnvs = []string{"REVISION", "ISTIO_META_REVISION"}
+	// the revision could also be found from the istiod address, such as "istiod-canary.istio-system.svc:15012"
+	zTunnelIstiodAddressEnvs = []string{"XDS_ADDRESS", "CA_ADDRESS"}
+)
+
+// ZTunnelDumpedConnection is the outbound connection in the ztunnel admin config dump
+type ZTunnelDumpedConnection struct {
+	SrcIP           string
+	SrcPort         uint16
+	OriginalDstIP   string
+	OriginalDstPort uint16
+	ActualDstIP     string
+	ActualDstPort   uint16
+}
+
+type zTunnelConfigDump struct {
+	Connections struct {
+		Outbound []struct {
+			Src         string `json:"src"`
+			OriginalDst string `json:"original_dst"`
+			ActualDst   string `json:"actual_dst"`
+		} `json:"outbound"`
+	} `json:"connections"`
+}
+
+// ParseZTunnelOutboundConnections parse the outbound connections from the "/config_dump" of the ztunnel admin,
+// the invalid address would be ignored
+func ParseZTunnelOutboundConnections(reader io.Reader) ([]*ZTunnelDumpedConnection, error) {
+	dump := &zTunnelConfigDump{}
+	if err := json.NewDecoder(reader).Decode(dump); err != nil {
+		return nil, fmt.Errorf("decode the ztunnel config dump failure: %v", err)
+	}
+	result := make([]*ZTunnelDumpedConnection, 0, len(dump.Connections.Outbound))
+	for _, outbound := range dump.Connections.Outbound {
+		srcIP, srcPort, err := splitZTunnelAddress(outbound.Src)
+		if err != nil {
+			continue
+		}
+		originalIP, originalPort, err := splitZTunnelAddress(outbound.OriginalDst)
+		if err != nil {
+			continue
+		}
+		actualIP, actualPort, err := splitZTunnelAddress(outbound.ActualDst)
+		if err != nil {
+			continue
+		}
+		result = append(result, &ZTunnelDumpedConnection{
+			SrcIP:           srcIP,
+			SrcPort:         srcPort,
+			OriginalDstIP:   originalIP,
+			OriginalDstPort: originalPort,
+			ActualDstIP:     actualIP,
+			ActualDstPort:   actualPort,
+		})
+	}
+	return result, nil
+}
+
+func splitZTunnelAddress(address string) (string, uint16, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", 0, err
+	}
+	parsedPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return "", 0, err
+	}
+	// same format with the address of socket, the IPv4-mapped IPv6 address is converted to the IPv4 format
+	if parsed := net.ParseIP(host); parsed != nil {
+		host = parsed.String()
+	}
+	return host, uint16(parsedPort), nil
+}
+
+// ParseZTunnelIdentity parse the mesh and revision from the environments("key=value") of the ztunnel process,
+// the result is "mesh/revision", or only the revision when the mesh is unknown
+func ParseZTunnelIdentity(environ []string) string {
+	envs := make(map[string]string, len(environ))
+	for _, env := range environ {
+		if key, value, found := strings.Cut(env, "="); found && value != "" {
+			envs[key] = value
+		}
+	}
+	mesh := firstZTunnelEnv(envs, zTunnelMeshEnvs)
+	revision := firstZTunnelEnv(envs, zTunnelRevisionEnvs)
+	if revision == "" {
+		revision = parseIstiodRevision(firstZTunnelEnv(envs, zTunnelIstiodAddressEnvs))
+	}
+	if mesh == "" {
+		return revision
+	}
+	return mesh + "/" + revision
+}
+
+func firstZTunnelEnv(envs map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value := envs[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func parseIstiodRevision(address string) string {
+	hostname := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		hostname = h
+	}
+	service, _, _ := strings.Cut(hostname, ".")
+	if revision, found := strings.CutPrefix(service, "istiod-"); found && revision != "" {
+		return revision
+	}
+	return ZTunnelDefaultRevision
+}
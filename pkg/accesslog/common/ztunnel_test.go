@@ -0,0 +1,85 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseZTunnelOutboundConnections(t *testing.T) {
+	dump := `{
+  "connections": {
+    "inbound": [{"src": "10.0.1.3:40212", "original_dst": "10.0.0.5:8080", "actual_dst": "10.0.0.5:8080"}],
+    "outbound": [
+      {"src": "10.0.0.5:51234", "original_dst": "10.96.0.10:80", "actual_dst": "10.0.1.3:15008"},
+      {"src": "[fd00::5]:51235", "original_dst": "[fd00:96::10]:80", "actual_dst": "[fd00::3]:15008"},
+      {"src": "[::ffff:10.0.0.6]:51236", "original_dst": "[fd00:96:0:0::11]:80", "actual_dst": "[FD00::4]:15008"},
+      {"src": "invalid", "original_dst": "10.96.0.10:80", "actual_dst": "10.0.1.3:15008"}
+    ]
+  }
+}`
+	tests := []ZTunnelDumpedConnection{
+		{SrcIP: "10.0.0.5", SrcPort: 51234, OriginalDstIP: "10.96.0.10", OriginalDstPort: 80, ActualDstIP: "10.0.1.3", ActualDstPort: 15008},
+		{SrcIP: "fd00::5", SrcPort: 51235, OriginalDstIP: "fd00:96::10", OriginalDstPort: 80, ActualDstIP: "fd00::3", ActualDstPort: 15008},
+		{SrcIP: "10.0.0.6", SrcPort: 51236, OriginalDstIP: "fd00:96::11", OriginalDstPort: 80, ActualDstIP: "fd00::4", ActualDstPort: 15008},
+	}
+
+	connections, err := ParseZTunnelOutboundConnections(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("parse failure: %v", err)
+	}
+	if len(connections) != len(tests) {
+		t.Fatalf("expected %d connections, actual %d", len(tests), len(connections))
+	}
+	for i, expected := range tests {
+		if *connections[i] != expected {
+			t.Fatalf("expected %v, actual %v", expected, *connections[i])
+		}
+	}
+
+	if _, err := ParseZTunnelOutboundConnections(strings.NewReader("not json")); err == nil {
+		t.Fatalf("expected the error when the dump is not JSON")
+	}
+}
+
+func TestParseZTunnelIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  []string
+		expected string
+	}{
+		{name: "no environments", environ: []string{""}, expected: ZTunnelDefaultRevision},
+		{name: "revision environment", environ: []string{"REVISION=canary", "XDS_ADDRESS=istiod-stable.istio-system.svc:15012"}, expected: "canary"},
+		{name: "revision from istiod address", environ: []string{"XDS_ADDRESS=istiod-1-22.istio-system.svc:15012"}, expected: "1-22"},
+		{name: "default istiod address", environ: []string{"CA_ADDRESS=istiod.istio-system.svc:15012"}, expected: ZTunnelDefaultRevision},
+		{
+			name:     "with mesh",
+			environ:  []string{"TRUST_DOMAIN=cluster.local", "ISTIO_META_MESH_ID=mesh1", "ISTIO_META_REVISION=canary"},
+			expected: "mesh1/canary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := ParseZTunnelIdentity(tt.environ); actual != tt.expected {
+				t.Fatalf("expected %s, actual %s", tt.expected, actual)
+			}
+		})
+	}
+}
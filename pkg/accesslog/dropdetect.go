@@ -0,0 +1,117 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package accesslog
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+)
+
+type dropDetection struct {
+	period  time.Duration
+	sampler *common.AdaptiveSampler
+	rate    atomic.Int32
+
+	lastDrops uint64
+}
+
+func newDropDetection(config *common.DropDetectionConfig) (*dropDetection, error) {
+	period, err := time.ParseDuration(config.CheckPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parse the drop detection check period error: %v", err)
+	}
+	detection := &dropDetection{period: period}
+	detection.rate.Store(100)
+	if config.AdaptiveSampling {
+		if err := validateAdaptiveSampling(config); err != nil {
+			return nil, err
+		}
+		detection.sampler = common.NewAdaptiveSampler(config.MinSamplingRate, config.RecoverStep)
+	}
+	return detection, nil
+}
+
+func validateAdaptiveSampling(config *common.DropDetectionConfig) error {
+	if config.MinSamplingRate < 1 || config.MinSamplingRate > 100 {
+		return fmt.Errorf("the drop detection min sampling rate must be in [1, 100]")
+	}
+	if config.RecoverStep < 1 {
+		return fmt.Errorf("the drop detection recover step must be bigger than 0")
+	}
+	return nil
+}
+
+func (r *Runner) startDropDetection() {
+	selfobs.RegisterGauge(selfobs.DataSamplingRate, "module", "access_log", func() float64 {
+		return float64(r.dropDetection.rate.Load())
+	})
+	go func() {
+		ticker := time.NewTicker(r.dropDetection.period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.checkDroppedEvents(); err != nil {
+					log.Warnf("check the dropped events failure: %v", err)
+				}
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (r *Runner) checkDroppedEvents() error {
+	var values []uint64
+	if err := r.context.BPF.RoverQueueSubmitFailure.Lookup(uint32(0), &values); err != nil {
+		return fmt.Errorf("read the queue submit failures error: %v", err)
+	}
+	var drops uint64
+	for _, v := range values {
+		drops += v
+	}
+	detection := r.dropDetection
+	var dropped uint64
+	if drops >= detection.lastDrops {
+		dropped = drops - detection.lastDrops
+	}
+	detection.lastDrops = drops
+	if dropped > 0 {
+		log.Warnf("%d access log events dropped in the kernel in the last %s", dropped, detection.period)
+		selfobs.Increase(selfobs.QueueSubmitFailure, "module", "access_log", int64(dropped))
+	}
+	if detection.sampler == nil {
+		return nil
+	}
+
+	changed := detection.sampler.Update(dropped)
+	if !changed {
+		return nil
+	}
+	rate := detection.sampler.Rate()
+	if err := r.context.BPF.SocketDataSamplingSkip.Update(uint32(0), uint32(100-rate), 0); err != nil {
+		return fmt.Errorf("update the socket data sampling error: %v", err)
+	}
+	detection.rate.Store(int32(rate))
+	log.Infof("the socket data of %d%% connections are kept by the adaptive sampling", rate)
+	return nil
+}
@@ -35,7 +35,8 @@ type SocketConnectEvent struct {
 	Role                  uint8
 	SocketFamily          uint8
 	ConnectSuccess        uint8
-	Pad0                  uint32
+	Transport             uint8
+	Pad0                  [3]uint8
 	RemoteAddrV4          uint32
 	RemoteAddrPort        uint32
 	RemoteAddrV6          [16]uint8
@@ -58,7 +59,8 @@ func (c *SocketConnectEvent) ReadFrom(r btf.Reader) {
 	c.Role = r.ReadUint8()
 	c.SocketFamily = r.ReadUint8()
 	c.ConnectSuccess = r.ReadUint8()
-	c.Pad0 = r.ReadUint32()
+	c.Transport = r.ReadUint8()
+	r.ReadUint8Array(c.Pad0[:], 3)
 	c.RemoteAddrV4 = r.ReadUint32()
 	c.RemoteAddrPort = r.ReadUint32()
 	r.ReadUint8Array(c.RemoteAddrV6[:], 16)
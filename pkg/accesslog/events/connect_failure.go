@@ -0,0 +1,65 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package events
+
+import (
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/btf"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+// SocketConnectFailureEvent is the connect operation failure,
+// the RandomID is zero when the connect syscall return failure directly
+type SocketConnectFailureEvent struct {
+	ConID          uint64
+	RandomID       uint64
+	StartTime      uint64
+	EndTime        uint64
+	PID            uint32
+	Error          uint32
+	SocketFamily   uint32
+	RemoteAddrPort uint32
+	RemoteAddrV4   uint32
+	RemoteAddrV6   [16]uint8
+}
+
+func (c *SocketConnectFailureEvent) ReadFrom(r btf.Reader) {
+	c.ConID = r.ReadUint64()
+	c.RandomID = r.ReadUint64()
+	c.StartTime = r.ReadUint64()
+	c.EndTime = r.ReadUint64()
+	c.PID = r.ReadUint32()
+	c.Error = r.ReadUint32()
+	c.SocketFamily = r.ReadUint32()
+	c.RemoteAddrPort = r.ReadUint32()
+	c.RemoteAddrV4 = r.ReadUint32()
+	r.ReadUint8Array(c.RemoteAddrV6[:], 16)
+}
+
+func (c *SocketConnectFailureEvent) GetConnectionID() uint64 {
+	return c.ConID
+}
+
+func (c *SocketConnectFailureEvent) GetRandomID() uint64 {
+	return c.RandomID
+}
+
+func (c *SocketConnectFailureEvent) Timestamp() time.Time {
+	return host.Time(c.EndTime)
+}
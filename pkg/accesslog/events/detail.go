@@ -39,6 +39,9 @@ type SocketDetail interface {
 	GetFunctionName() enums.SocketFunctionName
 	GetProtocol() enums.ConnectionProtocol
 	GetSSL() uint8
+	GetBufferPressure() SocketBufferPressure
+	GetSendBufferFullCount() uint16
+	GetReceiveBufferDropCount() uint16
 }
 
 type SocketDetailEvent struct {
@@ -71,6 +74,12 @@ type SocketDetailEvent struct {
 	FunctionName                  enums.SocketFunctionName
 	Protocol                      enums.ConnectionProtocol
 	SSL                           uint8
+	SendBufferFullCount           uint16
+	ReceiveBufferDropCount        uint16
+	BufferPressure                SocketBufferPressure
+	// the time of the syscall blocked on waiting the send buffer memory or the receive data
+	SendBufferWaitDuration uint32
+	ReceiveWaitDuration    uint32
 }
 
 func (d *SocketDetailEvent) ReadFrom(r btf.Reader) {
@@ -101,6 +110,11 @@ func (d *SocketDetailEvent) ReadFrom(r btf.Reader) {
 	d.FunctionName = enums.SocketFunctionName(r.ReadUint8())
 	d.Protocol = enums.ConnectionProtocol(r.ReadUint8())
 	d.SSL = r.ReadUint8()
+	d.SendBufferFullCount = r.ReadUint16()
+	d.ReceiveBufferDropCount = r.ReadUint16()
+	d.BufferPressure = SocketBufferPressure(r.ReadUint32())
+	d.SendBufferWaitDuration = r.ReadUint32()
+	d.ReceiveWaitDuration = r.ReadUint32()
 }
 
 func (d *SocketDetailEvent) Time() uint64 {
@@ -150,3 +164,15 @@ func (d *SocketDetailEvent) GetProtocol() enums.ConnectionProtocol {
 func (d *SocketDetailEvent) GetSSL() uint8 {
 	return d.SSL
 }
+
+func (d *SocketDetailEvent) GetBufferPressure() SocketBufferPressure {
+	return d.BufferPressure
+}
+
+func (d *SocketDetailEvent) GetSendBufferFullCount() uint16 {
+	return d.SendBufferFullCount
+}
+
+func (d *SocketDetailEvent) GetReceiveBufferDropCount() uint16 {
+	return d.ReceiveBufferDropCount
+}
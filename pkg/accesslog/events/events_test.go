@@ -24,6 +24,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/skywalking-rover/pkg/tools/btf"
 
@@ -58,7 +59,8 @@ func TestBufferRead(t *testing.T) {
 27 15 00 00 1d 02 00 00 37 3c 00 00 00 00 00 00
 23 4a 01 00 bb 49 01 00 00 00 00 00 e4 01 00 00
 24 21 00 00 01 00 00 00 39 d6 00 00 00 00 00 00
-03 00 00 00 00 00 00 00 02 02 00 02 02 09 01 00`,
+03 00 00 00 00 00 00 00 02 02 00 02 02 09 01 00
+00 00 00 00 00 00 00 00 40 0d 03 00 a0 86 01 00`,
 			create: func() btf.EventReader {
 				return &SocketDetailEvent{}
 			},
@@ -75,6 +77,17 @@ b2 2d 26 7c 5a 30 02 00 5e 34 26 7c 5a 30 02 00
 		},
 		{
 			hex: `
+04 00 00 00 7a a1 00 00 4e 56 83 76 00 00 00 00
+b2 2d 26 7c 5a 30 02 00 5e 34 26 7c 5a 30 02 00
+7a a1 00 00 6f 00 00 00 02 00 00 00 50 00 00 00
+0a 00 00 01 00 00 00 00 00 00 00 00 00 00 ff ff
+0a 00 00 01`,
+			create: func() btf.EventReader {
+				return &SocketConnectFailureEvent{}
+			},
+		},
+		{
+			hex: `
 03 00 02 01 00 00 2a 06 9c 5c fc 7b 5a 30 02 00
 20 c5 fd 7b 5a 30 02 00 04 00 00 00 7a a1 00 00
 4e 56 83 76 00 00 00 00 02 00 00 00 00 00 00 00
@@ -1390,3 +1403,40 @@ fa 0d 8d 69 a3 e6 6d a5 29 98 d7 20 6f a3 13 ea
 		})
 	}
 }
+
+func TestSocketBufferPressureCause(t *testing.T) {
+	tests := []struct {
+		pressure SocketBufferPressure
+		cause    string
+		name     string
+	}{
+		{pressure: 0, cause: "", name: ""},
+		{pressure: SocketBufferPressurePeerZeroWindow | SocketBufferPressureSendBufferFull, cause: "slow_peer",
+			name: "peer_zero_window|send_buffer_full"},
+		{pressure: SocketBufferPressureSendBufferFull, cause: "slow_network", name: "send_buffer_full"},
+		{pressure: SocketBufferPressureLocalZeroWindow, cause: "slow_local", name: "local_zero_window"},
+		{pressure: SocketBufferPressureReceiveBufferDrop, cause: "slow_local", name: "receive_buffer_drop"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.cause, tt.pressure.Cause())
+		assert.Equal(t, tt.name, tt.pressure.String())
+	}
+}
+
+func TestSocketHealth(t *testing.T) {
+	tests := []struct {
+		health   SocketHealth
+		abnormal bool
+		avgRTT   time.Duration
+	}{
+		{health: SocketHealth{}, abnormal: false, avgRTT: 0},
+		{health: SocketHealth{RTTSum: 3000, RTTSamples: 2}, abnormal: false, avgRTT: 1500 * time.Microsecond},
+		{health: SocketHealth{Retransmits: 1}, abnormal: true, avgRTT: 0},
+		{health: SocketHealth{ZeroWindows: 2, RTTSum: 100, RTTSamples: 1}, abnormal: true, avgRTT: 100 * time.Microsecond},
+		{health: SocketHealth{ResetsReceived: 1}, abnormal: true, avgRTT: 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.abnormal, tt.health.Abnormal())
+		assert.Equal(t, tt.avgRTT, tt.health.AvgRTT())
+	}
+}
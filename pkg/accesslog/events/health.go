@@ -0,0 +1,48 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package events
+
+import "time"
+
+// SocketHealth is the network health of the connection in the socket_health_map,
+// sampled by the retransmit and reset tracepoints and the write operations
+type SocketHealth struct {
+	RandomID       uint64
+	Retransmits    uint32
+	ZeroWindows    uint32
+	ResetsSent     uint32
+	ResetsReceived uint32
+	RTTMin         uint32
+	RTTMax         uint32
+	RTTSum         uint64
+	RTTSamples     uint32
+	_              uint32
+}
+
+// Abnormal means the network layer problem has been detected in the connection
+func (h *SocketHealth) Abnormal() bool {
+	return h.Retransmits > 0 || h.ZeroWindows > 0 || h.ResetsSent > 0 || h.ResetsReceived > 0
+}
+
+// AvgRTT is the average of the smoothed RTT samples
+func (h *SocketHealth) AvgRTT() time.Duration {
+	if h.RTTSamples == 0 {
+		return 0
+	}
+	return time.Duration(h.RTTSum/uint64(h.RTTSamples)) * time.Microsecond
+}
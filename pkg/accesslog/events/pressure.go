@@ -0,0 +1,88 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package events
+
+import "strings"
+
+// SocketBufferPressure is the socket buffer pressure flags detected when transferring the data
+type SocketBufferPressure uint32
+
+const (
+	// SocketBufferPressurePeerZeroWindow the peer advertised zero window
+	SocketBufferPressurePeerZeroWindow SocketBufferPressure = 1 << iota
+	// SocketBufferPressurePeerZeroWindowProbe the peer zero window is persistent, the zero window probes has been sent
+	SocketBufferPressurePeerZeroWindowProbe
+	// SocketBufferPressureLocalZeroWindow the local advertised zero window
+	SocketBufferPressureLocalZeroWindow
+	// SocketBufferPressureSendBufferFull waiting for the send buffer memory
+	SocketBufferPressureSendBufferFull
+	// SocketBufferPressureReceiveBufferDrop the receive queue dropped packets
+	SocketBufferPressureReceiveBufferDrop
+)
+
+var socketBufferPressureNames = []struct {
+	flag SocketBufferPressure
+	name string
+}{
+	{SocketBufferPressurePeerZeroWindow, "peer_zero_window"},
+	{SocketBufferPressurePeerZeroWindowProbe, "peer_zero_window_probe"},
+	{SocketBufferPressureLocalZeroWindow, "local_zero_window"},
+	{SocketBufferPressureSendBufferFull, "send_buffer_full"},
+	{SocketBufferPressureReceiveBufferDrop, "receive_buffer_drop"},
+}
+
+func (p SocketBufferPressure) Has(flag SocketBufferPressure) bool {
+	return p&flag != 0
+}
+
+// Flags split all the matched single flags
+func (p SocketBufferPressure) Flags() []SocketBufferPressure {
+	result := make([]SocketBufferPressure, 0)
+	for _, n := range socketBufferPressureNames {
+		if p.Has(n.flag) {
+			result = append(result, n.flag)
+		}
+	}
+	return result
+}
+
+// Cause distinguish the slowness is caused by the peer, the network or the local process:
+// the peer is slow when it stop the sending through the zero window,
+// the network is slow when the send buffer is full but the peer window is still opened,
+// the local is slow when it advertised the zero window or the receive buffer is overflow.
+func (p SocketBufferPressure) Cause() string {
+	switch {
+	case p.Has(SocketBufferPressurePeerZeroWindow):
+		return "slow_peer"
+	case p.Has(SocketBufferPressureSendBufferFull):
+		return "slow_network"
+	case p.Has(SocketBufferPressureLocalZeroWindow), p.Has(SocketBufferPressureReceiveBufferDrop):
+		return "slow_local"
+	}
+	return ""
+}
+
+func (p SocketBufferPressure) String() string {
+	names := make([]string, 0)
+	for _, n := range socketBufferPressureNames {
+		if p.Has(n.flag) {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
@@ -22,23 +22,23 @@ import (
 )
 
 type ZTunnelSocketMappingEvent struct {
-	OriginalSrcIP        uint32
-	OriginalDestIP       uint32
+	OriginalSrcIP        [16]uint8
+	OriginalDestIP       [16]uint8
+	LoadBalancedDestIP   [16]uint8
 	OriginalSrcPort      uint16
 	OriginalDestPort     uint16
-	LoadBalancedDestIP   uint32
 	LoadBalancedDestPort uint16
 	Pad0                 uint16
-	Pad1                 uint32
+	PID                  uint32
 }
 
 func (z *ZTunnelSocketMappingEvent) ReadFrom(r btf.Reader) {
-	z.OriginalSrcIP = r.ReadUint32()
-	z.OriginalDestIP = r.ReadUint32()
+	r.ReadUint8Array(z.OriginalSrcIP[:], 16)
+	r.ReadUint8Array(z.OriginalDestIP[:], 16)
+	r.ReadUint8Array(z.LoadBalancedDestIP[:], 16)
 	z.OriginalSrcPort = r.ReadUint16()
 	z.OriginalDestPort = r.ReadUint16()
-	z.LoadBalancedDestIP = r.ReadUint32()
 	z.LoadBalancedDestPort = r.ReadUint16()
 	z.Pad0 = r.ReadUint16()
-	z.Pad1 = r.ReadUint32()
+	z.PID = r.ReadUint32()
 }
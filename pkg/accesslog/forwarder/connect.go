@@ -28,6 +28,7 @@ import (
 
 func init() {
 	RegisterKernelLogBuilder(common.LogTypeConnect, connectLogBuilder)
+	RegisterKernelLogBuilder(common.LogTypeConnectFailure, connectFailureLogBuilder)
 }
 
 func SendConnectEvent(context *common.AccessLogContext, event *events.SocketConnectEvent, socketPair *ip.SocketPair) {
@@ -37,6 +38,11 @@ func SendConnectEvent(context *common.AccessLogContext, event *events.SocketConn
 	}))
 }
 
+// SendConnectFailureEvent the connection is failure after the connect operation returned(non-blocking connect)
+func SendConnectFailureEvent(context *common.AccessLogContext, event *events.SocketConnectFailureEvent) {
+	context.Queue.AppendKernelLog(common.NewKernelLogEvent(common.LogTypeConnectFailure, event))
+}
+
 func connectLogBuilder(event events.Event) *v3.AccessLogKernelLog {
 	connectEvent := event.(*common.ConnectEventWithSocket)
 	switch connectEvent.FuncName {
@@ -62,3 +68,16 @@ func connectLogBuilder(event events.Event) *v3.AccessLogKernelLog {
 	}
 	return nil
 }
+
+func connectFailureLogBuilder(event events.Event) *v3.AccessLogKernelLog {
+	failureEvent := event.(*events.SocketConnectFailureEvent)
+	return &v3.AccessLogKernelLog{
+		Operation: &v3.AccessLogKernelLog_Connect{
+			Connect: &v3.AccessLogKernelConnectOperation{
+				StartTime: BuildOffsetTimestamp(failureEvent.StartTime),
+				EndTime:   BuildOffsetTimestamp(failureEvent.EndTime),
+				Success:   false,
+			},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package forwarder
+
+import (
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+)
+
+// SendCorrelationEvent send the correlation record when the correlation is enabled and the request contains the header
+func SendCorrelationEvent(context *common.AccessLogContext, details []events.SocketDetail, header func(key string) string,
+	method, path string, statusCode int) {
+	if context.Correlation == nil || len(details) == 0 || context.ShedLevel() >= selfprotect.LevelDisablePayload {
+		return
+	}
+	requestID := header(context.Correlation.Header)
+	if requestID == "" {
+		return
+	}
+	var headers map[string]string
+	for _, name := range context.Correlation.ExtraHeaders {
+		if value := header(name); value != "" {
+			if headers == nil {
+				headers = make(map[string]string)
+			}
+			headers[name] = value
+		}
+	}
+	context.Correlation.Append(&common.CorrelationRecord{
+		ConnectionID: details[0].GetConnectionID(),
+		RandomID:     details[0].GetRandomID(),
+		RequestID:    requestID,
+		StartTime:    details[0].GetStartTime(),
+		EndTime:      details[len(details)-1].GetEndTime(),
+		Method:       method,
+		Path:         path,
+		StatusCode:   statusCode,
+		Headers:      headers,
+		CreateTime:   time.Now(),
+	})
+}
@@ -0,0 +1,48 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package forwarder
+
+import (
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+)
+
+// PayloadCaptureRule returns the payload capture rule of the protocol,
+// nil means the payload capture is disabled or the payload inspection is shed by the self-protection
+func PayloadCaptureRule(context *common.AccessLogContext, protocol string) *common.PayloadCaptureRule {
+	if context.Payloads == nil || context.ShedLevel() >= selfprotect.LevelDisablePayload {
+		return nil
+	}
+	return context.Payloads.Rule(protocol)
+}
+
+// SendPayloadEvent send the captured payload record, the connection and time are filled from the details
+func SendPayloadEvent(context *common.AccessLogContext, details []events.SocketDetail, record *common.PayloadRecord) {
+	if context.Payloads == nil || len(details) == 0 || record == nil {
+		return
+	}
+	record.ConnectionID = details[0].GetConnectionID()
+	record.RandomID = details[0].GetRandomID()
+	record.StartTime = details[0].GetStartTime()
+	record.EndTime = details[len(details)-1].GetEndTime()
+	record.CreateTime = time.Now()
+	context.Payloads.Append(record)
+}
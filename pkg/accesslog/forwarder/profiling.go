@@ -0,0 +1,79 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package forwarder
+
+import (
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/accesslog/events"
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker/bpf/network"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+)
+
+// SendContinuousProfilingEvent notify the served HTTP request to the continuous profiling checkers,
+// it only works when the continuous profiling is reading the HTTP events from the access logs
+func SendContinuousProfilingEvent(context *common.AccessLogContext, details []events.SocketDetail, uri string, statusCode int) {
+	if !network.IsAccessLogSource() || len(details) == 0 {
+		return
+	}
+	connection := context.ConnectionMgr.FindByID(details[0].GetConnectionID(), details[0].GetRandomID())
+	if connection == nil || connection.RPCConnection == nil || connection.RPCConnection.Role != v3.DetectPoint_server {
+		return
+	}
+	network.NotifyAccessLogEvent(&profilingHTTPEvent{
+		pid:        int32(connection.PID),
+		uri:        uri,
+		statusCode: statusCode,
+		startTime:  details[0].GetStartTime(),
+		endTime:    details[len(details)-1].GetEndTime(),
+	})
+}
+
+type profilingHTTPEvent struct {
+	pid        int32
+	uri        string
+	statusCode int
+	startTime  uint64
+	endTime    uint64
+}
+
+func (e *profilingHTTPEvent) Pid() int32 {
+	return e.pid
+}
+
+func (e *profilingHTTPEvent) RequestURI() string {
+	return e.uri
+}
+
+func (e *profilingHTTPEvent) IsResponseError() bool {
+	return e.statusCode >= 500
+}
+
+func (e *profilingHTTPEvent) Duration() time.Duration {
+	if e.endTime < e.startTime {
+		return 0
+	}
+	return time.Duration(e.endTime - e.startTime)
+}
+
+func (e *profilingHTTPEvent) StartTime() time.Time {
+	return host.Time(e.startTime)
+}
@@ -22,5 +22,8 @@ import (
 )
 
 func SendTransferProtocolEvent(context *common.AccessLogContext, event common.ProtocolLog) {
+	if !context.SampleProtocolLog() {
+		return
+	}
 	context.Queue.AppendProtocolLog(event)
 }
@@ -19,11 +19,16 @@ package accesslog
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/apache/skywalking-rover/pkg/accesslog/bpf"
 	"github.com/apache/skywalking-rover/pkg/accesslog/common"
 	"github.com/apache/skywalking-rover/pkg/core"
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
+
+	"github.com/docker/go-units"
 )
 
 const ModuleName = "access_log"
@@ -46,6 +51,10 @@ func (m *Module) RequiredModules() []string {
 	return []string{core.ModuleName, process.ModuleName}
 }
 
+func (m *Module) RequiredCapabilities() []string {
+	return []string{capability.Kprobe, capability.Tracepoint}
+}
+
 func (m *Module) Config() module.ConfigInterface {
 	return m.config
 }
@@ -63,6 +72,23 @@ func (m *Module) Start(ctx context.Context, mgr *module.Manager) error {
 	return nil
 }
 
+// DryRun only load the BPF programs without attaching them, the maps are not pinned even the state handover is active
+func (m *Module) DryRun() error {
+	var ringBufferSize int64
+	if m.config.RingBuffer.Active {
+		size, err := units.RAMInBytes(m.config.RingBuffer.Size)
+		if err != nil {
+			return fmt.Errorf("parse the ring buffer size error: %v", err)
+		}
+		ringBufferSize = size
+	}
+	loader, err := bpf.NewLoader(int(ringBufferSize), "")
+	if err != nil {
+		return err
+	}
+	return loader.Close()
+}
+
 func (m *Module) NotifyStartSuccess() {
 }
 
@@ -72,3 +98,15 @@ func (m *Module) Shutdown(context.Context, *module.Manager) error {
 	}
 	return nil
 }
+
+func (m *Module) ReloadConfig(conf module.ConfigInterface) error {
+	if m.runner == nil {
+		return fmt.Errorf("the access log is not running")
+	}
+	newConf := conf.(*common.Config)
+	if err := m.runner.ReloadConfig(m.config, newConf); err != nil {
+		return err
+	}
+	m.config = newConf
+	return nil
+}
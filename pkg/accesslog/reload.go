@@ -0,0 +1,59 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package accesslog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+)
+
+// ReloadConfig apply the dynamic settings of the access log, the other settings require restarting the rover:
+// 1. exclude_namespaces and exclude_cluster: the monitoring processes are rechecked by the process listener
+// 2. drop_detection.min_sampling_rate and drop_detection.recover_step: only when the adaptive sampling is active
+// The current config is shared with the running components, so it's never changed, the caller replaces it after succeed
+func (r *Runner) ReloadConfig(current, conf *common.Config) error {
+	// make sure only the dynamic settings are changed
+	expected := *conf
+	expected.ExcludeNamespaces = current.ExcludeNamespaces
+	expected.ExcludeClusters = current.ExcludeClusters
+	expected.DropDetection.MinSamplingRate = current.DropDetection.MinSamplingRate
+	expected.DropDetection.RecoverStep = current.DropDetection.RecoverStep
+	if !reflect.DeepEqual(&expected, current) {
+		return fmt.Errorf("only the exclude_namespaces, exclude_cluster, drop_detection.min_sampling_rate and " +
+			"drop_detection.recover_step could be reloaded, please restart the rover to apply other changes")
+	}
+
+	var sampler *common.AdaptiveSampler
+	if r.dropDetection != nil {
+		sampler = r.dropDetection.sampler
+	}
+	samplingChanged := conf.DropDetection.MinSamplingRate != current.DropDetection.MinSamplingRate ||
+		conf.DropDetection.RecoverStep != current.DropDetection.RecoverStep
+	if samplingChanged && sampler != nil {
+		if err := validateAdaptiveSampling(&conf.DropDetection); err != nil {
+			return err
+		}
+		sampler.Reconfigure(conf.DropDetection.MinSamplingRate, conf.DropDetection.RecoverStep)
+	}
+
+	r.filter.Update(strings.Split(conf.ExcludeNamespaces, ","), strings.Split(conf.ExcludeClusters, ","))
+	return nil
+}
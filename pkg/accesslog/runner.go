@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	process2 "github.com/shirou/gopsutil/process"
 
 	"github.com/sirupsen/logrus"
@@ -35,8 +36,10 @@ import (
 	"github.com/apache/skywalking-rover/pkg/accesslog/sender"
 	"github.com/apache/skywalking-rover/pkg/core"
 	"github.com/apache/skywalking-rover/pkg/core/backend"
+	"github.com/apache/skywalking-rover/pkg/dns"
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 
 	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
 )
@@ -51,15 +54,38 @@ type Runner struct {
 	mgr        *module.Manager
 	backendOp  backend.Operator
 	cluster    string
+	instanceID string
 	ctx        context.Context
-	sender     *sender.GRPCSender
+	sender     *sender.Sender
+	filter     *common.StaticMonitorFilter
+
+	watchdogPeriod       time.Duration
+	selfProtectionPeriod time.Duration
+	dropDetection        *dropDetection
 }
 
 func NewRunner(mgr *module.Manager, config *common.Config) (*Runner, error) {
-	bpfLoader, err := bpf.NewLoader()
+	var err error
+	var ringBufferSize int64
+	if config.RingBuffer.Active {
+		if ringBufferSize, err = units.RAMInBytes(config.RingBuffer.Size); err != nil {
+			return nil, fmt.Errorf("parse the ring buffer size error: %v", err)
+		}
+	}
+	var pinPath string
+	if config.StateHandover.Active {
+		if config.StateHandover.PinPath == "" {
+			return nil, fmt.Errorf("please provide the pin path of the state handover")
+		}
+		pinPath = config.StateHandover.PinPath
+	}
+	bpfLoader, err := bpf.NewLoader(int(ringBufferSize), pinPath)
 	if err != nil {
 		return nil, err
 	}
+	if bpfLoader.Handover {
+		log.Infof("the active connections and monitored processes are handed over from the previous rover")
+	}
 	flushDuration, err := time.ParseDuration(config.Flush.Period)
 	if err != nil {
 		return nil, fmt.Errorf("parse flush period error: %v", err)
@@ -67,21 +93,85 @@ func NewRunner(mgr *module.Manager, config *common.Config) (*Runner, error) {
 	coreModule := mgr.FindModule(core.ModuleName).(core.Operator)
 	backendOP := coreModule.BackendOperator()
 	clusterName := coreModule.ClusterName()
-	monitorFilter := common.NewStaticMonitorFilter(strings.Split(config.ExcludeNamespaces, ","), strings.Split(config.ExcludeClusters, ","))
+	processFilter, err := filter.New(&config.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("build the process filter error: %v", err)
+	}
+	monitorFilter := common.NewStaticMonitorFilter(strings.Split(config.ExcludeNamespaces, ","), strings.Split(config.ExcludeClusters, ","),
+		processFilter)
 	connectionMgr := common.NewConnectionManager(config, mgr, bpfLoader, monitorFilter)
 	runner := &Runner{
 		context: &common.AccessLogContext{
-			BPF:           bpfLoader,
-			Config:        config,
-			ConnectionMgr: connectionMgr,
+			BPF:            bpfLoader,
+			Config:         config,
+			ConnectionMgr:  connectionMgr,
+			ParseStats:     common.NewProtocolParseStats(),
+			BufferPressure: common.NewSocketBufferPressureStats(),
+			RPC:            common.NewFlushQueue[*common.RPCRecord](),
 		},
 		collectors: collector.Collectors(),
 		mgr:        mgr,
 		backendOp:  backendOP,
 		cluster:    clusterName,
-		sender:     sender.NewGRPCSender(mgr, connectionMgr),
+		instanceID: coreModule.InstanceID(),
+		filter:     monitorFilter,
+	}
+	exporter, err := sender.NewExporter(mgr, connectionMgr, &config.Exporter)
+	if err != nil {
+		return nil, err
+	}
+	if runner.sender, err = sender.NewSender(exporter, &config.Sender); err != nil {
+		return nil, err
 	}
 	runner.context.Queue = common.NewQueue(config.Flush.MaxCountOneStream, flushDuration, runner)
+	if config.ProtocolAnalyze.TLSKeyLog.Active {
+		runner.context.TLSKeyLogs = common.NewTLSKeyLogs()
+	}
+	if config.Correlation.Active {
+		if config.Correlation.Header == "" {
+			return nil, fmt.Errorf("please provide the correlation header")
+		}
+		runner.context.Correlation = common.NewCorrelationQueue(config.Correlation.Header, config.Correlation.ExtraHeaders)
+	}
+	if config.DNS.Active {
+		runner.context.DNS = common.NewFlushQueue[*dns.Lookup]()
+	}
+	if config.Payload.Active {
+		payload := config.Payload
+		if runner.context.Payloads, err = common.NewPayloadCapture(payload.Rules, payload.RedactHeaders, payload.RedactPatterns); err != nil {
+			return nil, err
+		}
+	}
+	if config.TLSHandshake.Active {
+		runner.context.TLSHandshakes = common.NewFlushQueue[*common.TLSHandshake]()
+	}
+	if config.ZTunnel.HBONE {
+		runner.context.HBONE = common.NewHBONETunnels()
+	}
+	if config.Watchdog.Active {
+		if runner.context.Watchdog, runner.watchdogPeriod, err = newWatchdog(&config.Watchdog); err != nil {
+			return nil, err
+		}
+	}
+	if endpoint := config.ProtocolAnalyze.Endpoint; endpoint.Rules != "" || endpoint.CollapseID || endpoint.MaxDepth > 0 {
+		runner.context.EndpointNormalizer, err = common.NewEndpointNormalizer(endpoint.Rules, endpoint.CollapseID, endpoint.MaxDepth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.SelfProtection.Active {
+		if runner.context.SelfProtection, runner.selfProtectionPeriod, err = newSelfProtection(&config.SelfProtection); err != nil {
+			return nil, err
+		}
+	} else {
+		// following the load shedding level of the resource governor of rover, it is started by the core module
+		runner.context.SelfProtection = coreModule.ResourceGovernor().Guard()
+	}
+	if config.DropDetection.CheckPeriod != "" {
+		if runner.dropDetection, err = newDropDetection(&config.DropDetection); err != nil {
+			return nil, err
+		}
+	}
 	return runner, nil
 }
 
@@ -102,12 +192,28 @@ func (r *Runner) Start(ctx context.Context) error {
 		return err
 	}
 
+	if r.context.Watchdog != nil {
+		r.startWatchdog()
+	}
+	if r.selfProtectionPeriod > 0 {
+		r.context.SelfProtection.Start(ctx, r.selfProtectionPeriod)
+	}
+	if r.dropDetection != nil {
+		r.startDropDetection()
+	}
 	return nil
 }
 
 func (r *Runner) Consume(kernels chan common.KernelLog, protocols chan common.ProtocolLog) {
-	if r.backendOp.GetConnectionStatus() != backend.Connected {
-		log.Warnf("failure to connect to the backend, skip generating access log")
+	if err := r.sender.Ready(); err != nil {
+		if !r.sender.RetryEnabled() {
+			log.Warnf("%v, skip generating access log", err)
+			return
+		}
+		log.Warnf("%v, keep the access log in the retry queue", err)
+		batch := r.sender.NewBatch()
+		r.buildConnectionLogs(batch, kernels, protocols)
+		r.sender.AddRetryBatch(batch)
 		return
 	}
 
@@ -221,8 +327,14 @@ func (r *Runner) buildProtocolLog(protocolLog common.ProtocolLog) (*common.Conne
 		}
 		return nil, nil, nil, true
 	}
+	connection.RecordRequest(protocolLog.ProtocolLog())
 	kernelLogs := make([]*v3.AccessLogKernelLog, 0)
-	for _, kl := range protocolLog.RelateKernelLogs() {
+	for i, kl := range protocolLog.RelateKernelLogs() {
+		// the first kernel log is already recorded when finding the connection
+		if i > 0 {
+			connection.RecordTransfer(kl)
+			r.context.ConnectionMgr.RecordSyscall(connection, kl)
+		}
 		event := forwarder.BuildKernelLogFromEvent(common.LogTypeKernelTransfer, kl)
 		if event == nil {
 			continue
@@ -254,5 +366,5 @@ func (r *Runner) buildKernelLog(kernelLog common.KernelLog) (*common.ConnectionI
 
 func (r *Runner) Stop() error {
 	r.context.ConnectionMgr.Stop()
-	return nil
+	return r.sender.Stop()
 }
@@ -0,0 +1,45 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package accesslog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+)
+
+func newSelfProtection(config *common.SelfProtectionConfig) (*selfprotect.Guard, time.Duration, error) {
+	period, err := time.ParseDuration(config.CheckPeriod)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse the self-protection check period error: %v", err)
+	}
+	cpuLimit, memoryLimit, err := selfprotect.ParseLimits(config.CPULimit, config.MemoryLimit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse the self-protection limits error: %v", err)
+	}
+	if config.SamplingRate < 0 || config.SamplingRate > 100 {
+		return nil, 0, fmt.Errorf("the self-protection sampling rate must be in [0, 100]")
+	}
+	guard, err := selfprotect.NewGuard(cpuLimit, memoryLimit, config.HighWatermark, config.LowWatermark)
+	if err != nil {
+		return nil, 0, err
+	}
+	return guard, period, nil
+}
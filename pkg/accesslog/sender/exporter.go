@@ -0,0 +1,175 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+
+	"github.com/hashicorp/go-multierror"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+const (
+	ExporterTypeGRPC   = "grpc"
+	ExporterTypeFile   = "file"
+	ExporterTypeStdout = "stdout"
+	ExporterTypeOTLP   = "otlp"
+)
+
+// Exporter exports the batch of access logs to the destination
+type Exporter interface {
+	// Name of the exporter
+	Name() string
+	// Ready checks the exporter could export the logs now, returns the reason when not ready
+	Ready() error
+	// Export the batch logs, the context is canceled when the sender restarted
+	Export(ctx context.Context, batch *BatchLogs) error
+	// Close the exporter
+	Close() error
+}
+
+// NewExporter creates the exporter by the types(split by ","), the logs are exported to all of them
+func NewExporter(mgr *module.Manager, connectionMgr *common.ConnectionManager, config *common.ExporterConfig) (Exporter, error) {
+	nodeInfo := newNodeInfoBuilder(mgr, connectionMgr)
+	exporters := make([]Exporter, 0)
+	for _, exporterType := range strings.Split(config.Type, ",") {
+		exporter, err := newExporter(mgr, nodeInfo, config, strings.TrimSpace(exporterType))
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	if len(exporters) == 1 {
+		return exporters[0], nil
+	}
+	return &multipleExporter{exporters: exporters}, nil
+}
+
+func newExporter(mgr *module.Manager, nodeInfo *nodeInfoBuilder, config *common.ExporterConfig, exporterType string) (Exporter, error) {
+	switch exporterType {
+	case "", ExporterTypeGRPC:
+		return newGRPCExporter(mgr, nodeInfo, &config.GRPC)
+	case ExporterTypeFile:
+		return newFileExporter(nodeInfo, &config.File)
+	case ExporterTypeStdout:
+		return newStdoutExporter(nodeInfo), nil
+	case ExporterTypeOTLP:
+		return newOTLPExporter(mgr, nodeInfo), nil
+	default:
+		return nil, fmt.Errorf("unknown access log exporter type: %s", exporterType)
+	}
+}
+
+// multipleExporter exporting the logs to all the ready exporters
+type multipleExporter struct {
+	exporters []Exporter
+}
+
+func (m *multipleExporter) Name() string {
+	names := make([]string, 0, len(m.exporters))
+	for _, e := range m.exporters {
+		names = append(names, e.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (m *multipleExporter) Ready() error {
+	var result error
+	for _, e := range m.exporters {
+		err := e.Ready()
+		if err == nil {
+			return nil
+		}
+		result = multierror.Append(result, err)
+	}
+	return result
+}
+
+func (m *multipleExporter) Export(ctx context.Context, batch *BatchLogs) error {
+	var result error
+	for _, e := range m.exporters {
+		if err := e.Ready(); err != nil {
+			log.Warnf("the access log exporter %s is not ready, skip exporting: %v", e.Name(), err)
+			continue
+		}
+		if err := e.Export(ctx, batch); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s: %v", e.Name(), err))
+		}
+	}
+	return result
+}
+
+func (m *multipleExporter) Close() error {
+	var result error
+	for _, e := range m.exporters {
+		if err := e.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+type nodeInfoBuilder struct {
+	mgr           *module.Manager
+	connectionMgr *common.ConnectionManager
+	clusterName   string
+}
+
+func newNodeInfoBuilder(mgr *module.Manager, connectionMgr *common.ConnectionManager) *nodeInfoBuilder {
+	return &nodeInfoBuilder{
+		mgr:           mgr,
+		connectionMgr: connectionMgr,
+		clusterName:   mgr.FindModule(core.ModuleName).(core.Operator).ClusterName(),
+	}
+}
+
+func (n *nodeInfoBuilder) NodeName() string {
+	return n.mgr.FindModule(process.ModuleName).(process.K8sOperator).NodeName()
+}
+
+func (n *nodeInfoBuilder) Build(needs bool) *v3.EBPFAccessLogNodeInfo {
+	if !needs {
+		return nil
+	}
+	netInterfaces := make([]*v3.EBPFAccessLogNodeNetInterface, 0)
+	for i, n := range host.AllNetworkInterfaces() {
+		netInterfaces = append(netInterfaces, &v3.EBPFAccessLogNodeNetInterface{
+			Index: int32(i),
+			Mtu:   int32(n.MTU),
+			Name:  n.Name,
+		})
+	}
+	return &v3.EBPFAccessLogNodeInfo{
+		Name:          n.NodeName(),
+		NetInterfaces: netInterfaces,
+		BootTime:      host.TimeToInstant(0),
+		ClusterName:   n.clusterName,
+		Policy: &v3.EBPFAccessLogPolicy{
+			ExcludeNamespaces: n.connectionMgr.GetExcludeNamespaces(),
+		},
+	}
+}
@@ -0,0 +1,149 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/go-units"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/rotate"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+// the written size of one export chunk, the chunk always ends with a whole line
+var fileExportChunkSize = 64 * 1024
+
+// fileExporter writing the access log messages as the JSON lines,
+// every line contains the connection, and the node info is only contained in the first line of each flush
+type fileExporter struct {
+	name      string
+	nodeInfo  *nodeInfoBuilder
+	writer    io.WriteCloser
+	marshaler protojson.MarshalOptions
+}
+
+func newFileExporter(nodeInfo *nodeInfoBuilder, config *common.FileExporterConfig) (*fileExporter, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("please provide the file path of the access log exporter")
+	}
+	var maxSize int64
+	if config.MaxSize != "" {
+		size, err := units.RAMInBytes(config.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("parse the max size of the access log file error: %v", err)
+		}
+		maxSize = size
+	}
+	writer, err := rotate.NewWriter(config.Path, maxSize, config.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &fileExporter{name: ExporterTypeFile, nodeInfo: nodeInfo, writer: writer}, nil
+}
+
+func newStdoutExporter(nodeInfo *nodeInfoBuilder) *fileExporter {
+	return &fileExporter{name: ExporterTypeStdout, nodeInfo: nodeInfo, writer: nopCloser{os.Stdout}}
+}
+
+func (f *fileExporter) Name() string {
+	return f.name
+}
+
+func (f *fileExporter) Ready() error {
+	return nil
+}
+
+func (f *fileExporter) Export(ctx context.Context, batch *BatchLogs) error {
+	buf := bytes.NewBuffer(make([]byte, 0, fileExportChunkSize))
+	firstLog := true
+	for connection, logs := range batch.logs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(logs.kernels) > 0 {
+			if err := f.appendMessage(buf, f.buildAccessLogMessage(firstLog, connection, logs.kernels, nil)); err != nil {
+				return err
+			}
+			firstLog = false
+		}
+		for _, protocolLog := range logs.protocols {
+			if err := f.appendMessage(buf, f.buildAccessLogMessage(firstLog, connection, protocolLog.kernels, protocolLog.protocol)); err != nil {
+				return err
+			}
+			firstLog = false
+		}
+		if buf.Len() >= fileExportChunkSize {
+			if err := f.flush(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return f.flush(buf)
+}
+
+func (f *fileExporter) buildAccessLogMessage(firstLog bool, conn *common.ConnectionInfo,
+	kernelLogs []*v3.AccessLogKernelLog, protocolLog *v3.AccessLogProtocolLogs) *v3.EBPFAccessLogMessage {
+	return &v3.EBPFAccessLogMessage{
+		Node:        f.nodeInfo.Build(firstLog),
+		Connection:  conn.RPCConnection,
+		KernelLogs:  kernelLogs,
+		ProtocolLog: protocolLog,
+	}
+}
+
+func (f *fileExporter) appendMessage(buf *bytes.Buffer, msg *v3.EBPFAccessLogMessage) error {
+	data, err := f.marshaler.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal the access log message error: %v", err)
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+func (f *fileExporter) flush(buf *bytes.Buffer) error {
+	if buf.Len() == 0 {
+		return nil
+	}
+	defer buf.Reset()
+	if _, err := f.writer.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing the access log to %s error: %v", f.name, err)
+	}
+	return nil
+}
+
+func (f *fileExporter) Close() error {
+	return f.writer.Close()
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,165 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/core/backend"
+	"github.com/apache/skywalking-rover/pkg/module"
+
+	"github.com/sirupsen/logrus"
+
+	"google.golang.org/grpc"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+// grpcExporter sending the access log to the backend through the gRPC streaming
+type grpcExporter struct {
+	nodeInfo  *nodeInfoBuilder
+	alsClient v3.EBPFAccessLogServiceClient
+	backendOp backend.Operator
+	callOpts  []grpc.CallOption
+}
+
+func newGRPCExporter(mgr *module.Manager, nodeInfo *nodeInfoBuilder, config *common.GRPCExporterConfig) (*grpcExporter, error) {
+	if err := checkCompression(config.Compression); err != nil {
+		return nil, err
+	}
+	callOpts := make([]grpc.CallOption, 0)
+	if config.Compression != CompressionNone {
+		callOpts = append(callOpts, grpc.UseCompressor(config.Compression))
+	}
+	backendOp := mgr.FindModule(core.ModuleName).(core.Operator).BackendOperator()
+	return &grpcExporter{
+		nodeInfo:  nodeInfo,
+		alsClient: v3.NewEBPFAccessLogServiceClient(backendOp.GetConnection()),
+		backendOp: backendOp,
+		callOpts:  callOpts,
+	}, nil
+}
+
+func (g *grpcExporter) Name() string {
+	return ExporterTypeGRPC
+}
+
+func (g *grpcExporter) Ready() error {
+	if g.backendOp.GetConnectionStatus() != backend.Connected {
+		return fmt.Errorf("failure to connect to the backend")
+	}
+	if !g.backendOp.SupportService(v3.EBPFAccessLogService_ServiceDesc.ServiceName) {
+		return fmt.Errorf("the backend not supports the access log service")
+	}
+	return nil
+}
+
+func (g *grpcExporter) Close() error {
+	return nil
+}
+
+func (g *grpcExporter) Export(ctx context.Context, batch *BatchLogs) error {
+	timeout, cancelFunc := context.WithTimeout(ctx, time.Second*20)
+	defer cancelFunc()
+	streaming, err := g.alsClient.Collect(timeout, g.callOpts...)
+	if err != nil {
+		return err
+	}
+
+	firstLog := true
+	firstConnection := true
+	var sendError error
+	for connection, logs := range batch.logs {
+		if len(logs.kernels) == 0 && len(logs.protocols) == 0 {
+			continue
+		}
+		if log.Enable(logrus.DebugLevel) {
+			log.Debugf("ready to sending access log with connection, connection ID: %d, random ID: %d, "+
+				"local: %s, remote: %s, role: %s, contains ztunnel address: %t, kernel logs count: %d, protocol log count: %d",
+				connection.ConnectionID, connection.RandomID, connection.RPCConnection.Local, connection.RPCConnection.Remote,
+				connection.RPCConnection.Role, connection.RPCConnection.Attachment != nil, len(logs.kernels), len(logs.protocols))
+		}
+
+		if len(logs.kernels) > 0 {
+			sendError = g.sendLogToTheStream(streaming,
+				g.buildAccessLogMessage(firstLog, firstConnection, connection, logs.kernels, nil))
+			firstLog, firstConnection = false, false
+		}
+		for _, protocolLog := range logs.protocols {
+			sendError = g.sendLogToTheStream(streaming,
+				g.buildAccessLogMessage(firstLog, firstConnection, connection, protocolLog.kernels, protocolLog.protocol))
+			firstLog, firstConnection = false, false
+		}
+		if sendError != nil {
+			g.closeStream(streaming)
+			return fmt.Errorf("sending access log error: %v", sendError)
+		}
+
+		firstConnection = true
+	}
+
+	g.closeStream(streaming)
+	return nil
+}
+
+func (g *grpcExporter) closeStream(s v3.EBPFAccessLogService_CollectClient) {
+	if _, err := s.CloseAndRecv(); err != nil {
+		log.Warnf("closing the access log streaming error: %v", err)
+	}
+}
+
+func (g *grpcExporter) sendLogToTheStream(streaming v3.EBPFAccessLogService_CollectClient, logMsg *v3.EBPFAccessLogMessage) error {
+	if err := streaming.Send(logMsg); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (g *grpcExporter) buildAccessLogMessage(firstLog, firstConnection bool, conn *common.ConnectionInfo,
+	kernelLogs []*v3.AccessLogKernelLog, protocolLog *v3.AccessLogProtocolLogs) *v3.EBPFAccessLogMessage {
+	var rpcCon *v3.AccessLogConnection
+	if firstConnection {
+		rpcCon = g.downgradeConnection(conn.RPCConnection)
+	}
+	return &v3.EBPFAccessLogMessage{
+		Node:        g.nodeInfo.Build(firstLog),
+		Connection:  rpcCon,
+		KernelLogs:  kernelLogs,
+		ProtocolLog: protocolLog,
+	}
+}
+
+// downgradeConnection remove the fields which the backend cannot parse,
+// the connection is cloned because it may be rebuilt by the connection manager
+func (g *grpcExporter) downgradeConnection(con *v3.AccessLogConnection) *v3.AccessLogConnection {
+	if con == nil || con.Attachment == nil ||
+		g.backendOp.SupportField(string(con.ProtoReflect().Descriptor().FullName()), "attachment") {
+		return con
+	}
+	return &v3.AccessLogConnection{
+		Local:    con.Local,
+		Remote:   con.Remote,
+		Role:     con.Role,
+		TlsMode:  con.TlsMode,
+		Protocol: con.Protocol,
+	}
+}
@@ -20,10 +20,12 @@ package sender
 import (
 	"github.com/apache/skywalking-rover/pkg/accesslog/common"
 
+	"google.golang.org/protobuf/proto"
+
 	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
 )
 
-var maxLogsPerSend = 10_000
+var defaultLogsPerSend = 10_000
 
 type BatchLogs struct {
 	logs map[*common.ConnectionInfo]*ConnectionLogs
@@ -62,7 +64,40 @@ func (l *BatchLogs) AppendProtocolLog(connection *common.ConnectionInfo, kernels
 	})
 }
 
-func (l *BatchLogs) splitBatchLogs() []*BatchLogs {
+// merge the logs of the other batch into the current batch
+func (l *BatchLogs) merge(other *BatchLogs) {
+	for connection, otherLogs := range other.logs {
+		logs, ok := l.logs[connection]
+		if !ok {
+			l.logs[connection] = otherLogs
+			continue
+		}
+		logs.kernels = append(logs.kernels, otherLogs.kernels...)
+		logs.protocols = append(logs.protocols, otherLogs.protocols...)
+	}
+}
+
+// estimateSize of the batch in bytes, it is the size of the encoded logs
+func (l *BatchLogs) estimateSize() int64 {
+	var size int
+	for connection, logs := range l.logs {
+		if connection.RPCConnection != nil {
+			size += proto.Size(connection.RPCConnection)
+		}
+		for _, k := range logs.kernels {
+			size += proto.Size(k)
+		}
+		for _, p := range logs.protocols {
+			for _, k := range p.kernels {
+				size += proto.Size(k)
+			}
+			size += proto.Size(p.protocol)
+		}
+	}
+	return int64(size)
+}
+
+func (l *BatchLogs) splitBatchLogs(maxLogsPerSend int) []*BatchLogs {
 	logsCount := len(l.logs)
 	if logsCount == 0 {
 		return nil
@@ -0,0 +1,180 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/core/otlp"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+const otlpScopeName = "skywalking-rover/access-log"
+
+// otlpExporter exporting the L7 protocol logs to the OpenTelemetry collector as the log records,
+// the logs of each local process are grouped into the same resource, the L4 kernel logs are not exported
+type otlpExporter struct {
+	nodeInfo *nodeInfoBuilder
+	operator otlp.Operator
+}
+
+func newOTLPExporter(mgr *module.Manager, nodeInfo *nodeInfoBuilder) *otlpExporter {
+	return &otlpExporter{
+		nodeInfo: nodeInfo,
+		operator: mgr.FindModule(core.ModuleName).(core.Operator).OTLPOperator(),
+	}
+}
+
+func (o *otlpExporter) Name() string {
+	return ExporterTypeOTLP
+}
+
+func (o *otlpExporter) Ready() error {
+	if o.operator == nil {
+		return fmt.Errorf("the OTLP is not active in the core module")
+	}
+	return nil
+}
+
+func (o *otlpExporter) Export(ctx context.Context, batch *BatchLogs) error {
+	nodeName := o.nodeInfo.NodeName()
+	observedTime := uint64(time.Now().UnixNano())
+	resources := make(map[string]*logsv1.ResourceLogs)
+	result := make([]*logsv1.ResourceLogs, 0)
+	for connection, logs := range batch.logs {
+		if connection.RPCConnection == nil {
+			continue
+		}
+		for _, protocolLog := range logs.protocols {
+			record := o.buildLogRecord(connection.RPCConnection, connection.Attributes(), protocolLog.protocol, observedTime)
+			if record == nil {
+				continue
+			}
+			key := connection.RPCConnection.Local.String()
+			resource := resources[key]
+			if resource == nil {
+				resource = &logsv1.ResourceLogs{
+					Resource:  o.buildResource(connection.RPCConnection.Local, nodeName),
+					ScopeLogs: []*logsv1.ScopeLogs{{Scope: &commonv1.InstrumentationScope{Name: otlpScopeName}}},
+				}
+				resources[key] = resource
+				result = append(result, resource)
+			}
+			resource.ScopeLogs[0].LogRecords = append(resource.ScopeLogs[0].LogRecords, record)
+		}
+	}
+	return o.operator.ExportLogs(ctx, result)
+}
+
+func (o *otlpExporter) Close() error {
+	return nil
+}
+
+func (o *otlpExporter) buildResource(local *v3.ConnectionAddress, nodeName string) *resourcev1.Resource {
+	attrs := otlp.AppendStringAttribute(nil, "host.name", nodeName)
+	if k8s := local.GetKubernetes(); k8s != nil {
+		attrs = otlp.AppendStringAttribute(attrs, "service.name", k8s.GetServiceName())
+		attrs = otlp.AppendStringAttribute(attrs, "k8s.pod.name", k8s.GetPodName())
+		attrs = otlp.AppendStringAttribute(attrs, "k8s.container.name", k8s.GetContainerName())
+		attrs = otlp.AppendStringAttribute(attrs, "process.executable.name", k8s.GetProcessName())
+	} else if ip := local.GetIp(); ip != nil {
+		attrs = otlp.AppendStringAttribute(attrs, "host.ip", ip.GetHost())
+	}
+	return &resourcev1.Resource{Attributes: attrs}
+}
+
+func (o *otlpExporter) buildLogRecord(conn *v3.AccessLogConnection, connAttrs map[string]string,
+	protocol *v3.AccessLogProtocolLogs, observedTime uint64) *logsv1.LogRecord {
+	http := protocol.GetHttp()
+	if http == nil {
+		return nil
+	}
+	method := strings.ToUpper(http.GetRequest().GetMethod().String())
+	statusCode := http.GetResponse().GetStatusCode()
+	startTime := convertEBPFTimestamp(http.GetStartTime())
+	attrs := []*commonv1.KeyValue{
+		otlp.StringAttribute("http.request.method", method),
+		otlp.StringAttribute("url.path", http.GetRequest().GetPath()),
+		otlp.IntAttribute("http.response.status_code", int64(statusCode)),
+		otlp.StringAttribute("network.protocol.name", "http"),
+		otlp.StringAttribute("network.protocol.version", httpProtocolVersion(http.GetVersion())),
+		otlp.IntAttribute("http.request.body.size", int64(http.GetRequest().GetSizeOfBodyBytes())),
+		otlp.IntAttribute("http.response.body.size", int64(http.GetResponse().GetSizeOfBodyBytes())),
+		otlp.IntAttribute("rover.duration_ns", int64(convertEBPFTimestamp(http.GetEndTime())-startTime)),
+		otlp.StringAttribute("rover.role", conn.GetRole().String()),
+	}
+	attrs = otlp.AppendStringAttribute(attrs, "server.address", http.GetRequest().GetHost())
+	if k8s := conn.GetRemote().GetKubernetes(); k8s != nil {
+		attrs = otlp.AppendStringAttribute(attrs, "rover.remote.service", k8s.GetServiceName())
+		attrs = otlp.AppendStringAttribute(attrs, "rover.remote.pod", k8s.GetPodName())
+	} else if ip := conn.GetRemote().GetIp(); ip != nil {
+		attrs = otlp.AppendStringAttribute(attrs, "network.peer.address", ip.GetHost())
+		attrs = append(attrs, otlp.IntAttribute("network.peer.port", int64(ip.GetPort())))
+	}
+	// the extra attributes of the connection, such as the metadata of the remote address
+	keys := make([]string, 0, len(connAttrs))
+	for k := range connAttrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = otlp.AppendStringAttribute(attrs, k, connAttrs[k])
+	}
+
+	severity, severityText := logsv1.SeverityNumber_SEVERITY_NUMBER_INFO, "INFO"
+	if statusCode >= 500 {
+		severity, severityText = logsv1.SeverityNumber_SEVERITY_NUMBER_ERROR, "ERROR"
+	}
+	return &logsv1.LogRecord{
+		TimeUnixNano:         startTime,
+		ObservedTimeUnixNano: observedTime,
+		SeverityNumber:       severity,
+		SeverityText:         severityText,
+		Body: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{
+			StringValue: fmt.Sprintf("%s %s %d", method, http.GetRequest().GetPath(), statusCode),
+		}},
+		Attributes: attrs,
+	}
+}
+
+func httpProtocolVersion(version v3.AccessLogHTTPProtocolVersion) string {
+	if version == v3.AccessLogHTTPProtocolVersion_HTTP2 {
+		return "2"
+	}
+	return "1.1"
+}
+
+// convertEBPFTimestamp to the unix nano, the offset timestamp is based on the calibrated boot time
+func convertEBPFTimestamp(t *v3.EBPFTimestamp) uint64 {
+	if offset := t.GetOffset(); offset != nil {
+		return uint64(host.Time(offset.GetOffset()).UnixNano())
+	}
+	return 0
+}
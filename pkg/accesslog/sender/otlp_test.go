@@ -0,0 +1,50 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+func TestConvertEBPFTimestamp(t *testing.T) {
+	offset := func(v uint64) *v3.EBPFTimestamp {
+		return &v3.EBPFTimestamp{Timestamp: &v3.EBPFTimestamp_Offset{Offset: &v3.EBPFOffsetTimestamp{Offset: v}}}
+	}
+	tests := []struct {
+		name      string
+		timestamp *v3.EBPFTimestamp
+		expected  uint64
+	}{
+		{name: "nil timestamp", timestamp: nil, expected: 0},
+		{name: "no offset", timestamp: &v3.EBPFTimestamp{}, expected: 0},
+		{name: "boot time", timestamp: offset(0), expected: uint64(host.Time(0).UnixNano())},
+		{name: "after boot", timestamp: offset(uint64(time.Minute)), expected: uint64(host.Time(uint64(time.Minute)).UnixNano())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := convertEBPFTimestamp(tt.timestamp); actual != tt.expected {
+				t.Errorf("expected: %d, actual: %d", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import "container/list"
+
+// retryQueue keeping the failed batches for retrying, the oldest batches are dropped when exceeding the max size(bytes)
+type retryQueue struct {
+	batches *list.List
+	size    int64
+	maxSize int64
+}
+
+type retryBatch struct {
+	logs *BatchLogs
+	size int64
+	// the failed export attempts of the batch
+	attempts int
+}
+
+func newRetryQueue(maxSize int64) *retryQueue {
+	return &retryQueue{
+		batches: list.New(),
+		maxSize: maxSize,
+	}
+}
+
+func (q *retryQueue) Len() int {
+	return q.batches.Len()
+}
+
+// PushFront the batch which is failed to export, it would be retried first, returns the dropped connection count
+func (q *retryQueue) PushFront(batch *BatchLogs, attempts int) int {
+	size := batch.estimateSize()
+	q.batches.PushFront(&retryBatch{logs: batch, size: size, attempts: attempts})
+	q.size += size
+	return q.shrink()
+}
+
+// PushBack the batch which is not exported because the exporter is not ready, returns the dropped connection count
+func (q *retryQueue) PushBack(batch *BatchLogs) int {
+	size := batch.estimateSize()
+	q.batches.PushBack(&retryBatch{logs: batch, size: size})
+	q.size += size
+	return q.shrink()
+}
+
+// Pop the batch to retry, with its failed export attempts
+func (q *retryQueue) Pop() (*BatchLogs, int) {
+	e := q.batches.Front()
+	if e == nil {
+		return nil, 0
+	}
+	q.batches.Remove(e)
+	batch := e.Value.(*retryBatch)
+	q.size -= batch.size
+	return batch.logs, batch.attempts
+}
+
+func (q *retryQueue) shrink() int {
+	dropped := 0
+	for q.size > q.maxSize && q.batches.Len() > 0 {
+		batch, _ := q.Pop()
+		dropped += batch.ConnectionCount()
+	}
+	return dropped
+}
@@ -0,0 +1,90 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sender
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+)
+
+func TestRetryQueue(t *testing.T) {
+	newBatch := func(connections int) *BatchLogs {
+		batch := newBatchLogs()
+		for i := 0; i < connections; i++ {
+			batch.AppendKernelLog(&common.ConnectionInfo{ConnectionID: uint64(i)}, &v3.AccessLogKernelLog{
+				Operation: &v3.AccessLogKernelLog_Write{Write: &v3.AccessLogKernelWriteOperation{}},
+			})
+		}
+		return batch
+	}
+	batchSize := newBatch(1).estimateSize()
+
+	tests := []struct {
+		name      string
+		maxSize   int64
+		pushBack  []int
+		pushFront []int
+		dropped   int
+		popped    []int
+		attempts  []int
+	}{
+		{name: "keep all", maxSize: batchSize * 10, pushBack: []int{1, 2}, pushFront: []int{3}, popped: []int{3, 1, 2},
+			attempts: []int{1, 0, 0}},
+		{name: "drop the oldest", maxSize: batchSize * 3, pushBack: []int{1, 2, 1}, dropped: 1, popped: []int{2, 1},
+			attempts: []int{0, 0}},
+		{name: "drop the failed batch first", maxSize: batchSize * 3, pushBack: []int{2}, pushFront: []int{2}, dropped: 2,
+			popped: []int{2}, attempts: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := newRetryQueue(tt.maxSize)
+			dropped := 0
+			for _, c := range tt.pushBack {
+				dropped += queue.PushBack(newBatch(c))
+			}
+			for _, c := range tt.pushFront {
+				dropped += queue.PushFront(newBatch(c), 1)
+			}
+			if dropped != tt.dropped {
+				t.Fatalf("the dropped count should be %d, but got %d", tt.dropped, dropped)
+			}
+			popped, attempts := make([]int, 0), make([]int, 0)
+			for batch, attempt := queue.Pop(); batch != nil; batch, attempt = queue.Pop() {
+				popped = append(popped, batch.ConnectionCount())
+				attempts = append(attempts, attempt)
+			}
+			if len(popped) != len(tt.popped) {
+				t.Fatalf("the popped batches should be %v, but got %v", tt.popped, popped)
+			}
+			for i := range popped {
+				if popped[i] != tt.popped[i] {
+					t.Fatalf("the popped batches should be %v, but got %v", tt.popped, popped)
+				}
+				if attempts[i] != tt.attempts[i] {
+					t.Fatalf("the popped attempts should be %v, but got %v", tt.attempts, attempts)
+				}
+			}
+			if queue.size != 0 {
+				t.Fatalf("the size should be 0 after popped all, but got %d", queue.size)
+			}
+		})
+	}
+}
@@ -22,215 +22,369 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/skywalking-rover/pkg/accesslog/common"
-	"github.com/apache/skywalking-rover/pkg/core"
 	"github.com/apache/skywalking-rover/pkg/logger"
-	"github.com/apache/skywalking-rover/pkg/module"
-	"github.com/apache/skywalking-rover/pkg/process"
-	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
 
-	"github.com/sirupsen/logrus"
-
-	v32 "skywalking.apache.org/repo/goapi/collect/common/v3"
-	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/accesslog/v3"
+	"github.com/docker/go-units"
 )
 
 var log = logger.GetLogger("accesslog", "sender")
 
-// GRPCSender Async to sending the access log to the backend
-type GRPCSender struct {
+// restartWaitTimeout is the max time to wait for the previous sending loop exit when restarting
+const restartWaitTimeout = time.Second * 5
+
+// Sender Async to exporting the access log through the exporter
+type Sender struct {
 	logs   *list.List
 	notify chan bool
 	mutex  sync.Mutex
 	ctx    context.Context
 
-	mgr           *module.Manager
-	connectionMgr *common.ConnectionManager
-	alsClient     v3.EBPFAccessLogServiceClient
-	clusterName   string
+	// generation of the sending loop, the previous loop would exit when the sender restarted
+	generation   atomic.Int64
+	sentCount    atomic.Uint64
+	exportLock   sync.Mutex
+	exportCancel context.CancelFunc
+	loopDone     chan struct{}
+	restarting   bool
+
+	exporter Exporter
+
+	batchSize         int
+	batchInterval     time.Duration
+	maxPendingBatches int
+	lastExport        time.Time
+	// the retry queue of the failed batches, nil means not retrying
+	retries          *retryQueue
+	retryPeriod      time.Duration
+	retryMaxAttempts int
 }
 
-// NewGRPCSender creates a new GRPCSender
-func NewGRPCSender(mgr *module.Manager, connectionMgr *common.ConnectionManager) *GRPCSender {
-	return &GRPCSender{
-		logs:          list.New(),
-		notify:        make(chan bool, 1),
-		mgr:           mgr,
-		connectionMgr: connectionMgr,
-		clusterName:   mgr.FindModule(core.ModuleName).(core.Operator).ClusterName(),
-		alsClient: v3.NewEBPFAccessLogServiceClient(mgr.FindModule(core.ModuleName).(core.Operator).
-			BackendOperator().GetConnection()),
+// NewSender creates a new Sender
+func NewSender(exporter Exporter, config *common.SenderConfig) (*Sender, error) {
+	sender := &Sender{
+		logs:              list.New(),
+		notify:            make(chan bool, 1),
+		exporter:          exporter,
+		batchSize:         config.BatchSize,
+		maxPendingBatches: config.MaxPendingBatches,
+	}
+	if sender.batchSize <= 0 {
+		sender.batchSize = defaultLogsPerSend
+	}
+	if sender.maxPendingBatches <= 0 {
+		return nil, fmt.Errorf("the max pending batches of the access log sender must be bigger than 0")
+	}
+	var err error
+	if config.BatchInterval != "" {
+		if sender.batchInterval, err = time.ParseDuration(config.BatchInterval); err != nil {
+			return nil, fmt.Errorf("parse the batch interval of the access log sender error: %v", err)
+		}
 	}
+	if config.Retry.Active {
+		maxSize, err := units.RAMInBytes(config.Retry.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("parse the max size of the access log retry queue error: %v", err)
+		}
+		if sender.retryPeriod, err = time.ParseDuration(config.Retry.Period); err != nil {
+			return nil, fmt.Errorf("parse the retry period of the access log sender error: %v", err)
+		}
+		if sender.retryPeriod <= 0 {
+			return nil, fmt.Errorf("the retry period of the access log sender must be bigger than 0")
+		}
+		if sender.retryMaxAttempts = config.Retry.MaxAttempts; sender.retryMaxAttempts <= 0 {
+			return nil, fmt.Errorf("the retry max attempts of the access log sender must be bigger than 0")
+		}
+		sender.retries = newRetryQueue(maxSize)
+	}
+	return sender, nil
 }
 
-func (g *GRPCSender) Start(ctx context.Context) {
+func (g *Sender) Start(ctx context.Context) {
 	g.ctx = ctx
+	g.startSending(g.generation.Add(1))
+}
+
+func (g *Sender) startSending(generation int64) {
+	done := make(chan struct{})
+	g.exportLock.Lock()
+	g.loopDone = done
+	g.exportLock.Unlock()
 	go func() {
+		defer close(done)
+		var retryTicker <-chan time.Time
+		if g.retries != nil {
+			ticker := time.NewTicker(g.retryPeriod)
+			defer ticker.Stop()
+			retryTicker = ticker.C
+		}
 		for {
+			// the retry queue is only drained in the retry period, so the failed logs are not retried in a tight loop
+			retrying := false
 			select {
 			case <-g.notify:
-				if count, err := g.handleLogs(); err != nil {
-					log.Warnf("sending access log error, lost %d logs, error: %v", count, err)
+				if g.generation.Load() != generation {
+					// re-notify for the current sending loop
+					g.notifySending()
+					return
+				}
+			case <-retryTicker:
+				if g.generation.Load() != generation {
+					return
+				}
+				if g.retryCount() == 0 {
+					continue
 				}
-			case <-ctx.Done():
+				retrying = true
+			case <-g.ctx.Done():
+				return
+			}
+			if !g.waitBatchInterval() {
+				return
+			}
+			if count, err := g.handleLogs(generation, retrying); err != nil {
+				log.Warnf("sending access log error, lost %d logs, error: %v", count, err)
+			}
+			if g.generation.Load() != generation {
 				return
 			}
 		}
 	}()
 }
 
-func (g *GRPCSender) NewBatch() *BatchLogs {
-	return &BatchLogs{
-		logs: make(map[*common.ConnectionInfo]*ConnectionLogs),
-	}
+func (g *Sender) Name() string {
+	return "access log sender"
 }
 
-func (g *GRPCSender) AddBatch(batch *BatchLogs) {
-	// split logs
-	splitLogs := batch.splitBatchLogs()
-
-	// append the resend logs
+func (g *Sender) Progress() (handled, pending uint64) {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
-	for _, l := range splitLogs {
-		g.logs.PushBack(l)
+	pending = uint64(g.logs.Len())
+	if g.retries != nil {
+		pending += uint64(g.retries.Len())
 	}
+	return g.sentCount.Load(), pending
+}
 
-	// notify the sender
+// RetryEnabled is keeping the logs for retrying when the exporter failed or not ready
+func (g *Sender) RetryEnabled() bool {
+	return g.retries != nil
+}
+
+// Ready checks the exporter could export the access log now
+func (g *Sender) Ready() error {
+	return g.exporter.Ready()
+}
+
+// Restart the sender by canceling the wedged exporting, the new sending loop is started after the previous loop exited,
+// so the logs are never exported concurrently
+func (g *Sender) Restart() error {
+	if g.ctx == nil {
+		return fmt.Errorf("the sender is not started")
+	}
+	g.exportLock.Lock()
+	if g.restarting {
+		g.exportLock.Unlock()
+		return fmt.Errorf("the previous sending loop is still blocked, waiting for it exit")
+	}
+	generation := g.generation.Add(1)
+	if g.exportCancel != nil {
+		g.exportCancel()
+	}
+	g.restarting = true
+	done := g.loopDone
+	g.exportLock.Unlock()
+	// wake up the previous loop if it is waiting
+	g.notifySending()
+
+	restart := func() {
+		g.exportLock.Lock()
+		g.restarting = false
+		g.exportLock.Unlock()
+		g.startSending(generation)
+		g.notifySending()
+	}
 	select {
-	case g.notify <- true:
-	default:
+	case <-done:
+		restart()
+		return nil
+	case <-time.After(restartWaitTimeout):
+		go func() {
+			<-done
+			restart()
+		}()
+		return fmt.Errorf("the previous sending loop is still blocked after canceled, " +
+			"the new loop would be started after it exited")
 	}
 }
 
-func (g *GRPCSender) handleLogs() (int, error) {
-	for {
-		// pop logs
-		logs := g.popLogs()
-		if logs == nil {
-			return 0, nil
-		}
-		// send logs
-		now := time.Now()
-		if err := g.sendLogs(logs); err != nil {
-			return len(logs.logs), err
-		}
-		log.Infof("sending access log success, connection count: %d, use time: %s",
-			logs.ConnectionCount(), time.Since(now).String())
+func (g *Sender) notifySending() {
+	select {
+	case g.notify <- true:
+	default:
 	}
 }
 
-func (g *GRPCSender) sendLogs(batch *BatchLogs) error {
-	timeout, cancelFunc := context.WithTimeout(g.ctx, time.Second*20)
-	defer cancelFunc()
-	streaming, err := g.alsClient.Collect(timeout)
-	if err != nil {
-		return err
+func (g *Sender) NewBatch() *BatchLogs {
+	return &BatchLogs{
+		logs: make(map[*common.ConnectionInfo]*ConnectionLogs),
 	}
+}
 
-	firstLog := true
-	firstConnection := true
-	var sendError error
-	for connection, logs := range batch.logs {
-		if len(logs.kernels) == 0 && len(logs.protocols) == 0 {
-			continue
-		}
-		if log.Enable(logrus.DebugLevel) {
-			log.Debugf("ready to sending access log with connection, connection ID: %d, random ID: %d, "+
-				"local: %s, remote: %s, role: %s, contains ztunnel address: %t, kernel logs count: %d, protocol log count: %d",
-				connection.ConnectionID, connection.RandomID, connection.RPCConnection.Local, connection.RPCConnection.Remote,
-				connection.RPCConnection.Role, connection.RPCConnection.Attachment != nil, len(logs.kernels), len(logs.protocols))
-		}
-
-		if len(logs.kernels) > 0 {
-			sendError = g.sendLogToTheStream(streaming,
-				g.buildAccessLogMessage(firstLog, firstConnection, connection, logs.kernels, nil))
-			firstLog, firstConnection = false, false
-		}
-		for _, protocolLog := range logs.protocols {
-			sendError = g.sendLogToTheStream(streaming,
-				g.buildAccessLogMessage(firstLog, firstConnection, connection, protocolLog.kernels, protocolLog.protocol))
-			firstLog, firstConnection = false, false
-		}
-		if sendError != nil {
-			g.closeStream(streaming)
-			return fmt.Errorf("sending access log error: %v", sendError)
-		}
+func (g *Sender) AddBatch(batch *BatchLogs) {
+	// split logs
+	splitLogs := batch.splitBatchLogs(g.batchSize)
 
-		firstConnection = true
+	// append the resend logs
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for _, l := range splitLogs {
+		g.logs.PushBack(l)
+	}
+	dropped := 0
+	for g.logs.Len() > g.maxPendingBatches {
+		e := g.logs.Front()
+		dropped += e.Value.(*BatchLogs).ConnectionCount()
+		g.logs.Remove(e)
+	}
+	if dropped > 0 {
+		log.Warnf("the pending access logs exceed the max %d batches, dropped %d connection logs", g.maxPendingBatches, dropped)
 	}
 
-	g.closeStream(streaming)
-	return nil
+	// notify the sender
+	g.notifySending()
 }
 
-func (g *GRPCSender) closeStream(s v3.EBPFAccessLogService_CollectClient) {
-	if _, err := s.CloseAndRecv(); err != nil {
-		log.Warnf("closing the access log streaming error: %v", err)
+// AddRetryBatch keeping the batch in the retry queue when the exporter is not ready, it is sent in the next retry period
+func (g *Sender) AddRetryBatch(batch *BatchLogs) {
+	if g.retries == nil {
+		return
+	}
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	dropped := 0
+	for _, l := range batch.splitBatchLogs(g.batchSize) {
+		dropped += g.retries.PushBack(l)
+	}
+	if dropped > 0 {
+		log.Warnf("the access log retry queue is full, dropped %d connection logs", dropped)
 	}
 }
 
-func (g *GRPCSender) sendLogToTheStream(streaming v3.EBPFAccessLogService_CollectClient, logMsg *v3.EBPFAccessLogMessage) error {
-	if err := streaming.Send(logMsg); err != nil {
-		return err
-	}
-	return nil
+func (g *Sender) retryCount() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.retries.Len()
 }
 
-func (g *GRPCSender) buildAccessLogMessage(firstLog, firstConnection bool, conn *common.ConnectionInfo,
-	kernelLogs []*v3.AccessLogKernelLog, protocolLog *v3.AccessLogProtocolLogs) *v3.EBPFAccessLogMessage {
-	var rpcCon *v3.AccessLogConnection
-	if firstConnection {
-		rpcCon = conn.RPCConnection
+// waitBatchInterval waiting the batch interval since the last export, for merging more logs into one export
+func (g *Sender) waitBatchInterval() bool {
+	if g.batchInterval <= 0 {
+		return true
+	}
+	wait := g.batchInterval - time.Since(g.lastExport)
+	if wait <= 0 {
+		return true
 	}
-	return &v3.EBPFAccessLogMessage{
-		Node:        g.BuildNodeInfo(firstLog),
-		Connection:  rpcCon,
-		KernelLogs:  kernelLogs,
-		ProtocolLog: protocolLog,
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-g.ctx.Done():
+		return false
 	}
 }
 
-func (g *GRPCSender) BuildNodeInfo(needs bool) *v3.EBPFAccessLogNodeInfo {
-	if !needs {
-		return nil
+// retryLogs keeping the batch in the retry queue, the attempts is the failed export times of the batch,
+// the batch is dropped when reaching the max attempts
+func (g *Sender) retryLogs(batch *BatchLogs, attempts int, err error) {
+	if attempts >= g.retryMaxAttempts {
+		log.Warnf("sending access log error, dropped %d connection logs after %d failed attempts, error: %v",
+			batch.ConnectionCount(), attempts, err)
+		return
 	}
-	netInterfaces := make([]*v3.EBPFAccessLogNodeNetInterface, 0)
-	for i, n := range host.AllNetworkInterfaces() {
-		netInterfaces = append(netInterfaces, &v3.EBPFAccessLogNodeNetInterface{
-			Index: int32(i),
-			Mtu:   int32(n.MTU),
-			Name:  n.Name,
-		})
-	}
-	return &v3.EBPFAccessLogNodeInfo{
-		Name:          g.mgr.FindModule(process.ModuleName).(process.K8sOperator).NodeName(),
-		NetInterfaces: netInterfaces,
-		BootTime:      g.convertTimeToInstant(host.BootTime),
-		ClusterName:   g.clusterName,
-		Policy: &v3.EBPFAccessLogPolicy{
-			ExcludeNamespaces: g.connectionMgr.GetExcludeNamespaces(),
-		},
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if dropped := g.retries.PushFront(batch, attempts); dropped > 0 {
+		log.Warnf("the access log retry queue is full, dropped %d connection logs", dropped)
 	}
+	log.Warnf("sending access log error, %d connection logs are kept for retrying in the next retry period, "+
+		"failed attempts: %d/%d, error: %v", batch.ConnectionCount(), attempts, g.retryMaxAttempts, err)
 }
 
-func (g *GRPCSender) convertTimeToInstant(t time.Time) *v32.Instant {
-	return &v32.Instant{
-		Seconds: t.Unix(),
-		Nanos:   int32(t.Nanosecond()),
+func (g *Sender) handleLogs(generation int64, retrying bool) (int, error) {
+	for g.generation.Load() == generation {
+		// pop logs
+		logs, attempts := g.popLogs(retrying)
+		if logs == nil {
+			return 0, nil
+		}
+		if g.retries != nil {
+			if err := g.exporter.Ready(); err != nil {
+				// not counted as the failed attempt because the logs are not exported
+				g.retryLogs(logs, attempts, err)
+				return 0, nil
+			}
+		}
+		// send logs
+		now := time.Now()
+		err := g.exportLogs(logs)
+		g.lastExport = time.Now()
+		selfobs.Observe(selfobs.FlushDuration, "sender", "access_log", float64(time.Since(now).Milliseconds()))
+		g.sentCount.Add(1)
+		if err != nil {
+			selfobs.Increase(selfobs.FlushError, "sender", "access_log", 1)
+			if g.retries != nil {
+				g.retryLogs(logs, attempts+1, err)
+				return 0, nil
+			}
+			return len(logs.logs), err
+		}
+		log.Infof("sending access log success, exporter: %s, connection count: %d, use time: %s",
+			g.exporter.Name(), logs.ConnectionCount(), time.Since(now).String())
 	}
+	return 0, nil
+}
+
+func (g *Sender) exportLogs(batch *BatchLogs) error {
+	ctx, cancelFunc := context.WithCancel(g.ctx)
+	defer cancelFunc()
+	g.exportLock.Lock()
+	g.exportCancel = cancelFunc
+	g.exportLock.Unlock()
+	return g.exporter.Export(ctx, batch)
 }
 
-func (g *GRPCSender) popLogs() *BatchLogs {
+// Stop the sender and close the exporter
+func (g *Sender) Stop() error {
+	return g.exporter.Close()
+}
+
+// popLogs pops the retry logs with its failed attempts when retrying, otherwise merges the pending logs until reaching the batch size
+func (g *Sender) popLogs(retrying bool) (*BatchLogs, int) {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
+	if retrying {
+		return g.retries.Pop()
+	}
 	if g.logs.Len() == 0 {
-		return nil
+		return nil, 0
 	}
 	e := g.logs.Front()
 	logs := e.Value.(*BatchLogs)
 	g.logs.Remove(e)
-	return logs
+	for next := g.logs.Front(); next != nil; next = g.logs.Front() {
+		nextLogs := next.Value.(*BatchLogs)
+		if logs.ConnectionCount()+nextLogs.ConnectionCount() > g.batchSize {
+			break
+		}
+		logs.merge(nextLogs)
+		g.logs.Remove(next)
+	}
+	return logs, 0
 }
@@ -0,0 +1,86 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package accesslog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/accesslog/common"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+	"github.com/apache/skywalking-rover/pkg/tools/watchdog"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+const (
+	watchdogRestartsMeterName = "access_log_watchdog_restart_counter"
+	watchdogStalledMeterName  = "access_log_watchdog_stalled"
+)
+
+func newWatchdog(config *common.WatchdogConfig) (*watchdog.Watchdog, time.Duration, error) {
+	period, err := time.ParseDuration(config.CheckPeriod)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse the watchdog check period error: %v", err)
+	}
+	stallTimeout, err := time.ParseDuration(config.StallTimeout)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse the watchdog stall timeout error: %v", err)
+	}
+	maxBackoff, err := time.ParseDuration(config.MaxBackoff)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse the watchdog max backoff error: %v", err)
+	}
+	if maxBackoff < stallTimeout {
+		return nil, 0, fmt.Errorf("the watchdog max backoff must be bigger than the stall timeout")
+	}
+	return watchdog.NewWatchdog(stallTimeout, stallTimeout, maxBackoff), period, nil
+}
+
+func (r *Runner) startWatchdog() {
+	r.context.Watchdog.Register(r.context.Queue, r.sender)
+	sender := reporter.NewMeterSender("watchdog statuses", r.backendOp.GetConnection())
+	reporter.StartPeriodic(r.ctx, "watchdog statuses", r.watchdogPeriod, func(ctx context.Context) error {
+		r.context.Watchdog.Check(time.Now())
+		return r.reportWatchdogStatuses(ctx, sender)
+	})
+}
+
+func (r *Runner) reportWatchdogStatuses(ctx context.Context, sender *reporter.MeterSender) error {
+	statuses := r.context.Watchdog.Statuses()
+	if len(statuses) == 0 {
+		return nil
+	}
+	data := make([]*v3.MeterData, 0, len(statuses)*2)
+	for _, s := range statuses {
+		labels := []*v3.Label{{Name: "component", Value: s.Name}}
+		stalled := 0.0
+		if s.Stalled {
+			stalled = 1
+		}
+		data = append(data,
+			reporter.SingleValueMeter(watchdogRestartsMeterName, labels, float64(s.Restarts)),
+			reporter.SingleValueMeter(watchdogStalledMeterName, labels, stalled))
+	}
+	data[0].Service = common.SelfMeterService
+	data[0].ServiceInstance = r.instanceID
+	data[0].Timestamp = time.Now().UnixMilli()
+
+	return sender.Send(ctx, []*v3.MeterDataCollection{{MeterData: data}})
+}
@@ -0,0 +1,196 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package boot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/config"
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
+)
+
+// the statuses of the modules in the dry-run report
+const (
+	DryRunModuleActive   = "active"
+	DryRunModuleInactive = "inactive"
+	DryRunModuleDisabled = "disabled"
+	DryRunModuleFailure  = "failure"
+)
+
+// DryRunReport is the result of verifying the config file in the current node without starting the collection
+type DryRunReport struct {
+	// ConfigError is the error of loading or resolving the config file
+	ConfigError string             `json:"config_error,omitempty"`
+	Capability  *capability.Report `json:"capability"`
+	Modules     []*DryRunModule    `json:"modules"`
+	Processes   []*DryRunProcess   `json:"processes"`
+	// ProcessError is the error of starting the process module for detecting the processes
+	ProcessError string `json:"process_error,omitempty"`
+}
+
+type DryRunModule struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Reason is the missing capabilities of the disabled module, or the dry-run error of the failure module
+	Reason string `json:"reason,omitempty"`
+}
+
+// DryRunProcess is the detected process, with the active modules which would collect it
+type DryRunProcess struct {
+	Pid        int32    `json:"pid"`
+	DetectType string   `json:"detect_type"`
+	Layer      string   `json:"layer"`
+	Service    string   `json:"service"`
+	Instance   string   `json:"instance"`
+	Process    string   `json:"process"`
+	Labels     []string `json:"labels,omitempty"`
+	Monitored  bool     `json:"monitored"`
+	Modules    []string `json:"modules,omitempty"`
+}
+
+// DryRun verify the config file and the active modules, the processes are detected through starting the process module
+// when the wait duration bigger than zero
+func DryRun(ctx context.Context, file string, processWait time.Duration) *DryRunReport {
+	report := &DryRunReport{Capability: capability.BuildReport(make([]*capability.ModuleStatus, 0))}
+	conf, err := config.Load(file)
+	if err != nil {
+		report.ConfigError = fmt.Sprintf("load config error: %s, %v", file, err)
+		return report
+	}
+	modules, err := findAllDeclaredModules(conf)
+	if err != nil {
+		report.ConfigError = err.Error()
+		return report
+	}
+	starter := NewModuleStarter(modules)
+	starter.disableUnsupportedModules()
+	if err = starter.ResolveDependency(); err != nil {
+		report.ConfigError = err.Error()
+		return report
+	}
+
+	activeModules := make([]module.Module, 0)
+	for _, mod := range modules {
+		result := &DryRunModule{Name: mod.Name(), Status: DryRunModuleActive}
+		if missing, disabled := starter.disabledModules[mod.Name()]; disabled {
+			result.Status, result.Reason = DryRunModuleDisabled, fmt.Sprintf("unsupported capabilities: %v", missing)
+		} else if !mod.Config().IsActive() {
+			result.Status = DryRunModuleInactive
+		} else if runner, ok := mod.(module.DryRunner); ok {
+			if err := runner.DryRun(); err != nil {
+				result.Status, result.Reason = DryRunModuleFailure, err.Error()
+			}
+		}
+		if result.Status == DryRunModuleActive {
+			activeModules = append(activeModules, mod)
+		}
+		report.Modules = append(report.Modules, result)
+	}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Name < report.Modules[j].Name
+	})
+
+	if processWait > 0 {
+		processes, err := detectProcesses(ctx, modules, activeModules, processWait)
+		if err != nil {
+			report.ProcessError = err.Error()
+		}
+		report.Processes = processes
+	}
+	return report
+}
+
+// detectProcesses only start the process module(with the required modules) for detecting the processes
+func detectProcesses(ctx context.Context, modules, activeModules []module.Module, wait time.Duration) ([]*DryRunProcess, error) {
+	processModules := make([]module.Module, 0)
+	for _, mod := range modules {
+		switch mod.Name() {
+		case logger.ModuleName, core.ModuleName, process.ModuleName:
+			processModules = append(processModules, mod)
+		}
+	}
+	if !containsModule(processModules, process.ModuleName) {
+		return nil, fmt.Errorf("the process module is not declared")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var result []*DryRunProcess
+	err := NewModuleStarter(processModules).Run(ctx, func(mgr *module.Manager) {
+		defer cancel()
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+		result = buildDryRunProcesses(mgr.FindModule(process.ModuleName).(*process.Module), activeModules)
+	})
+	return result, err
+}
+
+func buildDryRunProcesses(processModule *process.Module, activeModules []module.Module) []*DryRunProcess {
+	// the active modules which collecting the monitored processes
+	collectors := make([]string, 0)
+	for _, mod := range activeModules {
+		for _, required := range mod.RequiredModules() {
+			if required == process.ModuleName {
+				collectors = append(collectors, mod.Name())
+				break
+			}
+		}
+	}
+	sort.Strings(collectors)
+
+	result := make([]*DryRunProcess, 0)
+	for _, p := range processModule.GetAllProcesses() {
+		entity := p.Entity()
+		item := &DryRunProcess{
+			Pid:        p.Pid(),
+			DetectType: p.DetectType().Name(),
+			Layer:      entity.Layer,
+			Service:    entity.ServiceName,
+			Instance:   entity.InstanceName,
+			Process:    entity.ProcessName,
+			Labels:     entity.Labels,
+			Monitored:  processModule.ShouldMonitor(p.Pid()),
+		}
+		if item.Monitored {
+			item.Modules = collectors
+		}
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pid < result[j].Pid
+	})
+	return result
+}
+
+func containsModule(modules []module.Module, name string) bool {
+	for _, mod := range modules {
+		if mod.Name() == name {
+			return true
+		}
+	}
+	return false
+}
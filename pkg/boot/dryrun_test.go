@@ -0,0 +1,72 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package boot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		configError string
+		modules     map[string]string
+	}{
+		{
+			name:    "valid config",
+			content: "logger:\n  level: INFO\npprof:\n  active: false\n  port: 6060\n",
+			modules: map[string]string{"logger": DryRunModuleActive, "pprof": DryRunModuleInactive},
+		},
+		{
+			name:        "unknown module",
+			content:     "logger:\n  level: INFO\nunknown:\n  active: true\n",
+			configError: "could not found module: unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "rover.yaml")
+			if err := os.WriteFile(file, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			report := DryRun(context.Background(), file, 0)
+			if tt.configError != "" {
+				if !strings.Contains(report.ConfigError, tt.configError) {
+					t.Fatalf("expected config error: %s, actual: %s", tt.configError, report.ConfigError)
+				}
+				return
+			}
+			if report.ConfigError != "" {
+				t.Fatalf("should not have config error: %s", report.ConfigError)
+			}
+			if len(report.Modules) != len(tt.modules) {
+				t.Fatalf("expected modules: %v, actual count: %d", tt.modules, len(report.Modules))
+			}
+			for _, m := range report.Modules {
+				if tt.modules[m.Name] != m.Status {
+					t.Fatalf("expected module %s status: %s, actual: %s", m.Name, tt.modules[m.Name], m.Status)
+				}
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
 )
 
 type ModuleStarter struct {
@@ -39,6 +40,8 @@ type ModuleStarter struct {
 	orderedModules []module.Module
 	startedModules []module.Module
 	moduleManager  *module.Manager
+	// disabledModules are the modules not started by the unsupported kernel capabilities, with the missing capabilities
+	disabledModules map[string][]string
 }
 
 func NewModuleStarter(modules []module.Module) *ModuleStarter {
@@ -53,16 +56,19 @@ func NewModuleStarter(modules []module.Module) *ModuleStarter {
 		moduleMap[mod.Name()] = mod
 	}
 	return &ModuleStarter{
-		original:       modules,
-		activeModules:  activeModules,
-		moduleMap:      moduleMap,
-		orderedModules: make([]module.Module, 0),
-		visited:        make(map[string]bool),
-		startedModules: make([]module.Module, 0),
+		original:        modules,
+		activeModules:   activeModules,
+		moduleMap:       moduleMap,
+		orderedModules:  make([]module.Module, 0),
+		visited:         make(map[string]bool),
+		startedModules:  make([]module.Module, 0),
+		disabledModules: make(map[string][]string),
 	}
 }
 
 func (m *ModuleStarter) Run(ctx context.Context, startUpSuccessCallback func(*module.Manager)) error {
+	m.disableUnsupportedModules()
+
 	// resolve module dependencies
 	if err := m.ResolveDependency(); err != nil {
 		return err
@@ -123,6 +129,59 @@ func (m *ModuleStarter) Run(ctx context.Context, startUpSuccessCallback func(*mo
 	return nil
 }
 
+// disableUnsupportedModules removes the modules which require the unsupported kernel capabilities from the active modules,
+// and the modules depending on them are removed too
+func (m *ModuleStarter) disableUnsupportedModules() {
+	for _, mod := range m.activeModules {
+		requirer, ok := mod.(module.CapabilityRequirer)
+		if !ok {
+			continue
+		}
+		if missing := capability.Unsupported(requirer.RequiredCapabilities()); len(missing) > 0 {
+			log.Warnf("module %s is disabled, the kernel capabilities %v are not supported", mod.Name(), missing)
+			m.disabledModules[mod.Name()] = missing
+		}
+	}
+	for changed := len(m.disabledModules) > 0; changed; {
+		changed = false
+		for _, mod := range m.activeModules {
+			if _, disabled := m.disabledModules[mod.Name()]; disabled {
+				continue
+			}
+			for _, required := range mod.RequiredModules() {
+				if missing, disabled := m.disabledModules[required]; disabled {
+					log.Warnf("module %s is disabled, the required module %s is disabled", mod.Name(), required)
+					m.disabledModules[mod.Name()] = missing
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	if len(m.disabledModules) == 0 {
+		return
+	}
+	activeModules := make([]module.Module, 0, len(m.activeModules))
+	for _, mod := range m.activeModules {
+		if _, disabled := m.disabledModules[mod.Name()]; !disabled {
+			activeModules = append(activeModules, mod)
+		}
+	}
+	m.activeModules = activeModules
+}
+
+// CapabilityStatuses are the statuses of the started and disabled modules for the capability report
+func (m *ModuleStarter) CapabilityStatuses() []*capability.ModuleStatus {
+	result := make([]*capability.ModuleStatus, 0, len(m.startedModules)+len(m.disabledModules))
+	for _, mod := range m.startedModules {
+		result = append(result, &capability.ModuleStatus{Name: mod.Name(), Status: capability.StatusActive})
+	}
+	for name, missing := range m.disabledModules {
+		result = append(result, &capability.ModuleStatus{Name: name, Status: capability.StatusDisabled, Missing: missing})
+	}
+	return result
+}
+
 func (m *ModuleStarter) ResolveDependency() error {
 	// make the log module as first active module
 	sort.Slice(m.activeModules, func(i, _ int) bool {
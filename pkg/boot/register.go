@@ -20,11 +20,18 @@ package boot
 import (
 	"github.com/apache/skywalking-rover/pkg/accesslog"
 	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/dns"
+	"github.com/apache/skywalking-rover/pkg/fdpressure"
+	"github.com/apache/skywalking-rover/pkg/icmp"
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/pcap"
+	"github.com/apache/skywalking-rover/pkg/podtraffic"
 	"github.com/apache/skywalking-rover/pkg/pprof"
 	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/processexit"
 	"github.com/apache/skywalking-rover/pkg/profiling"
+	"github.com/apache/skywalking-rover/pkg/toptalkers"
 )
 
 func init() {
@@ -35,4 +42,11 @@ func init() {
 	module.Register(profiling.NewModule())
 	module.Register(accesslog.NewModule())
 	module.Register(pprof.NewModule())
+	module.Register(dns.NewModule())
+	module.Register(toptalkers.NewModule())
+	module.Register(icmp.NewModule())
+	module.Register(fdpressure.NewModule())
+	module.Register(processexit.NewModule())
+	module.Register(podtraffic.NewModule())
+	module.Register(pcap.NewModule())
 }
@@ -0,0 +1,144 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package boot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/apache/skywalking-rover/pkg/config"
+	"github.com/apache/skywalking-rover/pkg/module"
+)
+
+// ConfigWatcher checks the configuration file periodically, when the file changed,
+// the changed module configs are applied through the module.ConfigReloader without restarting the modules
+type ConfigWatcher struct {
+	file    string
+	period  time.Duration
+	modules map[string]module.Module
+
+	content  []byte
+	settings map[string]interface{}
+}
+
+func NewConfigWatcher(file string, conf *config.Config, startedModules []module.Module, period time.Duration) *ConfigWatcher {
+	modules := make(map[string]module.Module, len(startedModules))
+	for _, mod := range startedModules {
+		modules[mod.Name()] = mod
+	}
+	settings := make(map[string]interface{})
+	for _, key := range conf.GetTopLevelKeys() {
+		settings[key] = conf.GetSettings(key)
+	}
+	content, _ := os.ReadFile(file)
+	return &ConfigWatcher{
+		file:     file,
+		period:   period,
+		modules:  modules,
+		content:  content,
+		settings: settings,
+	}
+}
+
+func (w *ConfigWatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.check()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (w *ConfigWatcher) check() {
+	content, err := os.ReadFile(w.file)
+	if err != nil {
+		log.Warnf("read the config file %s failure: %v", w.file, err)
+		return
+	}
+	if bytes.Equal(content, w.content) {
+		return
+	}
+	w.content = content
+	conf, err := config.Load(w.file)
+	if err != nil {
+		log.Warnf("the config file %s changed, but load failure: %v", w.file, err)
+		return
+	}
+	if err := w.reload(conf); err != nil {
+		log.Warnf("the config file %s changed, but some changes are rejected: %v", w.file, err)
+	}
+}
+
+// reload the modules which config changed, returns the error when any changed module could not apply the config
+func (w *ConfigWatcher) reload(conf *config.Config) error {
+	names := conf.GetTopLevelKeys()
+	for name := range w.settings {
+		if conf.GetSettings(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result error
+	for _, name := range names {
+		settings := conf.GetSettings(name)
+		if reflect.DeepEqual(w.settings[name], settings) {
+			continue
+		}
+		if err := w.reloadModule(name, conf); err != nil {
+			result = multierror.Append(result, fmt.Errorf("module %s: %v", name, err))
+			continue
+		}
+		// only keep the applied settings, so the rejected changes are compared and tried again in the next change
+		w.settings[name] = settings
+		log.Infof("the config of module %s is reloaded", name)
+	}
+	return result
+}
+
+func (w *ConfigWatcher) reloadModule(name string, conf *config.Config) error {
+	mod := w.modules[name]
+	if mod == nil {
+		return fmt.Errorf("the module is not started, please restart the rover to apply the changes")
+	}
+	reloader, ok := mod.(module.ConfigReloader)
+	if !ok {
+		return fmt.Errorf("the module does not support reloading the config, please restart the rover to apply the changes")
+	}
+	newConf := reflect.New(reflect.TypeOf(mod.Config()).Elem()).Interface().(module.ConfigInterface)
+	if err := conf.UnMarshalWithKey(name, newConf); err != nil {
+		return fmt.Errorf("read the config error: %v", err)
+	}
+	if !newConf.IsActive() {
+		return fmt.Errorf("could not deactivate the module at runtime, please restart the rover to apply the changes")
+	}
+	return reloader.ReloadConfig(newConf)
+}
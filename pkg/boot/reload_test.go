@@ -0,0 +1,136 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/config"
+	"github.com/apache/skywalking-rover/pkg/module"
+)
+
+func TestConfigWatcherReload(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		changed  string
+		reloaded []string
+		rejected bool
+	}{
+		{
+			name:     "dynamic setting changed",
+			original: "reload:\n  active: true\n  level: info\nstatic:\n  active: true\n",
+			changed:  "reload:\n  active: true\n  level: debug\nstatic:\n  active: true\n",
+			reloaded: []string{"debug"},
+		},
+		{
+			name:     "nothing changed",
+			original: "reload:\n  active: true\n  level: info\nstatic:\n  active: true\n",
+			changed:  "reload:\n  active: true\n  level: info\nstatic:\n  active: true\n",
+		},
+		{
+			name:     "non-dynamic setting rejected by module",
+			original: "reload:\n  active: true\n  level: info\n",
+			changed:  "reload:\n  active: true\n  level: trace\n",
+			rejected: true,
+		},
+		{
+			name:     "module not support reload",
+			original: "reload:\n  active: true\n  level: info\nstatic:\n  active: true\n",
+			changed:  "reload:\n  active: true\n  level: info\nstatic:\n  active: false\n",
+			rejected: true,
+		},
+		{
+			name:     "module not started",
+			original: "reload:\n  active: true\n  level: info\n",
+			changed:  "reload:\n  active: true\n  level: info\nstatic:\n  active: true\n",
+			rejected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "rover.yaml")
+			original := writeAndLoadConfig(t, file, tt.original)
+			mod := &testReloadModule{conf: &testReloadConfig{}}
+			if err := original.UnMarshalWithKey("reload", mod.conf); err != nil {
+				t.Fatal(err)
+			}
+			watcher := NewConfigWatcher(file, original, []module.Module{mod, &testModule{name: "static"}}, 0)
+
+			changed := writeAndLoadConfig(t, file, tt.changed)
+			err := watcher.reload(changed)
+			if (err != nil) != tt.rejected {
+				t.Fatalf("want rejected: %t, got error: %v", tt.rejected, err)
+			}
+			if fmt.Sprint(mod.reloaded) != fmt.Sprint(tt.reloaded) {
+				t.Fatalf("want reloaded: %v, got: %v", tt.reloaded, mod.reloaded)
+			}
+
+			// the applied settings are not reloaded again, and the rejected settings are still compared as changed
+			err = watcher.reload(changed)
+			if (err != nil) != tt.rejected {
+				t.Fatalf("reload again want rejected: %t, got error: %v", tt.rejected, err)
+			}
+			if fmt.Sprint(mod.reloaded) != fmt.Sprint(tt.reloaded) {
+				t.Fatalf("reload again want reloaded: %v, got: %v", tt.reloaded, mod.reloaded)
+			}
+		})
+	}
+}
+
+func writeAndLoadConfig(t *testing.T, file, content string) *config.Config {
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := config.Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conf
+}
+
+type testReloadConfig struct {
+	module.Config `mapstructure:",squash"`
+	Level         string `mapstructure:"level"`
+}
+
+type testReloadModule struct {
+	testModule
+	conf     *testReloadConfig
+	reloaded []string
+}
+
+func (t *testReloadModule) Name() string {
+	return "reload"
+}
+
+func (t *testReloadModule) Config() module.ConfigInterface {
+	return t.conf
+}
+
+func (t *testReloadModule) ReloadConfig(conf module.ConfigInterface) error {
+	level := conf.(*testReloadConfig).Level
+	if level == "trace" {
+		return fmt.Errorf("unsupported level")
+	}
+	t.reloaded = append(t.reloaded, level)
+	return nil
+}
@@ -20,15 +20,19 @@ package boot
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/apache/skywalking-rover/pkg/config"
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
 )
 
 var log = logger.GetLogger("boot", "starter")
 
-func RunModules(ctx context.Context, file string, startUpSuccessCallback func(*module.Manager)) error {
+// RunModules starts all declared modules in the config file, and blocks until the rover shutdown,
+// the config file is watched in the reload period when the period is bigger than zero
+func RunModules(ctx context.Context, file string, reloadPeriod time.Duration, startUpSuccessCallback func(*module.Manager)) error {
 	// read config files
 	conf, err := config.Load(file)
 	if err != nil {
@@ -42,8 +46,18 @@ func RunModules(ctx context.Context, file string, startUpSuccessCallback func(*m
 	}
 
 	// startup all modules
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	starter := NewModuleStarter(modules)
-	return starter.Run(ctx, startUpSuccessCallback)
+	return starter.Run(ctx, func(mgr *module.Manager) {
+		capability.BuildReport(starter.CapabilityStatuses()).Publish()
+		if reloadPeriod > 0 {
+			NewConfigWatcher(file, conf, starter.startedModules, reloadPeriod).Start(ctx)
+		}
+		if startUpSuccessCallback != nil {
+			startUpSuccessCallback(mgr)
+		}
+	})
 }
 
 func findAllDeclaredModules(conf *config.Config) ([]module.Module, error) {
@@ -65,3 +65,8 @@ func (c *Config) GetTopLevelKeys() []string {
 func (c *Config) UnMarshalWithKey(key string, val interface{}) error {
 	return c.conf.UnmarshalKey(key, val)
 }
+
+// GetSettings of the key, used to compare the config values between two configurations
+func (c *Config) GetSettings(key string) interface{} {
+	return c.conf.Get(key)
+}
@@ -17,7 +17,11 @@
 
 package core
 
-import "github.com/apache/skywalking-rover/pkg/core/backend"
+import (
+	"github.com/apache/skywalking-rover/pkg/core/backend"
+	"github.com/apache/skywalking-rover/pkg/core/otlp"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+)
 
 // Operator when the other module operate with core module
 type Operator interface {
@@ -27,4 +31,8 @@ type Operator interface {
 	ClusterName() string
 	// BackendOperator for operate with backend client
 	BackendOperator() backend.Operator
+	// OTLPOperator for exporting to the OpenTelemetry collector, nil when the OTLP is not active
+	OTLPOperator() otlp.Operator
+	// ResourceGovernor for throttling the modules by the resource usage of rover, nil when the governor is not active
+	ResourceGovernor() *selfprotect.Governor
 }
@@ -34,4 +34,10 @@ type Operator interface {
 	GetConnectionStatus() ConnectionStatus
 	// RegisterListener of connection status change
 	RegisterListener() chan<- ConnectionStatus
+	// SupportService check the gRPC service(full name) is supported by the backend
+	SupportService(service string) bool
+	// Addresses of the backend servers(host:port)
+	Addresses() []string
+	// SupportField check the field of the message(full name) could be parsed by the backend
+	SupportField(message, field string) bool
 }
@@ -0,0 +1,88 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	// register the client side health checking function
+	_ "google.golang.org/grpc/health"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const (
+	// LoadBalanceRoundRobin sending the requests and streams to all the ready backends in turn
+	LoadBalanceRoundRobin = "round_robin"
+	// LoadBalancePickFirst sending to the first ready backend, failover to the next one when it is disconnected
+	LoadBalancePickFirst = "pick_first"
+
+	multipleBackendScheme = "rover-backend"
+)
+
+// buildTarget builds the target and dial options for connecting the backend addresses(split by ","),
+// the multiple addresses are resolved statically, and balanced by the load balance policy
+func buildTarget(conf *Config) (string, []grpc.DialOption, error) {
+	addresses := splitAddresses(conf.Addr)
+	if len(addresses) == 0 {
+		return "", nil, fmt.Errorf("please provide the backend address")
+	}
+	policy := conf.LoadBalance
+	if policy == "" {
+		policy = LoadBalancePickFirst
+	}
+	if policy != LoadBalanceRoundRobin && policy != LoadBalancePickFirst {
+		return "", nil, fmt.Errorf("unknown backend load balance policy: %s", policy)
+	}
+	// keep the default connecting behavior of the single address, the policy is only used for balancing the multiple addresses
+	if len(addresses) == 1 {
+		return addresses[0], nil, nil
+	}
+	serviceConfig := fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]`, policy)
+	if conf.HealthCheck {
+		serviceConfig += `,"healthCheckConfig":{"serviceName":""}`
+	}
+	serviceConfig += "}"
+	options := []grpc.DialOption{grpc.WithDefaultServiceConfig(serviceConfig)}
+
+	builder := manual.NewBuilderWithScheme(multipleBackendScheme)
+	state := resolver.State{Addresses: make([]resolver.Address, 0, len(addresses))}
+	for _, addr := range addresses {
+		// verify the TLS certificate by the host of each address, instead of the authority of the target
+		serverName := addr
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			serverName = host
+		}
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr, ServerName: serverName})
+	}
+	builder.InitialState(state)
+	return builder.Scheme() + ":///backend", append(options, grpc.WithResolvers(builder)), nil
+}
+
+func splitAddresses(addr string) []string {
+	addresses := make([]string, 0)
+	for _, a := range strings.Split(addr, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addresses = append(addresses, a)
+		}
+	}
+	return addresses
+}
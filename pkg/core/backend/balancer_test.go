@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestBuildTarget(t *testing.T) {
+	tests := []struct {
+		addr        string
+		loadBalance string
+		target      string
+		err         bool
+	}{
+		{addr: "localhost:11800", target: "localhost:11800"},
+		{addr: " localhost:11800, ", loadBalance: LoadBalancePickFirst, target: "localhost:11800"},
+		{addr: "oap-1:11800,oap-2:11800", target: multipleBackendScheme + ":///backend"},
+		{addr: " , ", err: true},
+		{addr: "localhost:11800", loadBalance: "random", err: true},
+	}
+	for _, tt := range tests {
+		target, _, err := buildTarget(&Config{Addr: tt.addr, LoadBalance: tt.loadBalance})
+		if (err != nil) != tt.err {
+			t.Fatalf("%s: expected error: %t, actual: %v", tt.addr, tt.err, err)
+		}
+		if target != tt.target {
+			t.Fatalf("%s: expected target: %s, actual: %s", tt.addr, tt.target, target)
+		}
+	}
+}
+
+func TestFailoverToTheReadyBackend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	// the closed address is never ready, all the requests should be sent to the ready backend
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = closed.Close()
+
+	for _, policy := range []string{LoadBalanceRoundRobin, LoadBalancePickFirst} {
+		target, options, err := buildTarget(&Config{
+			Addr:        closed.Addr().String() + "," + listener.Addr().String(),
+			LoadBalance: policy,
+			HealthCheck: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := grpc.NewClient(target, append(options, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := grpc_health_v1.NewHealthClient(conn)
+		for i := 0; i < 3; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.WaitForReady(true))
+			cancel()
+			if err != nil {
+				t.Fatalf("%s: the request should be sent to the ready backend, but got: %v", policy, err)
+			}
+		}
+		_ = conn.Close()
+	}
+}
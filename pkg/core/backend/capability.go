@@ -0,0 +1,181 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+)
+
+var log = logger.GetLogger("core", "backend")
+
+// capabilityNegotiateTimeout is the timeout of each server reflection request
+const capabilityNegotiateTimeout = time.Second * 10
+
+// capabilities of the backend, detected through the gRPC server reflection when connected,
+// all services and fields are treated as supported when the backend not supports the reflection
+type capabilities struct {
+	lock     sync.RWMutex
+	detected bool
+	services map[string]bool
+	// the fields of the message, nil value means the message is not found in the backend
+	messages map[string]map[string]bool
+}
+
+func (c *Client) negotiateCapabilities() {
+	resp, err := c.reflect(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	c.capabilities.lock.Lock()
+	defer c.capabilities.lock.Unlock()
+	c.capabilities.messages = make(map[string]map[string]bool)
+	if err != nil {
+		c.capabilities.detected = false
+		if status.Code(err) == codes.Unimplemented {
+			log.Infof("the backend not supports the server reflection, treat all the capabilities as supported")
+		} else {
+			log.Warnf("negotiate the backend capabilities failure, treat all the capabilities as supported: %v", err)
+		}
+		return
+	}
+	services := make(map[string]bool)
+	names := make([]string, 0)
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services[s.GetName()] = true
+		names = append(names, s.GetName())
+	}
+	c.capabilities.detected = true
+	c.capabilities.services = services
+	log.Infof("negotiated the backend capabilities, services: %s", strings.Join(names, ","))
+}
+
+// SupportService check the gRPC service(full name) is supported by the backend
+func (c *Client) SupportService(service string) bool {
+	c.capabilities.lock.RLock()
+	defer c.capabilities.lock.RUnlock()
+	return !c.capabilities.detected || c.capabilities.services[service]
+}
+
+// SupportField check the field of the message(full name) could be parsed by the backend,
+// the older backend would drop the unknown fields
+func (c *Client) SupportField(message, field string) bool {
+	c.capabilities.lock.RLock()
+	detected := c.capabilities.detected
+	fields, queried := c.capabilities.messages[message]
+	c.capabilities.lock.RUnlock()
+	if !detected {
+		return true
+	}
+	if !queried {
+		var err error
+		if fields, err = c.queryMessageFields(message); err != nil {
+			// the message could be queried again in the next time
+			log.Warnf("query the message %s from the backend failure, treat it as supported: %v", message, err)
+			return true
+		}
+		c.capabilities.lock.Lock()
+		c.capabilities.messages[message] = fields
+		c.capabilities.lock.Unlock()
+	}
+	return fields[field]
+}
+
+func (c *Client) queryMessageFields(message string) (map[string]bool, error) {
+	resp, err := c.reflect(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: message},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return findMessageFields(resp.GetFileDescriptorResponse().GetFileDescriptorProto(), message)
+}
+
+func (c *Client) reflect(req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, capabilityNegotiateTimeout)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(c.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+	if err = stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, status.Error(codes.Code(errResp.GetErrorCode()), errResp.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+// findMessageFields find the field names of the message(full name) in the serialized file descriptors
+func findMessageFields(files [][]byte, message string) (map[string]bool, error) {
+	for _, data := range files {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("unmarshal the file descriptor failure: %v", err)
+		}
+		prefix := ""
+		if file.GetPackage() != "" {
+			prefix = file.GetPackage() + "."
+		}
+		if !strings.HasPrefix(message, prefix) {
+			continue
+		}
+		if fields := findFieldsInMessages(file.GetMessageType(), strings.TrimPrefix(message, prefix)); fields != nil {
+			return fields, nil
+		}
+	}
+	return nil, nil
+}
+
+func findFieldsInMessages(messages []*descriptorpb.DescriptorProto, name string) map[string]bool {
+	current, nested, hasNested := strings.Cut(name, ".")
+	for _, m := range messages {
+		if m.GetName() != current {
+			continue
+		}
+		if hasNested {
+			return findFieldsInMessages(m.GetNestedType(), nested)
+		}
+		fields := make(map[string]bool)
+		for _, f := range m.GetField() {
+			fields[f.GetName()] = true
+		}
+		return fields
+	}
+	return nil
+}
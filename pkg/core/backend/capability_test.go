@@ -0,0 +1,67 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestFindMessageFields(t *testing.T) {
+	file, err := proto.Marshal(&descriptorpb.FileDescriptorProto{
+		Package: proto.String("skywalking.v3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("AccessLogConnection"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("local")},
+					{Name: proto.String("attachment")},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{Name: proto.String("Nested"), Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("value")}}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal the file descriptor failure: %v", err)
+	}
+
+	tests := []struct {
+		message string
+		field   string
+		support bool
+	}{
+		{message: "skywalking.v3.AccessLogConnection", field: "attachment", support: true},
+		{message: "skywalking.v3.AccessLogConnection", field: "not_exist", support: false},
+		{message: "skywalking.v3.AccessLogConnection.Nested", field: "value", support: true},
+		{message: "skywalking.v3.NotExist", field: "local", support: false},
+		{message: "other.AccessLogConnection", field: "local", support: false},
+	}
+	for _, tt := range tests {
+		fields, err := findMessageFields([][]byte{file}, tt.message)
+		if err != nil {
+			t.Fatalf("find the message %s failure: %v", tt.message, err)
+		}
+		if fields[tt.field] != tt.support {
+			t.Fatalf("%s.%s: expected support: %t, actual: %t", tt.message, tt.field, tt.support, fields[tt.field])
+		}
+	}
+}
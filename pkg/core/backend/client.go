@@ -38,6 +38,8 @@ type Client struct {
 	listeners []chan<- ConnectionStatus
 	ctx       context.Context
 	cancel    context.CancelFunc
+
+	capabilities capabilities
 }
 
 func NewClient(config *Config) *Client {
@@ -54,8 +56,11 @@ func (c *Client) Start(parent context.Context) error {
 	}
 
 	// build connection
-	addr := c.config.Addr
-	conn, err := grpc.NewClient(addr, options...)
+	target, targetOptions, err := buildTarget(c.config)
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.NewClient(target, append(options, targetOptions...)...)
 	if err != nil {
 		return err
 	}
@@ -70,6 +75,10 @@ func (c *Client) GetConnection() grpc.ClientConnInterface {
 	return c.conn
 }
 
+func (c *Client) Addresses() []string {
+	return splitAddresses(c.config.Addr)
+}
+
 func (c *Client) Stop() error {
 	c.cancel()
 	return c.conn.Close()
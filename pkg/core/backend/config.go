@@ -18,7 +18,11 @@
 package backend
 
 type Config struct {
-	Addr string `mapstructure:"addr"` // Server address
+	Addr string `mapstructure:"addr"` // Server addresses, split by ","
+	// The load balance policy of the multiple server addresses, "round_robin" or "pick_first"
+	LoadBalance string `mapstructure:"load_balance"`
+	// Checking the health of each server through the gRPC health checking protocol
+	HealthCheck bool `mapstructure:"health_check"`
 	// TLS settings
 	EnableTLS          bool   `mapstructure:"enable_tls"`           // Enable TLS connect to server
 	ClientPemPath      string `mapstructure:"client_pem_path"`      // The file path of client.pem. The config only works when opening the TLS switch.
@@ -27,4 +31,6 @@ type Config struct {
 	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // Controls whether a client verifies the server's certificate chain and host name.
 	Authentication     string `mapstructure:"authentication"`       // The auth value when send request
 	CheckPeriod        int    `mapstructure:"check_period"`         // How frequently to check the connection(second)
+	// Negotiate the capabilities of the backend through the gRPC server reflection when connected
+	NegotiateCapabilities bool `mapstructure:"negotiate_capabilities"`
 }
@@ -24,6 +24,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/status"
+
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
 )
 
 func (c *Client) registerCheckStatus(ctx context.Context) {
@@ -73,7 +75,13 @@ func (c *Client) reportError(err error) {
 
 func (c *Client) updateStatus(s ConnectionStatus) {
 	if c.status != s {
+		if c.status == Disconnect && s == Connected {
+			selfobs.Increase(selfobs.BackendReconnect, "", "", 1)
+		}
 		c.status = s
+		if s == Connected && c.config.NegotiateCapabilities {
+			go c.negotiateCapabilities()
+		}
 		for _, lis := range c.listeners {
 			lis <- s
 		}
@@ -0,0 +1,51 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+// the drift duration is large enough to print the log
+const clockDriftLogThreshold = time.Millisecond * 10
+
+// startClockCalibration periodically recalibrate the boot time for converting the BPF time to wall clock
+func startClockCalibration(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				drift, err := host.CalibrateBootTime()
+				if err != nil {
+					log.Warnf("calibrate the boot time failure: %v", err)
+					continue
+				}
+				if drift > clockDriftLogThreshold || drift < -clockDriftLogThreshold {
+					log.Infof("the clock has been drifted %s, the boot time is calibrated", drift)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -19,6 +19,7 @@ package core
 
 import (
 	"github.com/apache/skywalking-rover/pkg/core/backend"
+	"github.com/apache/skywalking-rover/pkg/core/otlp"
 	"github.com/apache/skywalking-rover/pkg/module"
 )
 
@@ -27,8 +28,57 @@ type Config struct {
 	module.Config `mapstructure:",squash"`
 
 	ClusterName string `mapstructure:"cluster_name"`
+	// the period of recalibrate the clock for converting the BPF time
+	ClockCalibratePeriod string `mapstructure:"clock_calibrate_period"`
+	// the period of reporting the internal health counters of rover to the backend
+	SelfReportPeriod string `mapstructure:"self_report_period"`
+	// the node-local endpoint exposing the internal health of rover in the prometheus format
+	SelfMetrics *SelfMetricsConfig `mapstructure:"self_metrics"`
+	// the max count of ELF and kernel symbols cached in the node, shared by all modules
+	SymbolCacheSize int `mapstructure:"symbol_cache_size"`
+	// locating the BTF when the kernel not exposes the BTF
+	BTFHub *BTFHubConfig `mapstructure:"btf_hub"`
+	// throttling the modules when the resource usage of rover approaches the limits
+	ResourceGovernor *ResourceGovernorConfig `mapstructure:"resource_governor"`
 	// backend connection
 	BackendConfig *backend.Config `mapstructure:"backend"`
+	// the OpenTelemetry collector connection
+	OTLPConfig *otlp.Config `mapstructure:"otlp"`
+}
+
+type SelfMetricsConfig struct {
+	// is the endpoint active
+	Active bool `mapstructure:"active"`
+	// the listening port of the endpoint
+	Port int `mapstructure:"port"`
+	// the http path of the endpoint
+	Path string `mapstructure:"path"`
+}
+
+type BTFHubConfig struct {
+	// the BTF file or the directory in the btfhub layout
+	Path string `mapstructure:"path"`
+	// the URL template for downloading the BTF
+	DownloadURL string `mapstructure:"download_url"`
+	// the directory saving the downloaded BTF
+	CacheDir string `mapstructure:"cache_dir"`
+	// the timeout of each downloading
+	DownloadTimeout string `mapstructure:"download_timeout"`
+}
+
+type ResourceGovernorConfig struct {
+	// is the governor active
+	Active bool `mapstructure:"active"`
+	// the period of checking the resource usage
+	CheckPeriod string `mapstructure:"check_period"`
+	// the CPU limit in cores
+	CPULimit string `mapstructure:"cpu_limit"`
+	// the resident memory limit
+	MemoryLimit string `mapstructure:"memory_limit"`
+	// the percentage of the limits to escalate the throttling
+	HighWatermark int `mapstructure:"high_watermark"`
+	// the percentage of the limits to recover the throttling
+	LowWatermark int `mapstructure:"low_watermark"`
 }
 
 func (c *Config) IsActive() bool {
@@ -19,23 +19,35 @@ package core
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/apache/skywalking-rover/pkg/core/backend"
+	"github.com/apache/skywalking-rover/pkg/core/otlp"
+	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/btf"
+	"github.com/apache/skywalking-rover/pkg/tools/elf"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
 )
 
 const ModuleName = "core"
 
+var log = logger.GetLogger("core")
+
 type Module struct {
 	config *Config
 
 	instanceID    string
 	clusterName   string
 	backendClient *backend.Client
+	otlpClient    *otlp.Client
+	metricsServer *selfMetricsServer
+	governor      *selfprotect.Governor
 }
 
 func NewModule() *Module {
@@ -58,16 +70,61 @@ func (m *Module) ClusterName() string {
 	return m.clusterName
 }
 
-func (m *Module) Start(ctx context.Context, _ *module.Manager) error {
+func (m *Module) Start(ctx context.Context, mgr *module.Manager) error {
 	// generate instance id
 	m.instanceID = uuid.New().String()
 	m.clusterName = m.config.ClusterName
+	if m.config.ClockCalibratePeriod != "" {
+		period, err := time.ParseDuration(m.config.ClockCalibratePeriod)
+		if err != nil {
+			return fmt.Errorf("parse the clock calibrate period failure: %v", err)
+		}
+		startClockCalibration(ctx, period)
+	}
+	elf.SetSymbolCacheSize(m.config.SymbolCacheSize)
+	if hub := m.config.BTFHub; hub != nil {
+		hubConfig := &btf.HubConfig{Path: hub.Path, DownloadURL: hub.DownloadURL, CacheDir: hub.CacheDir}
+		if hub.DownloadTimeout != "" {
+			timeout, err := time.ParseDuration(hub.DownloadTimeout)
+			if err != nil {
+				return fmt.Errorf("parse the BTF download timeout failure: %v", err)
+			}
+			hubConfig.DownloadTimeout = timeout
+		}
+		btf.SetHubConfig(hubConfig)
+	}
+	if m.config.ResourceGovernor != nil && m.config.ResourceGovernor.Active {
+		governor, period, err := newResourceGovernor(m.config.ResourceGovernor)
+		if err != nil {
+			return err
+		}
+		governor.Guard().Start(ctx, period)
+		m.governor = governor
+	}
+	if m.config.SelfMetrics != nil && m.config.SelfMetrics.Active {
+		m.metricsServer = newSelfMetricsServer(m.config.SelfMetrics)
+		m.metricsServer.Start(mgr)
+	}
 	// backend client
 	if m.config.BackendConfig != nil {
 		m.backendClient = backend.NewClient(m.config.BackendConfig)
 		if err := m.backendClient.Start(ctx); err != nil {
 			return err
 		}
+		if m.config.SelfReportPeriod != "" {
+			period, err := time.ParseDuration(m.config.SelfReportPeriod)
+			if err != nil {
+				return fmt.Errorf("parse the self report period failure: %v", err)
+			}
+			startSelfObservabilityReport(ctx, period, m.backendClient, m.instanceID)
+		}
+	}
+	// OpenTelemetry collector client
+	if m.config.OTLPConfig != nil && m.config.OTLPConfig.Active {
+		m.otlpClient = otlp.NewClient(m.config.OTLPConfig)
+		if err := m.otlpClient.Start(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -75,11 +132,17 @@ func (m *Module) Start(ctx context.Context, _ *module.Manager) error {
 func (m *Module) NotifyStartSuccess() {
 }
 
-func (m *Module) Shutdown(context.Context, *module.Manager) error {
+func (m *Module) Shutdown(ctx context.Context, _ *module.Manager) error {
 	var result *multierror.Error
+	if m.metricsServer != nil {
+		result = multierror.Append(result, m.metricsServer.Shutdown(ctx))
+	}
 	if m.backendClient != nil {
 		result = multierror.Append(result, m.backendClient.Stop())
 	}
+	if m.otlpClient != nil {
+		result = multierror.Append(result, m.otlpClient.Stop())
+	}
 	return result.ErrorOrNil()
 }
 
@@ -90,6 +153,17 @@ func (m *Module) BackendOperator() backend.Operator {
 	return m.backendClient
 }
 
+func (m *Module) OTLPOperator() otlp.Operator {
+	if m.otlpClient == nil {
+		return nil
+	}
+	return m.otlpClient
+}
+
+func (m *Module) ResourceGovernor() *selfprotect.Governor {
+	return m.governor
+}
+
 func (m *Module) InstanceID() string {
 	return m.instanceID
 }
@@ -0,0 +1,172 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	collectorlogsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	collectorprofilesv1 "go.opentelemetry.io/proto/otlp/collector/profiles/v1development"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	profilesv1 "go.opentelemetry.io/proto/otlp/profiles/v1development"
+)
+
+const defaultTimeout = time.Second * 10
+
+type Client struct {
+	config  *Config
+	conn    *grpc.ClientConn
+	headers metadata.MD
+	timeout time.Duration
+
+	logsClient     collectorlogsv1.LogsServiceClient
+	profilesClient collectorprofilesv1.ProfilesServiceClient
+}
+
+func NewClient(config *Config) *Client {
+	return &Client{config: config}
+}
+
+// Start the client and connect to the collector
+func (c *Client) Start() error {
+	if c.config.Addr == "" {
+		return fmt.Errorf("please provide the address of the OTLP collector")
+	}
+	c.timeout = defaultTimeout
+	if c.config.Timeout != "" {
+		timeout, err := time.ParseDuration(c.config.Timeout)
+		if err != nil {
+			return fmt.Errorf("parse the OTLP export timeout failure: %v", err)
+		}
+		c.timeout = timeout
+	}
+	headers, err := parseHeaders(c.config.Headers)
+	if err != nil {
+		return err
+	}
+	c.headers = headers
+
+	credential := insecure.NewCredentials()
+	if c.config.EnableTLS {
+		tlsConfig, err := buildTLSConfig(c.config)
+		if err != nil {
+			return err
+		}
+		credential = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(c.config.Addr, grpc.WithTransportCredentials(credential))
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	c.logsClient = collectorlogsv1.NewLogsServiceClient(conn)
+	c.profilesClient = collectorprofilesv1.NewProfilesServiceClient(conn)
+	return nil
+}
+
+func (c *Client) ExportLogs(ctx context.Context, logs []*logsv1.ResourceLogs) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	ctx, cancel := c.exportContext(ctx)
+	defer cancel()
+	resp, err := c.logsClient.Export(ctx, &collectorlogsv1.ExportLogsServiceRequest{ResourceLogs: logs})
+	if err != nil {
+		return err
+	}
+	if rejected := resp.GetPartialSuccess().GetRejectedLogRecords(); rejected > 0 {
+		return fmt.Errorf("the collector rejected %d log records: %s", rejected, resp.GetPartialSuccess().GetErrorMessage())
+	}
+	return nil
+}
+
+func (c *Client) ExportProfiles(ctx context.Context, profiles []*profilesv1.ResourceProfiles) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	ctx, cancel := c.exportContext(ctx)
+	defer cancel()
+	resp, err := c.profilesClient.Export(ctx, &collectorprofilesv1.ExportProfilesServiceRequest{ResourceProfiles: profiles})
+	if err != nil {
+		return err
+	}
+	if rejected := resp.GetPartialSuccess().GetRejectedProfiles(); rejected > 0 {
+		return fmt.Errorf("the collector rejected %d profiles: %s", rejected, resp.GetPartialSuccess().GetErrorMessage())
+	}
+	return nil
+}
+
+func (c *Client) exportContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if len(c.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, c.headers)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func (c *Client) Stop() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+func parseHeaders(headers string) (metadata.MD, error) {
+	result := metadata.MD{}
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		kv := strings.SplitN(header, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("the OTLP header should be key=value: %s", header)
+		}
+		result.Append(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+	}
+	return result, nil
+}
+
+func buildTLSConfig(conf *Config) (*tls.Config, error) {
+	tlsConfig := new(tls.Config)
+	tlsConfig.Renegotiation = tls.RenegotiateNever
+	tlsConfig.InsecureSkipVerify = conf.InsecureSkipVerify
+	if conf.CaPemPath == "" {
+		return tlsConfig, nil
+	}
+	ca, err := os.ReadFile(conf.CaPemPath)
+	if err != nil {
+		return nil, fmt.Errorf("read the OTLP CA file failure: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("failed to append the OTLP CA certificates")
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
@@ -0,0 +1,29 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+type Config struct {
+	Active bool   `mapstructure:"active"`
+	Addr   string `mapstructure:"addr"` // The gRPC address of the OpenTelemetry collector
+	// TLS settings
+	EnableTLS          bool   `mapstructure:"enable_tls"`           // Enable TLS connect to the collector
+	CaPemPath          string `mapstructure:"ca_pem_path"`          // The file path of ca.pem. The config only works when opening the TLS switch.
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // Controls whether a client verifies the server's certificate chain and host name.
+	Headers            string `mapstructure:"headers"`              // The headers(key=value, split by ",") when send request
+	Timeout            string `mapstructure:"timeout"`              // The timeout of each export request
+}
@@ -0,0 +1,124 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+)
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// selfMetricsServer exposes the internal health of rover as the prometheus metrics
+type selfMetricsServer struct {
+	config *SelfMetricsConfig
+
+	mutex    sync.Mutex
+	server   *http.Server
+	shutdown bool
+}
+
+func newSelfMetricsServer(config *SelfMetricsConfig) *selfMetricsServer {
+	return &selfMetricsServer{config: config}
+}
+
+func (s *selfMetricsServer) Start(mgr *module.Manager) {
+	path := s.config.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(buildPrometheusMetrics())
+	})
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.config.Port),
+		ReadHeaderTimeout: 3 * time.Second,
+		Handler:           mux,
+	}
+	go func() {
+		err := s.server.ListenAndServe()
+		s.mutex.Lock()
+		shutdown := s.shutdown
+		s.mutex.Unlock()
+		if err != nil && !shutdown {
+			mgr.ShutdownModules(fmt.Errorf("self metrics server failure: %v", err))
+		}
+	}()
+	log.Infof("self metrics server is listening on :%d%s", s.config.Port, path)
+}
+
+func (s *selfMetricsServer) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.shutdown = true
+	server := s.server
+	s.mutex.Unlock()
+	if server != nil {
+		return server.Shutdown(ctx)
+	}
+	return nil
+}
+
+// buildPrometheusMetrics renders the counters, gauges and summaries in the prometheus text exposition format
+func buildPrometheusMetrics() []byte {
+	buf := &bytes.Buffer{}
+	var lastName string
+	writeType := func(name, metricType string) {
+		if name != lastName {
+			fmt.Fprintf(buf, "# TYPE %s %s\n", name, metricType)
+			lastName = name
+		}
+	}
+	for _, s := range selfobs.Samples() {
+		writeType(s.Name, "counter")
+		writePrometheusLine(buf, s.Name, s.LabelName, s.LabelValue, strconv.FormatInt(s.Value, 10))
+	}
+	for _, g := range selfobs.Gauges() {
+		writeType(g.Name, "gauge")
+		writePrometheusLine(buf, g.Name, g.LabelName, g.LabelValue, strconv.FormatFloat(g.Value, 'g', -1, 64))
+	}
+	for _, s := range selfobs.Summaries() {
+		writeType(s.Name, "summary")
+		writePrometheusLine(buf, s.Name+"_sum", s.LabelName, s.LabelValue, strconv.FormatFloat(s.Sum, 'g', -1, 64))
+		writePrometheusLine(buf, s.Name+"_count", s.LabelName, s.LabelValue, strconv.FormatInt(s.Count, 10))
+	}
+	return buf.Bytes()
+}
+
+func writePrometheusLine(buf *bytes.Buffer, name, labelName, labelValue, value string) {
+	buf.WriteString(name)
+	if labelName != "" {
+		fmt.Fprintf(buf, "{%s=\"%s\"}", labelName, prometheusLabelEscaper.Replace(labelValue))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,70 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/core/backend"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+// startSelfObservabilityReport periodically report the internal health counters of rover as the meters of the rover service
+func startSelfObservabilityReport(ctx context.Context, period time.Duration, client *backend.Client, instanceID string) {
+	sender := reporter.NewMeterSender("self observability meters", client.GetConnection())
+	reporter.StartPeriodic(ctx, "self observability meters", period, func(ctx context.Context) error {
+		if client.GetConnectionStatus() != backend.Connected {
+			return nil
+		}
+		return reportSelfObservability(ctx, sender, instanceID)
+	})
+}
+
+func reportSelfObservability(ctx context.Context, sender *reporter.MeterSender, instanceID string) error {
+	var data []*v3.MeterData
+	for _, s := range selfobs.Samples() {
+		data = append(data, buildSelfObservabilityMeter(s.Name, s.LabelName, s.LabelValue, float64(s.Value)))
+	}
+	for _, g := range selfobs.Gauges() {
+		data = append(data, buildSelfObservabilityMeter(g.Name, g.LabelName, g.LabelValue, g.Value))
+	}
+	for _, s := range selfobs.Summaries() {
+		data = append(data, buildSelfObservabilityMeter(s.Name+"_sum", s.LabelName, s.LabelValue, s.Sum),
+			buildSelfObservabilityMeter(s.Name+"_count", s.LabelName, s.LabelValue, float64(s.Count)))
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	data[0].Service = selfobs.ServiceName
+	data[0].ServiceInstance = instanceID
+	data[0].Timestamp = time.Now().UnixMilli()
+
+	return sender.Send(ctx, []*v3.MeterDataCollection{{MeterData: data}})
+}
+
+func buildSelfObservabilityMeter(name, labelName, labelValue string, value float64) *v3.MeterData {
+	var labels []*v3.Label
+	if labelName != "" {
+		labels = append(labels, &v3.Label{Name: labelName, Value: labelValue})
+	}
+	return reporter.SingleValueMeter(name, labels, value)
+}
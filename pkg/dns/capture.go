@@ -0,0 +1,180 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/net/bpf"
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ipv4HeaderMinLen = 20
+	ipv6HeaderLen    = 40
+	udpHeaderLen     = 8
+	udpProtocol      = 17
+
+	captureBufferSize  = 65536
+	captureReadTimeout = time.Second
+)
+
+// packet is the DNS message header decoded from a captured UDP packet
+type packet struct {
+	SrcIP    string
+	SrcPort  uint16
+	DstIP    string
+	DstPort  uint16
+	ID       uint16
+	Response bool
+	RCode    dnsmessage.RCode
+	// Question is the first question name and type of the message, for correlating the proxied queries
+	Question string
+}
+
+// capture reads the DNS packets from all interfaces of the current network namespace
+type capture struct {
+	fd  int
+	buf []byte
+}
+
+func newCapture(port int) (*capture, error) {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, int(htons(unix.ETH_P_ALL)))
+	if err != nil {
+		return nil, fmt.Errorf("open packet socket failure: %v", err)
+	}
+	if err := attachPortFilter(fd, uint32(port)); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+	timeout := unix.NsecToTimeval(captureReadTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("setting the read timeout failure: %v", err)
+	}
+	return &capture{fd: fd, buf: make([]byte, captureBufferSize)}, nil
+}
+
+// read the next DNS packet, return nil when there have no packet in the read timeout or the packet cannot be decoded
+func (c *capture) read() (*packet, error) {
+	n, _, err := unix.Recvfrom(c.fd, c.buf, 0)
+	if err != nil {
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodePacket(c.buf[:n]), nil
+}
+
+func (c *capture) Close() error {
+	return unix.Close(c.fd)
+}
+
+// attachPortFilter only accept the non-fragmented UDP packets which source or destination port is matched
+// the packet socket is SOCK_DGRAM, so the data is started with the network header
+func attachPortFilter(fd int, port uint32) error {
+	instructions := []bpf.Instruction{
+		bpf.LoadAbsolute{Off: 0, Size: 1},
+		bpf.ALUOpConstant{Op: bpf.ALUOpShiftRight, Val: 4},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 4, SkipFalse: 9},
+		// IPv4
+		bpf.LoadAbsolute{Off: 9, Size: 1},
+		bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: udpProtocol, SkipTrue: 15},
+		bpf.LoadAbsolute{Off: 6, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpBitsSet, Val: 0x1fff, SkipTrue: 13},
+		bpf.LoadMemShift{Off: 0},
+		bpf.LoadIndirect{Off: 0, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: port, SkipTrue: 9},
+		bpf.LoadIndirect{Off: 2, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: port, SkipTrue: 7, SkipFalse: 8},
+		// IPv6
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 6, SkipFalse: 7},
+		bpf.LoadAbsolute{Off: 6, Size: 1},
+		bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: udpProtocol, SkipTrue: 5},
+		bpf.LoadAbsolute{Off: ipv6HeaderLen, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: port, SkipTrue: 2},
+		bpf.LoadAbsolute{Off: ipv6HeaderLen + 2, Size: 2},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: port, SkipFalse: 1},
+		bpf.RetConstant{Val: captureBufferSize},
+		bpf.RetConstant{Val: 0},
+	}
+	raw, err := bpf.Assemble(instructions)
+	if err != nil {
+		return fmt.Errorf("assemble the packet filter failure: %v", err)
+	}
+	filters := make([]unix.SockFilter, 0, len(raw))
+	for _, r := range raw {
+		filters = append(filters, unix.SockFilter{Code: r.Op, Jt: r.Jt, Jf: r.Jf, K: r.K})
+	}
+	program := &unix.SockFprog{Len: uint16(len(filters)), Filter: &filters[0]}
+	if err := unix.SetsockoptSockFprog(fd, unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, program); err != nil {
+		return fmt.Errorf("attach the packet filter failure: %v", err)
+	}
+	return nil
+}
+
+// decodePacket from the network header, return nil if the data is not a valid DNS message
+func decodePacket(data []byte) *packet {
+	if len(data) == 0 {
+		return nil
+	}
+	var srcIP, dstIP net.IP
+	var payload []byte
+	switch data[0] >> 4 {
+	case 4:
+		headerLen := int(data[0]&0x0f) * 4
+		if headerLen < ipv4HeaderMinLen || len(data) < headerLen+udpHeaderLen || data[9] != udpProtocol {
+			return nil
+		}
+		srcIP, dstIP = net.IP(data[12:16]), net.IP(data[16:20])
+		payload = data[headerLen:]
+	case 6:
+		if len(data) < ipv6HeaderLen+udpHeaderLen || data[6] != udpProtocol {
+			return nil
+		}
+		srcIP, dstIP = net.IP(data[8:24]), net.IP(data[24:40])
+		payload = data[ipv6HeaderLen:]
+	default:
+		return nil
+	}
+
+	message, err := ParseMessage(payload[udpHeaderLen:], false)
+	if err != nil {
+		return nil
+	}
+	return &packet{
+		SrcIP:    srcIP.String(),
+		SrcPort:  binary.BigEndian.Uint16(payload[0:2]),
+		DstIP:    dstIP.String(),
+		DstPort:  binary.BigEndian.Uint16(payload[2:4]),
+		ID:       message.ID,
+		Response: message.Response,
+		RCode:    message.RCode,
+		Question: message.Question(),
+	}
+}
+
+func htons(v uint16) uint16 {
+	return (v << 8) | (v >> 8)
+}
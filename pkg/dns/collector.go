@@ -0,0 +1,313 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+var log = logger.GetLogger("dns")
+
+// queryKey identify a DNS query through the client address and the message ID
+type queryKey struct {
+	ClientIP   string
+	ClientPort uint16
+	ID         uint16
+}
+
+type pendingQuery struct {
+	Key       resolverKey
+	StartTime time.Time
+	// Question of the query sent by the pod, for correlating the upstream query forwarded by the DNS proxy
+	Question string
+}
+
+// Collector aggregates the captured DNS queries and responses by the pod and resolver
+type Collector struct {
+	processOperator process.Operator
+	sender          *reporter.MeterSender
+	port            int
+	queryTimeout    time.Duration
+	reportPeriod    time.Duration
+	meterPrefix     string
+	// the DNS proxy port of the ztunnel, zero means the ztunnel DNS proxy is not captured
+	zTunnelPort int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex sync.Mutex
+	pods  map[string]*api.ProcessEntity
+	// the addresses of the ztunnel, the queries from these addresses are forwarded on behalf of the pods
+	zTunnelAddresses map[string]bool
+	pending          map[queryKey]*pendingQuery
+	metrics          map[resolverKey]*resolverMetrics
+}
+
+func NewCollector(mgr *module.Manager, config *Config) (*Collector, error) {
+	queryTimeout, err := time.ParseDuration(config.QueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("parsing query timeout failure: %v", err)
+	}
+	reportPeriod, err := time.ParseDuration(config.ReportPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parsing report period failure: %v", err)
+	}
+	if config.MeterPrefix == "" {
+		return nil, fmt.Errorf("please provide the meter prefix")
+	}
+	var zTunnelPort int
+	if config.ZTunnel.Active {
+		if config.ZTunnel.Port <= 0 {
+			return nil, fmt.Errorf("please provide the ztunnel DNS proxy port")
+		}
+		zTunnelPort = config.ZTunnel.Port
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	return &Collector{
+		processOperator:  mgr.FindModule(process.ModuleName).(process.Operator),
+		sender:           reporter.NewMeterSender("DNS metrics", coreOperator.BackendOperator().GetConnection()),
+		port:             config.Port,
+		queryTimeout:     queryTimeout,
+		reportPeriod:     reportPeriod,
+		meterPrefix:      config.MeterPrefix + "_",
+		zTunnelPort:      zTunnelPort,
+		pods:             make(map[string]*api.ProcessEntity),
+		zTunnelAddresses: make(map[string]bool),
+		pending:          make(map[queryKey]*pendingQuery),
+		metrics:          make(map[resolverKey]*resolverMetrics),
+	}, nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	ports := []int{c.port}
+	if c.zTunnelPort > 0 {
+		ports = append(ports, c.zTunnelPort)
+	}
+	captures := make([]*capture, 0, len(ports))
+	for _, port := range ports {
+		capture, err := newCapture(port)
+		if err != nil {
+			for _, opened := range captures {
+				_ = opened.Close()
+			}
+			return err
+		}
+		captures = append(captures, capture)
+	}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.refreshPods()
+
+	for _, capture := range captures {
+		go c.readPackets(capture)
+	}
+	reporter.StartPeriodic(c.ctx, "DNS metrics", c.reportPeriod, c.flush)
+	return nil
+}
+
+func (c *Collector) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return nil
+}
+
+func (c *Collector) readPackets(capture *capture) {
+	defer func() {
+		if err := capture.Close(); err != nil {
+			log.Warnf("close the DNS packet capture failure: %v", err)
+		}
+	}()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+		p, err := capture.read()
+		if err != nil {
+			log.Errorf("read DNS packet failure, stop the DNS monitoring: %v", err)
+			return
+		}
+		if p != nil {
+			c.handlePacket(p, time.Now())
+		}
+	}
+}
+
+// handlePacket correlates the query and response, the same packet could be captured multiple times
+// on different interfaces(such as the veth pair and the bridge), so only the first query and response are counted
+func (c *Collector) handlePacket(p *packet, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !p.Response {
+		c.handleQuery(p, now)
+		return
+	}
+
+	key := queryKey{ClientIP: p.DstIP, ClientPort: p.DstPort, ID: p.ID}
+	query := c.pending[key]
+	if query == nil {
+		return
+	}
+	delete(c.pending, key)
+	c.resolverMetrics(query.Key).RecordResponse(p.RCode, float64(now.Sub(query.StartTime))/float64(time.Millisecond))
+}
+
+func (c *Collector) handleQuery(p *packet, now time.Time) {
+	key := queryKey{ClientIP: p.SrcIP, ClientPort: p.SrcPort, ID: p.ID}
+	if _, exist := c.pending[key]; exist {
+		return
+	}
+	if c.zTunnelAddresses[p.SrcIP] {
+		// the upstream query forwarded by the ztunnel DNS proxy, attribute it to the pod which sent the same question
+		origin := c.findProxiedQuery(p.Question)
+		if origin == nil {
+			return
+		}
+		resolver := resolverKey{ServiceName: origin.Key.ServiceName, InstanceName: origin.Key.InstanceName,
+			Resolver: p.DstIP, Proxy: zTunnelProxyName}
+		c.pending[key] = &pendingQuery{Key: resolver, StartTime: now}
+		c.resolverMetrics(resolver).Queries++
+		return
+	}
+	pod := c.pods[p.SrcIP]
+	if pod == nil {
+		return
+	}
+	resolver := resolverKey{ServiceName: pod.ServiceName, InstanceName: pod.InstanceName, Resolver: p.DstIP}
+	c.pending[key] = &pendingQuery{Key: resolver, StartTime: now, Question: p.Question}
+	c.resolverMetrics(resolver).Queries++
+}
+
+// findProxiedQuery finds the latest pending query sent by the pod which have the same question
+func (c *Collector) findProxiedQuery(question string) *pendingQuery {
+	if question == "" {
+		return nil
+	}
+	var result *pendingQuery
+	for _, query := range c.pending {
+		if query.Question != question {
+			continue
+		}
+		if result == nil || query.StartTime.After(result.StartTime) {
+			result = query
+		}
+	}
+	return result
+}
+
+func (c *Collector) resolverMetrics(key resolverKey) *resolverMetrics {
+	m := c.metrics[key]
+	if m == nil {
+		m = newResolverMetrics()
+		c.metrics[key] = m
+	}
+	return m
+}
+
+// refreshPods builds the pod IP to the entity mapping, the IP shared by multiple pods(host network) are ignored
+func (c *Collector) refreshPods() {
+	pods := make(map[string]*api.ProcessEntity)
+	shared := make(map[string]bool)
+	for _, p := range c.processOperator.FindAllRegisteredProcesses() {
+		if p.DetectType() != api.Kubernetes {
+			continue
+		}
+		entity := p.Entity()
+		for _, host := range p.ExposeHosts() {
+			if exist := pods[host]; exist != nil && exist.InstanceName != entity.InstanceName {
+				shared[host] = true
+				continue
+			}
+			pods[host] = entity
+		}
+	}
+	for host := range shared {
+		delete(pods, host)
+	}
+	zTunnelAddresses := make(map[string]bool)
+	if c.zTunnelPort > 0 {
+		zTunnelAddresses = findZTunnelAddresses()
+		for host := range zTunnelAddresses {
+			delete(pods, host)
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pods = pods
+	c.zTunnelAddresses = zTunnelAddresses
+}
+
+func (c *Collector) flush(ctx context.Context) error {
+	now := time.Now()
+	c.mutex.Lock()
+	for key, query := range c.pending {
+		if now.Sub(query.StartTime) >= c.queryTimeout {
+			c.resolverMetrics(query.Key).Timeouts++
+			delete(c.pending, key)
+		}
+	}
+	metrics := c.metrics
+	c.metrics = make(map[resolverKey]*resolverMetrics)
+	c.mutex.Unlock()
+
+	c.refreshPods()
+	return c.sender.Send(ctx, c.buildMeters(metrics, now))
+}
+
+func (c *Collector) buildMeters(metrics map[resolverKey]*resolverMetrics, now time.Time) []*v3.MeterDataCollection {
+	type instance struct {
+		ServiceName  string
+		InstanceName string
+	}
+	meters := make(map[instance][]*v3.MeterData)
+	for key, m := range metrics {
+		labels := []*v3.Label{{Name: "resolver", Value: key.Resolver}}
+		if key.Proxy != "" {
+			labels = append(labels, &v3.Label{Name: "proxy", Value: key.Proxy})
+		}
+		ins := instance{ServiceName: key.ServiceName, InstanceName: key.InstanceName}
+		meters[ins] = m.AppendMeters(meters[ins], c.meterPrefix, labels)
+	}
+
+	result := make([]*v3.MeterDataCollection, 0, len(meters))
+	for ins, data := range meters {
+		if len(data) == 0 {
+			continue
+		}
+		data[0].Service = ins.ServiceName
+		data[0].ServiceInstance = ins.InstanceName
+		data[0].Timestamp = now.UnixMilli()
+		result = append(result, &v3.MeterDataCollection{MeterData: data})
+	}
+	return result
+}
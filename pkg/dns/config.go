@@ -0,0 +1,46 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import "github.com/apache/skywalking-rover/pkg/module"
+
+type Config struct {
+	module.Config `mapstructure:",squash"`
+
+	// Port is the DNS server port to capture
+	Port int `mapstructure:"port"`
+	// QueryTimeout is the max duration to wait the response of a query
+	QueryTimeout string `mapstructure:"query_timeout"`
+	// ReportPeriod is the period of sending the aggregated metrics to the backend
+	ReportPeriod string `mapstructure:"report_period"`
+	// MeterPrefix is the prefix of all DNS meter names
+	MeterPrefix string `mapstructure:"meter_prefix"`
+	// ZTunnel is the DNS proxy of the ztunnel in the Ambient Istio
+	ZTunnel ZTunnelConfig `mapstructure:"ztunnel"`
+}
+
+type ZTunnelConfig struct {
+	// Active the attribution of the DNS queries proxied by the ztunnel
+	Active bool `mapstructure:"active"`
+	// Port is the DNS proxy port of the ztunnel
+	Port int `mapstructure:"port"`
+}
+
+func (c *Config) IsActive() bool {
+	return c.Active
+}
@@ -0,0 +1,208 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusTimeout is the status of the query which retried without the response
+	StatusTimeout = "TIMEOUT"
+	// the max count of the connect attempts cached for each process
+	maxAttemptsPerProcess = 128
+	// the max count of the connect attempts correlated with one lookup
+	maxCorrelatedAttempts = 10
+)
+
+// Lookup is the finished query of the DNS protocol, which used to correlate with the following connections of the process
+type Lookup struct {
+	ConnectionID uint64
+	RandomID     uint64
+	// the resolver address, filled when the connection is found
+	Resolver string
+	Name     string
+	Type     string
+	// the response code, or "TIMEOUT" when the query is retried without the response
+	RCode string
+	// the BPF time of the query start and response end
+	StartTime uint64
+	EndTime   uint64
+	// the addresses in the A and AAAA answers
+	Answers []string
+
+	// the time of the lookup been created, for expiring the lookup which connection is not found
+	CreateTime time.Time
+}
+
+// Duration of the lookup
+func (l *Lookup) Duration() time.Duration {
+	if l.EndTime < l.StartTime {
+		return 0
+	}
+	return time.Duration(l.EndTime - l.StartTime)
+}
+
+// ConnectAttempt is the connect operation of the process, which may be affected by the previous lookup
+type ConnectAttempt struct {
+	ConnectionID uint64
+	RandomID     uint64
+	PID          uint32
+	RemoteIP     string
+	RemotePort   uint16
+	// the BPF time of the connect operation
+	StartTime uint64
+	EndTime   uint64
+	// the reason of the connect failure, empty means success
+	Failure string
+
+	CreateTime time.Time
+}
+
+// Correlation is the slow or failed lookup and the connect attempts of the same process after it
+type Correlation struct {
+	PID      uint32
+	Lookup   *Lookup
+	Attempts []*ConnectAttempt
+}
+
+// Correlator correlate the slow or failed lookups with the following connect attempts of the same process,
+// the attempt is correlated when it's started in the window after the lookup finished, and connecting to the
+// answered address(if the lookup have the answers)
+type Correlator struct {
+	slowThreshold time.Duration
+	window        time.Duration
+
+	mutex    sync.Mutex
+	lookups  []*Correlation
+	attempts map[uint32][]*ConnectAttempt
+}
+
+func NewCorrelator(slowThreshold, window time.Duration) *Correlator {
+	return &Correlator{
+		slowThreshold: slowThreshold,
+		window:        window,
+		attempts:      make(map[uint32][]*ConnectAttempt),
+	}
+}
+
+// IsSlow check the lookup duration reached the slow threshold
+func (c *Correlator) IsSlow(lookup *Lookup) bool {
+	return lookup.Duration() >= c.slowThreshold
+}
+
+// IsAbnormal check the lookup is slow or failed
+func (c *Correlator) IsAbnormal(lookup *Lookup) bool {
+	return lookup.RCode != "NOERROR" || c.IsSlow(lookup)
+}
+
+// AddLookup record the lookup of the process, the normal lookup is ignored
+func (c *Correlator) AddLookup(pid uint32, lookup *Lookup) {
+	if !c.IsAbnormal(lookup) {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lookups = append(c.lookups, &Correlation{PID: pid, Lookup: lookup})
+}
+
+// AddAttempt record the connect attempt, the attempts are cached in the window even no lookup correlated,
+// because the lookup is decoded from the protocol data which later than the connect event
+func (c *Correlator) AddAttempt(attempt *ConnectAttempt) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	attempts := append(c.attempts[attempt.PID], attempt)
+	if len(attempts) > maxAttemptsPerProcess {
+		attempts = attempts[len(attempts)-maxAttemptsPerProcess:]
+	}
+	c.attempts[attempt.PID] = attempts
+}
+
+// MarkFailure update the result of the connect attempt, the attempt is added when not found
+func (c *Correlator) MarkFailure(failed *ConnectAttempt) {
+	c.mutex.Lock()
+	for _, attempt := range c.attempts[failed.PID] {
+		if failed.RandomID != 0 && attempt.ConnectionID == failed.ConnectionID && attempt.RandomID == failed.RandomID {
+			attempt.Failure = failed.Failure
+			c.mutex.Unlock()
+			return
+		}
+	}
+	c.mutex.Unlock()
+	c.AddAttempt(failed)
+}
+
+// Expire return the correlations which window is passed, and clean the expired attempts
+func (c *Correlator) Expire(now time.Time) []*Correlation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var result []*Correlation
+	pending := c.lookups[:0]
+	for _, correlation := range c.lookups {
+		// the lookup is created after it finished, so the attempts in the window are all received
+		if now.Sub(correlation.Lookup.CreateTime) < c.window {
+			pending = append(pending, correlation)
+			continue
+		}
+		correlation.Attempts = c.matchAttempts(correlation.PID, correlation.Lookup)
+		result = append(result, correlation)
+	}
+	c.lookups = pending
+
+	for pid, attempts := range c.attempts {
+		kept := attempts[:0]
+		for _, attempt := range attempts {
+			if now.Sub(attempt.CreateTime) < c.window*2 {
+				kept = append(kept, attempt)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.attempts, pid)
+		} else {
+			c.attempts[pid] = kept
+		}
+	}
+	return result
+}
+
+func (c *Correlator) matchAttempts(pid uint32, lookup *Lookup) []*ConnectAttempt {
+	answers := make(map[string]bool, len(lookup.Answers))
+	for _, answer := range lookup.Answers {
+		answers[answer] = true
+	}
+	windowEnd := lookup.EndTime + uint64(c.window)
+	var result []*ConnectAttempt
+	for _, attempt := range c.attempts[pid] {
+		if attempt.StartTime < lookup.EndTime || attempt.StartTime > windowEnd {
+			continue
+		}
+		if len(answers) > 0 && !answers[attempt.RemoteIP] {
+			continue
+		}
+		result = append(result, attempt)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartTime < result[j].StartTime
+	})
+	if len(result) > maxCorrelatedAttempts {
+		result = result[:maxCorrelatedAttempts]
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCorrelator(t *testing.T) {
+	now := time.Now()
+	ms := uint64(time.Millisecond)
+	correlator := NewCorrelator(500*time.Millisecond, 10*time.Second)
+	// the normal lookup is ignored
+	correlator.AddLookup(1, &Lookup{Name: "fast.local", RCode: "NOERROR", StartTime: 0, EndTime: 10 * ms, CreateTime: now})
+	// slow lookup only correlated with the answered addresses
+	correlator.AddLookup(1, &Lookup{Name: "slow.local", RCode: "NOERROR", StartTime: 0, EndTime: 800 * ms,
+		Answers: []string{"10.0.0.1"}, CreateTime: now})
+	// failed lookup correlated with all the attempts
+	correlator.AddLookup(2, &Lookup{Name: "missing.local", RCode: "NXDOMAIN", StartTime: 0, EndTime: 20 * ms, CreateTime: now})
+
+	correlator.AddAttempt(&ConnectAttempt{ConnectionID: 1, RandomID: 1, PID: 1, RemoteIP: "10.0.0.1", StartTime: 900 * ms, CreateTime: now})
+	correlator.AddAttempt(&ConnectAttempt{ConnectionID: 2, RandomID: 1, PID: 1, RemoteIP: "10.0.0.2", StartTime: 900 * ms, CreateTime: now})
+	// before the lookup finished
+	correlator.AddAttempt(&ConnectAttempt{ConnectionID: 3, RandomID: 1, PID: 1, RemoteIP: "10.0.0.1", StartTime: 100 * ms, CreateTime: now})
+	// out of the window
+	correlator.AddAttempt(&ConnectAttempt{ConnectionID: 4, RandomID: 1, PID: 1, RemoteIP: "10.0.0.1", StartTime: 20000 * ms, CreateTime: now})
+	correlator.AddAttempt(&ConnectAttempt{ConnectionID: 5, RandomID: 1, PID: 2, RemoteIP: "10.0.0.3", StartTime: 30 * ms, CreateTime: now})
+	correlator.MarkFailure(&ConnectAttempt{ConnectionID: 1, RandomID: 1, PID: 1, Failure: "timeout", CreateTime: now})
+	// the failure of the attempt which not reported by the connect event
+	correlator.MarkFailure(&ConnectAttempt{PID: 2, RemoteIP: "10.0.0.4", StartTime: 40 * ms, Failure: "refused", CreateTime: now})
+
+	if result := correlator.Expire(now.Add(time.Second)); len(result) != 0 {
+		t.Fatalf("the correlations should be waiting in the window: %+v", result)
+	}
+	result := correlator.Expire(now.Add(10 * time.Second))
+	if len(result) != 2 {
+		t.Fatalf("expected 2 correlations, actual: %d", len(result))
+	}
+	expected := map[string][]string{
+		"slow.local":    {"10.0.0.1/timeout"},
+		"missing.local": {"10.0.0.3/", "10.0.0.4/refused"},
+	}
+	for _, correlation := range result {
+		attempts := make([]string, 0)
+		for _, attempt := range correlation.Attempts {
+			attempts = append(attempts, attempt.RemoteIP+"/"+attempt.Failure)
+		}
+		if want := expected[correlation.Lookup.Name]; !reflect.DeepEqual(want, attempts) {
+			t.Errorf("lookup %s expected attempts: %v, actual: %v", correlation.Lookup.Name, want, attempts)
+		}
+	}
+	if result = correlator.Expire(now.Add(30 * time.Second)); len(result) != 0 || len(correlator.attempts) != 0 {
+		t.Fatalf("the correlations and attempts should be cleaned")
+	}
+}
@@ -0,0 +1,193 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	headerLength = 12
+	// the max count of the labels in one name, includes the compression pointers
+	maxLabels = 128
+)
+
+// ErrInvalidData is the data is not a DNS message
+var ErrInvalidData = errors.New("invalid DNS data")
+
+var rCodeNames = map[dnsmessage.RCode]string{
+	dnsmessage.RCodeSuccess:        "NOERROR",
+	dnsmessage.RCodeFormatError:    "FORMERR",
+	dnsmessage.RCodeServerFailure:  "SERVFAIL",
+	dnsmessage.RCodeNameError:      "NXDOMAIN",
+	dnsmessage.RCodeNotImplemented: "NOTIMP",
+	dnsmessage.RCodeRefused:        "REFUSED",
+}
+
+// Message is the decoded DNS message, only the first question is decoded
+type Message struct {
+	ID       uint16
+	Response bool
+	RCode    dnsmessage.RCode
+	// Name is the lower case query name without the trailing dot, "." for the root
+	Name string
+	// Type is the query type, such as "A", "AAAA"
+	Type string
+	// Answers are the addresses in the A and AAAA answers, only decoded when required
+	Answers []string
+}
+
+// ParseMessage decode the header and the first question of the message, and the answered addresses if required
+func ParseMessage(data []byte, withAnswers bool) (*Message, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(data)
+	if err != nil {
+		return nil, err
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(strings.ToLower(question.Name.String()), ".")
+	if name == "" {
+		name = "."
+	}
+	message := &Message{
+		ID:       header.ID,
+		Response: header.Response,
+		RCode:    header.RCode,
+		Name:     name,
+		Type:     strings.TrimPrefix(question.Type.String(), "Type"),
+	}
+	if withAnswers && header.Response {
+		if err := parser.SkipAllQuestions(); err == nil {
+			message.Answers = answerAddresses(&parser)
+		}
+	}
+	return message, nil
+}
+
+// Question is the name and type of the query, for matching the queries with the same question
+func (m *Message) Question() string {
+	return m.Name + " " + m.Type
+}
+
+// RCodeName is the name of the response code, such as "NOERROR", "NXDOMAIN"
+func RCodeName(code dnsmessage.RCode) string {
+	if name, exist := rCodeNames[code]; exist {
+		return name
+	}
+	return fmt.Sprintf("RCODE_%d", code)
+}
+
+// MessageLength returns the length of the DNS message at the start of data by walking all the records,
+// -1 if the data is not complete, or ErrInvalidData when the data is not a DNS message.
+// The datagrams could be concatenated when sending by sendmmsg
+func MessageLength(data []byte) (int, error) {
+	if len(data) < headerLength {
+		return -1, nil
+	}
+	// the opcode must be QUERY(0), IQUERY(1), STATUS(2), NOTIFY(4) or UPDATE(5)
+	if opcode := (data[2] >> 3) & 0x0F; opcode > 5 || opcode == 3 {
+		return 0, ErrInvalidData
+	}
+	questions := int(binary.BigEndian.Uint16(data[4:]))
+	if questions == 0 {
+		return 0, ErrInvalidData
+	}
+	records := int(binary.BigEndian.Uint16(data[6:])) + int(binary.BigEndian.Uint16(data[8:])) +
+		int(binary.BigEndian.Uint16(data[10:]))
+	offset := headerLength
+	var err error
+	for i := 0; i < questions+records; i++ {
+		if offset, err = skipName(data, offset); err != nil || offset < 0 {
+			return offset, err
+		}
+		if i < questions {
+			// type and class
+			offset += 4
+			continue
+		}
+		// type, class, TTL and data length
+		if offset+10 > len(data) {
+			return -1, nil
+		}
+		offset += 10 + int(binary.BigEndian.Uint16(data[offset+8:]))
+	}
+	if offset > len(data) {
+		return -1, nil
+	}
+	return offset, nil
+}
+
+// skipName returns the offset after the name, -1 if the data is not complete
+func skipName(data []byte, offset int) (int, error) {
+	for i := 0; i < maxLabels; i++ {
+		if offset >= len(data) {
+			return -1, nil
+		}
+		length := int(data[offset])
+		switch length & 0xC0 {
+		case 0xC0:
+			// the compression pointer is always the end of the name
+			return offset + 2, nil
+		case 0x00:
+			if length == 0 {
+				return offset + 1, nil
+			}
+			offset += 1 + length
+		default:
+			return 0, ErrInvalidData
+		}
+	}
+	return 0, ErrInvalidData
+}
+
+func answerAddresses(parser *dnsmessage.Parser) []string {
+	var addresses []string
+	for {
+		header, err := parser.AnswerHeader()
+		if err != nil {
+			return addresses
+		}
+		switch header.Type {
+		case dnsmessage.TypeA:
+			resource, err := parser.AResource()
+			if err != nil {
+				return addresses
+			}
+			addresses = append(addresses, net.IP(resource.A[:]).String())
+		case dnsmessage.TypeAAAA:
+			resource, err := parser.AAAAResource()
+			if err != nil {
+				return addresses
+			}
+			addresses = append(addresses, net.IP(resource.AAAA[:]).String())
+		default:
+			if err := parser.SkipAnswer(); err != nil {
+				return addresses
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func buildMessage(t *testing.T, id uint16, response bool, rcode dnsmessage.RCode, name string,
+	queryType dnsmessage.Type, answers ...[4]byte) []byte {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, Response: response, RCode: rcode})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		t.Fatal(err)
+	}
+	question := dnsmessage.Question{Name: dnsmessage.MustNewName(name), Type: queryType, Class: dnsmessage.ClassINET}
+	if err := builder.Question(question); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.StartAnswers(); err != nil {
+		t.Fatal(err)
+	}
+	for _, answer := range answers {
+		header := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 30}
+		if err := builder.AResource(header, dnsmessage.AResource{A: answer}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	message, err := builder.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func TestMessageLength(t *testing.T) {
+	query := buildMessage(t, 1, false, dnsmessage.RCodeSuccess, "example.com.", dnsmessage.TypeA)
+	response := buildMessage(t, 1, true, dnsmessage.RCodeSuccess, "example.com.", dnsmessage.TypeA,
+		[4]byte{10, 0, 0, 1}, [4]byte{10, 0, 0, 2})
+	tests := []struct {
+		name     string
+		data     []byte
+		length   int
+		hasError bool
+	}{
+		{name: "query", data: query, length: len(query)},
+		{name: "response with compressed names", data: response, length: len(response)},
+		{name: "concatenated datagrams", data: append(append([]byte{}, query...), response...), length: len(query)},
+		{name: "header not complete", data: query[:8], length: -1},
+		{name: "answer not complete", data: response[:len(response)-2], length: -1},
+		{name: "no question", data: make([]byte, 12), hasError: true},
+		{name: "invalid label", data: append(append([]byte{}, query[:12]...), 0x80, 0x00), hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			length, err := MessageLength(tt.data)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("expected error: %t, actual: %v", tt.hasError, err)
+			}
+			if err == nil && length != tt.length {
+				t.Errorf("expected length: %d, actual: %d", tt.length, length)
+			}
+		})
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *Message
+	}{
+		{
+			name:     "query",
+			data:     buildMessage(t, 1, false, dnsmessage.RCodeSuccess, "Api.Example.com.", dnsmessage.TypeA),
+			expected: &Message{ID: 1, Name: "api.example.com", Type: "A"},
+		},
+		{
+			name: "response with answers",
+			data: buildMessage(t, 2, true, dnsmessage.RCodeSuccess, "api.example.com.", dnsmessage.TypeA,
+				[4]byte{10, 0, 0, 1}, [4]byte{10, 0, 0, 2}),
+			expected: &Message{ID: 2, Response: true, Name: "api.example.com", Type: "A", Answers: []string{"10.0.0.1", "10.0.0.2"}},
+		},
+		{
+			name:     "failed response",
+			data:     buildMessage(t, 3, true, dnsmessage.RCodeNameError, "missing.local.", dnsmessage.TypeAAAA),
+			expected: &Message{ID: 3, Response: true, RCode: dnsmessage.RCodeNameError, Name: "missing.local", Type: "AAAA"},
+		},
+		{
+			name:     "root name",
+			data:     buildMessage(t, 4, false, dnsmessage.RCodeSuccess, ".", dnsmessage.TypeNS),
+			expected: &Message{ID: 4, Name: ".", Type: "NS"},
+		},
+		{
+			name: "header not complete",
+			data: make([]byte, 8),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := ParseMessage(tt.data, true)
+			if tt.expected == nil {
+				if err == nil {
+					t.Fatalf("expected error, actual: %+v", message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(message, tt.expected) {
+				t.Errorf("expected message: %+v, actual: %+v", tt.expected, message)
+			}
+		})
+	}
+}
+
+func TestRCodeName(t *testing.T) {
+	tests := []struct {
+		code     dnsmessage.RCode
+		expected string
+	}{
+		{code: dnsmessage.RCodeSuccess, expected: "NOERROR"},
+		{code: dnsmessage.RCodeNameError, expected: "NXDOMAIN"},
+		{code: dnsmessage.RCodeServerFailure, expected: "SERVFAIL"},
+		{code: 11, expected: "RCODE_11"},
+	}
+	for _, tt := range tests {
+		if name := RCodeName(tt.code); name != tt.expected {
+			t.Errorf("expected name of %d: %s, actual: %s", tt.code, tt.expected, name)
+		}
+	}
+}
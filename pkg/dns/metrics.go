@@ -0,0 +1,161 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"golang.org/x/net/dns/dnsmessage"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+// latencyBuckets of DNS query duration(ms), each value is the lower bound of the bucket
+var latencyBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+var latencyPercentiles = []struct {
+	Name  string
+	Value float64
+}{
+	{Name: "p50", Value: 0.5},
+	{Name: "p90", Value: 0.9},
+	{Name: "p99", Value: 0.99},
+}
+
+// resolverKey is the pod and the DNS server which the pod queried
+type resolverKey struct {
+	ServiceName  string
+	InstanceName string
+	Resolver     string
+	// Proxy is the name of DNS proxy which forwarded the query to the resolver on behalf of the pod, such as the ztunnel
+	Proxy string
+}
+
+// resolverMetrics is the aggregated DNS queries of a resolver key in a report period
+type resolverMetrics struct {
+	Queries  int64
+	Success  int64
+	NXDomain int64
+	Failures int64
+	Timeouts int64
+	Latency  []int64
+}
+
+func newResolverMetrics() *resolverMetrics {
+	return &resolverMetrics{Latency: make([]int64, len(latencyBuckets))}
+}
+
+func (m *resolverMetrics) RecordResponse(code dnsmessage.RCode, durationMs float64) {
+	switch code {
+	case dnsmessage.RCodeSuccess:
+		m.Success++
+	case dnsmessage.RCodeNameError:
+		m.NXDomain++
+	default:
+		m.Failures++
+	}
+	m.Latency[bucketIndex(latencyBuckets, durationMs)]++
+}
+
+func (m *resolverMetrics) AppendMeters(list []*v3.MeterData, prefix string, labels []*v3.Label) []*v3.MeterData {
+	list = appendSingleValue(list, prefix+"request_counter", labels, m.Queries)
+	list = appendSingleValue(list, prefix+"success_counter", labels, m.Success)
+	list = appendSingleValue(list, prefix+"nxdomain_counter", labels, m.NXDomain)
+	list = appendSingleValue(list, prefix+"failure_counter", labels, m.Failures)
+	list = appendSingleValue(list, prefix+"timeout_counter", labels, m.Timeouts)
+
+	var responses int64
+	values := make([]*v3.MeterBucketValue, 0, len(latencyBuckets))
+	for inx, bucket := range latencyBuckets {
+		responses += m.Latency[inx]
+		values = append(values, &v3.MeterBucketValue{Bucket: bucket, Count: m.Latency[inx]})
+	}
+	if responses == 0 {
+		return list
+	}
+	list = append(list, &v3.MeterData{
+		Metric: &v3.MeterData_Histogram{
+			Histogram: &v3.MeterHistogram{
+				Name:   prefix + "latency_histogram",
+				Labels: labels,
+				Values: values,
+			},
+		},
+	})
+	for _, p := range latencyPercentiles {
+		percentileLabels := append(append(make([]*v3.Label, 0, len(labels)+1), labels...), &v3.Label{Name: "percentile", Value: p.Name})
+		list = append(list, &v3.MeterData{
+			Metric: &v3.MeterData_SingleValue{
+				SingleValue: &v3.MeterSingleValue{
+					Name:   prefix + "latency_percentile",
+					Labels: percentileLabels,
+					Value:  percentile(latencyBuckets, m.Latency, p.Value),
+				},
+			},
+		})
+	}
+	return list
+}
+
+func appendSingleValue(list []*v3.MeterData, name string, labels []*v3.Label, value int64) []*v3.MeterData {
+	return append(list, &v3.MeterData{
+		Metric: &v3.MeterData_SingleValue{
+			SingleValue: &v3.MeterSingleValue{
+				Name:   name,
+				Labels: labels,
+				Value:  float64(value),
+			},
+		},
+	})
+}
+
+// bucketIndex find the bucket which the value belongs to
+func bucketIndex(buckets []float64, value float64) int {
+	for inx := len(buckets) - 1; inx > 0; inx-- {
+		if value >= buckets[inx] {
+			return inx
+		}
+	}
+	return 0
+}
+
+// percentile estimates the percentile value from the bucket counts,
+// the upper bound of the matched bucket is used, or the lower bound if it's the last bucket
+func percentile(buckets []float64, counts []int64, p float64) float64 {
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(float64(total)*p + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var current int64
+	for inx, c := range counts {
+		current += c
+		if current < rank {
+			continue
+		}
+		if inx+1 < len(buckets) {
+			return buckets[inx+1]
+		}
+		return buckets[inx]
+	}
+	return buckets[len(buckets)-1]
+}
@@ -0,0 +1,43 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	buckets := []float64{0, 1, 2, 5, 10}
+	tests := []struct {
+		name   string
+		counts []int64
+		p      float64
+		result float64
+	}{
+		{name: "empty", counts: []int64{0, 0, 0, 0, 0}, p: 0.5, result: 0},
+		{name: "first bucket", counts: []int64{10, 0, 0, 0, 0}, p: 0.99, result: 1},
+		{name: "middle", counts: []int64{5, 3, 2, 0, 0}, p: 0.5, result: 1},
+		{name: "tail", counts: []int64{5, 3, 1, 0, 1}, p: 0.99, result: 10},
+		{name: "p90", counts: []int64{0, 8, 1, 1, 0}, p: 0.9, result: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if r := percentile(buckets, tt.counts, tt.p); r != tt.result {
+				t.Errorf("percentile() = %v, want %v", r, tt.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"context"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
+)
+
+const ModuleName = "dns"
+
+type Module struct {
+	config *Config
+
+	collector *Collector
+}
+
+func NewModule() *Module {
+	return &Module{config: &Config{}}
+}
+
+func (m *Module) Name() string {
+	return ModuleName
+}
+
+func (m *Module) RequiredModules() []string {
+	return []string{core.ModuleName, process.ModuleName}
+}
+
+func (m *Module) RequiredCapabilities() []string {
+	return []string{capability.PacketSocket}
+}
+
+func (m *Module) Config() module.ConfigInterface {
+	return m.config
+}
+
+func (m *Module) Start(ctx context.Context, mgr *module.Manager) error {
+	collector, err := NewCollector(mgr, m.config)
+	if err != nil {
+		return err
+	}
+	if err := collector.Start(ctx); err != nil {
+		return err
+	}
+	m.collector = collector
+	return nil
+}
+
+func (m *Module) NotifyStartSuccess() {
+}
+
+func (m *Module) Shutdown(context.Context, *module.Manager) error {
+	if m.collector != nil {
+		return m.collector.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"net"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
+)
+
+const zTunnelProxyName = "ztunnel"
+
+// findZTunnelAddresses finds all the local addresses of the ztunnel processes in the host,
+// the DNS queries sent from these addresses are forwarded by the ztunnel DNS proxy on behalf of the workloads
+func findZTunnelAddresses() map[string]bool {
+	result := make(map[string]bool)
+	processes, err := procfs.Processes()
+	if err != nil {
+		log.Warnf("list processes for finding ztunnel failure: %v", err)
+		return result
+	}
+	for _, p := range processes {
+		exe, err := procfs.Exe(p.Pid)
+		if err != nil || !strings.HasSuffix(exe, "/ztunnel") {
+			continue
+		}
+		if data, err := procfs.ReadFile(p.Pid, "net/fib_trie"); err == nil {
+			for _, addr := range parseFibTrieLocalAddresses(data) {
+				result[addr] = true
+			}
+		}
+		if data, err := procfs.ReadFile(p.Pid, "net/if_inet6"); err == nil {
+			for _, addr := range parseIfInet6Addresses(data) {
+				result[addr] = true
+			}
+		}
+	}
+	return result
+}
+
+// parseFibTrieLocalAddresses reads the IPv4 local host addresses(exclude loopback) from the "/proc/net/fib_trie"
+func parseFibTrieLocalAddresses(data []byte) []string {
+	result := make([]string, 0)
+	exists := make(map[string]bool)
+	var last string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "|--") {
+			last = strings.TrimSpace(strings.TrimPrefix(line, "|--"))
+			continue
+		}
+		if !strings.HasPrefix(line, "/32 host LOCAL") || last == "" {
+			continue
+		}
+		addr := net.ParseIP(last)
+		if addr != nil && !addr.IsLoopback() && !exists[last] {
+			exists[last] = true
+			result = append(result, last)
+		}
+	}
+	return result
+}
+
+// parseIfInet6Addresses reads the IPv6 addresses(exclude loopback and link local) from the "/proc/net/if_inet6"
+func parseIfInet6Addresses(data []byte) []string {
+	result := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || len(fields[0]) != 32 {
+			continue
+		}
+		raw, err := hex.DecodeString(fields[0])
+		if err != nil {
+			continue
+		}
+		addr := net.IP(raw)
+		if addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+			continue
+		}
+		result = append(result, addr.String())
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFibTrieLocalAddresses(t *testing.T) {
+	data := `Main:
+  +-- 0.0.0.0/0 3 0 5
+     |-- 0.0.0.0
+        /0 universe UNICAST
+     +-- 10.244.1.0/24 2 0 2
+        |-- 10.244.1.0
+           /24 link UNICAST
+        |-- 10.244.1.12
+           /32 host LOCAL
+     +-- 127.0.0.0/8 2 0 2
+        |-- 127.0.0.1
+           /32 host LOCAL
+Local:
+  +-- 0.0.0.0/0 3 0 5
+        |-- 10.244.1.12
+           /32 host LOCAL
+`
+	result := parseFibTrieLocalAddresses([]byte(data))
+	if expected := []string{"10.244.1.12"}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, actual %v", expected, result)
+	}
+}
+
+func TestParseIfInet6Addresses(t *testing.T) {
+	data := `00000000000000000000000000000001 01 80 10 80       lo
+fd000000000000000000000000000012 02 40 00 00     eth0
+fe80000000000000a8f3c2fffe4d1e2a 02 40 20 80     eth0
+`
+	result := parseIfInet6Addresses([]byte(data))
+	if expected := []string{"fd00::12"}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, actual %v", expected, result)
+	}
+}
@@ -0,0 +1,153 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fdpressure
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+var log = logger.GetLogger("fdpressure")
+
+type serviceInstance struct {
+	service  string
+	instance string
+}
+
+// Collector periodically reads the file descriptors and sockets usage of all monitored processes
+type Collector struct {
+	processOperator process.Operator
+	sender          *reporter.MeterSender
+	reportPeriod    time.Duration
+	meterPrefix     string
+	throttle        *selfprotect.Throttle
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewCollector(mgr *module.Manager, config *Config) (*Collector, error) {
+	reportPeriod, err := time.ParseDuration(config.ReportPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parsing report period failure: %v", err)
+	}
+	if config.MeterPrefix == "" {
+		return nil, fmt.Errorf("please provide the meter prefix")
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	return &Collector{
+		processOperator: mgr.FindModule(process.ModuleName).(process.Operator),
+		sender:          reporter.NewMeterSender("FD pressure metrics", coreOperator.BackendOperator().GetConnection()),
+		reportPeriod:    reportPeriod,
+		meterPrefix:     config.MeterPrefix + "_",
+		throttle:        coreOperator.ResourceGovernor().Register(ModuleName, selfprotect.PriorityLow),
+	}, nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	reporter.StartPeriodic(c.ctx, "FD pressure", c.reportPeriod, c.report)
+	return nil
+}
+
+func (c *Collector) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return nil
+}
+
+func (c *Collector) report(ctx context.Context) error {
+	if c.throttle.Paused() {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	meters := make(map[serviceInstance][]*v3.MeterData)
+	// the TCP sockets are shared by the processes in the same network namespace
+	netNSSockets := make(map[uint64][]*tcpSocket)
+	for _, p := range c.processOperator.FindAllRegisteredProcesses() {
+		fds, err := readProcessFDs(p.Pid())
+		if err != nil {
+			log.Debugf("read the file descriptors of process %d failure: %v", p.Pid(), err)
+			continue
+		}
+		metadata := serviceInstance{service: p.Entity().ServiceName, instance: p.Entity().InstanceName}
+		meters[metadata] = append(meters[metadata],
+			c.buildMeter("open_fds", p, float64(fds.Open)),
+			c.buildMeter("open_sockets", p, float64(len(fds.Sockets))))
+
+		if limit, err := readOpenFilesLimit(p.Pid()); err != nil {
+			log.Debugf("read the open files limit of process %d failure: %v", p.Pid(), err)
+		} else if limit != math.MaxUint64 && limit > 0 {
+			meters[metadata] = append(meters[metadata],
+				c.buildMeter("fd_limit", p, float64(limit)),
+				c.buildMeter("fd_usage", p, float64(fds.Open)*100/float64(limit)))
+		}
+
+		sockets, err := c.findTCPSockets(p.Pid(), netNSSockets)
+		if err != nil {
+			log.Debugf("read the TCP sockets of process %d failure: %v", p.Pid(), err)
+			continue
+		}
+		meters[metadata] = append(meters[metadata], c.buildMeter("time_wait", p, float64(countTimeWait(sockets, fds.Sockets))))
+	}
+	collections := make([]*v3.MeterDataCollection, 0, len(meters))
+	for metadata, data := range meters {
+		data[0].Service = metadata.service
+		data[0].ServiceInstance = metadata.instance
+		data[0].Timestamp = now
+		collections = append(collections, &v3.MeterDataCollection{MeterData: data})
+	}
+	return c.sender.Send(ctx, collections)
+}
+
+func (c *Collector) findTCPSockets(pid int32, cache map[uint64][]*tcpSocket) ([]*tcpSocket, error) {
+	netNS, err := host.NetworkNamespaceInode(pid)
+	if err != nil {
+		return nil, err
+	}
+	if sockets, exist := cache[netNS]; exist {
+		return sockets, nil
+	}
+	sockets, err := readTCPSockets(pid)
+	if err != nil {
+		return nil, err
+	}
+	cache[netNS] = sockets
+	return sockets, nil
+}
+
+func (c *Collector) buildMeter(name string, p api.ProcessInterface, value float64) *v3.MeterData {
+	return reporter.SingleValueMeter(c.meterPrefix+name, []*v3.Label{
+		{Name: "process_name", Value: p.Entity().ProcessName},
+		{Name: "layer", Value: p.Entity().Layer},
+	}, value)
+}
@@ -0,0 +1,189 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fdpressure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+const (
+	tcpStateTimeWait = 0x06
+	tcpStateListen   = 0x0A
+)
+
+// processFDs is the file descriptors usage of a process
+type processFDs struct {
+	// the count of opened file descriptors
+	Open int
+	// the socket inodes of the opened file descriptors
+	Sockets map[uint64]bool
+}
+
+// tcpSocket is a socket entry in the TCP table of the network namespace
+type tcpSocket struct {
+	LocalPort uint16
+	State     uint8
+	Inode     uint64
+}
+
+// readProcessFDs reads all the opened file descriptors of the process
+func readProcessFDs(pid int32) (*processFDs, error) {
+	dir := host.GetHostProcInHost(fmt.Sprintf("%d/fd", pid))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := &processFDs{Open: len(entries), Sockets: make(map[uint64]bool)}
+	for _, entry := range entries {
+		// the file descriptor may be closed already
+		link, err := os.Readlink(dir + "/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		if inode, ok := parseSocketInode(link); ok {
+			result.Sockets[inode] = true
+		}
+	}
+	return result, nil
+}
+
+// parseSocketInode parse the inode from the socket FD link, the format is "socket:[inode]"
+func parseSocketInode(link string) (uint64, bool) {
+	if !strings.HasPrefix(link, "socket:[") || !strings.HasSuffix(link, "]") {
+		return 0, false
+	}
+	inode, err := strconv.ParseUint(link[len("socket:["):len(link)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return inode, true
+}
+
+// readOpenFilesLimit reads the soft limit of the max open files of the process
+func readOpenFilesLimit(pid int32) (uint64, error) {
+	file, err := os.Open(host.GetHostProcInHost(fmt.Sprintf("%d/limits", pid)))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return parseOpenFilesLimit(file)
+}
+
+// parseOpenFilesLimit parse the "Max open files" soft limit from the limits file, the unlimited value is treated as max uint64
+func parseOpenFilesLimit(reader io.Reader) (uint64, error) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) == 0 {
+			break
+		}
+		if fields[0] == "unlimited" {
+			return math.MaxUint64, nil
+		}
+		return strconv.ParseUint(fields[0], 10, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("could not found the max open files limit")
+}
+
+// readTCPSockets reads all the IPv4 and IPv6 TCP sockets in the network namespace of the process
+func readTCPSockets(pid int32) ([]*tcpSocket, error) {
+	result := make([]*tcpSocket, 0)
+	for _, name := range []string{"tcp", "tcp6"} {
+		file, err := os.Open(host.GetHostProcInHost(fmt.Sprintf("%d/net/%s", pid, name)))
+		if err != nil {
+			// the IPv6 may be disabled
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		sockets, err := parseTCPSockets(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sockets...)
+	}
+	return result, nil
+}
+
+// parseTCPSockets parse the TCP table, each line format is:
+// "sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode ..."
+func parseTCPSockets(reader io.Reader) ([]*tcpSocket, error) {
+	result := make([]*tcpSocket, 0)
+	scanner := bufio.NewScanner(reader)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		local := strings.Split(fields[1], ":")
+		if len(local) != 2 {
+			return nil, fmt.Errorf("the local address format not right: %s", fields[1])
+		}
+		port, err := strconv.ParseUint(local[1], 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the local port failure: %v", err)
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the socket state failure: %v", err)
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the socket inode failure: %v", err)
+		}
+		result = append(result, &tcpSocket{LocalPort: uint16(port), State: uint8(state), Inode: inode})
+	}
+	return result, scanner.Err()
+}
+
+// countTimeWait counts the TIME_WAIT sockets which local port is listened by the process,
+// the TIME_WAIT socket have no owner, so only the server side could be attributed to the process
+func countTimeWait(sockets []*tcpSocket, processSockets map[uint64]bool) int {
+	listenPorts := make(map[uint16]bool)
+	for _, s := range sockets {
+		if s.State == tcpStateListen && processSockets[s.Inode] {
+			listenPorts[s.LocalPort] = true
+		}
+	}
+	count := 0
+	for _, s := range sockets {
+		if s.State == tcpStateTimeWait && listenPorts[s.LocalPort] {
+			count++
+		}
+	}
+	return count
+}
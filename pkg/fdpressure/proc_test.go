@@ -0,0 +1,93 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fdpressure
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseOpenFilesLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected uint64
+		hasError bool
+	}{
+		{
+			name: "normal",
+			content: `Limit                     Soft Limit           Hard Limit           Units
+Max processes             63704                63704                processes
+Max open files            1024                 524288               files
+Max locked memory         8388608              8388608              bytes`,
+			expected: 1024,
+		},
+		{
+			name:     "unlimited",
+			content:  `Max open files            unlimited            unlimited            files`,
+			expected: math.MaxUint64,
+		},
+		{
+			name:     "not found",
+			content:  `Max processes             63704                63704                processes`,
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseOpenFilesLimit(strings.NewReader(tt.content))
+			if (err != nil) != tt.hasError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tt.expected {
+				t.Fatalf("expected %d, actual %d", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCountTimeWait(t *testing.T) {
+	content := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 2001 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:1F90 0100007F:D2F0 06 00000000:00000000 03:00000F9D 00000000     0        0 0 3 0000000000000000
+   3: 0100007F:1F90 0100007F:D2F2 06 00000000:00000000 03:00000F9D 00000000     0        0 0 3 0000000000000000
+   4: 0100007F:0050 0100007F:D2F4 06 00000000:00000000 03:00000F9D 00000000     0        0 0 3 0000000000000000
+   5: 0100007F:D2F6 0100007F:1F90 06 00000000:00000000 03:00000F9D 00000000     0        0 0 3 0000000000000000`
+	sockets, err := parseTCPSockets(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 6 {
+		t.Fatalf("expected 6 sockets, actual %d", len(sockets))
+	}
+	if count := countTimeWait(sockets, map[uint64]bool{1001: true}); count != 2 {
+		t.Fatalf("expected 2 TIME_WAIT sockets, actual %d", count)
+	}
+}
+
+func TestParseSocketInode(t *testing.T) {
+	if inode, ok := parseSocketInode("socket:[12345]"); !ok || inode != 12345 {
+		t.Fatalf("parsing the socket inode failure: %d", inode)
+	}
+	if _, ok := parseSocketInode("/dev/null"); ok {
+		t.Fatalf("the file should not be a socket")
+	}
+}
@@ -0,0 +1,107 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package icmp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	captureBufferSize  = 65536
+	captureReadTimeout = time.Second
+)
+
+// capture reads the ICMP packets from all interfaces of the current network namespace
+type capture struct {
+	fd  int
+	buf []byte
+}
+
+func newCapture() (*capture, error) {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, int(htons(unix.ETH_P_ALL)))
+	if err != nil {
+		return nil, fmt.Errorf("open packet socket failure: %v", err)
+	}
+	if err := attachICMPFilter(fd); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+	timeout := unix.NsecToTimeval(captureReadTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("setting the read timeout failure: %v", err)
+	}
+	return &capture{fd: fd, buf: make([]byte, captureBufferSize)}, nil
+}
+
+// read the next ICMP message, return nil when there have no packet in the read timeout or the packet cannot be decoded
+func (c *capture) read() (*message, error) {
+	n, _, err := unix.Recvfrom(c.fd, c.buf, 0)
+	if err != nil {
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeMessage(c.buf[:n]), nil
+}
+
+func (c *capture) Close() error {
+	return unix.Close(c.fd)
+}
+
+// attachICMPFilter only accept the ICMP(IPv4) and ICMPv6(IPv6 without extension headers) packets,
+// the packet socket is SOCK_DGRAM, so the data is started with the network header
+func attachICMPFilter(fd int) error {
+	instructions := []bpf.Instruction{
+		bpf.LoadAbsolute{Off: 0, Size: 1},
+		bpf.ALUOpConstant{Op: bpf.ALUOpShiftRight, Val: 4},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 4, SkipFalse: 2},
+		// IPv4
+		bpf.LoadAbsolute{Off: 9, Size: 1},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: icmpProtocol, SkipTrue: 3, SkipFalse: 4},
+		// IPv6
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 6, SkipFalse: 3},
+		bpf.LoadAbsolute{Off: 6, Size: 1},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: icmpv6Protocol, SkipFalse: 1},
+		bpf.RetConstant{Val: captureBufferSize},
+		bpf.RetConstant{Val: 0},
+	}
+	raw, err := bpf.Assemble(instructions)
+	if err != nil {
+		return fmt.Errorf("assemble the packet filter failure: %v", err)
+	}
+	filters := make([]unix.SockFilter, 0, len(raw))
+	for _, r := range raw {
+		filters = append(filters, unix.SockFilter{Code: r.Op, Jt: r.Jt, Jf: r.Jf, K: r.K})
+	}
+	program := &unix.SockFprog{Len: uint16(len(filters)), Filter: &filters[0]}
+	if err := unix.SetsockoptSockFprog(fd, unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, program); err != nil {
+		return fmt.Errorf("attach the packet filter failure: %v", err)
+	}
+	return nil
+}
+
+func htons(v uint16) uint16 {
+	return (v << 8) | (v >> 8)
+}
@@ -0,0 +1,245 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package icmp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+var log = logger.GetLogger("icmp")
+
+// duplicateWindow is the duration of the same message captured on multiple interfaces(such as the veth pair and the bridge)
+const duplicateWindow = time.Second
+
+type instanceKey struct {
+	ServiceName  string
+	InstanceName string
+}
+
+// messageKey is the pod, the message type and the remote address which the message related
+type messageKey struct {
+	Instance instanceKey
+	Type     string
+	Reason   string
+	Remote   string
+}
+
+type pathKey struct {
+	Instance instanceKey
+	Remote   string
+}
+
+// Collector aggregates the captured ICMP messages by the monitored pods
+type Collector struct {
+	processOperator process.Operator
+	sender          *reporter.MeterSender
+	reportPeriod    time.Duration
+	meterPrefix     string
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	capture *capture
+
+	mutex    sync.Mutex
+	pods     map[string]*api.ProcessEntity
+	received map[uint64]time.Time
+	messages map[messageKey]int64
+	pathMTU  map[pathKey]uint32
+}
+
+func NewCollector(mgr *module.Manager, config *Config) (*Collector, error) {
+	reportPeriod, err := time.ParseDuration(config.ReportPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parsing report period failure: %v", err)
+	}
+	if config.MeterPrefix == "" {
+		return nil, fmt.Errorf("please provide the meter prefix")
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	return &Collector{
+		processOperator: mgr.FindModule(process.ModuleName).(process.Operator),
+		sender:          reporter.NewMeterSender("ICMP metrics", coreOperator.BackendOperator().GetConnection()),
+		reportPeriod:    reportPeriod,
+		meterPrefix:     config.MeterPrefix + "_",
+		pods:            make(map[string]*api.ProcessEntity),
+		received:        make(map[uint64]time.Time),
+		messages:        make(map[messageKey]int64),
+		pathMTU:         make(map[pathKey]uint32),
+	}, nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	capture, err := newCapture()
+	if err != nil {
+		return err
+	}
+	c.capture = capture
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.refreshPods()
+
+	go c.readMessages()
+	reporter.StartPeriodic(c.ctx, "ICMP metrics", c.reportPeriod, c.flush)
+	return nil
+}
+
+func (c *Collector) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return nil
+}
+
+func (c *Collector) readMessages() {
+	defer func() {
+		if err := c.capture.Close(); err != nil {
+			log.Warnf("close the ICMP packet capture failure: %v", err)
+		}
+	}()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+		m, err := c.capture.read()
+		if err != nil {
+			log.Errorf("read ICMP packet failure, stop the ICMP monitoring: %v", err)
+			return
+		}
+		if m != nil {
+			c.handleMessage(m, time.Now())
+		}
+	}
+}
+
+// handleMessage attributes the message to the pods, the echo message is attributed to the both sides,
+// and the error message is attributed to the pod which sent the original packet
+func (c *Collector) handleMessage(m *message, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if last, exist := c.received[m.Identity]; exist && now.Sub(last) < duplicateWindow {
+		return
+	}
+	c.received[m.Identity] = now
+
+	if !m.IsError() {
+		c.recordMessage(c.pods[m.SrcIP], m, m.DstIP)
+		c.recordMessage(c.pods[m.DstIP], m, m.SrcIP)
+		return
+	}
+	pod := c.pods[m.OriginalSrcIP]
+	c.recordMessage(pod, m, m.OriginalDstIP)
+	if pod == nil || m.MTU == 0 {
+		return
+	}
+	key := pathKey{Instance: instanceKey{ServiceName: pod.ServiceName, InstanceName: pod.InstanceName}, Remote: m.OriginalDstIP}
+	if mtu, exist := c.pathMTU[key]; !exist || m.MTU < mtu {
+		c.pathMTU[key] = m.MTU
+	}
+}
+
+func (c *Collector) recordMessage(pod *api.ProcessEntity, m *message, remote string) {
+	if pod == nil {
+		return
+	}
+	c.messages[messageKey{
+		Instance: instanceKey{ServiceName: pod.ServiceName, InstanceName: pod.InstanceName},
+		Type:     m.Type,
+		Reason:   m.Reason,
+		Remote:   remote,
+	}]++
+}
+
+// refreshPods builds the pod IP to the entity mapping, the IP shared by multiple pods(host network) are ignored
+func (c *Collector) refreshPods() {
+	pods := make(map[string]*api.ProcessEntity)
+	shared := make(map[string]bool)
+	for _, p := range c.processOperator.FindAllRegisteredProcesses() {
+		if p.DetectType() != api.Kubernetes {
+			continue
+		}
+		entity := p.Entity()
+		for _, host := range p.ExposeHosts() {
+			if exist := pods[host]; exist != nil && exist.InstanceName != entity.InstanceName {
+				shared[host] = true
+				continue
+			}
+			pods[host] = entity
+		}
+	}
+	for host := range shared {
+		delete(pods, host)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pods = pods
+}
+
+func (c *Collector) flush(ctx context.Context) error {
+	now := time.Now()
+	c.mutex.Lock()
+	for identity, t := range c.received {
+		if now.Sub(t) >= duplicateWindow {
+			delete(c.received, identity)
+		}
+	}
+	messages, pathMTU := c.messages, c.pathMTU
+	c.messages, c.pathMTU = make(map[messageKey]int64), make(map[pathKey]uint32)
+	c.mutex.Unlock()
+
+	c.refreshPods()
+	return c.sender.Send(ctx, c.buildMeters(messages, pathMTU, now))
+}
+
+func (c *Collector) buildMeters(messages map[messageKey]int64, pathMTU map[pathKey]uint32, now time.Time) []*v3.MeterDataCollection {
+	meters := make(map[instanceKey][]*v3.MeterData)
+	for key, count := range messages {
+		meters[key.Instance] = append(meters[key.Instance], reporter.SingleValueMeter(c.meterPrefix+"message_counter", []*v3.Label{
+			{Name: "type", Value: key.Type},
+			{Name: "reason", Value: key.Reason},
+			{Name: "remote", Value: key.Remote},
+		}, float64(count)))
+	}
+	for key, mtu := range pathMTU {
+		meters[key.Instance] = append(meters[key.Instance], reporter.SingleValueMeter(c.meterPrefix+"path_mtu", []*v3.Label{
+			{Name: "remote", Value: key.Remote},
+		}, float64(mtu)))
+	}
+
+	result := make([]*v3.MeterDataCollection, 0, len(meters))
+	for ins, data := range meters {
+		data[0].Service = ins.ServiceName
+		data[0].ServiceInstance = ins.InstanceName
+		data[0].Timestamp = now.UnixMilli()
+		result = append(result, &v3.MeterDataCollection{MeterData: data})
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package icmp
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"net"
+)
+
+const (
+	icmpProtocol   = 1
+	icmpv6Protocol = 58
+
+	ipv4HeaderMinLen = 20
+	ipv6HeaderLen    = 40
+	icmpHeaderLen    = 8
+	// the max ICMP bytes to identify the same message captured on multiple interfaces
+	icmpIdentifyLen = 64
+)
+
+const (
+	TypeEchoRequest            = "echo_request"
+	TypeEchoReply              = "echo_reply"
+	TypeDestinationUnreachable = "destination_unreachable"
+	TypePacketTooBig           = "packet_too_big"
+	TypeTimeExceeded           = "time_exceeded"
+
+	ReasonNetworkUnreachable  = "network_unreachable"
+	ReasonHostUnreachable     = "host_unreachable"
+	ReasonProtocolUnreachable = "protocol_unreachable"
+	ReasonPortUnreachable     = "port_unreachable"
+	ReasonFragmentationNeeded = "fragmentation_needed"
+	ReasonAdminProhibited     = "admin_prohibited"
+	ReasonOther               = "other"
+)
+
+// message is the decoded ICMP message
+type message struct {
+	SrcIP string
+	DstIP string
+	Type  string
+	// the reason of the destination unreachable message
+	Reason string
+	// the next hop MTU of the fragmentation needed or packet too big message
+	MTU uint32
+	// the source and destination address of the original packet which caused the error message
+	OriginalSrcIP string
+	OriginalDstIP string
+	// the identity of the message, the same message captured on multiple interfaces have the same identity
+	Identity uint64
+}
+
+// IsError the message is reported for the original packet
+func (m *message) IsError() bool {
+	return m.Type != TypeEchoRequest && m.Type != TypeEchoReply
+}
+
+// decodeMessage from the network header, return nil if the data is not a supported ICMP message
+func decodeMessage(data []byte) *message {
+	if len(data) == 0 {
+		return nil
+	}
+	var srcIP, dstIP net.IP
+	var payload []byte
+	var result *message
+	switch data[0] >> 4 {
+	case 4:
+		headerLen := int(data[0]&0x0f) * 4
+		// ignore the non-first fragments
+		if headerLen < ipv4HeaderMinLen || len(data) < headerLen+icmpHeaderLen || data[9] != icmpProtocol ||
+			binary.BigEndian.Uint16(data[6:8])&0x1fff != 0 {
+			return nil
+		}
+		srcIP, dstIP, payload = net.IP(data[12:16]), net.IP(data[16:20]), data[headerLen:]
+		result = decodeICMPv4(payload)
+	case 6:
+		if len(data) < ipv6HeaderLen+icmpHeaderLen || data[6] != icmpv6Protocol {
+			return nil
+		}
+		srcIP, dstIP, payload = net.IP(data[8:24]), net.IP(data[24:40]), data[ipv6HeaderLen:]
+		result = decodeICMPv6(payload)
+	}
+	if result == nil {
+		return nil
+	}
+	result.SrcIP, result.DstIP = srcIP.String(), dstIP.String()
+	if result.IsError() {
+		if result.OriginalSrcIP, result.OriginalDstIP = decodeOriginalAddress(payload[icmpHeaderLen:]); result.OriginalSrcIP == "" {
+			return nil
+		}
+	}
+
+	// the TTL and checksum of the network header could be changed when forwarding, so only the addresses are identified
+	hash := fnv.New64a()
+	_, _ = hash.Write(srcIP)
+	_, _ = hash.Write(dstIP)
+	if len(payload) > icmpIdentifyLen {
+		payload = payload[:icmpIdentifyLen]
+	}
+	_, _ = hash.Write(payload)
+	result.Identity = hash.Sum64()
+	return result
+}
+
+func decodeICMPv4(payload []byte) *message {
+	code := payload[1]
+	switch payload[0] {
+	case 0:
+		return &message{Type: TypeEchoReply}
+	case 8:
+		return &message{Type: TypeEchoRequest}
+	case 11:
+		return &message{Type: TypeTimeExceeded}
+	case 3:
+	default:
+		return nil
+	}
+	result := &message{Type: TypeDestinationUnreachable}
+	switch code {
+	case 0, 6:
+		result.Reason = ReasonNetworkUnreachable
+	case 1, 7:
+		result.Reason = ReasonHostUnreachable
+	case 2:
+		result.Reason = ReasonProtocolUnreachable
+	case 3:
+		result.Reason = ReasonPortUnreachable
+	case 4:
+		result.Reason = ReasonFragmentationNeeded
+		result.MTU = uint32(binary.BigEndian.Uint16(payload[6:8]))
+	case 9, 10, 13:
+		result.Reason = ReasonAdminProhibited
+	default:
+		result.Reason = ReasonOther
+	}
+	return result
+}
+
+func decodeICMPv6(payload []byte) *message {
+	code := payload[1]
+	switch payload[0] {
+	case 128:
+		return &message{Type: TypeEchoRequest}
+	case 129:
+		return &message{Type: TypeEchoReply}
+	case 2:
+		return &message{Type: TypePacketTooBig, MTU: binary.BigEndian.Uint32(payload[4:8])}
+	case 3:
+		return &message{Type: TypeTimeExceeded}
+	case 1:
+	default:
+		return nil
+	}
+	result := &message{Type: TypeDestinationUnreachable}
+	switch code {
+	case 0:
+		result.Reason = ReasonNetworkUnreachable
+	case 1:
+		result.Reason = ReasonAdminProhibited
+	case 3:
+		result.Reason = ReasonHostUnreachable
+	case 4:
+		result.Reason = ReasonPortUnreachable
+	default:
+		result.Reason = ReasonOther
+	}
+	return result
+}
+
+// decodeOriginalAddress decode the addresses of the original packet which is embedded in the ICMP error message
+func decodeOriginalAddress(data []byte) (src, dst string) {
+	if len(data) == 0 {
+		return "", ""
+	}
+	switch data[0] >> 4 {
+	case 4:
+		if len(data) < ipv4HeaderMinLen {
+			return "", ""
+		}
+		return net.IP(data[12:16]).String(), net.IP(data[16:20]).String()
+	case 6:
+		if len(data) < ipv6HeaderLen {
+			return "", ""
+		}
+		return net.IP(data[8:24]).String(), net.IP(data[24:40]).String()
+	}
+	return "", ""
+}
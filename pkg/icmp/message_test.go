@@ -0,0 +1,89 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package icmp
+
+import (
+	"net"
+	"testing"
+)
+
+func buildIPv4(src, dst string, payload []byte) []byte {
+	header := make([]byte, ipv4HeaderMinLen)
+	header[0] = 0x45
+	header[8] = 64
+	header[9] = icmpProtocol
+	copy(header[12:16], net.ParseIP(src).To4())
+	copy(header[16:20], net.ParseIP(dst).To4())
+	return append(header, payload...)
+}
+
+func buildIPv6(src, dst string, payload []byte) []byte {
+	header := make([]byte, ipv6HeaderLen)
+	header[0] = 0x60
+	header[6] = icmpv6Protocol
+	copy(header[8:24], net.ParseIP(src).To16())
+	copy(header[24:40], net.ParseIP(dst).To16())
+	return append(header, payload...)
+}
+
+func TestDecodeMessage(t *testing.T) {
+	original := buildIPv4("10.0.0.1", "10.0.1.1", make([]byte, 8))
+	original6 := buildIPv6("fd00::1", "fd00::2", make([]byte, 8))
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *message
+	}{
+		{
+			name:     "echo request",
+			data:     buildIPv4("10.0.0.1", "10.0.0.2", []byte{8, 0, 0, 0, 0, 1, 0, 1}),
+			expected: &message{SrcIP: "10.0.0.1", DstIP: "10.0.0.2", Type: TypeEchoRequest},
+		},
+		{
+			name: "fragmentation needed",
+			data: buildIPv4("10.0.2.1", "10.0.0.1", append([]byte{3, 4, 0, 0, 0, 0, 0x05, 0x78}, original...)),
+			expected: &message{SrcIP: "10.0.2.1", DstIP: "10.0.0.1", Type: TypeDestinationUnreachable,
+				Reason: ReasonFragmentationNeeded, MTU: 1400, OriginalSrcIP: "10.0.0.1", OriginalDstIP: "10.0.1.1"},
+		},
+		{
+			name: "packet too big",
+			data: buildIPv6("fd00::3", "fd00::1", append([]byte{2, 0, 0, 0, 0, 0, 0x05, 0x00}, original6...)),
+			expected: &message{SrcIP: "fd00::3", DstIP: "fd00::1", Type: TypePacketTooBig,
+				MTU: 1280, OriginalSrcIP: "fd00::1", OriginalDstIP: "fd00::2"},
+		},
+		{
+			name:     "error without original packet",
+			data:     buildIPv4("10.0.2.1", "10.0.0.1", []byte{3, 1, 0, 0, 0, 0, 0, 0}),
+			expected: nil,
+		},
+		{
+			name:     "unsupported type",
+			data:     buildIPv4("10.0.0.1", "10.0.0.2", []byte{13, 0, 0, 0, 0, 0, 0, 0}),
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		actual := decodeMessage(tt.data)
+		if actual != nil {
+			actual.Identity = 0
+		}
+		if (actual == nil) != (tt.expected == nil) || (actual != nil && *actual != *tt.expected) {
+			t.Fatalf("%s: expected %+v, actual %+v", tt.name, tt.expected, actual)
+		}
+	}
+}
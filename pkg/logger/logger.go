@@ -19,12 +19,18 @@ package logger
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
 
 var (
 	root = initializeDefaultLogger()
+
+	// moduleLoggers are the loggers of each module, share the formatter and output with the root logger,
+	// and the level of each one is decided by the module levels
+	moduleLoggers     = make(map[string]*logrus.Logger)
+	moduleLoggersLock sync.Mutex
 )
 
 type Logger struct {
@@ -38,9 +44,28 @@ func GetLogger(modules ...string) *Logger {
 	if len(modules) > 0 {
 		moduleString = strings.Join(modules, ".")
 	}
-	return &Logger{Entry: root.WithField("module", moduleString), module: modules}
+	return &Logger{Entry: moduleLogger(moduleString).WithField("module", moduleString), module: modules}
 }
 
+// Enable check the level is enabled in the module
 func (l *Logger) Enable(level logrus.Level) bool {
-	return root.IsLevelEnabled(level)
+	return l.Logger.IsLevelEnabled(level)
+}
+
+func moduleLogger(module string) *logrus.Logger {
+	moduleLoggersLock.Lock()
+	defer moduleLoggersLock.Unlock()
+	if l := moduleLoggers[module]; l != nil {
+		return l
+	}
+	l := logrus.New()
+	l.SetFormatter(root.Formatter)
+	l.SetOutput(root.Out)
+	if levels != nil {
+		l.SetLevel(levels.levelOf(module))
+	} else {
+		l.SetLevel(root.GetLevel())
+	}
+	moduleLoggers[module] = l
+	return l
 }
@@ -55,3 +55,12 @@ func (m *Module) NotifyStartSuccess() {
 func (m *Module) Shutdown(context.Context, *module.Manager) error {
 	return nil
 }
+
+func (m *Module) ReloadConfig(conf module.ConfigInterface) error {
+	newConf := conf.(*Config)
+	if err := updateLogger(newConf); err != nil {
+		return err
+	}
+	*m.config = *newConf
+	return nil
+}
@@ -18,39 +18,182 @@
 package logger
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/tools/rotate"
+
+	"github.com/docker/go-units"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	DefaultLoggerLevel = logrus.InfoLevel
+
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
 type Config struct {
 	Level string `mapstructure:"level"`
+	// the format of the logs, "text" or "json"
+	Format string `mapstructure:"format"`
+	// the levels of the modules, such as "access_log.collector.ztunnel=debug,profiling=warn"
+	ModuleLevels string     `mapstructure:"module_levels"`
+	File         FileConfig `mapstructure:"file"`
+}
+
+type FileConfig struct {
+	// the path of the log file, empty means printing to the console
+	Path string `mapstructure:"path"`
+	// the max size of the log file before rotating, such as "100M", empty means never rotate
+	MaxSize string `mapstructure:"max_size"`
+	// the max count of the rotated files
+	MaxBackups int `mapstructure:"max_backups"`
+}
+
+var (
+	// levels of the modules, guarded by the module loggers lock
+	levels *levelSettings
+
+	output     *rotate.Writer
+	outputConf FileConfig
+	outputLock sync.Mutex
+)
+
+// levelSettings is the level of all modules, the module without the level uses the default level
+type levelSettings struct {
+	defaultLevel logrus.Level
+	modules      map[string]logrus.Level
+}
+
+// levelOf the module, the longest matched module prefix is used, such as the "access_log.collector"
+// matches the "access_log.collector" and "access_log.collector.ztunnel" modules
+func (s *levelSettings) levelOf(module string) logrus.Level {
+	result, matched := s.defaultLevel, -1
+	for prefix, level := range s.modules {
+		if len(prefix) <= matched {
+			continue
+		}
+		if module == prefix || strings.HasPrefix(module, prefix+".") {
+			result, matched = level, len(prefix)
+		}
+	}
+	return result
+}
+
+func parseLevelSettings(config *Config) (*levelSettings, error) {
+	level, err := logrus.ParseLevel(config.Level)
+	if err != nil {
+		return nil, err
+	}
+	result := &levelSettings{defaultLevel: level, modules: make(map[string]logrus.Level)}
+	for _, moduleLevel := range strings.Split(config.ModuleLevels, ",") {
+		if moduleLevel = strings.TrimSpace(moduleLevel); moduleLevel == "" {
+			continue
+		}
+		module, l, found := strings.Cut(moduleLevel, "=")
+		if !found {
+			return nil, fmt.Errorf("the module level must be \"module=level\": %s", moduleLevel)
+		}
+		if result.modules[strings.TrimSpace(module)], err = logrus.ParseLevel(strings.TrimSpace(l)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 // setupLogger when Bootstrap
 func setupLogger(config *Config) (err error) {
-	return updateLogger(root, config)
+	return updateLogger(config)
 }
 
-func updateLogger(log *logrus.Logger, config *Config) error {
-	level, err := logrus.ParseLevel(config.Level)
+// updateLogger apply the config to the root and all module loggers
+func updateLogger(config *Config) error {
+	settings, err := parseLevelSettings(config)
 	if err != nil {
 		return err
 	}
-	log.SetLevel(level)
+	formatter, err := buildFormatter(config.Format)
+	if err != nil {
+		return err
+	}
+	out, err := updateOutput(config.File)
+	if err != nil {
+		return err
+	}
+	moduleLoggersLock.Lock()
+	defer moduleLoggersLock.Unlock()
+	levels = settings
+	root.SetLevel(settings.defaultLevel)
+	root.SetFormatter(formatter)
+	root.SetOutput(out)
+	for module, l := range moduleLoggers {
+		l.SetLevel(settings.levelOf(module))
+		l.SetFormatter(formatter)
+		l.SetOutput(out)
+	}
 	return nil
 }
 
+func buildFormatter(format string) (logrus.Formatter, error) {
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return newTextFormatter(), nil
+	case FormatJSON:
+		return &logrus.JSONFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %s", format)
+	}
+}
+
+// updateOutput reopen the log file only when the file config is changed
+func updateOutput(conf FileConfig) (io.Writer, error) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	if output != nil && conf == outputConf {
+		return output, nil
+	}
+	var writer *rotate.Writer
+	if conf.Path != "" {
+		maxSize := int64(0)
+		if conf.MaxSize != "" {
+			size, err := units.RAMInBytes(conf.MaxSize)
+			if err != nil {
+				return nil, fmt.Errorf("parsing the max size of the log file failure: %v", err)
+			}
+			maxSize = size
+		}
+		var err error
+		if writer, err = rotate.NewWriter(conf.Path, maxSize, conf.MaxBackups); err != nil {
+			return nil, err
+		}
+	}
+	if output != nil {
+		_ = output.Close()
+	}
+	output, outputConf = writer, conf
+	if writer == nil {
+		return os.Stderr, nil
+	}
+	return writer, nil
+}
+
 func initializeDefaultLogger() *logrus.Logger {
 	l := logrus.New()
 	l.SetLevel(DefaultLoggerLevel)
-	l.SetFormatter(&logrus.TextFormatter{
+	l.SetFormatter(newTextFormatter())
+	return l
+}
+
+func newTextFormatter() logrus.Formatter {
+	return &logrus.TextFormatter{
 		FullTimestamp: true,
 		DisableColors: true,
-	})
-	return l
+	}
 }
 
 func (c *Config) IsActive() bool {
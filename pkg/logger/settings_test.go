@@ -0,0 +1,79 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package logger
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestModuleLevels(t *testing.T) {
+	settings, err := parseLevelSettings(&Config{Level: "info", ModuleLevels: "access_log=warn, access_log.collector.ztunnel=debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		module   string
+		expected logrus.Level
+	}{
+		{module: "profiling", expected: logrus.InfoLevel},
+		{module: "access_log", expected: logrus.WarnLevel},
+		{module: "access_log.collector", expected: logrus.WarnLevel},
+		{module: "access_log.collector.ztunnel", expected: logrus.DebugLevel},
+		{module: "access_log.collector.ztunnel.cache", expected: logrus.DebugLevel},
+		{module: "access_logger", expected: logrus.InfoLevel},
+	}
+	for _, tt := range tests {
+		if actual := settings.levelOf(tt.module); actual != tt.expected {
+			t.Errorf("module %s expected level: %s, actual: %s", tt.module, tt.expected, actual)
+		}
+	}
+	if _, err := parseLevelSettings(&Config{Level: "info", ModuleLevels: "access_log"}); err == nil {
+		t.Errorf("should be failure when the module level is illegal")
+	}
+}
+
+func TestModuleLoggerLevels(t *testing.T) {
+	before := GetLogger("access_log", "collector", "ztunnel")
+	if err := updateLogger(&Config{Level: "info", ModuleLevels: "access_log.collector=debug"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = updateLogger(&Config{Level: DefaultLoggerLevel.String()})
+	}()
+	after := GetLogger("access_log", "collector")
+	other := GetLogger("profiling")
+
+	tests := []struct {
+		name     string
+		log      *Logger
+		level    logrus.Level
+		expected bool
+	}{
+		{name: "created before update", log: before, level: logrus.DebugLevel, expected: true},
+		{name: "created after update", log: after, level: logrus.DebugLevel, expected: true},
+		{name: "default level", log: other, level: logrus.DebugLevel, expected: false},
+		{name: "default level info", log: other, level: logrus.InfoLevel, expected: true},
+	}
+	for _, tt := range tests {
+		if actual := tt.log.Enable(tt.level); actual != tt.expected {
+			t.Errorf("%s: expected enable %s: %t, actual: %t", tt.name, tt.level, tt.expected, actual)
+		}
+	}
+}
@@ -47,3 +47,25 @@ type Module interface {
 	// 3. The Rover receive the close SIGNAL
 	Shutdown(ctx context.Context, mgr *Manager) error
 }
+
+// ConfigReloader is optional for the Module, the module implements it could apply the dynamic settings
+// without restarting when the configuration file changed
+type ConfigReloader interface {
+	// ReloadConfig apply the new config(same type with the Config), the new config is read from the changed configuration file
+	// If any non-dynamic setting is changed, the module must return an error and keep running with the current config
+	ReloadConfig(conf ConfigInterface) error
+}
+
+// CapabilityRequirer is optional for the Module, the module implements it is not started when any required
+// kernel capability is not supported by the running kernel, instead of failing the rover
+type CapabilityRequirer interface {
+	// RequiredCapabilities are the names of the kernel capabilities, defined in the capability tool
+	RequiredCapabilities() []string
+}
+
+// DryRunner is optional for the Module, the module implements it could verify whether it could be started in the current node,
+// such as loading the BPF programs, without starting the collection
+type DryRunner interface {
+	// DryRun verify the module with the config, and release all resources before return
+	DryRun() error
+}
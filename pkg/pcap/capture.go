@@ -0,0 +1,168 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pcap
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	captureReadTimeout = time.Second
+
+	pcapMagic = 0xa1b2c3d4
+	// the packets are started with the IPv4 or IPv6 header
+	pcapLinkTypeRaw       = 101
+	pcapHeaderLen         = 24
+	pcapRecordHeaderLen   = 16
+	pcapVersionMajor      = 2
+	pcapVersionMinor      = 4
+	packetSocketNoTraffic = 0
+)
+
+// Result is the statistics of a finished capture
+type Result struct {
+	Packets int   `json:"packets"`
+	Bytes   int64 `json:"bytes"`
+	// Truncated means the capture is stopped by the size limit
+	Truncated bool `json:"truncated"`
+}
+
+// capture the packets of the tuple through the packet socket in the network namespace of the process
+type capture struct {
+	fd      int
+	snapLen int
+	buf     []byte
+}
+
+func newCapture(pid int32, tuple *Tuple, snapLen int) (*capture, error) {
+	// the socket is created without any protocol, so no packet is received before the filter attached
+	var fd int
+	var err error
+	if pid > 0 {
+		fd, err = host.SocketInNetworkNamespace(pid, unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, packetSocketNoTraffic)
+	} else {
+		fd, err = unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, packetSocketNoTraffic)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open packet socket failure: %v", err)
+	}
+	c := &capture{fd: fd, snapLen: snapLen, buf: make([]byte, snapLen)}
+	if err := c.init(tuple); err != nil {
+		_ = unix.Close(fd)
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *capture) init(tuple *Tuple) error {
+	instructions, err := buildFilter(tuple, uint32(c.snapLen))
+	if err != nil {
+		return err
+	}
+	if err := attachFilter(c.fd, instructions); err != nil {
+		return err
+	}
+	timeout := unix.NsecToTimeval(captureReadTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(c.fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		return fmt.Errorf("setting the read timeout failure: %v", err)
+	}
+	// receiving the packets of all interfaces in the network namespace
+	if err := unix.Bind(c.fd, &unix.SockaddrLinklayer{Protocol: htons(unix.ETH_P_ALL)}); err != nil {
+		return fmt.Errorf("bind the packet socket failure: %v", err)
+	}
+	return nil
+}
+
+// run the capture and write the packets as the pcap format, until the context done or the size limit reached
+func (c *capture) run(ctx context.Context, writer io.Writer, maxSize int64) (*Result, error) {
+	result := &Result{}
+	if err := writePcapHeader(writer, uint32(c.snapLen)); err != nil {
+		return result, err
+	}
+	result.Bytes = pcapHeaderLen
+	for ctx.Err() == nil {
+		// the original length of the packet is returned with the MSG_TRUNC
+		n, from, err := unix.Recvfrom(c.fd, c.buf, unix.MSG_TRUNC)
+		if err != nil {
+			if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return result, err
+		}
+		// the packets through the loopback are received twice(outgoing and incoming)
+		if link, ok := from.(*unix.SockaddrLinklayer); ok && link.Hatype == unix.ARPHRD_LOOPBACK &&
+			link.Pkttype == unix.PACKET_OUTGOING {
+			continue
+		}
+		captured := n
+		if captured > len(c.buf) {
+			captured = len(c.buf)
+		}
+		if result.Bytes+int64(pcapRecordHeaderLen+captured) > maxSize {
+			result.Truncated = true
+			return result, nil
+		}
+		if err := writePcapRecord(writer, time.Now(), c.buf[:captured], n); err != nil {
+			return result, err
+		}
+		result.Packets++
+		result.Bytes += int64(pcapRecordHeaderLen + captured)
+	}
+	return result, nil
+}
+
+func (c *capture) Close() error {
+	return unix.Close(c.fd)
+}
+
+func writePcapHeader(writer io.Writer, snapLen uint32) error {
+	header := make([]byte, pcapHeaderLen)
+	binary.LittleEndian.PutUint32(header[0:4], pcapMagic)
+	binary.LittleEndian.PutUint16(header[4:6], pcapVersionMajor)
+	binary.LittleEndian.PutUint16(header[6:8], pcapVersionMinor)
+	binary.LittleEndian.PutUint32(header[16:20], snapLen)
+	binary.LittleEndian.PutUint32(header[20:24], pcapLinkTypeRaw)
+	_, err := writer.Write(header)
+	return err
+}
+
+func writePcapRecord(writer io.Writer, ts time.Time, data []byte, originalLen int) error {
+	header := make([]byte, pcapRecordHeaderLen)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(header[4:8], uint32(ts.Nanosecond()/int(time.Microsecond)))
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(data)))
+	binary.LittleEndian.PutUint32(header[12:16], uint32(originalLen))
+	if _, err := writer.Write(header); err != nil {
+		return err
+	}
+	_, err := writer.Write(data)
+	return err
+}
+
+func htons(v uint16) uint16 {
+	return (v << 8) | (v >> 8)
+}
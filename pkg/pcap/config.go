@@ -0,0 +1,39 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pcap
+
+import "github.com/apache/skywalking-rover/pkg/module"
+
+type Config struct {
+	module.Config `mapstructure:",squash"`
+
+	// Address is the bind address of the HTTP server for requesting the captures
+	Address string `mapstructure:"address"`
+	// OutputDir is the directory of the capture files when the capture is written locally
+	OutputDir string `mapstructure:"output_dir"`
+	// MaxDuration is the max duration of a capture, the capture is stopped automatically when reached
+	MaxDuration string `mapstructure:"max_duration"`
+	// MaxSize is the max size of a capture file, the capture is stopped automatically when reached
+	MaxSize string `mapstructure:"max_size"`
+	// SnapLen is the max captured bytes of each packet
+	SnapLen int `mapstructure:"snap_len"`
+}
+
+func (c *Config) IsActive() bool {
+	return c.Active
+}
@@ -0,0 +1,232 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ipv6HeaderLen = 40
+
+	tcpProtocol = 6
+	udpProtocol = 17
+
+	labelIPv6   = "ipv6"
+	labelReject = "reject"
+)
+
+// Tuple is the connection to capture, the empty field matches any value,
+// the packets in both directions of the connection are matched
+type Tuple struct {
+	// Protocol is the transport protocol, 0 means TCP and UDP
+	Protocol   uint8
+	LocalIP    net.IP
+	LocalPort  uint16
+	RemoteIP   net.IP
+	RemotePort uint16
+}
+
+func (t *Tuple) String() string {
+	return fmt.Sprintf("protocol: %d, local: %s:%d, remote: %s:%d", t.Protocol, t.LocalIP, t.LocalPort, t.RemoteIP, t.RemotePort)
+}
+
+// family of the addresses in the tuple, 0 means any family
+func (t *Tuple) family() (int, error) {
+	family := 0
+	for _, ip := range []net.IP{t.LocalIP, t.RemoteIP} {
+		if ip == nil {
+			continue
+		}
+		current := unix.AF_INET6
+		if ip.To4() != nil {
+			current = unix.AF_INET
+		}
+		if family != 0 && family != current {
+			return 0, fmt.Errorf("the local and remote address must be the same family")
+		}
+		family = current
+	}
+	return family, nil
+}
+
+// filterBuilder assemble the packet filter with the named jump targets
+type filterBuilder struct {
+	instructions []bpf.Instruction
+	jumps        map[int][2]string
+	labels       map[string]int
+}
+
+func (b *filterBuilder) add(ins ...bpf.Instruction) {
+	b.instructions = append(b.instructions, ins...)
+}
+
+// jump to the labels by the condition, the empty label means the next instruction
+func (b *filterBuilder) jump(cond bpf.JumpTest, val uint32, trueLabel, falseLabel string) {
+	b.jumps[len(b.instructions)] = [2]string{trueLabel, falseLabel}
+	b.add(bpf.JumpIf{Cond: cond, Val: val})
+}
+
+func (b *filterBuilder) label(name string) {
+	b.labels[name] = len(b.instructions)
+}
+
+func (b *filterBuilder) resolve() ([]bpf.Instruction, error) {
+	for index, labels := range b.jumps {
+		ins := b.instructions[index].(bpf.JumpIf)
+		for i, label := range labels {
+			if label == "" {
+				continue
+			}
+			target, exist := b.labels[label]
+			if !exist || target <= index || target-index-1 > 255 {
+				return nil, fmt.Errorf("invalid jump target: %s", label)
+			}
+			if i == 0 {
+				ins.SkipTrue = uint8(target - index - 1)
+			} else {
+				ins.SkipFalse = uint8(target - index - 1)
+			}
+		}
+		b.instructions[index] = ins
+	}
+	return b.instructions, nil
+}
+
+// buildFilter build the packet filter of the tuple for the SOCK_DGRAM packet socket,
+// so the data is started with the network header
+func buildFilter(tuple *Tuple, snapLen uint32) ([]bpf.Instruction, error) {
+	family, err := tuple.family()
+	if err != nil {
+		return nil, err
+	}
+	b := &filterBuilder{jumps: make(map[int][2]string), labels: make(map[string]int)}
+	b.add(bpf.LoadAbsolute{Off: 0, Size: 1}, bpf.ALUOpConstant{Op: bpf.ALUOpShiftRight, Val: 4})
+	b.jump(bpf.JumpEqual, 4, "", labelIPv6)
+
+	// IPv4
+	if family == unix.AF_INET6 {
+		b.add(bpf.RetConstant{Val: 0})
+	} else {
+		b.buildProtocol(tuple, 9, "v4")
+		if tuple.LocalPort != 0 || tuple.RemotePort != 0 {
+			// the fragments have no transport header
+			b.add(bpf.LoadAbsolute{Off: 6, Size: 2})
+			b.jump(bpf.JumpBitsSet, 0x1fff, labelReject, "")
+			b.add(bpf.LoadMemShift{Off: 0})
+		}
+		b.buildDirection(tuple, false, "v4", snapLen)
+	}
+
+	// IPv6
+	b.label(labelIPv6)
+	if family == unix.AF_INET {
+		b.add(bpf.RetConstant{Val: 0})
+	} else {
+		b.jump(bpf.JumpNotEqual, 6, labelReject, "")
+		b.buildProtocol(tuple, 6, "v6")
+		b.buildDirection(tuple, true, "v6", snapLen)
+	}
+
+	b.label(labelReject)
+	b.add(bpf.RetConstant{Val: 0})
+	return b.resolve()
+}
+
+// buildProtocol check the transport protocol, the ports are only existing in the TCP and UDP
+func (b *filterBuilder) buildProtocol(tuple *Tuple, offset uint32, prefix string) {
+	b.add(bpf.LoadAbsolute{Off: offset, Size: 1})
+	if tuple.Protocol != 0 {
+		b.jump(bpf.JumpNotEqual, uint32(tuple.Protocol), labelReject, "")
+		return
+	}
+	matched := prefix + "_protocol"
+	b.jump(bpf.JumpEqual, tcpProtocol, matched, "")
+	b.jump(bpf.JumpNotEqual, udpProtocol, labelReject, "")
+	b.label(matched)
+}
+
+// buildDirection match the tuple in the outgoing direction, otherwise try to match in the incoming direction
+func (b *filterBuilder) buildDirection(tuple *Tuple, ipv6 bool, prefix string, snapLen uint32) {
+	incoming := prefix + "_incoming"
+	b.buildAddresses(tuple.LocalIP, tuple.LocalPort, tuple.RemoteIP, tuple.RemotePort, ipv6, incoming)
+	b.add(bpf.RetConstant{Val: snapLen})
+	b.label(incoming)
+	b.buildAddresses(tuple.RemoteIP, tuple.RemotePort, tuple.LocalIP, tuple.LocalPort, ipv6, labelReject)
+	b.add(bpf.RetConstant{Val: snapLen})
+}
+
+func (b *filterBuilder) buildAddresses(srcIP net.IP, srcPort uint16, dstIP net.IP, dstPort uint16, ipv6 bool, mismatch string) {
+	srcOffset, dstOffset := uint32(12), uint32(16)
+	if ipv6 {
+		srcOffset, dstOffset = 8, 24
+	}
+	b.buildIP(srcIP, srcOffset, ipv6, mismatch)
+	b.buildIP(dstIP, dstOffset, ipv6, mismatch)
+	b.buildPort(srcPort, 0, ipv6, mismatch)
+	b.buildPort(dstPort, 2, ipv6, mismatch)
+}
+
+func (b *filterBuilder) buildIP(ip net.IP, offset uint32, ipv6 bool, mismatch string) {
+	if ip == nil {
+		return
+	}
+	data := ip.To4()
+	if ipv6 {
+		data = ip.To16()
+	}
+	for i := 0; i < len(data); i += 4 {
+		b.add(bpf.LoadAbsolute{Off: offset + uint32(i), Size: 4})
+		b.jump(bpf.JumpNotEqual, binary.BigEndian.Uint32(data[i:i+4]), mismatch, "")
+	}
+}
+
+// buildPort check the port in the transport header, the IPv4 header length is loaded into the X register
+func (b *filterBuilder) buildPort(port uint16, offset uint32, ipv6 bool, mismatch string) {
+	if port == 0 {
+		return
+	}
+	if ipv6 {
+		b.add(bpf.LoadAbsolute{Off: ipv6HeaderLen + offset, Size: 2})
+	} else {
+		b.add(bpf.LoadIndirect{Off: offset, Size: 2})
+	}
+	b.jump(bpf.JumpNotEqual, uint32(port), mismatch, "")
+}
+
+// attachFilter attach the assembled filter to the socket
+func attachFilter(fd int, instructions []bpf.Instruction) error {
+	raw, err := bpf.Assemble(instructions)
+	if err != nil {
+		return fmt.Errorf("assemble the packet filter failure: %v", err)
+	}
+	filters := make([]unix.SockFilter, 0, len(raw))
+	for _, r := range raw {
+		filters = append(filters, unix.SockFilter{Code: r.Op, Jt: r.Jt, Jf: r.Jf, K: r.K})
+	}
+	program := &unix.SockFprog{Len: uint16(len(filters)), Filter: &filters[0]}
+	if err := unix.SetsockoptSockFprog(fd, unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, program); err != nil {
+		return fmt.Errorf("attach the packet filter failure: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pcap
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"golang.org/x/net/bpf"
+)
+
+func buildTestPacket(protocol uint8, srcIP, dstIP string, srcPort, dstPort uint16) []byte {
+	src, dst := net.ParseIP(srcIP), net.ParseIP(dstIP)
+	var data []byte
+	if src.To4() != nil {
+		data = make([]byte, 20+8)
+		data[0] = 0x45
+		data[9] = protocol
+		copy(data[12:16], src.To4())
+		copy(data[16:20], dst.To4())
+	} else {
+		data = make([]byte, ipv6HeaderLen+8)
+		data[0] = 0x60
+		data[6] = protocol
+		copy(data[8:24], src.To16())
+		copy(data[24:40], dst.To16())
+	}
+	binary.BigEndian.PutUint16(data[len(data)-8:], srcPort)
+	binary.BigEndian.PutUint16(data[len(data)-6:], dstPort)
+	return data
+}
+
+func TestBuildFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		tuple  *Tuple
+		packet []byte
+		match  bool
+	}{
+		{name: "any tcp", tuple: &Tuple{},
+			packet: buildTestPacket(tcpProtocol, "10.0.0.1", "10.0.0.2", 1234, 80), match: true},
+		{name: "not tcp or udp", tuple: &Tuple{},
+			packet: buildTestPacket(1, "10.0.0.1", "10.0.0.2", 0, 0), match: false},
+		{name: "outgoing", tuple: &Tuple{Protocol: tcpProtocol, LocalIP: net.ParseIP("10.0.0.1"), RemotePort: 80},
+			packet: buildTestPacket(tcpProtocol, "10.0.0.1", "10.0.0.2", 1234, 80), match: true},
+		{name: "incoming", tuple: &Tuple{Protocol: tcpProtocol, LocalIP: net.ParseIP("10.0.0.1"), RemotePort: 80},
+			packet: buildTestPacket(tcpProtocol, "10.0.0.2", "10.0.0.1", 80, 1234), match: true},
+		{name: "other port", tuple: &Tuple{LocalIP: net.ParseIP("10.0.0.1"), RemotePort: 80},
+			packet: buildTestPacket(tcpProtocol, "10.0.0.1", "10.0.0.2", 1234, 8080), match: false},
+		{name: "other protocol", tuple: &Tuple{Protocol: udpProtocol},
+			packet: buildTestPacket(tcpProtocol, "10.0.0.1", "10.0.0.2", 1234, 80), match: false},
+		{name: "ipv6 full tuple", tuple: &Tuple{LocalIP: net.ParseIP("fd00::1"), LocalPort: 1234,
+			RemoteIP: net.ParseIP("fd00::2"), RemotePort: 80},
+			packet: buildTestPacket(udpProtocol, "fd00::2", "fd00::1", 80, 1234), match: true},
+		{name: "ipv6 other address", tuple: &Tuple{RemoteIP: net.ParseIP("fd00::2")},
+			packet: buildTestPacket(tcpProtocol, "fd00::1", "fd00::3", 80, 1234), match: false},
+		{name: "ipv4 tuple with ipv6 packet", tuple: &Tuple{RemoteIP: net.ParseIP("10.0.0.2")},
+			packet: buildTestPacket(tcpProtocol, "fd00::1", "fd00::2", 80, 1234), match: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions, err := buildFilter(tt.tuple, 65535)
+			if err != nil {
+				t.Fatalf("build filter failure: %v", err)
+			}
+			vm, err := bpf.NewVM(instructions)
+			if err != nil {
+				t.Fatalf("load filter failure: %v", err)
+			}
+			accepted, err := vm.Run(tt.packet)
+			if err != nil {
+				t.Fatalf("run filter failure: %v", err)
+			}
+			if (accepted > 0) != tt.match {
+				t.Fatalf("expected match: %t, actual accepted: %d", tt.match, accepted)
+			}
+		})
+	}
+
+	if _, err := buildFilter(&Tuple{LocalIP: net.ParseIP("10.0.0.1"), RemoteIP: net.ParseIP("fd00::1")}, 65535); err == nil {
+		t.Fatalf("the mixed address family should be failure")
+	}
+}
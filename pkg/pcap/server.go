@@ -0,0 +1,282 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pcap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/go-units"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+)
+
+var log = logger.GetLogger("pcap")
+
+const (
+	defaultCaptureDuration = 10 * time.Second
+
+	outputStream = "stream"
+	outputFile   = "file"
+)
+
+// Server accept the capture requests through the HTTP, only one capture is running at the same time
+type Server struct {
+	config          *Config
+	processOperator process.Operator
+	maxDuration     time.Duration
+	maxSize         int64
+
+	server    *http.Server
+	capturing atomic.Bool
+}
+
+// request is the parsed capture request
+type request struct {
+	pid      int32
+	tuple    *Tuple
+	duration time.Duration
+	maxSize  int64
+	output   string
+}
+
+type fileResponse struct {
+	*Result
+	File string `json:"file"`
+}
+
+func NewServer(mgr *module.Manager, config *Config) (*Server, error) {
+	maxDuration, err := time.ParseDuration(config.MaxDuration)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the max duration failure: %v", err)
+	}
+	maxSize, err := units.RAMInBytes(config.MaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the max size failure: %v", err)
+	}
+	if config.SnapLen <= 0 {
+		return nil, fmt.Errorf("the snap length must be positive")
+	}
+	s := &Server{
+		config:          config,
+		processOperator: mgr.FindModule(process.ModuleName).(process.Operator),
+		maxDuration:     maxDuration,
+		maxSize:         maxSize,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pcap", s.handleCapture)
+	s.server = &http.Server{
+		Addr:              config.Address,
+		ReadHeaderTimeout: 3 * time.Second,
+		Handler:           mux,
+	}
+	return s, nil
+}
+
+// Start listen the address synchronously, so the module start failure when the address cannot be bound
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("listen the pcap server address %s failure: %v", s.config.Address, err)
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Errorf("the pcap server is stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) handleCapture(writer http.ResponseWriter, req *http.Request) {
+	r, err := s.parseRequest(req)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.capturing.CompareAndSwap(false, true) {
+		http.Error(writer, "another capture is running", http.StatusTooManyRequests)
+		return
+	}
+	defer s.capturing.Store(false)
+
+	c, err := newCapture(r.pid, r.tuple, s.config.SnapLen)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer c.Close()
+	log.Infof("start capture the packets, pid: %d, %s, duration: %s, max size: %d", r.pid, r.tuple, r.duration, r.maxSize)
+
+	// the capture is stopped when the client disconnected
+	ctx, cancel := context.WithTimeout(req.Context(), r.duration)
+	defer cancel()
+	if r.output == outputFile {
+		s.captureToFile(ctx, writer, c, r)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/vnd.tcpdump.pcap")
+	result, err := c.run(ctx, &flushWriter{writer: writer}, r.maxSize)
+	if err != nil {
+		log.Warnf("capture the packets failure: %v", err)
+		return
+	}
+	log.Infof("the capture is finished, packets: %d, bytes: %d", result.Packets, result.Bytes)
+}
+
+func (s *Server) captureToFile(ctx context.Context, writer http.ResponseWriter, c *capture, r *request) {
+	if err := os.MkdirAll(s.config.OutputDir, 0o700); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	path := filepath.Join(s.config.OutputDir, fmt.Sprintf("rover-%d-%s.pcap", r.pid, time.Now().Format("20060102150405")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+	result, err := c.run(ctx, file, r.maxSize)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Infof("the capture is written to %s, packets: %d, bytes: %d", path, result.Packets, result.Bytes)
+	writer.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(writer).Encode(&fileResponse{Result: result, File: path})
+}
+
+func (s *Server) parseRequest(req *http.Request) (*request, error) {
+	query := req.URL.Query()
+	r := &request{duration: defaultCaptureDuration, maxSize: s.maxSize, output: outputStream, tuple: &Tuple{}}
+	var err error
+	if pid := query.Get("pid"); pid != "" {
+		value, e := strconv.ParseInt(pid, 10, 32)
+		if e != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid pid: %s", pid)
+		}
+		r.pid = int32(value)
+	} else if service := query.Get("service"); service != "" {
+		if r.pid, err = s.findServicePid(service); err != nil {
+			return nil, err
+		}
+	}
+	if err = parseTuple(query.Get, r.tuple); err != nil {
+		return nil, err
+	}
+	if duration := query.Get("duration"); duration != "" {
+		if r.duration, err = time.ParseDuration(duration); err != nil || r.duration <= 0 {
+			return nil, fmt.Errorf("invalid duration: %s", duration)
+		}
+	}
+	if r.duration > s.maxDuration {
+		r.duration = s.maxDuration
+	}
+	if size := query.Get("max_size"); size != "" {
+		value, e := units.RAMInBytes(size)
+		if e != nil || value <= pcapHeaderLen {
+			return nil, fmt.Errorf("invalid max size: %s", size)
+		}
+		if value < r.maxSize {
+			r.maxSize = value
+		}
+	}
+	switch output := query.Get("output"); output {
+	case "", outputStream:
+	case outputFile:
+		r.output = outputFile
+	default:
+		return nil, fmt.Errorf("unknown output: %s", output)
+	}
+	return r, nil
+}
+
+// findServicePid find the first monitoring process of the service, the packets are captured in its network namespace
+func (s *Server) findServicePid(service string) (int32, error) {
+	for _, p := range s.processOperator.FindAllRegisteredProcesses() {
+		if p.Entity().ServiceName == service {
+			return p.Pid(), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot found the process of service: %s", service)
+}
+
+// parseTuple read the tuple from the request parameters
+func parseTuple(get func(string) string, tuple *Tuple) error {
+	switch protocol := strings.ToLower(get("protocol")); protocol {
+	case "":
+	case "tcp":
+		tuple.Protocol = tcpProtocol
+	case "udp":
+		tuple.Protocol = udpProtocol
+	default:
+		return fmt.Errorf("unknown protocol: %s", protocol)
+	}
+	for _, f := range []struct {
+		name string
+		ip   *net.IP
+	}{{"local_ip", &tuple.LocalIP}, {"remote_ip", &tuple.RemoteIP}} {
+		if value := get(f.name); value != "" {
+			if *f.ip = net.ParseIP(value); *f.ip == nil {
+				return fmt.Errorf("invalid %s: %s", f.name, value)
+			}
+		}
+	}
+	for _, f := range []struct {
+		name string
+		port *uint16
+	}{{"local_port", &tuple.LocalPort}, {"remote_port", &tuple.RemotePort}} {
+		if value := get(f.name); value != "" {
+			port, err := strconv.ParseUint(value, 10, 16)
+			if err != nil || port == 0 {
+				return fmt.Errorf("invalid %s: %s", f.name, value)
+			}
+			*f.port = uint16(port)
+		}
+	}
+	_, err := tuple.family()
+	return err
+}
+
+// flushWriter flush each write, then the packets are streamed to the client timely
+type flushWriter struct {
+	writer io.Writer
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.writer.Write(p)
+	if flusher, ok := f.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
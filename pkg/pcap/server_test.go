@@ -0,0 +1,58 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pcap
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerStart(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen the occupied address failure: %v", err)
+	}
+	defer occupied.Close()
+
+	tests := []struct {
+		name    string
+		address string
+		failure bool
+	}{
+		{name: "free address", address: "127.0.0.1:0"},
+		{name: "address in use", address: occupied.Addr().String(), failure: true},
+		{name: "invalid address", address: "127.0.0.1:-1", failure: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{config: &Config{Address: tt.address}, server: &http.Server{Handler: http.NewServeMux(), ReadHeaderTimeout: time.Second}}
+			err := s.Start()
+			if (err != nil) != tt.failure {
+				t.Fatalf("expected start failure: %t, actual error: %v", tt.failure, err)
+			}
+			if err == nil {
+				if err := s.Stop(context.Background()); err != nil {
+					t.Errorf("stop the server failure: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package podtraffic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cilium/ebpf"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/tools/btf"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+// $BPF_CLANG and $BPF_CFLAGS are set by the Makefile.
+// nolint
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -no-global-types -target $TARGET -cc $BPF_CLANG -cflags $BPF_CFLAGS bpf $REPO_ROOT/bpf/podtraffic/tc.c -- -I$REPO_ROOT/bpf/include
+
+var log = logger.GetLogger("podtraffic")
+
+// the direction is based on the host interface, the ingress of the host veth is the egress of the pod
+const (
+	hostDirectionIngress uint32 = 0
+	hostDirectionEgress  uint32 = 1
+)
+
+type trafficKey struct {
+	Ifindex   uint32
+	Direction uint32
+}
+
+type trafficValue struct {
+	Bytes   uint64
+	Packets uint64
+}
+
+type serviceInstance struct {
+	service  string
+	instance string
+}
+
+// monitoredInterface is the host veth interface of the pod which is attached the traffic accounting programs
+type monitoredInterface struct {
+	netNS      uint64
+	podName    string
+	instances  map[serviceInstance]bool
+	attachment tcAttachment
+	last       map[uint32]trafficValue
+}
+
+// Collector accounting the bytes and packets of the pod interfaces through the traffic control programs,
+// which includes the traffic that not passing through the socket, such as the forwarded or dropped packets
+type Collector struct {
+	processOperator process.Operator
+	sender          *reporter.MeterSender
+	reportPeriod    time.Duration
+	meterPrefix     string
+
+	bpf        *bpfObjects
+	attacher   *tcAttacher
+	interfaces map[uint32]*monitoredInterface
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewCollector(mgr *module.Manager, config *Config) (*Collector, error) {
+	reportPeriod, err := time.ParseDuration(config.ReportPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parsing report period failure: %v", err)
+	}
+	if config.MeterPrefix == "" {
+		return nil, fmt.Errorf("please provide the meter prefix")
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	return &Collector{
+		processOperator: mgr.FindModule(process.ModuleName).(process.Operator),
+		sender:          reporter.NewMeterSender("pod traffic metrics", coreOperator.BackendOperator().GetConnection()),
+		reportPeriod:    reportPeriod,
+		meterPrefix:     config.MeterPrefix + "_",
+		interfaces:      make(map[uint32]*monitoredInterface),
+	}, nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	objs := &bpfObjects{}
+	if err := btf.LoadBPFAndAssign(loadBpf, objs); err != nil {
+		return fmt.Errorf("load the pod traffic BPF program failure: %v", err)
+	}
+	c.bpf = objs
+	c.attacher = newTCAttacher(objs.TcIngress, objs.TcEgress)
+
+	reporter.StartPeriodic(c.ctx, "pod traffic", c.reportPeriod, c.report)
+	return nil
+}
+
+func (c *Collector) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	var result error
+	for ifindex, iface := range c.interfaces {
+		if err := iface.attachment.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("detach the pod traffic program from interface %d failure: %v", ifindex, err))
+		}
+	}
+	if c.bpf != nil {
+		if err := c.bpf.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// syncInterfaces attaching the programs to the host veth of new pods, and detaching from the removed pods
+func (c *Collector) syncInterfaces() {
+	hostNetNS, err := host.NetworkNamespaceInode(1)
+	if err != nil {
+		log.Warnf("read the network namespace of host failure: %v", err)
+		return
+	}
+	netNSInterfaces := make(map[uint64][]*podInterface)
+	current := make(map[uint32]*monitoredInterface)
+	for _, p := range c.processOperator.FindAllRegisteredProcesses() {
+		netNS, err := host.NetworkNamespaceInode(p.Pid())
+		// the pod using the host network has no dedicated interface
+		if err != nil || netNS == hostNetNS {
+			continue
+		}
+		podInterfaces, exist := netNSInterfaces[netNS]
+		if !exist {
+			if podInterfaces, err = readPodInterfaces(p.Pid()); err != nil {
+				log.Debugf("read the interfaces of process %d failure: %v", p.Pid(), err)
+			}
+			netNSInterfaces[netNS] = podInterfaces
+		}
+		instance := serviceInstance{service: p.Entity().ServiceName, instance: p.Entity().InstanceName}
+		for _, podIface := range podInterfaces {
+			iface := current[podIface.PeerIndex]
+			if iface == nil {
+				iface = &monitoredInterface{netNS: netNS, podName: podIface.Name, instances: make(map[serviceInstance]bool)}
+				current[podIface.PeerIndex] = iface
+			}
+			iface.instances[instance] = true
+		}
+	}
+
+	for ifindex, iface := range c.interfaces {
+		// the interface index could be reused by the new pod
+		if newIface := current[ifindex]; newIface != nil && newIface.netNS == iface.netNS {
+			continue
+		}
+		c.detach(ifindex, iface)
+	}
+	for ifindex, iface := range current {
+		if exist := c.interfaces[ifindex]; exist != nil {
+			exist.podName, exist.instances = iface.podName, iface.instances
+			continue
+		}
+		attachment, err := c.attacher.Attach(ifindex)
+		if err != nil {
+			log.Warnf("attach the pod traffic program to interface %d(%s in pod) failure: %v", ifindex, iface.podName, err)
+			continue
+		}
+		iface.attachment = attachment
+		iface.last = make(map[uint32]trafficValue)
+		c.interfaces[ifindex] = iface
+	}
+}
+
+func (c *Collector) detach(ifindex uint32, iface *monitoredInterface) {
+	if err := iface.attachment.Close(); err != nil {
+		log.Warnf("detach the pod traffic program from interface %d failure: %v", ifindex, err)
+	}
+	for _, direction := range []uint32{hostDirectionIngress, hostDirectionEgress} {
+		key := trafficKey{Ifindex: ifindex, Direction: direction}
+		if err := c.bpf.InterfaceTraffic.Delete(&key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			log.Warnf("delete the traffic of interface %d failure: %v", ifindex, err)
+		}
+	}
+	delete(c.interfaces, ifindex)
+}
+
+func (c *Collector) report(ctx context.Context) error {
+	c.syncInterfaces()
+	if len(c.interfaces) == 0 {
+		return nil
+	}
+	var key trafficKey
+	var perCPUValues []trafficValue
+	deltas := make(map[trafficKey]trafficValue)
+	iterator := c.bpf.InterfaceTraffic.Iterate()
+	for iterator.Next(&key, &perCPUValues) {
+		iface := c.interfaces[key.Ifindex]
+		if iface == nil {
+			continue
+		}
+		var total trafficValue
+		for _, v := range perCPUValues {
+			total.Bytes += v.Bytes
+			total.Packets += v.Packets
+		}
+		deltas[key] = trafficDelta(iface.last[key.Direction], total)
+		iface.last[key.Direction] = total
+	}
+	if err := iterator.Err(); err != nil {
+		return fmt.Errorf("read the pod traffic failure: %v", err)
+	}
+
+	meters := make(map[serviceInstance][]*v3.MeterData)
+	for ifindex, iface := range c.interfaces {
+		podEgress := deltas[trafficKey{Ifindex: ifindex, Direction: hostDirectionIngress}]
+		podIngress := deltas[trafficKey{Ifindex: ifindex, Direction: hostDirectionEgress}]
+		for instance := range iface.instances {
+			meters[instance] = append(meters[instance],
+				c.buildMeter("ingress_bytes", iface.podName, float64(podIngress.Bytes)),
+				c.buildMeter("ingress_packets", iface.podName, float64(podIngress.Packets)),
+				c.buildMeter("egress_bytes", iface.podName, float64(podEgress.Bytes)),
+				c.buildMeter("egress_packets", iface.podName, float64(podEgress.Packets)))
+		}
+	}
+	now := time.Now().UnixMilli()
+	collections := make([]*v3.MeterDataCollection, 0, len(meters))
+	for metadata, data := range meters {
+		data[0].Service = metadata.service
+		data[0].ServiceInstance = metadata.instance
+		data[0].Timestamp = now
+		collections = append(collections, &v3.MeterDataCollection{MeterData: data})
+	}
+	return c.sender.Send(ctx, collections)
+}
+
+func (c *Collector) buildMeter(name, iface string, value float64) *v3.MeterData {
+	return reporter.SingleValueMeter(c.meterPrefix+name, []*v3.Label{{Name: "interface", Value: iface}}, value)
+}
+
+// trafficDelta is the increased traffic since the last read, the counter is restarted when the map entry recreated
+func trafficDelta(last, current trafficValue) trafficValue {
+	if current.Bytes < last.Bytes || current.Packets < last.Packets {
+		return current
+	}
+	return trafficValue{Bytes: current.Bytes - last.Bytes, Packets: current.Packets - last.Packets}
+}
@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package podtraffic
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+// the rtnetlink link constants, defined in the linux/rtnetlink.h and linux/if_link.h
+const (
+	ifInfoMsgLength = 16
+	iflaIfName      = 3
+	iflaLink        = 5
+	iflaLinkInfo    = 18
+	iflaInfoKind    = 1
+	vethKind        = "veth"
+)
+
+// podInterface is the veth interface in the pod, the traffic is accounted through the peer interface in the host
+type podInterface struct {
+	Name      string
+	Index     uint32
+	PeerIndex uint32
+}
+
+// readPodInterfaces reading all veth interfaces in the network namespace of the process
+func readPodInterfaces(pid int32) ([]*podInterface, error) {
+	netNS, err := os.Open(host.GetHostProcInHost(fmt.Sprintf("%d/ns/net", pid)))
+	if err != nil {
+		return nil, err
+	}
+	defer netNS.Close()
+	conn, err := netlink.Dial(unix.NETLINK_ROUTE, &netlink.Config{NetNS: int(netNS.Fd())})
+	if err != nil {
+		return nil, fmt.Errorf("dial the route netlink in the network namespace of process %d failure: %v", pid, err)
+	}
+	defer conn.Close()
+
+	messages, err := conn.Execute(netlink.Message{
+		Header: netlink.Header{Type: unix.RTM_GETLINK, Flags: netlink.Request | netlink.Dump},
+		Data:   make([]byte, ifInfoMsgLength),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query the links in the network namespace of process %d failure: %v", pid, err)
+	}
+	result := make([]*podInterface, 0)
+	for _, m := range messages {
+		iface, err := parseLinkMessage(m.Data)
+		if err != nil {
+			return nil, err
+		}
+		if iface != nil {
+			result = append(result, iface)
+		}
+	}
+	return result, nil
+}
+
+// parseLinkMessage parse the veth interface from the RTM_NEWLINK message,
+// return nil if the link is not a veth interface or the peer is unknown
+func parseLinkMessage(data []byte) (*podInterface, error) {
+	if len(data) < ifInfoMsgLength {
+		return nil, fmt.Errorf("the link message is too short: %d", len(data))
+	}
+	decoder, err := netlink.NewAttributeDecoder(data[ifInfoMsgLength:])
+	if err != nil {
+		return nil, err
+	}
+	iface := &podInterface{Index: nlenc.Uint32(data[4:8])}
+	var kind string
+	for decoder.Next() {
+		switch decoder.Type() {
+		case iflaIfName:
+			iface.Name = decoder.String()
+		case iflaLink:
+			iface.PeerIndex = decoder.Uint32()
+		case iflaLinkInfo:
+			decoder.Nested(func(info *netlink.AttributeDecoder) error {
+				for info.Next() {
+					if info.Type() == iflaInfoKind {
+						kind = info.String()
+					}
+				}
+				return nil
+			})
+		}
+	}
+	if err := decoder.Err(); err != nil {
+		return nil, err
+	}
+	if kind != vethKind || iface.PeerIndex == 0 {
+		return nil, nil
+	}
+	return iface, nil
+}
@@ -0,0 +1,113 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package podtraffic
+
+import (
+	"testing"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+)
+
+func buildLinkMessage(t *testing.T, index uint32, name, kind string, peer uint32) []byte {
+	encoder := netlink.NewAttributeEncoder()
+	encoder.String(iflaIfName, name)
+	if peer > 0 {
+		encoder.Uint32(iflaLink, peer)
+	}
+	if kind != "" {
+		encoder.Nested(iflaLinkInfo, func(info *netlink.AttributeEncoder) error {
+			info.String(iflaInfoKind, kind)
+			return nil
+		})
+	}
+	attrs, err := encoder.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make([]byte, ifInfoMsgLength)
+	nlenc.PutUint32(header[4:8], index)
+	return append(header, attrs...)
+}
+
+func TestParseLinkMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *podInterface
+		hasError bool
+	}{
+		{
+			name:     "veth",
+			data:     buildLinkMessage(t, 3, "eth0", "veth", 12),
+			expected: &podInterface{Name: "eth0", Index: 3, PeerIndex: 12},
+		},
+		{
+			name: "loopback",
+			data: buildLinkMessage(t, 1, "lo", "", 0),
+		},
+		{
+			name: "non veth with link",
+			data: buildLinkMessage(t, 4, "ipip0", "ipip", 2),
+		},
+		{
+			name:     "too short",
+			data:     []byte{0, 0, 0},
+			hasError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseLinkMessage(tt.data)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("expected error: %t, actual: %v", tt.hasError, err)
+			}
+			if tt.expected == nil {
+				if actual != nil {
+					t.Errorf("expected nil, actual: %v", actual)
+				}
+				return
+			}
+			if actual == nil || *actual != *tt.expected {
+				t.Errorf("expected: %v, actual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTrafficDelta(t *testing.T) {
+	tests := []struct {
+		name     string
+		last     trafficValue
+		current  trafficValue
+		expected trafficValue
+	}{
+		{name: "increased", last: trafficValue{Bytes: 100, Packets: 2}, current: trafficValue{Bytes: 250, Packets: 5},
+			expected: trafficValue{Bytes: 150, Packets: 3}},
+		{name: "restarted", last: trafficValue{Bytes: 100, Packets: 2}, current: trafficValue{Bytes: 40, Packets: 1},
+			expected: trafficValue{Bytes: 40, Packets: 1}},
+		{name: "first read", current: trafficValue{Bytes: 40, Packets: 1}, expected: trafficValue{Bytes: 40, Packets: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := trafficDelta(tt.last, tt.current); actual != tt.expected {
+				t.Errorf("expected: %v, actual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package podtraffic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+
+	"golang.org/x/sys/unix"
+)
+
+// the traffic control constants, defined in the linux/pkt_sched.h, linux/rtnetlink.h and linux/pkt_cls.h
+const (
+	tcMsgLength         = 20
+	tcaKind             = 1
+	tcaOptions          = 2
+	tcaBPFFD            = 6
+	tcaBPFName          = 7
+	tcaBPFFlags         = 8
+	tcaBPFFlagActDirect = 1
+	tcHandleClsact      = 0xFFFF0000
+	tcParentClsact      = 0xFFFFFFF1
+	tcParentIngress     = 0xFFFFFFF2
+	tcParentEgress      = 0xFFFFFFF3
+	tcFilterHandle      = 1
+	tcFilterPriority    = 0xC000
+	tcFilterIngressName = "rover_pod_traffic_ingress"
+	tcFilterEgressName  = "rover_pod_traffic_egress"
+	tcClsactQdiscKind   = "clsact"
+	tcBPFClassifierKind = "bpf"
+)
+
+type tcAttachMode int
+
+const (
+	tcAttachModeUnknown tcAttachMode = iota
+	tcAttachModeTCX
+	tcAttachModeNetlink
+)
+
+// tcAttacher attaching the traffic accounting programs to the host interfaces,
+// prefer the TCX(kernel 6.6+) and fallback to the clsact qdisc with the BPF classifier
+type tcAttacher struct {
+	ingress *ebpf.Program
+	egress  *ebpf.Program
+	mode    tcAttachMode
+}
+
+func newTCAttacher(ingress, egress *ebpf.Program) *tcAttacher {
+	return &tcAttacher{ingress: ingress, egress: egress}
+}
+
+// tcAttachment is the ingress and egress programs attached to a host interface
+type tcAttachment interface {
+	Close() error
+}
+
+func (a *tcAttacher) Attach(ifindex uint32) (tcAttachment, error) {
+	if a.mode != tcAttachModeNetlink {
+		attachment, err := a.attachTCX(ifindex)
+		if err == nil {
+			a.mode = tcAttachModeTCX
+			return attachment, nil
+		}
+		if a.mode == tcAttachModeTCX || !errors.Is(err, ebpf.ErrNotSupported) {
+			return nil, err
+		}
+		log.Infof("the TCX is not supported in current kernel, attach the pod traffic program through the clsact qdisc")
+		a.mode = tcAttachModeNetlink
+	}
+	return a.attachNetlink(ifindex)
+}
+
+type tcxAttachment struct {
+	links []link.Link
+}
+
+func (t *tcxAttachment) Close() error {
+	var result error
+	for _, l := range t.links {
+		if err := l.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (a *tcAttacher) attachTCX(ifindex uint32) (tcAttachment, error) {
+	attachment := &tcxAttachment{}
+	for _, program := range []struct {
+		prog   *ebpf.Program
+		attach ebpf.AttachType
+	}{{a.ingress, ebpf.AttachTCXIngress}, {a.egress, ebpf.AttachTCXEgress}} {
+		// attach to the head, then the traffic is accounted before redirected by other programs
+		l, err := link.AttachTCX(link.TCXOptions{
+			Interface: int(ifindex),
+			Program:   program.prog,
+			Attach:    program.attach,
+			Anchor:    link.Head(),
+		})
+		if err != nil {
+			_ = attachment.Close()
+			return nil, err
+		}
+		attachment.links = append(attachment.links, l)
+	}
+	return attachment, nil
+}
+
+type netlinkAttachment struct {
+	ifindex uint32
+}
+
+func (n *netlinkAttachment) Close() error {
+	conn, err := netlink.Dial(unix.NETLINK_ROUTE, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var result error
+	for _, parent := range []uint32{tcParentIngress, tcParentEgress} {
+		encoder := netlink.NewAttributeEncoder()
+		encoder.String(tcaKind, tcBPFClassifierKind)
+		err := tcExecute(conn, unix.RTM_DELTFILTER, 0, buildTCMessage(n.ifindex, tcFilterHandle, parent, tcFilterInfo()), encoder)
+		// the filters are removed with the interface
+		if err != nil && !errors.Is(err, unix.ENODEV) && !errors.Is(err, unix.ENOENT) && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (a *tcAttacher) attachNetlink(ifindex uint32) (tcAttachment, error) {
+	conn, err := netlink.Dial(unix.NETLINK_ROUTE, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial the route netlink failure: %v", err)
+	}
+	defer conn.Close()
+
+	// the clsact qdisc could be shared with other programs, so it is not deleted when detach
+	qdisc := netlink.NewAttributeEncoder()
+	qdisc.String(tcaKind, tcClsactQdiscKind)
+	err = tcExecute(conn, unix.RTM_NEWQDISC, netlink.Create|netlink.Excl, buildTCMessage(ifindex, tcHandleClsact, tcParentClsact, 0), qdisc)
+	if err != nil && !errors.Is(err, unix.EEXIST) {
+		return nil, fmt.Errorf("add the clsact qdisc to interface %d failure: %v", ifindex, err)
+	}
+
+	attachment := &netlinkAttachment{ifindex: ifindex}
+	for _, filter := range []struct {
+		prog   *ebpf.Program
+		parent uint32
+		name   string
+	}{
+		{a.ingress, tcParentIngress, tcFilterIngressName},
+		{a.egress, tcParentEgress, tcFilterEgressName},
+	} {
+		encoder := netlink.NewAttributeEncoder()
+		encoder.String(tcaKind, tcBPFClassifierKind)
+		encoder.Nested(tcaOptions, func(options *netlink.AttributeEncoder) error {
+			options.Uint32(tcaBPFFD, uint32(filter.prog.FD()))
+			options.String(tcaBPFName, filter.name)
+			options.Uint32(tcaBPFFlags, tcaBPFFlagActDirect)
+			return nil
+		})
+		// replace the filter which is left by the previous rover
+		err := tcExecute(conn, unix.RTM_NEWTFILTER, netlink.Create|netlink.Replace,
+			buildTCMessage(ifindex, tcFilterHandle, filter.parent, tcFilterInfo()), encoder)
+		if err != nil {
+			_ = attachment.Close()
+			return nil, fmt.Errorf("add the BPF filter %s to interface %d failure: %v", filter.name, ifindex, err)
+		}
+	}
+	return attachment, nil
+}
+
+func tcExecute(conn *netlink.Conn, msgType netlink.HeaderType, flags netlink.HeaderFlags,
+	tcMsg []byte, encoder *netlink.AttributeEncoder) error {
+	attrs, err := encoder.Encode()
+	if err != nil {
+		return err
+	}
+	_, err = conn.Execute(netlink.Message{
+		Header: netlink.Header{Type: msgType, Flags: netlink.Request | netlink.Acknowledge | flags},
+		Data:   append(tcMsg, attrs...),
+	})
+	return err
+}
+
+// buildTCMessage build the tcmsg struct, the fields are in the native endian
+func buildTCMessage(ifindex, handle, parent, info uint32) []byte {
+	data := make([]byte, tcMsgLength)
+	data[0] = unix.AF_UNSPEC
+	nlenc.PutUint32(data[4:8], ifindex)
+	nlenc.PutUint32(data[8:12], handle)
+	nlenc.PutUint32(data[12:16], parent)
+	nlenc.PutUint32(data[16:20], info)
+	return data
+}
+
+// tcFilterInfo is the priority and the protocol(network byte order) of the filter
+func tcFilterInfo() uint32 {
+	protocol := make([]byte, 2)
+	binary.BigEndian.PutUint16(protocol, unix.ETH_P_ALL)
+	return tcFilterPriority<<16 | uint32(nlenc.Uint16(protocol))
+}
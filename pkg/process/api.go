@@ -39,4 +39,6 @@ type K8sOperator interface {
 	NodeName() string
 	// IsPodIP check the ip is pod ip
 	IsPodIP(ip string) (bool, error)
+	// FindServiceByClusterIP find the kubernetes service name("name.namespace") through the cluster IP, return empty if not found
+	FindServiceByClusterIP(ip string) string
 }
@@ -31,11 +31,15 @@ type ProcessDetectType int8
 const (
 	_ ProcessDetectType = iota
 	Kubernetes
+	VM
 )
 
 func (d ProcessDetectType) Name() string {
-	if d == Kubernetes {
+	switch d {
+	case Kubernetes:
 		return "Kubernetes"
+	case VM:
+		return "VM"
 	}
 	return "not matched"
 }
@@ -20,6 +20,7 @@ package process
 import (
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/process/finders/kubernetes"
+	"github.com/apache/skywalking-rover/pkg/process/finders/vm"
 )
 
 type Config struct {
@@ -32,6 +33,7 @@ type Config struct {
 	PropertiesReportPeriod int `mapstructure:"properties_report_period"`
 
 	Kubernetes *kubernetes.Config `mapstructure:"kubernetes"`
+	VM         *vm.Config         `mapstructure:"vm"`
 }
 
 func (c *Config) IsActive() bool {
@@ -0,0 +1,166 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/process/finders/kubernetes"
+)
+
+// Config of the process filter, all the empty settings are ignored
+type Config struct {
+	// The kubernetes namespaces(split by ",") to include or exclude
+	IncludeNamespaces string `mapstructure:"include_namespaces"`
+	ExcludeNamespaces string `mapstructure:"exclude_namespaces"`
+	// The kubernetes label selector of the pod to include or exclude
+	IncludePodLabels string `mapstructure:"include_pod_labels"`
+	ExcludePodLabels string `mapstructure:"exclude_pod_labels"`
+	// The regex of the process name(entity process name or the execute file name) to include or exclude
+	IncludeProcessNames string `mapstructure:"include_process_names"`
+	ExcludeProcessNames string `mapstructure:"exclude_process_names"`
+}
+
+// Filter decides whether the process should be monitored,
+// the namespace and pod label rules only work for the kubernetes processes
+type Filter struct {
+	includeNamespaces map[string]bool
+	excludeNamespaces map[string]bool
+	includePodLabels  labels.Selector
+	excludePodLabels  labels.Selector
+	includeNames      *regexp.Regexp
+	excludeNames      *regexp.Regexp
+}
+
+// New the process filter, return nil(means including all processes) when the config is empty
+func New(conf *Config) (*Filter, error) {
+	if conf == nil || *conf == (Config{}) {
+		return nil, nil
+	}
+	f := &Filter{
+		includeNamespaces: splitToSet(conf.IncludeNamespaces),
+		excludeNamespaces: splitToSet(conf.ExcludeNamespaces),
+	}
+	var err error
+	if f.includePodLabels, err = parseSelector(conf.IncludePodLabels); err != nil {
+		return nil, fmt.Errorf("parsing the include pod labels error: %v", err)
+	}
+	if f.excludePodLabels, err = parseSelector(conf.ExcludePodLabels); err != nil {
+		return nil, fmt.Errorf("parsing the exclude pod labels error: %v", err)
+	}
+	if f.includeNames, err = parseRegex(conf.IncludeProcessNames); err != nil {
+		return nil, fmt.Errorf("parsing the include process names error: %v", err)
+	}
+	if f.excludeNames, err = parseRegex(conf.ExcludeProcessNames); err != nil {
+		return nil, fmt.Errorf("parsing the exclude process names error: %v", err)
+	}
+	return f, nil
+}
+
+// Match the process should be monitored, the nil filter matches all processes
+func (f *Filter) Match(p api.ProcessInterface) bool {
+	if f == nil {
+		return true
+	}
+	if p.DetectType() == api.Kubernetes {
+		if kp, ok := p.DetectProcess().(*kubernetes.Process); ok && !f.matchPod(kp) {
+			return false
+		}
+	}
+	names := []string{p.Entity().ProcessName}
+	if exe, err := p.ExeName(); err == nil {
+		names = append(names, exe)
+	}
+	if f.includeNames != nil && !matchAny(f.includeNames, names) {
+		return false
+	}
+	if f.excludeNames != nil && matchAny(f.excludeNames, names) {
+		return false
+	}
+	return true
+}
+
+func (f *Filter) matchPod(p *kubernetes.Process) bool {
+	pod := p.PodContainer().Pod
+	if len(f.includeNamespaces) > 0 && !f.includeNamespaces[pod.Namespace] {
+		return false
+	}
+	if f.excludeNamespaces[pod.Namespace] {
+		return false
+	}
+	podLabels := labels.Set(pod.Labels)
+	if f.includePodLabels != nil && !f.includePodLabels.Matches(podLabels) {
+		return false
+	}
+	if f.excludePodLabels != nil && f.excludePodLabels.Matches(podLabels) {
+		return false
+	}
+	return true
+}
+
+// Processes filter the matched processes
+func (f *Filter) Processes(processes []api.ProcessInterface) []api.ProcessInterface {
+	if f == nil {
+		return processes
+	}
+	result := make([]api.ProcessInterface, 0, len(processes))
+	for _, p := range processes {
+		if f.Match(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func splitToSet(value string) map[string]bool {
+	result := make(map[string]bool)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result[v] = true
+		}
+	}
+	return result
+}
+
+func parseSelector(value string) (labels.Selector, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return labels.Parse(value)
+}
+
+func parseRegex(value string) (*regexp.Regexp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return regexp.Compile(value)
+}
+
+func matchAny(r *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if v != "" && r.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/process"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/process/finders/kubernetes"
+	"github.com/apache/skywalking-rover/pkg/tools/profiling"
+)
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    *Config
+		process *testProcess
+		match   bool
+	}{
+		{
+			name:    "empty config",
+			conf:    &Config{},
+			process: newTestPodProcess("default", map[string]string{"app": "order"}, "java"),
+			match:   true,
+		},
+		{
+			name:    "include namespace",
+			conf:    &Config{IncludeNamespaces: "prod, default"},
+			process: newTestPodProcess("default", nil, "java"),
+			match:   true,
+		},
+		{
+			name:    "not included namespace",
+			conf:    &Config{IncludeNamespaces: "prod"},
+			process: newTestPodProcess("default", nil, "java"),
+			match:   false,
+		},
+		{
+			name:    "exclude namespace",
+			conf:    &Config{ExcludeNamespaces: "kube-system,default"},
+			process: newTestPodProcess("default", nil, "java"),
+			match:   false,
+		},
+		{
+			name:    "include pod labels",
+			conf:    &Config{IncludePodLabels: "app in (order,payment)"},
+			process: newTestPodProcess("default", map[string]string{"app": "order"}, "java"),
+			match:   true,
+		},
+		{
+			name:    "exclude pod labels",
+			conf:    &Config{ExcludePodLabels: "sidecar.istio.io/inject=false"},
+			process: newTestPodProcess("default", map[string]string{"sidecar.istio.io/inject": "false"}, "java"),
+			match:   false,
+		},
+		{
+			name:    "exclude process name",
+			conf:    &Config{ExcludeProcessNames: "^pilot-agent$|^envoy$"},
+			process: newTestPodProcess("default", nil, "envoy"),
+			match:   false,
+		},
+		{
+			name:    "include process name",
+			conf:    &Config{IncludeProcessNames: "^java$"},
+			process: newTestPodProcess("default", nil, "python"),
+			match:   false,
+		},
+		{
+			name:    "pod rules ignored for non kubernetes process",
+			conf:    &Config{IncludeNamespaces: "prod", IncludeProcessNames: "^java$"},
+			process: &testProcess{detectType: api.VM, entity: &api.ProcessEntity{ProcessName: "java"}},
+			match:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if match := f.Match(tt.process); match != tt.match {
+				t.Fatalf("want match: %t, got: %t", tt.match, match)
+			}
+		})
+	}
+}
+
+func newTestPodProcess(namespace string, podLabels map[string]string, processName string) *testProcess {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "demo", Labels: podLabels}}
+	entity := &api.ProcessEntity{ProcessName: processName}
+	return &testProcess{
+		detectType: api.Kubernetes,
+		entity:     entity,
+		detected:   kubernetes.NewProcess(&process.Process{Pid: 1}, "", &kubernetes.PodContainer{Pod: pod}, entity),
+	}
+}
+
+type testProcess struct {
+	detectType api.ProcessDetectType
+	entity     *api.ProcessEntity
+	detected   api.DetectedProcess
+}
+
+func (t *testProcess) ID() string                         { return "" }
+func (t *testProcess) Pid() int32                         { return 1 }
+func (t *testProcess) DetectType() api.ProcessDetectType  { return t.detectType }
+func (t *testProcess) Entity() *api.ProcessEntity         { return t.entity }
+func (t *testProcess) ProfilingStat() *profiling.Info     { return nil }
+func (t *testProcess) ExeName() (string, error)           { return t.entity.ProcessName, nil }
+func (t *testProcess) OriginalProcess() *process.Process  { return nil }
+func (t *testProcess) DetectProcess() api.DetectedProcess { return t.detected }
+func (t *testProcess) PortIsExpose(int) bool              { return false }
+func (t *testProcess) DetectNewExposePort(int)            {}
+func (t *testProcess) ExposeHosts() []string              { return nil }
@@ -0,0 +1,77 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// containerIDRegex matches a 64 characters container ID at the end of a cgroup path segment, the optional prefix
+// is the runtime name, such as "cri-containerd-", "crio-", "docker-", "libpod-" or "cri-containerd:"
+var containerIDRegex = regexp.MustCompile(`^(?:.*[-:])?([0-9a-f]{64})(?:\.scope)?$`)
+
+// parseCGroupContainerIDs parsing the content of "/proc/<pid>/cgroup" and return the container IDs of each hierarchy,
+// both cgroup v1("<id>:<controllers>:<path>") and cgroup v2("0::<path>") are supported.
+// The path could be:
+// 1. cgroupfs driver: /kubepods/burstable/pod<uid>/<id>, /docker/<id>
+// 2. systemd driver: /kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod<uid>.slice/cri-containerd-<id>.scope
+// 3. containerd with systemd driver: /system.slice/containerd.service/kubepods-burstable-pod<uid>.slice:cri-containerd:<id>
+// 4. nested cgroup under the container scope: /system.slice/docker-<id>.scope/init.scope
+// 5. relative to the cgroup namespace of the rover: /../../kubepods-pod<uid>.slice/cri-containerd-<id>.scope
+func parseCGroupContainerIDs(data []byte) []string {
+	cgroups := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		infos := strings.SplitN(scanner.Text(), ":", 3)
+		if len(infos) < 3 {
+			continue
+		}
+		if id := parseCGroupPathContainerID(infos[2]); id != "" {
+			cgroups[id] = true
+		}
+	}
+	result := make([]string, 0, len(cgroups))
+	for k := range cgroups {
+		result = append(result, k)
+	}
+	return result
+}
+
+func parseCGroupPathContainerID(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	// the container scope is usually the last segment, but the container could create nested cgroups under it
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := segments[i]
+		// the conmon process of CRI-O is monitoring the container, not the container itself
+		if strings.HasPrefix(segment, "crio-conmon-") {
+			return ""
+		}
+		if matches := containerIDRegex.FindStringSubmatch(segment); len(matches) > 1 {
+			return matches[1]
+		}
+	}
+	// keep the last segment for the runtimes which are not using the 64 characters container ID
+	last := segments[len(segments)-1]
+	if len(segments) < 2 || last == "" || last == ".." || strings.HasSuffix(last, ".slice") {
+		return ""
+	}
+	return last
+}
@@ -0,0 +1,102 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kubernetes
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const testContainerID = "7dae778c37bd1204677518f1032bbecf01f5c41878ea7bd370021263417cc626"
+
+func TestParseCGroupContainerIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup string
+		want   []string
+	}{
+		{
+			name:   "containerd cgroup v2 with systemd driver",
+			cgroup: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1b2c.slice/cri-containerd-" + testContainerID + ".scope\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name:   "containerd cgroup v2 under containerd service",
+			cgroup: "0::/system.slice/containerd.service/kubepods-besteffort-pod1b2c.slice:cri-containerd:" + testContainerID + "\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name:   "containerd cgroup v2 relative to cgroup namespace",
+			cgroup: "0::/../../kubepods-besteffort-pod1b2c.slice/cri-containerd-" + testContainerID + ".scope\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name:   "cri-o cgroup v2",
+			cgroup: "0::/kubepods.slice/kubepods-pod1b2c.slice/crio-" + testContainerID + ".scope\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name:   "cri-o cgroup v2 with nested container cgroup",
+			cgroup: "0::/kubepods.slice/kubepods-pod1b2c.slice/crio-" + testContainerID + ".scope/container\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name:   "cri-o conmon",
+			cgroup: "0::/kubepods.slice/kubepods-pod1b2c.slice/crio-conmon-" + testContainerID + ".scope\n",
+			want:   []string{},
+		},
+		{
+			name:   "docker cgroup v2 with systemd driver",
+			cgroup: "0::/system.slice/docker-" + testContainerID + ".scope\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name:   "docker cgroup v2 with systemd inside container",
+			cgroup: "0::/system.slice/docker-" + testContainerID + ".scope/init.scope\n",
+			want:   []string{testContainerID},
+		},
+		{
+			name: "docker cgroup v1 with cgroupfs driver",
+			cgroup: "12:pids:/docker/" + testContainerID + "\n" +
+				"11:cpu,cpuacct:/docker/" + testContainerID + "\n" +
+				"1:name=systemd:/docker/" + testContainerID + "\n",
+			want: []string{testContainerID},
+		},
+		{
+			name: "kubernetes cgroup v1 with cgroupfs driver",
+			cgroup: "12:memory:/kubepods/burstable/pod1b2c/" + testContainerID + "\n" +
+				"0::/\n",
+			want: []string{testContainerID},
+		},
+		{
+			name:   "host process",
+			cgroup: "0::/user.slice/user-1000.slice/user@1000.service/app.slice\n0::/init.scope\n0::/\n",
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCGroupContainerIDs([]byte(tt.cgroup))
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("want: %v, got: %v", tt.want, got)
+			}
+		})
+	}
+}
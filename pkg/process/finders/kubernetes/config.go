@@ -31,6 +31,7 @@ type Config struct {
 	NodeName   string            `mapstructure:"node_name"`
 	Namespaces string            `mapstructure:"namespaces"`
 	Analyzers  []*ProcessBuilder `mapstructure:"analyzers"`
+	CRI        *CRIConfig        `mapstructure:"cri"`
 }
 
 type ProcessBuilder struct {
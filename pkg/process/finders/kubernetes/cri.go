@@ -0,0 +1,186 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	criPodNamespaceLabel = "io.kubernetes.pod.namespace"
+	criPodNameLabel      = "io.kubernetes.pod.name"
+	criPodUIDLabel       = "io.kubernetes.pod.uid"
+	criContainerLabel    = "io.kubernetes.container.name"
+
+	defaultCRITimeout = time.Second * 2
+)
+
+// the well-known CRI runtime sockets, used when the endpoint is not declared
+var defaultCRIEndpoints = []string{
+	"/run/containerd/containerd.sock",
+	"/run/k3s/containerd/containerd.sock",
+	"/run/crio/crio.sock",
+	"/var/run/cri-dockerd.sock",
+}
+
+type CRIConfig struct {
+	Active   bool   `mapstructure:"active"`
+	Endpoint string `mapstructure:"endpoint"`
+	Timeout  string `mapstructure:"timeout"`
+}
+
+// CRIContainer is the kubernetes metadata of the container which reported by the container runtime
+type CRIContainer struct {
+	ID            string
+	Namespace     string
+	PodName       string
+	PodUID        string
+	ContainerName string
+}
+
+// CRIClient query the running containers from the local container runtime(containerd, CRI-O) through the CRI API
+type CRIClient struct {
+	endpoint string
+	timeout  time.Duration
+	conn     *grpc.ClientConn
+	client   runtimeapi.RuntimeServiceClient
+}
+
+func NewCRIClient(ctx context.Context, conf *CRIConfig) (*CRIClient, error) {
+	timeout := defaultCRITimeout
+	if conf.Timeout != "" {
+		t, err := time.ParseDuration(conf.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the CRI timeout error: %v", err)
+		}
+		timeout = t
+	}
+	endpoint, err := findCRIEndpoint(conf.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient("unix://"+endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("connect to the CRI endpoint %s error: %v", endpoint, err)
+	}
+	c := &CRIClient{
+		endpoint: endpoint,
+		timeout:  timeout,
+		conn:     conn,
+		client:   runtimeapi.NewRuntimeServiceClient(conn),
+	}
+
+	// make sure the runtime supports the CRI v1 API
+	versionCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	version, err := c.client.Version(versionCtx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("query the version from CRI endpoint %s error: %v", endpoint, err)
+	}
+	log.Infof("connected to the container runtime %s(%s) through CRI endpoint: %s",
+		version.RuntimeName, version.RuntimeVersion, endpoint)
+	return c, nil
+}
+
+// Containers query all running containers which created by the kubernetes, the key is the container ID
+func (c *CRIClient) Containers(ctx context.Context) (map[string]*CRIContainer, error) {
+	listCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	resp, err := c.client.ListContainers(listCtx, &runtimeapi.ListContainersRequest{
+		Filter: &runtimeapi.ContainerFilter{
+			State: &runtimeapi.ContainerStateValue{State: runtimeapi.ContainerState_CONTAINER_RUNNING},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list containers from CRI endpoint %s error: %v", c.endpoint, err)
+	}
+	result := make(map[string]*CRIContainer, len(resp.Containers))
+	for _, container := range resp.Containers {
+		if cc := newCRIContainer(container); cc != nil {
+			result[cc.ID] = cc
+		}
+	}
+	return result, nil
+}
+
+func (c *CRIClient) Close() error {
+	return c.conn.Close()
+}
+
+func newCRIContainer(container *runtimeapi.Container) *CRIContainer {
+	labels := container.Labels
+	if labels[criPodNamespaceLabel] == "" || labels[criPodNameLabel] == "" || labels[criContainerLabel] == "" {
+		return nil
+	}
+	return &CRIContainer{
+		ID:            container.Id,
+		Namespace:     labels[criPodNamespaceLabel],
+		PodName:       labels[criPodNameLabel],
+		PodUID:        labels[criPodUIDLabel],
+		ContainerName: labels[criContainerLabel],
+	}
+}
+
+func findCRIEndpoint(endpoint string) (string, error) {
+	if endpoint != "" {
+		return strings.TrimPrefix(endpoint, "unix://"), nil
+	}
+	for _, e := range defaultCRIEndpoints {
+		if info, err := os.Stat(e); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("could not found the CRI endpoint from: %s", strings.Join(defaultCRIEndpoints, ", "))
+}
+
+// MergeCRIContainers adding the containers which reported by the container runtime into the cgroup ID based containers,
+// the container ID of the pod status may be absent or out of date(such as the container just restarted),
+// so the pod container is matched by the pod and container name
+func MergeCRIContainers(containers map[string]*PodContainer, criContainers map[string]*CRIContainer) {
+	if len(criContainers) == 0 {
+		return
+	}
+	podContainers := make(map[string]*PodContainer, len(containers))
+	for _, c := range containers {
+		podContainers[criContainerKey(c.Pod.Namespace, c.Pod.Name, c.ContainerSpec.Name)] = c
+	}
+	for id, cc := range criContainers {
+		if containers[id] != nil {
+			continue
+		}
+		pc := podContainers[criContainerKey(cc.Namespace, cc.PodName, cc.ContainerName)]
+		if pc == nil || (cc.PodUID != "" && string(pc.Pod.UID) != cc.PodUID) {
+			continue
+		}
+		containers[id] = pc
+	}
+}
+
+func criContainerKey(namespace, podName, containerName string) string {
+	return namespace + "/" + podName + "/" + containerName
+}
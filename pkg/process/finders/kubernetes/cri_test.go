@@ -0,0 +1,81 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kubernetes
+
+import (
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMergeCRIContainers(t *testing.T) {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "demo", UID: types.UID("uid-1")}}
+	container := &PodContainer{Pod: pod, ContainerSpec: v1.Container{Name: "app"}}
+	tests := []struct {
+		name          string
+		criContainers map[string]*CRIContainer
+		want          []string
+	}{
+		{
+			name: "restarted container",
+			criContainers: map[string]*CRIContainer{
+				"new": {ID: "new", Namespace: "default", PodName: "demo", PodUID: "uid-1", ContainerName: "app"},
+			},
+			want: []string{"new", "old"},
+		},
+		{
+			name: "pod recreated with same name",
+			criContainers: map[string]*CRIContainer{
+				"new": {ID: "new", Namespace: "default", PodName: "demo", PodUID: "uid-2", ContainerName: "app"},
+			},
+			want: []string{"old"},
+		},
+		{
+			name: "unknown container",
+			criContainers: map[string]*CRIContainer{
+				"new": {ID: "new", Namespace: "default", PodName: "demo", PodUID: "uid-1", ContainerName: "sidecar"},
+			},
+			want: []string{"old"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers := map[string]*PodContainer{"old": container}
+			MergeCRIContainers(containers, tt.criContainers)
+			ids := make([]string, 0, len(containers))
+			for id, c := range containers {
+				if c != container {
+					t.Fatalf("the container %s is mapped to the wrong pod container", id)
+				}
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			if len(ids) != len(tt.want) {
+				t.Fatalf("want: %v, got: %v", tt.want, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("want: %v, got: %v", tt.want, ids)
+				}
+			}
+		})
+	}
+}
@@ -18,12 +18,9 @@
 package kubernetes
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"hash/fnv"
-	"os"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -51,16 +48,14 @@ import (
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/process/api"
 	"github.com/apache/skywalking-rover/pkg/process/finders/base"
-	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
 )
 
 var log = logger.GetLogger("process", "finder", "kubernetes")
 
 var (
-	kubepodsRegex      = regexp.MustCompile(`cri-containerd-(?P<Group>\w+)\.scope`)
-	openShiftPodsRegex = regexp.MustCompile(`crio-(?P<Group>\w+)\.scope`)
-	ipExistTimeout     = time.Minute * 10
-	ipSearchParallel   = 10
+	ipExistTimeout   = time.Minute * 10
+	ipSearchParallel = 10
 )
 
 type ProcessFinder struct {
@@ -78,6 +73,8 @@ type ProcessFinder struct {
 	k8sConfig *rest.Config
 	registry  Registry
 	CLI       *kubernetes.Clientset
+	// the optional container runtime client
+	cri *CRIClient
 
 	// for IsPodIP check
 	podIPChecker *cache.Expiring
@@ -124,6 +121,12 @@ func (f *ProcessFinder) InitWithRegistry(ctx context.Context, conf base.FinderBa
 	}
 	f.processCache = processCache
 
+	if f.conf.CRI != nil && f.conf.CRI.Active {
+		if f.cri, err = NewCRIClient(ctx, f.conf.CRI); err != nil {
+			log.Warnf("could not use the CRI to mapping the containers, only use the pod status: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -173,12 +176,12 @@ func (f *ProcessFinder) Start() {
 
 func (f *ProcessFinder) analyzeProcesses() error {
 	// find out all containers
-	containers := f.registry.BuildPodContainers()
+	containers := f.buildPodContainers()
 	if len(containers) == 0 {
 		return nil
 	}
 
-	processes, err := process.Processes()
+	processes, err := procfs.Processes()
 	if err != nil {
 		return err
 	}
@@ -194,6 +197,20 @@ func (f *ProcessFinder) analyzeProcesses() error {
 	return nil
 }
 
+func (f *ProcessFinder) buildPodContainers() map[string]*PodContainer {
+	containers := f.registry.BuildPodContainers()
+	if f.cri == nil || len(containers) == 0 {
+		return containers
+	}
+	criContainers, err := f.cri.Containers(f.ctx)
+	if err != nil {
+		log.Warnf("query containers from CRI failure: %v", err)
+		return containers
+	}
+	MergeCRIContainers(containers, criContainers)
+	return containers
+}
+
 func (f *ProcessFinder) buildProcess(p *process.Process, detectedProcesses []api.DetectedProcess,
 	containers map[string]*PodContainer) ([]api.DetectedProcess, bool) {
 	createTime, err := p.CreateTime()
@@ -292,47 +309,24 @@ func (f *ProcessFinder) buildEntity(err error, ps *process.Process, pc *PodConta
 }
 
 func (f *ProcessFinder) GetProcessCGroup(pid int32) ([]string, error) {
-	processCgroupFilePath := host.GetHostProcInHost(fmt.Sprintf("%d/cgroup", pid))
-	cgroupFile, err := os.Open(processCgroupFilePath)
+	cgroupData, err := procfs.ReadFile(pid, "cgroup")
 	if err != nil {
 		return nil, err
 	}
-	defer cgroupFile.Close()
 
-	cgroups := make(map[string]bool)
-	scanner := bufio.NewScanner(cgroupFile)
-	for scanner.Scan() {
-		infos := strings.Split(scanner.Text(), ":")
-		if len(infos) < 3 {
-			continue
-		}
-		lastPath := strings.LastIndex(infos[2], "/")
-		if lastPath > 1 && lastPath != len(infos[2])-1 {
-			path := infos[2][lastPath+1:]
-			// adapt Kubepod
-			// ex: cri-containerd-7dae778c37bd1204677518f1032bbecf01f5c41878ea7bd370021263417cc626.scope
-			if kubepod := kubepodsRegex.FindStringSubmatch(path); len(kubepod) >= 1 {
-				path = kubepod[1]
-			}
-			if openShiftPod := openShiftPodsRegex.FindStringSubmatch(path); len(openShiftPod) >= 1 {
-				path = openShiftPod[1]
-			}
-			cgroups[path] = true
-		}
-	}
+	cgroups := parseCGroupContainerIDs(cgroupData)
 	if len(cgroups) == 0 {
 		return nil, fmt.Errorf("no cgroups")
 	}
-	result := make([]string, 0)
-	for k := range cgroups {
-		result = append(result, k)
-	}
-	return result, nil
+	return cgroups, nil
 }
 
 func (f *ProcessFinder) Stop() error {
 	close(f.stopChan)
 	f.cancelCtx()
+	if f.cri != nil {
+		return f.cri.Close()
+	}
 	return nil
 }
 
@@ -415,7 +409,7 @@ func (f *ProcessFinder) ShouldMonitor(pid int32) bool {
 		return false
 	}
 	// analyze the process needs to be monitored
-	processes, monitor := f.buildProcess(newProcess, nil, f.registry.BuildPodContainers())
+	processes, monitor := f.buildProcess(newProcess, nil, f.buildPodContainers())
 	if !monitor || len(processes) == 0 {
 		return false
 	}
@@ -423,6 +417,10 @@ func (f *ProcessFinder) ShouldMonitor(pid int32) bool {
 	return true
 }
 
+func (f *ProcessFinder) FindServiceByClusterIP(ip string) string {
+	return f.registry.FindServiceByClusterIP(ip)
+}
+
 func (f *ProcessFinder) IsPodIP(ip string) (bool, error) {
 	val, exist := f.podIPChecker.Get(ip)
 	if exist {
@@ -35,6 +35,8 @@ type Registry interface {
 	Start(stopChan chan struct{})
 	BuildPodContainers() map[string]*PodContainer
 	FindServiceName(namespace, podName string) string
+	// FindServiceByClusterIP find the service name("name.namespace") through the cluster IP of the service
+	FindServiceByClusterIP(ip string) string
 }
 
 type StaticNamespaceRegistry struct {
@@ -42,6 +44,7 @@ type StaticNamespaceRegistry struct {
 	serviceInformers []cache.SharedInformer
 
 	podServiceNameCache map[string]string
+	clusterIPCache      map[string]string
 }
 
 func NewStaticNamespaceRegistry(cli *kubernetes.Clientset, namespaces []string, nodeName string) Registry {
@@ -49,6 +52,7 @@ func NewStaticNamespaceRegistry(cli *kubernetes.Clientset, namespaces []string,
 		podInformers:        make([]cache.SharedInformer, 0),
 		serviceInformers:    make([]cache.SharedInformer, 0),
 		podServiceNameCache: make(map[string]string),
+		clusterIPCache:      make(map[string]string),
 	}
 	for _, ns := range namespaces {
 		podListWatch := cache.NewListWatchFromClient(cli.CoreV1().RESTClient(), "pods", ns, fields.OneTermEqualSelector("spec.nodeName", nodeName))
@@ -94,8 +98,24 @@ func (r *StaticNamespaceRegistry) FindServiceName(namespace, podName string) str
 	return r.podServiceNameCache[namespace+"_"+podName]
 }
 
+func (r *StaticNamespaceRegistry) FindServiceByClusterIP(ip string) string {
+	return r.clusterIPCache[ip]
+}
+
 func (r *StaticNamespaceRegistry) recomposePodServiceName() {
 	result := make(map[string]string)
+	clusterIPs := make(map[string]string)
+	for i := range r.serviceInformers {
+		for _, serviceT := range r.serviceInformers[i].GetStore().List() {
+			service := serviceT.(*v1.Service)
+			for _, ip := range service.Spec.ClusterIPs {
+				if ip != "" && ip != v1.ClusterIPNone {
+					clusterIPs[ip] = service.Name + "." + service.Namespace
+				}
+			}
+		}
+	}
+	r.clusterIPCache = clusterIPs
 	for i := range r.podInformers {
 		for _, podT := range r.podInformers[i].GetStore().List() {
 			for _, serviceT := range r.serviceInformers[i].GetStore().List() {
@@ -21,6 +21,7 @@ import (
 	"reflect"
 
 	"github.com/apache/skywalking-rover/pkg/process/finders/kubernetes"
+	"github.com/apache/skywalking-rover/pkg/process/finders/vm"
 
 	"github.com/apache/skywalking-rover/pkg/process/finders/base"
 )
@@ -29,6 +30,7 @@ var finders = make(map[reflect.Type]base.ProcessFinder)
 
 func init() {
 	registerFinder(reflect.TypeOf(&kubernetes.Config{}), &kubernetes.ProcessFinder{})
+	registerFinder(reflect.TypeOf(&vm.Config{}), &vm.ProcessFinder{})
 }
 
 func registerFinder(t reflect.Type, finder base.ProcessFinder) {
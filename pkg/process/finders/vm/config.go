@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package vm
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/process/finders/base"
+)
+
+type Config struct {
+	base.FinderBaseConfig
+
+	Active bool `mapstructure:"active"`
+
+	// Period of scanning all processes in the host
+	Period    string            `mapstructure:"period"`
+	Processes []*ProcessBuilder `mapstructure:"processes"`
+
+	// runtime
+	period time.Duration
+}
+
+// ProcessBuilder declares how to match the process and build the process entity,
+// the process must match all the declared regex
+type ProcessBuilder struct {
+	ExePath      string `mapstructure:"exe_path"`
+	CommandLine  string `mapstructure:"command_line"`
+	Environment  string `mapstructure:"environment"`
+	Layer        string `mapstructure:"layer"`
+	ServiceName  string `mapstructure:"service_name"`
+	InstanceName string `mapstructure:"instance_name"`
+	ProcessName  string `mapstructure:"process_name"`
+	LabelsStr    string `mapstructure:"labels"`
+
+	// runtime
+	ExePathRegex        *regexp.Regexp
+	CommandLineRegex    *regexp.Regexp
+	EnvironmentRegex    *regexp.Regexp
+	ServiceNameBuilder  *base.TemplateBuilder
+	InstanceNameBuilder *base.TemplateBuilder
+	ProcessNameBuilder  *base.TemplateBuilder
+	Labels              []string
+}
+
+func (c *Config) ActiveFinder() bool {
+	return c != nil && c.Active
+}
+
+func (c *Config) init() error {
+	var err error
+	c.period, err = base.DurationMustNotNull(nil, "period", c.Period)
+	if err != nil {
+		return err
+	}
+	if len(c.Processes) == 0 {
+		return fmt.Errorf("the processes of VM finder must be set")
+	}
+	for _, b := range c.Processes {
+		if err := b.init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *ProcessBuilder) init() error {
+	if b.ExePath == "" && b.CommandLine == "" && b.Environment == "" {
+		return fmt.Errorf("at least one of the exe path, command line or environment regex must be set")
+	}
+	var err error
+	if b.ExePathRegex, err = optionalRegex("exe path", b.ExePath); err != nil {
+		return err
+	}
+	if b.CommandLineRegex, err = optionalRegex("command line", b.CommandLine); err != nil {
+		return err
+	}
+	if b.EnvironmentRegex, err = optionalRegex("environment", b.Environment); err != nil {
+		return err
+	}
+	b.ServiceNameBuilder, err = base.TemplateMustNotNull(err, "service name", b.ServiceName)
+	b.InstanceNameBuilder, err = base.TemplateMustNotNull(err, "instance name", b.InstanceName)
+	b.ProcessNameBuilder, err = base.TemplateMustNotNull(err, "process name", b.ProcessName)
+	err = base.StringMustNotNull(err, "layer", b.Layer)
+	b.Labels = base.ParseLabels(b.LabelsStr)
+	if err != nil {
+		return fmt.Errorf("build process finder error: %v", err)
+	}
+	return nil
+}
+
+func optionalRegex(name, value string) (*regexp.Regexp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	r, err := regexp.Compile(value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the %s regex error: %s, %v", name, value, err)
+	}
+	return r, nil
+}
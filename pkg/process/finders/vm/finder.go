@@ -0,0 +1,260 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/shirou/gopsutil/process"
+
+	commonv3 "skywalking.apache.org/repo/goapi/collect/common/v3"
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/process/v3"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/process/finders/base"
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
+)
+
+var log = logger.GetLogger("process", "finder", "vm")
+
+// ProcessFinder detect the processes running in the host(outside the kubernetes) through the declared regex
+type ProcessFinder struct {
+	conf *Config
+
+	// runtime
+	manager      base.ProcessManager
+	ctx          context.Context
+	cancelCtx    context.CancelFunc
+	processCache *lru.Cache
+}
+
+func (f *ProcessFinder) Init(ctx context.Context, conf base.FinderBaseConfig, manager base.ProcessManager) error {
+	f.conf = conf.(*Config)
+	if err := f.conf.init(); err != nil {
+		return err
+	}
+	f.manager = manager
+	f.ctx, f.cancelCtx = context.WithCancel(ctx)
+	processCache, err := lru.New(5000)
+	if err != nil {
+		return err
+	}
+	f.processCache = processCache
+	return nil
+}
+
+func (f *ProcessFinder) Start() {
+	go func() {
+		timeTicker := time.NewTicker(f.conf.period)
+		for {
+			select {
+			case <-timeTicker.C:
+				if err := f.analyzeProcesses(); err != nil {
+					log.Errorf("found process failure: %v", err)
+				}
+			case <-f.ctx.Done():
+				timeTicker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (f *ProcessFinder) analyzeProcesses() error {
+	processes, err := procfs.Processes()
+	if err != nil {
+		return err
+	}
+
+	result := make([]api.DetectedProcess, 0)
+	for _, p := range processes {
+		result, _ = f.buildProcess(p, result)
+	}
+
+	f.manager.SyncAllProcessInFinder(result)
+	return nil
+}
+
+func (f *ProcessFinder) buildProcess(p *process.Process, detectedProcesses []api.DetectedProcess) ([]api.DetectedProcess, bool) {
+	if int(p.Pid) == os.Getpid() {
+		return detectedProcesses, false
+	}
+	createTime, err := p.CreateTime()
+	if err != nil {
+		return detectedProcesses, false
+	}
+	processCacheKey := fmt.Sprintf("%d_%d", p.Pid, createTime)
+	if cached, exist := f.processCache.Get(processCacheKey); exist {
+		if cached == nil {
+			return detectedProcesses, false
+		}
+		return append(detectedProcesses, cached.(*Process)), true
+	}
+
+	ps, err := f.matchProcess(p)
+	if err != nil {
+		log.Warnf("build the process entity error for pid: %d, err: %v", p.Pid, err)
+		return detectedProcesses, false
+	}
+	if ps == nil {
+		// not matched process would never be matched, until the pid is reused
+		f.processCache.Add(processCacheKey, nil)
+		return detectedProcesses, false
+	}
+	f.processCache.Add(processCacheKey, ps)
+	return append(detectedProcesses, ps), true
+}
+
+func (f *ProcessFinder) matchProcess(p *process.Process) (*Process, error) {
+	exe, err := p.Exe()
+	if err != nil {
+		return nil, nil
+	}
+	cmdline, err := p.Cmdline()
+	if err != nil {
+		return nil, nil
+	}
+	environ := func() []string {
+		envs, _ := p.Environ()
+		return envs
+	}
+	for _, b := range f.conf.Processes {
+		if !b.matches(exe, cmdline, environ) {
+			continue
+		}
+		entity := &api.ProcessEntity{
+			Layer:  b.Layer,
+			Labels: b.Labels,
+		}
+		if entity.ServiceName, err = renderTemplate(b.ServiceNameBuilder, p, f); err != nil {
+			return nil, err
+		}
+		if entity.InstanceName, err = renderTemplate(b.InstanceNameBuilder, p, f); err != nil {
+			return nil, err
+		}
+		if entity.ProcessName, err = renderTemplate(b.ProcessNameBuilder, p, f); err != nil {
+			return nil, err
+		}
+		return NewProcess(p, cmdline, entity), nil
+	}
+	return nil, nil
+}
+
+// matches the process must match all declared regex, the environment matches any "key=value" of the process
+func (b *ProcessBuilder) matches(exe, cmdline string, environ func() []string) bool {
+	if b.ExePathRegex != nil && !b.ExePathRegex.MatchString(exe) {
+		return false
+	}
+	if b.CommandLineRegex != nil && !b.CommandLineRegex.MatchString(cmdline) {
+		return false
+	}
+	if b.EnvironmentRegex != nil {
+		for _, env := range environ() {
+			if b.EnvironmentRegex.MatchString(env) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (f *ProcessFinder) Stop() error {
+	f.cancelCtx()
+	return nil
+}
+
+func (f *ProcessFinder) DetectType() api.ProcessDetectType {
+	return api.VM
+}
+
+func (f *ProcessFinder) ShouldMonitor(pid int32) bool {
+	newProcess, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+	processes, monitor := f.buildProcess(newProcess, nil)
+	if !monitor || len(processes) == 0 {
+		return false
+	}
+	f.manager.AddDetectedProcess(processes)
+	return true
+}
+
+func (f *ProcessFinder) ValidateProcessIsSame(p1, p2 api.DetectedProcess) bool {
+	v1 := p1.(*Process)
+	v2 := p2.(*Process)
+	return p1.Pid() == p2.Pid() && v1.cmd == v2.cmd && p1.Entity().SameWith(p2.Entity())
+}
+
+func (f *ProcessFinder) BuildNecessaryProperties(ps api.DetectedProcess) []*commonv3.KeyStringValuePair {
+	return []*commonv3.KeyStringValuePair{
+		{
+			Key:   "support_ebpf_profiling",
+			Value: strconv.FormatBool(ps.ProfilingStat() != nil),
+		},
+	}
+}
+
+func (f *ProcessFinder) BuildEBPFProcess(ctx *base.BuildEBPFProcessContext, ps api.DetectedProcess) *v3.EBPFProcessProperties {
+	hostProcess := &v3.EBPFHostProcessMetadata{}
+	hostProcess.Pid = ps.Pid()
+	hostProcess.Entity = &v3.EBPFProcessEntityMetadata{
+		Layer:        ps.Entity().Layer,
+		ServiceName:  ps.Entity().ServiceName,
+		InstanceName: ps.Entity().InstanceName,
+		ProcessName:  ps.Entity().ProcessName,
+		Labels:       ps.Entity().Labels,
+	}
+	hostProcess.Properties = []*commonv3.KeyStringValuePair{
+		{
+			Key:   "host_ip",
+			Value: ctx.HostIP,
+		},
+		{
+			Key:   "pid",
+			Value: strconv.FormatInt(int64(ps.Pid()), 10),
+		},
+		{
+			Key:   "command_line",
+			Value: ps.(*Process).cmd,
+		},
+	}
+	hostProcess.Properties = append(hostProcess.Properties, f.BuildNecessaryProperties(ps)...)
+	return &v3.EBPFProcessProperties{Metadata: &v3.EBPFProcessProperties_HostProcess{
+		HostProcess: hostProcess,
+	}}
+}
+
+func (f *ProcessFinder) ParseProcessID(ps api.DetectedProcess, downstream *v3.EBPFProcessDownstream) string {
+	if downstream.GetHostProcess() == nil {
+		return ""
+	}
+	if ps.Pid() == downstream.GetHostProcess().GetPid() &&
+		base.EntityIsSameWithProtocol(ps.Entity(), downstream.GetHostProcess().GetEntityMetadata()) {
+		return downstream.GetProcessId()
+	}
+	return ""
+}
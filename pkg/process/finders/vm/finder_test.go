@@ -0,0 +1,81 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package vm
+
+import (
+	"testing"
+)
+
+func TestProcessBuilderMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		builder     *ProcessBuilder
+		exe         string
+		cmdline     string
+		environment []string
+		matches     bool
+	}{
+		{
+			name:    "exe path",
+			builder: &ProcessBuilder{ExePath: `/java$`},
+			exe:     "/usr/lib/jvm/bin/java",
+			cmdline: "java -jar app.jar",
+			matches: true,
+		},
+		{
+			name:    "exe path and command line",
+			builder: &ProcessBuilder{ExePath: `/java$`, CommandLine: `-jar order\.jar`},
+			exe:     "/usr/lib/jvm/bin/java",
+			cmdline: "java -jar app.jar",
+			matches: false,
+		},
+		{
+			name:        "environment",
+			builder:     &ProcessBuilder{CommandLine: `python`, Environment: `^SW_SERVICE=`},
+			exe:         "/usr/bin/python3",
+			cmdline:     "python3 app.py",
+			environment: []string{"PATH=/usr/bin", "SW_SERVICE=order"},
+			matches:     true,
+		},
+		{
+			name:        "environment not found",
+			builder:     &ProcessBuilder{Environment: `^SW_SERVICE=`},
+			exe:         "/usr/bin/python3",
+			cmdline:     "python3 app.py",
+			environment: []string{"PATH=/usr/bin"},
+			matches:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.builder.Layer = "OS_LINUX"
+			tt.builder.ServiceName = "{{.Process.ExeName}}"
+			tt.builder.InstanceName = "{{.Rover.HostName}}"
+			tt.builder.ProcessName = "{{.Process.ExeName}}"
+			if err := tt.builder.init(); err != nil {
+				t.Fatal(err)
+			}
+			matches := tt.builder.matches(tt.exe, tt.cmdline, func() []string {
+				return tt.environment
+			})
+			if matches != tt.matches {
+				t.Fatalf("want matches: %t, got: %t", tt.matches, matches)
+			}
+		})
+	}
+}
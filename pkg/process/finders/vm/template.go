@@ -0,0 +1,60 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shirou/gopsutil/process"
+
+	"github.com/apache/skywalking-rover/pkg/process/finders/base"
+)
+
+func renderTemplate(builder *base.TemplateBuilder, p *process.Process, finder *ProcessFinder) (string, error) {
+	moduleManager := finder.manager.GetModuleManager()
+	return builder.Execute(&EntityRenderContext{
+		Rover:   base.NewTemplateRover(moduleManager),
+		Process: &TemplateProcess{TemplateProcess: base.NewTemplateProcess(moduleManager, p)},
+	})
+}
+
+type EntityRenderContext struct {
+	Rover   *base.TemplateRover
+	Process *TemplateProcess
+}
+
+type TemplateProcess struct {
+	*base.TemplateProcess
+}
+
+// EnvValue the first exist environment value of the process from the names(split by ",")
+func (p *TemplateProcess) EnvValue(names string) (string, error) {
+	envs, err := p.Environ()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range strings.Split(names, ",") {
+		for _, env := range envs {
+			if k, v, found := strings.Cut(env, "="); found && k == name {
+				return v, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("could not found matches environment, want names: %s", names)
+}
@@ -57,7 +57,8 @@ func (m *Module) Start(ctx context.Context, mgr *module.Manager) error {
 	if err != nil {
 		return err
 	}
-	processManager, err := finders.NewProcessManager(ctx, mgr, period, m.config.PropertiesReportPeriod, m.config.Kubernetes)
+	processManager, err := finders.NewProcessManager(ctx, mgr, period, m.config.PropertiesReportPeriod,
+		m.config.Kubernetes, m.config.VM)
 	if err != nil {
 		return err
 	}
@@ -114,3 +115,11 @@ func (m *Module) IsPodIP(ip string) (bool, error) {
 	}
 	return k8sFinder.(*kubernetes.ProcessFinder).IsPodIP(ip)
 }
+
+func (m *Module) FindServiceByClusterIP(ip string) string {
+	k8sFinder, exist := m.manager.Finder(api.Kubernetes)
+	if !exist {
+		return ""
+	}
+	return k8sFinder.(*kubernetes.ProcessFinder).FindServiceByClusterIP(ip)
+}
@@ -0,0 +1,254 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package processexit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/tools/btf"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/event/v3"
+)
+
+// $BPF_CLANG and $BPF_CFLAGS are set by the Makefile.
+// nolint
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -no-global-types -target $TARGET -cc $BPF_CLANG -cflags $BPF_CFLAGS bpf $REPO_ROOT/bpf/processexit/exit.c -- -I$REPO_ROOT/bpf/include
+
+var log = logger.GetLogger("processexit")
+
+// the OOM killer always kills the victim by SIGKILL, the following exit event should not be reported again
+const oomKillDeduplicateDuration = time.Minute
+
+type removedProcess struct {
+	entities  []api.ProcessInterface
+	removedAt time.Time
+}
+
+// Collector watches the processes terminated by fatal signals or killed by the OOM killer,
+// and reports them as the events of the service instance
+type Collector struct {
+	processOperator  process.Operator
+	eventClient      v3.EventServiceClient
+	ignoreSignals    map[unix.Signal]bool
+	removedRetention time.Duration
+
+	bpf    *bpfObjects
+	linker *btf.Linker
+
+	// the processes may be removed from the process module before the exit event is handled
+	removedProcesses map[int32]*removedProcess
+	oomKilled        map[int32]time.Time
+	lock             sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewCollector(mgr *module.Manager, config *Config) (*Collector, error) {
+	ignoreSignals, err := parseSignals(config.IgnoreSignals)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ignore signals failure: %v", err)
+	}
+	retention, err := time.ParseDuration(config.RemovedProcessRetention)
+	if err != nil {
+		return nil, fmt.Errorf("parsing removed process retention failure: %v", err)
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	return &Collector{
+		processOperator:  mgr.FindModule(process.ModuleName).(process.Operator),
+		eventClient:      v3.NewEventServiceClient(coreOperator.BackendOperator().GetConnection()),
+		ignoreSignals:    ignoreSignals,
+		removedRetention: retention,
+		removedProcesses: make(map[int32]*removedProcess),
+		oomKilled:        make(map[int32]time.Time),
+	}, nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	objs := &bpfObjects{}
+	if err := btf.LoadBPFAndAssign(loadBpf, objs); err != nil {
+		return fmt.Errorf("load the process exit BPF program failure: %v", err)
+	}
+	c.bpf = objs
+
+	c.linker = btf.NewLinker()
+	c.linker.AddTracePoint("sched", "sched_process_exit", objs.TracepointSchedProcessExit)
+	c.linker.AddTracePoint("oom", "mark_victim", objs.TracepointOomMarkVictim)
+	c.linker.ReadEventAsync(objs.ProcessExitQueue, func(data interface{}) {
+		c.handleEvent(data.(*exitEvent))
+	}, func() interface{} {
+		return &exitEvent{}
+	})
+	if err := c.linker.HasError(); err != nil {
+		_ = c.Stop()
+		return fmt.Errorf("attach the process exit BPF program failure: %v", err)
+	}
+
+	c.processOperator.AddListener(c)
+	return nil
+}
+
+func (c *Collector) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.processOperator.DeleteListener(c)
+	var err error
+	if c.linker != nil {
+		err = c.linker.Close()
+	}
+	if c.bpf != nil {
+		if e := c.bpf.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (c *Collector) AddNewProcess(pid int32, _ []api.ProcessInterface) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.removedProcesses, pid)
+}
+
+func (c *Collector) RemoveProcess(pid int32, entities []api.ProcessInterface) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.removedProcesses[pid] = &removedProcess{entities: entities, removedAt: time.Now()}
+}
+
+func (c *Collector) RecheckAllProcesses(map[int32][]api.ProcessInterface) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	for pid, p := range c.removedProcesses {
+		if now.Sub(p.removedAt) > c.removedRetention {
+			delete(c.removedProcesses, pid)
+		}
+	}
+	for pid, killedAt := range c.oomKilled {
+		if now.Sub(killedAt) > oomKillDeduplicateDuration {
+			delete(c.oomKilled, pid)
+		}
+	}
+}
+
+func (c *Collector) handleEvent(event *exitEvent) {
+	pid := int32(event.Pid)
+	processes := c.findProcesses(pid)
+	if len(processes) == 0 {
+		return
+	}
+
+	var name, message string
+	parameters := map[string]string{"pid": strconv.Itoa(int(pid))}
+	switch event.Type {
+	case exitTypeOOMKill:
+		c.lock.Lock()
+		c.oomKilled[pid] = time.Now()
+		c.lock.Unlock()
+		name = "ProcessOOMKilled"
+		message = "process %s(pid: %d) is killed by the OOM killer"
+	case exitTypeSignal:
+		status := parseExitCode(event.ExitCode)
+		if c.ignoreSignals[status.Signal] || c.isOOMKilled(pid, status.Signal) {
+			return
+		}
+		name = "ProcessKilledBySignal"
+		message = "process %s(pid: %d) is terminated by signal " + status.SignalName()
+		if status.CoreDumped {
+			message += " (core dumped)"
+		}
+		parameters["signal"] = status.SignalName()
+		parameters["core_dumped"] = strconv.FormatBool(status.CoreDumped)
+	default:
+		return
+	}
+
+	eventTime := host.Time(event.Timestamp).UnixMilli()
+	for _, p := range processes {
+		e := &v3.Event{
+			Uuid: uuid.New().String(),
+			Source: &v3.Source{
+				Service:         p.Entity().ServiceName,
+				ServiceInstance: p.Entity().InstanceName,
+			},
+			Name:       name,
+			Type:       v3.Type_Error,
+			Message:    fmt.Sprintf(message, p.Entity().ProcessName, pid),
+			Parameters: parameters,
+			StartTime:  eventTime,
+			EndTime:    eventTime,
+			Layer:      p.Entity().Layer,
+		}
+		if err := c.sendEvent(e); err != nil {
+			log.Warnf("send the process exit event of process %d failure: %v", pid, err)
+		}
+	}
+}
+
+func (c *Collector) findProcesses(pid int32) []api.ProcessInterface {
+	if processes := c.processOperator.FindProcessByPID(pid); len(processes) > 0 {
+		return processes
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if p := c.removedProcesses[pid]; p != nil {
+		return p.entities
+	}
+	return nil
+}
+
+// isOOMKilled check the process is terminated by the OOM killer, which already reported
+func (c *Collector) isOOMKilled(pid int32, sig unix.Signal) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	killedAt, exist := c.oomKilled[pid]
+	if !exist {
+		return false
+	}
+	delete(c.oomKilled, pid)
+	return sig == unix.SIGKILL && time.Since(killedAt) < oomKillDeduplicateDuration
+}
+
+func (c *Collector) sendEvent(e *v3.Event) error {
+	stream, err := c.eventClient.Collect(c.ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(e); err != nil {
+		return err
+	}
+	_, err = stream.CloseAndRecv()
+	return err
+}
@@ -0,0 +1,82 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package processexit
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	exitTypeSignal  uint32 = 1
+	exitTypeOOMKill uint32 = 2
+
+	exitCodeSignalMask = 0x7f
+	exitCodeCoreDumped = 0x80
+)
+
+// exitEvent is the event sent by the BPF program when a process terminated by signal or selected by the OOM killer
+type exitEvent struct {
+	Pid       uint32
+	Type      uint32
+	ExitCode  uint32
+	Reserve   uint32
+	Timestamp uint64
+}
+
+type exitStatus struct {
+	Signal     unix.Signal
+	CoreDumped bool
+}
+
+// parseExitCode decode the exit code of the task_struct, the lower 7 bits is the terminate signal
+func parseExitCode(code uint32) exitStatus {
+	return exitStatus{
+		Signal:     unix.Signal(code & exitCodeSignalMask),
+		CoreDumped: code&exitCodeCoreDumped != 0,
+	}
+}
+
+func (s exitStatus) SignalName() string {
+	if name := unix.SignalName(s.Signal); name != "" {
+		return name
+	}
+	return fmt.Sprintf("SIG%d", int(s.Signal))
+}
+
+// parseSignals parse the comma separated signal names, the "SIG" prefix is optional
+func parseSignals(names string) (map[unix.Signal]bool, error) {
+	result := make(map[unix.Signal]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !strings.HasPrefix(name, "SIG") {
+			name = "SIG" + name
+		}
+		sig := unix.SignalNum(name)
+		if sig == 0 {
+			return nil, fmt.Errorf("unknown signal: %s", name)
+		}
+		result[sig] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package processexit
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestParseExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     uint32
+		expected exitStatus
+		sigName  string
+	}{
+		{
+			name:     "killed",
+			code:     9,
+			expected: exitStatus{Signal: unix.SIGKILL},
+			sigName:  "SIGKILL",
+		},
+		{
+			name:     "segmentation fault with core dump",
+			code:     0x8b,
+			expected: exitStatus{Signal: unix.SIGSEGV, CoreDumped: true},
+			sigName:  "SIGSEGV",
+		},
+		{
+			name:     "exit status is ignored",
+			code:     1<<8 | 6,
+			expected: exitStatus{Signal: unix.SIGABRT},
+			sigName:  "SIGABRT",
+		},
+		{
+			name:     "realtime signal",
+			code:     40,
+			expected: exitStatus{Signal: unix.Signal(40)},
+			sigName:  "SIG40",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := parseExitCode(tt.code)
+			if actual != tt.expected {
+				t.Fatalf("expected %v, actual %v", tt.expected, actual)
+			}
+			if actual.SignalName() != tt.sigName {
+				t.Fatalf("expected signal name %s, actual %s", tt.sigName, actual.SignalName())
+			}
+		})
+	}
+}
+
+func TestParseSignals(t *testing.T) {
+	tests := []struct {
+		name     string
+		signals  string
+		expected map[unix.Signal]bool
+		hasError bool
+	}{
+		{
+			name:     "empty",
+			signals:  "",
+			expected: map[unix.Signal]bool{},
+		},
+		{
+			name:     "with and without prefix",
+			signals:  "SIGTERM, int",
+			expected: map[unix.Signal]bool{unix.SIGTERM: true, unix.SIGINT: true},
+		},
+		{
+			name:     "unknown",
+			signals:  "SIGTERM,SIGUNKNOWN",
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseSignals(tt.signals)
+			if (err != nil) != tt.hasError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.hasError && !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package profiling
 
 import (
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 	continuousBase "github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
 	taskBase "github.com/apache/skywalking-rover/pkg/profiling/task/base"
 )
@@ -29,6 +30,8 @@ type Config struct {
 	CheckInterval string `mapstructure:"check_interval"` // Check the profiling task interval
 	FlushInterval string `mapstructure:"flush_interval"` // Flush profiling data interval
 
+	Filter *filter.Config `mapstructure:"filter"` // Which processes could be profiled
+
 	TaskConfig       *taskBase.TaskConfig             `mapstructure:"task"`       // Profiling task config
 	ContinuousConfig *continuousBase.ContinuousConfig `mapstructure:"continuous"` // Continuous profiling config
 }
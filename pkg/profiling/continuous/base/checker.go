@@ -31,6 +31,7 @@ const (
 	CheckTypeSystemLoad          CheckType = "SYSTEM_LOAD"
 	CheckTypeHTTPErrorRate       CheckType = "HTTP_ERROR_RATE"
 	CheckTypeHTTPAvgResponseTime CheckType = "HTTP_AVG_RESPONSE_TIME"
+	CheckTypeHTTPP99ResponseTime CheckType = "HTTP_P99_RESPONSE_TIME"
 )
 
 type Checker interface {
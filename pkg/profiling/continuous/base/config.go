@@ -23,9 +23,20 @@ type ContinuousConfig struct {
 	FetchInterval string        `mapstructure:"fetch_interval"` // The interval of fetch metrics from the system
 	CheckInterval string        `mapstructure:"check_interval"` // The interval of check metrics is reach the thresholds
 	Trigger       TriggerConfig `mapstructure:"trigger"`
+	Webhook       WebhookConfig `mapstructure:"webhook"`
+	NetworkSource string        `mapstructure:"network_source"` // The source of the HTTP events for the network checkers
+	PolicyFile    string        `mapstructure:"policy_file"`    // The local policies file, the policies from the backend are ignored when set
 }
 
 type TriggerConfig struct {
 	ExecuteDuration string `mapstructure:"execute_duration"` // the duration of the profiling task
 	SilenceDuration string `mapstructure:"silence_duration"` // the duration between the execution of the same profiling task.
 }
+
+type WebhookConfig struct {
+	Active       bool   `mapstructure:"active"`        // Is active the webhook to trigger the policies from the external systems
+	Address      string `mapstructure:"address"`       // The bind address of the webhook HTTP server
+	Port         int    `mapstructure:"port"`          // The bind port of the webhook HTTP server
+	Token        string `mapstructure:"token"`         // The shared token of the requests, empty means no authentication
+	ServiceLabel string `mapstructure:"service_label"` // The alert label name to find the service name
+}
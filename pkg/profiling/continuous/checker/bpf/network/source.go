@@ -0,0 +1,60 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package network
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	// EventSourceBPF the HTTP events are read by the BPF program of the continuous profiling
+	EventSourceBPF = "bpf"
+	// EventSourceAccessLog the HTTP events are analyzed from the access logs
+	EventSourceAccessLog = "access_log"
+)
+
+var accessLogSource atomic.Bool
+
+// ChangeEventSource change where the HTTP events are read from
+func ChangeEventSource(source string) error {
+	switch source {
+	case "", EventSourceBPF:
+		accessLogSource.Store(false)
+	case EventSourceAccessLog:
+		accessLogSource.Store(true)
+	default:
+		return fmt.Errorf("unknown network event source: %s", source)
+	}
+	return nil
+}
+
+// IsAccessLogSource the HTTP events are provided by the access log module, so the BPF program is not needed
+func IsAccessLogSource() bool {
+	return accessLogSource.Load()
+}
+
+// NotifyAccessLogEvent dispatch the HTTP event observed from the access logs to the checkers
+func NotifyAccessLogEvent(event BufferEvent) {
+	if !accessLogSource.Load() {
+		return
+	}
+	for _, n := range notifiers {
+		n.ReceiveBufferEvent(event)
+	}
+}
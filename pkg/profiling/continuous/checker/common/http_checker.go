@@ -126,6 +126,10 @@ func (n *HTTPBasedChecker) SyncPolicies(policies []*base.SyncPolicyWithProcesses
 		}
 		return item
 	}, func(pid int32, isDelete bool) {
+		// the HTTP events are provided by the access logs, no need to monitor the process through BPF
+		if network.IsAccessLogSource() {
+			return
+		}
 		// notify to the listener
 		var err error
 		defer func() {
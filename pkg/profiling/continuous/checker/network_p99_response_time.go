@@ -0,0 +1,112 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package checker
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker/bpf/network"
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker/common"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
+)
+
+const (
+	// the response time(ms) histogram buckets grow by 10%, so the error of the percentile is less than 10%
+	responseTimeBucketGrowth = 1.1
+	// the max bucket covers the 60 seconds response time
+	responseTimeBucketCount = 117
+	responseTimePercentile  = 0.99
+)
+
+var responseTimeBucketGrowthLog = math.Log(responseTimeBucketGrowth)
+
+type NetworkHTTPP99ResponseTimeChecker struct {
+	*common.HTTPBasedChecker
+}
+
+func NewNetworkP99ResponseTimeChecker() *NetworkHTTPP99ResponseTimeChecker {
+	return &NetworkHTTPP99ResponseTimeChecker{}
+}
+
+func (n *NetworkHTTPP99ResponseTimeChecker) Init(*base.ContinuousConfig) error {
+	// the backend has no percentile monitor type, so the cause is reported as the response time type
+	n.HTTPBasedChecker = common.NewHTTPBasedChecker(
+		base.CheckTypeHTTPP99ResponseTime, func(val string) (float64, error) {
+			return strconv.ParseFloat(val, 64)
+		}, func() base.WindowData[network.BufferEvent, float64] {
+			return &processNetworkP99ResponseTimeStatics{}
+		}, v3.ContinuousProfilingTriggeredMonitorType_HTTPAvgResponseTime)
+	return nil
+}
+
+// processNetworkP99ResponseTimeStatics counts the response time in the exponential histogram,
+// for keeping the memory bounded no matter how many requests in the window
+type processNetworkP99ResponseTimeStatics struct {
+	buckets    []int
+	totalCount int
+}
+
+func (s *processNetworkP99ResponseTimeStatics) Reset() {
+	for i := range s.buckets {
+		s.buckets[i] = 0
+	}
+	s.totalCount = 0
+}
+
+func (s *processNetworkP99ResponseTimeStatics) Accept(data network.BufferEvent) {
+	if s.buckets == nil {
+		s.buckets = make([]int, responseTimeBucketCount)
+	}
+	s.buckets[responseTimeBucket(float64(data.Duration().Microseconds())/1000)]++
+	s.totalCount++
+}
+
+func (s *processNetworkP99ResponseTimeStatics) Get() float64 {
+	if s.totalCount == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(s.totalCount) * responseTimePercentile))
+	count := 0
+	for i, c := range s.buckets {
+		count += c
+		if count >= rank {
+			return responseTimeBucketBound(i)
+		}
+	}
+	return responseTimeBucketBound(len(s.buckets) - 1)
+}
+
+// responseTimeBucket find the bucket index of the response time(ms), the bucket i contains (growth^(i-1), growth^i]
+func responseTimeBucket(ms float64) int {
+	if ms <= 1 {
+		return 0
+	}
+	index := int(math.Ceil(math.Log(ms) / responseTimeBucketGrowthLog))
+	if index >= responseTimeBucketCount {
+		return responseTimeBucketCount - 1
+	}
+	return index
+}
+
+// responseTimeBucketBound is the upper bound(ms) of the bucket
+func responseTimeBucketBound(index int) float64 {
+	return math.Round(math.Pow(responseTimeBucketGrowth, float64(index))*100) / 100
+}
@@ -0,0 +1,65 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker/bpf/network"
+)
+
+type responseTimeEvent struct {
+	network.BufferEvent
+	duration time.Duration
+}
+
+func (e *responseTimeEvent) Duration() time.Duration {
+	return e.duration
+}
+
+func TestP99ResponseTimeStatics(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations map[time.Duration]int
+		min, max  float64
+	}{
+		{name: "empty", durations: map[time.Duration]int{}, min: 0, max: 0},
+		{name: "all fast", durations: map[time.Duration]int{time.Millisecond * 10: 100}, min: 10, max: 11},
+		{name: "slow tail", durations: map[time.Duration]int{time.Millisecond * 10: 98, time.Millisecond * 500: 2}, min: 500, max: 550},
+		{name: "below percentile", durations: map[time.Duration]int{time.Millisecond * 10: 995, time.Second * 5: 5}, min: 10, max: 11},
+		{name: "over max bucket", durations: map[time.Duration]int{time.Minute * 5: 1}, min: 60000, max: 70000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statics := &processNetworkP99ResponseTimeStatics{}
+			for d, count := range tt.durations {
+				for i := 0; i < count; i++ {
+					statics.Accept(&responseTimeEvent{duration: d})
+				}
+			}
+			if p99 := statics.Get(); p99 < tt.min || p99 > tt.max {
+				t.Fatalf("expected p99 in [%f, %f], actual %f", tt.min, tt.max, p99)
+			}
+			statics.Reset()
+			if statics.Get() != 0 {
+				t.Fatalf("expected zero after reset")
+			}
+		})
+	}
+}
@@ -27,8 +27,11 @@ import (
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/process"
 	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
 	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker"
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker/bpf/network"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
 
 	profilingv3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
 	meterv3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
@@ -47,7 +50,8 @@ func init() {
 		checker.NewProcessThreadCountChecker(),
 		// network
 		checker.NewNetworkResponseErrorChecker(),
-		checker.NewNetworkAvgResponseTimeChecker())
+		checker.NewNetworkAvgResponseTimeChecker(),
+		checker.NewNetworkP99ResponseTimeChecker())
 }
 
 type Checkers struct {
@@ -55,16 +59,23 @@ type Checkers struct {
 	fetchDuration   time.Duration
 	checkDuration   time.Duration
 	processOperator process.Operator
+	processFilter   *filter.Filter
 	triggers        *Triggers
 	policiesCache   map[string]*base.ServicePolicy
+	policyFile      string
+	throttle        *selfprotect.Throttle
+
+	externalTriggers chan *externalTrigger
 
 	meterClient      meterv3.MeterReportServiceClient
 	continuousClient profilingv3.ContinuousProfilingServiceClient
 	ctx              context.Context
 }
 
-func NewCheckers(ctx context.Context, moduleMgr *module.Manager, conf *base.ContinuousConfig, triggers *Triggers) (*Checkers, error) {
-	connection := moduleMgr.FindModule(core.ModuleName).(core.Operator).BackendOperator().GetConnection()
+func NewCheckers(ctx context.Context, moduleMgr *module.Manager, conf *base.ContinuousConfig, triggers *Triggers,
+	processFilter *filter.Filter) (*Checkers, error) {
+	coreOperator := moduleMgr.FindModule(core.ModuleName).(core.Operator)
+	connection := coreOperator.BackendOperator().GetConnection()
 	meterClient := meterv3.NewMeterReportServiceClient(connection)
 	continuousClient := profilingv3.NewContinuousProfilingServiceClient(connection)
 
@@ -81,6 +92,10 @@ func NewCheckers(ctx context.Context, moduleMgr *module.Manager, conf *base.Cont
 		return nil, fmt.Errorf("check duration error: %v", err)
 	}
 
+	if err = network.ChangeEventSource(conf.NetworkSource); err != nil {
+		return nil, err
+	}
+
 	for _, checker := range checkerRegistration {
 		if e := checker.Init(conf); e != nil {
 			err = multierror.Append(err, e)
@@ -97,8 +112,12 @@ func NewCheckers(ctx context.Context, moduleMgr *module.Manager, conf *base.Cont
 		fetchDuration:    fetchDuration,
 		checkDuration:    checkDuration,
 		processOperator:  moduleMgr.FindModule(process.ModuleName).(process.Operator),
+		processFilter:    processFilter,
 		triggers:         triggers,
 		policiesCache:    make(map[string]*base.ServicePolicy),
+		policyFile:       conf.PolicyFile,
+		throttle:         coreOperator.ResourceGovernor().Register("continuous_profiling", selfprotect.PriorityNormal),
+		externalTriggers: make(chan *externalTrigger),
 		ctx:              ctx,
 	}, nil
 }
@@ -111,11 +130,19 @@ func (c *Checkers) Start() {
 		for {
 			select {
 			case <-fetchTicker.C:
+				if c.throttle.Paused() {
+					continue
+				}
 				if err := c.fetchAllData(); err != nil {
 					log.Errorf("fetch all data error: %v", err)
 				}
 			case <-checkTicker.C:
+				if c.throttle.Paused() {
+					continue
+				}
 				c.checkAllThresholds()
+			case t := <-c.externalTriggers:
+				t.result <- c.triggerExternal(t.requests)
 			case <-c.ctx.Done():
 				checkTicker.Stop()
 				return
@@ -124,6 +151,24 @@ func (c *Checkers) Start() {
 	}()
 }
 
+// TriggerExternal trigger the matched policies immediately, the causes are handled in the checking goroutine
+func (c *Checkers) TriggerExternal(ctx context.Context, requests []*ExternalTriggerRequest) (int, error) {
+	t := &externalTrigger{requests: requests, result: make(chan int, 1)}
+	select {
+	case c.externalTriggers <- t:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-c.ctx.Done():
+		return 0, fmt.Errorf("the continuous profiling is shutdown")
+	}
+	select {
+	case count := <-t.result:
+		return count, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
 func (c *Checkers) Stop() error {
 	var err error
 	for _, checker := range checkerRegistration {
@@ -203,7 +248,7 @@ func (c *Checkers) ShouldCheck(p api.ProcessInterface, item *base.PolicyItem) bo
 }
 
 func (c *Checkers) updatePolicyCache() (bool, error) {
-	processes := c.processOperator.FindAllRegisteredProcesses()
+	processes := c.processFilter.Processes(c.processOperator.FindAllRegisteredProcesses())
 	if len(processes) == 0 {
 		// if existing policies, then clean it
 		if (len(c.policiesCache)) > 0 {
@@ -275,6 +320,9 @@ func (c *Checkers) checkProcessesAreSame(from, target map[string]api.ProcessInte
 }
 
 func (c *Checkers) queryPolicyUpdates(servicePolicies map[string]string) (map[string]*base.ServicePolicy, error) {
+	if c.policyFile != "" {
+		return loadLocalPolicies(c.policyFile, servicePolicies)
+	}
 	queries := make([]*profilingv3.ContinuousProfilingServicePolicyQuery, 0)
 	for k, v := range servicePolicies {
 		queries = append(queries, &profilingv3.ContinuousProfilingServicePolicyQuery{
@@ -309,7 +357,10 @@ func (c *Checkers) queryPolicyUpdates(servicePolicies map[string]string) (map[st
 	if err != nil {
 		return nil, fmt.Errorf("error to unmarshal the policy updates: %v", err)
 	}
+	return buildServicePolicies(updates), nil
+}
 
+func buildServicePolicies(updates []*QueryPolicyUpdate) map[string]*base.ServicePolicy {
 	result := make(map[string]*base.ServicePolicy)
 	for _, update := range updates {
 		servicePolicy := &base.ServicePolicy{
@@ -344,7 +395,7 @@ func (c *Checkers) queryPolicyUpdates(servicePolicies map[string]string) (map[st
 
 		result[update.ServiceName] = servicePolicy
 	}
-	return result, nil
+	return result
 }
 
 type QueryPolicyUpdate struct {
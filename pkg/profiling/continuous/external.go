@@ -0,0 +1,98 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package continuous
+
+import (
+	"strconv"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/checker/common"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
+)
+
+var externalMonitorTypes = map[base.CheckType]v3.ContinuousProfilingTriggeredMonitorType{
+	base.CheckTypeProcessCPU:          v3.ContinuousProfilingTriggeredMonitorType_ProcessCPU,
+	base.CheckTypeProcessThreadCount:  v3.ContinuousProfilingTriggeredMonitorType_ProcessThreadCount,
+	base.CheckTypeSystemLoad:          v3.ContinuousProfilingTriggeredMonitorType_SystemLoad,
+	base.CheckTypeHTTPErrorRate:       v3.ContinuousProfilingTriggeredMonitorType_HTTPErrorRate,
+	base.CheckTypeHTTPAvgResponseTime: v3.ContinuousProfilingTriggeredMonitorType_HTTPAvgResponseTime,
+	base.CheckTypeHTTPP99ResponseTime: v3.ContinuousProfilingTriggeredMonitorType_HTTPAvgResponseTime,
+}
+
+// ExternalTriggerRequest is the request from the external systems(such as webhook) to trigger the policies immediately
+type ExternalTriggerRequest struct {
+	// Service name of the policies, required
+	Service string `json:"service"`
+	// ProfilingType only triggers the policies with the specific profiling type, optional
+	ProfilingType base.TargetProfilingType `json:"profiling_type"`
+	// CheckType only triggers the policy items with the specific check type, optional
+	CheckType base.CheckType `json:"check_type"`
+}
+
+type externalTrigger struct {
+	requests []*ExternalTriggerRequest
+	result   chan int
+}
+
+// triggerExternal generate the causes from the matched policies and trigger the profiling tasks,
+// return the count of the triggered tasks
+func (c *Checkers) triggerExternal(requests []*ExternalTriggerRequest) int {
+	causes := make([]base.ThresholdCause, 0)
+	for _, req := range requests {
+		causes = append(causes, c.buildExternalCauses(req)...)
+	}
+	if len(causes) == 0 {
+		return 0
+	}
+	return c.triggers.handleCauses(causes)
+}
+
+func (c *Checkers) buildExternalCauses(req *ExternalTriggerRequest) []base.ThresholdCause {
+	servicePolicy := c.policiesCache[req.Service]
+	if servicePolicy == nil {
+		return nil
+	}
+	causes := make([]base.ThresholdCause, 0)
+	for _, policy := range servicePolicy.Policies {
+		if req.ProfilingType != "" && policy.TargetProfilingType != req.ProfilingType {
+			continue
+		}
+		for checkType, item := range policy.Items {
+			monitorType, exist := externalMonitorTypes[checkType]
+			if !exist || (req.CheckType != "" && checkType != req.CheckType) {
+				continue
+			}
+			// the value is not measured by the rover, so treat the current value as the threshold been reached
+			threshold, _ := strconv.ParseFloat(item.Threshold, 64)
+			for _, p := range servicePolicy.Processes {
+				if !c.ShouldCheck(p, item) {
+					continue
+				}
+				if item.URIRegex != "" {
+					causes = append(causes, common.NewURICause(p, true, item.URIRegex, item, monitorType, threshold, threshold))
+				} else if len(item.URIList) > 0 {
+					causes = append(causes, common.NewURICause(p, false, item.URIList[0], item, monitorType, threshold, threshold))
+				} else {
+					causes = append(causes, common.NewSingleValueCause(p, item, monitorType, threshold, threshold))
+				}
+			}
+		}
+	}
+	return causes
+}
@@ -0,0 +1,80 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package continuous
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
+)
+
+// localPolicyAllServices is the service name of the local policy which applies to the services without their own policy
+const localPolicyAllServices = "*"
+
+// loadLocalPolicies read the policies from the local file instead of the backend, the file has the same JSON format
+// as the policies queried from the backend, the UUID is generated from the file content,
+// so the policies are updated once the file changed
+func loadLocalPolicies(file string, servicePolicies map[string]string) (map[string]*base.ServicePolicy, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read the local policies file failure: %v", err)
+	}
+	updates, err := parseLocalPolicies(content, servicePolicies)
+	if err != nil {
+		return nil, fmt.Errorf("parse the local policies file %s failure: %v", file, err)
+	}
+	return buildServicePolicies(updates), nil
+}
+
+func parseLocalPolicies(content []byte, servicePolicies map[string]string) ([]*QueryPolicyUpdate, error) {
+	policies := make([]*QueryPolicyUpdate, 0)
+	if err := json.Unmarshal(content, &policies); err != nil {
+		return nil, err
+	}
+	serviceWithPolicy := make(map[string]*QueryPolicyUpdate, len(policies))
+	for _, p := range policies {
+		if p.ServiceName == "" {
+			return nil, fmt.Errorf("the service name of the policy cannot be empty")
+		}
+		serviceWithPolicy[p.ServiceName] = p
+	}
+	sum := sha256.Sum256(content)
+	uuid := "local-" + hex.EncodeToString(sum[:8])
+
+	updates := make([]*QueryPolicyUpdate, 0)
+	for service, existingUUID := range servicePolicies {
+		if existingUUID == uuid {
+			continue
+		}
+		policy := serviceWithPolicy[service]
+		if policy == nil {
+			policy = serviceWithPolicy[localPolicyAllServices]
+		}
+		// the service without any policy still need to be updated, for removing the older policies
+		update := &QueryPolicyUpdate{ServiceName: service, UUID: uuid}
+		if policy != nil {
+			update.Profiling = policy.Profiling
+		}
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
@@ -0,0 +1,73 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package continuous
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
+)
+
+func TestParseLocalPolicies(t *testing.T) {
+	content := []byte(`[
+	{"ServiceName": "*", "Profiling": {"ON_CPU": {"PROCESS_CPU": {"Threshold": "80", "Period": 10, "Count": 3}}}},
+	{"ServiceName": "svc", "Profiling": {"NETWORK": {"HTTP_P99_RESPONSE_TIME": {"Threshold": "500", "Period": 10, "Count": 3}}}}
+]`)
+	updates, err := parseLocalPolicies(content, map[string]string{"svc": "", "other": "", "updated": "older"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 3 {
+		t.Fatalf("expected 3 updates, actual %d", len(updates))
+	}
+	policies := buildServicePolicies(updates)
+	tests := []struct {
+		service   string
+		profiling base.TargetProfilingType
+		check     base.CheckType
+	}{
+		{service: "svc", profiling: base.TargetProfilingTypeNetwork, check: base.CheckTypeHTTPP99ResponseTime},
+		{service: "other", profiling: base.TargetProfilingTypeOnCPU, check: base.CheckTypeProcessCPU},
+		{service: "updated", profiling: base.TargetProfilingTypeOnCPU, check: base.CheckTypeProcessCPU},
+	}
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			servicePolicy := policies[tt.service]
+			if servicePolicy == nil || len(servicePolicy.Policies) != 1 {
+				t.Fatalf("expected one policy of the service: %v", servicePolicy)
+			}
+			policy := servicePolicy.Policies[0]
+			if policy.TargetProfilingType != tt.profiling || policy.Items[tt.check] == nil {
+				t.Fatalf("unexpected policy: %v", policy)
+			}
+		})
+	}
+
+	// the same content should not be updated again
+	unchanged, err := parseLocalPolicies(content, map[string]string{"svc": policies["svc"].UUID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unchanged) != 0 {
+		t.Fatalf("expected no updates, actual %d", len(unchanged))
+	}
+
+	if _, err := parseLocalPolicies([]byte(`[{"Profiling": {}}]`), map[string]string{"svc": ""}); err == nil {
+		t.Fatalf("expected the error of the empty service name")
+	}
+}
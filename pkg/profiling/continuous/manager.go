@@ -20,8 +20,11 @@ package continuous
 import (
 	"context"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
 	"github.com/apache/skywalking-rover/pkg/profiling/task"
 )
@@ -31,12 +34,14 @@ var log = logger.GetLogger("profiling", "continuous")
 type Manager struct {
 	checkers *Checkers
 	triggers *Triggers
+	webhook  *Webhook
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-func NewManager(ctx context.Context, taskManager *task.Manager, moduleMgr *module.Manager, config *base.ContinuousConfig) (*Manager, error) {
+func NewManager(ctx context.Context, taskManager *task.Manager, moduleMgr *module.Manager, config *base.ContinuousConfig,
+	processFilter *filter.Filter) (*Manager, error) {
 	m := &Manager{}
 	m.ctx, m.cancel = context.WithCancel(ctx)
 
@@ -46,17 +51,28 @@ func NewManager(ctx context.Context, taskManager *task.Manager, moduleMgr *modul
 		return nil, err
 	}
 	m.triggers = triggers
-	checkers, err := NewCheckers(m.ctx, moduleMgr, config, m.triggers)
+	checkers, err := NewCheckers(m.ctx, moduleMgr, config, m.triggers, processFilter)
 	if err != nil {
 		return nil, err
 	}
 	m.checkers = checkers
 
+	if config.Webhook.Active {
+		webhook, err := NewWebhook(&config.Webhook, checkers)
+		if err != nil {
+			return nil, err
+		}
+		m.webhook = webhook
+	}
+
 	return m, nil
 }
 
 func (m *Manager) Start() {
 	m.checkers.Start()
+	if m.webhook != nil {
+		m.webhook.Start()
+	}
 }
 
 func (m *Manager) CheckPolicies() error {
@@ -65,6 +81,11 @@ func (m *Manager) CheckPolicies() error {
 
 func (m *Manager) Shutdown() error {
 	err := m.checkers.Stop()
+	if m.webhook != nil {
+		if e := m.webhook.Shutdown(); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
 	m.cancel()
 	return err
 }
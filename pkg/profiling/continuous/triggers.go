@@ -70,18 +70,21 @@ func NewTriggers(ctx context.Context, moduleMgr *module.Manager, taskManager *ta
 	}, nil
 }
 
-func (m *Triggers) handleCauses(causes []base.ThresholdCause) {
+func (m *Triggers) handleCauses(causes []base.ThresholdCause) int {
 	// generate the profiling tasks from the triggerRegistration
 	profilingTypeWithCauses := make(map[base.TargetProfilingType][]base.ThresholdCause)
 	for _, cause := range causes {
 		profilingType := cause.FromPolicy().Policy.TargetProfilingType
 		profilingTypeWithCauses[profilingType] = append(profilingTypeWithCauses[profilingType], cause)
 	}
+	total := 0
 	for profilingType, ps := range profilingTypeWithCauses {
 		if taskCount := triggerRegistration[profilingType].TriggerTasks(m, ps); taskCount > 0 {
 			log.Infof("total generate %d %s tasks", taskCount, profilingType)
+			total += taskCount
 		}
 	}
+	return total
 }
 
 func (m *Triggers) ReportProcesses(process api.ProcessInterface, profilingProcesses []api.ProcessInterface, cases []base.ThresholdCause,
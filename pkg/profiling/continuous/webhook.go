@@ -0,0 +1,177 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package continuous
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
+)
+
+// Webhook is the HTTP server for the external systems to trigger the continuous profiling policies,
+// such as the on-node admin API and the alertmanager webhook
+type Webhook struct {
+	config   *base.WebhookConfig
+	checkers *Checkers
+	server   *http.Server
+}
+
+type webhookResponse struct {
+	Triggered int    `json:"triggered"`
+	Error     string `json:"error,omitempty"`
+}
+
+type alertmanagerPayload struct {
+	Alerts []*alertmanagerAlert `json:"alerts"`
+}
+
+type alertmanagerAlert struct {
+	Status string            `json:"status"`
+	Labels map[string]string `json:"labels"`
+}
+
+func NewWebhook(config *base.WebhookConfig, checkers *Checkers) (*Webhook, error) {
+	if config.Port <= 0 {
+		return nil, fmt.Errorf("the continuous profiling webhook port must be bigger than 0")
+	}
+	if config.ServiceLabel == "" {
+		return nil, fmt.Errorf("the continuous profiling webhook service label cannot be empty")
+	}
+	if config.Token == "" && config.Address != "127.0.0.1" && config.Address != "localhost" {
+		log.Warnf("the continuous profiling webhook is bound to %q without the token, "+
+			"anyone could reach the address can trigger the profiling tasks", config.Address)
+	}
+	w := &Webhook{config: config, checkers: checkers}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/continuous/trigger", w.handleTrigger)
+	mux.HandleFunc("/continuous/alertmanager", w.handleAlertmanager)
+	w.server = &http.Server{
+		Addr:              net.JoinHostPort(config.Address, strconv.Itoa(config.Port)),
+		ReadHeaderTimeout: 3 * time.Second,
+		Handler:           w.authenticate(mux),
+	}
+	return w, nil
+}
+
+// authenticate check the shared token of each request when the token is configured
+func (w *Webhook) authenticate(next http.Handler) http.Handler {
+	if w.config.Token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(w.config.Token)) != 1 {
+			w.response(writer, http.StatusUnauthorized, 0, fmt.Errorf("invalid token"))
+			return
+		}
+		next.ServeHTTP(writer, req)
+	})
+}
+
+func (w *Webhook) Start() {
+	go func() {
+		if err := w.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("the continuous profiling webhook server failure: %v", err)
+		}
+	}()
+}
+
+func (w *Webhook) Shutdown() error {
+	return w.server.Shutdown(context.Background())
+}
+
+func (w *Webhook) handleTrigger(writer http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.response(writer, http.StatusMethodNotAllowed, 0, fmt.Errorf("only support POST method"))
+		return
+	}
+	request := &ExternalTriggerRequest{}
+	if err := json.NewDecoder(req.Body).Decode(request); err != nil {
+		w.response(writer, http.StatusBadRequest, 0, fmt.Errorf("decode the request failure: %v", err))
+		return
+	}
+	if request.Service == "" {
+		w.response(writer, http.StatusBadRequest, 0, fmt.Errorf("the service cannot be empty"))
+		return
+	}
+	w.trigger(req.Context(), writer, []*ExternalTriggerRequest{request})
+}
+
+func (w *Webhook) handleAlertmanager(writer http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.response(writer, http.StatusMethodNotAllowed, 0, fmt.Errorf("only support POST method"))
+		return
+	}
+	payload := &alertmanagerPayload{}
+	if err := json.NewDecoder(req.Body).Decode(payload); err != nil {
+		w.response(writer, http.StatusBadRequest, 0, fmt.Errorf("decode the alerts failure: %v", err))
+		return
+	}
+	w.trigger(req.Context(), writer, parseAlertmanagerRequests(payload, w.config.ServiceLabel))
+}
+
+func (w *Webhook) trigger(ctx context.Context, writer http.ResponseWriter, requests []*ExternalTriggerRequest) {
+	if len(requests) == 0 {
+		w.response(writer, http.StatusOK, 0, nil)
+		return
+	}
+	count, err := w.checkers.TriggerExternal(ctx, requests)
+	if err != nil {
+		w.response(writer, http.StatusServiceUnavailable, 0, err)
+		return
+	}
+	log.Infof("total generate %d continuous profiling tasks from the webhook", count)
+	w.response(writer, http.StatusOK, count, nil)
+}
+
+func (w *Webhook) response(writer http.ResponseWriter, status, triggered int, err error) {
+	resp := &webhookResponse{Triggered: triggered}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	if e := json.NewEncoder(writer).Encode(resp); e != nil {
+		log.Warnf("write the webhook response failure: %v", e)
+	}
+}
+
+// parseAlertmanagerRequests converts the firing alerts to the trigger requests,
+// the service name is read from the service label, and the optional "profiling_type" and "check_type" labels narrow the policies
+func parseAlertmanagerRequests(payload *alertmanagerPayload, serviceLabel string) []*ExternalTriggerRequest {
+	result := make([]*ExternalTriggerRequest, 0)
+	for _, alert := range payload.Alerts {
+		if alert.Status != "firing" || alert.Labels[serviceLabel] == "" {
+			continue
+		}
+		result = append(result, &ExternalTriggerRequest{
+			Service:       alert.Labels[serviceLabel],
+			ProfilingType: base.TargetProfilingType(alert.Labels["profiling_type"]),
+			CheckType:     base.CheckType(alert.Labels["check_type"]),
+		})
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package continuous
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/continuous/base"
+)
+
+func TestParseAlertmanagerRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		alerts   []*alertmanagerAlert
+		expected []*ExternalTriggerRequest
+	}{
+		{
+			name: "firing alert",
+			alerts: []*alertmanagerAlert{
+				{Status: "firing", Labels: map[string]string{"service": "svc"}},
+			},
+			expected: []*ExternalTriggerRequest{{Service: "svc"}},
+		},
+		{
+			name: "with profiling and check type",
+			alerts: []*alertmanagerAlert{
+				{Status: "firing", Labels: map[string]string{"service": "svc", "profiling_type": "ON_CPU", "check_type": "PROCESS_CPU"}},
+			},
+			expected: []*ExternalTriggerRequest{
+				{Service: "svc", ProfilingType: base.TargetProfilingTypeOnCPU, CheckType: base.CheckTypeProcessCPU},
+			},
+		},
+		{
+			name: "resolved or no service",
+			alerts: []*alertmanagerAlert{
+				{Status: "resolved", Labels: map[string]string{"service": "svc"}},
+				{Status: "firing", Labels: map[string]string{"job": "svc"}},
+			},
+			expected: []*ExternalTriggerRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := parseAlertmanagerRequests(&alertmanagerPayload{Alerts: tt.alerts}, "service")
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Fatalf("expected %v, actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWebhookAuthenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		expected      int
+	}{
+		{name: "no token configured", expected: http.StatusOK},
+		{name: "valid token", token: "secret", authorization: "Bearer secret", expected: http.StatusOK},
+		{name: "missing token", token: "secret", expected: http.StatusUnauthorized},
+		{name: "invalid token", token: "secret", authorization: "Bearer other", expected: http.StatusUnauthorized},
+		{name: "token without the bearer", token: "secret", authorization: "secret", expected: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Webhook{config: &base.WebhookConfig{Token: tt.token}}
+			handler := w.authenticate(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/continuous/trigger", http.NoBody)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != tt.expected {
+				t.Errorf("expected status: %d, actual: %d", tt.expected, recorder.Code)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/profiling/continuous"
 
 	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 	"github.com/apache/skywalking-rover/pkg/profiling/task"
 )
 
@@ -50,12 +51,17 @@ func NewManager(ctx context.Context, manager *module.Manager, conf *Config) (*Ma
 		return nil, fmt.Errorf("parse profiling data flush interval failure: %v", err)
 	}
 
-	taskManager, err := task.NewManager(ctx, manager, conf.TaskConfig)
+	processFilter, err := filter.New(conf.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("build the profiling process filter failure: %v", err)
+	}
+
+	taskManager, err := task.NewManager(ctx, manager, conf.TaskConfig, processFilter)
 	if err != nil {
 		return nil, err
 	}
 
-	continuousManager, err := continuous.NewManager(ctx, taskManager, manager, conf.ContinuousConfig)
+	continuousManager, err := continuous.NewManager(ctx, taskManager, manager, conf.ContinuousConfig, processFilter)
 	if err != nil {
 		return nil, err
 	}
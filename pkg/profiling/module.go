@@ -26,6 +26,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/tools/capability"
 )
 
 const ModuleName = "profiling"
@@ -50,6 +51,10 @@ func (m *Module) RequiredModules() []string {
 	return []string{core.ModuleName, process.ModuleName}
 }
 
+func (m *Module) RequiredCapabilities() []string {
+	return []string{capability.Kprobe, capability.PerfEvent}
+}
+
 func (m *Module) Config() module.ConfigInterface {
 	return m.config
 }
@@ -26,18 +26,32 @@ import (
 )
 
 type TaskConfig struct {
-	OnCPU   *OnCPUConfig   `mapstructure:"on_cpu"`  // ON_CPU type of profiling task config
-	Network *NetworkConfig `mapstructure:"network"` // NETWORK type of profiling task config
+	Exporter   string            `mapstructure:"exporter"`    // The exporters(split by ",") of the profiling data, supports "grpc" and "otlp"
+	OnCPU      *OnCPUConfig      `mapstructure:"on_cpu"`      // ON_CPU type of profiling task config
+	Network    *NetworkConfig    `mapstructure:"network"`     // NETWORK type of profiling task config
+	MemoryLeak *MemoryLeakConfig `mapstructure:"memory_leak"` // MEMORY_LEAK type of profiling task config
 }
 
 type OnCPUConfig struct {
-	Period string `mapstructure:"dump_period"` // The duration of dump stack
+	Period          string `mapstructure:"dump_period"`        // The duration of dump stack
+	JavaPerfMapDump bool   `mapstructure:"java_perf_map_dump"` // Trigger the JVM to dump the JIT symbols into the perf map file
+}
+
+type MemoryLeakConfig struct {
+	SampleRate int    `mapstructure:"sample_rate"` // Only track one of each sample rate allocations
+	MinAge     string `mapstructure:"min_age"`     // The min duration of the outstanding allocation to be reported
 }
 
 type NetworkConfig struct {
 	ReportInterval  string                `mapstructure:"report_interval"`  // The duration of data report interval
 	MeterPrefix     string                `mapstructure:"meter_prefix"`     // The prefix of meter name
 	ProtocolAnalyze ProtocolAnalyzeConfig `mapstructure:"protocol_analyze"` // The 7-Layer protocol analyze
+	RingBuffer      RingBufferConfig      `mapstructure:"ring_buffer"`      // The BPF ring buffer of the data queues
+}
+
+type RingBufferConfig struct {
+	Active bool   `mapstructure:"active"` // Is transporting the data through the BPF ring buffer when supported
+	Size   string `mapstructure:"size"`   // The size of each ring buffer
 }
 
 type ProtocolAnalyzeConfig struct {
@@ -29,6 +29,7 @@ const (
 	TargetTypeOnCPU           TargetType = "ON_CPU"
 	TargetTypeOffCPU          TargetType = "OFF_CPU"
 	TargetTypeNetworkTopology TargetType = "NETWORK"
+	TargetTypeMemoryLeak      TargetType = "MEMORY_LEAK"
 )
 
 func ParseTargetType(err error, val string) (TargetType, error) {
@@ -41,6 +42,8 @@ func ParseTargetType(err error, val string) (TargetType, error) {
 		return TargetTypeOffCPU, nil
 	} else if TargetType(val) == TargetTypeNetworkTopology {
 		return TargetTypeNetworkTopology, nil
+	} else if TargetType(val) == TargetTypeMemoryLeak {
+		return TargetTypeMemoryLeak, nil
 	}
 	return "", fmt.Errorf("could not found target type: %s", val)
 }
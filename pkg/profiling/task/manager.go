@@ -20,17 +20,24 @@ package task
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/core/otlp"
 
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/process"
 	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/process/filter"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/base"
 
+	"github.com/hashicorp/go-multierror"
+
+	profilesv1 "go.opentelemetry.io/proto/otlp/profiles/v1development"
+
 	common_v3 "skywalking.apache.org/repo/goapi/collect/common/v3"
 	profiling_v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
 )
@@ -40,7 +47,11 @@ var log = logger.GetLogger("profiling", "task")
 type Manager struct {
 	moduleMgr       *module.Manager
 	processOperator process.Operator
+	processFilter   *filter.Filter
 	profilingClient profiling_v3.EBPFProfilingServiceClient
+	otlpOperator    otlp.Operator
+	exportGRPC      bool
+	lastFlushTime   time.Time
 	ctx             context.Context
 	cancel          context.CancelFunc
 	taskConfig      *base.TaskConfig
@@ -50,7 +61,7 @@ type Manager struct {
 	lastUpdateTime int64
 }
 
-func NewManager(ctx context.Context, moduleMgr *module.Manager, taskConfig *base.TaskConfig) (*Manager, error) {
+func NewManager(ctx context.Context, moduleMgr *module.Manager, taskConfig *base.TaskConfig, processFilter *filter.Filter) (*Manager, error) {
 	coreOperator := moduleMgr.FindModule(core.ModuleName).(core.Operator)
 	connection := coreOperator.BackendOperator().GetConnection()
 	profilingClient := profiling_v3.NewEBPFProfilingServiceClient(connection)
@@ -59,11 +70,20 @@ func NewManager(ctx context.Context, moduleMgr *module.Manager, taskConfig *base
 		return nil, err
 	}
 
+	exportGRPC, otlpOperator, err := parseProfilingExporters(taskConfig.Exporter, coreOperator)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	manager := &Manager{
 		moduleMgr:       moduleMgr,
 		processOperator: processOperator,
+		processFilter:   processFilter,
 		profilingClient: profilingClient,
+		otlpOperator:    otlpOperator,
+		exportGRPC:      exportGRPC,
+		lastFlushTime:   time.Now(),
 		taskConfig:      taskConfig,
 		tasks:           make(map[string]*Context),
 		instanceID:      coreOperator.InstanceID(),
@@ -73,6 +93,22 @@ func NewManager(ctx context.Context, moduleMgr *module.Manager, taskConfig *base
 	return manager, nil
 }
 
+func parseProfilingExporters(exporters string, coreOperator core.Operator) (exportGRPC bool, otlpOperator otlp.Operator, err error) {
+	for _, exporter := range strings.Split(exporters, ",") {
+		switch strings.TrimSpace(exporter) {
+		case "", "grpc":
+			exportGRPC = true
+		case "otlp":
+			if otlpOperator = coreOperator.OTLPOperator(); otlpOperator == nil {
+				return false, nil, fmt.Errorf("the OTLP is not active in the core module, cannot export the profiling data")
+			}
+		default:
+			return false, nil, fmt.Errorf("unknown profiling data exporter: %s", exporter)
+		}
+	}
+	return exportGRPC, otlpOperator, nil
+}
+
 func (m *Manager) Start() {
 }
 
@@ -90,6 +126,9 @@ func (m *Manager) BuildContextFromCommand(command *common_v3.Command) (*Context,
 		if taskProcess == nil {
 			return nil, fmt.Errorf("could not found %s processes %s", t.TaskID, t.ProcessIDList)
 		}
+		if !m.processFilter.Match(taskProcess) {
+			return nil, fmt.Errorf("the process %s of task %s is excluded by the profiling filter", processID, t.TaskID)
+		}
 		processes = append(processes, taskProcess)
 	}
 
@@ -342,17 +381,23 @@ func (m *Manager) StartingWatchTask() error {
 func (m *Manager) FlushProfilingData() error {
 	// cleanup the stopped after flush profiling data to make sure all the profiling data been sent
 	defer m.checkStoppedTaskAndRemoved()
+	flushTime, lastFlushTime := time.Now(), m.lastFlushTime
+	m.lastFlushTime = flushTime
 	if len(m.tasks) == 0 {
 		log.Debugf("no profiling task need to flush")
 		return nil
 	}
 
-	stream, err := m.profilingClient.CollectProfilingData(m.ctx)
-	if err != nil {
-		return err
+	var stream profiling_v3.EBPFProfilingService_CollectProfilingDataClient
+	if m.exportGRPC {
+		var err error
+		if stream, err = m.profilingClient.CollectProfilingData(m.ctx); err != nil {
+			return err
+		}
 	}
-	currentMilli := time.Now().UnixMilli()
+	currentMilli := flushTime.UnixMilli()
 	totalSendCount := make(map[string]int)
+	otlpProfiles := make([]*profilesv1.ResourceProfiles, 0)
 	for _, t := range m.tasks {
 		data, err1 := t.runner.FlushData()
 		if err1 != nil {
@@ -365,6 +410,14 @@ func (m *Manager) FlushProfilingData() error {
 		}
 
 		totalSendCount[t.TaskID()] += len(data)
+		if m.otlpOperator != nil {
+			if profile := buildOTLPProfile(t, data, lastFlushTime, flushTime); profile != nil {
+				otlpProfiles = append(otlpProfiles, profile)
+			}
+		}
+		if stream == nil {
+			continue
+		}
 		// only the first data have task metadata
 		data[0].Task = &profiling_v3.EBPFProfilingTaskMetadata{
 			TaskId:             t.task.TaskID,
@@ -384,6 +437,16 @@ func (m *Manager) FlushProfilingData() error {
 	if len(totalSendCount) > 0 {
 		log.Infof("send profiling data summary: %v", totalSendCount)
 	}
-	_, err = stream.CloseAndRecv()
-	return err
+	var result error
+	if m.otlpOperator != nil {
+		if err := m.otlpOperator.ExportProfiles(m.ctx, otlpProfiles); err != nil {
+			result = multierror.Append(result, fmt.Errorf("export the profiling data to OTLP failure: %v", err))
+		}
+	}
+	if stream != nil {
+		if _, err := stream.CloseAndRecv(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
 }
@@ -0,0 +1,105 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package memleak
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/tools/profiling"
+)
+
+// allocationKind is the arguments layout of the allocation functions, each kind is attached with the same programs
+type allocationKind int
+
+const (
+	allocationMalloc allocationKind = iota
+	allocationCalloc
+	allocationRealloc
+	allocationAligned
+	allocationPosixMemalign
+	allocationFree
+)
+
+// the shared library which provides the allocation functions, such as glibc, musl, jemalloc and tcmalloc
+var allocatorModuleRegex = regexp.MustCompile(`^libc[.-]|^ld-musl|^libjemalloc[.-]|^libtcmalloc(_minimal)?[.-]`)
+
+// the prefixes of the allocation functions which could be statically linked into the executable file,
+// such as the jemalloc built with the prefix(je_), the jemalloc of the rust(_rjem_) and the tcmalloc(tc_)
+var allocatorSymbolPrefixes = []string{"je_", "_rjem_", "tc_"}
+
+// the allocation functions of the allocators, the standard names are in the front
+var allocationFunctions = []struct {
+	kind    allocationKind
+	symbols []string
+}{
+	{allocationMalloc, []string{"malloc", "je_malloc", "_rjem_malloc", "tc_malloc", "mallocx", "je_mallocx", "_rjem_mallocx",
+		"tc_new", "tc_newarray"}},
+	{allocationCalloc, []string{"calloc", "je_calloc", "_rjem_calloc", "tc_calloc"}},
+	{allocationRealloc, []string{"realloc", "je_realloc", "_rjem_realloc", "tc_realloc", "rallocx", "je_rallocx", "_rjem_rallocx"}},
+	{allocationAligned, []string{"memalign", "aligned_alloc", "je_memalign", "je_aligned_alloc", "_rjem_memalign",
+		"_rjem_aligned_alloc", "tc_memalign"}},
+	{allocationPosixMemalign, []string{"posix_memalign", "je_posix_memalign", "_rjem_posix_memalign", "tc_posix_memalign"}},
+	{allocationFree, []string{"free", "je_free", "_rjem_free", "tc_free", "sdallocx", "je_sdallocx", "_rjem_sdallocx",
+		"dallocx", "je_dallocx", "_rjem_dallocx", "tc_delete", "tc_deletearray"}},
+}
+
+// findAllocationFunctions finds the allocation functions which need to be attached in the module, grouped by the kind,
+// returns nil when the module is not an allocator. The aliases of the same function are only attached once.
+func findAllocationFunctions(module *profiling.Module) map[allocationKind][]string {
+	symbols := make(map[string]uint64, len(module.Symbols))
+	for _, s := range module.Symbols {
+		symbols[s.Name] = s.Location
+	}
+	if !allocatorModuleRegex.MatchString(module.Name) && !containsPrefixedAllocationFunction(symbols) {
+		return nil
+	}
+
+	result := make(map[allocationKind][]string)
+	attached := make(map[uint64]bool)
+	for _, f := range allocationFunctions {
+		for _, name := range f.symbols {
+			location, exist := symbols[name]
+			if !exist || attached[location] {
+				continue
+			}
+			attached[location] = true
+			result[f.kind] = append(result[f.kind], name)
+		}
+	}
+	if len(result[allocationMalloc]) == 0 || len(result[allocationFree]) == 0 {
+		return nil
+	}
+	return result
+}
+
+func containsPrefixedAllocationFunction(symbols map[string]uint64) bool {
+	for _, f := range allocationFunctions {
+		for _, name := range f.symbols {
+			if _, exist := symbols[name]; !exist {
+				continue
+			}
+			for _, prefix := range allocatorSymbolPrefixes {
+				if strings.HasPrefix(name, prefix) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
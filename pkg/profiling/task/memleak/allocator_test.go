@@ -0,0 +1,75 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package memleak
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/profiling"
+)
+
+func TestFindAllocationFunctions(t *testing.T) {
+	symbols := func(names ...string) []*profiling.Symbol {
+		result := make([]*profiling.Symbol, 0, len(names))
+		for i, n := range names {
+			result = append(result, &profiling.Symbol{Name: n, Location: uint64(i + 1)})
+		}
+		return result
+	}
+	tests := []struct {
+		name     string
+		module   *profiling.Module
+		expected map[allocationKind][]string
+	}{
+		{
+			name:   "glibc",
+			module: &profiling.Module{Name: "libc.so.6", Symbols: symbols("malloc", "calloc", "free", "posix_memalign", "strlen")},
+			expected: map[allocationKind][]string{
+				allocationMalloc: {"malloc"}, allocationCalloc: {"calloc"}, allocationFree: {"free"},
+				allocationPosixMemalign: {"posix_memalign"},
+			},
+		},
+		{
+			name: "tcmalloc aliases",
+			module: &profiling.Module{Name: "libtcmalloc.so.4", Symbols: []*profiling.Symbol{
+				{Name: "malloc", Location: 1}, {Name: "tc_malloc", Location: 1}, {Name: "free", Location: 2}, {Name: "tc_free", Location: 2},
+			}},
+			expected: map[allocationKind][]string{allocationMalloc: {"malloc"}, allocationFree: {"free"}},
+		},
+		{
+			name:   "static rust jemalloc",
+			module: &profiling.Module{Name: "server", Symbols: symbols("main", "_rjem_malloc", "_rjem_sdallocx")},
+			expected: map[allocationKind][]string{
+				allocationMalloc: {"_rjem_malloc"}, allocationFree: {"_rjem_sdallocx"},
+			},
+		},
+		{
+			name:     "not allocator",
+			module:   &profiling.Module{Name: "libssl.so.3", Symbols: symbols("SSL_read", "malloc", "free")},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := findAllocationFunctions(tt.module); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, but got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package memleak
+
+import "time"
+
+// AllocInfo is the sampled allocation which not been freed
+type AllocInfo struct {
+	Size        uint64
+	Timestamp   uint64
+	UserStackID int32
+	Reported    uint32
+}
+
+type OutstandingCounter struct {
+	Count uint64
+	Bytes uint64
+}
+
+// aggregateOutstanding aggregates the allocations which are outstanding longer than the min age and not reported yet,
+// return the counters of each user stack and the address of the aggregated allocations
+func aggregateOutstanding(allocations map[uint64]AllocInfo, now uint64, minAge time.Duration) (
+	outstanding map[int32]*OutstandingCounter, reported []uint64) {
+	outstanding = make(map[int32]*OutstandingCounter)
+	for addr, info := range allocations {
+		if info.Reported != 0 || info.Timestamp > now || now-info.Timestamp < uint64(minAge.Nanoseconds()) {
+			continue
+		}
+		reported = append(reported, addr)
+		// the stack cannot be collected
+		if info.UserStackID < 0 {
+			continue
+		}
+		counter := outstanding[info.UserStackID]
+		if counter == nil {
+			counter = &OutstandingCounter{}
+			outstanding[info.UserStackID] = counter
+		}
+		counter.Count++
+		counter.Bytes += info.Size
+	}
+	return outstanding, reported
+}
@@ -0,0 +1,53 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package memleak
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestAggregateOutstanding(t *testing.T) {
+	now := uint64(100 * time.Second)
+	allocations := map[uint64]AllocInfo{
+		// outstanding
+		0x1000: {Size: 16, Timestamp: uint64(10 * time.Second), UserStackID: 1},
+		0x2000: {Size: 32, Timestamp: uint64(20 * time.Second), UserStackID: 1},
+		0x3000: {Size: 64, Timestamp: uint64(30 * time.Second), UserStackID: 2},
+		// too young
+		0x4000: {Size: 128, Timestamp: uint64(90 * time.Second), UserStackID: 2},
+		// already reported
+		0x5000: {Size: 256, Timestamp: uint64(10 * time.Second), UserStackID: 3, Reported: 1},
+		// no stack
+		0x6000: {Size: 512, Timestamp: uint64(10 * time.Second), UserStackID: -14},
+	}
+	outstanding, reported := aggregateOutstanding(allocations, now, time.Minute)
+	expected := map[int32]*OutstandingCounter{
+		1: {Count: 2, Bytes: 48},
+		2: {Count: 1, Bytes: 64},
+	}
+	if !reflect.DeepEqual(outstanding, expected) {
+		t.Fatalf("expected outstanding %v, actual %v", expected, outstanding)
+	}
+	sort.Slice(reported, func(i, j int) bool { return reported[i] < reported[j] })
+	if !reflect.DeepEqual(reported, []uint64{0x1000, 0x2000, 0x3000, 0x6000}) {
+		t.Fatalf("unexpected reported allocations: %v", reported)
+	}
+}
@@ -0,0 +1,233 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build linux
+
+package memleak
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/cilium/ebpf"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/base"
+	"github.com/apache/skywalking-rover/pkg/tools/btf"
+	"github.com/apache/skywalking-rover/pkg/tools/process"
+	"github.com/apache/skywalking-rover/pkg/tools/profiling"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
+)
+
+// $BPF_CLANG and $BPF_CFLAGS are set by the Makefile.
+// nolint
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -no-global-types -target $TARGET -cc $BPF_CLANG -cflags $BPF_CFLAGS bpf $REPO_ROOT/bpf/profiling/memleak.c -- -I$REPO_ROOT/bpf/include
+
+var log = logger.GetLogger("profiling", "task", "memleak")
+
+type Runner struct {
+	base             *base.Runner
+	pid              int32
+	processProfiling *profiling.Info
+	sampleRate       int
+	minAge           time.Duration
+
+	// runtime
+	bpf             *bpfObjects
+	uprobe          *btf.Linker
+	stopChan        chan bool
+	flushDataNotify context.CancelFunc
+}
+
+func NewRunner(config *base.TaskConfig, _ *module.Manager) (base.ProfileTaskRunner, error) {
+	if config.MemoryLeak == nil {
+		return nil, fmt.Errorf("please provide the MEMORY_LEAK config")
+	}
+	if config.MemoryLeak.SampleRate < 1 {
+		return nil, fmt.Errorf("the MEMORY_LEAK sample rate must be bigger than 0")
+	}
+	minAge, err := time.ParseDuration(config.MemoryLeak.MinAge)
+	if err != nil {
+		return nil, fmt.Errorf("the MEMORY_LEAK min age format not right, current value: %s", config.MemoryLeak.MinAge)
+	}
+	return &Runner{
+		base:       base.NewBaseRunner(),
+		sampleRate: config.MemoryLeak.SampleRate,
+		minAge:     minAge,
+	}, nil
+}
+
+func (r *Runner) Init(_ *base.ProfilingTask, processes []api.ProcessInterface) error {
+	if len(processes) != 1 {
+		return fmt.Errorf("the processes count must be 1, current is: %d", len(processes))
+	}
+	curProcess := processes[0]
+	r.pid = curProcess.Pid()
+	r.processProfiling = curProcess.ProfilingStat()
+	r.stopChan = make(chan bool, 1)
+	return nil
+}
+
+func (r *Runner) Run(_ context.Context, notify base.ProfilingRunningSuccessNotify) error {
+	modules, err := process.Modules(r.pid)
+	if err != nil {
+		return fmt.Errorf("read the modules of the process failure: %v", err)
+	}
+	allocators := make(map[string]map[allocationKind][]string)
+	for _, m := range modules {
+		if functions := findAllocationFunctions(m); functions != nil {
+			allocators[m.Path] = functions
+		}
+	}
+	if len(allocators) == 0 {
+		return fmt.Errorf("could not found the memory allocator of the process")
+	}
+
+	objs := bpfObjects{}
+	spec, err := loadBpf()
+	if err != nil {
+		return err
+	}
+	// update the monitor pid and sample rate
+	for _, p := range spec.Programs {
+		for i, ins := range p.Instructions {
+			switch ins.Reference() {
+			case "MONITOR_PID":
+				p.Instructions[i].Constant = int64(r.pid)
+				p.Instructions[i].Offset = 0
+			case "SAMPLE_RATE":
+				p.Instructions[i].Constant = int64(r.sampleRate)
+				p.Instructions[i].Offset = 0
+			}
+		}
+	}
+	if err1 := spec.LoadAndAssign(&objs, btf.GetEBPFCollectionOptionsIfNeed(spec)); err1 != nil {
+		return err1
+	}
+	r.bpf = &objs
+
+	linker := btf.NewLinker()
+	for path, functions := range allocators {
+		log.Debugf("attach to the allocation functions of %s: %v", path, functions)
+		file := linker.OpenUProbeExeFile(path)
+		file.AddLinkWithSymbols(functions[allocationMalloc], objs.MallocEnter, objs.MallocExit)
+		file.AddLinkWithSymbols(functions[allocationCalloc], objs.CallocEnter, objs.CallocExit)
+		file.AddLinkWithSymbols(functions[allocationRealloc], objs.ReallocEnter, objs.ReallocExit)
+		file.AddLinkWithSymbols(functions[allocationAligned], objs.AlignedAllocEnter, objs.AlignedAllocExit)
+		file.AddLinkWithSymbols(functions[allocationPosixMemalign], objs.PosixMemalignEnter, objs.PosixMemalignExit)
+		for _, free := range functions[allocationFree] {
+			file.AddLinkWithType(free, true, objs.FreeEnterProbe)
+		}
+	}
+	r.uprobe = linker
+	if err := linker.HasError(); err != nil {
+		return fmt.Errorf("link to the allocation functions failure: %v", err)
+	}
+
+	notify()
+	<-r.stopChan
+	return nil
+}
+
+func (r *Runner) Stop() error {
+	var err error
+	r.base.ShutdownOnce.Do(func() {
+		// wait for all profiling data been consumed finished
+		cancel, cancelFunc := context.WithCancel(context.Background())
+		r.flushDataNotify = cancelFunc
+		select {
+		case <-cancel.Done():
+		case <-time.After(10 * time.Second):
+		}
+
+		if r.bpf != nil {
+			if err1 := r.bpf.Close(); err1 != nil {
+				err = multierror.Append(err, err1)
+			}
+			r.bpf = nil
+		}
+		if r.uprobe != nil {
+			if err1 := r.uprobe.Close(); err1 != nil {
+				err = multierror.Append(err, err1)
+			}
+		}
+		close(r.stopChan)
+	})
+	return err
+}
+
+// FlushData report the stacks of the allocations which are outstanding longer than the min age,
+// each allocation is only reported once, the switch count is the allocations count and the duration is the total bytes
+func (r *Runner) FlushData() ([]*v3.EBPFProfilingData, error) {
+	if r.bpf == nil {
+		return nil, nil
+	}
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return nil, err
+	}
+
+	allocations := make(map[uint64]AllocInfo)
+	var addr uint64
+	var info AllocInfo
+	iterate := r.bpf.Allocations.Iterate()
+	for iterate.Next(&addr, &info) {
+		allocations[addr] = info
+	}
+	outstanding, reported := aggregateOutstanding(allocations, uint64(ts.Nano()), r.minAge)
+	for _, a := range reported {
+		info := allocations[a]
+		info.Reported = 1
+		// the allocation may be freed already
+		if err := r.bpf.Allocations.Update(a, info, ebpf.UpdateExist); err != nil {
+			log.Debugf("mark the allocation as reported failure: %v", err)
+		}
+	}
+
+	result := make([]*v3.EBPFProfilingData, 0, len(outstanding))
+	stackSymbols := make([]uint64, 100)
+	for stackID, counter := range outstanding {
+		d := r.base.GenerateProfilingData(r.processProfiling, uint32(stackID), r.bpf.Stacks,
+			v3.EBPFProfilingStackType_PROCESS_USER_SPACE, stackSymbols)
+		if d == nil {
+			continue
+		}
+		result = append(result, &v3.EBPFProfilingData{
+			Profiling: &v3.EBPFProfilingData_OffCPU{
+				OffCPU: &v3.EBPFOffCPUProfiling{
+					Stacks:      []*v3.EBPFProfilingStackMetadata{d},
+					SwitchCount: int32(counter.Count),
+					Duration:    int64(counter.Bytes),
+				},
+			},
+		})
+	}
+	log.Debugf("total found %d outstanding allocation stacks", len(result))
+
+	if r.flushDataNotify != nil {
+		r.flushDataNotify()
+	}
+	return result, nil
+}
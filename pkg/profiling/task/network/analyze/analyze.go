@@ -22,6 +22,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/base"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/layer4"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/layer7"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/tls"
 )
 
 // NewContext Wrap the analyzer builder
@@ -30,6 +31,7 @@ func NewContext(monitorProcesses map[int32][]api.ProcessInterface) *base.Analyze
 	// register all listeners
 	context.AddListener(layer4.NewListener())
 	context.AddListener(layer7.NewListener(context))
+	context.AddListener(tls.NewListener())
 
 	return context
 }
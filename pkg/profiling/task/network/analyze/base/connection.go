@@ -98,4 +98,8 @@ type ActiveConnectionInBPF struct {
 	IsSSL              uint8
 	_                  uint8
 	_                  uint32
+
+	// the latest TCP quality of the connection
+	SRTTUs  uint32
+	SndCwnd uint32
 }
@@ -69,4 +69,7 @@ type SocketCloseEvent struct {
 
 	WriteRTTCount   uint32
 	WriteRTTExeTime uint32
+
+	SRTTUs  uint32
+	SndCwnd uint32
 }
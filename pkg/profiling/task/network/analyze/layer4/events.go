@@ -39,6 +39,9 @@ type SocketExceptionOperationEvent struct {
 	LocalAddrV6    [16]uint8
 	LocalAddrPort  uint32
 	Type           enums.SocketExceptionOperationType
+	SRTTUs         uint32
+	SndCwnd        uint32
+	DropReason     uint32
 }
 
 func (l *Listener) handleSocketExceptionOperationEvent(data interface{}) {
@@ -64,9 +67,23 @@ func (l *Listener) handleSocketExceptionOperationEvent(data interface{}) {
 		exceptionValue.RetransmitCount++
 	case enums.SocketExceptionOperationDrop:
 		exceptionValue.DropCount++
+		if event.DropReason != 0 {
+			if exceptionValue.DropReasons == nil {
+				exceptionValue.DropReasons = make(map[uint32]int)
+			}
+			exceptionValue.DropReasons[event.DropReason]++
+		}
+	case enums.SocketExceptionOperationOutOfOrder:
+		exceptionValue.OutOfOrderCount++
 	default:
 		log.Warnf("unknown socket exception operation type: %d", event.Type)
 	}
+	// the TCP quality snapshot
+	if event.SRTTUs > 0 || event.SndCwnd > 0 {
+		exceptionValue.QualitySampleCount++
+		exceptionValue.TotalSRTTUs += uint64(event.SRTTUs)
+		exceptionValue.TotalSndCwnd += uint64(event.SndCwnd)
+	}
 
 	if log.Enable(logrus.DebugLevel) {
 		marshal, _ := json.Marshal(event)
@@ -88,4 +105,12 @@ type SocketBasicKey struct {
 type SocketExceptionValue struct {
 	DropCount       int
 	RetransmitCount int
+	OutOfOrderCount int
+	// the drop count with the kernel drop reason
+	DropReasons map[uint32]int
+
+	// the TCP quality sampling(SRTT and congestion window) when the exception operation happens
+	QualitySampleCount int
+	TotalSRTTUs        uint64
+	TotalSndCwnd       uint64
 }
@@ -0,0 +1,87 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package layer4
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func TestHandleSocketExceptionOperationEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []*SocketExceptionOperationEvent
+		expected *SocketExceptionValue
+	}{
+		{
+			name: "retransmit with the quality sampling",
+			events: []*SocketExceptionOperationEvent{
+				{Type: enums.SocketExceptionOperationRetransmit, SRTTUs: 100, SndCwnd: 10},
+				{Type: enums.SocketExceptionOperationRetransmit, SRTTUs: 300, SndCwnd: 20},
+			},
+			expected: &SocketExceptionValue{RetransmitCount: 2, QualitySampleCount: 2, TotalSRTTUs: 400, TotalSndCwnd: 30},
+		},
+		{
+			name: "drop with reasons",
+			events: []*SocketExceptionOperationEvent{
+				{Type: enums.SocketExceptionOperationDrop, DropReason: 2},
+				{Type: enums.SocketExceptionOperationDrop, DropReason: 2},
+				{Type: enums.SocketExceptionOperationDrop},
+			},
+			expected: &SocketExceptionValue{DropCount: 3, DropReasons: map[uint32]int{2: 2}},
+		},
+		{
+			name: "out of order without the quality",
+			events: []*SocketExceptionOperationEvent{
+				{Type: enums.SocketExceptionOperationOutOfOrder},
+			},
+			expected: &SocketExceptionValue{OutOfOrderCount: 1},
+		},
+		{
+			name: "unknown type only sampled",
+			events: []*SocketExceptionOperationEvent{
+				{Type: 100, SRTTUs: 50},
+			},
+			expected: &SocketExceptionValue{QualitySampleCount: 1, TotalSRTTUs: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := NewListener()
+			for _, event := range tt.events {
+				event.Pid = 1
+				event.RemoteAddrPort = 80
+				listener.handleSocketExceptionOperationEvent(event)
+			}
+			values := listener.cleanAndGetAllExceptionContexts()
+			if len(values) != 1 {
+				t.Fatalf("expected 1 socket, actual: %d", len(values))
+			}
+			for _, value := range values {
+				if !reflect.DeepEqual(value, tt.expected) {
+					t.Errorf("expected: %+v, actual: %+v", tt.expected, value)
+				}
+			}
+			if len(listener.cleanAndGetAllExceptionContexts()) != 0 {
+				t.Errorf("the exception values should be cleaned")
+			}
+		})
+	}
+}
@@ -21,7 +21,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/sirupsen/logrus"
@@ -32,6 +34,7 @@ import (
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/base"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/events"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network/bpf"
+	"github.com/apache/skywalking-rover/pkg/tools/btf"
 	"github.com/apache/skywalking-rover/pkg/tools/enums"
 
 	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
@@ -45,6 +48,10 @@ type Listener struct {
 	// socket retransmit/drop
 	socketExceptionStatics       map[SocketBasicKey]*SocketExceptionValue
 	socketExceptionOperationLock sync.Mutex
+	// the kernel drop reason names, read from the BTF
+	dropReasons map[uint64]string
+	// the report interval, for calculating the throughput
+	reportInterval time.Duration
 }
 
 func NewListener() *Listener {
@@ -57,7 +64,18 @@ func (l *Listener) Name() string {
 	return Name
 }
 
-func (l *Listener) Init(*profiling.TaskConfig, *module.Manager) error {
+func (l *Listener) Init(config *profiling.TaskConfig, _ *module.Manager) error {
+	reportInterval, err := time.ParseDuration(config.Network.ReportInterval)
+	if err != nil {
+		return fmt.Errorf("parsing report interval failure: %v", err)
+	}
+	l.reportInterval = reportInterval
+
+	reasons, err := btf.KernelEnumValues("skb_drop_reason")
+	if err != nil {
+		log.Warnf("cannot read the drop reasons from the kernel, the drop reason would be the code: %v", err)
+	}
+	l.dropReasons = reasons
 	return nil
 }
 
@@ -82,6 +100,7 @@ func (l *Listener) ReceiveCloseConnection(ctx *base.ConnectionContext, event *ev
 	layer4.WriteCounter.UpdateToCurrent(event.WriteBytes, event.WriteCount, event.WriteExeTime)
 	layer4.ReadCounter.UpdateToCurrent(event.ReadBytes, event.ReadCount, event.ReadExeTime)
 	layer4.WriteRTTCounter.UpdateToCurrent(0, uint64(event.WriteRTTCount), uint64(event.WriteRTTExeTime))
+	l.sampleTCPQuality(layer4, event.SRTTUs, event.SndCwnd)
 
 	// connection close execute time
 	layer4.CloseExecuteTime = event.ExeTime
@@ -103,7 +122,9 @@ func (l *Listener) PreFlushConnectionMetrics(ccs []*base.ConnectionWithBPF, bpfL
 			layer4.WriteCounter.UpdateToCurrent(activeConnection.WriteBytes, activeConnection.WriteCount, activeConnection.WriteExeTime)
 			layer4.ReadCounter.UpdateToCurrent(activeConnection.ReadBytes, activeConnection.ReadCount, activeConnection.ReadExeTime)
 			layer4.WriteRTTCounter.UpdateToCurrent(0, activeConnection.WriteRTTCount, activeConnection.WriteRTTExeTime)
+			l.sampleTCPQuality(layer4, activeConnection.SRTTUs, activeConnection.SndCwnd)
 		}
+		l.increaseThroughput(layer4)
 		// build cache
 		keyWithContext[l.generateConID(connection.ConnectionID, connection.RandomID)] = connection
 
@@ -162,6 +183,30 @@ func (l *Listener) PreFlushConnectionMetrics(ccs []*base.ConnectionWithBPF, bpfL
 	return nil
 }
 
+// sampleTCPQuality add the latest smoothed RTT and congestion window of the connection as one sample
+func (l *Listener) sampleTCPQuality(layer4 *Metrics, srttUs, sndCwnd uint32) {
+	if srttUs == 0 && sndCwnd == 0 {
+		// not a TCP connection or no data transmitted yet
+		return
+	}
+	layer4.SRTTCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, 1, uint64(srttUs)))
+	layer4.CongestionWindowCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, 1, uint64(sndCwnd)))
+}
+
+// increaseThroughput add the write/read bytes per second in current report interval into the histogram
+func (l *Listener) increaseThroughput(layer4 *Metrics) {
+	seconds := l.reportInterval.Seconds()
+	if seconds <= 0 {
+		return
+	}
+	if bytes := layer4.WriteCounter.CalculateIncrease().Bytes; bytes > 0 {
+		layer4.WriteThroughputHistogram.Cur.IncreaseByValue(uint64(float64(bytes) / seconds))
+	}
+	if bytes := layer4.ReadCounter.CalculateIncrease().Bytes; bytes > 0 {
+		layer4.ReadThroughputHistogram.Cur.IncreaseByValue(uint64(float64(bytes) / seconds))
+	}
+}
+
 func (l *Listener) PostFlushConnectionMetrics(ccs []*base.ConnectionContext) {
 	for _, connection := range ccs {
 		metrics := l.getMetrics(connection.Metrics)
@@ -173,12 +218,22 @@ func (l *Listener) PostFlushConnectionMetrics(ccs []*base.ConnectionContext) {
 		metrics.WriteRTTHistogram.RefreshCurrent()
 		metrics.WriteExeTimeHistogram.RefreshCurrent()
 		metrics.ReadExeTimeHistogram.RefreshCurrent()
+		metrics.WriteThroughputHistogram.RefreshCurrent()
+		metrics.ReadThroughputHistogram.RefreshCurrent()
 		metrics.ConnectCounter.RefreshCurrent()
 		metrics.CloseCounter.RefreshCurrent()
 		metrics.ConnectExeTimeHistogram.RefreshCurrent()
 		metrics.CloseExeTimeHistogram.RefreshCurrent()
 		metrics.RetransmitCounter.RefreshCurrent()
 		metrics.DropCounter.RefreshCurrent()
+		metrics.OutOfOrderCounter.RefreshCurrent()
+		for _, counter := range metrics.DropReasonCounters {
+			counter.RefreshCurrent()
+		}
+		metrics.SRTTCounter.RefreshCurrent()
+		metrics.CongestionWindowCounter.RefreshCurrent()
+		metrics.ExceptionSRTTCounter.RefreshCurrent()
+		metrics.ExceptionCwndCounter.RefreshCurrent()
 	}
 }
 
@@ -197,10 +252,18 @@ func (l *Listener) FlushMetrics(traffics []*base.ProcessTraffic, builder *base.M
 			collection = l.appendCounterValues(collection, metricsPrefix, "close", traffic, p, metrics.CloseCounter, builder)
 			collection = l.appendCounterValues(collection, metricsPrefix, "retransmit", traffic, p, metrics.RetransmitCounter, builder)
 			collection = l.appendCounterValues(collection, metricsPrefix, "drop", traffic, p, metrics.DropCounter, builder)
+			collection = l.appendCounterValues(collection, metricsPrefix, "out_of_order", traffic, p, metrics.OutOfOrderCounter, builder)
+			collection = l.appendDropReasonValues(collection, metricsPrefix, traffic, p, metrics, builder)
+			collection = l.appendAvgValue(collection, metricsPrefix, "srtt", traffic, p, metrics.SRTTCounter, builder)
+			collection = l.appendAvgValue(collection, metricsPrefix, "congestion_window", traffic, p, metrics.CongestionWindowCounter, builder)
+			collection = l.appendAvgValue(collection, metricsPrefix, "exception_srtt", traffic, p, metrics.ExceptionSRTTCounter, builder)
+			collection = l.appendAvgValue(collection, metricsPrefix, "exception_cwnd", traffic, p, metrics.ExceptionCwndCounter, builder)
 
 			collection = l.appendHistogramValue(collection, metricsPrefix, "write_rtt", traffic, p, metrics.WriteRTTHistogram, builder)
 			collection = l.appendHistogramValue(collection, metricsPrefix, "write_exe_time", traffic, p, metrics.WriteExeTimeHistogram, builder)
 			collection = l.appendHistogramValue(collection, metricsPrefix, "read_exe_time", traffic, p, metrics.ReadExeTimeHistogram, builder)
+			collection = l.appendHistogramValue(collection, metricsPrefix, "write_throughput", traffic, p, metrics.WriteThroughputHistogram, builder)
+			collection = l.appendHistogramValue(collection, metricsPrefix, "read_throughput", traffic, p, metrics.ReadThroughputHistogram, builder)
 			collection = l.appendHistogramValue(collection, metricsPrefix, "connect_exe_time", traffic, p, metrics.ConnectExeTimeHistogram, builder)
 			collection = l.appendHistogramValue(collection, metricsPrefix, "close_exe_time", traffic, p, metrics.CloseExeTimeHistogram, builder)
 
@@ -281,6 +344,14 @@ func (l *Listener) mergeExceptionToAppointConnection(expCtx *SocketExceptionValu
 	layer4 := l.getMetrics(conCtx.Metrics)
 	layer4.DropCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, uint64(expCtx.DropCount), 0))
 	layer4.RetransmitCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, uint64(expCtx.RetransmitCount), 0))
+	layer4.OutOfOrderCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, uint64(expCtx.OutOfOrderCount), 0))
+	for reason, count := range expCtx.DropReasons {
+		layer4.DropReasonCounter(l.dropReasonName(reason)).IncreaseToCurrent(NewSocketDataCounterWithValue(0, uint64(count), 0))
+	}
+	if expCtx.QualitySampleCount > 0 {
+		layer4.ExceptionSRTTCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, uint64(expCtx.QualitySampleCount), expCtx.TotalSRTTUs))
+		layer4.ExceptionCwndCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, uint64(expCtx.QualitySampleCount), expCtx.TotalSndCwnd))
+	}
 }
 
 func (l *Listener) appendCounterValues(metrics []*v3.MeterData, metricsPrefix, name string, traffic *base.ProcessTraffic,
@@ -301,6 +372,39 @@ func (l *Listener) appendCounterValues(metrics []*v3.MeterData, metricsPrefix, n
 	return metrics
 }
 
+func (l *Listener) dropReasonName(reason uint32) string {
+	if name, exist := l.dropReasons[uint64(reason)]; exist {
+		return strings.TrimPrefix(name, "SKB_DROP_REASON_")
+	}
+	return fmt.Sprintf("%d", reason)
+}
+
+// appendDropReasonValues append the drop counter with the "reason" label
+func (l *Listener) appendDropReasonValues(metrics []*v3.MeterData, metricsPrefix string, traffic *base.ProcessTraffic,
+	local api.ProcessInterface, layer4 *Metrics, builder *base.MetricsBuilder) []*v3.MeterData {
+	for reason, counter := range layer4.DropReasonCounters {
+		if !counter.Cur.NotEmpty() {
+			continue
+		}
+		meter := l.buildSingleValue(metricsPrefix, "drop_reason_counts_counter", traffic, local, float64(counter.Cur.Count), builder)
+		single := meter.GetSingleValue()
+		single.Labels = append(single.Labels, &v3.Label{Name: "reason", Value: reason})
+		metrics = append(metrics, meter)
+	}
+	return metrics
+}
+
+// appendAvgValue append the average value of the sampling counter, the execute time is the total value of sampling
+func (l *Listener) appendAvgValue(metrics []*v3.MeterData, metricsPrefix, name string, traffic *base.ProcessTraffic,
+	local api.ProcessInterface, counter *SocketDataCounterWithHistory, builder *base.MetricsBuilder) []*v3.MeterData {
+	metric := counter.Cur
+	if !metric.NotEmpty() {
+		return metrics
+	}
+	return append(metrics, l.buildSingleValue(metricsPrefix, name+"_avg", traffic, local,
+		float64(metric.ExeTime)/float64(metric.Count), builder))
+}
+
 func (l *Listener) appendHistogramValue(metrics []*v3.MeterData, metricsPrefix, name string, traffic *base.ProcessTraffic,
 	local api.ProcessInterface, histogram *SocketDataHistogramWithHistory, metricsBuilder *base.MetricsBuilder) []*v3.MeterData {
 	data := histogram.Cur
@@ -309,17 +413,12 @@ func (l *Listener) appendHistogramValue(metrics []*v3.MeterData, metricsPrefix,
 	}
 
 	role, labels := metricsBuilder.BuildBasicMeterLabels(traffic, local)
+	buckets := data.Unit.Buckets()
 	values := make([]*v3.MeterBucketValue, 0)
 	for bucket, count := range data.Buckets {
 		var bucketInx = int(bucket)
-		if bucketInx >= SocketHistogramBucketsCount {
-			bucketInx = SocketHistogramBucketsCount - 1
-		}
-		var buckets []float64
-		if data.Unit == HistogramDataUnitUS {
-			buckets = SocketHistogramBucketsUs
-		} else {
-			buckets = SocketHistogramBucketsNs
+		if bucketInx >= len(buckets) {
+			bucketInx = len(buckets) - 1
 		}
 		values = append(values, &v3.MeterBucketValue{
 			Bucket: buckets[bucketInx],
@@ -0,0 +1,175 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package layer4
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/base"
+)
+
+func TestDropReasonName(t *testing.T) {
+	reasons := map[uint64]string{
+		2: "SKB_DROP_REASON_NOT_SPECIFIED",
+		3: "NO_SOCKET",
+	}
+	tests := []struct {
+		name     string
+		reasons  map[uint64]string
+		reason   uint32
+		expected string
+	}{
+		{
+			name:     "name with the prefix",
+			reasons:  reasons,
+			reason:   2,
+			expected: "NOT_SPECIFIED",
+		},
+		{
+			name:     "name without the prefix",
+			reasons:  reasons,
+			reason:   3,
+			expected: "NO_SOCKET",
+		},
+		{
+			name:     "unknown reason",
+			reasons:  reasons,
+			reason:   100,
+			expected: "100",
+		},
+		{
+			name:     "kernel reasons not loaded",
+			reason:   2,
+			expected: "2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := NewListener()
+			listener.dropReasons = tt.reasons
+			if actual := listener.dropReasonName(tt.reason); actual != tt.expected {
+				t.Errorf("expected reason name: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMergeDropReasons(t *testing.T) {
+	tests := []struct {
+		name       string
+		exceptions []*SocketExceptionValue
+		expected   map[string]uint64
+	}{
+		{
+			name: "single exception",
+			exceptions: []*SocketExceptionValue{
+				{DropCount: 3, DropReasons: map[uint32]int{2: 2, 100: 1}},
+			},
+			expected: map[string]uint64{"NOT_SPECIFIED": 2, "100": 1},
+		},
+		{
+			name: "multiple exceptions with the same reason",
+			exceptions: []*SocketExceptionValue{
+				{DropCount: 1, DropReasons: map[uint32]int{2: 1}},
+				{DropCount: 2, DropReasons: map[uint32]int{2: 2}},
+			},
+			expected: map[string]uint64{"NOT_SPECIFIED": 3},
+		},
+		{
+			name: "drop without reasons",
+			exceptions: []*SocketExceptionValue{
+				{DropCount: 1},
+			},
+			expected: map[string]uint64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := NewListener()
+			listener.dropReasons = map[uint64]string{2: "SKB_DROP_REASON_NOT_SPECIFIED"}
+			analyzeContext := base.NewAnalyzerContext(nil)
+			analyzeContext.AddListener(listener)
+			connection := &base.ConnectionContext{Metrics: analyzeContext.NewConnectionMetrics()}
+			for _, exception := range tt.exceptions {
+				listener.mergeExceptionToAppointConnection(exception, connection)
+			}
+
+			// merge to the process level metrics, the reasons should be kept
+			merged := NewLayer4Metrics()
+			merged.MergeMetricsFromConnection(connection, listener.getMetrics(connection.Metrics))
+			if len(merged.DropReasonCounters) != len(tt.expected) {
+				t.Fatalf("expected reason count: %d, actual: %d", len(tt.expected), len(merged.DropReasonCounters))
+			}
+			for reason, count := range tt.expected {
+				counter := merged.DropReasonCounters[reason]
+				if counter == nil {
+					t.Fatalf("expected reason exist: %s", reason)
+				}
+				if counter.Cur.Count != count {
+					t.Errorf("expected drop count of %s: %d, actual: %d", reason, count, counter.Cur.Count)
+				}
+			}
+		})
+	}
+}
+
+func TestSampleTCPQuality(t *testing.T) {
+	tests := []struct {
+		name          string
+		samples       [][2]uint32
+		expectedCount uint64
+		expectedSRTT  uint64
+		expectedCwnd  uint64
+	}{
+		{
+			name:          "multiple connections",
+			samples:       [][2]uint32{{100, 10}, {300, 30}},
+			expectedCount: 2,
+			expectedSRTT:  400,
+			expectedCwnd:  40,
+		},
+		{
+			name:    "non TCP connection",
+			samples: [][2]uint32{{0, 0}},
+		},
+		{
+			name:          "only congestion window",
+			samples:       [][2]uint32{{0, 10}, {0, 0}},
+			expectedCount: 1,
+			expectedCwnd:  10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := NewListener()
+			metrics := NewLayer4Metrics()
+			for _, sample := range tt.samples {
+				listener.sampleTCPQuality(metrics, sample[0], sample[1])
+			}
+			if srtt := metrics.SRTTCounter.CalculateIncrease(); srtt.Count != tt.expectedCount || srtt.ExeTime != tt.expectedSRTT {
+				t.Errorf("expected srtt count: %d, total: %d, actual count: %d, total: %d",
+					tt.expectedCount, tt.expectedSRTT, srtt.Count, srtt.ExeTime)
+			}
+			cwnd := metrics.CongestionWindowCounter.CalculateIncrease()
+			if cwnd.Count != tt.expectedCount || cwnd.ExeTime != tt.expectedCwnd {
+				t.Errorf("expected congestion window count: %d, total: %d, actual count: %d, total: %d",
+					tt.expectedCount, tt.expectedCwnd, cwnd.Count, cwnd.ExeTime)
+			}
+		})
+	}
+}
@@ -33,6 +33,9 @@ type Metrics struct {
 	WriteExeTimeHistogram *SocketDataHistogramWithHistory
 	// read execute time
 	ReadExeTimeHistogram *SocketDataHistogramWithHistory
+	// write/read throughput of each report interval
+	WriteThroughputHistogram *SocketDataHistogramWithHistory
+	ReadThroughputHistogram  *SocketDataHistogramWithHistory
 
 	// the connection connect or close execute time
 	ConnectExecuteTime      uint64
@@ -45,22 +48,41 @@ type Metrics struct {
 	// exception counters
 	RetransmitCounter *SocketDataCounterWithHistory
 	DropCounter       *SocketDataCounterWithHistory
+	OutOfOrderCounter *SocketDataCounterWithHistory
+	// the drop counter of each drop reason
+	DropReasonCounters map[string]*SocketDataCounterWithHistory
+
+	// TCP quality of every connection, sampled the latest value of each connection when flushing or closing,
+	// the count is sampling count, the execute time is the total value of sampling
+	SRTTCounter             *SocketDataCounterWithHistory
+	CongestionWindowCounter *SocketDataCounterWithHistory
+	// TCP quality sampled only when the exception operation happens(not periodically)
+	ExceptionSRTTCounter *SocketDataCounterWithHistory
+	ExceptionCwndCounter *SocketDataCounterWithHistory
 }
 
 func NewLayer4Metrics() *Metrics {
 	return &Metrics{
-		WriteCounter:            NewSocketDataCounterWithHistory(),
-		ReadCounter:             NewSocketDataCounterWithHistory(),
-		WriteRTTCounter:         NewSocketDataCounterWithHistory(),
-		WriteRTTHistogram:       NewSocketDataHistogramWithHistory(HistogramDataUnitUS),
-		WriteExeTimeHistogram:   NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
-		ReadExeTimeHistogram:    NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
-		ConnectCounter:          NewSocketDataCounterWithHistory(),
-		ConnectExeTimeHistogram: NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
-		CloseCounter:            NewSocketDataCounterWithHistory(),
-		CloseExeTimeHistogram:   NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
-		RetransmitCounter:       NewSocketDataCounterWithHistory(),
-		DropCounter:             NewSocketDataCounterWithHistory(),
+		WriteCounter:             NewSocketDataCounterWithHistory(),
+		ReadCounter:              NewSocketDataCounterWithHistory(),
+		WriteRTTCounter:          NewSocketDataCounterWithHistory(),
+		WriteRTTHistogram:        NewSocketDataHistogramWithHistory(HistogramDataUnitUS),
+		WriteExeTimeHistogram:    NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
+		ReadExeTimeHistogram:     NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
+		WriteThroughputHistogram: NewSocketDataHistogramWithHistory(HistogramDataUnitBytesPerSecond),
+		ReadThroughputHistogram:  NewSocketDataHistogramWithHistory(HistogramDataUnitBytesPerSecond),
+		ConnectCounter:           NewSocketDataCounterWithHistory(),
+		ConnectExeTimeHistogram:  NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
+		CloseCounter:             NewSocketDataCounterWithHistory(),
+		CloseExeTimeHistogram:    NewSocketDataHistogramWithHistory(HistogramDataUnitNS),
+		RetransmitCounter:        NewSocketDataCounterWithHistory(),
+		DropCounter:              NewSocketDataCounterWithHistory(),
+		OutOfOrderCounter:        NewSocketDataCounterWithHistory(),
+		DropReasonCounters:       make(map[string]*SocketDataCounterWithHistory),
+		SRTTCounter:              NewSocketDataCounterWithHistory(),
+		CongestionWindowCounter:  NewSocketDataCounterWithHistory(),
+		ExceptionSRTTCounter:     NewSocketDataCounterWithHistory(),
+		ExceptionCwndCounter:     NewSocketDataCounterWithHistory(),
 	}
 }
 
@@ -74,9 +96,19 @@ func (l *Metrics) MergeMetricsFromConnection(connection *base.ConnectionContext,
 	l.WriteRTTHistogram.IncreaseToCurrent(metrics.WriteRTTHistogram.CalculateIncrease())
 	l.WriteExeTimeHistogram.IncreaseToCurrent(metrics.WriteExeTimeHistogram.CalculateIncrease())
 	l.ReadExeTimeHistogram.IncreaseToCurrent(metrics.ReadExeTimeHistogram.CalculateIncrease())
+	l.WriteThroughputHistogram.IncreaseToCurrent(metrics.WriteThroughputHistogram.CalculateIncrease())
+	l.ReadThroughputHistogram.IncreaseToCurrent(metrics.ReadThroughputHistogram.CalculateIncrease())
 
 	l.RetransmitCounter.IncreaseToCurrent(metrics.RetransmitCounter.CalculateIncrease())
 	l.DropCounter.IncreaseToCurrent(metrics.DropCounter.CalculateIncrease())
+	l.OutOfOrderCounter.IncreaseToCurrent(metrics.OutOfOrderCounter.CalculateIncrease())
+	for reason, counter := range metrics.DropReasonCounters {
+		l.DropReasonCounter(reason).IncreaseToCurrent(counter.CalculateIncrease())
+	}
+	l.SRTTCounter.IncreaseToCurrent(metrics.SRTTCounter.CalculateIncrease())
+	l.CongestionWindowCounter.IncreaseToCurrent(metrics.CongestionWindowCounter.CalculateIncrease())
+	l.ExceptionSRTTCounter.IncreaseToCurrent(metrics.ExceptionSRTTCounter.CalculateIncrease())
+	l.ExceptionCwndCounter.IncreaseToCurrent(metrics.ExceptionCwndCounter.CalculateIncrease())
 
 	if connection.FlushDataCount == 0 && metrics.ConnectExecuteTime > 0 {
 		l.ConnectCounter.IncreaseToCurrent(NewSocketDataCounterWithValue(0, 1, metrics.ConnectExecuteTime))
@@ -88,6 +120,16 @@ func (l *Metrics) MergeMetricsFromConnection(connection *base.ConnectionContext,
 	}
 }
 
+// DropReasonCounter get or create the drop counter of the reason
+func (l *Metrics) DropReasonCounter(reason string) *SocketDataCounterWithHistory {
+	counter := l.DropReasonCounters[reason]
+	if counter == nil {
+		counter = NewSocketDataCounterWithHistory()
+		l.DropReasonCounters[reason] = counter
+	}
+	return counter
+}
+
 type SocketDataCounter struct {
 	Bytes   uint64
 	Count   uint64
@@ -173,6 +215,12 @@ var SocketHistogramBucketsUs = []float64{0, 10, 50, 100, 500, 1000, 1200, 1500,
 	3000000, 5000000}
 var SocketHistogramBucketsCount = len(SocketHistogramBucketsNs)
 
+// SocketHistogramBucketsBytesPerSecond means the throughput histogram bucket: 0, 1KB/s, 10KB/s, 100KB/s, 500KB/s, 1MB/s,
+// 5MB/s, 10MB/s, 50MB/s, 100MB/s, 500MB/s, 1GB/s
+// value unit: bytes/s
+var SocketHistogramBucketsBytesPerSecond = []float64{0, 1 << 10, 10 << 10, 100 << 10, 500 << 10, 1 << 20,
+	5 << 20, 10 << 20, 50 << 20, 100 << 20, 500 << 20, 1 << 30}
+
 type SocketDataHistogram struct {
 	Unit    HistogramDataUnit
 	Buckets map[uint64]uint32
@@ -196,13 +244,14 @@ func (h *SocketDataHistogram) Increase(other *SocketDataHistogram) {
 
 func (h *SocketDataHistogram) IncreaseByValue(val uint64) {
 	floatVal := float64(val)
-	for inx, curVal := range SocketHistogramBucketsNs {
+	buckets := h.Unit.Buckets()
+	for inx, curVal := range buckets {
 		if inx > 0 && curVal > floatVal {
 			h.Buckets[uint64(inx-1)]++
 			return
 		}
 	}
-	h.Buckets[uint64(len(SocketHistogramBucketsNs)-1)]++
+	h.Buckets[uint64(len(buckets)-1)]++
 }
 
 func (h *SocketDataHistogram) NotEmpty() bool {
@@ -215,8 +264,9 @@ func (h *SocketDataHistogram) NotEmpty() bool {
 }
 
 func NewSocketDataHistogram(unit HistogramDataUnit) *SocketDataHistogram {
-	buckets := make(map[uint64]uint32, SocketHistogramBucketsCount)
-	for i := 0; i < SocketHistogramBucketsCount; i++ {
+	count := len(unit.Buckets())
+	buckets := make(map[uint64]uint32, count)
+	for i := 0; i < count; i++ {
 		buckets[uint64(i)] = 0
 	}
 	return &SocketDataHistogram{
@@ -228,10 +278,23 @@ func NewSocketDataHistogram(unit HistogramDataUnit) *SocketDataHistogram {
 type HistogramDataUnit int
 
 const (
-	HistogramDataUnitNS HistogramDataUnit = 1
-	HistogramDataUnitUS HistogramDataUnit = 2
+	HistogramDataUnitNS             HistogramDataUnit = 1
+	HistogramDataUnitUS             HistogramDataUnit = 2
+	HistogramDataUnitBytesPerSecond HistogramDataUnit = 3
 )
 
+// Buckets of the histogram unit
+func (u HistogramDataUnit) Buckets() []float64 {
+	switch u {
+	case HistogramDataUnitUS:
+		return SocketHistogramBucketsUs
+	case HistogramDataUnitBytesPerSecond:
+		return SocketHistogramBucketsBytesPerSecond
+	default:
+		return SocketHistogramBucketsNs
+	}
+}
+
 type SocketDataHistogramWithHistory struct {
 	Pre *SocketDataHistogram
 	Cur *SocketDataHistogram
@@ -0,0 +1,120 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package layer4
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramIncreaseByValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     HistogramDataUnit
+		value    uint64
+		expected uint64
+	}{
+		{name: "ns zero", unit: HistogramDataUnitNS, value: 0, expected: 0},
+		{name: "ns 1.6ms", unit: HistogramDataUnitNS, value: 1600000, expected: 7},
+		{name: "us 1.6ms", unit: HistogramDataUnitUS, value: 1600, expected: 7},
+		{name: "us exceed the last bucket", unit: HistogramDataUnitUS, value: 10000000, expected: uint64(len(SocketHistogramBucketsUs) - 1)},
+		{name: "throughput 1KB/s", unit: HistogramDataUnitBytesPerSecond, value: 1 << 10, expected: 1},
+		{name: "throughput 200KB/s", unit: HistogramDataUnitBytesPerSecond, value: 200 << 10, expected: 3},
+		{name: "throughput exceed 1GB/s", unit: HistogramDataUnitBytesPerSecond, value: 2 << 30,
+			expected: uint64(len(SocketHistogramBucketsBytesPerSecond) - 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			histogram := NewSocketDataHistogram(tt.unit)
+			if len(histogram.Buckets) != len(tt.unit.Buckets()) {
+				t.Fatalf("expected bucket count: %d, actual: %d", len(tt.unit.Buckets()), len(histogram.Buckets))
+			}
+			histogram.IncreaseByValue(tt.value)
+			for bucket, count := range histogram.Buckets {
+				expected := uint32(0)
+				if bucket == tt.expected {
+					expected = 1
+				}
+				if count != expected {
+					t.Errorf("expected count of bucket %d: %d, actual: %d", bucket, expected, count)
+				}
+			}
+		})
+	}
+}
+
+func TestIncreaseThroughput(t *testing.T) {
+	tests := []struct {
+		name           string
+		reportInterval time.Duration
+		preWriteBytes  uint64
+		curWriteBytes  uint64
+		preReadBytes   uint64
+		curReadBytes   uint64
+		expectedWrite  map[uint64]uint32
+		expectedRead   map[uint64]uint32
+	}{
+		{
+			name:           "write and read",
+			reportInterval: 10 * time.Second,
+			preWriteBytes:  1000,
+			curWriteBytes:  1000 + 200<<10*10,
+			curReadBytes:   2 << 20 * 10,
+			expectedWrite:  map[uint64]uint32{3: 1},
+			expectedRead:   map[uint64]uint32{5: 1},
+		},
+		{
+			name:           "no increase bytes",
+			reportInterval: 10 * time.Second,
+			preWriteBytes:  1000,
+			curWriteBytes:  1000,
+			expectedWrite:  map[uint64]uint32{},
+			expectedRead:   map[uint64]uint32{},
+		},
+		{
+			name:          "report interval not set",
+			curWriteBytes: 1 << 20,
+			curReadBytes:  1 << 20,
+			expectedWrite: map[uint64]uint32{},
+			expectedRead:  map[uint64]uint32{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := NewListener()
+			listener.reportInterval = tt.reportInterval
+			metrics := NewLayer4Metrics()
+			metrics.WriteCounter.UpdateToCurrent(tt.preWriteBytes, 1, 0)
+			metrics.WriteCounter.UpdateToCurrent(tt.curWriteBytes, 2, 0)
+			metrics.ReadCounter.UpdateToCurrent(tt.preReadBytes, 1, 0)
+			metrics.ReadCounter.UpdateToCurrent(tt.curReadBytes, 2, 0)
+
+			listener.increaseThroughput(metrics)
+			assertHistogramBuckets(t, "write", tt.expectedWrite, metrics.WriteThroughputHistogram.Cur)
+			assertHistogramBuckets(t, "read", tt.expectedRead, metrics.ReadThroughputHistogram.Cur)
+		})
+	}
+}
+
+func assertHistogramBuckets(t *testing.T, name string, expected map[uint64]uint32, histogram *SocketDataHistogram) {
+	for bucket, count := range histogram.Buckets {
+		if count != expected[bucket] {
+			t.Errorf("expected %s count of bucket %d: %d, actual: %d", name, bucket, expected[bucket], count)
+		}
+	}
+}
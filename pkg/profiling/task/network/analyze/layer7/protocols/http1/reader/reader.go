@@ -222,10 +222,24 @@ func (m *MessageOpt) readBodyUntilCurrentPackageFinished(buf *buffer.Buffer, rea
 			return nil, enums.ParseResultSkipPackage, err
 		}
 	}
+	// the buffered data is included in the body, so the reading position stays at the end of the package
+	_, _ = reader.Discard(reader.Buffered())
 	endPosition := buf.Position()
 	return buf.Slice(true, startPosition, endPosition), enums.ParseResultSuccess, nil
 }
 
+// emptyBody is the body of the message which has no body, such as the request without the content length
+func (m *MessageOpt) emptyBody(buf *buffer.Buffer, reader *bufio.Reader) (*buffer.Buffer, enums.ParseResult, error) {
+	position := buf.OffsetPosition(-reader.Buffered())
+	return buf.Slice(true, position, position), enums.ParseResultSuccess, nil
+}
+
+// finishReading move the reading position of the buffer to the end of the message,
+// because the reader may read ahead the data of the next message, such as the pipelined requests
+func finishReading(buf *buffer.Buffer, reader *bufio.Reader) {
+	buf.ResetCurrentPosition(buf.OffsetPosition(-reader.Buffered()))
+}
+
 func (m *MessageOpt) checkChunkedBody(buf *buffer.Buffer, bodyReader *bufio.Reader) (*buffer.Buffer, enums.ParseResult, error) {
 	buffers := make([]*buffer.Buffer, 0)
 	for {
@@ -239,6 +253,13 @@ func (m *MessageOpt) checkChunkedBody(buf *buffer.Buffer, bodyReader *bufio.Read
 			return nil, enums.ParseResultSkipPackage, fmt.Errorf("read chunked size error: %s", needBytesStr)
 		}
 		if needBytes == 0 {
+			// skip the trailers until the empty line, the last line may not be received yet
+			for {
+				trailer, _, e := bodyReader.ReadLine()
+				if e != nil || len(trailer) == 0 {
+					break
+				}
+			}
 			break
 		}
 		b, r, err1 := m.checkBodyWithSize(buf, bodyReader, int(needBytes), false)
@@ -137,6 +137,7 @@ func (r *Reader) ReadRequest(buf *buffer.Buffer, readBody bool) (*Request, enums
 			result.bodyBuffer = b
 		}
 	}
+	finishReading(buf, bufReader)
 
 	return result, enums.ParseResultSuccess, nil
 }
@@ -158,5 +159,6 @@ func (r *Request) readFullBody(bodyReader *bufio.Reader, original *buffer.Buffer
 		return r.checkChunkedBody(original, bodyReader)
 	}
 
-	return r.readBodyUntilCurrentPackageFinished(original, bodyReader)
+	// the request without the content length and chunked encoding has no body(RFC 7230 3.3.3)
+	return r.emptyBody(original, bodyReader)
 }
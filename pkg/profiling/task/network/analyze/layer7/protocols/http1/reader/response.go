@@ -112,6 +112,7 @@ func (r *Reader) ReadResponse(req *Request, buf *buffer.Buffer, readBody bool) (
 		}
 		result.bodyBuffer = b
 	}
+	finishReading(buf, bufReader)
 
 	return result, enums.ParseResultSuccess, nil
 }
@@ -127,6 +128,8 @@ func (r *Response) readFullResponseBody(bodyReader *bufio.Reader, original *buff
 		return nil, enums.ParseResultSkipPackage, err
 	} else if length > 0 {
 		return r.checkBodyWithSize(original, bodyReader, length, true)
+	} else if length == 0 || r.noBody() {
+		return r.emptyBody(original, bodyReader)
 	}
 
 	if r.isChunked() {
@@ -135,3 +138,12 @@ func (r *Response) readFullResponseBody(bodyReader *bufio.Reader, original *buff
 
 	return r.readBodyUntilCurrentPackageFinished(original, bodyReader)
 }
+
+// noBody means the response has no body no matter the headers(RFC 7230 3.3.3)
+func (r *Response) noBody() bool {
+	status := r.original.StatusCode
+	if (status >= 100 && status < 200) || status == http.StatusNoContent || status == http.StatusNotModified {
+		return true
+	}
+	return r.req != nil && r.req.original != nil && r.req.original.Method == http.MethodHead
+}
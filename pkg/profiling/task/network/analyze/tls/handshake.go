@@ -0,0 +1,125 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+)
+
+const handshakeDataMaxSize = 256
+
+// the reason of the handshake which not finished before the connection closed
+const handshakeIncomplete = "incomplete"
+
+// HandshakeEvent is the TLS record detected in the raw socket data during the handshake
+type HandshakeEvent struct {
+	ConnectionID uint64
+	RandomID     uint64
+	Timestamp    uint64
+	DataLen      uint32
+	Direction    enums.SocketDataDirection
+	RecordType   uint8
+	Finished     uint8
+	Reserved     uint8
+	Data         [handshakeDataMaxSize]byte
+}
+
+// HandshakeResult is the finished or failed handshake of a connection
+type HandshakeResult struct {
+	Version  string
+	Duration time.Duration
+	// the reason of the handshake failure, empty means success
+	Error string
+}
+
+// handshakeTracker tracks the handshake from the client hello to the first encrypted record from the client
+type handshakeTracker struct {
+	startTime       uint64
+	clientDirection enums.SocketDataDirection
+	version         string
+	createTime      time.Time
+}
+
+func newHandshakeTracker(event *HandshakeEvent, now time.Time) *handshakeTracker {
+	return &handshakeTracker{startTime: event.Timestamp, clientDirection: event.Direction, createTime: now}
+}
+
+// Receive the record of the handshake, return the result when the handshake is finished or failed
+func (h *handshakeTracker) Receive(event *HandshakeEvent) *HandshakeResult {
+	data := event.Data[:min(int(event.DataLen), handshakeDataMaxSize)]
+	switch event.RecordType {
+	case ssl.TLSRecordHandshake:
+		if event.Direction != h.clientDirection && len(data) > 5 {
+			if hello, err := ssl.ParseTLSHello(data[5:]); err == nil && hello.Type == ssl.TLSHandshakeServerHello {
+				h.version = ssl.TLSVersionName(hello.Version)
+			}
+		}
+	case ssl.TLSRecordAlert:
+		// alert record: level(1) + description(1), only the fatal alert would break the handshake
+		if len(data) >= 7 && data[5] == 2 {
+			return &HandshakeResult{Version: h.version, Error: alertName(data[6])}
+		}
+	case ssl.TLSRecordApplicationData:
+		if event.Direction == h.clientDirection {
+			return &HandshakeResult{Version: h.version, Duration: time.Duration(event.Timestamp - h.startTime)}
+		}
+	}
+	return nil
+}
+
+// Close the tracker when the connection closed before the handshake finished
+func (h *handshakeTracker) Close() *HandshakeResult {
+	return &HandshakeResult{Version: h.version, Error: handshakeIncomplete}
+}
+
+var alertNames = map[uint8]string{
+	0:   "close_notify",
+	10:  "unexpected_message",
+	20:  "bad_record_mac",
+	40:  "handshake_failure",
+	42:  "bad_certificate",
+	43:  "unsupported_certificate",
+	44:  "certificate_revoked",
+	45:  "certificate_expired",
+	46:  "certificate_unknown",
+	47:  "illegal_parameter",
+	48:  "unknown_ca",
+	49:  "access_denied",
+	50:  "decode_error",
+	51:  "decrypt_error",
+	70:  "protocol_version",
+	71:  "insufficient_security",
+	80:  "internal_error",
+	86:  "inappropriate_fallback",
+	109: "missing_extension",
+	110: "unsupported_extension",
+	112: "unrecognized_name",
+	116: "certificate_required",
+	120: "no_application_protocol",
+}
+
+func alertName(description uint8) string {
+	if name, exist := alertNames[description]; exist {
+		return name
+	}
+	return fmt.Sprintf("alert_%d", description)
+}
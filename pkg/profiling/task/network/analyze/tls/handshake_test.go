@@ -0,0 +1,106 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+	"github.com/apache/skywalking-rover/pkg/tools/ssl"
+)
+
+func buildServerHello(legacyVersion uint16, supportedVersion uint16) []byte {
+	body := []byte{byte(legacyVersion >> 8), byte(legacyVersion)}
+	body = append(body, make([]byte, 32)...)
+	// session id(empty), cipher suite, compression
+	body = append(body, 0, 0x13, 0x01, 0)
+	if supportedVersion != 0 {
+		body = append(body, 0, 6, 0, 43, 0, 2, byte(supportedVersion>>8), byte(supportedVersion))
+	}
+	handshake := append([]byte{ssl.TLSHandshakeServerHello, 0, 0, byte(len(body))}, body...)
+	return append([]byte{ssl.TLSRecordHandshake, 3, 3, 0, byte(len(handshake))}, handshake...)
+}
+
+func buildEvent(direction enums.SocketDataDirection, recordType uint8, timestamp uint64, data []byte) *HandshakeEvent {
+	event := &HandshakeEvent{Direction: direction, RecordType: recordType, Timestamp: timestamp, DataLen: uint32(len(data))}
+	copy(event.Data[:], data)
+	return event
+}
+
+func TestHandshakeTracker(t *testing.T) {
+	egress, ingress := enums.SocketDataDirectionEgress, enums.SocketDataDirectionIngress
+	clientHello := []byte{ssl.TLSRecordHandshake, 3, 1, 0, 100, ssl.TLSHandshakeClientHello}
+	tests := []struct {
+		name   string
+		events []*HandshakeEvent
+		result *HandshakeResult
+	}{
+		{
+			name: "tls 1.3 finished",
+			events: []*HandshakeEvent{
+				buildEvent(egress, ssl.TLSRecordHandshake, 1000, clientHello),
+				buildEvent(ingress, ssl.TLSRecordHandshake, 2000, buildServerHello(0x0303, 0x0304)),
+				buildEvent(ingress, ssl.TLSRecordApplicationData, 2500, nil),
+				buildEvent(egress, ssl.TLSRecordApplicationData, uint64(time.Millisecond)+1000, nil),
+			},
+			result: &HandshakeResult{Version: "TLSv1.3", Duration: time.Millisecond},
+		},
+		{
+			name: "tls 1.2 server side",
+			events: []*HandshakeEvent{
+				buildEvent(ingress, ssl.TLSRecordHandshake, 1000, clientHello),
+				buildEvent(egress, ssl.TLSRecordHandshake, 2000, buildServerHello(0x0303, 0)),
+				buildEvent(egress, ssl.TLSRecordApplicationData, 2500, nil),
+				buildEvent(ingress, ssl.TLSRecordApplicationData, 3000, nil),
+			},
+			result: &HandshakeResult{Version: "TLSv1.2", Duration: 2000},
+		},
+		{
+			name: "fatal alert",
+			events: []*HandshakeEvent{
+				buildEvent(egress, ssl.TLSRecordHandshake, 1000, clientHello),
+				buildEvent(ingress, ssl.TLSRecordAlert, 2000, []byte{ssl.TLSRecordAlert, 3, 3, 0, 2, 2, 40}),
+			},
+			result: &HandshakeResult{Error: "handshake_failure"},
+		},
+		{
+			name: "closed before finished",
+			events: []*HandshakeEvent{
+				buildEvent(egress, ssl.TLSRecordHandshake, 1000, clientHello),
+				buildEvent(ingress, ssl.TLSRecordHandshake, 2000, buildServerHello(0x0303, 0x0304)),
+			},
+			result: &HandshakeResult{Version: "TLSv1.3", Error: handshakeIncomplete},
+		},
+	}
+	for _, tt := range tests {
+		tracker := newHandshakeTracker(tt.events[0], time.Now())
+		var result *HandshakeResult
+		for _, e := range tt.events[1:] {
+			if result = tracker.Receive(e); result != nil {
+				break
+			}
+		}
+		if result == nil {
+			result = tracker.Close()
+		}
+		if *result != *tt.result {
+			t.Fatalf("%s: expected %+v, actual %+v", tt.name, tt.result, result)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	profiling "github.com/apache/skywalking-rover/pkg/profiling/task/base"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/base"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/events"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/bpf"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+var log = logger.GetLogger("profiling", "task", "network", "tls")
+
+var Name = "tls"
+
+// the max duration to track the handshake, the handshake may not be detected completely(such as the data been merged)
+var handshakeTrackTimeout = time.Minute
+
+type connectionKey struct {
+	ConnectionID uint64
+	RandomID     uint64
+}
+
+// Listener aggregates the TLS handshakes by the destination kubernetes service
+type Listener struct {
+	k8sOperator process.K8sOperator
+
+	mutex    sync.Mutex
+	trackers map[connectionKey]*handshakeTracker
+	results  map[connectionKey][]*HandshakeResult
+}
+
+func NewListener() *Listener {
+	return &Listener{
+		trackers: make(map[connectionKey]*handshakeTracker),
+		results:  make(map[connectionKey][]*HandshakeResult),
+	}
+}
+
+func (l *Listener) Name() string {
+	return Name
+}
+
+func (l *Listener) Init(_ *profiling.TaskConfig, moduleManager *module.Manager) error {
+	l.k8sOperator = moduleManager.FindModule(process.ModuleName).(process.K8sOperator)
+	return nil
+}
+
+func (l *Listener) GenerateMetrics() base.ConnectionMetrics {
+	return NewMetrics()
+}
+
+func (l *Listener) RegisterBPFEvents(_ context.Context, bpfLoader *bpf.Loader) {
+	bpfLoader.ReadEventAsync(bpfLoader.TlsHandshakeEventQueue, l.handleHandshakeEvent, func() interface{} {
+		return &HandshakeEvent{}
+	})
+}
+
+func (l *Listener) handleHandshakeEvent(data interface{}) {
+	event := data.(*HandshakeEvent)
+	key := connectionKey{ConnectionID: event.ConnectionID, RandomID: event.RandomID}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	tracker := l.trackers[key]
+	if tracker == nil {
+		// the first event of the connection is always the client hello
+		l.trackers[key] = newHandshakeTracker(event, time.Now())
+		return
+	}
+	if result := tracker.Receive(event); result != nil {
+		log.Debugf("detected TLS handshake of connection %d_%d, version: %s, duration: %s, error: %s",
+			event.ConnectionID, event.RandomID, result.Version, result.Duration, result.Error)
+		delete(l.trackers, key)
+		l.results[key] = append(l.results[key], result)
+	}
+}
+
+func (l *Listener) ReceiveNewConnection(*base.ConnectionContext, *events.SocketConnectEvent) {
+}
+
+func (l *Listener) ReceiveCloseConnection(ctx *base.ConnectionContext, _ *events.SocketCloseEvent) {
+	key := connectionKey{ConnectionID: ctx.ConnectionID, RandomID: ctx.RandomID}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if tracker := l.trackers[key]; tracker != nil {
+		delete(l.trackers, key)
+		l.results[key] = append(l.results[key], tracker.Close())
+	}
+	l.moveResultsToConnection(key, ctx)
+}
+
+func (l *Listener) UpdateExtensionConfig(*profiling.ExtensionConfig) {
+}
+
+func (l *Listener) PreFlushConnectionMetrics(ccs []*base.ConnectionWithBPF, _ *bpf.Loader) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, cc := range ccs {
+		key := connectionKey{ConnectionID: cc.Connection.ConnectionID, RandomID: cc.Connection.RandomID}
+		l.moveResultsToConnection(key, cc.Connection)
+	}
+	// the results which connection is not monitored are dropped
+	l.results = make(map[connectionKey][]*HandshakeResult)
+	now := time.Now()
+	for key, tracker := range l.trackers {
+		if now.Sub(tracker.createTime) > handshakeTrackTimeout {
+			delete(l.trackers, key)
+		}
+	}
+	return nil
+}
+
+func (l *Listener) moveResultsToConnection(key connectionKey, ctx *base.ConnectionContext) {
+	results := l.results[key]
+	if len(results) == 0 {
+		return
+	}
+	delete(l.results, key)
+	metrics := ctx.Metrics.GetMetrics(Name).(*Metrics)
+	for _, r := range results {
+		metrics.Append(r)
+	}
+}
+
+type remoteServiceKey struct {
+	Local  api.ProcessInterface
+	Role   enums.ConnectionRole
+	Remote string
+	// the remote is resolved as the kubernetes service, otherwise it's the remote IP address
+	IsService bool
+}
+
+func (l *Listener) FlushMetrics(traffics []*base.ProcessTraffic, builder *base.MetricsBuilder) {
+	aggregated := make(map[remoteServiceKey]*Metrics)
+	for _, traffic := range traffics {
+		metrics := traffic.Metrics.GetMetrics(Name).(*Metrics)
+		if metrics.IsEmpty() {
+			continue
+		}
+		role := traffic.Role
+		if role == enums.ConnectionRoleUnknown {
+			role = enums.ConnectionRoleClient
+		}
+		remote, isService := l.remoteService(traffic)
+		for _, p := range traffic.LocalProcesses {
+			key := remoteServiceKey{Local: p, Role: role, Remote: remote, IsService: isService}
+			if aggregated[key] == nil {
+				aggregated[key] = NewMetrics()
+			}
+			aggregated[key].merge(metrics)
+		}
+	}
+
+	for key, metrics := range aggregated {
+		builder.AppendMetrics(key.Local.Entity().ServiceName, key.Local.Entity().InstanceName,
+			l.buildMeters(builder.MetricPrefix(), key, metrics))
+	}
+}
+
+// remoteService find the kubernetes service of the remote address, through the remote processes in the same node
+// or the cluster IP of the service, otherwise use the remote IP address
+func (l *Listener) remoteService(traffic *base.ProcessTraffic) (string, bool) {
+	for _, p := range traffic.RemoteProcesses {
+		if p.DetectType() == api.Kubernetes {
+			return p.Entity().ServiceName, true
+		}
+	}
+	if l.k8sOperator != nil {
+		if service := l.k8sOperator.FindServiceByClusterIP(traffic.RemoteIP); service != "" {
+			return service, true
+		}
+	}
+	return traffic.RemoteIP, false
+}
+
+func (l *Listener) buildMeters(prefix string, key remoteServiceKey, metrics *Metrics) []*v3.MeterData {
+	labels := []*v3.Label{
+		{Name: "process_id", Value: key.Local.ID()},
+		{Name: "side", Value: key.Role.String()},
+	}
+	if key.IsService {
+		labels = append(labels, &v3.Label{Name: "remote_service", Value: key.Remote})
+	} else {
+		labels = append(labels, &v3.Label{Name: "remote_address", Value: key.Remote})
+	}
+	namePrefix := fmt.Sprintf("%s%s_tls_", prefix, key.Role.String())
+
+	result := []*v3.MeterData{l.buildSingleValue(namePrefix+"handshake_counter", labels, metrics.HandshakeCount)}
+	for reason, count := range metrics.ErrorCounts {
+		result = append(result, l.buildSingleValue(namePrefix+"handshake_error_counter",
+			append(labels[:len(labels):len(labels)], &v3.Label{Name: "reason", Value: reason}), count))
+	}
+	for version, count := range metrics.VersionCounts {
+		result = append(result, l.buildSingleValue(namePrefix+"version_counter",
+			append(labels[:len(labels):len(labels)], &v3.Label{Name: "version", Value: version}), count))
+	}
+
+	values := make([]*v3.MeterBucketValue, 0, len(handshakeDurationBuckets))
+	var finished int64
+	for inx, bucket := range handshakeDurationBuckets {
+		finished += metrics.DurationBuckets[inx]
+		values = append(values, &v3.MeterBucketValue{Bucket: bucket, Count: metrics.DurationBuckets[inx]})
+	}
+	if finished > 0 {
+		result = append(result, &v3.MeterData{
+			Metric: &v3.MeterData_Histogram{
+				Histogram: &v3.MeterHistogram{
+					Name:   namePrefix + "handshake_duration_histogram",
+					Labels: labels,
+					Values: values,
+				},
+			},
+		})
+	}
+	return result
+}
+
+func (l *Listener) buildSingleValue(name string, labels []*v3.Label, value int64) *v3.MeterData {
+	return &v3.MeterData{
+		Metric: &v3.MeterData_SingleValue{
+			SingleValue: &v3.MeterSingleValue{
+				Name:   name,
+				Labels: labels,
+				Value:  float64(value),
+			},
+		},
+	}
+}
+
+func (l *Listener) PostFlushConnectionMetrics(ccs []*base.ConnectionContext) {
+	for _, cc := range ccs {
+		cc.Metrics.GetMetrics(Name).(*Metrics).Reset()
+	}
+}
@@ -0,0 +1,95 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/task/network/analyze/base"
+)
+
+// the buckets(ms) of the handshake duration histogram, each value is the lower bound of the bucket
+var handshakeDurationBuckets = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+// Metrics is the TLS handshakes of the connection or the traffic in the current report interval
+type Metrics struct {
+	HandshakeCount int64
+	// key: the failure reason, value: count
+	ErrorCounts map[string]int64
+	// key: the TLS version name, value: count
+	VersionCounts map[string]int64
+	// the count of the finished handshakes in each duration bucket
+	DurationBuckets []int64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		ErrorCounts:     make(map[string]int64),
+		VersionCounts:   make(map[string]int64),
+		DurationBuckets: make([]int64, len(handshakeDurationBuckets)),
+	}
+}
+
+func (m *Metrics) Append(result *HandshakeResult) {
+	m.HandshakeCount++
+	if result.Version != "" {
+		m.VersionCounts[result.Version]++
+	}
+	if result.Error != "" {
+		m.ErrorCounts[result.Error]++
+		return
+	}
+	m.DurationBuckets[durationBucketIndex(float64(result.Duration)/float64(time.Millisecond))]++
+}
+
+func (m *Metrics) MergeMetricsFromConnection(_ *base.ConnectionContext, data base.ConnectionMetrics) {
+	m.merge(data.(*Metrics))
+}
+
+func (m *Metrics) merge(other *Metrics) {
+	m.HandshakeCount += other.HandshakeCount
+	for reason, count := range other.ErrorCounts {
+		m.ErrorCounts[reason] += count
+	}
+	for version, count := range other.VersionCounts {
+		m.VersionCounts[version] += count
+	}
+	for inx, count := range other.DurationBuckets {
+		m.DurationBuckets[inx] += count
+	}
+}
+
+func (m *Metrics) IsEmpty() bool {
+	return m.HandshakeCount == 0
+}
+
+func (m *Metrics) Reset() {
+	m.HandshakeCount = 0
+	m.ErrorCounts = make(map[string]int64)
+	m.VersionCounts = make(map[string]int64)
+	m.DurationBuckets = make([]int64, len(handshakeDurationBuckets))
+}
+
+func durationBucketIndex(durationMs float64) int {
+	for inx := len(handshakeDurationBuckets) - 1; inx > 0; inx-- {
+		if durationMs >= handshakeDurationBuckets[inx] {
+			return inx
+		}
+	}
+	return 0
+}
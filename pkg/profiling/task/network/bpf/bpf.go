@@ -32,9 +32,11 @@ type Loader struct {
 	*bpfObjects
 }
 
-func NewLoader() (*Loader, error) {
+// NewLoader loads the BPF objects, the data queues are transported through the ring buffer
+// when the size is bigger than 0 and the kernel supports it
+func NewLoader(ringBufferSize int) (*Loader, error) {
 	objs := bpfObjects{}
-	if err := btf.LoadBPFAndAssign(loadBpf, &objs); err != nil {
+	if err := btf.LoadBPFAndAssignWithRingBuffer(loadBpf, &objs, ringBufferSize); err != nil {
 		return nil, err
 	}
 
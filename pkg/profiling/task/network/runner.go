@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/cilium/ebpf"
+	"github.com/docker/go-units"
 
 	"github.com/hashicorp/go-multierror"
 
@@ -54,6 +55,7 @@ type Runner struct {
 	eventClient    v3.SpanAttachedEventReportServiceClient
 	reportInterval time.Duration
 	meterPrefix    string
+	ringBufferSize int
 
 	bpf            *bpf.Loader
 	processes      map[int32][]api.ProcessInterface
@@ -124,7 +126,7 @@ func (r *Runner) Start(ctx context.Context, task *base.ProfilingTask, processes
 
 	r.ctx, r.cancel = context.WithCancel(ctx)
 	// load bpf program
-	bpfLoader, err := bpf.NewLoader()
+	bpfLoader, err := bpf.NewLoader(r.ringBufferSize)
 	if err != nil {
 		return err
 	}
@@ -172,6 +174,10 @@ func (r *Runner) Start(ctx context.Context, task *base.ProfilingTask, processes
 		"kfree_skb_reason": bpfLoader.KfreeSkbReason}); e != nil {
 		log.Warnf("cannot monitor the tcp drop, ignore it and keep profiling: %v", e)
 	}
+	// out of order, the function could be inlined in some kernel versions
+	if e := bpfLoader.AddLinkOrError(link.Kprobe, map[string]*ebpf.Program{"tcp_data_queue_ofo": bpfLoader.TcpDataQueueOfo}); e != nil {
+		log.Warnf("cannot monitor the tcp out of order, ignore it and keep profiling: %v", e)
+	}
 
 	bpfLoader.AddLink(link.Kprobe, map[string]*ebpf.Program{"ip_finish_output": bpfLoader.IpFinishOutput})
 	bpfLoader.AddLink(link.Kprobe, map[string]*ebpf.Program{"skb_copy_datagram_iter": bpfLoader.SkbCopyDatagramIter})
@@ -355,6 +361,13 @@ func (r *Runner) init0(config *base.TaskConfig, moduleMgr *module.Manager) error
 		return fmt.Errorf("please provide the meter prefix")
 	}
 	r.meterPrefix = config.Network.MeterPrefix + "_"
+	if config.Network.RingBuffer.Active {
+		size, err := units.RAMInBytes(config.Network.RingBuffer.Size)
+		if err != nil {
+			return fmt.Errorf("parsing ring buffer size failure: %v", err)
+		}
+		r.ringBufferSize = int(size)
+	}
 
 	err = r.analyzeContext.Init(config, moduleMgr)
 	if err != nil {
@@ -101,30 +101,42 @@ func (r *Runner) Run(_ context.Context, notify base.ProfilingRunningSuccessNotif
 		return err
 	}
 	// update the monitor pid
-	funcName := "do_finish_task_switch"
-	replacedPid := false
-	for i, ins := range spec.Programs[funcName].Instructions {
-		if ins.Reference() == "MONITOR_PID" {
-			spec.Programs[funcName].Instructions[i].Constant = int64(r.pid)
-			spec.Programs[funcName].Instructions[i].Offset = 0
-			replacedPid = true
+	for _, funcName := range []string{"do_finish_task_switch", "sched_switch"} {
+		if err1 := replaceMonitorPid(spec.Programs[funcName], r.pid); err1 != nil {
+			return fmt.Errorf("replace the monitor pid of %s failure: %v", funcName, err1)
 		}
 	}
-	if !replacedPid {
-		return fmt.Errorf("replace the monitor pid failure")
-	}
 	if err1 := spec.LoadAndAssign(&objs, btf.GetEBPFCollectionOptionsIfNeed(spec)); err1 != nil {
 		return err1
 	}
 	r.bpf = &objs
 
+	// prefer the scheduler switch tracepoint which aggregates the blocked time only,
+	// fallback to the finish task switch kprobe when the tracepoint cannot be attached
+	linker := btf.NewLinker()
+	linker.AddTracePoint("sched", "sched_switch", objs.SchedSwitch)
+	if err = linker.HasError(); err != nil {
+		log.Warnf("attach to the scheduler switch tracepoint failure, fallback to the finish task switch kprobe: %v", err)
+		_ = linker.Close()
+		if linker, err = r.linkFinishTaskSwitch(&objs); err != nil {
+			return err
+		}
+	}
+	r.kprobe = linker
+
+	notify()
+	<-r.stopChan
+	return nil
+}
+
+func (r *Runner) linkFinishTaskSwitch(objs *bpfObjects) (*btf.Linker, error) {
 	symbols := r.findMatchesSymbol()
 	linker := btf.NewLinker()
 	linkedCount := 0
 	for _, symbol := range symbols {
 		switchers := make(map[string]*ebpf.Program)
 		switchers[symbol] = objs.DoFinishTaskSwitch
-		err = linker.AddLinkOrError(link.Kprobe, switchers)
+		err := linker.AddLinkOrError(link.Kprobe, switchers)
 		if err != nil {
 			log.Warnf("link to finish task swtich(%s) failure: %v", symbol, err)
 			continue
@@ -133,16 +145,30 @@ func (r *Runner) Run(_ context.Context, notify base.ProfilingRunningSuccessNotif
 	}
 
 	if linkedCount == 0 {
-		return fmt.Errorf("link to finish task swtich failure: no symbol linked")
+		return nil, fmt.Errorf("link to finish task swtich failure: no symbol linked")
 	}
 
 	if err := linker.HasError(); err != nil {
-		return fmt.Errorf("link to finish task swtich failure: %v", err)
+		return nil, fmt.Errorf("link to finish task swtich failure: %v", err)
 	}
-	r.kprobe = linker
+	return linker, nil
+}
 
-	notify()
-	<-r.stopChan
+func replaceMonitorPid(prog *ebpf.ProgramSpec, pid int32) error {
+	if prog == nil {
+		return fmt.Errorf("program not found")
+	}
+	replacedPid := false
+	for i, ins := range prog.Instructions {
+		if ins.Reference() == "MONITOR_PID" {
+			prog.Instructions[i].Constant = int64(pid)
+			prog.Instructions[i].Offset = 0
+			replacedPid = true
+		}
+	}
+	if !replacedPid {
+		return fmt.Errorf("the monitor pid reference not found")
+	}
 	return nil
 }
 
@@ -0,0 +1,174 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build linux
+
+package oncpu
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cilium/ebpf"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/task/base"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/profiling"
+)
+
+const (
+	pythonStackMaxDepth = 32
+	pythonEvalFrame     = "_PyEval_EvalFrameDefault"
+	pythonMaxStringLen  = 256
+
+	// the offsets in the PyASCIIObject and PyCompactUnicodeObject
+	pythonUnicodeState    = 32
+	pythonUnicodeASCII    = 48
+	pythonUnicodeUTF8     = 56
+	pythonUnicodeASCIIBit = 1 << 6
+)
+
+// pythonOffsets must be same with the "python_offsets_t" in the BPF
+type pythonOffsets struct {
+	TStateCurrentAddr  uint64
+	TStateFrame        uint32
+	CFrameCurrentFrame uint32
+	FrameBack          uint32
+	FrameCode          uint32
+}
+
+type pythonStack struct {
+	Codes [pythonStackMaxDepth]uint64
+}
+
+// pythonSymbolizer reading the code object names from the python process memory
+type pythonSymbolizer struct {
+	runtime *profiling.PythonRuntime
+	mem     *os.File
+	codes   map[uint64]string
+}
+
+func newPythonSymbolizer(pid int32, info *profiling.Info) (*pythonSymbolizer, error) {
+	runtime, err := info.FindPythonRuntime()
+	if err != nil {
+		return nil, err
+	}
+	mem, err := os.Open(host.GetHostProcInHost(fmt.Sprintf("%d/mem", pid)))
+	if err != nil {
+		return nil, fmt.Errorf("open the process memory failure: %v", err)
+	}
+	return &pythonSymbolizer{runtime: runtime, mem: mem, codes: make(map[uint64]string)}, nil
+}
+
+func (p *pythonSymbolizer) UpdateOffsets(m *ebpf.Map) error {
+	return m.Update(uint32(0), &pythonOffsets{
+		TStateCurrentAddr:  p.runtime.TStateCurrentAddr,
+		TStateFrame:        p.runtime.Offsets.TStateFrame,
+		CFrameCurrentFrame: p.runtime.Offsets.CFrameCurrentFrame,
+		FrameBack:          p.runtime.Offsets.FrameBack,
+		FrameCode:          p.runtime.Offsets.FrameCode,
+	}, ebpf.UpdateAny)
+}
+
+// Symbols of the python stack, the top frame first
+func (p *pythonSymbolizer) Symbols(stack *pythonStack) []string {
+	result := make([]string, 0)
+	for _, code := range stack.Codes {
+		if code == 0 {
+			break
+		}
+		result = append(result, p.codeName(code))
+	}
+	return result
+}
+
+func (p *pythonSymbolizer) codeName(code uint64) string {
+	if name := p.codes[code]; name != "" {
+		return name
+	}
+	offsets := p.runtime.Offsets
+	name := p.readString(p.readPointer(code + offsets.CodeName))
+	if name == "" {
+		return base.MissingSymbol
+	}
+	filename := p.readString(p.readPointer(code + offsets.CodeFilename))
+	var line [4]byte
+	if _, err := p.mem.ReadAt(line[:], int64(code+offsets.CodeFirstLineNo)); err == nil {
+		name = fmt.Sprintf("%s (%s:%d)", name, filename, binary.LittleEndian.Uint32(line[:]))
+	}
+	p.codes[code] = name
+	return name
+}
+
+func (p *pythonSymbolizer) readPointer(addr uint64) uint64 {
+	var buf [8]byte
+	if addr == 0 {
+		return 0
+	}
+	if _, err := p.mem.ReadAt(buf[:], int64(addr)); err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// readString from the PyUnicodeObject, only the compact ASCII or UTF-8 cached string is supported
+func (p *pythonSymbolizer) readString(addr uint64) string {
+	if addr == 0 {
+		return ""
+	}
+	var state [4]byte
+	if _, err := p.mem.ReadAt(state[:], int64(addr+pythonUnicodeState)); err != nil {
+		return ""
+	}
+	data := addr + pythonUnicodeASCII
+	if binary.LittleEndian.Uint32(state[:])&pythonUnicodeASCIIBit == 0 {
+		if data = p.readPointer(addr + pythonUnicodeUTF8); data == 0 {
+			return ""
+		}
+	}
+	buf := make([]byte, pythonMaxStringLen)
+	n, _ := p.mem.ReadAt(buf, int64(data))
+	str, _, _ := strings.Cut(string(buf[:n]), "\x00")
+	return str
+}
+
+func (p *pythonSymbolizer) Close() error {
+	return p.mem.Close()
+}
+
+// mergePythonStack replacing the native interpreter frames with the python frames,
+// keep the native frames before the first and after the last python eval frame
+func mergePythonStack(native, python []string) []string {
+	first, last := -1, -1
+	for i, s := range native {
+		if s == pythonEvalFrame {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return append(python, native...)
+	}
+	result := make([]string, 0, len(native)+len(python))
+	result = append(result, native[:first]...)
+	result = append(result, python...)
+	return append(result, native[last+1:]...)
+}
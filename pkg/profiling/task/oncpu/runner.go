@@ -22,6 +22,7 @@ package oncpu
 import (
 	"context"
 	"fmt"
+	"os"
 	"runtime"
 	"time"
 
@@ -48,6 +49,7 @@ var log = logger.GetLogger("profiling", "task", "oncpu")
 type Event struct {
 	UserStackID   uint32
 	KernelStackID uint32
+	PythonStackID uint32
 }
 
 type Runner struct {
@@ -56,6 +58,9 @@ type Runner struct {
 	processProfiling *profiling.Info
 	kernelProfiling  *profiling.Info
 	dumpFrequency    int64
+	javaPerfMapDump  bool
+	perfMapModTime   time.Time
+	python           *pythonSymbolizer
 
 	// runtime
 	perfEventFds    []int
@@ -77,8 +82,9 @@ func NewRunner(config *base.TaskConfig, _ *module.Manager) (base.ProfileTaskRunn
 		return nil, fmt.Errorf("the ON_CPU dump period could not be smaller than 1ms")
 	}
 	return &Runner{
-		base:          base.NewBaseRunner(),
-		dumpFrequency: time.Second.Milliseconds() / dumpPeriod.Milliseconds(),
+		base:            base.NewBaseRunner(),
+		dumpFrequency:   time.Second.Milliseconds() / dumpPeriod.Milliseconds(),
+		javaPerfMapDump: config.OnCPU.JavaPerfMapDump,
 	}, nil
 }
 
@@ -92,6 +98,13 @@ func (r *Runner) Init(_ *base.ProfilingTask, processes []api.ProcessInterface) e
 	if r.processProfiling = curProcess.ProfilingStat(); r.processProfiling == nil {
 		return fmt.Errorf("this process could not be profiling")
 	}
+	r.javaPerfMapDump = r.javaPerfMapDump && process.IsJavaProcess(r.pid)
+	r.refreshPerfMap()
+	python, err := newPythonSymbolizer(r.pid, r.processProfiling)
+	if err != nil {
+		log.Debugf("the process(%d) is not a supported python process: %v", r.pid, err)
+	}
+	r.python = python
 	// kernel profiling stat
 	kernelProfiling, err := process.KernelFileProfilingStat()
 	if err != nil {
@@ -128,6 +141,11 @@ func (r *Runner) Run(_ context.Context, notify base.ProfilingRunningSuccessNotif
 	}
 	defer objs.Close()
 	r.bpf = &objs
+	if r.python != nil {
+		if err := r.python.UpdateOffsets(objs.PythonOffsets); err != nil {
+			return fmt.Errorf("update the python offsets failure: %v", err)
+		}
+	}
 
 	// opened perf events
 	perfEvents, err := r.openPerfEvent(objs.DoPerfEvent.FD())
@@ -144,6 +162,26 @@ func (r *Runner) Run(_ context.Context, notify base.ProfilingRunningSuccessNotif
 	return nil
 }
 
+// refreshPerfMap reload the JIT symbols when the perf map file has been updated
+func (r *Runner) refreshPerfMap() {
+	if r.javaPerfMapDump {
+		if err := process.DumpJavaPerfMap(r.pid); err != nil {
+			log.Warnf("dump the perf map of java process(%d) failure: %v", r.pid, err)
+		}
+	}
+	stat, err := os.Stat(process.PerfMapFilePath(r.pid))
+	if err != nil || !stat.ModTime().After(r.perfMapModTime) {
+		return
+	}
+	info, err := process.RefreshPerfMap(r.pid, r.processProfiling)
+	if err != nil {
+		log.Warnf("refresh the perf map of process(%d) failure: %v", r.pid, err)
+		return
+	}
+	r.processProfiling = info
+	r.perfMapModTime = stat.ModTime()
+}
+
 func (r *Runner) openPerfEvent(perfFd int) ([]int, error) {
 	eventAttr := &unix.PerfEventAttr{
 		Type:   unix.PERF_TYPE_SOFTWARE,
@@ -203,6 +241,11 @@ func (r *Runner) Stop() error {
 				result = multierror.Append(result, err)
 			}
 		}
+		if r.python != nil {
+			if err := r.python.Close(); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
 
 		close(r.stopChan)
 	})
@@ -213,6 +256,7 @@ func (r *Runner) FlushData() ([]*v3.EBPFProfilingData, error) {
 	if r.bpf == nil {
 		return nil, nil
 	}
+	r.refreshPerfMap()
 	var stack Event
 	var counter uint32
 	iterate := r.bpf.Counts.Iterate()
@@ -229,9 +273,10 @@ func (r *Runner) FlushData() ([]*v3.EBPFProfilingData, error) {
 		}
 
 		// user stack
-		if d := r.base.GenerateProfilingData(r.processProfiling, stack.UserStackID, stacks,
-			v3.EBPFProfilingStackType_PROCESS_USER_SPACE, stackSymbols); d != nil {
-			metadatas = append(metadatas, d)
+		userStack := r.base.GenerateProfilingData(r.processProfiling, stack.UserStackID, stacks,
+			v3.EBPFProfilingStackType_PROCESS_USER_SPACE, stackSymbols)
+		if userStack = r.appendPythonStack(userStack, stack); userStack != nil {
+			metadatas = append(metadatas, userStack)
 		}
 
 		if len(metadatas) == 0 {
@@ -270,6 +315,31 @@ func (r *Runner) FlushData() ([]*v3.EBPFProfilingData, error) {
 	return result, nil
 }
 
+// appendPythonStack merge the python frames into the user space stack
+func (r *Runner) appendPythonStack(userStack *v3.EBPFProfilingStackMetadata, event Event) *v3.EBPFProfilingStackMetadata {
+	if r.python == nil || event.PythonStackID == 0 {
+		return userStack
+	}
+	var stack pythonStack
+	if err := r.bpf.PythonStacks.Lookup(event.PythonStackID, &stack); err != nil {
+		log.Debugf("could not found the python stack: %d, error: %v", event.PythonStackID, err)
+		return userStack
+	}
+	symbols := r.python.Symbols(&stack)
+	if len(symbols) == 0 {
+		return userStack
+	}
+	if userStack == nil {
+		return &v3.EBPFProfilingStackMetadata{
+			StackType:    v3.EBPFProfilingStackType_PROCESS_USER_SPACE,
+			StackId:      int32(event.PythonStackID),
+			StackSymbols: symbols,
+		}
+	}
+	userStack.StackSymbols = mergePythonStack(userStack.StackSymbols, symbols)
+	return userStack
+}
+
 func (r *Runner) closePerfEvent(fd int) error {
 	if fd <= 0 {
 		return nil
@@ -0,0 +1,148 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package task
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/apache/skywalking-rover/pkg/core/otlp"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/base"
+
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	profilesv1 "go.opentelemetry.io/proto/otlp/profiles/v1development"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	profiling_v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
+)
+
+const otlpProfilesScopeName = "skywalking-rover/profiling"
+
+// the sample types(type and unit) of the OTLP profile for each profiling target
+var otlpProfileSampleTypes = map[base.TargetType][][2]string{
+	base.TargetTypeOnCPU:      {{"samples", "count"}},
+	base.TargetTypeOffCPU:     {{"switches", "count"}, {"off_cpu", "nanoseconds"}},
+	base.TargetTypeMemoryLeak: {{"allocations", "count"}, {"space", "bytes"}},
+}
+
+// otlpProfileBuilder converts the profiling data of a task into the OTLP profile,
+// the symbols are deduplicated through the string, function and location tables
+type otlpProfileBuilder struct {
+	profile   *profilesv1.Profile
+	strings   map[string]int32
+	locations map[string]int32
+}
+
+func newOTLPProfileBuilder(c *Context, sampleTypes [][2]string, startTime, endTime time.Time) *otlpProfileBuilder {
+	b := &otlpProfileBuilder{
+		profile: &profilesv1.Profile{
+			StringTable:   []string{""},
+			TimeNanos:     startTime.UnixNano(),
+			DurationNanos: endTime.Sub(startTime).Nanoseconds(),
+		},
+		strings:   map[string]int32{"": 0},
+		locations: make(map[string]int32),
+	}
+	id := uuid.New()
+	b.profile.ProfileId = id[:]
+	for _, t := range sampleTypes {
+		b.profile.SampleType = append(b.profile.SampleType, &profilesv1.ValueType{
+			TypeStrindex:           b.stringIndex(t[0]),
+			UnitStrindex:           b.stringIndex(t[1]),
+			AggregationTemporality: profilesv1.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+		})
+	}
+	b.profile.AttributeTable = []*commonv1.KeyValue{
+		otlp.StringAttribute("rover.task_id", c.task.TaskID),
+		otlp.StringAttribute("rover.target_type", string(c.task.TargetType)),
+	}
+	b.profile.AttributeIndices = []int32{0, 1}
+	return b
+}
+
+func (b *otlpProfileBuilder) stringIndex(s string) int32 {
+	if index, exist := b.strings[s]; exist {
+		return index
+	}
+	index := int32(len(b.profile.StringTable))
+	b.profile.StringTable = append(b.profile.StringTable, s)
+	b.strings[s] = index
+	return index
+}
+
+func (b *otlpProfileBuilder) locationIndex(symbol string) int32 {
+	if index, exist := b.locations[symbol]; exist {
+		return index
+	}
+	functionIndex := int32(len(b.profile.FunctionTable))
+	b.profile.FunctionTable = append(b.profile.FunctionTable, &profilesv1.Function{NameStrindex: b.stringIndex(symbol)})
+	index := int32(len(b.profile.LocationTable))
+	b.profile.LocationTable = append(b.profile.LocationTable, &profilesv1.Location{
+		Line: []*profilesv1.Line{{FunctionIndex: functionIndex}},
+	})
+	b.locations[symbol] = index
+	return index
+}
+
+// addSample with the stacks which ordered from the leaf(kernel) to the root(user)
+func (b *otlpProfileBuilder) addSample(stacks []*profiling_v3.EBPFProfilingStackMetadata, values ...int64) {
+	start := int32(len(b.profile.LocationIndices))
+	for _, stack := range stacks {
+		for _, symbol := range stack.GetStackSymbols() {
+			b.profile.LocationIndices = append(b.profile.LocationIndices, b.locationIndex(symbol))
+		}
+	}
+	b.profile.Sample = append(b.profile.Sample, &profilesv1.Sample{
+		LocationsStartIndex: start,
+		LocationsLength:     int32(len(b.profile.LocationIndices)) - start,
+		Value:               values,
+	})
+}
+
+// buildOTLPProfile converts the flushed profiling data of the task, returns nil when the target type is not supported
+func buildOTLPProfile(c *Context, data []*profiling_v3.EBPFProfilingData, startTime, endTime time.Time) *profilesv1.ResourceProfiles {
+	sampleTypes := otlpProfileSampleTypes[c.task.TargetType]
+	if len(sampleTypes) == 0 || len(data) == 0 {
+		return nil
+	}
+	builder := newOTLPProfileBuilder(c, sampleTypes, startTime, endTime)
+	for _, d := range data {
+		if onCPU := d.GetOnCPU(); onCPU != nil {
+			builder.addSample(onCPU.GetStacks(), int64(onCPU.GetDumpCount()))
+		} else if offCPU := d.GetOffCPU(); offCPU != nil {
+			builder.addSample(offCPU.GetStacks(), int64(offCPU.GetSwitchCount()), offCPU.GetDuration())
+		}
+	}
+
+	var attrs []*commonv1.KeyValue
+	if len(c.processes) > 0 {
+		entity := c.processes[0].Entity()
+		attrs = otlp.AppendStringAttribute(attrs, "service.name", entity.ServiceName)
+		attrs = otlp.AppendStringAttribute(attrs, "service.instance.id", entity.InstanceName)
+		attrs = otlp.AppendStringAttribute(attrs, "process.executable.name", entity.ProcessName)
+		attrs = append(attrs, otlp.IntAttribute("process.pid", int64(c.processes[0].Pid())))
+	}
+	return &profilesv1.ResourceProfiles{
+		Resource: &resourcev1.Resource{Attributes: attrs},
+		ScopeProfiles: []*profilesv1.ScopeProfiles{{
+			Scope:    &commonv1.InstrumentationScope{Name: otlpProfilesScopeName},
+			Profiles: []*profilesv1.Profile{builder.profile},
+		}},
+	}
+}
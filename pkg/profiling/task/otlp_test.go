@@ -0,0 +1,88 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package task
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/profiling/task/base"
+
+	profiling_v3 "skywalking.apache.org/repo/goapi/collect/ebpf/profiling/v3"
+)
+
+func TestBuildOTLPProfile(t *testing.T) {
+	stack := func(symbols ...string) *profiling_v3.EBPFProfilingStackMetadata {
+		return &profiling_v3.EBPFProfilingStackMetadata{StackSymbols: symbols}
+	}
+	offCPU := func(switches int32, duration int64, stacks ...*profiling_v3.EBPFProfilingStackMetadata) *profiling_v3.EBPFProfilingData {
+		return &profiling_v3.EBPFProfilingData{Profiling: &profiling_v3.EBPFProfilingData_OffCPU{
+			OffCPU: &profiling_v3.EBPFOffCPUProfiling{Stacks: stacks, SwitchCount: switches, Duration: duration},
+		}}
+	}
+	c := &Context{task: &base.ProfilingTask{TaskID: "task-1", TargetType: base.TargetTypeOffCPU}}
+	data := []*profiling_v3.EBPFProfilingData{
+		offCPU(2, 100, stack("schedule", "do_nanosleep"), stack("nanosleep", "main")),
+		offCPU(1, 50, stack("schedule"), stack("read", "main")),
+	}
+	start := time.Unix(100, 0)
+
+	result := buildOTLPProfile(c, data, start, start.Add(time.Second*5))
+	if result == nil {
+		t.Fatal("the profile should be built")
+	}
+	profile := result.ScopeProfiles[0].Profiles[0]
+	symbols := func(sampleIndex int) []string {
+		sample := profile.Sample[sampleIndex]
+		names := make([]string, 0)
+		for _, locationIndex := range profile.LocationIndices[sample.LocationsStartIndex : sample.LocationsStartIndex+sample.LocationsLength] {
+			function := profile.FunctionTable[profile.LocationTable[locationIndex].Line[0].FunctionIndex]
+			names = append(names, profile.StringTable[function.NameStrindex])
+		}
+		return names
+	}
+
+	tests := []struct {
+		sample  int
+		symbols []string
+		values  []int64
+	}{
+		{sample: 0, symbols: []string{"schedule", "do_nanosleep", "nanosleep", "main"}, values: []int64{2, 100}},
+		{sample: 1, symbols: []string{"schedule", "read", "main"}, values: []int64{1, 50}},
+	}
+	for _, tt := range tests {
+		if s := symbols(tt.sample); !reflect.DeepEqual(s, tt.symbols) {
+			t.Errorf("the symbols of sample %d should be %v, but got %v", tt.sample, tt.symbols, s)
+		}
+		if v := profile.Sample[tt.sample].Value; !reflect.DeepEqual(v, tt.values) {
+			t.Errorf("the values of sample %d should be %v, but got %v", tt.sample, tt.values, v)
+		}
+	}
+	if len(profile.LocationTable) != 5 {
+		t.Errorf("the locations should be deduplicated to 5, but got %d", len(profile.LocationTable))
+	}
+	if profile.DurationNanos != int64(time.Second*5) || len(profile.SampleType) != 2 {
+		t.Errorf("the duration or sample types is not correct: %d, %d", profile.DurationNanos, len(profile.SampleType))
+	}
+
+	c.task.TargetType = base.TargetTypeNetworkTopology
+	if buildOTLPProfile(c, data, start, start) != nil {
+		t.Errorf("the network profiling should not be converted")
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/skywalking-rover/pkg/module"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/base"
+	"github.com/apache/skywalking-rover/pkg/profiling/task/memleak"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/network"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/offcpu"
 	"github.com/apache/skywalking-rover/pkg/profiling/task/oncpu"
@@ -35,6 +36,7 @@ func init() {
 	profilingRunners[base.TargetTypeOnCPU] = oncpu.NewRunner
 	profilingRunners[base.TargetTypeOffCPU] = offcpu.NewRunner
 	profilingRunners[base.TargetTypeNetworkTopology] = network.NewRunner
+	profilingRunners[base.TargetTypeMemoryLeak] = memleak.NewRunner
 }
 
 func NewProfilingRunner(taskType base.TargetType, taskConfig *base.TaskConfig, moduleMgr *module.Manager) (base.ProfileTaskRunner, error) {
@@ -0,0 +1,117 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const vpcEndpointPrefix = "vpce-"
+
+// NetworkInterface is the elastic network interface(ENI) which owned the IP address
+type NetworkInterface struct {
+	ID               string
+	InterfaceType    string
+	Description      string
+	VpcID            string
+	SubnetID         string
+	AvailabilityZone string
+	OwnerID          string
+	RequesterID      string
+	RequesterManaged bool
+}
+
+// VPCEndpointID is the ID of the VPC endpoint when the interface is created for the interface endpoint
+func (n *NetworkInterface) VPCEndpointID() string {
+	if n.InterfaceType != "vpc_endpoint" {
+		return ""
+	}
+	for _, field := range strings.Fields(n.Description) {
+		if strings.HasPrefix(field, vpcEndpointPrefix) {
+			return field
+		}
+	}
+	return ""
+}
+
+// EC2Client querying the EC2 API through the AWS SDK, the credentials are loaded by the default chain of the SDK,
+// such as the environment variables, the web identity(IRSA) and the instance role from the instance metadata service
+type EC2Client struct {
+	client *ec2.Client
+}
+
+// NewEC2Client create the EC2 client, the region is the configured one of the SDK, or the region of the current instance
+func NewEC2Client(ctx context.Context, timeout time.Duration) (*EC2Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(timeout)))
+	if err != nil {
+		return nil, fmt.Errorf("load the AWS config failure: %v", err)
+	}
+	if cfg.Region == "" {
+		region, err := imds.NewFromConfig(cfg).GetRegion(ctx, &imds.GetRegionInput{})
+		if err != nil {
+			return nil, fmt.Errorf("query the region of the instance failure: %v", err)
+		}
+		cfg.Region = region.Region
+	}
+	return &EC2Client{client: ec2.NewFromConfig(cfg)}, nil
+}
+
+// FindNetworkInterface find the network interface which owned the IP address,
+// the private address is matched first, then the associated public address(such as the elastic IP).
+// return nil if the address not owned by any network interface in the account
+func (c *EC2Client) FindNetworkInterface(ctx context.Context, address string) (*NetworkInterface, error) {
+	filter := "association.public-ip"
+	if parsed := net.ParseIP(address); parsed != nil && parsed.IsPrivate() {
+		filter = "addresses.private-ip-address"
+	}
+	output, err := c.client.DescribeNetworkInterfaces(ctx, &ec2.DescribeNetworkInterfacesInput{
+		Filters: []types.Filter{{Name: awssdk.String(filter), Values: []string{address}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.NetworkInterfaces) == 0 {
+		return nil, nil
+	}
+	return convertNetworkInterface(&output.NetworkInterfaces[0]), nil
+}
+
+func convertNetworkInterface(n *types.NetworkInterface) *NetworkInterface {
+	return &NetworkInterface{
+		ID:               awssdk.ToString(n.NetworkInterfaceId),
+		InterfaceType:    string(n.InterfaceType),
+		Description:      awssdk.ToString(n.Description),
+		VpcID:            awssdk.ToString(n.VpcId),
+		SubnetID:         awssdk.ToString(n.SubnetId),
+		AvailabilityZone: awssdk.ToString(n.AvailabilityZone),
+		OwnerID:          awssdk.ToString(n.OwnerId),
+		RequesterID:      awssdk.ToString(n.RequesterId),
+		RequesterManaged: awssdk.ToBool(n.RequesterManaged),
+	}
+}
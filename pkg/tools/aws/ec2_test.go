@@ -0,0 +1,95 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestConvertNetworkInterface(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      types.NetworkInterface
+		expected   *NetworkInterface
+		endpointID string
+	}{
+		{
+			name: "vpc endpoint",
+			input: types.NetworkInterface{
+				NetworkInterfaceId: awssdk.String("eni-0f7db31a2b7e1a3e5"),
+				SubnetId:           awssdk.String("subnet-0e3a2c4d"),
+				VpcId:              awssdk.String("vpc-11223344"),
+				AvailabilityZone:   awssdk.String("us-east-1a"),
+				Description:        awssdk.String("VPC Endpoint Interface vpce-0a1b2c3d4e5f"),
+				OwnerId:            awssdk.String("123456789012"),
+				RequesterId:        awssdk.String("727180483921"),
+				RequesterManaged:   awssdk.Bool(true),
+				InterfaceType:      types.NetworkInterfaceTypeVpcEndpoint,
+			},
+			expected: &NetworkInterface{
+				ID:               "eni-0f7db31a2b7e1a3e5",
+				InterfaceType:    "vpc_endpoint",
+				Description:      "VPC Endpoint Interface vpce-0a1b2c3d4e5f",
+				VpcID:            "vpc-11223344",
+				SubnetID:         "subnet-0e3a2c4d",
+				AvailabilityZone: "us-east-1a",
+				OwnerID:          "123456789012",
+				RequesterID:      "727180483921",
+				RequesterManaged: true,
+			},
+			endpointID: "vpce-0a1b2c3d4e5f",
+		},
+		{
+			name: "nat gateway",
+			input: types.NetworkInterface{
+				NetworkInterfaceId: awssdk.String("eni-0a2b3c"),
+				VpcId:              awssdk.String("vpc-11223344"),
+				Description:        awssdk.String("Interface for NAT Gateway nat-0123456789"),
+				RequesterManaged:   awssdk.Bool(true),
+				InterfaceType:      types.NetworkInterfaceType("nat_gateway"),
+			},
+			expected: &NetworkInterface{
+				ID:               "eni-0a2b3c",
+				InterfaceType:    "nat_gateway",
+				Description:      "Interface for NAT Gateway nat-0123456789",
+				VpcID:            "vpc-11223344",
+				RequesterManaged: true,
+			},
+		},
+		{
+			name:     "empty fields",
+			input:    types.NetworkInterface{},
+			expected: &NetworkInterface{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := convertNetworkInterface(&tt.input)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("interface not same, expected: %v, actual: %v", tt.expected, actual)
+			}
+			if id := actual.VPCEndpointID(); id != tt.endpointID {
+				t.Errorf("VPC endpoint ID not same, expected: %s, actual: %s", tt.endpointID, id)
+			}
+		})
+	}
+}
@@ -18,43 +18,151 @@
 package btf
 
 import (
-	"bytes"
 	"embed"
 	"fmt"
-	"path/filepath"
+	"os"
+	"reflect"
 	"sync"
 
 	"github.com/cilium/ebpf"
 	"github.com/cilium/ebpf/btf"
+	"github.com/cilium/ebpf/features"
 
 	"github.com/apache/skywalking-rover/pkg/logger"
-	"github.com/apache/skywalking-rover/pkg/tools/operator"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
 )
 
 //go:embed *
 var assets embed.FS
 
+const (
+	ringBufferEnabledVariable = "rover_ringbuf_enabled"
+	maxRingBufferEntries      = 1 << 30
+)
+
 var (
 	spec        *btf.Spec
 	findBTFOnce sync.Once
 
+	ringBufferSupported   bool
+	ringBufferSupportOnce sync.Once
+
 	log = logger.GetLogger("tools", "btf")
 )
 
 func LoadBPFAndAssign(loadBPF func() (*ebpf.CollectionSpec, error), objs interface{}) error {
-	bpf, err := loadBPF()
-	if err != nil {
-		return err
+	return LoadBPFAndAssignWithRingBuffer(loadBPF, objs, 0)
+}
+
+// LoadBPFAndAssignWithRingBuffer is same with the LoadBPFAndAssign, and the data queues are transported through
+// the BPF ring buffer with the size(bytes) when the size is bigger than 0 and the kernel supports it,
+// otherwise the data queues are downgraded to the perf event array
+func LoadBPFAndAssignWithRingBuffer(loadBPF func() (*ebpf.CollectionSpec, error), objs interface{}, ringBufferSize int) error {
+	_, err := LoadBPFAndAssignWithPinning(loadBPF, objs, ringBufferSize, nil)
+	return err
+}
+
+// IsRingBufferSupported checks the kernel supports the BPF ring buffer(since 5.8)
+func IsRingBufferSupported() bool {
+	ringBufferSupportOnce.Do(func() {
+		err := features.HaveMapType(ebpf.RingBuf)
+		ringBufferSupported = err == nil
+		if err != nil {
+			log.Infof("the BPF ring buffer is not supported, the perf event array is used: %v", err)
+		}
+	})
+	return ringBufferSupported
+}
+
+func prepareDataQueues(spec *ebpf.CollectionSpec, ringBufferSize int) error {
+	useRingBuffer := ringBufferSize > 0 && IsRingBufferSupported()
+	found := false
+	for _, m := range spec.Maps {
+		if m.Type != ebpf.RingBuf {
+			continue
+		}
+		found = true
+		if useRingBuffer {
+			m.MaxEntries = ringBufferEntries(ringBufferSize)
+			continue
+		}
+		// the key size, value size and max entries are fixed by the CPU count when creating
+		m.Type = ebpf.PerfEventArray
+		m.KeySize, m.ValueSize, m.MaxEntries = 0, 0, 0
+	}
+	if !found || !useRingBuffer {
+		return nil
+	}
+	variable, ok := spec.Variables[ringBufferEnabledVariable]
+	if !ok {
+		return fmt.Errorf("the ring buffer switch %s is not found", ringBufferEnabledVariable)
+	}
+	return variable.Set(uint8(1))
+}
+
+// ringBufferEntries rounds up the size to the power of two and the multiple of the page size
+func ringBufferEntries(size int) uint32 {
+	entries := uint32(os.Getpagesize())
+	for entries < uint32(size) && entries < maxRingBufferEntries {
+		entries <<= 1
 	}
+	return entries
+}
+
+// RegisterMapsUtilization registers the utilization gauges of all hash maps in the loaded BPF maps struct,
+// the maps should be long-lived, the gauges are never unregistered
+func RegisterMapsUtilization(objs interface{}) {
+	registerMapsUtilization(reflect.Indirect(reflect.ValueOf(objs)))
+}
+
+func registerMapsUtilization(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field, fieldType := v.Field(i), v.Type().Field(i)
+		if fieldType.Anonymous {
+			registerMapsUtilization(reflect.Indirect(field))
+			continue
+		}
+		if !field.CanInterface() {
+			continue
+		}
+		m, ok := field.Interface().(*ebpf.Map)
+		name := fieldType.Tag.Get("ebpf")
+		if !ok || m == nil || name == "" || m.MaxEntries() == 0 {
+			continue
+		}
+		switch m.Type() {
+		case ebpf.Hash, ebpf.LRUHash, ebpf.PerCPUHash, ebpf.LRUCPUHash:
+			selfobs.RegisterGauge(selfobs.MapUtilization, "map", name, func() float64 {
+				return float64(countMapEntries(m)) / float64(m.MaxEntries())
+			})
+		}
+	}
+}
 
-	return bpf.LoadAndAssign(objs, GetEBPFCollectionOptionsIfNeed(bpf))
+// countMapEntries counts the keys of the map, the result is bounded by the max entries
+// since the iteration restarts when the current key is deleted concurrently
+func countMapEntries(m *ebpf.Map) uint32 {
+	var key []byte
+	var count uint32
+	for count < m.MaxEntries() {
+		next, err := m.NextKeyBytes(key)
+		if err != nil || next == nil {
+			break
+		}
+		count++
+		key = next
+	}
+	return count
 }
 
 func GetEBPFCollectionOptionsIfNeed(_ *ebpf.CollectionSpec) *ebpf.CollectionOptions {
 	findBTFOnce.Do(func() {
 		readSpec, kernel, err := getKernelBTFAddress()
 		if err != nil {
-			log.Warnf("found BTF failure: %v", err)
+			log.Warnf("found BTF failure, the BPF programs may fail to load: %v", err)
 			return
 		}
 
@@ -73,28 +181,14 @@ func getKernelBTFAddress() (spec *btf.Spec, fromKernel bool, err error) {
 		return nil, true, nil
 	}
 
-	distributeInfo, err := operator.GetDistributionInfo()
-	if err != nil {
-		return nil, false, fmt.Errorf("could not load the system distribute info: %v", err)
-	}
-	uname, err := operator.GetOSUname()
-	if err != nil {
-		return nil, false, fmt.Errorf("could not load the uname info: %v", err)
-	}
-
-	path := fmt.Sprintf("files/%s/%s/%s/%s.btf", distributeInfo.Name, distributeInfo.Version,
-		distributeInfo.Architecture, uname.Release)
-	bpfObjBuff, err := asset(path)
+	log.Infof("the kernel BTF is not exposed, trying to find the BTF from the BTF hub: %v", err)
+	kernel, err := readKernelInfo()
 	if err != nil {
-		return nil, false, fmt.Errorf("could not found customized BTF file: %s", path)
+		return nil, false, err
 	}
-	spec, err = btf.LoadSpecFromReader(bytes.NewReader(bpfObjBuff))
+	spec, err = findHubBTF(hubConfig, kernel)
 	if err != nil {
-		return nil, false, fmt.Errorf("could not load customized BTF file: %s", path)
+		return nil, false, err
 	}
 	return spec, false, nil
 }
-
-func asset(file string) ([]byte, error) {
-	return assets.ReadFile(filepath.ToSlash(file))
-}
@@ -0,0 +1,48 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"github.com/cilium/ebpf/btf"
+)
+
+// KernelEnumValues read all the value names of the enum in the kernel BTF, such as "skb_drop_reason",
+// the enum values could be changed in different kernel versions, so it should be read at runtime
+func KernelEnumValues(name string) (map[uint64]string, error) {
+	kernelSpec, err := loadKernelSpec()
+	if err != nil {
+		return nil, err
+	}
+	var enum *btf.Enum
+	if err := kernelSpec.TypeByName(name, &enum); err != nil {
+		return nil, err
+	}
+	result := make(map[uint64]string, len(enum.Values))
+	for _, v := range enum.Values {
+		result[v.Value] = v.Name
+	}
+	return result, nil
+}
+
+func loadKernelSpec() (*btf.Spec, error) {
+	// the customized BTF would be loaded when the kernel BTF not exists
+	if GetEBPFCollectionOptionsIfNeed(nil); spec != nil {
+		return spec, nil
+	}
+	return btf.LoadKernelSpec()
+}
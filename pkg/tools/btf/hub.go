@@ -0,0 +1,265 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/ebpf/btf"
+
+	"github.com/apache/skywalking-rover/pkg/tools/operator"
+)
+
+const embeddedHubDir = "files"
+
+// HubConfig locates the BTF of the running kernel when the kernel not exposes the BTF(/sys/kernel/btf/vmlinux),
+// the BTF files are organized in the btfhub layout: <distribution>/<version>/<arch>/<kernel release>.btf
+type HubConfig struct {
+	// Path is a BTF file, or a directory in the btfhub layout
+	Path string
+	// DownloadURL is the template of the URL for downloading the BTF file, the placeholders
+	// {distribution}, {version}, {arch} and {release} are replaced by the running kernel,
+	// the file could be the raw BTF or gzip compressed(".gz", ".tar.gz" or ".tgz")
+	DownloadURL string
+	// CacheDir saves the downloaded BTF files, so the file is only downloaded once in the node
+	CacheDir string
+	// DownloadTimeout of each downloading
+	DownloadTimeout time.Duration
+}
+
+var hubConfig = &HubConfig{}
+
+// SetHubConfig changes the locating of the BTF, should be called before any BPF program is loaded
+func SetHubConfig(conf *HubConfig) {
+	if conf != nil {
+		hubConfig = conf
+	}
+}
+
+// kernelInfo is the identity of the running kernel in the btfhub
+type kernelInfo struct {
+	Distribution string
+	Version      string
+	Arch         string
+	Release      string
+}
+
+func readKernelInfo() (*kernelInfo, error) {
+	distributeInfo, err := operator.GetDistributionInfo()
+	if err != nil {
+		return nil, fmt.Errorf("could not load the system distribute info: %v", err)
+	}
+	uname, err := operator.GetOSUname()
+	if err != nil {
+		return nil, fmt.Errorf("could not load the uname info: %v", err)
+	}
+	return &kernelInfo{
+		Distribution: distributeInfo.Name,
+		Version:      distributeInfo.Version,
+		Arch:         distributeInfo.Architecture,
+		Release:      uname.Release,
+	}, nil
+}
+
+func (k *kernelInfo) dir() string {
+	return path.Join(k.Distribution, k.Version, k.Arch)
+}
+
+func (k *kernelInfo) file() string {
+	return path.Join(k.dir(), k.Release+".btf")
+}
+
+func (k *kernelInfo) String() string {
+	return fmt.Sprintf("%s %s %s(%s)", k.Distribution, k.Version, k.Arch, k.Release)
+}
+
+// findHubBTF locates the BTF of the kernel from the configured path, the downloaded cache, the download URL
+// and the embedded files in order, the error describes all the tried sources when no BTF matches
+func findHubBTF(conf *HubConfig, kernel *kernelInfo) (*btf.Spec, error) {
+	var tried []string
+	if conf.Path != "" {
+		spec, err := loadBTFFromPath(conf.Path, kernel)
+		if err == nil {
+			return spec, nil
+		}
+		tried = append(tried, err.Error())
+	}
+	if conf.CacheDir != "" {
+		if spec, err := loadBTFFile(filepath.Join(conf.CacheDir, filepath.FromSlash(kernel.file()))); err == nil {
+			return spec, nil
+		}
+	}
+	if conf.DownloadURL != "" {
+		spec, err := downloadBTF(conf, kernel)
+		if err == nil {
+			return spec, nil
+		}
+		tried = append(tried, err.Error())
+	}
+	embedded, err := fs.Sub(assets, embeddedHubDir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := fs.ReadFile(embedded, kernel.file()); err == nil {
+		return btf.LoadSpecFromReader(bytes.NewReader(data))
+	}
+	tried = append(tried, fmt.Sprintf("not found in the embedded BTF files, %s", describeCandidates(embedded, kernel)))
+	return nil, fmt.Errorf("could not found the BTF of the kernel %s: %s", kernel, strings.Join(tried, "; "))
+}
+
+func loadBTFFromPath(p string, kernel *kernelInfo) (*btf.Spec, error) {
+	stat, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("read the BTF path %s failure: %v", p, err)
+	}
+	if !stat.IsDir() {
+		return loadBTFFile(p)
+	}
+	file := filepath.Join(p, filepath.FromSlash(kernel.file()))
+	if _, err = os.Stat(file); err != nil {
+		return nil, fmt.Errorf("not found %s in the BTF path, %s", kernel.file(), describeCandidates(os.DirFS(p), kernel))
+	}
+	return loadBTFFile(file)
+}
+
+func loadBTFFile(file string) (*btf.Spec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := btf.LoadSpecFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not load the BTF file %s: %v", file, err)
+	}
+	return spec, nil
+}
+
+func downloadBTF(conf *HubConfig, kernel *kernelInfo) (*btf.Spec, error) {
+	url := renderDownloadURL(conf.DownloadURL, kernel)
+	timeout := conf.DownloadTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("build the BTF download request of %s failure: %v", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download the BTF from %s failure: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download the BTF from %s failure: the response status is %s", url, resp.Status)
+	}
+	data, err := decompressBTF(url, resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decompress the BTF downloaded from %s failure: %v", url, err)
+	}
+	spec, err := btf.LoadSpecFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not load the BTF downloaded from %s: %v", url, err)
+	}
+	if conf.CacheDir != "" {
+		cache := filepath.Join(conf.CacheDir, filepath.FromSlash(kernel.file()))
+		if err = os.MkdirAll(filepath.Dir(cache), 0o755); err == nil {
+			err = os.WriteFile(cache, data, 0o600)
+		}
+		if err != nil {
+			log.Warnf("save the downloaded BTF to %s failure: %v", cache, err)
+		}
+	}
+	log.Infof("the BTF of the kernel %s is downloaded from %s", kernel, url)
+	return spec, nil
+}
+
+func renderDownloadURL(template string, kernel *kernelInfo) string {
+	return strings.NewReplacer(
+		"{distribution}", kernel.Distribution,
+		"{version}", kernel.Version,
+		"{arch}", kernel.Arch,
+		"{release}", kernel.Release,
+	).Replace(template)
+}
+
+// decompressBTF reads the BTF from the raw file, the gzip file, or the first file in the gzip compressed tarball
+func decompressBTF(name string, reader io.Reader) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		archive := tar.NewReader(gz)
+		for {
+			header, err := archive.Next()
+			if err != nil {
+				return nil, fmt.Errorf("not found the BTF file in the tarball: %v", err)
+			}
+			if header.Typeflag == tar.TypeReg {
+				return io.ReadAll(archive)
+			}
+		}
+	case strings.HasSuffix(name, ".gz"):
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return io.ReadAll(gz)
+	default:
+		return io.ReadAll(reader)
+	}
+}
+
+// describeCandidates lists the kernel releases which have the BTF with the same distribution, version and arch,
+// for helping to find out the reason of the mismatch
+func describeCandidates(fsys fs.FS, kernel *kernelInfo) string {
+	entries, err := fs.ReadDir(fsys, kernel.dir())
+	if err != nil {
+		return fmt.Sprintf("no BTF files for %s %s %s", kernel.Distribution, kernel.Version, kernel.Arch)
+	}
+	releases := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".btf") {
+			releases = append(releases, strings.TrimSuffix(e.Name(), ".btf"))
+		}
+	}
+	if len(releases) == 0 {
+		return fmt.Sprintf("no BTF files for %s %s %s", kernel.Distribution, kernel.Version, kernel.Arch)
+	}
+	sort.Strings(releases)
+	return fmt.Sprintf("the kernel releases of %s %s %s with the BTF files are: %s",
+		kernel.Distribution, kernel.Version, kernel.Arch, strings.Join(releases, ", "))
+}
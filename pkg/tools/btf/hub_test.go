@@ -0,0 +1,102 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDescribeCandidates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ubuntu/20.04/x86_64/5.4.0-42-generic.btf":  {},
+		"ubuntu/20.04/x86_64/5.4.0-26-generic.btf":  {},
+		"ubuntu/20.04/x86_64/README.md":             {},
+		"ubuntu/18.04/x86_64/4.15.0-20-generic.btf": {},
+	}
+	tests := []struct {
+		name     string
+		kernel   *kernelInfo
+		expected string
+	}{
+		{
+			name:     "same version",
+			kernel:   &kernelInfo{Distribution: "ubuntu", Version: "20.04", Arch: "x86_64", Release: "5.4.0-100-generic"},
+			expected: "the kernel releases of ubuntu 20.04 x86_64 with the BTF files are: 5.4.0-26-generic, 5.4.0-42-generic",
+		},
+		{
+			name:     "no version",
+			kernel:   &kernelInfo{Distribution: "ubuntu", Version: "22.04", Arch: "x86_64", Release: "5.15.0-25-generic"},
+			expected: "no BTF files for ubuntu 22.04 x86_64",
+		},
+		{
+			name:     "no arch",
+			kernel:   &kernelInfo{Distribution: "ubuntu", Version: "18.04", Arch: "arm64", Release: "4.15.0-20-generic"},
+			expected: "no BTF files for ubuntu 18.04 arm64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := describeCandidates(fsys, tt.kernel); actual != tt.expected {
+				t.Fatalf("expected: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDecompressBTF(t *testing.T) {
+	content := []byte("btf content")
+	gzipped := func(data []byte) []byte {
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		_, _ = w.Write(data)
+		_ = w.Close()
+		return buf.Bytes()
+	}
+	tarball := &bytes.Buffer{}
+	tw := tar.NewWriter(tarball)
+	_ = tw.WriteHeader(&tar.Header{Name: "5.4.0-42-generic.btf", Typeflag: tar.TypeReg, Size: int64(len(content)), Mode: 0o600})
+	_, _ = tw.Write(content)
+	_ = tw.Close()
+
+	tests := []struct {
+		name string
+		file string
+		data []byte
+	}{
+		{name: "raw", file: "5.4.0-42-generic.btf", data: content},
+		{name: "gzip", file: "5.4.0-42-generic.btf.gz", data: gzipped(content)},
+		{name: "tarball", file: "5.4.0-42-generic.btf.tar.gz", data: gzipped(tarball.Bytes())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := decompressBTF("https://example.com/"+tt.file, bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("decompress failure: %v", err)
+			}
+			if !bytes.Equal(actual, content) {
+				t.Fatalf("expected: %s, actual: %s", content, actual)
+			}
+		})
+	}
+}
@@ -32,10 +32,12 @@ import (
 
 	"github.com/apache/skywalking-rover/pkg/tools/elf"
 	"github.com/apache/skywalking-rover/pkg/tools/process"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
 
 	"github.com/cilium/ebpf"
 	"github.com/cilium/ebpf/link"
 	"github.com/cilium/ebpf/perf"
+	"github.com/cilium/ebpf/ringbuf"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -100,6 +102,7 @@ type UProbeExeFile struct {
 
 func (m *Linker) AddLink(linkF LinkFunc, symbolWithPrograms map[string]*ebpf.Program) {
 	if e := m.AddLinkOrError(linkF, symbolWithPrograms); e != nil {
+		selfobs.Increase(selfobs.ProbeFailure, "type", "kprobe", 1)
 		m.errors = multierror.Append(m.errors, e)
 	}
 }
@@ -141,6 +144,7 @@ func (m *Linker) AddSysCallWithKProbe(call string, linkK LinkFunc, p *ebpf.Progr
 	kprobe, err := linkK(syscallPrefix+call, p, nil)
 
 	if err != nil {
+		selfobs.Increase(selfobs.ProbeFailure, "type", "syscall", 1)
 		m.errors = multierror.Append(m.errors, fmt.Errorf("could not attach syscall with %s: %v", defaultSymbolPrefix+call, err))
 	} else {
 		log.Debugf("attach to the syscall: %s", syscallPrefix+call)
@@ -153,6 +157,7 @@ func (m *Linker) AddTracePoint(sys, name string, p *ebpf.Program) {
 	defer m.linkMutex.Unlock()
 	l, e := link.Tracepoint(sys, name, p, nil)
 	if e != nil {
+		selfobs.Increase(selfobs.ProbeFailure, "type", "tracepoint", 1)
 		m.errors = multierror.Append(m.errors, fmt.Errorf("open %s error: %v", name, e))
 	} else {
 		log.Debugf("attach to the tracepoint: sys: %s, name: %s", sys, name)
@@ -166,15 +171,28 @@ func (m *Linker) ReadEventAsync(emap *ebpf.Map, bufReader RingBufferReader, data
 
 func (m *Linker) ReadEventAsyncWithBufferSize(emap *ebpf.Map, bufReader RingBufferReader, perCPUBuffer,
 	parallels int, dataSupplier func() interface{}) {
+	if parallels < 1 {
+		m.errors = multierror.Append(m.errors, fmt.Errorf("parallels rading count must bigger than 1"))
+		return
+	}
+	// the ring buffer is shared by all CPUs, so the per CPU buffer size is ignored
+	if emap.Type() == ebpf.RingBuf {
+		rd, err := ringbuf.NewReader(emap)
+		if err != nil {
+			m.errors = multierror.Append(m.errors, fmt.Errorf("open ring buffer error: %v", err))
+			return
+		}
+		m.closers = append(m.closers, rd)
+		for i := 0; i < parallels; i++ {
+			m.asyncReadRingBufferEvent(rd, emap, dataSupplier, bufReader)
+		}
+		return
+	}
 	rd, err := perf.NewReader(emap, perCPUBuffer)
 	if err != nil {
 		m.errors = multierror.Append(m.errors, fmt.Errorf("open ring buffer error: %v", err))
 		return
 	}
-	if parallels < 1 {
-		m.errors = multierror.Append(m.errors, fmt.Errorf("parallels rading count must bigger than 1"))
-		return
-	}
 	m.closers = append(m.closers, rd)
 
 	recordBuilder := newPerfRecordBuilder(dataSupplier())
@@ -185,6 +203,7 @@ func (m *Linker) ReadEventAsyncWithBufferSize(emap *ebpf.Map, bufReader RingBuff
 
 func (m *Linker) asyncReadEvent(rd *perf.Reader, emap *ebpf.Map, recordPool *perfRecordBuilder,
 	dataSupplier func() interface{}, bufReader RingBufferReader) {
+	mapName := eventMapName(emap)
 	go func() {
 		for {
 			record := recordPool.GetRecord()
@@ -200,34 +219,64 @@ func (m *Linker) asyncReadEvent(rd *perf.Reader, emap *ebpf.Map, recordPool *per
 
 			if record.LostSamples != 0 {
 				log.Warnf("perf event queue(%s) full, dropped %d samples", emap.String(), record.LostSamples)
+				selfobs.Increase(selfobs.PerfBufferLost, "map", mapName, int64(record.LostSamples))
 				recordPool.PutRecord(record)
 				continue
 			}
 
-			data := dataSupplier()
-			if r, ok := data.(EventReader); ok {
-				sampleReader := NewReader(record.RawSample)
-				r.ReadFrom(sampleReader)
-				if readErr := sampleReader.HasError(); readErr != nil {
-					log.Warnf("parsing data from %s, raw size: %d, ringbuffer error: %v", emap.String(), len(record.RawSample), err)
-					recordPool.PutRecord(record)
-					continue
-				}
-			} else {
-				if err := binary.Read(bytes.NewBuffer(record.RawSample), binary.LittleEndian, data); err != nil {
-					log.Warnf("parsing data from %s, raw size: %d, ringbuffer error: %v", emap.String(), len(record.RawSample), err)
-					recordPool.PutRecord(record)
-					continue
-				}
+			if data, ok := decodeEvent(emap, record.RawSample, dataSupplier); ok {
+				bufReader(data)
+				selfobs.Increase(selfobs.EventRead, "map", mapName, 1)
 			}
+			recordPool.PutRecord(record)
+		}
+	}()
+}
 
-			bufReader(data)
+func (m *Linker) asyncReadRingBufferEvent(rd *ringbuf.Reader, emap *ebpf.Map, dataSupplier func() interface{}, bufReader RingBufferReader) {
+	mapName := eventMapName(emap)
+	go func() {
+		var record ringbuf.Record
+		for {
+			if err := rd.ReadInto(&record); err != nil {
+				if errors.Is(err, ringbuf.ErrClosed) {
+					return
+				}
+				log.Warnf("read from %s ringbuffer error: %v", emap.String(), err)
+				continue
+			}
 
-			recordPool.PutRecord(record)
+			if data, ok := decodeEvent(emap, record.RawSample, dataSupplier); ok {
+				bufReader(data)
+				selfobs.Increase(selfobs.EventRead, "map", mapName, 1)
+			}
 		}
 	}()
 }
 
+func eventMapName(emap *ebpf.Map) string {
+	if info, err := emap.Info(); err == nil && info.Name != "" {
+		return info.Name
+	}
+	return emap.String()
+}
+
+func decodeEvent(emap *ebpf.Map, raw []byte, dataSupplier func() interface{}) (interface{}, bool) {
+	data := dataSupplier()
+	if r, ok := data.(EventReader); ok {
+		sampleReader := NewReader(raw)
+		r.ReadFrom(sampleReader)
+		if readErr := sampleReader.HasError(); readErr != nil {
+			log.Warnf("parsing data from %s, raw size: %d, ringbuffer error: %v", emap.String(), len(raw), readErr)
+			return nil, false
+		}
+	} else if err := binary.Read(bytes.NewBuffer(raw), binary.LittleEndian, data); err != nil {
+		log.Warnf("parsing data from %s, raw size: %d, ringbuffer error: %v", emap.String(), len(raw), err)
+		return nil, false
+	}
+	return data, true
+}
+
 func (m *Linker) OpenUProbeExeFile(path string) *UProbeExeFile {
 	executable, err := link.OpenExecutable(path)
 	if err != nil {
@@ -263,14 +312,19 @@ func (u *UProbeExeFile) AddGoLink(symbol string, enter, exit *ebpf.Program, elfF
 }
 
 func (u *UProbeExeFile) AddLinkWithType(symbol string, enter bool, p *ebpf.Program) {
+	u.addLinkWithAddress(symbol, enter, p, 0)
+}
+
+func (u *UProbeExeFile) addLinkWithAddress(symbol string, enter bool, p *ebpf.Program, symbolAddress uint64) {
 	if !u.found {
 		return
 	}
 	if p == nil {
 		return
 	}
-	lk, err := u.addLinkWithType0(symbol, enter, p, 0)
+	lk, err := u.addLinkWithType0(symbol, enter, p, symbolAddress, 0)
 	if err != nil {
+		selfobs.Increase(selfobs.ProbeFailure, "type", "uprobe", 1)
 		u.linker.errors = multierror.Append(u.linker.errors, fmt.Errorf("file: %s, symbol: %s, type: %s, error: %v",
 			u.addr, symbol, u.parseEnterOrExitString(enter), err))
 	} else if lk != nil {
@@ -279,7 +333,10 @@ func (u *UProbeExeFile) AddLinkWithType(symbol string, enter bool, p *ebpf.Progr
 	}
 }
 
-func (u *UProbeExeFile) addLinkWithType0(symbol string, enter bool, p *ebpf.Program, customizeAddress uint64) (link.Link, error) {
+// addLinkWithType0 attach the uprobe to the symbol, the symbolAddress is the file offset of the symbol,
+// which is required when the symbol is not in the ELF symbol table(such as stripped Go binary)
+func (u *UProbeExeFile) addLinkWithType0(symbol string, enter bool, p *ebpf.Program,
+	symbolAddress, customizeAddress uint64) (link.Link, error) {
 	u.linker.linkMutex.Lock()
 	defer u.linker.linkMutex.Unlock()
 	// check already linked
@@ -299,23 +356,30 @@ func (u *UProbeExeFile) addLinkWithType0(symbol string, enter bool, p *ebpf.Prog
 	}
 
 	var opts *link.UprobeOptions
-	if customizeAddress > 0 {
+	if symbolAddress > 0 || customizeAddress > 0 {
 		opts = &link.UprobeOptions{
-			Offset: customizeAddress,
+			Address: symbolAddress,
+			Offset:  customizeAddress,
 		}
 	}
 	return fun(symbol, p, opts)
 }
 
 func (u *UProbeExeFile) AddGoLinkWithType(symbol string, enter bool, p *ebpf.Program, elfFile *elf.File) {
-	// if is entered type of probe, then same with the other programs
+	// if is entered type of probe, then same with the other programs,
+	// the address is read from the Go pclntab when the symbol table is stripped
 	if enter {
-		u.AddLinkWithType(symbol, enter, p)
+		var symbolAddress uint64
+		if targetSymbol := elfFile.FindSymbol(symbol); targetSymbol != nil {
+			symbolAddress = elfFile.FindBaseAddressForAttach(targetSymbol.Location)
+		}
+		u.addLinkWithAddress(symbol, enter, p, symbolAddress)
 		return
 	}
 
 	links, err := u.addGoExitLink0(symbol, p, elfFile)
 	if err != nil {
+		selfobs.Increase(selfobs.ProbeFailure, "type", "uprobe", 1)
 		u.linker.errors = multierror.Append(u.linker.errors, fmt.Errorf("file: %s, symbol: %s, type: %s, error: %v",
 			u.addr, symbol, u.parseEnterOrExitString(enter), err))
 	} else {
@@ -332,6 +396,7 @@ func (u *UProbeExeFile) addGoExitLink0(symbol string, p *ebpf.Program, elfFile *
 	if targetSymbol == nil {
 		return nil, fmt.Errorf("could not found the symbol")
 	}
+	symbolAddress := elfFile.FindBaseAddressForAttach(targetSymbol.Location)
 
 	// find the symbol real data buffer
 	buffer, err := elfFile.ReadSymbolData(".text", targetSymbol.Location, targetSymbol.Size)
@@ -380,7 +445,7 @@ func (u *UProbeExeFile) addGoExitLink0(symbol string, p *ebpf.Program, elfFile *
 
 	var result []link.Link
 	for _, address := range addresses {
-		l, err := u.addLinkWithType0(symbol, true, p, address)
+		l, err := u.addLinkWithType0(symbol, true, p, symbolAddress, address)
 		if err != nil {
 			return nil, err
 		}
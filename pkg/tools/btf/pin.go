@@ -0,0 +1,120 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/ebpf"
+
+	"golang.org/x/sys/unix"
+)
+
+// MapPinning pins the maps into the BPF filesystem, so the state in the maps is handed over
+// to the next rover when rover is restarted or upgraded
+type MapPinning struct {
+	// Path is the directory in the BPF filesystem for pinning the maps
+	Path string
+	// Maps are the names of the pinned maps
+	Maps []string
+}
+
+// LoadBPFAndAssignWithPinning is same with the LoadBPFAndAssignWithRingBuffer, and the maps in the pinning
+// are reused from the BPF filesystem when they are pinned by the previous rover, returns true when any map is reused.
+// The pinned maps are recreated when they are incompatible with current rover, such as the structure is changed
+func LoadBPFAndAssignWithPinning(loadBPF func() (*ebpf.CollectionSpec, error), objs interface{}, ringBufferSize int,
+	pinning *MapPinning) (bool, error) {
+	bpf, err := loadBPF()
+	if err != nil {
+		return false, err
+	}
+	if err = prepareDataQueues(bpf, ringBufferSize); err != nil {
+		return false, err
+	}
+	options := GetEBPFCollectionOptionsIfNeed(bpf)
+	if pinning == nil {
+		return false, bpf.LoadAndAssign(objs, options)
+	}
+
+	reused, err := pinning.prepare(bpf, options)
+	if err != nil {
+		return false, err
+	}
+	err = bpf.LoadAndAssign(objs, options)
+	if err != nil && errors.Is(err, ebpf.ErrMapIncompatible) {
+		log.Warnf("the pinned maps in %s are incompatible with current rover, the state is not handed over: %v", pinning.Path, err)
+		if err = pinning.unpin(); err != nil {
+			return false, err
+		}
+		reused = false
+		err = bpf.LoadAndAssign(objs, options)
+	}
+	if err != nil {
+		return false, err
+	}
+	return reused, nil
+}
+
+// prepare marks the maps pinned by name in the spec, returns true when any map is already pinned
+func (p *MapPinning) prepare(spec *ebpf.CollectionSpec, options *ebpf.CollectionOptions) (bool, error) {
+	if err := os.MkdirAll(p.Path, 0o700); err != nil {
+		return false, fmt.Errorf("create the pin path %s failure: %v", p.Path, err)
+	}
+	var fs unix.Statfs_t
+	if err := unix.Statfs(p.Path, &fs); err != nil {
+		return false, fmt.Errorf("read the filesystem of the pin path %s failure: %v", p.Path, err)
+	}
+	if uint32(fs.Type) != unix.BPF_FS_MAGIC {
+		return false, fmt.Errorf("the pin path %s is not in the BPF filesystem", p.Path)
+	}
+	if err := pinMapsByName(spec, p.Maps); err != nil {
+		return false, err
+	}
+	options.Maps.PinPath = p.Path
+
+	reused := false
+	for _, name := range p.Maps {
+		if _, err := os.Stat(filepath.Join(p.Path, name)); err == nil {
+			reused = true
+		}
+	}
+	return reused, nil
+}
+
+func (p *MapPinning) unpin() error {
+	for _, name := range p.Maps {
+		if err := os.Remove(filepath.Join(p.Path, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove the pinned map %s failure: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func pinMapsByName(spec *ebpf.CollectionSpec, names []string) error {
+	for _, name := range names {
+		m, exist := spec.Maps[name]
+		if !exist {
+			return fmt.Errorf("the pinned map %s is not found", name)
+		}
+		m.Pinning = ebpf.PinByName
+	}
+	return nil
+}
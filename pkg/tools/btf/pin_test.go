@@ -0,0 +1,62 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf"
+)
+
+func TestPinMapsByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		pinned  []string
+		success bool
+	}{
+		{name: "pin maps", pinned: []string{"active_connection_map", "process_monitor_control"}, success: true},
+		{name: "nothing pinned", pinned: nil, success: true},
+		{name: "map not found", pinned: []string{"active_connection_map", "not_exist"}, success: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &ebpf.CollectionSpec{Maps: map[string]*ebpf.MapSpec{
+				"active_connection_map":   {Name: "active_connection_map", Type: ebpf.Hash},
+				"process_monitor_control": {Name: "process_monitor_control", Type: ebpf.Hash},
+				"socket_data_args":        {Name: "socket_data_args", Type: ebpf.Hash},
+			}}
+			err := pinMapsByName(spec, tt.pinned)
+			if (err == nil) != tt.success {
+				t.Fatalf("expected success: %t, actual error: %v", tt.success, err)
+			}
+			if !tt.success {
+				return
+			}
+			pinned := make(map[string]bool)
+			for _, name := range tt.pinned {
+				pinned[name] = true
+			}
+			for name, m := range spec.Maps {
+				if (m.Pinning == ebpf.PinByName) != pinned[name] {
+					t.Fatalf("the pinning of map %s is not expected: %d", name, m.Pinning)
+				}
+			}
+		})
+	}
+}
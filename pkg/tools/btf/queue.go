@@ -19,9 +19,13 @@ package btf
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/apache/skywalking-rover/pkg/tools/watchdog"
+
 	"github.com/cilium/ebpf"
 )
 
@@ -29,6 +33,9 @@ import (
 // if the reducing count is almost full, then added a warning log
 const queueChannelReducingCountCheckInterval = time.Second * 5
 
+// partitionRestartWaitTimeout is the max time to wait for the previous consumer of the partition exit when restarting
+const partitionRestartWaitTimeout = time.Second * 5
+
 type PartitionContext interface {
 	Start(ctx context.Context)
 	Consume(data interface{})
@@ -40,6 +47,7 @@ type EventQueue struct {
 	receivers  []*mapReceiver
 	partitions []*partition
 
+	ctx       context.Context
 	startOnce sync.Once
 }
 
@@ -89,6 +97,16 @@ func (e *EventQueue) PartitionContexts() []PartitionContext {
 	return result
 }
 
+// WatchdogComponents returns all partitions as the watchdog components,
+// the receivers would be blocked when the partition stopped consuming
+func (e *EventQueue) WatchdogComponents() []watchdog.Component {
+	result := make([]watchdog.Component, 0, len(e.partitions))
+	for _, p := range e.partitions {
+		result = append(result, &partitionComponent{queue: e, partition: p})
+	}
+	return result
+}
+
 func (e *EventQueue) start0(ctx context.Context, linker *Linker) {
 	for _, r := range e.receivers {
 		func(receiver *mapReceiver) {
@@ -98,21 +116,9 @@ func (e *EventQueue) start0(ctx context.Context, linker *Linker) {
 		}(r)
 	}
 
-	for i := 0; i < len(e.partitions); i++ {
-		go func(ctx context.Context, inx int) {
-			p := e.partitions[inx]
-			p.ctx.Start(ctx)
-			for {
-				select {
-				// consume the data
-				case data := <-p.channel:
-					p.ctx.Consume(data)
-				// shutdown the consumer
-				case <-ctx.Done():
-					return
-				}
-			}
-		}(ctx, i)
+	e.ctx = ctx
+	for _, p := range e.partitions {
+		e.startPartition(p)
 	}
 
 	// channel reducing count check
@@ -137,15 +143,109 @@ func (e *EventQueue) start0(ctx context.Context, linker *Linker) {
 	}()
 }
 
+// startPartition start consuming the partition with a new generation, the previous generation consumer
+// must be exited before, so the partition context is never consumed concurrently
+func (e *EventQueue) startPartition(p *partition) {
+	p.lock.Lock()
+	ctx, cancel := context.WithCancel(e.ctx)
+	done := make(chan struct{})
+	p.cancel = cancel
+	p.done = done
+	p.generation++
+	generation := p.generation
+	partitionCtx := p.ctx
+	p.lock.Unlock()
+
+	go func() {
+		defer close(done)
+		partitionCtx.Start(ctx)
+		for {
+			select {
+			// consume the data
+			case data := <-p.channel:
+				partitionCtx.Consume(data)
+				p.consumed.Add(1)
+				if ctx.Err() != nil {
+					return
+				}
+			// shutdown the consumer
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	log.Debugf("queue %s partition %d is started, generation: %d", e.name, p.index, generation)
+}
+
 func (e *EventQueue) routerTransformer(data interface{}, routeGenerator func(data interface{}) int) {
 	key := routeGenerator(data)
 	e.Push(key, data)
 }
 
 type partition struct {
-	index   int
-	channel chan interface{}
-	ctx     PartitionContext
+	index    int
+	channel  chan interface{}
+	consumed atomic.Uint64
+
+	lock       sync.Mutex
+	ctx        PartitionContext
+	cancel     context.CancelFunc
+	done       chan struct{}
+	generation int
+	// is waiting for the previous consumer exit to restart
+	restarting bool
+}
+
+type partitionComponent struct {
+	queue     *EventQueue
+	partition *partition
+}
+
+func (c *partitionComponent) Name() string {
+	return fmt.Sprintf("%s-%d", c.queue.name, c.partition.index)
+}
+
+func (c *partitionComponent) Progress() (handled, pending uint64) {
+	return c.partition.consumed.Load(), uint64(len(c.partition.channel))
+}
+
+// Restart the partition consumer, the previous consumer is canceled and must be exited before starting the new one,
+// the partition context is kept, so the buffered connection state is not lost.
+// If the previous consumer is still blocked in consuming, the new consumer would be started after it exited
+func (c *partitionComponent) Restart() error {
+	p := c.partition
+	p.lock.Lock()
+	if p.cancel == nil {
+		p.lock.Unlock()
+		return fmt.Errorf("the queue %s is not started", c.queue.name)
+	}
+	if p.restarting {
+		p.lock.Unlock()
+		return fmt.Errorf("the previous consumer is still blocked, waiting for it exit")
+	}
+	p.cancel()
+	p.restarting = true
+	done := p.done
+	p.lock.Unlock()
+
+	restart := func() {
+		p.lock.Lock()
+		p.restarting = false
+		p.lock.Unlock()
+		c.queue.startPartition(p)
+	}
+	select {
+	case <-done:
+		restart()
+		return nil
+	case <-time.After(partitionRestartWaitTimeout):
+		go func() {
+			<-done
+			restart()
+		}()
+		return fmt.Errorf("the previous consumer is still blocked after canceled, " +
+			"the new consumer would be started after it exited")
+	}
 }
 
 func newPartition(index, size int, ctx PartitionContext) *partition {
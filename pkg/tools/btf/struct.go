@@ -0,0 +1,110 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cilium/ebpf/btf"
+)
+
+// KernelStructMemberOffset read the byte offset of the member in the kernel struct through the BTF, such as ("sock", "__sk_common.skc_family"),
+// the nested member is split by ".", and the members in the anonymous struct or union are found automatically.
+// the offset could be changed in different kernel versions, so it should be read at runtime instead of hard-coded
+func KernelStructMemberOffset(structName, member string) (uint32, error) {
+	kernelSpec, err := loadKernelSpec()
+	if err != nil {
+		return 0, err
+	}
+	var s *btf.Struct
+	if err := kernelSpec.TypeByName(structName, &s); err != nil {
+		return 0, err
+	}
+	offset, err := structMemberOffset(s, strings.Split(member, "."))
+	if err != nil {
+		return 0, fmt.Errorf("find the member %s in struct %s failure: %v", member, structName, err)
+	}
+	return offset, nil
+}
+
+// KernelStructSize read the byte size of the kernel struct through the BTF
+func KernelStructSize(structName string) (uint32, error) {
+	kernelSpec, err := loadKernelSpec()
+	if err != nil {
+		return 0, err
+	}
+	var s *btf.Struct
+	if err := kernelSpec.TypeByName(structName, &s); err != nil {
+		return 0, err
+	}
+	return s.Size, nil
+}
+
+func structMemberOffset(typ btf.Type, path []string) (uint32, error) {
+	var offset btf.Bits
+	current := typ
+	for _, name := range path {
+		members, err := compositeMembers(current)
+		if err != nil {
+			return 0, err
+		}
+		memberOffset, member := findMember(members, name)
+		if member == nil {
+			return 0, fmt.Errorf("member %s not found", name)
+		}
+		if member.BitfieldSize > 0 || memberOffset%8 != 0 {
+			return 0, fmt.Errorf("member %s is a bitfield", name)
+		}
+		offset += memberOffset
+		current = member.Type
+	}
+	return uint32(offset / 8), nil
+}
+
+// findMember find the member by name, including the members in the anonymous struct or union,
+// return the bits offset relative to the members owner
+func findMember(members []btf.Member, name string) (btf.Bits, *btf.Member) {
+	for i := range members {
+		m := &members[i]
+		if m.Name == name {
+			return m.Offset, m
+		}
+		if m.Name != "" {
+			continue
+		}
+		nested, err := compositeMembers(m.Type)
+		if err != nil {
+			continue
+		}
+		if offset, found := findMember(nested, name); found != nil {
+			return m.Offset + offset, found
+		}
+	}
+	return 0, nil
+}
+
+func compositeMembers(typ btf.Type) ([]btf.Member, error) {
+	switch t := btf.UnderlyingType(typ).(type) {
+	case *btf.Struct:
+		return t.Members, nil
+	case *btf.Union:
+		return t.Members, nil
+	}
+	return nil, fmt.Errorf("type %s is not a struct or union", typ.TypeName())
+}
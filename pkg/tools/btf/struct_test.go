@@ -0,0 +1,76 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package btf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cilium/ebpf/btf"
+)
+
+func TestStructMemberOffset(t *testing.T) {
+	u16 := &btf.Int{Name: "u16", Size: 2}
+	u32 := &btf.Int{Name: "u32", Size: 4}
+	sockCommon := &btf.Struct{Name: "sock_common", Size: 16, Members: []btf.Member{
+		{Name: "", Type: &btf.Union{Size: 8, Members: []btf.Member{
+			{Name: "skc_addrpair", Type: &btf.Int{Name: "u64", Size: 8}},
+			{Name: "", Type: &btf.Struct{Size: 8, Members: []btf.Member{
+				{Name: "skc_daddr", Type: u32, Offset: 0},
+				{Name: "skc_rcv_saddr", Type: u32, Offset: 32},
+			}}},
+		}}},
+		{Name: "skc_family", Type: u16, Offset: 64},
+		{Name: "skc_state", Type: u16, Offset: 80, BitfieldSize: 4},
+	}}
+	sock := &btf.Struct{Name: "sock", Size: 32, Members: []btf.Member{
+		{Name: "__sk_common", Type: &btf.Typedef{Name: "sock_common_t", Type: sockCommon}, Offset: 0},
+		{Name: "sk_rcvbuf", Type: u32, Offset: 128},
+	}}
+
+	tests := []struct {
+		path     string
+		expected uint32
+		err      bool
+	}{
+		{path: "sk_rcvbuf", expected: 16},
+		{path: "__sk_common.skc_family", expected: 8},
+		{path: "__sk_common.skc_addrpair", expected: 0},
+		{path: "__sk_common.skc_rcv_saddr", expected: 4},
+		{path: "__sk_common.skc_state", err: true},
+		{path: "sk_rcvbuf.value", err: true},
+		{path: "not_exist", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			offset, err := structMemberOffset(sock, strings.Split(tt.path, "."))
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, but got offset: %d", offset)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if offset != tt.expected {
+				t.Errorf("expected: %d, actual: %d", tt.expected, offset)
+			}
+		})
+	}
+}
@@ -211,6 +211,10 @@ func (p *Position) PrevDataID() uint64 {
 	return p.element.Value.(SocketDataBuffer).PrevDataID()
 }
 
+func (p *Position) StartTime() uint64 {
+	return p.element.Value.(SocketDataBuffer).StartTime()
+}
+
 func (p *Position) Seq() int {
 	return p.element.Value.(SocketDataBuffer).DataSequence()
 }
@@ -715,6 +719,15 @@ func (r *Buffer) RemoveReadElements(includeDetails bool) bool {
 	return false
 }
 
+// ResetCurrentPosition move the reading position back to the position, such as the end of the parsed message,
+// so the data read ahead(such as the pipelined message) could be read again
+func (r *Buffer) ResetCurrentPosition(p *Position) {
+	if p == nil || p.element == nil {
+		return
+	}
+	r.current = p.Clone()
+}
+
 // SkipCurrentElement skip current element in reader, if return true means have read finished
 func (r *Buffer) SkipCurrentElement() bool {
 	r.head.element = r.nextElement(r.current.element)
@@ -850,6 +863,39 @@ func (r *Buffer) DeleteExpireEvents(expireDuration time.Duration) int {
 	return count
 }
 
+// RemoveEventsBefore removes the data and detail events which data id is not bigger than the data id,
+// and return the removed detail events
+func (r *Buffer) RemoveEventsBefore(dataID uint64) []SocketDataDetail {
+	r.eventLocker.Lock()
+	defer r.eventLocker.Unlock()
+
+	r.deleteEventsWithJudgement(r.dataEvents, func(element *list.Element) bool {
+		if element.Value == nil {
+			return true
+		}
+		buffer := element.Value.(SocketDataBuffer)
+		if buffer.DataID() > dataID {
+			return false
+		}
+		PooledBuffer.Put(buffer.ReleaseBuffer())
+		return true
+	})
+	details := make([]SocketDataDetail, 0)
+	r.deleteEventsWithJudgement(r.detailEvents, func(element *list.Element) bool {
+		detail, ok := element.Value.(SocketDataDetail)
+		if !ok {
+			return true
+		}
+		if detail.DataID() > dataID {
+			return false
+		}
+		details = append(details, detail)
+		return true
+	})
+	r.shouldResetPosition = true
+	return details
+}
+
 func (r *Buffer) DataLength() int {
 	r.eventLocker.RLock()
 	defer r.eventLocker.RUnlock()
@@ -0,0 +1,253 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capability
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/btf"
+	"github.com/cilium/ebpf/features"
+	"github.com/cilium/ebpf/rlimit"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/tools/operator"
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+)
+
+var log = logger.GetLogger("tools", "capability")
+
+// the kernel capabilities which are probed when rover starting
+const (
+	// KernelBTF the kernel exposes the BTF, otherwise the BTF is located from the BTF hub
+	KernelBTF = "kernel_btf"
+	// Kprobe the kprobe programs
+	Kprobe = "kprobe"
+	// Uprobe the uprobe programs, for the TLS and the language runtimes
+	Uprobe = "uprobe"
+	// Tracepoint the tracepoint programs
+	Tracepoint = "tracepoint"
+	// PerfEvent the perf event programs, for the on CPU profiling
+	PerfEvent = "perf_event"
+	// SchedCLS the traffic control classifier programs
+	SchedCLS = "sched_cls"
+	// RingBuffer the BPF ring buffer, otherwise the perf event array is used
+	RingBuffer = "ring_buffer"
+	// ProbeReadUser the bpf_probe_read_user helper, for reading the user space memory
+	ProbeReadUser = "probe_read_user"
+	// PacketSocket the AF_PACKET socket, for capturing the packets in the user space
+	PacketSocket = "packet_socket"
+)
+
+// the statuses of the modules in the report
+const (
+	StatusActive   = "active"
+	StatusDegraded = "degraded"
+	StatusDisabled = "disabled"
+)
+
+type probe struct {
+	name  string
+	check func() error
+}
+
+var probes = []probe{
+	{name: KernelBTF, check: func() error {
+		_, err := btf.LoadKernelSpec()
+		return err
+	}},
+	{name: Kprobe, check: func() error { return features.HaveProgramType(ebpf.Kprobe) }},
+	{name: Uprobe, check: func() error {
+		if err := features.HaveProgramType(ebpf.Kprobe); err != nil {
+			return err
+		}
+		_, err := os.Stat("/sys/bus/event_source/devices/uprobe")
+		return err
+	}},
+	{name: Tracepoint, check: func() error { return features.HaveProgramType(ebpf.TracePoint) }},
+	{name: PerfEvent, check: func() error { return features.HaveProgramType(ebpf.PerfEvent) }},
+	{name: SchedCLS, check: func() error { return features.HaveProgramType(ebpf.SchedCLS) }},
+	{name: RingBuffer, check: func() error { return features.HaveMapType(ebpf.RingBuf) }},
+	{name: ProbeReadUser, check: func() error { return features.HaveProgramHelper(ebpf.Kprobe, asm.FnProbeReadUser) }},
+	{name: PacketSocket, check: func() error {
+		fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return err
+		}
+		return unix.Close(fd)
+	}},
+}
+
+// Result of probing a capability
+type Result struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ModuleStatus is the running status of a module under the kernel capabilities
+type ModuleStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Missing are the unsupported capabilities which the module required
+	Missing []string `json:"missing,omitempty"`
+	// Degraded are the features of the module disabled by the unsupported capabilities
+	Degraded []string `json:"degraded,omitempty"`
+}
+
+// Report describes which features are degraded in the current node
+type Report struct {
+	Kernel       string          `json:"kernel"`
+	Capabilities []*Result       `json:"capabilities"`
+	Modules      []*ModuleStatus `json:"modules"`
+}
+
+var (
+	results   map[string]*Result
+	probeOnce sync.Once
+
+	degradations    = make(map[string][]string)
+	degradationsMux sync.Mutex
+)
+
+// Probe all capabilities of the running kernel, the probing only happens once
+func Probe() []*Result {
+	probeOnce.Do(func() {
+		// the BPF objects are accounted by the memlock before kernel 5.11
+		if err := rlimit.RemoveMemlock(); err != nil {
+			log.Warnf("remove the memlock limit failure: %v", err)
+		}
+		results = make(map[string]*Result, len(probes))
+		for _, p := range probes {
+			result := &Result{Name: p.name, Supported: true}
+			if err := p.check(); err != nil {
+				result.Supported, result.Reason = false, err.Error()
+			}
+			results[p.name] = result
+		}
+	})
+	list := make([]*Result, 0, len(probes))
+	for _, p := range probes {
+		list = append(list, results[p.name])
+	}
+	return list
+}
+
+// Supported returns true when the running kernel supports the capability
+func Supported(name string) bool {
+	Probe()
+	result := results[name]
+	return result != nil && result.Supported
+}
+
+// Unsupported returns the capabilities not supported by the running kernel
+func Unsupported(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if !Supported(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Degrade records the feature of the module is disabled since the capability is not supported
+func Degrade(module, feature, capability string) {
+	log.Warnf("the %s of module %s is disabled, the kernel capability %s is not supported", feature, module, capability)
+	degradationsMux.Lock()
+	defer degradationsMux.Unlock()
+	degradations[module] = append(degradations[module], fmt.Sprintf("%s(%s)", feature, capability))
+}
+
+// BuildReport merges the probed capabilities, the module statuses decided when starting,
+// and the degraded features recorded by the modules
+func BuildReport(modules []*ModuleStatus) *Report {
+	report := &Report{Capabilities: Probe(), Modules: modules}
+	if uname, err := operator.GetOSUname(); err == nil {
+		report.Kernel = uname.Release
+	}
+	degradationsMux.Lock()
+	defer degradationsMux.Unlock()
+	for _, m := range modules {
+		if degraded := degradations[m.Name]; len(degraded) > 0 {
+			m.Degraded = degraded
+			if m.Status == StatusActive {
+				m.Status = StatusDegraded
+			}
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+	return report
+}
+
+// Publish the report through the log, and the meters of the rover service
+func (r *Report) Publish() {
+	var unsupported, degraded []string
+	for _, c := range r.Capabilities {
+		supported := 0.0
+		if c.Supported {
+			supported = 1
+		} else {
+			unsupported = append(unsupported, c.Name)
+		}
+		selfobs.RegisterGauge(selfobs.KernelCapability, "capability", c.Name, func() float64 {
+			return supported
+		})
+	}
+	for _, m := range r.Modules {
+		status := moduleStatusValue(m.Status)
+		if m.Status != StatusActive {
+			degraded = append(degraded, m.Name)
+		}
+		selfobs.RegisterGauge(selfobs.ModuleStatus, "module", m.Name, func() float64 {
+			return status
+		})
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		log.Warnf("marshal the capability report failure: %v", err)
+		return
+	}
+	if len(unsupported) == 0 && len(degraded) == 0 {
+		log.Infof("all kernel capabilities are supported, capability report: %s", data)
+		return
+	}
+	log.Warnf("unsupported kernel capabilities: [%s], degraded modules: [%s], capability report: %s",
+		strings.Join(unsupported, ", "), strings.Join(degraded, ", "), data)
+}
+
+func moduleStatusValue(status string) float64 {
+	switch status {
+	case StatusDegraded:
+		return 1
+	case StatusDisabled:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,56 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capability
+
+import "testing"
+
+func TestBuildReport(t *testing.T) {
+	Degrade("test_degraded", "TLS plaintext capture", Uprobe)
+	Degrade("test_disabled", "TLS plaintext capture", Uprobe)
+
+	tests := []struct {
+		name     string
+		status   string
+		expected string
+		degraded int
+	}{
+		{name: "test_active", status: StatusActive, expected: StatusActive},
+		{name: "test_degraded", status: StatusActive, expected: StatusDegraded, degraded: 1},
+		{name: "test_disabled", status: StatusDisabled, expected: StatusDisabled, degraded: 1},
+	}
+
+	modules := make([]*ModuleStatus, 0, len(tests))
+	for i := len(tests) - 1; i >= 0; i-- {
+		modules = append(modules, &ModuleStatus{Name: tests[i].name, Status: tests[i].status})
+	}
+	report := BuildReport(modules)
+	if len(report.Capabilities) != len(probes) {
+		t.Fatalf("expected %d capabilities, actual %d", len(probes), len(report.Capabilities))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := report.Modules[i]
+			if actual.Name != tt.name {
+				t.Fatalf("the modules should be sorted, expected %s, actual %s", tt.name, actual.Name)
+			}
+			if actual.Status != tt.expected || len(actual.Degraded) != tt.degraded {
+				t.Fatalf("expected status %s with %d degraded, actual %s with %v", tt.expected, tt.degraded, actual.Status, actual.Degraded)
+			}
+		})
+	}
+}
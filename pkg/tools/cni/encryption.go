@@ -0,0 +1,164 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cni
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+)
+
+var log = logger.GetLogger("tools", "cni")
+
+// EncryptionType is how the traffic is encrypted by the CNI
+type EncryptionType string
+
+const (
+	EncryptionNone      EncryptionType = "none"
+	EncryptionWireGuard EncryptionType = "wireguard"
+	EncryptionIPsec     EncryptionType = "ipsec"
+)
+
+const sysClassNet = "/sys/class/net"
+
+// EncryptedRoute is the destination network which the traffic is encrypted by the CNI
+type EncryptedRoute struct {
+	Network  *net.IPNet
+	Type     EncryptionType
+	Provider string
+	Device   string
+}
+
+// EncryptionTable is the encrypted routes in the current network namespace
+type EncryptionTable struct {
+	routes []*EncryptedRoute
+}
+
+// Match find the most specific encrypted route of the destination address, nil if not encrypted
+func (t *EncryptionTable) Match(address string) *EncryptedRoute {
+	if t == nil {
+		return nil
+	}
+	parsed := net.ParseIP(address)
+	if parsed == nil {
+		return nil
+	}
+	var result *EncryptedRoute
+	resultPrefix := -1
+	for _, route := range t.routes {
+		if !route.Network.Contains(parsed) {
+			continue
+		}
+		if prefix, _ := route.Network.Mask.Size(); prefix > resultPrefix {
+			result, resultPrefix = route, prefix
+		}
+	}
+	return result
+}
+
+// Types is all the encryption types in the table
+func (t *EncryptionTable) Types() []EncryptionType {
+	result := make([]EncryptionType, 0)
+	exists := make(map[EncryptionType]bool)
+	for _, route := range t.routes {
+		if !exists[route.Type] {
+			exists[route.Type] = true
+			result = append(result, route.Type)
+		}
+	}
+	return result
+}
+
+// DetectEncryption reading the WireGuard peers and the IPsec policies to build the encrypted routes,
+// the partial routes are returned even some of them detect failure
+func DetectEncryption() (*EncryptionTable, error) {
+	table := &EncryptionTable{}
+	var err error
+	devices, e := wireGuardDevices(sysClassNet)
+	if e != nil {
+		err = multierror.Append(err, e)
+	}
+	if len(devices) > 0 {
+		routes, e := readWireGuardRoutes(devices)
+		if e != nil {
+			err = multierror.Append(err, e)
+		}
+		table.routes = append(table.routes, routes...)
+	}
+	routes, e := readIPsecRoutes(ipsecProvider(sysClassNet))
+	if e != nil {
+		err = multierror.Append(err, e)
+	}
+	table.routes = append(table.routes, routes...)
+	return table, err
+}
+
+// wireGuardDevices find all the WireGuard devices through the device type
+func wireGuardDevices(sysNet string) ([]string, error) {
+	entries, err := os.ReadDir(sysNet)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, entry := range entries {
+		uevent, err := os.ReadFile(filepath.Join(sysNet, entry.Name(), "uevent"))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(uevent), "\n") {
+			if line == "DEVTYPE=wireguard" {
+				result = append(result, entry.Name())
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// wireGuardProvider detect which CNI created the WireGuard device through the device name
+func wireGuardProvider(device string) string {
+	switch {
+	case strings.HasPrefix(device, "cilium_"):
+		return "cilium"
+	case strings.HasSuffix(device, ".cali"):
+		return "calico"
+	}
+	return ""
+}
+
+// ipsecProvider detect which CNI is used in the node through the devices created by the CNI
+func ipsecProvider(sysNet string) string {
+	entries, err := os.ReadDir(sysNet)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		switch name := entry.Name(); {
+		case strings.HasPrefix(name, "cilium_"):
+			return "cilium"
+		case strings.HasSuffix(name, ".calico") || strings.HasPrefix(name, "cali"):
+			return "calico"
+		}
+	}
+	return ""
+}
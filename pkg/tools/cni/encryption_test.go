@@ -0,0 +1,162 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cni
+
+import (
+	"net"
+	"testing"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestEncryptionTableMatch(t *testing.T) {
+	_, podCIDR, _ := net.ParseCIDR("10.0.0.0/16")
+	_, nodeCIDR, _ := net.ParseCIDR("10.0.1.0/24")
+	_, v6CIDR, _ := net.ParseCIDR("fd00::/64")
+	table := &EncryptionTable{routes: []*EncryptedRoute{
+		{Network: podCIDR, Type: EncryptionIPsec},
+		{Network: nodeCIDR, Type: EncryptionWireGuard},
+		{Network: v6CIDR, Type: EncryptionWireGuard},
+	}}
+	tests := []struct {
+		address  string
+		expected EncryptionType
+	}{
+		{address: "10.0.1.5", expected: EncryptionWireGuard},
+		{address: "10.0.2.5", expected: EncryptionIPsec},
+		{address: "fd00::1", expected: EncryptionWireGuard},
+		{address: "8.8.8.8", expected: EncryptionNone},
+		{address: "invalid", expected: EncryptionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			actual := EncryptionNone
+			if route := table.Match(tt.address); route != nil {
+				actual = route.Type
+			}
+			if actual != tt.expected {
+				t.Errorf("expected: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWireGuardProvider(t *testing.T) {
+	tests := []struct {
+		device   string
+		expected string
+	}{
+		{device: "cilium_wg0", expected: "cilium"},
+		{device: "wireguard.cali", expected: "calico"},
+		{device: "wg-v6.cali", expected: "calico"},
+		{device: "wg0", expected: ""},
+	}
+	for _, tt := range tests {
+		if actual := wireGuardProvider(tt.device); actual != tt.expected {
+			t.Errorf("device: %s, expected: %s, actual: %s", tt.device, tt.expected, actual)
+		}
+	}
+}
+
+func TestParseXFRMOutPolicy(t *testing.T) {
+	buildPolicy := func(family uint16, address net.IP, prefix, direction, action uint8) []byte {
+		data := make([]byte, xfrmPolicyInfoLength)
+		copy(data[xfrmSelectorDestAddress:], address)
+		nlenc.PutUint16(data[xfrmSelectorFamily:xfrmSelectorFamily+2], family)
+		data[xfrmSelectorDestPrefix] = prefix
+		data[xfrmPolicyDirection] = direction
+		data[xfrmPolicyAction] = action
+		return data
+	}
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "ipv4 out", data: buildPolicy(unix.AF_INET, net.ParseIP("10.0.1.0").To4(), 24, xfrmPolicyDirectionOut, 0),
+			expected: "10.0.1.0/24"},
+		{name: "ipv6 out", data: buildPolicy(unix.AF_INET6, net.ParseIP("fd00::"), 64, xfrmPolicyDirectionOut, 0),
+			expected: "fd00::/64"},
+		{name: "inbound", data: buildPolicy(unix.AF_INET, net.ParseIP("10.0.1.0").To4(), 24, 0, 0)},
+		{name: "block", data: buildPolicy(unix.AF_INET, net.ParseIP("10.0.1.0").To4(), 24, xfrmPolicyDirectionOut, 1)},
+		{name: "match all", data: buildPolicy(unix.AF_INET, net.ParseIP("0.0.0.0").To4(), 0, xfrmPolicyDirectionOut, 0)},
+		{name: "too short", data: make([]byte, 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := ""
+			if n := parseXFRMOutPolicy(tt.data); n != nil {
+				actual = n.String()
+			}
+			if actual != tt.expected {
+				t.Errorf("expected: %s, actual: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseWireGuardAllowedIPs(t *testing.T) {
+	allowedIP := func(family uint16, address net.IP, mask uint8) func(*netlink.AttributeEncoder) error {
+		return func(e *netlink.AttributeEncoder) error {
+			e.Uint16(wireGuardAllowFamily, family)
+			e.Bytes(wireGuardAllowAddress, address)
+			e.Uint8(wireGuardAllowMask, mask)
+			return nil
+		}
+	}
+	encoder := netlink.NewAttributeEncoder()
+	encoder.String(wireGuardDeviceName, "cilium_wg0")
+	encoder.Nested(wireGuardDevicePeers, func(peers *netlink.AttributeEncoder) error {
+		peers.Nested(0, func(peer *netlink.AttributeEncoder) error {
+			peer.Nested(wireGuardPeerAllowIPs, func(ips *netlink.AttributeEncoder) error {
+				ips.Nested(0, allowedIP(unix.AF_INET, net.ParseIP("10.0.1.0").To4(), 24))
+				ips.Nested(0, allowedIP(unix.AF_INET, net.ParseIP("192.168.0.2").To4(), 32))
+				return nil
+			})
+			return nil
+		})
+		peers.Nested(0, func(peer *netlink.AttributeEncoder) error {
+			peer.Nested(wireGuardPeerAllowIPs, func(ips *netlink.AttributeEncoder) error {
+				ips.Nested(0, allowedIP(unix.AF_INET6, net.ParseIP("fd00:1::"), 64))
+				return nil
+			})
+			return nil
+		})
+		return nil
+	})
+	data, err := encoder.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks, err := parseWireGuardAllowedIPs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"10.0.1.0/24", "192.168.0.2/32", "fd00:1::/64"}
+	if len(networks) != len(expected) {
+		t.Fatalf("expected %d networks, actual: %v", len(expected), networks)
+	}
+	for i, n := range networks {
+		if n.String() != expected[i] {
+			t.Errorf("expected: %s, actual: %s", expected[i], n.String())
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cni
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/mdlayher/netlink"
+
+	"golang.org/x/sys/unix"
+)
+
+// the generic netlink and WireGuard netlink API constants, defined in the linux/genetlink.h and linux/wireguard.h
+const (
+	genlHeaderLength      = 4
+	genlCtrlGetFamily     = 3
+	genlCtrlAttrFamilyID  = 1
+	genlCtrlAttrName      = 2
+	wireGuardGenlName     = "wireguard"
+	wireGuardGenlVersion  = 1
+	wireGuardGetDevice    = 0
+	wireGuardDeviceName   = 2
+	wireGuardDevicePeers  = 8
+	wireGuardPeerAllowIPs = 9
+	wireGuardAllowFamily  = 1
+	wireGuardAllowAddress = 2
+	wireGuardAllowMask    = 3
+)
+
+// readWireGuardRoutes reading the allowed IPs of all peers in the WireGuard devices,
+// the traffic to these addresses is encrypted through the tunnel
+func readWireGuardRoutes(devices []string) ([]*EncryptedRoute, error) {
+	conn, err := netlink.Dial(unix.NETLINK_GENERIC, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial the generic netlink failure: %v", err)
+	}
+	defer conn.Close()
+	family, err := genlFamilyID(conn, wireGuardGenlName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*EncryptedRoute, 0)
+	for _, device := range devices {
+		encoder := netlink.NewAttributeEncoder()
+		encoder.String(wireGuardDeviceName, device)
+		messages, err := genlExecute(conn, family, wireGuardGetDevice, wireGuardGenlVersion, encoder, netlink.Dump)
+		if err != nil {
+			return result, fmt.Errorf("query the WireGuard device %s failure: %v", device, err)
+		}
+		for _, data := range messages {
+			networks, err := parseWireGuardAllowedIPs(data)
+			if err != nil {
+				return result, fmt.Errorf("parse the WireGuard device %s failure: %v", device, err)
+			}
+			for _, n := range networks {
+				result = append(result, &EncryptedRoute{Network: n, Type: EncryptionWireGuard, Provider: wireGuardProvider(device), Device: device})
+			}
+		}
+	}
+	return result, nil
+}
+
+// genlFamilyID resolve the family ID of the generic netlink through the family name
+func genlFamilyID(conn *netlink.Conn, name string) (netlink.HeaderType, error) {
+	encoder := netlink.NewAttributeEncoder()
+	encoder.String(genlCtrlAttrName, name)
+	messages, err := genlExecute(conn, unix.GENL_ID_CTRL, genlCtrlGetFamily, 1, encoder, 0)
+	if err != nil {
+		return 0, fmt.Errorf("resolve the generic netlink family %s failure: %v", name, err)
+	}
+	for _, data := range messages {
+		decoder, err := netlink.NewAttributeDecoder(data)
+		if err != nil {
+			return 0, err
+		}
+		for decoder.Next() {
+			if decoder.Type() == genlCtrlAttrFamilyID {
+				return netlink.HeaderType(decoder.Uint16()), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("the generic netlink family %s not found", name)
+}
+
+// genlExecute send the generic netlink request and return the attributes data of the responses
+func genlExecute(conn *netlink.Conn, family netlink.HeaderType, command, version uint8,
+	encoder *netlink.AttributeEncoder, flags netlink.HeaderFlags) ([][]byte, error) {
+	attrs, err := encoder.Encode()
+	if err != nil {
+		return nil, err
+	}
+	data := append([]byte{command, version, 0, 0}, attrs...)
+	messages, err := conn.Execute(netlink.Message{
+		Header: netlink.Header{Type: family, Flags: netlink.Request | flags},
+		Data:   data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]byte, 0, len(messages))
+	for _, m := range messages {
+		if len(m.Data) < genlHeaderLength {
+			continue
+		}
+		result = append(result, m.Data[genlHeaderLength:])
+	}
+	return result, nil
+}
+
+// parseWireGuardAllowedIPs parse the allowed IPs of all peers from the WireGuard device attributes
+func parseWireGuardAllowedIPs(data []byte) ([]*net.IPNet, error) {
+	decoder, err := netlink.NewAttributeDecoder(data)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*net.IPNet, 0)
+	for decoder.Next() {
+		if decoder.Type() != wireGuardDevicePeers {
+			continue
+		}
+		decoder.Nested(func(peers *netlink.AttributeDecoder) error {
+			for peers.Next() {
+				peers.Nested(func(peer *netlink.AttributeDecoder) error {
+					for peer.Next() {
+						if peer.Type() != wireGuardPeerAllowIPs {
+							continue
+						}
+						peer.Nested(func(allowedIPs *netlink.AttributeDecoder) error {
+							for allowedIPs.Next() {
+								allowedIPs.Nested(func(allowed *netlink.AttributeDecoder) error {
+									if n := parseWireGuardAllowedIP(allowed); n != nil {
+										result = append(result, n)
+									}
+									return nil
+								})
+							}
+							return nil
+						})
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+	}
+	return result, decoder.Err()
+}
+
+func parseWireGuardAllowedIP(decoder *netlink.AttributeDecoder) *net.IPNet {
+	var family uint16
+	var address []byte
+	var mask uint8
+	for decoder.Next() {
+		switch decoder.Type() {
+		case wireGuardAllowFamily:
+			family = decoder.Uint16()
+		case wireGuardAllowAddress:
+			address = decoder.Bytes()
+		case wireGuardAllowMask:
+			mask = decoder.Uint8()
+		}
+	}
+	return buildIPNet(family, address, mask)
+}
+
+func buildIPNet(family uint16, address []byte, prefix uint8) *net.IPNet {
+	var bits int
+	switch family {
+	case unix.AF_INET:
+		bits = net.IPv4len * 8
+		if len(address) < net.IPv4len {
+			return nil
+		}
+		address = address[:net.IPv4len]
+	case unix.AF_INET6:
+		bits = net.IPv6len * 8
+		if len(address) < net.IPv6len {
+			return nil
+		}
+		address = address[:net.IPv6len]
+	default:
+		return nil
+	}
+	if int(prefix) > bits {
+		return nil
+	}
+	mask := net.CIDRMask(int(prefix), bits)
+	return &net.IPNet{IP: net.IP(address).Mask(mask), Mask: mask}
+}
@@ -0,0 +1,90 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cni
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+
+	"golang.org/x/sys/unix"
+)
+
+// the offsets of the struct xfrm_userpolicy_info, defined in the linux/xfrm.h
+const (
+	xfrmSelectorDestAddress  = 0
+	xfrmSelectorFamily       = 40
+	xfrmSelectorDestPrefix   = 42
+	xfrmPolicyDirection      = 160
+	xfrmPolicyAction         = 161
+	xfrmPolicyInfoLength     = 168
+	xfrmPolicyDirectionOut   = 1
+	xfrmPolicyActionAllow    = 0
+	xfrmAddressLength        = 16
+	xfrmMessageGetPolicy     = 0x15
+	xfrmMessageNewPolicy     = 0x13
+	xfrmPolicyDestIPv4Length = 4
+)
+
+// readIPsecRoutes reading the outbound IPsec policies, the traffic to the selector destination is encrypted
+func readIPsecRoutes(provider string) ([]*EncryptedRoute, error) {
+	conn, err := netlink.Dial(unix.NETLINK_XFRM, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial the xfrm netlink failure: %v", err)
+	}
+	defer conn.Close()
+	messages, err := conn.Execute(netlink.Message{
+		Header: netlink.Header{Type: xfrmMessageGetPolicy, Flags: netlink.Request | netlink.Dump},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query the xfrm policies failure: %v", err)
+	}
+	result := make([]*EncryptedRoute, 0)
+	for _, m := range messages {
+		if m.Header.Type != xfrmMessageNewPolicy {
+			continue
+		}
+		if n := parseXFRMOutPolicy(m.Data); n != nil {
+			result = append(result, &EncryptedRoute{Network: n, Type: EncryptionIPsec, Provider: provider})
+		}
+	}
+	return result, nil
+}
+
+// parseXFRMOutPolicy parse the destination network of the outbound allowed policy,
+// the policy which matches all destinations is ignored, because it's not created for the pod traffic
+func parseXFRMOutPolicy(data []byte) *net.IPNet {
+	if len(data) < xfrmPolicyInfoLength {
+		return nil
+	}
+	if data[xfrmPolicyDirection] != xfrmPolicyDirectionOut || data[xfrmPolicyAction] != xfrmPolicyActionAllow {
+		return nil
+	}
+	prefix := data[xfrmSelectorDestPrefix]
+	if prefix == 0 {
+		return nil
+	}
+	family := nlenc.Uint16(data[xfrmSelectorFamily : xfrmSelectorFamily+2])
+	address := data[xfrmSelectorDestAddress : xfrmSelectorDestAddress+xfrmAddressLength]
+	if family == unix.AF_INET {
+		address = address[:xfrmPolicyDestIPv4Length]
+	}
+	return buildIPNet(family, address, prefix)
+}
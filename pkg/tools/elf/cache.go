@@ -0,0 +1,183 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elf
+
+import (
+	"container/list"
+	"debug/elf"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+)
+
+// DefaultSymbolCacheSize is the default max count of symbols in the node-level symbol cache
+const DefaultSymbolCacheSize = 1000000
+
+var (
+	symbolCache = newSymbolLRU(DefaultSymbolCacheSize)
+
+	log = logger.GetLogger("tools", "elf")
+)
+
+// SetSymbolCacheSize update the max count of symbols in the node-level symbol cache, 0 means disable the cache
+func SetSymbolCacheSize(size int) {
+	symbolCache.resize(size)
+}
+
+// LoadSymbols getting the symbols from the node-level cache, or load and cache them when not exists,
+// the returned symbols is shared, so the caller must not modify them
+func LoadSymbols(key string, loader func() ([]*Symbol, error)) ([]*Symbol, error) {
+	return symbolCache.load(key, loader)
+}
+
+// ReadSymbols read all symbols sorted by the location in the ELF file through the node-level cache,
+// including the separate debug file or the Go pclntab when the binary is stripped.
+// The cache is keyed by the build-id, so the same binary in the different processes or tasks only been analyzed once
+func ReadSymbols(path string, f *elf.File) ([]*Symbol, error) {
+	return LoadSymbols(symbolCacheKey(path, f), func() ([]*Symbol, error) {
+		return readAllSymbols(path, f), nil
+	})
+}
+
+func symbolCacheKey(path string, f *elf.File) string {
+	stripped := IsStripped(f)
+	if buildID := ReadBuildID(f); buildID != "" {
+		return fmt.Sprintf("build-id:%s:%t", buildID, stripped)
+	}
+	// the file without build-id only could be identified by the path and modify time
+	if stat, err := os.Stat(path); err == nil {
+		return fmt.Sprintf("file:%s:%d:%d", path, stat.Size(), stat.ModTime().UnixNano())
+	}
+	return ""
+}
+
+func readAllSymbols(path string, f *elf.File) []*Symbol {
+	symbols, _ := f.Symbols()
+	dynamicSymbols, _ := f.DynamicSymbols()
+	symbols = append(symbols, dynamicSymbols...)
+
+	result := make([]*Symbol, 0, len(symbols))
+	for _, s := range symbols {
+		result = append(result, &Symbol{Name: s.Name, Location: s.Value, Size: s.Size})
+	}
+	if IsStripped(f) {
+		result = append(result, readStrippedSymbols(path, f)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Location < result[j].Location
+	})
+	return result
+}
+
+// readStrippedSymbols read the symbols from the separate debug file when the binary is stripped,
+// or fall back to the Go pclntab when the binary is a Go program
+func readStrippedSymbols(path string, f *elf.File) []*Symbol {
+	debugPath := FindDebugFile(path, f)
+	if debugPath == "" {
+		return ReadGoPCLNTabSymbols(path, f)
+	}
+	debugFile, err := elf.Open(debugPath)
+	if err != nil {
+		log.Debugf("could not open the debug file: %s of %s, error: %v", debugPath, path, err)
+		return ReadGoPCLNTabSymbols(path, f)
+	}
+	defer debugFile.Close()
+	symbols, _ := debugFile.Symbols()
+	result := make([]*Symbol, 0, len(symbols))
+	for _, s := range symbols {
+		result = append(result, &Symbol{Name: s.Name, Location: s.Value, Size: s.Size})
+	}
+	return result
+}
+
+// symbolLRU is the least recently used cache bounded by the total count of symbols
+type symbolLRU struct {
+	lock     sync.Mutex
+	maxSize  int
+	curSize  int
+	elements map[string]*list.Element
+	order    *list.List
+}
+
+type symbolEntry struct {
+	key     string
+	symbols []*Symbol
+}
+
+func newSymbolLRU(maxSize int) *symbolLRU {
+	return &symbolLRU{
+		maxSize:  maxSize,
+		elements: make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *symbolLRU) load(key string, loader func() ([]*Symbol, error)) ([]*Symbol, error) {
+	if key == "" {
+		return loader()
+	}
+	c.lock.Lock()
+	if e, ok := c.elements[key]; ok {
+		c.order.MoveToFront(e)
+		c.lock.Unlock()
+		return e.Value.(*symbolEntry).symbols, nil
+	}
+	c.lock.Unlock()
+
+	// loading without the lock, the symbols analyze could be slow
+	symbols, err := loader()
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.elements[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*symbolEntry).symbols, nil
+	}
+	if len(symbols) > c.maxSize {
+		return symbols, nil
+	}
+	c.elements[key] = c.order.PushFront(&symbolEntry{key: key, symbols: symbols})
+	c.curSize += len(symbols)
+	c.evict()
+	return symbols, nil
+}
+
+func (c *symbolLRU) resize(maxSize int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxSize = maxSize
+	c.evict()
+}
+
+func (c *symbolLRU) evict() {
+	for c.curSize > c.maxSize {
+		e := c.order.Back()
+		if e == nil {
+			return
+		}
+		entry := c.order.Remove(e).(*symbolEntry)
+		delete(c.elements, entry.key)
+		c.curSize -= len(entry.symbols)
+	}
+}
@@ -0,0 +1,94 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSymbolLRU(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int
+		loads   []string
+		sizes   map[string]int
+		cached  []string
+		evicted []string
+	}{
+		{
+			name:    "evict least recently used",
+			maxSize: 5,
+			loads:   []string{"a", "b", "a", "c"},
+			sizes:   map[string]int{"a": 2, "b": 2, "c": 2},
+			cached:  []string{"a", "c"},
+			evicted: []string{"b"},
+		},
+		{
+			name:    "too large to cache",
+			maxSize: 3,
+			loads:   []string{"a", "b"},
+			sizes:   map[string]int{"a": 2, "b": 4},
+			cached:  []string{"a"},
+			evicted: []string{"b"},
+		},
+		{
+			name:    "disabled",
+			maxSize: 0,
+			loads:   []string{"a"},
+			sizes:   map[string]int{"a": 1},
+			evicted: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newSymbolLRU(tt.maxSize)
+			for _, key := range tt.loads {
+				if _, err := cache.load(key, func() ([]*Symbol, error) {
+					return make([]*Symbol, tt.sizes[key]), nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, key := range tt.cached {
+				if _, ok := cache.elements[key]; !ok {
+					t.Errorf("the key %s should be cached", key)
+				}
+			}
+			for _, key := range tt.evicted {
+				if _, ok := cache.elements[key]; ok {
+					t.Errorf("the key %s should be evicted", key)
+				}
+			}
+			if cache.curSize > tt.maxSize {
+				t.Errorf("the cache size %d is larger than the max size %d", cache.curSize, tt.maxSize)
+			}
+		})
+	}
+
+	// the loader error should not be cached
+	cache := newSymbolLRU(10)
+	if _, err := cache.load("err", func() ([]*Symbol, error) {
+		return nil, fmt.Errorf("load failure")
+	}); err == nil {
+		t.Fatalf("should return the loader error")
+	}
+	if _, ok := cache.elements["err"]; ok {
+		t.Fatalf("the failure result should not be cached")
+	}
+}
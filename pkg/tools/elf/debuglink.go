@@ -0,0 +1,147 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elf
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/path"
+)
+
+// DebugFileDirectory is the global directory to storage the separate debug files
+var DebugFileDirectory = "/usr/lib/debug"
+
+// IsStripped check the elf file have been removed the symbol table
+func IsStripped(f *elf.File) bool {
+	return f.Section(".symtab") == nil
+}
+
+// FindDebugFile to searching the separate debug file of the binary, the binary path could under the process root
+// such as "/proc/{pid}/root/usr/bin/app", so the debug file would be searched in the same root filesystem.
+// Searching by the build-id first, then the ".gnu_debuglink" section, return empty string if not found.
+func FindDebugFile(binaryPath string, f *elf.File) string {
+	return searchDebugFile(binaryPath, ReadBuildID(f), readDebugLink(f))
+}
+
+// debugLink is the debug file name and the CRC of it in the ".gnu_debuglink" section
+type debugLink struct {
+	name string
+	crc  uint32
+}
+
+func searchDebugFile(binaryPath, buildID string, link *debugLink) string {
+	root, subPath := host.SplitProcessRootPath(binaryPath)
+	if len(buildID) > 2 {
+		debugPath := filepath.Join(root, DebugFileDirectory, ".build-id", buildID[:2], buildID[2:]+".debug")
+		if path.Exists(debugPath) {
+			return debugPath
+		}
+	}
+
+	if link == nil {
+		return ""
+	}
+	dir := filepath.Dir(subPath)
+	for _, candidate := range []string{
+		filepath.Join(dir, link.name),
+		filepath.Join(dir, ".debug", link.name),
+		filepath.Join(DebugFileDirectory, dir, link.name),
+	} {
+		debugPath := filepath.Join(root, candidate)
+		if debugPath == binaryPath || !path.Exists(debugPath) {
+			continue
+		}
+		if fileCRC, err := calculateCRC(debugPath); err == nil && fileCRC == link.crc {
+			return debugPath
+		}
+	}
+	return ""
+}
+
+// ReadBuildID read the GNU build-id from the ".note.gnu.build-id" section, return empty string if not exists
+func ReadBuildID(f *elf.File) string {
+	section := f.Section(".note.gnu.build-id")
+	if section == nil {
+		return ""
+	}
+	data, err := section.Data()
+	if err != nil {
+		return ""
+	}
+	return parseBuildID(data, f.ByteOrder)
+}
+
+func parseBuildID(data []byte, order binary.ByteOrder) string {
+	if len(data) < 16 {
+		return ""
+	}
+	nameSize := order.Uint32(data[0:4])
+	descSize := order.Uint32(data[4:8])
+	noteType := order.Uint32(data[8:12])
+	descOffset := 12 + (nameSize+3)&^3
+	if noteType != 3 || uint32(len(data)) < descOffset+descSize {
+		return ""
+	}
+	return hex.EncodeToString(data[descOffset : descOffset+descSize])
+}
+
+func readDebugLink(f *elf.File) *debugLink {
+	section := f.Section(".gnu_debuglink")
+	if section == nil {
+		return nil
+	}
+	data, err := section.Data()
+	if err != nil {
+		return nil
+	}
+	return parseDebugLink(data, f.ByteOrder)
+}
+
+func parseDebugLink(data []byte, order binary.ByteOrder) *debugLink {
+	end := bytes.IndexByte(data, 0)
+	if end <= 0 {
+		return nil
+	}
+	// the crc is aligned to four bytes after the file name
+	crcOffset := (end + 4) &^ 3
+	if len(data) < crcOffset+4 {
+		return nil
+	}
+	return &debugLink{name: string(data[:end]), crc: order.Uint32(data[crcOffset : crcOffset+4])}
+}
+
+func calculateCRC(p string) (uint32, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	hash := crc32.NewIEEE()
+	if _, err := io.Copy(hash, file); err != nil {
+		return 0, err
+	}
+	return hash.Sum32(), nil
+}
@@ -0,0 +1,134 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elf
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBuildID(t *testing.T) {
+	note := func(noteType uint32, name string, desc []byte) []byte {
+		data := make([]byte, 12)
+		binary.LittleEndian.PutUint32(data[0:], uint32(len(name)+1))
+		binary.LittleEndian.PutUint32(data[4:], uint32(len(desc)))
+		binary.LittleEndian.PutUint32(data[8:], noteType)
+		nameData := make([]byte, (len(name)+1+3)&^3)
+		copy(nameData, name)
+		return append(append(data, nameData...), desc...)
+	}
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "gnu build-id", data: note(3, "GNU", []byte{0xab, 0xcd, 0xef, 0x01, 0x23}), expected: "abcdef0123"},
+		{name: "other note type", data: note(1, "GNU", []byte{0xab, 0xcd, 0xef, 0x01, 0x23})},
+		{name: "truncated desc", data: note(3, "GNU", []byte{0xab, 0xcd, 0xef, 0x01, 0x23})[:18]},
+		{name: "too short", data: []byte{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := parseBuildID(tt.data, binary.LittleEndian); actual != tt.expected {
+				t.Errorf("expected build-id: %q, actual: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseDebugLink(t *testing.T) {
+	link := func(name string, crc uint32) []byte {
+		data := make([]byte, (len(name)+4)&^3+4)
+		copy(data, name)
+		binary.LittleEndian.PutUint32(data[len(data)-4:], crc)
+		return data
+	}
+	tests := []struct {
+		name     string
+		data     []byte
+		expected *debugLink
+	}{
+		{name: "aligned name", data: link("app.debug", 0x12345678), expected: &debugLink{name: "app.debug", crc: 0x12345678}},
+		{name: "name with padding", data: link("a.dbg", 0x1), expected: &debugLink{name: "a.dbg", crc: 0x1}},
+		{name: "empty name", data: link("", 0x1)},
+		{name: "missing crc", data: link("app.debug", 0x1)[:10]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := parseDebugLink(tt.data, binary.LittleEndian)
+			if (actual == nil) != (tt.expected == nil) || (actual != nil && *actual != *tt.expected) {
+				t.Errorf("expected debug link: %v, actual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSearchDebugFile(t *testing.T) {
+	dir := t.TempDir()
+	debugDir := filepath.Join(dir, "debug")
+	defer func(original string) {
+		DebugFileDirectory = original
+	}(DebugFileDirectory)
+	DebugFileDirectory = debugDir
+
+	binaryPath := filepath.Join(dir, "bin", "app")
+	content := []byte("debug symbols")
+	crc := crc32.ChecksumIEEE(content)
+	files := map[string][]byte{
+		binaryPath: []byte("binary"),
+		filepath.Join(debugDir, ".build-id", "ab", "cdef.debug"): content,
+		filepath.Join(dir, "bin", ".debug", "app.debug"):         content,
+		filepath.Join(dir, "bin", "mismatch.debug"):              []byte("other"),
+		filepath.Join(debugDir, dir, "bin", "global.debug"):      content,
+	}
+	for p, data := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		buildID  string
+		link     *debugLink
+		expected string
+	}{
+		{name: "by build-id", buildID: "abcdef", link: &debugLink{name: "app.debug", crc: crc},
+			expected: filepath.Join(debugDir, ".build-id", "ab", "cdef.debug")},
+		{name: "by debug link in the .debug directory", buildID: "0000", link: &debugLink{name: "app.debug", crc: crc},
+			expected: filepath.Join(dir, "bin", ".debug", "app.debug")},
+		{name: "by debug link in the global directory", link: &debugLink{name: "global.debug", crc: crc},
+			expected: filepath.Join(debugDir, dir, "bin", "global.debug")},
+		{name: "crc mismatch", link: &debugLink{name: "mismatch.debug", crc: crc}},
+		{name: "link to the binary itself", link: &debugLink{name: "app", crc: crc32.ChecksumIEEE([]byte("binary"))}},
+		{name: "nothing found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := searchDebugFile(binaryPath, tt.buildID, tt.link); actual != tt.expected {
+				t.Errorf("expected debug file: %q, actual: %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -26,6 +26,8 @@ import (
 type File struct {
 	Path     string
 	realFile *elf.File
+	// debugFile is the separate debug file when the binary is stripped
+	debugFile *elf.File
 }
 
 type Symbol struct {
@@ -39,13 +41,22 @@ func NewFile(path string) (*File, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &File{
+	file := &File{
 		Path:     path,
 		realFile: f,
-	}, nil
+	}
+	if IsStripped(f) {
+		if debugPath := FindDebugFile(path, f); debugPath != "" {
+			file.debugFile, _ = elf.Open(debugPath)
+		}
+	}
+	return file, nil
 }
 
 func (f *File) Close() error {
+	if f.debugFile != nil {
+		_ = f.debugFile.Close()
+	}
 	return f.realFile.Close()
 }
 
@@ -60,21 +71,11 @@ func (f *File) FindSymbol(name string) *Symbol {
 }
 
 func (f *File) FilterSymbol(filter func(name string) bool, onlyOneResult bool) []*Symbol {
-	symbols, _ := f.realFile.Symbols()
-	dynamicSymbols, _ := f.realFile.DynamicSymbols()
-	if len(symbols) == 0 && len(dynamicSymbols) == 0 {
-		return nil
-	}
-	symbols = append(symbols, dynamicSymbols...)
-
+	symbols, _ := ReadSymbols(f.Path, f.realFile)
 	result := make([]*Symbol, 0)
 	for _, s := range symbols {
 		if filter(s.Name) {
-			result = append(result, &Symbol{
-				Name:     s.Name,
-				Location: s.Value,
-				Size:     s.Size,
-			})
+			result = append(result, s)
 			if onlyOneResult {
 				break
 			}
@@ -0,0 +1,198 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elf
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"encoding/binary"
+)
+
+const (
+	goPCLNTab118Magic = 0xfffffff0
+	goPCLNTab120Magic = 0xfffffff1
+
+	// the "textStart" is the third word after the 8 bytes header of the pclntab since Go 1.18, it's zero in the newer versions
+	goPCLNTabTextStartWord = 2
+	// the "text" field of the module data is after the pclntab header pointer, six slices and three words
+	goModuleDataTextWord = 1 + 6*3 + 3
+
+	relocationX8664Relative   = 8
+	relocationAARCH64Relative = 1027
+	relocationEntrySize       = 24
+)
+
+// ReadGoPCLNTabSymbols read the function symbols from the ".gopclntab" section of the Go binary,
+// it's useful when the symbol table has been stripped, but the Go runtime still needs the pclntab to work.
+// The parsed symbols are cached by the build-id, so the same binary is only parsed once
+func ReadGoPCLNTabSymbols(path string, f *elf.File) []*Symbol {
+	key := symbolCacheKey(path, f)
+	if key != "" {
+		key = "gopclntab:" + key
+	}
+	symbols, _ := LoadSymbols(key, func() ([]*Symbol, error) {
+		return parseGoPCLNTabSymbols(f), nil
+	})
+	return symbols
+}
+
+func parseGoPCLNTabSymbols(f *elf.File) []*Symbol {
+	pclntab := f.Section(".gopclntab")
+	text := f.Section(".text")
+	if pclntab == nil || text == nil {
+		return nil
+	}
+	data, err := pclntab.Data()
+	if err != nil {
+		return nil
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(data, goPCLNTabTextStart(f, pclntab.Addr, data, text.Addr)))
+	if err != nil {
+		return nil
+	}
+
+	result := make([]*Symbol, 0, len(table.Funcs))
+	for i := range table.Funcs {
+		fun := &table.Funcs[i]
+		result = append(result, &Symbol{Name: fun.Name, Location: fun.Entry, Size: fun.End - fun.Entry})
+	}
+	return result
+}
+
+// goPCLNTabTextStart find the address which the function entries in the pclntab are relative to since Go 1.18,
+// it's the "runtime.text" symbol, which is not the start of ".text" section when the binary is linked externally.
+// The newer Go versions don't write it into the pclntab header, so read the "text" field of the module data,
+// or fall back to the start of ".text" section
+func goPCLNTabTextStart(f *elf.File, addr uint64, data []byte, text uint64) uint64 {
+	start, ok := readGoPCLNTabTextStart(data, f.ByteOrder)
+	if !ok {
+		return text
+	}
+	if start != 0 {
+		return start
+	}
+	if start = readGoModuleDataText(f, addr); start != 0 {
+		return start
+	}
+	return text
+}
+
+// readGoModuleDataText read the "text" field of the module data, which first field is the pointer to the pclntab header.
+// The fields are filled by the dynamic relocations at the load time in the PIE binary, so read them from the relocation addend
+func readGoModuleDataText(f *elf.File, pclntabAddr uint64) uint64 {
+	ptrSize := uint64(8)
+	readPtr := f.ByteOrder.Uint64
+	if f.Class == elf.ELFCLASS32 {
+		ptrSize = 4
+		readPtr = func(b []byte) uint64 {
+			return uint64(f.ByteOrder.Uint32(b))
+		}
+	}
+	// the module data is in the ".go.module" section in the newer Go versions, and in the ".noptrdata" section before
+	for _, name := range []string{".go.module", ".noptrdata"} {
+		section := f.Section(name)
+		if section == nil {
+			continue
+		}
+		data, err := section.Data()
+		if err != nil {
+			continue
+		}
+		textOffset := goModuleDataTextWord * ptrSize
+		for i := uint64(0); i+textOffset+ptrSize <= uint64(len(data)); i += ptrSize {
+			if readPtr(data[i:]) == pclntabAddr {
+				if text := readPtr(data[i+textOffset:]); text != 0 {
+					return text
+				}
+				break
+			}
+		}
+	}
+
+	relocationType := relativeRelocationType(f.Machine)
+	if relocationType == 0 || f.Class != elf.ELFCLASS64 {
+		return 0
+	}
+	var relocations []byte
+	for _, section := range f.Sections {
+		if section.Type != elf.SHT_RELA {
+			continue
+		}
+		if d, err := section.Data(); err == nil {
+			relocations = append(relocations, d...)
+		}
+	}
+	moduleData, _, found := findRelativeRelocation(relocations, f.ByteOrder, relocationType, func(_, addend uint64) bool {
+		return addend == pclntabAddr
+	})
+	if !found {
+		return 0
+	}
+	textAddr := moduleData + goModuleDataTextWord*ptrSize
+	_, text, _ := findRelativeRelocation(relocations, f.ByteOrder, relocationType, func(offset, _ uint64) bool {
+		return offset == textAddr
+	})
+	return text
+}
+
+// readGoPCLNTabTextStart read the "textStart" in the pclntab header, only the Go 1.18 and later versions have it
+func readGoPCLNTabTextStart(data []byte, order binary.ByteOrder) (uint64, bool) {
+	if len(data) < 8 {
+		return 0, false
+	}
+	if magic := order.Uint32(data); magic != goPCLNTab118Magic && magic != goPCLNTab120Magic {
+		return 0, false
+	}
+	ptrSize := uint64(data[7])
+	offset := 8 + goPCLNTabTextStartWord*ptrSize
+	if uint64(len(data)) < offset+ptrSize {
+		return 0, false
+	}
+	switch ptrSize {
+	case 4:
+		return uint64(order.Uint32(data[offset:])), true
+	case 8:
+		return order.Uint64(data[offset:]), true
+	}
+	return 0, false
+}
+
+func relativeRelocationType(machine elf.Machine) uint32 {
+	switch machine {
+	case elf.EM_X86_64:
+		return relocationX8664Relative
+	case elf.EM_AARCH64:
+		return relocationAARCH64Relative
+	}
+	return 0
+}
+
+// findRelativeRelocation find the first relative relocation(Elf64_Rela) matched by the offset and addend
+func findRelativeRelocation(data []byte, order binary.ByteOrder, relocationType uint32,
+	matcher func(offset, addend uint64) bool) (offset, addend uint64, found bool) {
+	for i := 0; i+relocationEntrySize <= len(data); i += relocationEntrySize {
+		if uint32(order.Uint64(data[i+8:])) != relocationType {
+			continue
+		}
+		offset, addend = order.Uint64(data[i:]), order.Uint64(data[i+16:])
+		if matcher(offset, addend) {
+			return offset, addend, true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,179 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elf
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadGoPCLNTabTextStart(t *testing.T) {
+	header := func(magic uint32, ptrSize byte, start uint64) []byte {
+		data := make([]byte, 8+4*int(ptrSize))
+		binary.LittleEndian.PutUint32(data, magic)
+		data[6], data[7] = 1, ptrSize
+		if ptrSize == 4 {
+			binary.LittleEndian.PutUint32(data[8+2*4:], uint32(start))
+		} else {
+			binary.LittleEndian.PutUint64(data[8+2*8:], start)
+		}
+		return data
+	}
+	tests := []struct {
+		name  string
+		data  []byte
+		start uint64
+		ok    bool
+	}{
+		{name: "go1.20", data: header(goPCLNTab120Magic, 8, 0x401000), start: 0x401000, ok: true},
+		{name: "go1.18 32-bit", data: header(goPCLNTab118Magic, 4, 0x8049000), start: 0x8049000, ok: true},
+		{name: "PIE", data: header(goPCLNTab120Magic, 8, 0), start: 0, ok: true},
+		{name: "go1.16", data: header(0xfffffffa, 8, 0x401000)},
+		{name: "too short", data: header(goPCLNTab120Magic, 8, 0x401000)[:20]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, ok := readGoPCLNTabTextStart(tt.data, binary.LittleEndian)
+			if ok != tt.ok || start != tt.start {
+				t.Errorf("expected: (%#x, %t), actual: (%#x, %t)", tt.start, tt.ok, start, ok)
+			}
+		})
+	}
+}
+
+func TestFindRelativeRelocation(t *testing.T) {
+	relocation := func(offset, info, addend uint64) []byte {
+		data := make([]byte, relocationEntrySize)
+		binary.LittleEndian.PutUint64(data, offset)
+		binary.LittleEndian.PutUint64(data[8:], info)
+		binary.LittleEndian.PutUint64(data[16:], addend)
+		return data
+	}
+	var relocations []byte
+	relocations = append(relocations, relocation(0x1000, relocationX8664Relative, 0x2000)...)
+	relocations = append(relocations, relocation(0x3000, 1<<32|1, 0x4000)...)
+	relocations = append(relocations, relocation(0x3000, relocationX8664Relative, 0x5000)...)
+
+	tests := []struct {
+		name    string
+		matcher func(offset, addend uint64) bool
+		offset  uint64
+		addend  uint64
+		found   bool
+	}{
+		{name: "by offset", matcher: func(offset, _ uint64) bool { return offset == 0x1000 }, offset: 0x1000, addend: 0x2000, found: true},
+		{name: "skip other relocation type", matcher: func(offset, _ uint64) bool { return offset == 0x3000 },
+			offset: 0x3000, addend: 0x5000, found: true},
+		{name: "by addend", matcher: func(_, addend uint64) bool { return addend == 0x5000 }, offset: 0x3000, addend: 0x5000, found: true},
+		{name: "not found", matcher: func(offset, _ uint64) bool { return offset == 0x6000 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, addend, found := findRelativeRelocation(relocations, binary.LittleEndian, relocationX8664Relative, tt.matcher)
+			if found != tt.found || offset != tt.offset || addend != tt.addend {
+				t.Errorf("expected: (%#x, %#x, %t), actual: (%#x, %#x, %t)", tt.offset, tt.addend, tt.found, offset, addend, found)
+			}
+		})
+	}
+}
+
+func TestParseGoPCLNTabSymbols(t *testing.T) {
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is not found")
+	}
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module pclntab\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "exec", args: []string{"-buildmode=exe"}},
+		{name: "pie", args: []string{"-buildmode=pie"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full := buildGoBinary(t, goBinary, dir, tt.name, tt.args...)
+			stripped := buildGoBinary(t, goBinary, dir, tt.name+"-stripped", append(tt.args, "-ldflags=-s -w")...)
+			defer full.Close()
+			defer stripped.Close()
+			if !IsStripped(stripped) {
+				t.Fatalf("the binary should be stripped")
+			}
+
+			symbols, err := full.Symbols()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := make(map[string]uint64)
+			var textStart uint64
+			for _, s := range symbols {
+				if s.Name == "main.main" || s.Name == "runtime.main" {
+					expected[s.Name] = s.Value
+				} else if s.Name == "runtime.text" {
+					textStart = s.Value
+				}
+			}
+			// the text start should be found without falling back to the ".text" section
+			pclntab := stripped.Section(".gopclntab")
+			data, err := pclntab.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start := goPCLNTabTextStart(stripped, pclntab.Addr, data, 0); start != textStart {
+				t.Errorf("the text start should be %#x, but got %#x", textStart, start)
+			}
+			// the function entries in the pclntab should be same with the symbol table of the not stripped binary
+			for _, s := range parseGoPCLNTabSymbols(stripped) {
+				if location, ok := expected[s.Name]; ok {
+					if s.Location != location {
+						t.Errorf("the location of %s should be %#x, but got %#x", s.Name, location, s.Location)
+					}
+					delete(expected, s.Name)
+				}
+			}
+			if len(expected) > 0 {
+				t.Errorf("cannot find the functions in the pclntab: %v", expected)
+			}
+		})
+	}
+}
+
+func buildGoBinary(t *testing.T, goBinary, dir, name string, args ...string) *elf.File {
+	output := filepath.Join(dir, name)
+	cmd := exec.Command(goBinary, append(append([]string{"build", "-o", output}, args...), ".")...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("build the go binary failure: %v, %s", err, out)
+	}
+	f, err := elf.Open(output)
+	if err != nil {
+		t.Skipf("the go binary is not the ELF file: %v", err)
+	}
+	return f
+}
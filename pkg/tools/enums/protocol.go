@@ -30,6 +30,7 @@ const (
 	ConnectionProtocolUnknown ConnectionProtocol = 0
 	ConnectionProtocolHTTP    ConnectionProtocol = 1
 	ConnectionProtocolHTTP2   ConnectionProtocol = 2
+	ConnectionProtocolTLS     ConnectionProtocol = 3
 )
 
 var connectionProtocolMap = make(map[ConnectionProtocol]string)
@@ -37,12 +38,18 @@ var connectionProtocolMap = make(map[ConnectionProtocol]string)
 func init() {
 	RegisterConnectionProtocolString(ConnectionProtocolHTTP, http)
 	RegisterConnectionProtocolString(ConnectionProtocolHTTP2, http)
+	RegisterConnectionProtocolString(ConnectionProtocolTLS, tls)
 }
 
 func RegisterConnectionProtocolString(protocol ConnectionProtocol, name string) {
 	connectionProtocolMap[protocol] = name
 }
 
+// UnregisterConnectionProtocolString remove the name of the protocol, such as the analyzer registered in the tests
+func UnregisterConnectionProtocolString(protocol ConnectionProtocol) {
+	delete(connectionProtocolMap, protocol)
+}
+
 func ConnectionProtocolString(protocol ConnectionProtocol) string {
 	if name, ok := connectionProtocolMap[protocol]; ok {
 		return name
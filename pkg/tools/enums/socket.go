@@ -22,6 +22,7 @@ import "fmt"
 const (
 	unknown = "unknown"
 	http    = "http"
+	tls     = "tls"
 )
 
 var SocketFamilyUnknown = uint8(0xff)
@@ -58,6 +59,29 @@ func (r ConnectionRole) Revert() ConnectionRole {
 	}
 }
 
+// TransportProtocol is the L4 protocol of the socket, same with the IPPROTO_* in the kernel
+type TransportProtocol uint8
+
+const (
+	TransportProtocolUnknown TransportProtocol = 0
+	TransportProtocolTCP     TransportProtocol = 6
+	TransportProtocolUDP     TransportProtocol = 17
+	TransportProtocolSCTP    TransportProtocol = 132
+)
+
+func (t TransportProtocol) String() string {
+	switch t {
+	case TransportProtocolTCP:
+		return "tcp"
+	case TransportProtocolUDP:
+		return "udp"
+	case TransportProtocolSCTP:
+		return "sctp"
+	default:
+		return unknown
+	}
+}
+
 // SocketDataDirection indicates whether data is being written or receive
 type SocketDataDirection uint8
 
@@ -90,6 +114,7 @@ type SocketExceptionOperationType uint32
 const (
 	SocketExceptionOperationRetransmit SocketExceptionOperationType = 1
 	SocketExceptionOperationDrop       SocketExceptionOperationType = 2
+	SocketExceptionOperationOutOfOrder SocketExceptionOperationType = 3
 )
 
 type SocketMessageType uint8
@@ -135,6 +160,8 @@ const (
 	SocketFunctionNameSslRead    = 19
 	SocketFunctionNameGoTLSWrite = 20
 	SocketFunctionNameGoTLSRead  = 21
+	// SocketFunctionNameExisting the connection is established before monitoring, only generated in the user space
+	SocketFunctionNameExisting = 22
 )
 
 // nolint
@@ -182,6 +209,8 @@ func (f SocketFunctionName) String() string {
 		return "GoTLSWrite"
 	case SocketFunctionNameGoTLSRead:
 		return "GoTLSRead"
+	case SocketFunctionNameExisting:
+		return "Existing"
 	default:
 		return fmt.Sprintf("Unknown(%d)", f)
 	}
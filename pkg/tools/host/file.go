@@ -20,6 +20,8 @@ package host
 import (
 	"os"
 	"path"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -53,3 +55,20 @@ func GetHostEtcInHost(etcSubPath string) string {
 func cleanPath(p string) string {
 	return path.Clean(p)
 }
+
+// SplitProcessRootPath split the file path which located under the process root filesystem("/proc/{pid}/root")
+// into the root path and the sub path inside the process, if not under the process root, the root is empty
+func SplitProcessRootPath(p string) (root, subPath string) {
+	procPath := GetHostProcInHost("") + "/"
+	if !strings.HasPrefix(p, procPath) {
+		return "", p
+	}
+	parts := strings.SplitN(strings.TrimPrefix(p, procPath), "/", 3)
+	if len(parts) < 3 || parts[1] != "root" {
+		return "", p
+	}
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return "", p
+	}
+	return procPath + parts[0] + "/root", "/" + parts[2]
+}
@@ -0,0 +1,42 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package host
+
+import "testing"
+
+func TestSplitProcessRootPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		root    string
+		subPath string
+	}{
+		{name: "process root", path: GetHostProcInHost("123/root/usr/bin/app"), root: GetHostProcInHost("123/root"), subPath: "/usr/bin/app"},
+		{name: "not process root", path: "/usr/bin/app", subPath: "/usr/bin/app"},
+		{name: "other process file", path: GetHostProcInHost("123/exe"), subPath: GetHostProcInHost("123/exe")},
+		{name: "not pid", path: GetHostProcInHost("self/root/usr/bin/app"), subPath: GetHostProcInHost("self/root/usr/bin/app")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, subPath := SplitProcessRootPath(tt.path)
+			if root != tt.root || subPath != tt.subPath {
+				t.Errorf("expected: (%q, %q), actual: (%q, %q)", tt.root, tt.subPath, root, subPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// NetworkNamespaceInode read the inode of network namespace(nsfs) of the process,
+// the processes have the same inode means they are in the same network namespace
+func NetworkNamespaceInode(pid int32) (uint64, error) {
+	stat, err := os.Stat(GetHostProcInHost(fmt.Sprintf("%d/ns/net", pid)))
+	if err != nil {
+		return 0, err
+	}
+	sysStat, ok := stat.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("cannot read the network namespace inode of process: %d", pid)
+	}
+	return sysStat.Ino, nil
+}
+
+// DialInNetworkNamespace dial the address in the network namespace of the process,
+// such as accessing the admin port which only bound on the localhost of the pod
+func DialInNetworkNamespace(ctx context.Context, pid int32, network, address string) (net.Conn, error) {
+	var conn net.Conn
+	var dialErr error
+	err := executeInNetworkNamespace(pid, func() {
+		conn, dialErr = (&net.Dialer{}).DialContext(ctx, network, address)
+	})
+	if err != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return nil, err
+	}
+	return conn, dialErr
+}
+
+// SocketInNetworkNamespace create the socket in the network namespace of the process,
+// such as the packet socket for capturing the packets of the pod
+func SocketInNetworkNamespace(pid int32, domain, typ, proto int) (int, error) {
+	fd := -1
+	var socketErr error
+	err := executeInNetworkNamespace(pid, func() {
+		fd, socketErr = unix.Socket(domain, typ, proto)
+	})
+	if err != nil {
+		if fd >= 0 {
+			_ = unix.Close(fd)
+		}
+		return -1, err
+	}
+	return fd, socketErr
+}
+
+func executeInNetworkNamespace(pid int32, execute func()) error {
+	target, err := os.Open(GetHostProcInHost(fmt.Sprintf("%d/ns/net", pid)))
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	// the network namespace is per thread, and the socket is created in the namespace of the current thread
+	runtime.LockOSThread()
+	current, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	defer current.Close()
+	if err = unix.Setns(int(target.Fd()), unix.CLONE_NEWNET); err != nil {
+		runtime.UnlockOSThread()
+		return fmt.Errorf("enter the network namespace of process %d failure: %v", pid, err)
+	}
+	execute()
+	if err = unix.Setns(int(current.Fd()), unix.CLONE_NEWNET); err != nil {
+		// keep the thread locked, then the thread would be terminated when the goroutine exit
+		return fmt.Errorf("restore the network namespace failure: %v", err)
+	}
+	runtime.UnlockOSThread()
+	return nil
+}
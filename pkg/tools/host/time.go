@@ -19,6 +19,7 @@ package host
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sys/unix"
@@ -26,18 +27,53 @@ import (
 	v3 "skywalking.apache.org/repo/goapi/collect/common/v3"
 )
 
-// BootTime the System boot time
+// the max retry count when reading the clocks to find the closest sample
+const clockSampleCount = 5
+
+// BootTime the System boot time when rover started
 var BootTime time.Time
 
+// bootTimeNano is the latest calibrated boot time in nanoseconds, for converting the BPF time
+var bootTimeNano atomic.Int64
+
 func init() {
-	var ts unix.Timespec
-	err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts)
-	now := time.Now()
+	nano, err := readBootTimeNano()
 	if err != nil {
 		panic(fmt.Errorf("init boot time error: %v", err))
 	}
-	bootTimeNano := now.UnixNano() - ts.Nano()
-	BootTime = time.Unix(bootTimeNano/1e9, bootTimeNano%1e9)
+	bootTimeNano.Store(nano)
+	BootTime = time.Unix(nano/1e9, nano%1e9)
+}
+
+// CalibrateBootTime recalculate the boot time through the realtime and monotonic clock.
+// The BPF time is based on the monotonic clock, which not counting the suspend time and not affected by the NTP step,
+// so the offset to the wall clock could be drifted over the long uptime, return the drift duration of this calibration.
+func CalibrateBootTime() (time.Duration, error) {
+	nano, err := readBootTimeNano()
+	if err != nil {
+		return 0, err
+	}
+	previous := bootTimeNano.Swap(nano)
+	return time.Duration(nano - previous), nil
+}
+
+// readBootTimeNano sampling the realtime clock before and after the monotonic clock,
+// using the sample with the smallest window to reduce the scheduling noise
+func readBootTimeNano() (int64, error) {
+	var result, minWindow int64
+	for i := 0; i < clockSampleCount; i++ {
+		var ts unix.Timespec
+		before := time.Now().UnixNano()
+		if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+			return 0, err
+		}
+		after := time.Now().UnixNano()
+		if window := after - before; i == 0 || window < minWindow {
+			minWindow = window
+			result = before + window/2 - ts.Nano()
+		}
+	}
+	return result, nil
 }
 
 func TimeToInstant(bpfTime uint64) *v3.Instant {
@@ -49,6 +85,5 @@ func TimeToInstant(bpfTime uint64) *v3.Instant {
 }
 
 func Time(bpfTime uint64) time.Time {
-	timeCopy := time.Unix(BootTime.Unix(), int64(BootTime.Nanosecond()))
-	return timeCopy.Add(time.Duration(bpfTime))
+	return time.Unix(0, bootTimeNano.Load()+int64(bpfTime))
 }
@@ -0,0 +1,62 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package host
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	boot := Time(0)
+	tests := []struct {
+		name     string
+		bpfTime  uint64
+		expected time.Time
+	}{
+		{name: "boot time", bpfTime: 0, expected: boot},
+		{name: "one second after boot", bpfTime: uint64(time.Second), expected: boot.Add(time.Second)},
+		{name: "nanoseconds", bpfTime: 1234567, expected: boot.Add(1234567)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Time(tt.bpfTime); !actual.Equal(tt.expected) {
+				t.Errorf("expected time: %v, actual: %v", tt.expected, actual)
+			}
+			instant := TimeToInstant(tt.bpfTime)
+			if instant.GetSeconds() != tt.expected.Unix() || instant.GetNanos() != int32(tt.expected.Nanosecond()) {
+				t.Errorf("expected instant: %v, actual: %v", tt.expected, instant)
+			}
+		})
+	}
+}
+
+func TestCalibrateBootTime(t *testing.T) {
+	before := Time(0)
+	drift, err := CalibrateBootTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the clocks are read in the same process, so the drift should be tiny
+	if drift > time.Second || drift < -time.Second {
+		t.Fatalf("the drift is too large: %v", drift)
+	}
+	if after := Time(0); after.Sub(before) != drift {
+		t.Errorf("the boot time should be moved by the drift, expected: %v, actual: %v", drift, after.Sub(before))
+	}
+}
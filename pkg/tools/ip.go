@@ -59,7 +59,14 @@ func IsLocalHostAddress(address string) bool {
 	if host.ipAddressesByIP[address] {
 		return true
 	}
-	return address == "0.0.0.0"
+	if address == "0.0.0.0" || address == "::" {
+		return true
+	}
+	// the IPv6 address could be in the other format, such as IPv4-mapped
+	if parsed := net.ParseIP(address); parsed != nil && parsed.String() != address {
+		return IsLocalHostAddress(parsed.String())
+	}
+	return false
 }
 
 // Hostname of machine
@@ -79,6 +86,8 @@ type hostInfo struct {
 type hostIPAddress struct {
 	ipV4 string
 	ipV6 string
+	// all addresses in the interface
+	all []string
 }
 
 func queryHostInfo() *hostInfo {
@@ -92,8 +101,9 @@ func queryHostInfo() *hostInfo {
 	}
 	addressesByIP := make(map[string]bool)
 	for _, addr := range addressesByName {
-		addressesByIP[addr.ipV4] = true
-		addressesByIP[addr.ipV6] = true
+		for _, ip := range addr.all {
+			addressesByIP[ip] = true
+		}
 	}
 	return &hostInfo{name: name, ipAddressesByName: addressesByName, ipAddressesByIP: addressesByIP, defaultIPAddr: def}
 }
@@ -115,7 +125,7 @@ func localIPAddress0() (ipAddresses map[string]*hostIPAddress, defAddr string, e
 		if err != nil {
 			return nil, "", err
 		}
-		ipv4, ipv6 := analyzeIPAddresses(addrs)
+		ipv4, ipv6, all := analyzeIPAddresses(addrs)
 
 		if ipv4 != "" || ipv6 != "" {
 			if defV4 == "" {
@@ -124,7 +134,7 @@ func localIPAddress0() (ipAddresses map[string]*hostIPAddress, defAddr string, e
 			if defV6 == "" {
 				defV6 = ipv6
 			}
-			ipAddresses[i.Name] = &hostIPAddress{ipV4: ipv4, ipV6: ipv6}
+			ipAddresses[i.Name] = &hostIPAddress{ipV4: ipv4, ipV6: ipv6, all: all}
 		}
 	}
 
@@ -141,7 +151,7 @@ func localIPAddress0() (ipAddresses map[string]*hostIPAddress, defAddr string, e
 	return ipAddresses, defAddr, nil
 }
 
-func analyzeIPAddresses(addrs []net.Addr) (ipv4, ipv6 string) {
+func analyzeIPAddresses(addrs []net.Addr) (ipv4, ipv6 string, all []string) {
 	for _, addr := range addrs {
 		var ip net.IP
 		switch v := addr.(type) {
@@ -151,11 +161,16 @@ func analyzeIPAddresses(addrs []net.Addr) (ipv4, ipv6 string) {
 			ip = v.IP
 		}
 		if ip.To4() != nil {
-			ipv4 = ip.To4().String()
-		}
-		if ip.To16() != nil {
-			ipv6 = ip.To16().String()
+			if ipv4 == "" {
+				ipv4 = ip.To4().String()
+			}
+			all = append(all, ip.To4().String())
+		} else if ip.To16() != nil {
+			if ipv6 == "" {
+				ipv6 = ip.To16().String()
+			}
+			all = append(all, ip.To16().String())
 		}
 	}
-	return ipv4, ipv6
+	return ipv4, ipv6, all
 }
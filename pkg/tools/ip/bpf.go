@@ -20,6 +20,8 @@ package ip
 import (
 	"net"
 	"unsafe"
+
+	"golang.org/x/sys/unix"
 )
 
 func ParseIPV4(bpfVal uint32) string {
@@ -29,6 +31,46 @@ func ParseIPV4(bpfVal uint32) string {
 	return net.IP((*(*[net.IPv4len]byte)(unsafe.Pointer(&bpfVal)))[:]).String()
 }
 
+// ParseIPV6 parse the IPv6 address from BPF, the IPv4-mapped IPv6 address would be converted to the IPv4 format
 func ParseIPV6(bpfVal [16]uint8) string {
 	return net.IP((*(*[net.IPv6len]byte)(unsafe.Pointer(&bpfVal)))[:]).String()
 }
+
+// ParseIPV6FromUint64 parse the IPv6 address which read the "in6_addr" as two native-endian uint64 in BPF,
+// the all-zero address is "::", so the caller should check the address exists before parsing
+func ParseIPV6FromUint64(high, low uint64) string {
+	var val [16]uint8
+	*(*uint64)(unsafe.Pointer(&val[0])) = high
+	*(*uint64)(unsafe.Pointer(&val[8])) = low
+	return ParseIPV6(val)
+}
+
+// Family of the IP address, the IPv4-mapped IPv6 address is treated as the IPv4 address
+func Family(address string) uint32 {
+	parsed := net.ParseIP(address)
+	if parsed == nil {
+		return 0
+	}
+	if parsed.To4() != nil {
+		return unix.AF_INET
+	}
+	return unix.AF_INET6
+}
+
+// ToBPFIPV4 convert the IPv4 address to the BPF format, it's the reverse of ParseIPV4
+func ToBPFIPV4(address string) uint32 {
+	var result uint32
+	if parsed := net.ParseIP(address).To4(); parsed != nil {
+		copy((*(*[net.IPv4len]byte)(unsafe.Pointer(&result)))[:], parsed)
+	}
+	return result
+}
+
+// ToBPFIPV6 convert the IP address to the BPF format, the IPv4 address would be converted to the IPv4-mapped IPv6 address
+func ToBPFIPV6(address string) [16]uint8 {
+	var result [16]uint8
+	if parsed := net.ParseIP(address).To16(); parsed != nil {
+		copy(result[:], parsed)
+	}
+	return result
+}
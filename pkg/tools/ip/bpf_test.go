@@ -0,0 +1,60 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"net"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestParseIPV6(t *testing.T) {
+	var tests = []struct {
+		address string
+		result  string
+		family  uint32
+	}{
+		{address: "2001:db8::1", result: "2001:db8::1", family: unix.AF_INET6},
+		{address: "::ffff:10.0.0.1", result: "10.0.0.1", family: unix.AF_INET},
+		{address: "::1", result: "::1", family: unix.AF_INET6},
+		{address: "::", result: "::", family: unix.AF_INET6},
+	}
+
+	for _, test := range tests {
+		var val [16]uint8
+		copy(val[:], net.ParseIP(test.address).To16())
+		if actual := ParseIPV6(val); actual != test.result {
+			t.Fatalf("excepted: %s, actual: %s", test.result, actual)
+		}
+		high, low := *(*uint64)(unsafe.Pointer(&val[0])), *(*uint64)(unsafe.Pointer(&val[8]))
+		if actual := ParseIPV6FromUint64(high, low); actual != test.result {
+			t.Fatalf("excepted from uint64: %s, actual: %s", test.result, actual)
+		}
+		if actual := Family(test.result); actual != test.family {
+			t.Fatalf("excepted family: %d, actual: %d", test.family, actual)
+		}
+		if test.family == unix.AF_INET6 && ToBPFIPV6(test.result) != val {
+			t.Fatalf("excepted to BPF: %v, actual: %v", val, ToBPFIPV6(test.result))
+		}
+		if test.family == unix.AF_INET && ParseIPV4(ToBPFIPV4(test.result)) != test.result {
+			t.Fatalf("excepted IPv4 round trip: %s, actual: %s", test.result, ParseIPV4(ToBPFIPV4(test.result)))
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/apache/skywalking-rover/pkg/logger"
 	"github.com/apache/skywalking-rover/pkg/tools"
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
 )
 
 var log = logger.GetLogger("tools", "ip")
@@ -54,8 +55,12 @@ func NewConnTrack() (*ConnTrack, error) {
 }
 
 func (c *ConnTrack) UpdateRealPeerAddress(addr *SocketPair) error {
+	if addr.Role == enums.ConnectionRoleServer {
+		return c.updateOriginalPeerAddress(addr)
+	}
+	// the IPv4-mapped IPv6 socket is tracked as IPv4 in the conntrack
 	family := conntrack.IPv4
-	if addr.Family == unix.AF_INET6 {
+	if Family(addr.SrcIP) == unix.AF_INET6 || Family(addr.DestIP) == unix.AF_INET6 {
 		family = conntrack.IPv6
 	}
 
@@ -73,6 +78,7 @@ func (c *ConnTrack) UpdateRealPeerAddress(addr *SocketPair) error {
 
 		if res := c.filterValidateReply(session, tuple); res != nil {
 			if !ShouldIgnoreConntrack(addr.DestIP, res.Src.String(), *res.Proto.SrcPort) {
+				addr.OriginalDestIP, addr.OriginalDestPort = addr.DestIP, addr.DestPort
 				addr.DestIP = res.Src.String()
 				addr.NeedConnTrack = false
 				log.Debugf("update real peer address from conntrack: %s:%d", addr.DestIP, addr.DestPort)
@@ -86,6 +92,44 @@ func (c *ConnTrack) UpdateRealPeerAddress(addr *SocketPair) error {
 	return nil
 }
 
+// updateOriginalPeerAddress query the conntrack through the reply direction tuple of the server side connection,
+// to recover the original client address and the destination address before NAT, such as the NodePort of kube-proxy
+func (c *ConnTrack) updateOriginalPeerAddress(addr *SocketPair) error {
+	family := conntrack.IPv4
+	if Family(addr.SrcIP) == unix.AF_INET6 || Family(addr.DestIP) == unix.AF_INET6 {
+		family = conntrack.IPv6
+	}
+
+	// the reply direction is from the local server to the remote peer
+	reply := c.parseSocketToTuple(addr)
+	sessions, err := c.tracker.Get(conntrack.Conntrack, family, conntrack.Con{Reply: reply})
+	if err != nil {
+		return fmt.Errorf("cannot get the conntrack session by reply, family: %d, reply src: %s:%d, reply dest: %s:%d, error: %v",
+			family, reply.Src, *reply.Proto.SrcPort, reply.Dst, *reply.Proto.DstPort, err)
+	}
+	c.applyOriginalPeerAddress(addr, reply, sessions)
+	return nil
+}
+
+// applyOriginalPeerAddress update the peer address by the origin direction of the session which reply direction matched
+func (c *ConnTrack) applyOriginalPeerAddress(addr *SocketPair, reply *conntrack.IPTuple, sessions []conntrack.Con) bool {
+	for inx := range sessions {
+		session := sessions[inx]
+		if !c.ipTupleValid(session.Origin) || !c.ipTupleValid(session.Reply) || !c.ipTuplesEqual(reply, session.Reply) {
+			continue
+		}
+		addr.OriginalDestIP, addr.OriginalDestPort = session.Origin.Dst.String(), *session.Origin.Proto.DstPort
+		if !session.Origin.Src.Equal(*reply.Dst) {
+			addr.DestIP, addr.DestPort = session.Origin.Src.String(), *session.Origin.Proto.SrcPort
+			log.Debugf("update original peer address from conntrack: %s:%d, original destination: %s:%d",
+				addr.DestIP, addr.DestPort, addr.OriginalDestIP, addr.OriginalDestPort)
+		}
+		addr.NeedConnTrack = false
+		return true
+	}
+	return false
+}
+
 func ShouldIgnoreConntrack(originalDestIP, conntrackIP string, conntrackPort uint16) bool {
 	// if the original dest IP is not local host
 	// and the conntrack IP is local host, and port is 15001, such as 127.0.0.1:15001, means the conntrack is to istio-proxy
@@ -97,6 +141,12 @@ func (c *ConnTrack) parseSocketToTuple(addr *SocketPair) *conntrack.IPTuple {
 	tcp := uint8(syscall.IPPROTO_TCP)
 	srcIP := net.ParseIP(addr.SrcIP)
 	dstIP := net.ParseIP(addr.DestIP)
+	if v4 := srcIP.To4(); v4 != nil {
+		srcIP = v4
+	}
+	if v4 := dstIP.To4(); v4 != nil {
+		dstIP = v4
+	}
 	var srcPort, dstPort = addr.SrcPort, addr.DestPort
 	return &conntrack.IPTuple{
 		Src: &srcIP,
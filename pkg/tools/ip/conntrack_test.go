@@ -0,0 +1,93 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"testing"
+
+	"github.com/florianl/go-conntrack"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+)
+
+func TestApplyOriginalPeerAddress(t *testing.T) {
+	c := &ConnTrack{}
+	// the server 10.0.0.2:8080 accepted the connection from 10.0.1.5:40000
+	server := func() *SocketPair {
+		return &SocketPair{Role: enums.ConnectionRoleServer, SrcIP: "10.0.0.2", SrcPort: 8080,
+			DestIP: "10.0.1.5", DestPort: 40000, NeedConnTrack: true}
+	}
+	tests := []struct {
+		name         string
+		sessions     []conntrack.Con
+		applied      bool
+		destIP       string
+		destPort     uint16
+		originalIP   string
+		originalPort uint16
+	}{
+		{
+			name: "node port with SNAT",
+			sessions: []conntrack.Con{{
+				Origin: c.parseSocketToTuple(&SocketPair{SrcIP: "192.168.1.10", SrcPort: 52000, DestIP: "172.16.0.1", DestPort: 30080}),
+				Reply:  c.parseSocketToTuple(&SocketPair{SrcIP: "10.0.0.2", SrcPort: 8080, DestIP: "10.0.1.5", DestPort: 40000}),
+			}},
+			applied: true, destIP: "192.168.1.10", destPort: 52000, originalIP: "172.16.0.1", originalPort: 30080,
+		},
+		{
+			name: "service without SNAT",
+			sessions: []conntrack.Con{{
+				Origin: c.parseSocketToTuple(&SocketPair{SrcIP: "10.0.1.5", SrcPort: 40000, DestIP: "10.96.0.10", DestPort: 80}),
+				Reply:  c.parseSocketToTuple(&SocketPair{SrcIP: "10.0.0.2", SrcPort: 8080, DestIP: "10.0.1.5", DestPort: 40000}),
+			}},
+			applied: true, destIP: "10.0.1.5", destPort: 40000, originalIP: "10.96.0.10", originalPort: 80,
+		},
+		{
+			name: "reply not matched",
+			sessions: []conntrack.Con{{
+				Origin: c.parseSocketToTuple(&SocketPair{SrcIP: "10.0.1.6", SrcPort: 40000, DestIP: "10.96.0.10", DestPort: 80}),
+				Reply:  c.parseSocketToTuple(&SocketPair{SrcIP: "10.0.0.2", SrcPort: 8080, DestIP: "10.0.1.6", DestPort: 40000}),
+			}},
+			destIP: "10.0.1.5", destPort: 40000,
+		},
+		{
+			name:     "invalid session",
+			sessions: []conntrack.Con{{Reply: c.parseSocketToTuple(&SocketPair{SrcIP: "10.0.0.2", SrcPort: 8080, DestIP: "10.0.1.5", DestPort: 40000})}},
+			destIP:   "10.0.1.5", destPort: 40000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := server()
+			reply := c.parseSocketToTuple(addr)
+			if applied := c.applyOriginalPeerAddress(addr, reply, tt.sessions); applied != tt.applied {
+				t.Fatalf("expected applied: %t, actual: %t", tt.applied, applied)
+			}
+			if addr.DestIP != tt.destIP || addr.DestPort != tt.destPort {
+				t.Errorf("expected peer: %s:%d, actual: %s:%d", tt.destIP, tt.destPort, addr.DestIP, addr.DestPort)
+			}
+			if addr.OriginalDestIP != tt.originalIP || addr.OriginalDestPort != tt.originalPort {
+				t.Errorf("expected original destination: %s:%d, actual: %s:%d",
+					tt.originalIP, tt.originalPort, addr.OriginalDestIP, addr.OriginalDestPort)
+			}
+			if addr.NeedConnTrack == tt.applied {
+				t.Errorf("the conntrack should be finished when applied")
+			}
+		})
+	}
+}
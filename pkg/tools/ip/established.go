@@ -0,0 +1,78 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"fmt"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+
+	processNet "github.com/shirou/gopsutil/net"
+)
+
+const (
+	tcpStatusEstablished = "ESTABLISHED"
+	tcpStatusListen      = "LISTEN"
+)
+
+// EstablishedSocket is the TCP connection which is already established in the process
+type EstablishedSocket struct {
+	FD     uint32
+	Socket *SocketPair
+}
+
+// ParseEstablishedSockets read all established TCP connections of the process from the "/proc" filesystem,
+// the role is detected by whether the local port is listening in the process
+func ParseEstablishedSockets(pid int32) ([]*EstablishedSocket, error) {
+	connections, err := processNet.ConnectionsPid("tcp", pid)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get all connections from pid: %d, %v", pid, err)
+	}
+	return buildEstablishedSockets(connections), nil
+}
+
+func buildEstablishedSockets(connections []processNet.ConnectionStat) []*EstablishedSocket {
+	listenPorts := make(map[uint32]bool)
+	for _, con := range connections {
+		if con.Status == tcpStatusListen {
+			listenPorts[con.Laddr.Port] = true
+		}
+	}
+	result := make([]*EstablishedSocket, 0)
+	for _, con := range connections {
+		if con.Status != tcpStatusEstablished || con.Fd == 0 {
+			continue
+		}
+		role := enums.ConnectionRoleClient
+		if listenPorts[con.Laddr.Port] {
+			role = enums.ConnectionRoleServer
+		}
+		result = append(result, &EstablishedSocket{
+			FD: con.Fd,
+			Socket: &SocketPair{
+				Family:   con.Family,
+				Role:     role,
+				SrcIP:    con.Laddr.IP,
+				SrcPort:  uint16(con.Laddr.Port),
+				DestIP:   con.Raddr.IP,
+				DestPort: uint16(con.Raddr.Port),
+			},
+		})
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/enums"
+
+	processNet "github.com/shirou/gopsutil/net"
+)
+
+func TestBuildEstablishedSockets(t *testing.T) {
+	connections := []processNet.ConnectionStat{
+		{Fd: 3, Status: "LISTEN", Laddr: processNet.Addr{IP: "0.0.0.0", Port: 8080}},
+		{Fd: 4, Status: "ESTABLISHED", Laddr: processNet.Addr{IP: "10.0.0.1", Port: 8080},
+			Raddr: processNet.Addr{IP: "10.0.0.2", Port: 51000}},
+		{Fd: 5, Status: "ESTABLISHED", Laddr: processNet.Addr{IP: "10.0.0.1", Port: 42000},
+			Raddr: processNet.Addr{IP: "10.0.0.3", Port: 3306}},
+		{Fd: 6, Status: "CLOSE_WAIT", Laddr: processNet.Addr{IP: "10.0.0.1", Port: 42001},
+			Raddr: processNet.Addr{IP: "10.0.0.3", Port: 3306}},
+		{Fd: 0, Status: "ESTABLISHED", Laddr: processNet.Addr{IP: "10.0.0.1", Port: 42002},
+			Raddr: processNet.Addr{IP: "10.0.0.3", Port: 3306}},
+	}
+	var tests = []struct {
+		fd       uint32
+		role     enums.ConnectionRole
+		destPort uint16
+	}{
+		{fd: 4, role: enums.ConnectionRoleServer, destPort: 51000},
+		{fd: 5, role: enums.ConnectionRoleClient, destPort: 3306},
+	}
+
+	sockets := buildEstablishedSockets(connections)
+	if len(sockets) != len(tests) {
+		t.Fatalf("excepted sockets count: %d, actual: %d", len(tests), len(sockets))
+	}
+	for i, test := range tests {
+		actual := sockets[i]
+		if actual.FD != test.fd || actual.Socket.Role != test.role || actual.Socket.DestPort != test.destPort {
+			t.Fatalf("excepted: fd %d, role %s, dest port %d, actual: fd %d, role %s, dest port %d",
+				test.fd, test.role, test.destPort, actual.FD, actual.Socket.Role, actual.Socket.DestPort)
+		}
+	}
+}
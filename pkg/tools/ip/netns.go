@@ -0,0 +1,70 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/tools/procfs"
+)
+
+// the state of listening socket in the "/proc/{pid}/net/tcp"
+const tcpListenState = "0A"
+
+// IsListeningPort check the TCP port is listening in the network namespace of the process
+func IsListeningPort(pid int32, port uint16) bool {
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		if isListeningPortInFile(pid, file, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func isListeningPortInFile(pid int32, name string, port uint16) bool {
+	data, err := procfs.ReadFile(pid, name)
+	if err != nil {
+		return false
+	}
+	return containsListeningPort(data, port)
+}
+
+// containsListeningPort check the port is listening in the content of the "/proc/{pid}/net/tcp" or "/proc/{pid}/net/tcp6"
+func containsListeningPort(data []byte, port uint16) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		// format: "sl local_address rem_address st ...", the address is "{hex IP}:{hex port}"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpListenState {
+			continue
+		}
+		inx := strings.LastIndexByte(fields[1], ':')
+		if inx < 0 {
+			continue
+		}
+		if localPort, err := strconv.ParseUint(fields[1][inx+1:], 16, 16); err == nil && uint16(localPort) == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+// the count of the fields before the local addresses and after the remote addresses in the "/proc/net/sctp/assocs"
+const (
+	sctpAssocPrefixFields = 13
+	sctpAssocSuffixFields = 11
+)
+
+var sctpAssociationStates = []string{"closed", "cookie_wait", "cookie_echoed", "established",
+	"shutdown_pending", "shutdown_sent", "shutdown_received", "shutdown_ack_sent"}
+
+// SCTPAssociation is the association of the SCTP socket, the one-to-many style socket could have multiple associations
+type SCTPAssociation struct {
+	ID        uint32
+	State     string
+	Inode     uint64
+	LocalPort uint16
+	// the addresses of the multi-homing endpoint, the primary address is the first one
+	LocalAddresses  []string
+	RemotePort      uint16
+	RemoteAddresses []string
+	// the bytes of the data in the send and receive queue
+	TxQueue uint64
+	RxQueue uint64
+	// the count of the inbound and outbound streams
+	InStreams  uint32
+	OutStreams uint32
+	// the count of the retransmitted data chunks
+	Retransmits uint64
+}
+
+// ReadSCTPAssociations read all SCTP associations in the network namespace of the process
+func ReadSCTPAssociations(pid int32) ([]*SCTPAssociation, error) {
+	file, err := os.Open(host.GetHostProcInHost(fmt.Sprintf("%d/net/sctp/assocs", pid)))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseSCTPAssociations(file)
+}
+
+// SocketInode read the inode of the socket from the file descriptor link, such as "socket:[12345]"
+func SocketInode(pid, fd uint32) (uint64, error) {
+	link, err := os.Readlink(host.GetHostProcInHost(fmt.Sprintf("%d/fd/%d", pid, fd)))
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(link, "socket:[") || !strings.HasSuffix(link, "]") {
+		return 0, fmt.Errorf("the file descriptor is not socket: %s", link)
+	}
+	return strconv.ParseUint(link[len("socket:["):len(link)-1], 10, 64)
+}
+
+// ParseSCTPSocket build the socket pair of the SCTP socket from the associations, the addresses are the primary addresses,
+// the one-to-many style socket uses the first association since it has no peer address in the socket
+func ParseSCTPSocket(pid, sockfd uint32) (*SocketPair, error) {
+	inode, err := SocketInode(pid, sockfd)
+	if err != nil {
+		return nil, err
+	}
+	associations, err := ReadSCTPAssociations(int32(pid))
+	if err != nil {
+		return nil, err
+	}
+	for _, assoc := range associations {
+		if assoc.Inode != inode || len(assoc.LocalAddresses) == 0 || len(assoc.RemoteAddresses) == 0 {
+			continue
+		}
+		return &SocketPair{
+			Family:   Family(assoc.LocalAddresses[0]),
+			SrcIP:    assoc.LocalAddresses[0],
+			SrcPort:  assoc.LocalPort,
+			DestIP:   assoc.RemoteAddresses[0],
+			DestPort: assoc.RemotePort,
+		}, nil
+	}
+	return nil, fmt.Errorf("cannot found the SCTP association, pid: %d, socket FD: %d", pid, sockfd)
+}
+
+func parseSCTPAssociations(reader io.Reader) ([]*SCTPAssociation, error) {
+	result := make([]*SCTPAssociation, 0)
+	scanner := bufio.NewScanner(reader)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		assoc, err := parseSCTPAssociation(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, assoc)
+	}
+	return result, scanner.Err()
+}
+
+// parseSCTPAssociation parse the line as the following format:
+// ASSOC SOCK STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS
+// HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+func parseSCTPAssociation(line string) (*SCTPAssociation, error) {
+	local, remote, found := strings.Cut(line, "<->")
+	if !found {
+		return nil, fmt.Errorf("illegal sctp association: %s", line)
+	}
+	prefix, remoteFields := strings.Fields(local), strings.Fields(remote)
+	if len(prefix) <= sctpAssocPrefixFields || len(remoteFields) <= sctpAssocSuffixFields {
+		return nil, fmt.Errorf("illegal sctp association: %s", line)
+	}
+	suffix := remoteFields[len(remoteFields)-sctpAssocSuffixFields:]
+	numbers := make([]uint64, 0, 10)
+	for _, field := range []string{prefix[4], prefix[6], prefix[7], prefix[8], prefix[10], prefix[11], prefix[12],
+		suffix[1], suffix[2], suffix[6]} {
+		n, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("illegal sctp association field %s: %v", field, err)
+		}
+		numbers = append(numbers, n)
+	}
+	state := "unknown"
+	if numbers[0] < uint64(len(sctpAssociationStates)) {
+		state = sctpAssociationStates[numbers[0]]
+	}
+	return &SCTPAssociation{
+		ID:              uint32(numbers[1]),
+		State:           state,
+		TxQueue:         numbers[2],
+		RxQueue:         numbers[3],
+		Inode:           numbers[4],
+		LocalPort:       uint16(numbers[5]),
+		RemotePort:      uint16(numbers[6]),
+		LocalAddresses:  sortPrimaryAddress(prefix[sctpAssocPrefixFields:]),
+		RemoteAddresses: sortPrimaryAddress(remoteFields[:len(remoteFields)-sctpAssocSuffixFields]),
+		InStreams:       uint32(numbers[7]),
+		OutStreams:      uint32(numbers[8]),
+		Retransmits:     numbers[9],
+	}, nil
+}
+
+// sortPrimaryAddress move the primary address(starts with "*") to the first
+func sortPrimaryAddress(addresses []string) []string {
+	result := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		if primary := strings.TrimPrefix(addr, "*"); primary != addr {
+			result = append([]string{primary}, result...)
+			continue
+		}
+		result = append(result, addr)
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ip
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSCTPAssociations(t *testing.T) {
+	content := ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+       0        0 2   1   3  0        2      512        0       0 36421 3868  40000  *10.0.0.1 10.1.0.1 <-> *10.0.0.2 10.1.0.2 	   30000    10    10   10    0    0       3        1      512   212992   212992
+       0        0 1   10  1  1        0        0        0       0 36422 45000 3868  fd00::1 <-> *fd00::2 	   30000     2     2   10    1    0        0        1        0   212992   212992
+`
+	assocs, err := parseSCTPAssociations(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*SCTPAssociation{
+		{ID: 2, State: "established", Inode: 36421, LocalPort: 3868, LocalAddresses: []string{"10.0.0.1", "10.1.0.1"},
+			RemotePort: 40000, RemoteAddresses: []string{"10.0.0.2", "10.1.0.2"}, TxQueue: 512, InStreams: 10, OutStreams: 10, Retransmits: 3},
+		{ID: 0, State: "cookie_wait", Inode: 36422, LocalPort: 45000, LocalAddresses: []string{"fd00::1"},
+			RemotePort: 3868, RemoteAddresses: []string{"fd00::2"}, InStreams: 2, OutStreams: 2},
+	}
+	if !reflect.DeepEqual(assocs, expected) {
+		t.Fatalf("expected: %v, actual: %v", expected, assocs)
+	}
+
+	if _, err := parseSCTPAssociations(strings.NewReader("header\nillegal line\n")); err == nil {
+		t.Fatal("should be failure when the line is illegal")
+	}
+}
@@ -36,6 +36,9 @@ type SocketPair struct {
 	DestPort uint16
 
 	NeedConnTrack bool
+	// the original destination address before NAT, only exists when the address is updated by conntrack
+	OriginalDestIP   string
+	OriginalDestPort uint16
 }
 
 func (s *SocketPair) IsValid() bool {
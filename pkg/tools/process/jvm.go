@@ -0,0 +1,135 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build linux
+
+package process
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	host2 "github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/path"
+)
+
+var (
+	javaAttachTimeout = 5 * time.Second
+	javaAttachRetry   = 100 * time.Millisecond
+)
+
+// IsJavaProcess check the executable file of the process is java
+func IsJavaProcess(pid int32) bool {
+	exe, err := os.Readlink(host2.GetHostProcInHost(fmt.Sprintf("%d/exe", pid)))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(filepath.Base(exe), "java")
+}
+
+// DumpJavaPerfMap is triggering the JVM to write the JIT symbols into the perf map file,
+// by executing the "Compiler.perfmap" command through the JVM attach mechanism, which requires JDK 17+
+func DumpJavaPerfMap(pid int32) error {
+	conn, err := attachJVM(pid)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(javaAttachTimeout))
+
+	// protocol version, command, and three arguments, each ends with '\0'
+	if _, err = conn.Write([]byte("1\x00jcmd\x00Compiler.perfmap\x00\x00\x00")); err != nil {
+		return fmt.Errorf("send the perf map command to JVM failure: %v", err)
+	}
+	resp, err := io.ReadAll(conn)
+	if err != nil {
+		return fmt.Errorf("read the perf map command response failure: %v", err)
+	}
+	status, message, _ := bytes.Cut(resp, []byte("\n"))
+	if string(status) != "0" {
+		return fmt.Errorf("the JVM execute the perf map command failure, status: %s, message: %s",
+			status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func attachJVM(pid int32) (net.Conn, error) {
+	nspid := NamespacePid(pid)
+	tmpDir := host2.GetHostProcInHost(fmt.Sprintf("%d/root/tmp", pid))
+	socketPath := filepath.Join(tmpDir, fmt.Sprintf(".java_pid%d", nspid))
+	if !path.Exists(socketPath) {
+		if err := startJVMAttachListener(pid, tmpDir, nspid, socketPath); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := net.DialTimeout("unix", socketPath, javaAttachTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connect to the JVM attach socket failure: %s, %v", socketPath, err)
+	}
+	return conn, nil
+}
+
+// startJVMAttachListener creates the attach trigger file and send the SIGQUIT to the JVM,
+// then waiting for the attach listener socket been created
+func startJVMAttachListener(pid int32, tmpDir string, nspid int32, socketPath string) error {
+	attachFile := filepath.Join(tmpDir, fmt.Sprintf(".attach_pid%d", nspid))
+	if err := os.WriteFile(attachFile, nil, 0o600); err != nil {
+		return fmt.Errorf("create the JVM attach file failure: %v", err)
+	}
+	defer os.Remove(attachFile)
+	// the JVM only accept the attach file which owned by the same user
+	if uid, gid, err := processOwner(pid); err == nil {
+		_ = os.Chown(attachFile, uid, gid)
+	}
+
+	if err := syscall.Kill(int(pid), syscall.SIGQUIT); err != nil {
+		return fmt.Errorf("send the SIGQUIT to JVM failure: %v", err)
+	}
+	for deadline := time.Now().Add(javaAttachTimeout); time.Now().Before(deadline); time.Sleep(javaAttachRetry) {
+		if path.Exists(socketPath) {
+			return nil
+		}
+	}
+	return fmt.Errorf("wait the JVM attach listener timeout, process: %d", pid)
+}
+
+func processOwner(pid int32) (uid, gid int, err error) {
+	uids, err := readStatusField(pid, "Uid")
+	if err != nil || len(uids) < 2 {
+		return 0, 0, fmt.Errorf("could not read the uid of process: %d", pid)
+	}
+	gids, err := readStatusField(pid, "Gid")
+	if err != nil || len(gids) < 2 {
+		return 0, 0, fmt.Errorf("could not read the gid of process: %d", pid)
+	}
+	// using the effective user and group
+	if uid, err = strconv.Atoi(uids[1]); err != nil {
+		return 0, 0, err
+	}
+	if gid, err = strconv.Atoi(gids[1]); err != nil {
+		return 0, 0, err
+	}
+	return uid, gid, nil
+}
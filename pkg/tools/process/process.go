@@ -33,9 +33,10 @@ import (
 
 var (
 	// NotSupportProfilingExe mean which program are not support for profiling
-	// Not Support JIT and Script language for now
+	// Not Support Script language for now, the JIT language(such as java and node) is symbolized through the perf map file,
+	// and the python frames is walking by the ON_CPU profiling
 	NotSupportProfilingExe = []string{
-		"java", "python", "node", "bash", "ruby", "ssh",
+		"bash", "ruby", "ssh",
 	}
 
 	// executable file profiling finders
@@ -46,6 +47,9 @@ var (
 	// kernel profiling finder
 	kernelFinder = profiling.NewKernelFinder()
 
+	// JIT symbols finder(/tmp/perf-{pid}.map)
+	perfMapFinder = profiling.NewPerfMap()
+
 	// process map file analyze(/proc/{pid}/maps)
 	mapFileContentRegex = regexp.MustCompile("(?P<StartAddr>[a-f\\d]+)\\-(?P<EndAddr>[a-f\\d]+)\\s(?P<Perm>[^\\s]+)" +
 		"\\s(?P<Offset>[a-f\\d]+)\\s[a-f\\d]+\\:[a-f\\d]+\\s\\d+\\s+(?P<Name>[^\\n]+)")
@@ -142,9 +146,79 @@ func analyzeProfilingInfo(context *analyzeContext, pid int32) (*profiling.Info,
 		}
 		modules[moduleName] = module
 	}
+
+	// the symbols of JIT code only exist in the perf map file
+	if module := analyzePerfMapModule(pid); module != nil {
+		modules[module.Name] = module
+	}
+	return profiling.NewInfo(modules), nil
+}
+
+// PerfMapFilePath is the perf map file path(/tmp/perf-<pid>.map) of the process, the pid is in the process namespace
+func PerfMapFilePath(pid int32) string {
+	return host2.GetHostProcInHost(fmt.Sprintf("%d/root/tmp/perf-%d.map", pid, NamespacePid(pid)))
+}
+
+// RefreshPerfMap re-read the perf map file and build a new profiling info with the latest JIT symbols
+func RefreshPerfMap(pid int32, info *profiling.Info) (*profiling.Info, error) {
+	module := analyzePerfMapModule(pid)
+	if module == nil {
+		return nil, fmt.Errorf("could not read the perf map file: %s", PerfMapFilePath(pid))
+	}
+	modules := make(map[string]*profiling.Module)
+	if info != nil {
+		for _, m := range info.Modules {
+			if m.Type != profiling.ModuleTypePerfMap {
+				modules[m.Name] = m
+			}
+		}
+	}
+	modules[module.Name] = module
 	return profiling.NewInfo(modules), nil
 }
 
+func analyzePerfMapModule(pid int32) *profiling.Module {
+	modulePath := PerfMapFilePath(pid)
+	if !path.Exists(modulePath) {
+		return nil
+	}
+	module, err := perfMapFinder.ToModule(pid, fmt.Sprintf("/tmp/perf-%d.map", NamespacePid(pid)), modulePath, nil)
+	if err != nil {
+		log.Warnf("could not read the perf map file of process(%d): %s, error: %v", pid, modulePath, err)
+		return nil
+	}
+	return module
+}
+
+// NamespacePid read the pid of process in the innermost pid namespace, return the original pid if not found
+func NamespacePid(pid int32) int32 {
+	nspid, err := readStatusField(pid, "NSpid")
+	if err != nil || len(nspid) == 0 {
+		return pid
+	}
+	val, err := strconv.ParseInt(nspid[len(nspid)-1], 10, 32)
+	if err != nil {
+		return pid
+	}
+	return int32(val)
+}
+
+func readStatusField(pid int32, name string) ([]string, error) {
+	file, err := os.Open(host2.GetHostProcInHost(fmt.Sprintf("%d/status", pid)))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, val, found := strings.Cut(scanner.Text(), ":")
+		if found && key == name {
+			return strings.Fields(val), nil
+		}
+	}
+	return nil, fmt.Errorf("could not found the %s in the status of process: %d", name, pid)
+}
+
 func parseUInt64InModule(err error, moduleName, key, val string) (uint64, error) {
 	if err != nil {
 		return 0, err
@@ -0,0 +1,127 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package procfs is a shared layer for reading the "/proc" of host, the result would be cached in a short time,
+// so the multiple modules scanning the processes at the same time only read the "/proc" once.
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+var (
+	// ProcessListCacheTime is the time of reusing the latest process list
+	ProcessListCacheTime = time.Second * 3
+	// FileCacheTime is the time of reusing the file content of process, the network files("net/*") are never cached
+	FileCacheTime = time.Second * 3
+
+	processList = &processListCache{}
+	fileCache   = cache.NewExpiring()
+)
+
+type processListCache struct {
+	lock     sync.Mutex
+	pids     []int32
+	readTime time.Time
+}
+
+// Processes list all the processes in the host, the process instances are always created for each call,
+// so the callers could use them without affecting each other
+func Processes() ([]*process.Process, error) {
+	pids, err := processList.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*process.Process, 0, len(pids))
+	for _, pid := range pids {
+		// the process is already exists, so no needs to check it again by "process.NewProcess"
+		result = append(result, &process.Process{Pid: pid})
+	}
+	return result, nil
+}
+
+func (c *processListCache) load() ([]int32, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.pids != nil && time.Since(c.readTime) < ProcessListCacheTime {
+		return c.pids, nil
+	}
+
+	entries, err := os.ReadDir(host.GetHostProcInHost(""))
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int32, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, int32(pid))
+	}
+	c.pids = pids
+	c.readTime = time.Now()
+	return pids, nil
+}
+
+// ReadFile read the file of the process, the returned data is shared with the other callers and must not be modified
+func ReadFile(pid int32, name string) ([]byte, error) {
+	filePath := host.GetHostProcInHost(fmt.Sprintf("%d/%s", pid, name))
+	// the network state(such as the listening sockets) changes quickly, so always read the latest one
+	cacheable := !strings.HasPrefix(name, "net/")
+	if cacheable {
+		if data, exist := fileCache.Get(filePath); exist {
+			return data.([]byte), nil
+		}
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		fileCache.Set(filePath, data, FileCacheTime)
+	}
+	return data, nil
+}
+
+// Exe read the executable file path of the process
+func Exe(pid int32) (string, error) {
+	filePath := host.GetHostProcInHost(fmt.Sprintf("%d/exe", pid))
+	if exe, exist := fileCache.Get(filePath); exist {
+		return exe.(string), nil
+	}
+	exe, err := os.Readlink(filePath)
+	if err != nil {
+		return "", err
+	}
+	fileCache.Set(filePath, exe, FileCacheTime)
+	return exe, nil
+}
@@ -0,0 +1,69 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+func TestProcessesNotShared(t *testing.T) {
+	first, err := Processes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) == 0 {
+		t.Fatalf("the processes should not be empty")
+	}
+	pid := first[0].Pid
+	first[0].Pid = -1
+	second, err := Processes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first[0] == second[0] || second[0].Pid != pid {
+		t.Fatalf("the processes should not be shared between the calls, expected pid: %d, actual: %d", pid, second[0].Pid)
+	}
+}
+
+func TestReadFileCache(t *testing.T) {
+	pid := int32(os.Getpid())
+	tests := []struct {
+		name   string
+		cached bool
+	}{
+		{name: "cgroup", cached: true},
+		{name: "cmdline", cached: true},
+		{name: "net/tcp", cached: false},
+		{name: "net/tcp6", cached: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadFile(pid, tt.name); err != nil {
+				t.Skipf("the file is not readable: %v", err)
+			}
+			_, cached := fileCache.Get(host.GetHostProcInHost(fmt.Sprintf("%d/%s", pid, tt.name)))
+			if cached != tt.cached {
+				t.Errorf("expected cached: %t, actual: %t", tt.cached, cached)
+			}
+		})
+	}
+}
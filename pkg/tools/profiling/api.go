@@ -22,6 +22,7 @@ import (
 	"regexp"
 
 	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/tools/elf"
 
 	"github.com/ianlancetaylor/demangle"
 )
@@ -62,11 +63,7 @@ type ModuleRange struct {
 }
 
 // Symbol of executable file
-type Symbol struct {
-	Name     string
-	Location uint64
-	Size     uint64
-}
+type Symbol = elf.Symbol
 
 type StatFinder interface {
 	// IsSupport to stat the executable file for profiling
@@ -186,6 +183,9 @@ func (m *Module) contains(addr uint64) (uint64, bool) {
 }
 
 func (m *Module) findAddr(offset uint64) *Symbol {
+	if m.Type == ModuleTypePerfMap {
+		return m.findSizedAddr(offset)
+	}
 	start := 0
 	end := len(m.Symbols) - 1
 	for start < end {
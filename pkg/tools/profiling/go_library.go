@@ -20,9 +20,9 @@ package profiling
 import (
 	"debug/elf"
 	"fmt"
-	"sort"
 	"strings"
 
+	elf2 "github.com/apache/skywalking-rover/pkg/tools/elf"
 	"github.com/apache/skywalking-rover/pkg/tools/path"
 )
 
@@ -51,25 +51,12 @@ func (l *GoLibrary) AnalyzeSymbols(filePath string) ([]*Symbol, error) {
 	}
 	defer file.Close()
 
-	// exist symbol data
-	symbols, _ := file.Symbols()
-	dySyms, _ := file.DynamicSymbols()
-	if len(symbols) == 0 && len(dySyms) == 0 {
-		return nil, nil
-	}
-	symbols = append(symbols, dySyms...)
-
-	// adapt symbol struct
-	data := make([]*Symbol, len(symbols))
-	for i, sym := range symbols {
-		data[i] = &Symbol{Name: sym.Name, Location: sym.Value, Size: sym.Size}
+	// the symbols are shared through the node-level cache
+	symbols, err := elf2.ReadSymbols(filePath, file)
+	if err != nil || len(symbols) == 0 {
+		return nil, err
 	}
-
-	sort.SliceStable(data, func(i, j int) bool {
-		return data[i].Location < data[j].Location
-	})
-
-	return data, nil
+	return symbols, nil
 }
 
 func (l *GoLibrary) ToModule(_ int32, modName, modPath string, moduleRange []*ModuleRange) (*Module, error) {
@@ -20,11 +20,13 @@ package profiling
 import (
 	"bufio"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/apache/skywalking-rover/pkg/tools/elf"
 	"github.com/apache/skywalking-rover/pkg/tools/host"
 )
 
@@ -46,10 +48,37 @@ func (k *KernelFinder) IsSupport(filepath string) bool {
 }
 
 func (k *KernelFinder) Analyze(filepath string) (*Info, error) {
+	// the kernel symbols only changed when the kernel modules loaded or unloaded
+	symbols, err := elf.LoadSymbols(kernelSymbolCacheKey(), func() ([]*Symbol, error) {
+		return k.readSymbols(filepath)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	kernelModule := &Module{
+		Name:    "kernel",
+		Symbols: symbols,
+		// kernel module could handling all symbols
+		Ranges: []*ModuleRange{
+			{
+				StartAddr: 0,
+				EndAddr:   math.MaxUint64,
+			},
+		},
+	}
+
+	return NewInfo(map[string]*Module{
+		"kernel": kernelModule,
+	}), nil
+}
+
+func (k *KernelFinder) readSymbols(filepath string) ([]*Symbol, error) {
 	kernelPath, err := os.Open(host.GetHostProcInHost(filepath))
 	if err != nil {
 		return nil, err
 	}
+	defer kernelPath.Close()
 
 	scanner := bufio.NewScanner(kernelPath)
 	symbols := make([]*Symbol, 0)
@@ -66,20 +95,16 @@ func (k *KernelFinder) Analyze(filepath string) (*Info, error) {
 			Size:     0,
 		})
 	}
+	return symbols, nil
+}
 
-	kernelModule := &Module{
-		Name:    "kernel",
-		Symbols: symbols,
-		// kernel module could handling all symbols
-		Ranges: []*ModuleRange{
-			{
-				StartAddr: 0,
-				EndAddr:   math.MaxUint64,
-			},
-		},
+// kernelSymbolCacheKey is the hash of the loaded kernel modules, return empty string(not cache) if could not read
+func kernelSymbolCacheKey() string {
+	modules, err := os.ReadFile(host.GetHostProcInHost("modules"))
+	if err != nil {
+		return ""
 	}
-
-	return NewInfo(map[string]*Module{
-		"kernel": kernelModule,
-	}), nil
+	h := fnv.New64a()
+	_, _ = h.Write(modules)
+	return fmt.Sprintf("kallsyms:%x", h.Sum64())
 }
@@ -0,0 +1,129 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package profiling
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PerfMap is reading the symbols from the perf map file(/tmp/perf-<pid>.map),
+// which is written by the JIT runtime, such as the JVM with perf-map-agent or async-profiler
+type PerfMap struct {
+}
+
+func NewPerfMap() *PerfMap {
+	return &PerfMap{}
+}
+
+func (p *PerfMap) IsSupport(filePath string) bool {
+	name := filepath.Base(filePath)
+	return strings.HasPrefix(name, "perf-") && strings.HasSuffix(name, ".map")
+}
+
+func (p *PerfMap) AnalyzeSymbols(filePath string) ([]*Symbol, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parsePerfMapSymbols(bufio.NewScanner(file))
+}
+
+func (p *PerfMap) ToModule(_ int32, modName, modPath string, _ []*ModuleRange) (*Module, error) {
+	symbols, err := p.AnalyzeSymbols(modPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("could not found any symbol in the perf map file: %s", modPath)
+	}
+
+	// the address in the perf map file is absolute, so the module range is covering all symbols
+	moduleRange := &ModuleRange{StartAddr: symbols[0].Location}
+	for _, s := range symbols {
+		if end := s.Location + s.Size; end > moduleRange.EndAddr {
+			moduleRange.EndAddr = end
+		}
+	}
+	return &Module{
+		Name:    modName,
+		Path:    modPath,
+		Type:    ModuleTypePerfMap,
+		Ranges:  []*ModuleRange{moduleRange},
+		Symbols: symbols,
+	}, nil
+}
+
+// parsePerfMapSymbols parsing each line as "START SIZE symbol name", the address and size are hex format
+func parsePerfMapSymbols(scanner *bufio.Scanner) ([]*Symbol, error) {
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	symbols := make([]*Symbol, 0)
+	for scanner.Scan() {
+		fields := strings.SplitN(strings.TrimSpace(scanner.Text()), " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		location, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseUint(strings.TrimPrefix(fields[1], "0x"), 16, 64)
+		if err != nil || size == 0 {
+			continue
+		}
+		symbols = append(symbols, &Symbol{Name: fields[2], Location: location, Size: size})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// the JIT runtime appends the recompiled code to the end of file,
+	// so the latest symbol is used when the address has been reused
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Location < symbols[j].Location
+	})
+	result := make([]*Symbol, 0, len(symbols))
+	for _, s := range symbols {
+		if len(result) > 0 && result[len(result)-1].Location == s.Location {
+			result[len(result)-1] = s
+			continue
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// findSizedAddr finding the symbol which contains the address, the gap between JIT codes is not belong to any symbol
+func (m *Module) findSizedAddr(addr uint64) *Symbol {
+	inx := sort.Search(len(m.Symbols), func(i int) bool {
+		return m.Symbols[i].Location > addr
+	})
+	if inx == 0 {
+		return nil
+	}
+	if s := m.Symbols[inx-1]; addr < s.Location+s.Size {
+		return s
+	}
+	return nil
+}
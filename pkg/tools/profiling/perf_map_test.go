@@ -0,0 +1,64 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package profiling
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestPerfMapSymbols(t *testing.T) {
+	content := `7f3c1c000100 40 Interpreter
+7f3c1c000200 80 LFoo;::bar
+invalid line
+7f3c1c000400 0 empty size
+7f3c1c000200 90 LFoo;::bar (recompiled)
+0x7f3c1c000300 20 Lcom/example/App;::main ([Ljava/lang/String;)V
+`
+	symbols, err := parsePerfMapSymbols(bufio.NewScanner(strings.NewReader(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 3 {
+		t.Fatalf("the symbols count should be 3, current: %d", len(symbols))
+	}
+	module := &Module{Type: ModuleTypePerfMap, Symbols: symbols}
+
+	tests := []struct {
+		addr uint64
+		name string
+	}{
+		{addr: 0x7f3c1c000100, name: "Interpreter"},
+		{addr: 0x7f3c1c00013f, name: "Interpreter"},
+		{addr: 0x7f3c1c000140, name: ""},
+		{addr: 0x7f3c1c000288, name: "LFoo;::bar (recompiled)"},
+		{addr: 0x7f3c1c000310, name: "Lcom/example/App;::main ([Ljava/lang/String;)V"},
+		{addr: 0x7f3c1c000320, name: ""},
+		{addr: 0x10, name: ""},
+	}
+	for _, tt := range tests {
+		name := ""
+		if s := module.findAddr(tt.addr); s != nil {
+			name = s.Name
+		}
+		if name != tt.name {
+			t.Errorf("address %x should be symbol %q, but current is %q", tt.addr, tt.name, name)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package profiling
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+var pythonModuleRegex = regexp.MustCompile(`^(?:libpython|python)(3\.\d+)`)
+
+// PythonOffsets of the CPython structures in the x86_64 platform
+type PythonOffsets struct {
+	// the offset of "gilstate.tstate_current" in the "_PyRuntime"
+	TStateCurrent uint64
+	// the offset of the top frame in the PyThreadState, it's the "cframe" since python 3.11
+	TStateFrame uint32
+	// the offset of the current frame in the _PyCFrame, 0 means the frame is read from the thread state directly
+	CFrameCurrentFrame uint32
+	FrameBack          uint32
+	FrameCode          uint32
+	// the offsets in the PyCodeObject
+	CodeFilename    uint64
+	CodeName        uint64
+	CodeFirstLineNo uint64
+}
+
+var pythonVersionOffsets = map[string]*PythonOffsets{
+	"3.8": {TStateCurrent: 1368, TStateFrame: 24, FrameBack: 24, FrameCode: 32,
+		CodeFilename: 104, CodeName: 112, CodeFirstLineNo: 40},
+	"3.9": {TStateCurrent: 568, TStateFrame: 24, FrameBack: 24, FrameCode: 32,
+		CodeFilename: 104, CodeName: 112, CodeFirstLineNo: 40},
+	"3.10": {TStateCurrent: 568, TStateFrame: 24, FrameBack: 24, FrameCode: 32,
+		CodeFilename: 104, CodeName: 112, CodeFirstLineNo: 40},
+	"3.11": {TStateCurrent: 576, TStateFrame: 56, CFrameCurrentFrame: 8, FrameBack: 48, FrameCode: 32,
+		CodeFilename: 112, CodeName: 120, CodeFirstLineNo: 72},
+}
+
+// PythonRuntime is the CPython interpreter of the process
+type PythonRuntime struct {
+	Version string
+	// the address of the current running thread state pointer in the process
+	TStateCurrentAddr uint64
+	Offsets           *PythonOffsets
+}
+
+// FindPythonRuntime find the CPython interpreter module(executable file or libpython) and locate the runtime state
+func (i *Info) FindPythonRuntime() (*PythonRuntime, error) {
+	for _, m := range i.Modules {
+		submatch := pythonModuleRegex.FindStringSubmatch(filepath.Base(m.Name))
+		if len(submatch) != 2 {
+			continue
+		}
+		var runtimeSymbol *Symbol
+		for _, s := range m.Symbols {
+			if s.Name == "_PyRuntime" {
+				runtimeSymbol = s
+				break
+			}
+		}
+		if runtimeSymbol == nil {
+			continue
+		}
+		offsets := pythonVersionOffsets[submatch[1]]
+		if offsets == nil {
+			return nil, fmt.Errorf("not support the python version: %s", submatch[1])
+		}
+		addr, err := m.RuntimeAddress(runtimeSymbol.Location)
+		if err != nil {
+			return nil, err
+		}
+		return &PythonRuntime{
+			Version:           submatch[1],
+			TStateCurrentAddr: addr + offsets.TStateCurrent,
+			Offsets:           offsets,
+		}, nil
+	}
+	return nil, fmt.Errorf("could not found the python runtime")
+}
+
+// RuntimeAddress convert the symbol address in the module file to the virtual address in the process
+func (m *Module) RuntimeAddress(symbolAddr uint64) (uint64, error) {
+	if m.Type == ModuleTypeExec {
+		return symbolAddr, nil
+	}
+	if m.Type != ModuleTypeSo || len(m.Ranges) == 0 {
+		return 0, fmt.Errorf("could not calculate the runtime address in module: %s", m.Name)
+	}
+	// the executable range is mapping from the file offset, and the .text section is loaded with the (address - offset) delta
+	r := m.Ranges[0]
+	return r.StartAddr - r.FileOffset - (m.SoAddr - m.SoOffset) + symbolAddr, nil
+}
@@ -0,0 +1,76 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package profiling
+
+import "testing"
+
+func TestFindPythonRuntime(t *testing.T) {
+	tests := []struct {
+		name    string
+		module  *Module
+		version string
+		addr    uint64
+		err     bool
+	}{
+		{
+			name: "shared library",
+			module: &Module{Name: "/usr/lib/x86_64-linux-gnu/libpython3.10.so.1.0", Type: ModuleTypeSo,
+				SoAddr: 0x6d000, SoOffset: 0x6c000,
+				Ranges:  []*ModuleRange{{StartAddr: 0x7f0000070000, EndAddr: 0x7f0000300000, FileOffset: 0x6c000}},
+				Symbols: []*Symbol{{Name: "_PyRuntime", Location: 0x550000}}},
+			version: "3.10",
+			addr:    0x7f0000070000 - 0x6c000 - 0x1000 + 0x550000 + 568,
+		},
+		{
+			name: "executable file",
+			module: &Module{Name: "/usr/local/bin/python3.11", Type: ModuleTypeExec,
+				Symbols: []*Symbol{{Name: "_PyRuntime", Location: 0x900000}}},
+			version: "3.11",
+			addr:    0x900000 + 576,
+		},
+		{
+			name: "not support version",
+			module: &Module{Name: "/usr/bin/python3.6", Type: ModuleTypeExec,
+				Symbols: []*Symbol{{Name: "_PyRuntime", Location: 0x900000}}},
+			err: true,
+		},
+		{
+			name:   "not python",
+			module: &Module{Name: "/usr/bin/node", Type: ModuleTypeExec, Symbols: []*Symbol{{Name: "main", Location: 0x1000}}},
+			err:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := NewInfo(map[string]*Module{tt.module.Name: tt.module})
+			runtime, err := info.FindPythonRuntime()
+			if tt.err {
+				if err == nil {
+					t.Fatalf("should return error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if runtime.Version != tt.version || runtime.TStateCurrentAddr != tt.addr {
+				t.Fatalf("the runtime should be %s/%x, current: %s/%x", tt.version, tt.addr, runtime.Version, runtime.TStateCurrentAddr)
+			}
+		})
+	}
+}
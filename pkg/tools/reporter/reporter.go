@@ -0,0 +1,141 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+
+	"google.golang.org/grpc"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+	logv3 "skywalking.apache.org/repo/goapi/collect/logging/v3"
+)
+
+var log = logger.GetLogger("tools", "reporter")
+
+// Periodic is calling the report function in every period, until it's stopped or the context is done
+type Periodic struct {
+	cancel context.CancelFunc
+}
+
+// StartPeriodic start reporting in the background, the name is used in the failure logs
+func StartPeriodic(ctx context.Context, name string, period time.Duration, report func(ctx context.Context) error) *Periodic {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := report(ctx); err != nil {
+					log.Warnf("report the %s failure: %v", name, err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &Periodic{cancel: cancel}
+}
+
+// Stop the reporting, it's fine to stop the not started reporting
+func (p *Periodic) Stop() {
+	if p != nil && p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// MeterSender send the meters to the backend, each sending uses a new stream
+type MeterSender struct {
+	name   string
+	client v3.MeterReportServiceClient
+}
+
+func NewMeterSender(name string, conn grpc.ClientConnInterface) *MeterSender {
+	return &MeterSender{name: name, client: v3.NewMeterReportServiceClient(conn)}
+}
+
+// Send the meter collections, nothing is sent when the collections are empty
+func (s *MeterSender) Send(ctx context.Context, collections []*v3.MeterDataCollection) error {
+	if len(collections) == 0 {
+		return nil
+	}
+	batch, err := s.client.CollectBatch(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, e := batch.CloseAndRecv(); e != nil {
+			log.Warnf("close the %s stream error: %v", s.name, e)
+		}
+	}()
+	for _, collection := range collections {
+		if err := batch.Send(collection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogSender send the logs to the backend, each sending uses a new stream
+type LogSender struct {
+	name   string
+	client logv3.LogReportServiceClient
+}
+
+func NewLogSender(name string, conn grpc.ClientConnInterface) *LogSender {
+	return &LogSender{name: name, client: logv3.NewLogReportServiceClient(conn)}
+}
+
+// Send the logs, nothing is sent when the logs are empty
+func (s *LogSender) Send(ctx context.Context, logs []*logv3.LogData) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	collector, err := s.client.Collect(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, e := collector.CloseAndRecv(); e != nil {
+			log.Warnf("close the %s stream error: %v", s.name, e)
+		}
+	}()
+	for _, l := range logs {
+		if err := collector.Send(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SingleValueMeter build the single value meter with the labels
+func SingleValueMeter(name string, labels []*v3.Label, value float64) *v3.MeterData {
+	return &v3.MeterData{
+		Metric: &v3.MeterData_SingleValue{
+			SingleValue: &v3.MeterSingleValue{
+				Name:   name,
+				Labels: labels,
+				Value:  value,
+			},
+		},
+	}
+}
@@ -0,0 +1,91 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxSize    int64
+		maxBackups int
+		writes     []string
+		expected   map[string]string
+		removed    []string
+	}{
+		{
+			name:       "keep the backups",
+			maxSize:    10,
+			maxBackups: 2,
+			writes:     []string{"first\n", "second\n", "third\n", "fourth\n"},
+			expected:   map[string]string{"": "fourth\n", ".1": "third\n", ".2": "second\n"},
+			removed:    []string{".3"},
+		},
+		{
+			name:       "no backups",
+			maxSize:    10,
+			maxBackups: 0,
+			writes:     []string{"first\n", "second\n"},
+			expected:   map[string]string{"": "second\n"},
+			removed:    []string{".1"},
+		},
+		{
+			name:       "never rotate",
+			maxSize:    0,
+			maxBackups: 2,
+			writes:     []string{"first\n", "second\n"},
+			expected:   map[string]string{"": "first\nsecond\n"},
+			removed:    []string{".1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "rover.log")
+			writer, err := NewWriter(path, tt.maxSize, tt.maxBackups)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, content := range tt.writes {
+				if _, err = writer.Write([]byte(content)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err = writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+			for suffix, content := range tt.expected {
+				data, err := os.ReadFile(path + suffix)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != content {
+					t.Errorf("file %s expected content: %q, actual: %q", path+suffix, content, string(data))
+				}
+			}
+			for _, suffix := range tt.removed {
+				if _, err := os.Stat(path + suffix); !os.IsNotExist(err) {
+					t.Errorf("file %s should not exist", path+suffix)
+				}
+			}
+		})
+	}
+}
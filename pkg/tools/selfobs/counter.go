@@ -0,0 +1,195 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfobs
+
+import (
+	"sort"
+	"sync"
+)
+
+// ServiceName is the service name of the meters which describe the rover itself
+const ServiceName = "rover"
+
+const (
+	// QueueDropped counts the data dropped by the full queues, labeled by the "queue"
+	QueueDropped = "rover_queue_dropped_counter"
+	// PerfBufferLost counts the samples lost by the full perf event buffers, labeled by the "map"
+	PerfBufferLost = "rover_perf_buffer_lost_counter"
+	// BackendReconnect counts the reconnection to the backend
+	BackendReconnect = "rover_backend_reconnect_counter"
+	// ProbeFailure counts the BPF programs failed to attach, labeled by the probe "type"
+	ProbeFailure = "rover_probe_failure_counter"
+	// QueueSubmitFailure counts the events failed to submit into the queues from the kernel, labeled by the "module"
+	QueueSubmitFailure = "rover_queue_submit_failure_counter"
+	// EventRead counts the events read from the BPF perf event buffers, labeled by the "map"
+	EventRead = "rover_bpf_event_read_counter"
+	// FlushError counts the failures of flushing the data to the backend, labeled by the "sender"
+	FlushError = "rover_flush_error_counter"
+	// FlushDuration summaries the milliseconds of flushing the data to the backend, labeled by the "sender"
+	FlushDuration = "rover_flush_duration_milliseconds"
+	// MapUtilization gauges the ratio of used entries in the BPF maps, labeled by the "map"
+	MapUtilization = "rover_bpf_map_utilization"
+	// DataSamplingRate gauges the percentage of the connections whose socket data is kept, labeled by the "module"
+	DataSamplingRate = "rover_data_sampling_rate"
+	// ThrottleLevel gauges the load shedding level decided by the resource governor, 0 means not throttled
+	ThrottleLevel = "rover_throttle_level"
+	// CollectorPaused gauges whether the collector is paused by the resource governor, labeled by the "collector"
+	CollectorPaused = "rover_collector_paused"
+	// KernelCapability gauges whether the kernel capability is supported, labeled by the "capability"
+	KernelCapability = "rover_kernel_capability"
+	// ZTunnelMappingCache counts the lookups and removals of the ztunnel IP mapping cache, labeled by the "event",
+	// the event is "hit", "miss", "eviction"(evicted by the max entries) or "expired"
+	ZTunnelMappingCache = "rover_ztunnel_mapping_cache_counter"
+	// ZTunnelMappingCacheSize gauges the count of the mappings in the ztunnel IP mapping caches
+	ZTunnelMappingCacheSize = "rover_ztunnel_mapping_cache_size"
+	// ModuleStatus gauges the status of the module under the kernel capabilities, labeled by the "module",
+	// 0 means active, 1 means degraded, 2 means disabled
+	ModuleStatus = "rover_module_status"
+)
+
+// Sample is the accumulated value of a counter with the label
+type Sample struct {
+	Name       string
+	LabelName  string
+	LabelValue string
+	Value      int64
+}
+
+// Summary is the accumulated sum and count of the observed values with the label
+type Summary struct {
+	Name       string
+	LabelName  string
+	LabelValue string
+	Sum        float64
+	Count      int64
+}
+
+// Gauge is the current value of a registered gauge with the label
+type Gauge struct {
+	Name       string
+	LabelName  string
+	LabelValue string
+	Value      float64
+}
+
+type counterKey struct {
+	name       string
+	labelName  string
+	labelValue string
+}
+
+type summaryValue struct {
+	sum   float64
+	count int64
+}
+
+var (
+	counters    = make(map[counterKey]int64)
+	summaries   = make(map[counterKey]*summaryValue)
+	gauges      = make(map[counterKey]func() float64)
+	countersMux sync.Mutex
+)
+
+// Increase the counter, the label name could be empty when the counter has no label
+func Increase(name, labelName, labelValue string, delta int64) {
+	if delta == 0 {
+		return
+	}
+	countersMux.Lock()
+	defer countersMux.Unlock()
+	counters[counterKey{name: name, labelName: labelName, labelValue: labelValue}] += delta
+}
+
+// Samples returns the accumulated values of all counters since rover started, sorted by the name and label
+func Samples() []Sample {
+	countersMux.Lock()
+	result := make([]Sample, 0, len(counters))
+	for k, v := range counters {
+		result = append(result, Sample{Name: k.name, LabelName: k.labelName, LabelValue: k.labelValue, Value: v})
+	}
+	countersMux.Unlock()
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].LabelValue < result[j].LabelValue
+	})
+	return result
+}
+
+// Observe a value into the summary, such as the duration of an operation
+func Observe(name, labelName, labelValue string, value float64) {
+	countersMux.Lock()
+	defer countersMux.Unlock()
+	key := counterKey{name: name, labelName: labelName, labelValue: labelValue}
+	v := summaries[key]
+	if v == nil {
+		v = &summaryValue{}
+		summaries[key] = v
+	}
+	v.sum += value
+	v.count++
+}
+
+// Summaries returns the accumulated sum and count of all summaries since rover started, sorted by the name and label
+func Summaries() []Summary {
+	countersMux.Lock()
+	result := make([]Summary, 0, len(summaries))
+	for k, v := range summaries {
+		result = append(result, Summary{Name: k.name, LabelName: k.labelName, LabelValue: k.labelValue, Sum: v.sum, Count: v.count})
+	}
+	countersMux.Unlock()
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].LabelValue < result[j].LabelValue
+	})
+	return result
+}
+
+// RegisterGauge registers the supplier of the gauge value, it would be called when collecting the gauges,
+// the latter registration overrides the former one with the same name and label
+func RegisterGauge(name, labelName, labelValue string, supplier func() float64) {
+	countersMux.Lock()
+	defer countersMux.Unlock()
+	gauges[counterKey{name: name, labelName: labelName, labelValue: labelValue}] = supplier
+}
+
+// Gauges returns the current values of all registered gauges, sorted by the name and label
+func Gauges() []Gauge {
+	countersMux.Lock()
+	keys := make([]counterKey, 0, len(gauges))
+	suppliers := make([]func() float64, 0, len(gauges))
+	for k, v := range gauges {
+		keys = append(keys, k)
+		suppliers = append(suppliers, v)
+	}
+	countersMux.Unlock()
+	result := make([]Gauge, 0, len(keys))
+	for i, k := range keys {
+		result = append(result, Gauge{Name: k.name, LabelName: k.labelName, LabelValue: k.labelValue, Value: suppliers[i]()})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].LabelValue < result[j].LabelValue
+	})
+	return result
+}
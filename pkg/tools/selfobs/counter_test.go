@@ -0,0 +1,59 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfobs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSamples(t *testing.T) {
+	Increase(QueueDropped, "queue", "protocol", 2)
+	Increase(QueueDropped, "queue", "kernel", 1)
+	Increase(QueueDropped, "queue", "protocol", 3)
+	Increase(BackendReconnect, "", "", 1)
+	Increase(ProbeFailure, "type", "kprobe", 0)
+
+	expected := []Sample{
+		{Name: BackendReconnect, Value: 1},
+		{Name: QueueDropped, LabelName: "queue", LabelValue: "kernel", Value: 1},
+		{Name: QueueDropped, LabelName: "queue", LabelValue: "protocol", Value: 5},
+	}
+	if actual := Samples(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestSummariesAndGauges(t *testing.T) {
+	Observe(FlushDuration, "sender", "access_log", 10)
+	Observe(FlushDuration, "sender", "access_log", 5)
+	RegisterGauge(MapUtilization, "map", "socket_data", func() float64 { return 0.5 })
+
+	expectedSummaries := []Summary{
+		{Name: FlushDuration, LabelName: "sender", LabelValue: "access_log", Sum: 15, Count: 2},
+	}
+	if actual := Summaries(); !reflect.DeepEqual(actual, expectedSummaries) {
+		t.Fatalf("expected %v, actual %v", expectedSummaries, actual)
+	}
+	expectedGauges := []Gauge{
+		{Name: MapUtilization, LabelName: "map", LabelValue: "socket_data", Value: 0.5},
+	}
+	if actual := Gauges(); !reflect.DeepEqual(actual, expectedGauges) {
+		t.Fatalf("expected %v, actual %v", expectedGauges, actual)
+	}
+}
@@ -0,0 +1,132 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfprotect
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/skywalking-rover/pkg/tools/selfobs"
+)
+
+// Priority of the collectors registered into the governor, the lower priority collectors are paused earlier
+type Priority int
+
+const (
+	// PriorityLow collectors are paused since the payload inspection is disabled
+	PriorityLow Priority = iota
+	// PriorityNormal collectors are paused only when rover is downgraded to the layer 4 only
+	PriorityNormal
+	// PriorityHigh collectors are never paused
+	PriorityHigh
+)
+
+// shouldPause the collector with the priority in the load shedding level
+func (p Priority) shouldPause(level Level) bool {
+	switch p {
+	case PriorityLow:
+		return level >= LevelDisablePayload
+	case PriorityNormal:
+		return level >= LevelL4Only
+	default:
+		return false
+	}
+}
+
+// Governor shares the load shedding level of rover between all modules, so the modules could throttle the
+// event processing and pause the low priority collectors instead of competing the resources with the workloads
+type Governor struct {
+	guard *Guard
+
+	throttles    []*Throttle
+	throttlesMux sync.Mutex
+}
+
+// Throttle is the pausing state of a collector registered into the governor
+type Throttle struct {
+	name     string
+	priority Priority
+	paused   atomic.Bool
+}
+
+// NewGovernor creates the governor which follows the level changes of the guard
+func NewGovernor(guard *Guard) *Governor {
+	g := &Governor{guard: guard}
+	guard.OnLevelChange(g.levelChanged)
+	selfobs.RegisterGauge(selfobs.ThrottleLevel, "", "", func() float64 {
+		return float64(g.Level())
+	})
+	return g
+}
+
+// Guard returns the guard of the governor, nil when the governor is not active
+func (g *Governor) Guard() *Guard {
+	if g == nil {
+		return nil
+	}
+	return g.guard
+}
+
+// Level is the current load shedding level, always normal when the governor is not active
+func (g *Governor) Level() Level {
+	return g.Guard().Level()
+}
+
+// Register the collector with the priority, the returned throttle tells the collector should be paused or not,
+// nil governor returns nil throttle which is never paused
+func (g *Governor) Register(name string, priority Priority) *Throttle {
+	if g == nil {
+		return nil
+	}
+	t := &Throttle{name: name, priority: priority}
+	t.paused.Store(priority.shouldPause(g.Level()))
+	g.throttlesMux.Lock()
+	g.throttles = append(g.throttles, t)
+	g.throttlesMux.Unlock()
+	selfobs.RegisterGauge(selfobs.CollectorPaused, "collector", name, func() float64 {
+		if t.Paused() {
+			return 1
+		}
+		return 0
+	})
+	return t
+}
+
+func (g *Governor) levelChanged(_, to Level) {
+	g.throttlesMux.Lock()
+	defer g.throttlesMux.Unlock()
+	for _, t := range g.throttles {
+		paused := t.priority.shouldPause(to)
+		if t.paused.Swap(paused) == paused {
+			continue
+		}
+		if paused {
+			log.Warnf("pause the %s collector since the load shedding level of rover is %s", t.name, to)
+		} else {
+			log.Infof("resume the %s collector since the load shedding level of rover is %s", t.name, to)
+		}
+	}
+}
+
+// Paused returns true when the collector should skip the work in current period
+func (t *Throttle) Paused() bool {
+	if t == nil {
+		return false
+	}
+	return t.paused.Load()
+}
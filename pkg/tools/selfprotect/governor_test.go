@@ -0,0 +1,79 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfprotect
+
+import "testing"
+
+func TestGovernorPauseCollectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		levels   []Level
+		expected map[Priority]bool
+	}{
+		{
+			name:     "normal",
+			levels:   []Level{LevelNormal},
+			expected: map[Priority]bool{PriorityLow: false, PriorityNormal: false, PriorityHigh: false},
+		},
+		{
+			name:     "sampling",
+			levels:   []Level{LevelSampling},
+			expected: map[Priority]bool{PriorityLow: false, PriorityNormal: false, PriorityHigh: false},
+		},
+		{
+			name:     "disable payload",
+			levels:   []Level{LevelSampling, LevelDisablePayload},
+			expected: map[Priority]bool{PriorityLow: true, PriorityNormal: false, PriorityHigh: false},
+		},
+		{
+			name:     "l4 only",
+			levels:   []Level{LevelSampling, LevelDisablePayload, LevelL4Only},
+			expected: map[Priority]bool{PriorityLow: true, PriorityNormal: true, PriorityHigh: false},
+		},
+		{
+			name:     "recover",
+			levels:   []Level{LevelL4Only, LevelDisablePayload, LevelSampling},
+			expected: map[Priority]bool{PriorityLow: false, PriorityNormal: false, PriorityHigh: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Governor{}
+			throttles := make(map[Priority]*Throttle)
+			for p := range tt.expected {
+				throttles[p] = g.Register(tt.name, p)
+			}
+			current := LevelNormal
+			for _, l := range tt.levels {
+				g.levelChanged(current, l)
+				current = l
+			}
+			for p, expected := range tt.expected {
+				if actual := throttles[p].Paused(); actual != expected {
+					t.Fatalf("priority %d: expected paused %t, actual %t", p, expected, actual)
+				}
+			}
+		})
+	}
+
+	var governor *Governor
+	if governor.Register("nil", PriorityLow).Paused() {
+		t.Fatalf("the collector should never be paused when the governor is not active")
+	}
+}
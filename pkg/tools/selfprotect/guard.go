@@ -0,0 +1,168 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfprotect
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/shirou/gopsutil/process"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+)
+
+var log = logger.GetLogger("tools", "selfprotect")
+
+// Guard monitors the CPU and memory usage of rover itself, and decides the load shedding level
+// to prevent rover from being throttled or OOM-killed on the busy nodes
+type Guard struct {
+	process     *process.Process
+	cpuLimit    float64
+	memoryLimit uint64
+	high        float64
+	low         float64
+
+	level        atomic.Int32
+	lastCPUTime  float64
+	lastReadTime time.Time
+	listeners    []func(from, to Level)
+}
+
+// NewGuard creates the guard, the cpuLimit is the count of cores and the memoryLimit is the bytes of resident memory,
+// zero limit means the resource is not watched, the watermarks are the percentage(0-100) of the limits
+func NewGuard(cpuLimit float64, memoryLimit uint64, highWatermark, lowWatermark int) (*Guard, error) {
+	if cpuLimit <= 0 && memoryLimit == 0 {
+		return nil, fmt.Errorf("please provide the CPU or memory limit")
+	}
+	if highWatermark <= 0 || highWatermark > 100 || lowWatermark < 0 || lowWatermark >= highWatermark {
+		return nil, fmt.Errorf("the watermarks must be in (0, 100] and the low watermark must smaller than the high watermark")
+	}
+	p, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	return &Guard{
+		process:     p,
+		cpuLimit:    cpuLimit,
+		memoryLimit: memoryLimit,
+		high:        float64(highWatermark) / 100,
+		low:         float64(lowWatermark) / 100,
+	}, nil
+}
+
+// ParseLimits parses the CPU limit in cores and the memory limit in human-readable size(such as "1G"),
+// empty means the resource is not watched
+func ParseLimits(cpu, memory string) (cpuLimit float64, memoryLimit uint64, err error) {
+	if cpu != "" {
+		if cpuLimit, err = strconv.ParseFloat(cpu, 64); err != nil {
+			return 0, 0, fmt.Errorf("parse the CPU limit error: %v", err)
+		}
+	}
+	if memory != "" {
+		bytes, err := units.RAMInBytes(memory)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse the memory limit error: %v", err)
+		}
+		memoryLimit = uint64(bytes)
+	}
+	return cpuLimit, memoryLimit, nil
+}
+
+// Level is the current load shedding level
+func (g *Guard) Level() Level {
+	if g == nil {
+		return LevelNormal
+	}
+	return Level(g.level.Load())
+}
+
+// OnLevelChange adds the listener which is notified when the load shedding level is changed,
+// it should be added before the guard starts
+func (g *Guard) OnLevelChange(listener func(from, to Level)) {
+	g.listeners = append(g.listeners, listener)
+}
+
+// Start checking the resource usage with period until the context is done
+func (g *Guard) Start(ctx context.Context, period time.Duration) {
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := g.Check(time.Now()); err != nil {
+					log.Warnf("check the resource usage of rover failure: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Check reads the resource usage and updates the load shedding level
+func (g *Guard) Check(now time.Time) error {
+	usage, err := g.readUsage(now)
+	if err != nil {
+		return err
+	}
+	current := g.Level()
+	next := nextLevel(current, usage, g.high, g.low)
+	if next != current {
+		g.level.Store(int32(next))
+		log.Warnf("the resource usage of rover is %.1f%% of the limit, change the load shedding level from %s to %s",
+			usage*100, current, next)
+		for _, listener := range g.listeners {
+			listener(current, next)
+		}
+	}
+	return nil
+}
+
+// readUsage returns the highest usage ratio of the watched resources
+func (g *Guard) readUsage(now time.Time) (float64, error) {
+	var usage float64
+	if g.memoryLimit > 0 {
+		memory, err := g.process.MemoryInfo()
+		if err != nil {
+			return 0, err
+		}
+		usage = float64(memory.RSS) / float64(g.memoryLimit)
+	}
+	if g.cpuLimit > 0 {
+		times, err := g.process.Times()
+		if err != nil {
+			return 0, err
+		}
+		cpuTime := times.User + times.System
+		// the first read only records the CPU time
+		if !g.lastReadTime.IsZero() {
+			if elapsed := now.Sub(g.lastReadTime).Seconds(); elapsed > 0 {
+				usage = math.Max(usage, (cpuTime-g.lastCPUTime)/elapsed/g.cpuLimit)
+			}
+		}
+		g.lastCPUTime, g.lastReadTime = cpuTime, now
+	}
+	return usage, nil
+}
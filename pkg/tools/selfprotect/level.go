@@ -0,0 +1,59 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfprotect
+
+// Level is the load shedding level, the higher level sheds more load
+type Level int32
+
+const (
+	// LevelNormal means no load is shed
+	LevelNormal Level = iota
+	// LevelSampling means only part of the protocol logs are sampled
+	LevelSampling
+	// LevelDisablePayload means the payload inspection features are disabled
+	LevelDisablePayload
+	// LevelL4Only means the protocol analysis is disabled, only the layer 4 logs are kept
+	LevelL4Only
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelNormal:
+		return "normal"
+	case LevelSampling:
+		return "sampling"
+	case LevelDisablePayload:
+		return "disable_payload"
+	case LevelL4Only:
+		return "l4_only"
+	default:
+		return "unknown"
+	}
+}
+
+// nextLevel moves one level at a time, escalating when the usage reaches the high watermark,
+// and recovering when the usage falls below the low watermark
+func nextLevel(current Level, usage, high, low float64) Level {
+	if usage >= high && current < LevelL4Only {
+		return current + 1
+	}
+	if usage < low && current > LevelNormal {
+		return current - 1
+	}
+	return current
+}
@@ -0,0 +1,45 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package selfprotect
+
+import "testing"
+
+func TestNextLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  Level
+		usage    float64
+		expected Level
+	}{
+		{name: "stay normal", current: LevelNormal, usage: 0.5, expected: LevelNormal},
+		{name: "escalate", current: LevelNormal, usage: 0.85, expected: LevelSampling},
+		{name: "escalate one level at a time", current: LevelSampling, usage: 1.5, expected: LevelDisablePayload},
+		{name: "already highest", current: LevelL4Only, usage: 0.99, expected: LevelL4Only},
+		{name: "keep between watermarks", current: LevelDisablePayload, usage: 0.7, expected: LevelDisablePayload},
+		{name: "recover", current: LevelL4Only, usage: 0.3, expected: LevelDisablePayload},
+		{name: "already lowest", current: LevelNormal, usage: 0, expected: LevelNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := nextLevel(tt.current, tt.usage, 0.8, 0.6); actual != tt.expected {
+				t.Fatalf("expected %s, actual %s", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"strings"
+
+	"github.com/cilium/ebpf"
+)
+
+// the symbols only exist in the BoringSSL, for distinguishing with the statically linked OpenSSL(such as Node.js)
+var boringSSLSymbols = map[string]bool{"SSL_CTX_set_custom_verify": true, "SSL_set_private_key_method": true}
+
+// BoringSSL attach the SSL read and write of the BoringSSL which statically linked in the executable file,
+// the Envoy is excluded because it's already handled by the Envoy register.
+// The socket fd cannot be read through the offsets, it's learned from the socket syscalls inside the SSL read/write.
+func (r *Register) BoringSSL(sslWrite, sslWriteRet, sslRead, sslReadRet *ebpf.Program) {
+	r.addHandler("BoringSSL", func() (bool, error) {
+		for _, mod := range r.modules {
+			if strings.Contains(mod.Name, "/envoy") || strings.Contains(mod.Name, "libssl.so") {
+				continue
+			}
+			var readSymbol, writeSymbol, boringSymbol bool
+			for _, sym := range mod.Symbols {
+				switch sym.Name {
+				case "SSL_read":
+					readSymbol = true
+				case "SSL_write":
+					writeSymbol = true
+				default:
+					boringSymbol = boringSymbol || boringSSLSymbols[sym.Name]
+				}
+			}
+			if !readSymbol || !writeSymbol || !boringSymbol {
+				continue
+			}
+
+			file := r.linker.OpenUProbeExeFile(mod.Path)
+			file.AddLink("SSL_write", sslWrite, sslWriteRet)
+			file.AddLink("SSL_read", sslRead, sslReadRet)
+			if e := r.linker.HasError(); e != nil {
+				return false, e
+			}
+			log.Debugf("attached the statically linked BoringSSL, pid: %d, module: %s", r.pid, mod.Path)
+			return true, nil
+		}
+		return false, nil
+	})
+}
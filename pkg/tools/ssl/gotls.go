@@ -19,10 +19,12 @@ package ssl
 
 import (
 	"bytes"
+	"debug/buildinfo"
 	"encoding/binary"
 	"fmt"
 	"os/exec"
 	"regexp"
+	"runtime"
 	"strings"
 
 	"github.com/apache/skywalking-rover/pkg/tools/elf"
@@ -44,6 +46,15 @@ var (
 	goTLSRuntimeG        = "runtime.g"
 )
 
+// the known offsets of the structures, used when the DWARF is stripped
+const (
+	// https://github.com/golang/go/blob/go1.22.0/src/internal/poll/fd_unix.go#L17-L23
+	goPollFDSysFDOffset = 16
+	// https://github.com/golang/go/blob/go1.22.0/src/crypto/tls/conn.go#L27-L30
+	goTLSConnConnOffset     = 0
+	goTLSConnIsClientOffset = 16
+)
+
 type GoTLSArgsLocationType uint32
 
 const (
@@ -79,20 +90,33 @@ type GoTLSSymbolAddress struct {
 
 func (r *Register) GoTLS(symbolAddrMap *ebpf.Map, write, writeRet, read, readRet *ebpf.Program) {
 	r.addHandler("goTLS", func() (bool, error) {
+		pidExeFile := host.GetHostProcInHost(fmt.Sprintf("%d/exe", r.pid))
 		buildVersionSymbol := r.searchSymbolInModules(r.modules, func(a, b string) bool {
 			return a == b
 		}, "runtime.buildVersion")
+		var buildVersion string
 		if buildVersionSymbol == nil {
-			return false, nil
+			// the symbol table may be stripped, then reading the version from the build info
+			info, err := buildinfo.ReadFile(pidExeFile)
+			if err != nil {
+				return false, nil
+			}
+			buildVersion = info.GoVersion
 		}
-		pidExeFile := host.GetHostProcInHost(fmt.Sprintf("%d/exe", r.pid))
 		elfFile, err := elf.NewFile(pidExeFile)
 		if err != nil {
 			return false, fmt.Errorf("read executable file error: %v", err)
 		}
 		defer elfFile.Close()
 
-		v, err := r.getGoVersion(elfFile, buildVersionSymbol)
+		var v *version.Version
+		if buildVersionSymbol != nil {
+			v, err = r.getGoVersion(elfFile, buildVersionSymbol)
+		} else if ver, ok, e := r.gettingGoVersionFromString(buildVersion); ok {
+			v, err = ver, e
+		} else {
+			err = fmt.Errorf("the go version is failure to identify, version: %s", buildVersion)
+		}
 		if err != nil {
 			return false, err
 		}
@@ -183,7 +207,8 @@ func (r *Register) generateGOTLSSymbolOffsets(register *Register, elfFile *elf.F
 		goTLSReadSymbol, goTLSWriteSymbol, goTLSGIDStatusSymbol,
 		goTLSPollFDSymbol, goTLSConnSymbol, goTLSRuntimeG)
 	if err != nil {
-		return nil, err
+		log.Debugf("could not read the DWARF of the go program, using the known offsets of the go version %s: %v", v, err)
+		return r.generateGoTLSSymbolOffsetsByVersion(register, elfFile, v)
 	}
 
 	symbolAddresses := &GoTLSSymbolAddress{}
@@ -204,14 +229,7 @@ func (r *Register) generateGOTLSSymbolOffsets(register *Register, elfFile *elf.F
 		return nil, nil
 	}
 
-	sym := register.SearchSymbol(func(a, b string) bool {
-		return a == b
-	}, "go.itab.*net.TCPConn,net.Conn", "go:itab.*net.TCPConn,net.Conn")
-	if sym == nil {
-		log.Warnf("could not found the tcp connection symbol: go.itab.*net.TCPConn,net.Conn")
-		return nil, nil
-	}
-	symbolAddresses.TCPConnOffset = sym.Location
+	symbolAddresses.TCPConnOffset = r.findGoTCPConnItab(register)
 
 	var retValArg0, retValArg1 = "~r1", "~r2"
 	if v.Minor >= 18 {
@@ -240,6 +258,76 @@ func (r *Register) generateGOTLSSymbolOffsets(register *Register, elfFile *elf.F
 	return symbolAddresses, assignError
 }
 
+// generateGoTLSSymbolOffsetsByVersion build the offsets through the known structures and ABI of the go version,
+// for the binary which DWARF is stripped, the functions are found through the ".gopclntab" section
+func (r *Register) generateGoTLSSymbolOffsetsByVersion(register *Register, elfFile *elf.File,
+	v *version.Version) (*GoTLSSymbolAddress, error) {
+	if elfFile.FindSymbol(goTLSReadSymbol) == nil || elfFile.FindSymbol(goTLSWriteSymbol) == nil {
+		log.Warnf("could not found the go tls read or write symbol in the stripped go program")
+		return nil, nil
+	}
+	if v.Major != 1 || v.Minor < 13 {
+		return nil, fmt.Errorf("the go version is not support without DWARF: %s", v)
+	}
+	addresses := &GoTLSSymbolAddress{
+		FDSysFDOffset:  goPollFDSysFDOffset,
+		TLSConnOffset:  goTLSConnConnOffset,
+		IsClientOffset: goTLSConnIsClientOffset,
+		GIDOffset:      goRuntimeGIDOffset(v),
+		TCPConnOffset:  r.findGoTCPConnItab(register),
+	}
+	// func (c *Conn) Read/Write(b []byte) (int, error)
+	if goUsingRegisterABI(v) {
+		// the receiver and the slice pointer are in the first and second register,
+		// the returned int and the type of error are also in the first and second register
+		addresses.WriteConnectionLoc = GoSymbolLocation{Type: GoTLSArgsLocationTypeRegister, Offset: 0}
+		addresses.WriteBufferLoc = GoSymbolLocation{Type: GoTLSArgsLocationTypeRegister, Offset: 8}
+		addresses.WriteRet0Loc = GoSymbolLocation{Type: GoTLSArgsLocationTypeRegister, Offset: 0}
+		addresses.WriteRet1Loc = GoSymbolLocation{Type: GoTLSArgsLocationTypeRegister, Offset: 8}
+	} else {
+		// the return address(8) + receiver(8) + slice(24) + int(8) + error(16)
+		addresses.WriteConnectionLoc = GoSymbolLocation{Type: GoTLSArgsLocationTypeStack, Offset: 8}
+		addresses.WriteBufferLoc = GoSymbolLocation{Type: GoTLSArgsLocationTypeStack, Offset: 16}
+		addresses.WriteRet0Loc = GoSymbolLocation{Type: GoTLSArgsLocationTypeStack, Offset: 40}
+		addresses.WriteRet1Loc = GoSymbolLocation{Type: GoTLSArgsLocationTypeStack, Offset: 48}
+	}
+	// the read and write have the same signature
+	addresses.ReadConnectionLoc, addresses.ReadBufferLoc = addresses.WriteConnectionLoc, addresses.WriteBufferLoc
+	addresses.ReadRet0Loc, addresses.ReadRet1Loc = addresses.WriteRet0Loc, addresses.WriteRet1Loc
+	return addresses, nil
+}
+
+// findGoTCPConnItab find the address of the "*net.TCPConn" implements the "net.Conn",
+// return 0 if not found(such as stripped binary), then the connection type is not checked
+func (r *Register) findGoTCPConnItab(register *Register) uint64 {
+	sym := register.SearchSymbol(func(a, b string) bool {
+		return a == b
+	}, "go.itab.*net.TCPConn,net.Conn", "go:itab.*net.TCPConn,net.Conn")
+	if sym == nil {
+		log.Debugf("could not found the tcp connection symbol: go.itab.*net.TCPConn,net.Conn, pid: %d", r.pid)
+		return 0
+	}
+	return sym.Location
+}
+
+// goRuntimeGIDOffset is the offset of the "goid" in the "runtime.g"
+func goRuntimeGIDOffset(v *version.Version) uint64 {
+	// the "syscallbp" is added in the go 1.23, and the "gobuf.ret" is removed in the go 1.24
+	// https://github.com/golang/go/blob/go1.23.0/src/runtime/runtime2.go#L422-L468
+	if v.Major == 1 && v.Minor == 23 {
+		return 160
+	}
+	return 152
+}
+
+// goUsingRegisterABI the register based calling convention is enabled since go 1.17 in amd64, and go 1.18 in arm64
+func goUsingRegisterABI(v *version.Version) bool {
+	if runtime.GOARCH == "arm64" {
+		return v.GreaterOrEquals(version.Build(1, 18, 0))
+	}
+	return v.GreaterOrEquals(version.Build(1, 17, 0))
+}
+
 func (r *Register) assignGoTLSStructureOffset(err error, reader *elf.DwarfReader, structName, fieldName string, dest *uint64) error {
 	if err != nil {
 		return err
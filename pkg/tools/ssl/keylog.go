@@ -0,0 +1,144 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+// The labels in the NSS key log format
+// https://developer.mozilla.org/en-US/docs/Mozilla/Projects/NSS/Key_Log_Format
+const (
+	KeyLogClientRandom                 = "CLIENT_RANDOM"
+	KeyLogClientHandshakeTrafficSecret = "CLIENT_HANDSHAKE_TRAFFIC_SECRET"
+	KeyLogServerHandshakeTrafficSecret = "SERVER_HANDSHAKE_TRAFFIC_SECRET"
+	KeyLogClientTrafficSecret0         = "CLIENT_TRAFFIC_SECRET_0"
+	KeyLogServerTrafficSecret0         = "SERVER_TRAFFIC_SECRET_0"
+)
+
+const keyLogFileEnv = "SSLKEYLOGFILE"
+
+// KeyLog is the secrets exported by the application through the key log file,
+// the first key is the hex encoded client random, and the second key is the label
+type KeyLog map[string]map[string][]byte
+
+// Find the secret by the client random and label
+func (k KeyLog) Find(clientRandom []byte, label string) []byte {
+	return k[hex.EncodeToString(clientRandom)][label]
+}
+
+// ParseKeyLog parse the key log lines and append the secrets, the invalid lines are ignored
+func (k KeyLog) ParseKeyLog(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		secret, err := hex.DecodeString(fields[2])
+		if err != nil {
+			continue
+		}
+		random := strings.ToLower(fields[1])
+		secrets := k[random]
+		if secrets == nil {
+			secrets = make(map[string][]byte)
+			k[random] = secrets
+		}
+		secrets[fields[0]] = secret
+	}
+	return scanner.Err()
+}
+
+// KeyLogReader incrementally reads the key log file which is written by the application
+type KeyLogReader struct {
+	path   string
+	offset int64
+	keyLog KeyLog
+	mutex  sync.Mutex
+}
+
+func NewKeyLogReader(path string) *KeyLogReader {
+	return &KeyLogReader{path: path, keyLog: make(KeyLog)}
+}
+
+// FindKeyLogFile find the key log file path of the process, the path is read from the SSLKEYLOGFILE environment
+// when the specified path is empty, and located in the root file system of the process
+func FindKeyLogFile(pid int32, specified string) (string, error) {
+	keyLogPath := specified
+	if keyLogPath == "" {
+		environ, err := os.ReadFile(host.GetHostProcInHost(fmt.Sprintf("%d/environ", pid)))
+		if err != nil {
+			return "", err
+		}
+		for _, env := range bytes.Split(environ, []byte{0}) {
+			if value, found := strings.CutPrefix(string(env), keyLogFileEnv+"="); found {
+				keyLogPath = value
+				break
+			}
+		}
+	}
+	if keyLogPath == "" {
+		return "", fmt.Errorf("the %s environment is not found", keyLogFileEnv)
+	}
+	return host.GetHostProcInHost(fmt.Sprintf("%d/root%s", pid, keyLogPath)), nil
+}
+
+// Find the secret by the client random and label, read the new appended lines if the secret not found
+func (r *KeyLogReader) Find(clientRandom []byte, label string) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if secret := r.keyLog.Find(clientRandom, label); secret != nil {
+		return secret, nil
+	}
+	if err := r.readAppended(); err != nil {
+		return nil, err
+	}
+	return r.keyLog.Find(clientRandom, label), nil
+}
+
+func (r *KeyLogReader) readAppended() error {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err = file.Seek(r.offset, io.SeekStart); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	// only read the completed lines, the last line may be still writing
+	completed := bytes.LastIndexByte(data, '\n') + 1
+	if completed == 0 {
+		return nil
+	}
+	r.offset += int64(completed)
+	return r.keyLog.ParseKeyLog(bytes.NewReader(data[:completed]))
+}
@@ -0,0 +1,294 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The QUIC versions which Initial packets could be decrypted
+const (
+	QUICVersion1 uint32 = 0x00000001
+	QUICVersion2 uint32 = 0x6b3343cf
+)
+
+const (
+	quicLongHeaderForm = 0x80
+	quicFixedBit       = 0x40
+	quicSampleLen      = 16
+	quicMaxCIDLen      = 20
+
+	quicFramePadding           = 0x00
+	quicFramePing              = 0x01
+	quicFrameACK               = 0x02
+	quicFrameACKECN            = 0x03
+	quicFrameCrypto            = 0x06
+	quicInitialPacketTypeV1    = 0x00
+	quicInitialPacketTypeV2    = 0x01
+	quicLongHeaderTypeMask     = 0x30
+	quicLongHeaderTypeShift    = 4
+	quicLongHeaderReservedMask = 0x0f
+)
+
+// ErrNotQUICInitial means the packet is not the QUIC Initial packet of the supported versions
+var ErrNotQUICInitial = errors.New("not the QUIC initial packet")
+
+type quicVersionInfo struct {
+	salt              []byte
+	keyLabel          string
+	ivLabel           string
+	hpLabel           string
+	initialPacketType byte
+}
+
+var quicVersions = map[uint32]*quicVersionInfo{
+	QUICVersion1: {
+		salt: []byte{0x38, 0x76, 0x2c, 0xf7, 0xf5, 0x59, 0x34, 0xb3, 0x4d, 0x17,
+			0x9a, 0xe6, 0xa4, 0xc8, 0x0c, 0xad, 0xcc, 0xbb, 0x7f, 0x0a},
+		keyLabel: "quic key", ivLabel: "quic iv", hpLabel: "quic hp",
+		initialPacketType: quicInitialPacketTypeV1,
+	},
+	QUICVersion2: {
+		salt: []byte{0x0d, 0xed, 0xe3, 0xde, 0xf7, 0x00, 0xa6, 0xdb, 0x81, 0x93,
+			0x81, 0xbe, 0x6e, 0x26, 0x9d, 0xcb, 0xf9, 0xbd, 0x2e, 0xd9},
+		keyLabel: "quicv2 key", ivLabel: "quicv2 iv", hpLabel: "quicv2 hp",
+		initialPacketType: quicInitialPacketTypeV2,
+	},
+}
+
+// QUICInitial is the decrypted Initial packet of the QUIC, the keys are derived from the client destination connection ID
+type QUICInitial struct {
+	Version uint32
+	DCID    []byte
+	SCID    []byte
+	// the CRYPTO frames carry the TLS handshake messages(without the record layer)
+	CryptoFrames []*QUICCryptoFrame
+	// Length of the packet, the following data in the datagram are the coalesced packets
+	Length int
+}
+
+// QUICCryptoFrame is the data at the offset of the CRYPTO stream
+type QUICCryptoFrame struct {
+	Offset uint64
+	Data   []byte
+}
+
+// IsQUICLongHeader check the packet is started with the long header of the QUIC
+func IsQUICLongHeader(packet []byte) bool {
+	return len(packet) >= 7 && packet[0]&quicLongHeaderForm != 0 && packet[0]&quicFixedBit != 0
+}
+
+// ParseQUICInitial decrypt the Initial packet at the start of the datagram. The initialDCID is the destination connection ID
+// of the first Initial packet sent by the client, it's the packet DCID when empty. The client means the packet is sent by the client.
+func ParseQUICInitial(packet, initialDCID []byte, client bool) (*QUICInitial, error) {
+	if !IsQUICLongHeader(packet) {
+		return nil, ErrNotQUICInitial
+	}
+	version := binary.BigEndian.Uint32(packet[1:])
+	versionInfo := quicVersions[version]
+	if versionInfo == nil || (packet[0]&quicLongHeaderTypeMask)>>quicLongHeaderTypeShift != versionInfo.initialPacketType {
+		return nil, ErrNotQUICInitial
+	}
+	result := &QUICInitial{Version: version}
+
+	// connection IDs
+	offset := 5
+	var err error
+	if result.DCID, offset, err = quicReadConnectionID(packet, offset); err != nil {
+		return nil, err
+	}
+	if result.SCID, offset, err = quicReadConnectionID(packet, offset); err != nil {
+		return nil, err
+	}
+	// token
+	tokenLen, n := QUICReadVarint(packet[offset:])
+	if n == 0 || uint64(len(packet)-offset-n) < tokenLen {
+		return nil, fmt.Errorf("invalid QUIC initial token")
+	}
+	offset += n + int(tokenLen)
+	// the length of packet number and payload
+	length, n := QUICReadVarint(packet[offset:])
+	if n == 0 {
+		return nil, fmt.Errorf("invalid QUIC initial length")
+	}
+	pnOffset := offset + n
+	if uint64(len(packet)-pnOffset) < length || length < 4+quicSampleLen {
+		return nil, fmt.Errorf("the QUIC initial packet is truncated")
+	}
+	result.Length = pnOffset + int(length)
+
+	if len(initialDCID) == 0 {
+		initialDCID = result.DCID
+	}
+	payload, err := quicDecryptInitial(versionInfo, packet[:result.Length], pnOffset, initialDCID, client)
+	if err != nil {
+		return nil, err
+	}
+	if result.CryptoFrames, err = quicReadCryptoFrames(payload); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func quicReadConnectionID(packet []byte, offset int) (cid []byte, next int, err error) {
+	if offset >= len(packet) {
+		return nil, 0, fmt.Errorf("invalid QUIC connection ID")
+	}
+	l := int(packet[offset])
+	if l > quicMaxCIDLen || offset+1+l > len(packet) {
+		return nil, 0, fmt.Errorf("invalid QUIC connection ID length: %d", l)
+	}
+	return packet[offset+1 : offset+1+l], offset + 1 + l, nil
+}
+
+// QUICInitialKeys derive the AEAD key, IV and header protection key of the Initial packets
+func QUICInitialKeys(version uint32, initialDCID []byte, client bool) (key, iv, hp []byte, err error) {
+	versionInfo := quicVersions[version]
+	if versionInfo == nil {
+		return nil, nil, nil, fmt.Errorf("not support the QUIC version: 0x%08x", version)
+	}
+	key, iv, hp = quicInitialKeys(versionInfo, initialDCID, client)
+	return key, iv, hp, nil
+}
+
+func quicInitialKeys(versionInfo *quicVersionInfo, initialDCID []byte, client bool) (key, iv, hp []byte) {
+	// HKDF-Extract
+	mac := hmac.New(sha256.New, versionInfo.salt)
+	mac.Write(initialDCID)
+	initialSecret := mac.Sum(nil)
+	label := "server in"
+	if client {
+		label = "client in"
+	}
+	secret := hkdfExpandLabel(sha256.New, initialSecret, label, sha256.Size)
+	return hkdfExpandLabel(sha256.New, secret, versionInfo.keyLabel, 16),
+		hkdfExpandLabel(sha256.New, secret, versionInfo.ivLabel, 12),
+		hkdfExpandLabel(sha256.New, secret, versionInfo.hpLabel, 16)
+}
+
+// quicDecryptInitial remove the header protection and decrypt the payload, the packet only contains the Initial packet
+func quicDecryptInitial(versionInfo *quicVersionInfo, packet []byte, pnOffset int, initialDCID []byte, client bool) ([]byte, error) {
+	key, iv, hp := quicInitialKeys(versionInfo, initialDCID, client)
+	block, err := aes.NewCipher(hp)
+	if err != nil {
+		return nil, err
+	}
+	mask := make([]byte, aes.BlockSize)
+	block.Encrypt(mask, packet[pnOffset+4:pnOffset+4+quicSampleLen])
+
+	header := make([]byte, pnOffset+4)
+	copy(header, packet)
+	header[0] ^= mask[0] & quicLongHeaderReservedMask
+	pnLen := int(header[0]&0x03) + 1
+	var packetNumber uint64
+	for i := 0; i < pnLen; i++ {
+		header[pnOffset+i] ^= mask[1+i]
+		packetNumber = packetNumber<<8 | uint64(header[pnOffset+i])
+	}
+	header = header[:pnOffset+pnLen]
+
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := append([]byte(nil), iv...)
+	for i := 0; i < 8; i++ {
+		nonce[len(nonce)-1-i] ^= byte(packetNumber >> (8 * i))
+	}
+	payload, err := aead.Open(nil, nonce, packet[pnOffset+pnLen:], header)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt the QUIC initial packet failure: %v", err)
+	}
+	return payload, nil
+}
+
+// quicReadCryptoFrames read the CRYPTO frames, the frames after the unknown frame are ignored
+func quicReadCryptoFrames(payload []byte) ([]*QUICCryptoFrame, error) {
+	var frames []*QUICCryptoFrame
+	for len(payload) > 0 {
+		frameType, n := QUICReadVarint(payload)
+		if n == 0 {
+			return frames, nil
+		}
+		payload = payload[n:]
+		switch frameType {
+		case quicFramePadding, quicFramePing:
+		case quicFrameACK, quicFrameACKECN:
+			// largest acknowledged, ack delay, range count, first range
+			values, rest := quicSkipVarints(payload, 4)
+			if rest == nil {
+				return frames, nil
+			}
+			ranges := values[2] * 2
+			if frameType == quicFrameACKECN {
+				ranges += 3
+			}
+			if _, rest = quicSkipVarints(rest, int(min(ranges, uint64(len(rest))))); rest == nil {
+				return frames, nil
+			}
+			payload = rest
+		case quicFrameCrypto:
+			values, rest := quicSkipVarints(payload, 2)
+			if rest == nil || uint64(len(rest)) < values[1] {
+				return frames, fmt.Errorf("the QUIC crypto frame is truncated")
+			}
+			frames = append(frames, &QUICCryptoFrame{Offset: values[0], Data: rest[:values[1]]})
+			payload = rest[values[1]:]
+		default:
+			return frames, nil
+		}
+	}
+	return frames, nil
+}
+
+// quicSkipVarints read count of the variable-length integers, return nil data when not enough
+func quicSkipVarints(data []byte, count int) ([]uint64, []byte) {
+	values := make([]uint64, count)
+	for i := 0; i < count; i++ {
+		v, n := QUICReadVarint(data)
+		if n == 0 {
+			return nil, nil
+		}
+		values[i], data = v, data[n:]
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return values, data
+}
+
+// QUICReadVarint read the variable-length integer, return the read length, 0 means the data is not enough
+func QUICReadVarint(data []byte) (value uint64, n int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	n = 1 << (data[0] >> 6)
+	if len(data) < n {
+		return 0, 0
+	}
+	value = uint64(data[0] & 0x3f)
+	for i := 1; i < n; i++ {
+		value = value<<8 | uint64(data[i])
+	}
+	return value, n
+}
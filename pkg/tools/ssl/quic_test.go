@@ -0,0 +1,118 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func TestQUICInitialKeys(t *testing.T) {
+	// the keys of the client destination connection ID in RFC 9001 Appendix A.1
+	dcid, _ := hex.DecodeString("8394c8f03e515708")
+	tests := []struct {
+		client      bool
+		key, iv, hp string
+	}{
+		{client: true, key: "1f369613dd76d5467730efcbe3b1a22d", iv: "fa044b2f42a3fd3b46fb255c", hp: "9f50449e04a0e810283a1e9933adedd2"},
+		{client: false, key: "cf3a5331653c364c88f0f379b6067e37", iv: "0ac1493ca1905853b0bba03e", hp: "c206b8d9b9f0f37644430b490eeaa314"},
+	}
+	for _, tt := range tests {
+		key, iv, hp, err := QUICInitialKeys(QUICVersion1, dcid, tt.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(key) != tt.key || hex.EncodeToString(iv) != tt.iv || hex.EncodeToString(hp) != tt.hp {
+			t.Fatalf("unexpected keys of client(%t): %x, %x, %x", tt.client, key, iv, hp)
+		}
+	}
+}
+
+func TestParseQUICInitial(t *testing.T) {
+	dcid, scid := []byte{1, 2, 3, 4, 5, 6, 7, 8}, []byte{9, 10}
+	hello := []byte{TLSHandshakeClientHello, 0, 0, 4, 0x03, 0x03, 0xab, 0xcd}
+	// ACK, CRYPTO at offset 100, then padding
+	frames := []byte{quicFrameACK, 1, 0, 0, 0, quicFrameCrypto, 0x40, 100, byte(len(hello))}
+	frames = append(frames, hello...)
+	frames = append(frames, make([]byte, 40)...)
+
+	for _, version := range []uint32{QUICVersion1, QUICVersion2} {
+		packet := sealQUICInitial(t, version, dcid, scid, 2, frames, true)
+		// the coalesced packet should be ignored
+		datagram := append(append([]byte(nil), packet...), 0x40, 0x01)
+
+		initial, err := ParseQUICInitial(datagram, nil, true)
+		if err != nil {
+			t.Fatalf("version 0x%08x: %v", version, err)
+		}
+		if initial.Version != version || !bytes.Equal(initial.DCID, dcid) || !bytes.Equal(initial.SCID, scid) ||
+			initial.Length != len(packet) {
+			t.Fatalf("unexpected initial packet: %+v", initial)
+		}
+		if len(initial.CryptoFrames) != 1 || initial.CryptoFrames[0].Offset != 100 ||
+			!bytes.Equal(initial.CryptoFrames[0].Data, hello) {
+			t.Fatalf("unexpected crypto frames: %+v", initial.CryptoFrames)
+		}
+
+		// decrypt by the wrong direction keys
+		if _, err := ParseQUICInitial(datagram, nil, false); err == nil {
+			t.Fatalf("the packet should not be decrypted by the server keys")
+		}
+	}
+
+	if _, err := ParseQUICInitial([]byte{0x17, 0x03, 0x03, 0x00, 0x10, 0x00, 0x00}, nil, true); err != ErrNotQUICInitial {
+		t.Fatalf("the TLS record should not be the QUIC initial packet: %v", err)
+	}
+}
+
+// sealQUICInitial build the protected Initial packet with the one byte packet number
+func sealQUICInitial(t *testing.T, version uint32, dcid, scid []byte, packetNumber byte, frames []byte, client bool) []byte {
+	versionInfo := quicVersions[version]
+	key, iv, hp := quicInitialKeys(versionInfo, dcid, client)
+	aead, err := newAESGCM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := []byte{quicLongHeaderForm | quicFixedBit | versionInfo.initialPacketType<<quicLongHeaderTypeShift, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[1:], version)
+	header = append(append(header, byte(len(dcid))), dcid...)
+	header = append(append(header, byte(len(scid))), scid...)
+	header = append(header, 0) // token
+	length := 1 + len(frames) + aead.Overhead()
+	header = append(header, 0x40|byte(length>>8), byte(length))
+	pnOffset := len(header)
+	header = append(header, packetNumber)
+
+	nonce := append([]byte(nil), iv...)
+	nonce[len(nonce)-1] ^= packetNumber
+	packet := append(header, aead.Seal(nil, nonce, frames, header)...)
+
+	block, err := aes.NewCipher(hp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mask := make([]byte, aes.BlockSize)
+	block.Encrypt(mask, packet[pnOffset+4:pnOffset+4+quicSampleLen])
+	packet[0] ^= mask[0] & quicLongHeaderReservedMask
+	packet[pnOffset] ^= mask[1]
+	return packet
+}
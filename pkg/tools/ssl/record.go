@@ -0,0 +1,452 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// The content types of the TLS record
+const (
+	TLSRecordChangeCipherSpec uint8 = 20
+	TLSRecordAlert            uint8 = 21
+	TLSRecordHandshake        uint8 = 22
+	TLSRecordApplicationData  uint8 = 23
+)
+
+// The handshake message types
+const (
+	TLSHandshakeClientHello         uint8 = 1
+	TLSHandshakeServerHello         uint8 = 2
+	TLSHandshakeEncryptedExtensions uint8 = 8
+	TLSHandshakeFinished            uint8 = 20
+)
+
+const (
+	tlsRecordHeaderLen           = 5
+	tlsHandshakeHeaderLen        = 4
+	tlsVersion13          uint16 = 0x0304
+	tlsExtServerName      uint16 = 0
+	tlsExtALPN            uint16 = 16
+	tlsExtSupportedVer    uint16 = 43
+	tlsServerNameHost     uint8  = 0
+	tls12ExplicitNonce           = 8
+	tlsAEADTagLen                = 16
+)
+
+// TLSRecord is the plaintext record layer of the TLS
+type TLSRecord struct {
+	Type     uint8
+	Version  uint16
+	Header   []byte
+	Fragment []byte
+}
+
+// TLSHello is the client hello or server hello handshake message
+type TLSHello struct {
+	Type        uint8
+	Random      []byte
+	CipherSuite uint16
+	// IsTLS13 only detected from the server hello
+	IsTLS13 bool
+	// Version is the negotiated version, only detected from the server hello
+	Version uint16
+	// ServerName is the SNI, only detected from the client hello
+	ServerName string
+	// ALPN is the offered protocols in the client hello, or the selected protocol in the server hello(only in TLS 1.2)
+	ALPN []string
+}
+
+// ErrUnsupportedCipherSuite means the negotiated cipher suite cannot be decrypted, such as the CBC cipher suites
+var ErrUnsupportedCipherSuite = errors.New("not support the cipher suite")
+
+// TLSHandshakeMessage is the handshake message in the handshake records
+type TLSHandshakeMessage struct {
+	Type uint8
+	Body []byte
+}
+
+type cipherSuiteInfo struct {
+	keyLen int
+	hash   func() hash.Hash
+	// the AEAD is ChaCha20-Poly1305, which not contains the explicit nonce in TLS 1.2
+	chacha20 bool
+}
+
+// only the AES-GCM and ChaCha20-Poly1305 cipher suites are supported
+var cipherSuites = map[uint16]*cipherSuiteInfo{
+	// TLS 1.3
+	0x1301: {keyLen: 16, hash: sha256.New},
+	0x1302: {keyLen: 32, hash: sha512.New384},
+	0x1303: {keyLen: 32, hash: sha256.New, chacha20: true},
+	// TLS 1.2
+	0x009C: {keyLen: 16, hash: sha256.New},
+	0x009D: {keyLen: 32, hash: sha512.New384},
+	0xC02B: {keyLen: 16, hash: sha256.New},
+	0xC02C: {keyLen: 32, hash: sha512.New384},
+	0xC02F: {keyLen: 16, hash: sha256.New},
+	0xC030: {keyLen: 32, hash: sha512.New384},
+	0xCCA8: {keyLen: 32, hash: sha256.New, chacha20: true},
+	0xCCA9: {keyLen: 32, hash: sha256.New, chacha20: true},
+	0xCCAA: {keyLen: 32, hash: sha256.New, chacha20: true},
+}
+
+func (c *cipherSuiteInfo) newAEAD(key []byte) (cipher.AEAD, error) {
+	if c.chacha20 {
+		return chacha20poly1305.New(key)
+	}
+	return newAESGCM(key)
+}
+
+// tls12IVLen is the length of the IV in the key block of TLS 1.2,
+// the AES-GCM only uses the 4 bytes salt, and the ChaCha20-Poly1305 uses the full nonce
+func (c *cipherSuiteInfo) tls12IVLen() int {
+	if c.chacha20 {
+		return 12
+	}
+	return 4
+}
+
+// ParseTLSRecords parse the completed records from the data, return the records and the length of the read data
+func ParseTLSRecords(data []byte) (records []*TLSRecord, read int) {
+	for len(data)-read >= tlsRecordHeaderLen {
+		header := data[read : read+tlsRecordHeaderLen]
+		length := int(binary.BigEndian.Uint16(header[3:]))
+		if len(data)-read-tlsRecordHeaderLen < length {
+			break
+		}
+		records = append(records, &TLSRecord{
+			Type:     header[0],
+			Version:  binary.BigEndian.Uint16(header[1:]),
+			Header:   header,
+			Fragment: data[read+tlsRecordHeaderLen : read+tlsRecordHeaderLen+length],
+		})
+		read += tlsRecordHeaderLen + length
+	}
+	return records, read
+}
+
+// ParseTLSHandshakeMessages parse the completed handshake messages from the plaintext of the handshake records,
+// the multiple messages could be coalesced into one record, and one message could be split into multiple records
+func ParseTLSHandshakeMessages(data []byte) (messages []*TLSHandshakeMessage, read int) {
+	for len(data)-read >= tlsHandshakeHeaderLen {
+		header := data[read : read+tlsHandshakeHeaderLen]
+		length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		if len(data)-read-tlsHandshakeHeaderLen < length {
+			break
+		}
+		messages = append(messages, &TLSHandshakeMessage{
+			Type: header[0],
+			Body: data[read+tlsHandshakeHeaderLen : read+tlsHandshakeHeaderLen+length],
+		})
+		read += tlsHandshakeHeaderLen + length
+	}
+	return messages, read
+}
+
+// ParseEncryptedExtensionsALPN read the selected protocol from the encrypted extensions message body in TLS 1.3
+func ParseEncryptedExtensionsALPN(body []byte) string {
+	if len(body) < 2 {
+		return ""
+	}
+	extensions := body[2:min(len(body), 2+int(binary.BigEndian.Uint16(body)))]
+	for len(extensions) >= 4 {
+		extType := binary.BigEndian.Uint16(extensions)
+		extLen := int(binary.BigEndian.Uint16(extensions[2:]))
+		if len(extensions) < 4+extLen {
+			return ""
+		}
+		if extType == tlsExtALPN {
+			if protocols := parseTLSALPN(extensions[4 : 4+extLen]); len(protocols) > 0 {
+				return protocols[0]
+			}
+			return ""
+		}
+		extensions = extensions[4+extLen:]
+	}
+	return ""
+}
+
+// IsTLSRecordHeader check the data is started with a TLS handshake record
+func IsTLSRecordHeader(data []byte) bool {
+	return len(data) >= tlsRecordHeaderLen && data[0] == TLSRecordHandshake && data[1] == 0x03 && data[2] <= 0x04
+}
+
+// ParseTLSHello parse the client hello or server hello from the handshake record fragment
+func ParseTLSHello(fragment []byte) (*TLSHello, error) {
+	// type(1) + length(3) + version(2) + random(32) + session id length(1)
+	if len(fragment) < 39 {
+		return nil, fmt.Errorf("the handshake message is too short")
+	}
+	hello := &TLSHello{Type: fragment[0]}
+	if hello.Type != TLSHandshakeClientHello && hello.Type != TLSHandshakeServerHello {
+		return nil, fmt.Errorf("the handshake message is not hello: %d", hello.Type)
+	}
+	hello.Random = append([]byte(nil), fragment[6:38]...)
+	hello.Version = binary.BigEndian.Uint16(fragment[4:])
+	offset := 38 + 1 + int(fragment[38])
+	if hello.Type == TLSHandshakeClientHello {
+		// cipher suites and compression methods
+		if len(fragment) < offset+2 {
+			return hello, nil
+		}
+		offset += 2 + int(binary.BigEndian.Uint16(fragment[offset:]))
+		if len(fragment) < offset+1 {
+			return hello, nil
+		}
+		offset += 1 + int(fragment[offset])
+	} else {
+		// cipher suite(2) + compression method(1)
+		if len(fragment) < offset+3 {
+			return nil, fmt.Errorf("the server hello is too short")
+		}
+		hello.CipherSuite = binary.BigEndian.Uint16(fragment[offset:])
+		offset += 3
+	}
+	if len(fragment) < offset+2 {
+		return hello, nil
+	}
+	extensionsEnd := offset + 2 + int(binary.BigEndian.Uint16(fragment[offset:]))
+	offset += 2
+	// the fragment may be truncated, so only read the completed extensions
+	for offset+4 <= extensionsEnd && offset+4 <= len(fragment) {
+		extType := binary.BigEndian.Uint16(fragment[offset:])
+		extLen := int(binary.BigEndian.Uint16(fragment[offset+2:]))
+		offset += 4
+		if offset+extLen > len(fragment) {
+			break
+		}
+		ext := fragment[offset : offset+extLen]
+		switch extType {
+		case tlsExtSupportedVer:
+			if hello.Type == TLSHandshakeServerHello && extLen == 2 {
+				hello.Version = binary.BigEndian.Uint16(ext)
+				hello.IsTLS13 = hello.Version == tlsVersion13
+			}
+		case tlsExtServerName:
+			hello.ServerName = parseTLSServerName(ext)
+		case tlsExtALPN:
+			hello.ALPN = parseTLSALPN(ext)
+		}
+		offset += extLen
+	}
+	return hello, nil
+}
+
+// parseTLSServerName read the host name from the server name list
+func parseTLSServerName(ext []byte) string {
+	if len(ext) < 2 {
+		return ""
+	}
+	list := ext[2:min(len(ext), 2+int(binary.BigEndian.Uint16(ext)))]
+	for len(list) >= 3 {
+		nameType, nameLen := list[0], int(binary.BigEndian.Uint16(list[1:]))
+		if len(list) < 3+nameLen {
+			return ""
+		}
+		if nameType == tlsServerNameHost {
+			return string(list[3 : 3+nameLen])
+		}
+		list = list[3+nameLen:]
+	}
+	return ""
+}
+
+// parseTLSALPN read the protocol name list
+func parseTLSALPN(ext []byte) []string {
+	if len(ext) < 2 {
+		return nil
+	}
+	list := ext[2:min(len(ext), 2+int(binary.BigEndian.Uint16(ext)))]
+	var protocols []string
+	for len(list) >= 1 {
+		nameLen := int(list[0])
+		if len(list) < 1+nameLen {
+			break
+		}
+		protocols = append(protocols, string(list[1:1+nameLen]))
+		list = list[1+nameLen:]
+	}
+	return protocols
+}
+
+// RecordDecryptor decrypt the records of one direction in the TLS connection
+type RecordDecryptor struct {
+	aead  cipher.AEAD
+	iv    []byte
+	seq   uint64
+	tls13 bool
+	// the nonce is the IV XOR the sequence, otherwise the IV concat the explicit nonce of the record
+	xorNonce bool
+}
+
+// NewTLS13Decryptor create the decryptor by the traffic secret of TLS 1.3
+func NewTLS13Decryptor(cipherSuite uint16, secret []byte) (*RecordDecryptor, error) {
+	suite := cipherSuites[cipherSuite]
+	if suite == nil {
+		return nil, fmt.Errorf("%w: 0x%04x", ErrUnsupportedCipherSuite, cipherSuite)
+	}
+	key := hkdfExpandLabel(suite.hash, secret, "key", suite.keyLen)
+	iv := hkdfExpandLabel(suite.hash, secret, "iv", 12)
+	aead, err := suite.newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordDecryptor{aead: aead, iv: iv, tls13: true, xorNonce: true}, nil
+}
+
+// NewTLS12Decryptor create the decryptor by the master secret of TLS 1.2, the client decryptor decrypts the data sent by the client
+func NewTLS12Decryptor(cipherSuite uint16, masterSecret, clientRandom, serverRandom []byte, client bool) (*RecordDecryptor, error) {
+	suite := cipherSuites[cipherSuite]
+	if suite == nil {
+		return nil, fmt.Errorf("%w: 0x%04x", ErrUnsupportedCipherSuite, cipherSuite)
+	}
+	seed := append(append([]byte(nil), serverRandom...), clientRandom...)
+	// client write key + server write key + client write IV + server write IV
+	ivLen := suite.tls12IVLen()
+	keyBlock := tls12PRF(suite.hash, masterSecret, "key expansion", seed, 2*suite.keyLen+2*ivLen)
+	key, iv := keyBlock[suite.keyLen:2*suite.keyLen], keyBlock[2*suite.keyLen+ivLen:]
+	if client {
+		key, iv = keyBlock[:suite.keyLen], keyBlock[2*suite.keyLen:2*suite.keyLen+ivLen]
+	}
+	aead, err := suite.newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordDecryptor{aead: aead, iv: iv, xorNonce: suite.chacha20}, nil
+}
+
+// Decrypt the record, return the real content type and the plaintext
+func (d *RecordDecryptor) Decrypt(record *TLSRecord) (uint8, []byte, error) {
+	seq := make([]byte, 8)
+	binary.BigEndian.PutUint64(seq, d.seq)
+	var nonce, additional, ciphertext []byte
+	if d.xorNonce {
+		nonce = append([]byte(nil), d.iv...)
+		for i := range seq {
+			nonce[len(nonce)-8+i] ^= seq[i]
+		}
+		ciphertext = record.Fragment
+	} else {
+		if len(record.Fragment) < tls12ExplicitNonce {
+			return 0, nil, fmt.Errorf("the record is too short")
+		}
+		nonce = append(append([]byte(nil), d.iv...), record.Fragment[:tls12ExplicitNonce]...)
+		ciphertext = record.Fragment[tls12ExplicitNonce:]
+	}
+	if d.tls13 {
+		additional = record.Header
+	} else {
+		if len(ciphertext) < tlsAEADTagLen {
+			return 0, nil, fmt.Errorf("the record is too short")
+		}
+		additional = append(seq, record.Type, byte(record.Version>>8), byte(record.Version), 0, 0)
+		binary.BigEndian.PutUint16(additional[11:], uint16(len(ciphertext)-tlsAEADTagLen))
+	}
+	plaintext, err := d.aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return 0, nil, err
+	}
+	d.seq++
+	if !d.tls13 {
+		return record.Type, plaintext, nil
+	}
+	// the real content type is the last non-zero byte
+	for i := len(plaintext) - 1; i >= 0; i-- {
+		if plaintext[i] != 0 {
+			return plaintext[i], plaintext[:i], nil
+		}
+	}
+	return 0, nil, fmt.Errorf("cannot found the content type of the record")
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// hkdfExpandLabel is the HKDF-Expand-Label function in RFC 8446 with empty context
+func hkdfExpandLabel(h func() hash.Hash, secret []byte, label string, length int) []byte {
+	fullLabel := "tls13 " + label
+	info := make([]byte, 0, 4+len(fullLabel))
+	info = append(info, byte(length>>8), byte(length), byte(len(fullLabel)))
+	info = append(info, fullLabel...)
+	info = append(info, 0)
+
+	result := make([]byte, 0, length)
+	var previous []byte
+	for counter := byte(1); len(result) < length; counter++ {
+		mac := hmac.New(h, secret)
+		mac.Write(previous)
+		mac.Write(info)
+		mac.Write([]byte{counter})
+		previous = mac.Sum(nil)
+		result = append(result, previous...)
+	}
+	return result[:length]
+}
+
+// tls12PRF is the P_hash function in RFC 5246
+func tls12PRF(h func() hash.Hash, secret []byte, label string, seed []byte, length int) []byte {
+	labelAndSeed := append([]byte(label), seed...)
+	result := make([]byte, 0, length)
+	mac := hmac.New(h, secret)
+	mac.Write(labelAndSeed)
+	a := mac.Sum(nil)
+	for len(result) < length {
+		mac.Reset()
+		mac.Write(a)
+		mac.Write(labelAndSeed)
+		result = append(result, mac.Sum(nil)...)
+		mac.Reset()
+		mac.Write(a)
+		a = mac.Sum(nil)
+	}
+	return result[:length]
+}
+
+// TLSVersionName returns the readable name of the TLS protocol version
+func TLSVersionName(version uint16) string {
+	switch version {
+	case 0x0300:
+		return "SSLv3"
+	case 0x0301:
+		return "TLSv1.0"
+	case 0x0302:
+		return "TLSv1.1"
+	case 0x0303:
+		return "TLSv1.2"
+	case tlsVersion13:
+		return "TLSv1.3"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
@@ -0,0 +1,227 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+func TestHKDFExpandLabel(t *testing.T) {
+	// the server handshake traffic key and iv in RFC 8448 "Simple 1-RTT Handshake"
+	secret, _ := hex.DecodeString("b67b7d690cc16c4e75e54213cb2d37b4e9c912bcded9105d42befd59d391ad38")
+	key := hkdfExpandLabel(sha256.New, secret, "key", 16)
+	if hex.EncodeToString(key) != "3fce516009c21727d0f2e4e86ee403bc" {
+		t.Fatalf("unexpected key: %x", key)
+	}
+	iv := hkdfExpandLabel(sha256.New, secret, "iv", 12)
+	if hex.EncodeToString(iv) != "5d313eb2671276ee13000b30" {
+		t.Fatalf("unexpected iv: %x", iv)
+	}
+}
+
+func TestTLS13Decrypt(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x01}, 32)
+	key := hkdfExpandLabel(sha256.New, secret, "key", 16)
+	iv := hkdfExpandLabel(sha256.New, secret, "iv", 12)
+	aead, err := newAESGCM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryptor, err := NewTLS13Decryptor(0x1301, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seq, message := range []string{"GET / HTTP/1.1\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n"} {
+		nonce := append([]byte(nil), iv...)
+		nonce[len(nonce)-1] ^= byte(seq)
+		inner := append([]byte(message), TLSRecordApplicationData, 0, 0)
+		header := []byte{TLSRecordApplicationData, 0x03, 0x03, 0, 0}
+		binary.BigEndian.PutUint16(header[3:], uint16(len(inner)+aead.Overhead()))
+		data := append(header, aead.Seal(nil, nonce, inner, header)...)
+
+		records, read := ParseTLSRecords(data)
+		if len(records) != 1 || read != len(data) {
+			t.Fatalf("parsing the records failure, count: %d, read: %d", len(records), read)
+		}
+		contentType, plaintext, err := decryptor.Decrypt(records[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != TLSRecordApplicationData || string(plaintext) != message {
+			t.Fatalf("unexpected content type: %d, plaintext: %q", contentType, plaintext)
+		}
+	}
+}
+
+func TestChaCha20Decrypt(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x02}, 32)
+	message := "GET / HTTP/1.1\r\n\r\n"
+
+	// TLS 1.3, the real content type is appended in the plaintext
+	key := hkdfExpandLabel(sha256.New, secret, "key", 32)
+	iv := hkdfExpandLabel(sha256.New, secret, "iv", 12)
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := append([]byte(message), TLSRecordApplicationData)
+	header := []byte{TLSRecordApplicationData, 0x03, 0x03, 0, 0}
+	binary.BigEndian.PutUint16(header[3:], uint16(len(inner)+aead.Overhead()))
+	tls13Data := append(header, aead.Seal(nil, iv, inner, header)...)
+
+	// TLS 1.2, the nonce is the client write IV with the sequence, without the explicit nonce
+	clientRandom, serverRandom := bytes.Repeat([]byte{0x03}, 32), bytes.Repeat([]byte{0x04}, 32)
+	keyBlock := tls12PRF(sha256.New, secret, "key expansion", append(append([]byte(nil), serverRandom...), clientRandom...), 88)
+	aead, err = chacha20poly1305.New(keyBlock[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+	additional := []byte{0, 0, 0, 0, 0, 0, 0, 0, TLSRecordApplicationData, 0x03, 0x03, 0, byte(len(message))}
+	header = []byte{TLSRecordApplicationData, 0x03, 0x03, 0, byte(len(message) + aead.Overhead())}
+	tls12Data := append(header, aead.Seal(nil, keyBlock[64:76], []byte(message), additional)...)
+
+	tls13Decryptor, err := NewTLS13Decryptor(0x1303, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tls12Decryptor, err := NewTLS12Decryptor(0xCCA8, secret, clientRandom, serverRandom, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		decryptor *RecordDecryptor
+		data      []byte
+	}{
+		{name: "TLS 1.3", decryptor: tls13Decryptor, data: tls13Data},
+		{name: "TLS 1.2", decryptor: tls12Decryptor, data: tls12Data},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, _ := ParseTLSRecords(tt.data)
+			if len(records) != 1 {
+				t.Fatalf("parsing the records failure, count: %d", len(records))
+			}
+			contentType, plaintext, err := tt.decryptor.Decrypt(records[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != TLSRecordApplicationData || string(plaintext) != message {
+				t.Fatalf("unexpected content type: %d, plaintext: %q", contentType, plaintext)
+			}
+		})
+	}
+
+	if _, err := NewTLS12Decryptor(0x002F, secret, clientRandom, serverRandom, true); !errors.Is(err, ErrUnsupportedCipherSuite) {
+		t.Fatalf("the CBC cipher suite should be unsupported, error: %v", err)
+	}
+}
+
+func TestParseTLSHandshakeMessages(t *testing.T) {
+	alpn := []byte{0, 16, 0, 5, 0, 3, 2, 'h', '2'}
+	encryptedExtensions := append([]byte{TLSHandshakeEncryptedExtensions, 0, 0, byte(len(alpn) + 2), 0, byte(len(alpn))}, alpn...)
+	certificate := []byte{11, 0, 0, 3, 0, 0, 0}
+	finished := append([]byte{TLSHandshakeFinished, 0, 0, 32}, bytes.Repeat([]byte{0x05}, 32)...)
+	// the messages are coalesced into one record, and the finished message is split into the next record
+	data := append(append(append([]byte(nil), encryptedExtensions...), certificate...), finished[:10]...)
+
+	messages, read := ParseTLSHandshakeMessages(data)
+	if len(messages) != 2 || read != len(encryptedExtensions)+len(certificate) {
+		t.Fatalf("unexpected messages count: %d, read: %d", len(messages), read)
+	}
+	if messages[0].Type != TLSHandshakeEncryptedExtensions || ParseEncryptedExtensionsALPN(messages[0].Body) != "h2" {
+		t.Fatalf("unexpected encrypted extensions: %+v", messages[0])
+	}
+
+	messages, read = ParseTLSHandshakeMessages(append(data[read:], finished[10:]...))
+	if len(messages) != 1 || messages[0].Type != TLSHandshakeFinished || read != len(finished) {
+		t.Fatalf("unexpected finished message, count: %d, read: %d", len(messages), read)
+	}
+}
+
+func TestParseTLSHello(t *testing.T) {
+	random := bytes.Repeat([]byte{0xab}, 32)
+	body := []byte{0x03, 0x03}
+	body = append(body, random...)
+	body = append(body, 0)          // session id
+	body = append(body, 0x13, 0x01) // cipher suite
+	body = append(body, 0)          // compression method
+	body = append(body, 0, 6, 0, 43, 0, 2, 0x03, 0x04)
+	message := append([]byte{TLSHandshakeServerHello, 0, 0, byte(len(body))}, body...)
+
+	hello, err := ParseTLSHello(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hello.Type != TLSHandshakeServerHello || !bytes.Equal(hello.Random, random) || hello.CipherSuite != 0x1301 || !hello.IsTLS13 {
+		t.Fatalf("unexpected hello: %+v", hello)
+	}
+
+	body = []byte{0x03, 0x03}
+	body = append(body, random...)
+	body = append(body, 0)                            // session id
+	body = append(body, 0, 4, 0x13, 0x01, 0xc0, 0x2f) // cipher suites
+	body = append(body, 1, 0)                         // compression methods
+	sni := []byte{0, 0, 0, 16, 0, 14, 0, 0, 11}
+	sni = append(sni, "example.com"...)
+	alpn := []byte{0, 16, 0, 14, 0, 12, 2, 'h', '2', 8}
+	alpn = append(alpn, "http/1.1"...)
+	body = append(body, 0, byte(len(sni)+len(alpn)))
+	body = append(body, sni...)
+	body = append(body, alpn...)
+	message = append([]byte{TLSHandshakeClientHello, 0, 0, byte(len(body))}, body...)
+
+	hello, err = ParseTLSHello(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hello.Type != TLSHandshakeClientHello || hello.ServerName != "example.com" ||
+		strings.Join(hello.ALPN, ",") != "h2,http/1.1" {
+		t.Fatalf("unexpected hello: %+v", hello)
+	}
+}
+
+func TestParseKeyLog(t *testing.T) {
+	keyLog := make(KeyLog)
+	err := keyLog.ParseKeyLog(strings.NewReader(`# comment
+CLIENT_RANDOM ABCD 0102
+CLIENT_TRAFFIC_SECRET_0 abcd 0304
+SERVER_TRAFFIC_SECRET_0 abcd invalid
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	random := []byte{0xab, 0xcd}
+	if !bytes.Equal(keyLog.Find(random, KeyLogClientRandom), []byte{0x01, 0x02}) {
+		t.Fatalf("unexpected client random secret")
+	}
+	if !bytes.Equal(keyLog.Find(random, KeyLogClientTrafficSecret0), []byte{0x03, 0x04}) {
+		t.Fatalf("unexpected client traffic secret")
+	}
+	if keyLog.Find(random, KeyLogServerTrafficSecret0) != nil {
+		t.Fatalf("the invalid secret should be ignored")
+	}
+}
@@ -0,0 +1,69 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ssl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/skywalking-rover/pkg/tools/profiling"
+
+	"github.com/cilium/ebpf"
+)
+
+const (
+	// the mangled symbol of "<rustls::conn::Writer as std::io::Write>::write"
+	rustlsWriteSymbol = "Writer$u20$as$u20$std..io..Write$GT$5write17h"
+	// the mangled symbol of "<rustls::conn::Reader as std::io::Read>::read"(moved to "rustls::conn::connection" since 0.22)
+	rustlsReadSymbol = "Reader$u20$as$u20$std..io..Read$GT$4read17h"
+)
+
+// Rustls attach the plaintext read and write of the rustls(such as ztunnel), the symbols must not be stripped.
+// The plaintext is buffered in the rustls, so the socket fd is correlated with the socket syscalls in the same thread.
+func (r *Register) Rustls(processMap *ebpf.Map, write, writeRet, read, readRet *ebpf.Program) {
+	r.addHandler("Rustls", func() (bool, error) {
+		for _, mod := range r.modules {
+			writeSymbol := findRustlsSymbol(mod.Symbols, rustlsWriteSymbol)
+			readSymbol := findRustlsSymbol(mod.Symbols, rustlsReadSymbol)
+			if writeSymbol == "" || readSymbol == "" {
+				continue
+			}
+
+			if err := processMap.Put(uint32(r.pid), uint8(1)); err != nil {
+				return false, fmt.Errorf("setting the rustls process failure, pid: %d, error: %v", r.pid, err)
+			}
+			file := r.linker.OpenUProbeExeFile(mod.Path)
+			file.AddLink(writeSymbol, write, writeRet)
+			file.AddLink(readSymbol, read, readRet)
+			if e := r.linker.HasError(); e != nil {
+				return false, e
+			}
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+func findRustlsSymbol(symbols []*profiling.Symbol, suffix string) string {
+	for _, s := range symbols {
+		if strings.Contains(s.Name, "rustls..conn..") && strings.Contains(s.Name, suffix) {
+			return s.Name
+		}
+	}
+	return ""
+}
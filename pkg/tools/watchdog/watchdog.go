@@ -0,0 +1,146 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package watchdog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/logger"
+)
+
+var log = logger.GetLogger("tools", "watchdog")
+
+// Component is the part of the event pipeline which could be stalled, such as the queue consumer or the stream sender
+type Component interface {
+	// Name of the component, should be unique in the watchdog
+	Name() string
+	// Progress returns the monotonic count of the handled data, and the count of the pending data
+	Progress() (handled, pending uint64)
+	// Restart the stalled component
+	Restart() error
+}
+
+// Status of the watched component
+type Status struct {
+	Name     string
+	Stalled  bool
+	Restarts uint64
+}
+
+// Watchdog detect the stalled components and restart them with backoff,
+// the component is stalled when it has pending data but no data handled in the stall timeout
+type Watchdog struct {
+	stallTimeout time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+
+	components []*watched
+	lock       sync.Mutex
+}
+
+type watched struct {
+	component Component
+
+	lastHandled      uint64
+	lastProgressTime time.Time
+	stalled          bool
+	restarts         uint64
+	backoff          time.Duration
+	nextRestartTime  time.Time
+}
+
+func NewWatchdog(stallTimeout, minBackoff, maxBackoff time.Duration) *Watchdog {
+	return &Watchdog{
+		stallTimeout: stallTimeout,
+		minBackoff:   minBackoff,
+		maxBackoff:   maxBackoff,
+	}
+}
+
+// Register the components to be watched
+func (w *Watchdog) Register(components ...Component) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	now := time.Now()
+	for _, c := range components {
+		handled, _ := c.Progress()
+		w.components = append(w.components, &watched{
+			component:        c,
+			lastHandled:      handled,
+			lastProgressTime: now,
+			backoff:          w.minBackoff,
+		})
+	}
+}
+
+// Check all components and restart the stalled components which reached the backoff time
+func (w *Watchdog) Check(now time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, c := range w.components {
+		handled, pending := c.component.Progress()
+		if handled != c.lastHandled || pending == 0 {
+			if c.stalled {
+				log.Infof("the component %s is recovered", c.component.Name())
+			}
+			c.lastHandled = handled
+			c.lastProgressTime = now
+			c.stalled = false
+			c.backoff = w.minBackoff
+			continue
+		}
+		if now.Sub(c.lastProgressTime) < w.stallTimeout {
+			continue
+		}
+		if !c.stalled {
+			log.Warnf("detected the component %s is stalled, pending count: %d, no progress since: %s",
+				c.component.Name(), pending, c.lastProgressTime.Format(time.RFC3339))
+		}
+		c.stalled = true
+		if now.Before(c.nextRestartTime) {
+			continue
+		}
+		c.restarts++
+		if err := c.component.Restart(); err != nil {
+			log.Warnf("restart the stalled component %s failure: %v", c.component.Name(), err)
+		} else {
+			log.Infof("restarted the stalled component %s, restart times: %d", c.component.Name(), c.restarts)
+		}
+		c.nextRestartTime = now.Add(c.backoff)
+		c.backoff *= 2
+		if c.backoff > w.maxBackoff {
+			c.backoff = w.maxBackoff
+		}
+	}
+}
+
+// Statuses of all watched components
+func (w *Watchdog) Statuses() []*Status {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	result := make([]*Status, 0, len(w.components))
+	for _, c := range w.components {
+		result = append(result, &Status{
+			Name:     c.component.Name(),
+			Stalled:  c.stalled,
+			Restarts: c.restarts,
+		})
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeComponent struct {
+	handled  uint64
+	pending  uint64
+	restarts int
+}
+
+func (f *fakeComponent) Name() string {
+	return "fake"
+}
+
+func (f *fakeComponent) Progress() (handled, pending uint64) {
+	return f.handled, f.pending
+}
+
+func (f *fakeComponent) Restart() error {
+	f.restarts++
+	return nil
+}
+
+func TestWatchdogCheck(t *testing.T) {
+	component := &fakeComponent{}
+	w := NewWatchdog(time.Minute, time.Minute, time.Minute*3)
+	w.Register(component)
+	start := w.components[0].lastProgressTime
+
+	tests := []struct {
+		name     string
+		after    time.Duration
+		handled  uint64
+		pending  uint64
+		stalled  bool
+		restarts int
+	}{
+		{name: "idle without pending", after: time.Minute * 2, stalled: false, restarts: 0},
+		{name: "pending but in timeout", after: time.Minute * 2, pending: 10, stalled: false, restarts: 0},
+		{name: "stalled and restart", after: time.Minute * 3, pending: 10, stalled: true, restarts: 1},
+		{name: "stalled in backoff", after: time.Minute*3 + time.Second*30, pending: 10, stalled: true, restarts: 1},
+		{name: "stalled after backoff", after: time.Minute * 4, pending: 10, stalled: true, restarts: 2},
+		{name: "stalled in doubled backoff", after: time.Minute * 5, pending: 10, stalled: true, restarts: 2},
+		{name: "stalled after doubled backoff", after: time.Minute * 6, pending: 10, stalled: true, restarts: 3},
+		{name: "recovered", after: time.Minute * 7, handled: 5, pending: 10, stalled: false, restarts: 3},
+	}
+	for _, tt := range tests {
+		component.handled, component.pending = tt.handled, tt.pending
+		w.Check(start.Add(tt.after))
+		status := w.Statuses()[0]
+		if status.Stalled != tt.stalled || component.restarts != tt.restarts || int(status.Restarts) != tt.restarts {
+			t.Fatalf("%s: expected stalled: %t, restarts: %d, actual stalled: %t, restarts: %d",
+				tt.name, tt.stalled, tt.restarts, status.Stalled, component.restarts)
+		}
+	}
+}
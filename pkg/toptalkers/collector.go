@@ -0,0 +1,199 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package toptalkers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/skywalking-rover/pkg/core"
+	"github.com/apache/skywalking-rover/pkg/logger"
+	"github.com/apache/skywalking-rover/pkg/module"
+	"github.com/apache/skywalking-rover/pkg/process"
+	"github.com/apache/skywalking-rover/pkg/process/api"
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+	"github.com/apache/skywalking-rover/pkg/tools/reporter"
+	"github.com/apache/skywalking-rover/pkg/tools/selfprotect"
+
+	v3 "skywalking.apache.org/repo/goapi/collect/language/agent/v3"
+)
+
+var log = logger.GetLogger("toptalkers")
+
+// netNSSample is the counters of a pod network namespace
+type netNSSample struct {
+	Workload string
+	Counters *netNSCounters
+}
+
+// Collector periodically ranks the workloads on the current node by the network usage of the pod network namespaces
+type Collector struct {
+	processOperator process.Operator
+	sender          *reporter.MeterSender
+	reportPeriod    time.Duration
+	topN            int
+	meterPrefix     string
+	throttle        *selfprotect.Throttle
+	nodeName        string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	hostNetNS uint64
+	previous  map[uint64]*netNSSample
+}
+
+func NewCollector(mgr *module.Manager, config *Config) (*Collector, error) {
+	reportPeriod, err := time.ParseDuration(config.ReportPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("parsing report period failure: %v", err)
+	}
+	if config.TopN < 1 {
+		return nil, fmt.Errorf("the top N must be bigger than zero")
+	}
+	if config.MeterPrefix == "" {
+		return nil, fmt.Errorf("please provide the meter prefix")
+	}
+	processOperator := mgr.FindModule(process.ModuleName).(process.Operator)
+	nodeName := ""
+	if k8sOperator, ok := processOperator.(process.K8sOperator); ok {
+		nodeName = k8sOperator.NodeName()
+	}
+	if nodeName == "" {
+		if nodeName, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("cannot found the node name: %v", err)
+		}
+	}
+	coreOperator := mgr.FindModule(core.ModuleName).(core.Operator)
+	return &Collector{
+		processOperator: processOperator,
+		sender:          reporter.NewMeterSender("top talkers metrics", coreOperator.BackendOperator().GetConnection()),
+		reportPeriod:    reportPeriod,
+		topN:            config.TopN,
+		meterPrefix:     config.MeterPrefix + "_",
+		throttle:        coreOperator.ResourceGovernor().Register(ModuleName, selfprotect.PriorityLow),
+		nodeName:        nodeName,
+	}, nil
+}
+
+func (c *Collector) Start(ctx context.Context) error {
+	hostNetNS, err := host.NetworkNamespaceInode(1)
+	if err != nil {
+		log.Warnf("cannot read the network namespace of the host, the host network pods would be counted: %v", err)
+	}
+	c.hostNetNS = hostNetNS
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	c.previous = c.sample()
+
+	reporter.StartPeriodic(c.ctx, "top talkers", c.reportPeriod, c.report)
+	return nil
+}
+
+func (c *Collector) Stop() error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return nil
+}
+
+// sample reads the counters of all pod network namespaces, the host network namespace is ignored
+// because it is shared by the host network pods and the node, so the usage cannot be attributed to a workload
+func (c *Collector) sample() map[uint64]*netNSSample {
+	result := make(map[uint64]*netNSSample)
+	for _, p := range c.processOperator.FindAllRegisteredProcesses() {
+		if p.DetectType() != api.Kubernetes {
+			continue
+		}
+		netNS, err := host.NetworkNamespaceInode(p.Pid())
+		if err != nil || netNS == c.hostNetNS || result[netNS] != nil {
+			continue
+		}
+		counters, err := readNetNSCounters(p.Pid())
+		if err != nil {
+			log.Debugf("read the network counters of process %d failure: %v", p.Pid(), err)
+			continue
+		}
+		result[netNS] = &netNSSample{Workload: p.Entity().ServiceName, Counters: counters}
+	}
+	return result
+}
+
+func (c *Collector) report(ctx context.Context) error {
+	if c.throttle.Paused() {
+		// the counters are not compared across the paused periods
+		c.previous = nil
+		return nil
+	}
+	current := c.sample()
+	talkers := make(map[string]*workloadTalker)
+	seconds := c.reportPeriod.Seconds()
+	for netNS, sample := range current {
+		talker := talkers[sample.Workload]
+		if talker == nil {
+			talker = &workloadTalker{}
+			talkers[sample.Workload] = talker
+		}
+		talker.Connections += float64(sample.Counters.Connections)
+		// the network namespace is new created or recreated, so no delta in this period
+		prev := c.previous[netNS]
+		if prev == nil || prev.Counters.Bytes > sample.Counters.Bytes || prev.Counters.Opens > sample.Counters.Opens {
+			continue
+		}
+		talker.Bytes += float64(sample.Counters.Bytes - prev.Counters.Bytes)
+		talker.NewConnectionRate += float64(sample.Counters.Opens-prev.Counters.Opens) / seconds
+	}
+	c.previous = current
+
+	return c.sender.Send(ctx, c.buildMeters(talkers, time.Now()))
+}
+
+// buildMeters ranks the workloads in each category, the meter service is the workload and the instance is the current node
+func (c *Collector) buildMeters(talkers map[string]*workloadTalker, now time.Time) []*v3.MeterDataCollection {
+	meters := make(map[string][]*v3.MeterData)
+	for _, category := range rankCategories {
+		values := make(map[string]float64, len(talkers))
+		for workload, talker := range talkers {
+			values[workload] = category.Value(talker)
+		}
+		ranked := rankTopN(values, c.topN)
+		summary := make([]string, 0, len(ranked))
+		for i, r := range ranked {
+			meters[r.Workload] = append(meters[r.Workload], reporter.SingleValueMeter(c.meterPrefix+category.Name, []*v3.Label{
+				{Name: "node", Value: c.nodeName},
+				{Name: "rank", Value: strconv.Itoa(i + 1)},
+			}, r.Value))
+			summary = append(summary, fmt.Sprintf("%s=%.2f", r.Workload, r.Value))
+		}
+		if len(summary) > 0 {
+			log.Debugf("top talkers by %s on node %s: %s", category.Name, c.nodeName, strings.Join(summary, ", "))
+		}
+	}
+
+	result := make([]*v3.MeterDataCollection, 0, len(meters))
+	for workload, data := range meters {
+		data[0].Service = workload
+		data[0].ServiceInstance = c.nodeName
+		data[0].Timestamp = now.UnixMilli()
+		result = append(result, &v3.MeterDataCollection{MeterData: data})
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package toptalkers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/net"
+
+	"github.com/apache/skywalking-rover/pkg/tools/host"
+)
+
+// netNSCounters is the accumulated network counters of a network namespace
+type netNSCounters struct {
+	// the total sent and received bytes of all interfaces excluding the loopback
+	Bytes uint64
+	// the count of TCP connections which established or close wait currently
+	Connections uint64
+	// the total count of active(connect) and passive(accept) opened TCP connections
+	Opens uint64
+}
+
+// readNetNSCounters read the counters of the network namespace which the process is located
+func readNetNSCounters(pid int32) (*netNSCounters, error) {
+	interfaces, err := net.IOCountersByFile(true, host.GetHostProcInHost(fmt.Sprintf("%d/net/dev", pid)))
+	if err != nil {
+		return nil, fmt.Errorf("read the network interfaces failure: %v", err)
+	}
+	counters := &netNSCounters{}
+	for _, i := range interfaces {
+		if i.Name == "lo" {
+			continue
+		}
+		counters.Bytes += i.BytesSent + i.BytesRecv
+	}
+
+	file, err := os.Open(host.GetHostProcInHost(fmt.Sprintf("%d/net/snmp", pid)))
+	if err != nil {
+		return nil, fmt.Errorf("open the SNMP file failure: %v", err)
+	}
+	defer file.Close()
+	tcp, err := parseSNMPProtocol(file, "Tcp")
+	if err != nil {
+		return nil, err
+	}
+	counters.Connections = uint64(tcp["CurrEstab"])
+	counters.Opens = uint64(tcp["ActiveOpens"] + tcp["PassiveOpens"])
+	return counters, nil
+}
+
+// parseSNMPProtocol parse the counters of the protocol in the SNMP file,
+// each protocol have a header line with the counter names, and follow a line with the values
+func parseSNMPProtocol(reader io.Reader, protocol string) (map[string]int64, error) {
+	scanner := bufio.NewScanner(reader)
+	prefix := protocol + ":"
+	var names []string
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != prefix {
+			continue
+		}
+		if names == nil {
+			names = fields[1:]
+			continue
+		}
+		if len(fields)-1 != len(names) {
+			return nil, fmt.Errorf("the count of %s counter names and values are not matched", protocol)
+		}
+		result := make(map[string]int64, len(names))
+		for i, name := range names {
+			value, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parsing the %s counter %s failure: %v", protocol, name, err)
+			}
+			result[name] = value
+		}
+		return result, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("cannot found the %s counters", protocol)
+}
@@ -0,0 +1,69 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package toptalkers
+
+import "sort"
+
+// workloadTalker is the network usage of a workload on the current node in a report period
+type workloadTalker struct {
+	// the sent and received bytes in the period
+	Bytes float64
+	// the count of current established TCP connections
+	Connections float64
+	// the count of new opened TCP connections per second in the period
+	NewConnectionRate float64
+}
+
+// rankCategory defines how to rank the workloads
+type rankCategory struct {
+	Name  string
+	Value func(t *workloadTalker) float64
+}
+
+var rankCategories = []rankCategory{
+	{Name: "bytes", Value: func(t *workloadTalker) float64 { return t.Bytes }},
+	{Name: "connections", Value: func(t *workloadTalker) float64 { return t.Connections }},
+	{Name: "new_connection_rate", Value: func(t *workloadTalker) float64 { return t.NewConnectionRate }},
+}
+
+type rankedWorkload struct {
+	Workload string
+	Value    float64
+}
+
+// rankTopN ranks the workloads by the value in descending order, and only keep the first N workloads,
+// the workloads without any usage are ignored
+func rankTopN(values map[string]float64, n int) []rankedWorkload {
+	result := make([]rankedWorkload, 0, len(values))
+	for workload, value := range values {
+		if value <= 0 {
+			continue
+		}
+		result = append(result, rankedWorkload{Workload: workload, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value > result[j].Value
+		}
+		return result[i].Workload < result[j].Workload
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+// Licensed to Apache Software Foundation (ASF) under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Apache Software Foundation (ASF) licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package toptalkers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRankTopN(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]float64
+		n      int
+		result []rankedWorkload
+	}{
+		{
+			name:   "empty",
+			values: map[string]float64{},
+			n:      3,
+			result: []rankedWorkload{},
+		},
+		{
+			name:   "ignore no usage",
+			values: map[string]float64{"a": 0, "b": 10},
+			n:      3,
+			result: []rankedWorkload{{Workload: "b", Value: 10}},
+		},
+		{
+			name:   "only keep top N",
+			values: map[string]float64{"a": 1, "b": 30, "c": 20, "d": 20},
+			n:      3,
+			result: []rankedWorkload{{Workload: "b", Value: 30}, {Workload: "c", Value: 20}, {Workload: "d", Value: 20}},
+		},
+	}
+	for _, tt := range tests {
+		if got := rankTopN(tt.values, tt.n); !reflect.DeepEqual(got, tt.result) {
+			t.Fatalf("%s: expected %v, actual %v", tt.name, tt.result, got)
+		}
+	}
+}
+
+func TestParseSNMPProtocol(t *testing.T) {
+	snmp := `Ip: Forwarding DefaultTTL
+Ip: 1 64
+Tcp: RtoAlgorithm ActiveOpens PassiveOpens CurrEstab
+Tcp: 1 12 30 4
+Udp: InDatagrams
+Udp: 100
+`
+	tcp, err := parseSNMPProtocol(strings.NewReader(snmp), "Tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int64{"RtoAlgorithm": 1, "ActiveOpens": 12, "PassiveOpens": 30, "CurrEstab": 4}
+	if !reflect.DeepEqual(tcp, expected) {
+		t.Fatalf("expected %v, actual %v", expected, tcp)
+	}
+	if _, err := parseSNMPProtocol(strings.NewReader(snmp), "Sctp"); err == nil {
+		t.Fatalf("expected error for not exist protocol")
+	}
+}